@@ -0,0 +1,394 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/audit"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/filehash"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/pretty"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/verification"
+)
+
+// runHash prints the hash of a file, or of a directory tree as derived by filehash.HashDirectory. The hash type is
+// that of the configuration if one is given.
+func runHash(args []string, s streams) error {
+	flags := newFlagSet("hash", "[-config path] [-type hash] <path>", s)
+	path := flags.String("config", "", "path of the SDK configuration")
+	hashType := flags.String("type", string(contracts.SHA256Hash), "hash type, if no configuration is given")
+	ignore := flags.String("ignore", "", "comma separated patterns of the files to leave out of a directory")
+	if err := parse(flags, args, 1); err != nil {
+		return err
+	}
+
+	info := config.HashInfo{Type: contracts.HashType(*hashType)}
+	if *path != "" {
+		cfg, err := config.Load(*path)
+		if err != nil {
+			return err
+		}
+		info = cfg.Hash
+	}
+	hash, err := factories.NewHashProviderWithInfo(info)
+	if err != nil {
+		return err
+	}
+	stream, ok := hash.(interfaces.HashProviderStream)
+	if !ok {
+		return fmt.Errorf("%w: hash type %s cannot hash files", errUsage, info.Type)
+	}
+
+	name := flags.Arg(0)
+	stat, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	var key string
+	if stat.IsDir() {
+		key, err = filehash.HashDirectory(stream, name, split(*ignore))
+	} else {
+		key, err = filehash.HashFile(stream, name)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(s.out, key)
+	return err
+}
+
+// runAnnotate applies the annotators of the given kinds to the data and prints the signed annotations
+func runAnnotate(args []string, s streams) error {
+	flags := newFlagSet("annotate", "-config path [-kind kinds] [file]", s)
+	path := flags.String("config", "", "path of the SDK configuration (required)")
+	kinds := flags.String("kind", string(contracts.AnnotationSource), "comma separated annotation types to create")
+	if err := parse(flags, args, -1); err != nil {
+		return err
+	}
+	cfg, err := load(*path)
+	if err != nil {
+		return err
+	}
+	data, err := readInput(flags.Arg(0), s.in)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(s.out)
+	for _, kind := range split(*kinds) {
+		annotator, err := factories.NewAnnotator(contracts.AnnotationType(kind), cfg)
+		if err != nil {
+			return err
+		}
+		a, err := annotator.Do(context.Background(), data)
+		if err != nil {
+			return fmt.Errorf("%s annotator: %w", kind, err)
+		}
+		if err := enc.Encode(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPublish publishes the annotations read from the input to the configured stream, as a single AnnotationList
+func runPublish(args []string, s streams) error {
+	flags := newFlagSet("publish", "-config path [-action action] [file]", s)
+	path := flags.String("config", "", "path of the SDK configuration (required)")
+	action := flags.String("action", string(message.ActionCreate), "SDK action to publish the annotations under")
+	if err := parse(flags, args, -1); err != nil {
+		return err
+	}
+	cfg, err := load(*path)
+	if err != nil {
+		return err
+	}
+	items, err := readAnnotations(flags.Arg(0), s.in, contracts.LenientDecoding)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return errors.New("no annotations to publish")
+	}
+
+	list := contracts.AnnotationList{Items: items}
+	contentType := string(cfg.Stream.ContentType)
+	content, err := message.MarshalContent(list, contentType)
+	if err != nil {
+		return err
+	}
+	wrap := message.PublishWrapper{
+		Version:     message.WrapperVersion,
+		Action:      message.SdkAction(*action),
+		MessageType: fmt.Sprintf("%T", list),
+		Content:     content,
+		ContentType: contentType,
+	}
+	if version := cfg.Stream.SchemaVersion; version != 0 {
+		if wrap, err = wrap.ConvertTo(version); err != nil {
+			return err
+		}
+	}
+
+	provider, err := factories.NewStreamProvider(cfg.Stream, factories.NewLogger(config.LoggingInfo{MinLogLevel: slog.LevelWarn}))
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := provider.Connect(ctx); err != nil {
+		return err
+	}
+	err = provider.Publish(ctx, wrap)
+	// closing flushes a batching or buffering provider, so its error matters as much as that of the publish
+	return errors.Join(err, provider.Close())
+}
+
+// runVerify verifies the signatures of the annotations read from the input, or of those carried by a published
+// wrapper, against the public keys of the configuration. It fails unless every signature is valid.
+func runVerify(args []string, s streams) error {
+	flags := newFlagSet("verify", "-config path [-wrapped] [-strict] [file]", s)
+	path := flags.String("config", "", "path of the SDK configuration (required)")
+	wrapped := flags.Bool("wrapped", false, "read a PublishWrapper, as received from a stream, instead of annotations")
+	strict := flags.Bool("strict", false, "reject unknown fields, invalid ids and timestamps and oversized input")
+	if err := parse(flags, args, -1); err != nil {
+		return err
+	}
+	mode := contracts.LenientDecoding
+	if *strict {
+		mode = contracts.StrictDecoding
+	}
+	cfg, err := load(*path)
+	if err != nil {
+		return err
+	}
+	keys := verification.NewConfigResolver(cfg.Signature)
+
+	if *wrapped {
+		data, err := readInput(flags.Arg(0), s.in)
+		if err != nil {
+			return err
+		}
+		if _, err := verification.IngestWith(data, keys, mode); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(s.out, "ok")
+		return err
+	}
+
+	items, err := readAnnotations(flags.Arg(0), s.in, mode)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, a := range items {
+		if err := verification.VerifyAnnotation(a, keys); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintf(s.out, "%s: ok\n", a.Id)
+	}
+	return errors.Join(errs...)
+}
+
+// runAudit reads published wrappers back, from the mirror node of the configured Hedera stream if a topic is given
+// or else one per line from the input, and reports the problems found with the annotations they carry. It fails
+// unless none is found.
+func runAudit(args []string, s streams) error {
+	flags := newFlagSet("audit", "-config path [-topic id] [file]", s)
+	path := flags.String("config", "", "path of the SDK configuration (required)")
+	topic := flags.String("topic", "", "Hedera topic to read through the mirror node of the configured stream")
+	if err := parse(flags, args, -1); err != nil {
+		return err
+	}
+	cfg, err := load(*path)
+	if err != nil {
+		return err
+	}
+
+	var src audit.Source
+	if *topic != "" {
+		hedera, ok := cfg.Stream.Config.(config.HederaConfig)
+		if !ok {
+			return fmt.Errorf("%w: -topic requires a hedera stream", errUsage)
+		}
+		if src, err = audit.NewHederaSource(hedera.MirrorNode, *topic); err != nil {
+			return err
+		}
+	} else {
+		data, err := readInput(flags.Arg(0), s.in)
+		if err != nil {
+			return err
+		}
+		src = audit.NewReaderSource(bytes.NewReader(data))
+	}
+
+	report, err := audit.Audit(context.Background(), src, verification.NewConfigResolver(cfg.Signature))
+	if err != nil {
+		return err
+	}
+	for _, f := range report.Findings {
+		fmt.Fprintln(s.out, f)
+	}
+	fmt.Fprintf(s.out, "%d records, %d annotations from %d hosts, %d problems\n", report.Records,
+		report.Annotations, len(report.Hosts), len(report.Findings))
+	if !report.OK() {
+		return errors.New("audit found problems")
+	}
+	return nil
+}
+
+// runShow prints the annotations read from the input as a table
+func runShow(args []string, s streams) error {
+	flags := newFlagSet("show", "[file]", s)
+	if err := parse(flags, args, -1); err != nil {
+		return err
+	}
+	items, err := readAnnotations(flags.Arg(0), s.in, contracts.LenientDecoding)
+	if err != nil {
+		return err
+	}
+	return pretty.WriteTable(s.out, items)
+}
+
+// runDiff prints the criteria missing from, changed in or new to the second list of annotations, see pretty.Diff. It
+// fails if the lists differ, like diff(1).
+func runDiff(args []string, s streams) error {
+	flags := newFlagSet("diff", "<before> <after>", s)
+	if err := parse(flags, args, 2); err != nil {
+		return err
+	}
+	before, err := readAnnotations(flags.Arg(0), s.in, contracts.LenientDecoding)
+	if err != nil {
+		return err
+	}
+	after, err := readAnnotations(flags.Arg(1), s.in, contracts.LenientDecoding)
+	if err != nil {
+		return err
+	}
+	changes := pretty.Diff(before, after)
+	if err = pretty.WriteDiff(s.out, changes); err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		return fmt.Errorf("%d criteria differ", len(changes))
+	}
+	return nil
+}
+
+// newFlagSet returns the flag set of a command, reporting its usage on the error stream
+func newFlagSet(name string, synopsis string, s streams) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(s.err)
+	flags.Usage = func() {
+		fmt.Fprintf(s.err, "usage: alvarium %s %s\n", name, synopsis)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parse parses the flags, requiring exactly want positional arguments, or at most one if want is negative
+func parse(flags *flag.FlagSet, args []string, want int) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	n := flags.NArg()
+	if (want >= 0 && n != want) || (want < 0 && n > 1) {
+		flags.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// load reads the SDK configuration, which is required by all commands but hash, adding the layers it declares so
+// that annotations made in them are decoded
+func load(path string) (config.SdkInfo, error) {
+	if path == "" {
+		return config.SdkInfo{}, fmt.Errorf("%w: -config is required", errUsage)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return config.SdkInfo{}, err
+	}
+	cfg.AddLayers()
+	return cfg, nil
+}
+
+// readInput reads the named file, or stdin if the name is empty or "-"
+func readInput(name string, stdin io.Reader) ([]byte, error) {
+	if name == "" || name == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(name)
+}
+
+// readAnnotations reads a sequence of JSON annotations from the named file or stdin, as printed by annotate, decoding
+// them in the given mode
+func readAnnotations(name string, stdin io.Reader, mode contracts.DecodeMode) ([]contracts.Annotation, error) {
+	r := stdin
+	if name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var items []contracts.Annotation
+	dec := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		err := dec.Decode(&raw)
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("annotation %d: %w", len(items)+1, err)
+		}
+		a, err := contracts.UnmarshalAnnotation(raw, mode)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %d: %w", len(items)+1, err)
+		}
+		items = append(items, a)
+	}
+}
+
+// split returns the non-empty elements of a comma separated list
+func split(list string) []string {
+	var values []string
+	for _, v := range strings.Split(list, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,112 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Command alvarium exposes the SDK on the command line, so that files can be hashed and annotations created,
+// published and verified from CI scripts, or while debugging a broker or ledger integration, without writing Go.
+//
+// Usage:
+//
+//	alvarium hash [-config path] [-type hash] <path>
+//	alvarium annotate -config path [-kind kinds] [file]
+//	alvarium publish -config path [-action action] [file]
+//	alvarium verify -config path [-wrapped] [file]
+//	alvarium audit -config path [-topic id] [file]
+//	alvarium show [file]
+//	alvarium diff <before> <after>
+//
+// Annotations are read and written as JSON, one per line, so the output of annotate may be piped to publish or
+// verify. Input is read from stdin when no file, or "-", is given. The audit command reads published wrappers back,
+// one per line or from the configured Hedera topic, see pkg/audit.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// errUsage is wrapped by the errors of commands invoked with the wrong arguments
+var errUsage = errors.New("invalid usage")
+
+// streams are the standard streams of the process, replaced in tests
+type streams struct {
+	in  io.Reader
+	out io.Writer
+	err io.Writer
+}
+
+// command is a subcommand of the tool
+type command struct {
+	summary string
+	run     func(args []string, s streams) error
+}
+
+var commands = map[string]command{
+	"hash":     {"derive the hash of a file or directory tree", runHash},
+	"annotate": {"create and sign annotations for data", runAnnotate},
+	"publish":  {"publish annotations to the configured stream", runPublish},
+	"verify":   {"verify the signatures of annotations", runVerify},
+	"audit":    {"check the annotations read back from a ledger or topic", runAudit},
+	"show":     {"print annotations as a table", runShow},
+	"diff":     {"compare two lists of annotations by criterion", runDiff},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the subcommand named by args, returning the exit code: 0 on success, 1 on failure and 2 on misuse
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "alvarium: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	err := cmd.run(args[1:], streams{in: stdin, out: stdout, err: stderr})
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		// the flag set has already reported a failure to parse the flags
+		if err != errUsage {
+			fmt.Fprintf(stderr, "alvarium %s: %v\n", args[0], err)
+		}
+		return 2
+	default:
+		fmt.Fprintf(stderr, "alvarium %s: %v\n", args[0], err)
+		return 1
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: alvarium <command> [flags] [args]")
+	fmt.Fprintln(w, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(w, "\nrun alvarium <command> -h for the flags of a command")
+}
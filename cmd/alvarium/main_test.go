@@ -0,0 +1,244 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfig = "./test/config.json"
+
+// execute runs the tool with args and stdin, returning its exit code and output
+func execute(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"no command", nil, 2},
+		{"unknown command", []string{"sign"}, 2},
+		{"help", []string{"hash", "-h"}, 0},
+		{"unknown flag", []string{"hash", "-foo", "file"}, 2},
+		{"missing argument", []string{"hash"}, 2},
+		{"extra argument", []string{"verify", "-config", testConfig, "a", "b"}, 2},
+		{"missing config", []string{"annotate", "file"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := execute("", tt.args...)
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, stderr, "usage")
+		})
+	}
+}
+
+func TestRun_Hash(t *testing.T) {
+	root := t.TempDir()
+	name := filepath.Join(root, "artifact.bin")
+	if err := os.WriteFile(name, []byte("foo"), 0644); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		expected string
+	}{
+		{"file", []string{"hash", name}, 0, "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae\n"},
+		{"file with config", []string{"hash", "-config", testConfig, name}, 0,
+			"2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae\n"},
+		{"directory", []string{"hash", "-ignore", "*.bin", root}, 0,
+			"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855\n"},
+		{"missing file", []string{"hash", filepath.Join(root, "missing.bin")}, 1, ""},
+		{"invalid type", []string{"hash", "-type", "foo", name}, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, _ := execute("", tt.args...)
+			assert.Equal(t, tt.code, code)
+			assert.Equal(t, tt.expected, stdout)
+		})
+	}
+}
+
+func TestRun_AnnotateVerify(t *testing.T) {
+	code, annotations, stderr := execute("foo", "annotate", "-config", testConfig, "-kind", "src,tpm")
+	if code != 0 {
+		t.Fatalf(stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(annotations), "\n")
+	assert.Len(t, lines, 2)
+	var a contracts.Annotation
+	if err := json.Unmarshal([]byte(lines[0]), &a); err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Equal(t, contracts.AnnotationSource, a.Kind)
+	assert.Equal(t, "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", a.Key)
+
+	tampered := strings.Replace(lines[0], a.Key, strings.Repeat("0", len(a.Key)), 1)
+	unknownField := strings.Replace(lines[0], `"host":`, `"hostname":"x","host":`, 1)
+
+	tests := []struct {
+		name  string
+		args  []string
+		input string
+		code  int
+	}{
+		{"valid", nil, annotations, 0},
+		{"valid strict", []string{"-strict"}, annotations, 0},
+		{"tampered", nil, tampered, 1},
+		{"invalid json", nil, "{", 1},
+		{"unknown field", nil, unknownField, 0},
+		{"unknown field strict", []string{"-strict"}, unknownField, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, _ := execute(tt.input, append([]string{"verify", "-config", testConfig}, tt.args...)...)
+			assert.Equal(t, tt.code, code)
+			if tt.code == 0 {
+				assert.Equal(t, fmt.Sprintf("%s: ok\n", a.Id), strings.SplitAfter(stdout, "\n")[0])
+			}
+		})
+	}
+
+	t.Run("wrapped", func(t *testing.T) {
+		list := contracts.AnnotationList{Items: []contracts.Annotation{a}}
+		content, _ := json.Marshal(list)
+		wrap, _ := json.Marshal(message.PublishWrapper{
+			Version:     message.WrapperVersion,
+			Action:      message.ActionCreate,
+			MessageType: fmt.Sprintf("%T", list),
+			Content:     content,
+			ContentType: string(contracts.ContentTypeJSON),
+		})
+		code, stdout, stderr := execute(string(wrap), "verify", "-config", testConfig, "-wrapped")
+		assert.Equal(t, 0, code, stderr)
+		assert.Equal(t, "ok\n", stdout)
+
+		code, stdout, stderr = execute(string(wrap), "verify", "-config", testConfig, "-wrapped", "-strict")
+		assert.Equal(t, 0, code, stderr)
+		assert.Equal(t, "ok\n", stdout)
+	})
+}
+
+func TestRun_Publish(t *testing.T) {
+	_, annotations, _ := execute("foo", "annotate", "-config", testConfig)
+
+	tests := []struct {
+		name  string
+		args  []string
+		input string
+		code  int
+	}{
+		{"valid", nil, annotations, 0},
+		{"action", []string{"-action", "transit"}, annotations, 0},
+		{"no annotations", nil, "", 1},
+		{"invalid json", nil, "{", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"publish", "-config", testConfig}, tt.args...)
+			code, _, stderr := execute(tt.input, args...)
+			assert.Equal(t, tt.code, code, stderr)
+		})
+	}
+}
+
+func TestRun_Audit(t *testing.T) {
+	_, annotations, _ := execute("foo", "annotate", "-config", testConfig, "-kind", "src,tpm")
+	list := contracts.AnnotationList{}
+	for _, line := range strings.Split(strings.TrimSpace(annotations), "\n") {
+		var a contracts.Annotation
+		if err := json.Unmarshal([]byte(line), &a); err != nil {
+			t.Fatalf(err.Error())
+		}
+		list.Items = append(list.Items, a)
+	}
+	content, _ := json.Marshal(list)
+	wrap, _ := json.Marshal(message.PublishWrapper{
+		Version:     message.WrapperVersion,
+		Action:      message.ActionCreate,
+		MessageType: fmt.Sprintf("%T", list),
+		Content:     content,
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		code     int
+		expected string
+	}{
+		{"intact", nil, string(wrap) + "\n", 0, "1 records, 2 annotations from 1 hosts, 0 problems"},
+		{"duplicate", nil, string(wrap) + "\n" + string(wrap) + "\n", 1, "duplicate at line 2"},
+		{"malformed", nil, "{\n", 1, "malformed at line 1"},
+		{"topic without hedera stream", []string{"-topic", "0.0.1234"}, "", 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"audit", "-config", testConfig}, tt.args...)
+			code, stdout, stderr := execute(tt.input, args...)
+			assert.Equal(t, tt.code, code, stderr)
+			assert.Contains(t, stdout, tt.expected)
+		})
+	}
+}
+
+func TestRun_ShowDiff(t *testing.T) {
+	_, annotations, _ := execute("foo", "annotate", "-config", testConfig, "-kind", "src,tpm")
+	root := t.TempDir()
+	before := filepath.Join(root, "before.json")
+	after := filepath.Join(root, "after.json")
+	lines := strings.SplitAfter(annotations, "\n")
+	if err := os.WriteFile(before, []byte(annotations), 0644); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err := os.WriteFile(after, []byte(lines[0]), 0644); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	code, stdout, stderr := execute(annotations, "show")
+	assert.Equal(t, 0, code, stderr)
+	assert.Len(t, strings.Split(strings.TrimSpace(stdout), "\n"), 3)
+	assert.True(t, strings.HasPrefix(stdout, "KIND"))
+
+	code, stdout, _ = execute("", "diff", before, before)
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stdout)
+
+	code, stdout, _ = execute("", "diff", before, after)
+	assert.Equal(t, 1, code)
+	assert.True(t, strings.HasPrefix(stdout, "- tpm app 2c26b46b68ff"), stdout)
+
+	code, _, _ = execute("", "diff", before)
+	assert.Equal(t, 2, code)
+}
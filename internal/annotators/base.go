@@ -15,12 +15,225 @@
 package annotators
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/cose"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/canonical"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/jws"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/merklesig"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/tags"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
 )
 
+// DeriveHash returns the hash of the given data that identifies it in an annotation. If the caller has already
+// derived the hash, for example while streaming the data, it is taken from the context instead. Otherwise the hash
+// is derived on behalf of the tenant in the context, if any.
+func DeriveHash(ctx context.Context, hash interfaces.HashProvider, data []byte) (string, error) {
+	if key, ok := ctx.Value(contracts.DataHashKey).(string); ok {
+		return key, nil
+	}
+	hash, err := ForTenant(ctx, hash)
+	if err != nil {
+		return "", err
+	}
+	return hash.Derive(data), nil
+}
+
+// ParentKey returns the hash of the data from which the annotated data was derived, if the context identifies it
+func ParentKey(ctx context.Context) string {
+	key, _ := ctx.Value(contracts.ParentHashKey).(string)
+	return key
+}
+
+// NewId returns the identifier of the annotation, generated by the generator held by the context (see
+// contracts.IdGeneratorKey) if any. Otherwise the ULID assigned by contracts.NewAnnotation is kept.
+func NewId(ctx context.Context, a contracts.Annotation) ulid.ULID {
+	gen, ok := ctx.Value(contracts.IdGeneratorKey).(interfaces.IdGenerator)
+	if !ok {
+		return a.Id
+	}
+	return gen.NewId(a)
+}
+
+// Timestamp returns the time the annotation is made, read from the clock held by the context (see
+// contracts.ClockKey) if any. Otherwise the annotation keeps the time it was created at.
+func Timestamp(ctx context.Context, a contracts.Annotation) time.Time {
+	clock, ok := ctx.Value(contracts.ClockKey).(interfaces.Clock)
+	if !ok {
+		return a.Timestamp
+	}
+	return clock.Now()
+}
+
+// TagValue returns the tags of the annotation, read by the getter held by the context (see
+// contracts.TagValueGetterKey) if any. Otherwise the tags of the app layer are read from the TAG environment variable.
+func TagValue(ctx context.Context, a contracts.Annotation) contracts.TagList {
+	getter, ok := ctx.Value(contracts.TagValueGetterKey).(interfaces.TagValueGetter)
+	if !ok {
+		getter = tags.Default
+	}
+	return getter.GetTagValue(a.Layer)
+}
+
+// HostInfo returns the attributes of the host held by the context (see contracts.HostInfoKey), nil if none are
+// attached to the annotations
+func HostInfo(ctx context.Context) *contracts.HostInfo {
+	info, _ := ctx.Value(contracts.HostInfoKey).(*contracts.HostInfo)
+	return info
+}
+
+// ForTenant returns the hash provider to use for the tenant identified in the context, which is the given provider
+// unless it varies per tenant.
+func ForTenant(ctx context.Context, hash interfaces.HashProvider) (interfaces.HashProvider, error) {
+	tenant, ok := ctx.Value(contracts.TenantKey).(string)
+	if !ok {
+		return hash, nil
+	}
+	if t, ok := hash.(interfaces.TenantHashProvider); ok {
+		return t.ForTenant(tenant)
+	}
+	return hash, nil
+}
+
+// SignWithActiveKey stamps the annotation with the identifier of the signing key currently in effect, then signs it
+// over the signature base selected by the configured canonicalization. The identifier is covered by the signature.
+func SignWithActiveKey(keys config.SignatureInfo, signature interfaces.SignatureProvider, a *contracts.Annotation) error {
+	return signWithActiveKey(keys, signature, a, nil)
+}
+
+// signWithActiveKey signs the annotation as SignWithActiveKey does, keeping its encoding in enc, if any
+func signWithActiveKey(keys config.SignatureInfo, signature interfaces.SignatureProvider, a *contracts.Annotation,
+	enc *Encodings) error {
+	key := keys.ActivePrivateKey(time.Now())
+	a.KeyId = key.Id
+
+	buf := buffers.Get().(*[]byte)
+	defer buffers.Put(buf)
+	b, err := appendSignatureBase((*buf)[:0], *a, keys.Canonicalization)
+	*buf = b
+	if err != nil {
+		return fmt.Errorf("%w: %w", contracts.ErrSigningFailed, err)
+	}
+	var signed string
+	switch keys.Format {
+	case contracts.JWSFormat:
+		signed, err = jws.Sign(signature, key, b)
+	case contracts.COSEFormat:
+		signed, err = cose.Sign(signature, key, b)
+	default:
+		signed, err = signature.Sign(key, b)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", contracts.ErrSigningFailed, err)
+	}
+	a.Signature = signed
+	if keys.Canonicalization != contracts.JCSCanonicalization {
+		enc.keep(signed, b)
+	}
+	return nil
+}
+
+// SignBatchWithActiveKey signs many annotations with a single batch signature from the signing key currently in
+// effect, stamping each with the key's identifier. Batch signatures have their own representation, so the configured
+// format must be the default.
+func SignBatchWithActiveKey(keys config.SignatureInfo, signature interfaces.SignatureProvider,
+	items []contracts.Annotation) error {
+	return signBatchWithActiveKey(keys, signature, items, nil)
+}
+
+// signBatchWithActiveKey signs the annotations as SignBatchWithActiveKey does, keeping their encodings in enc, if any
+func signBatchWithActiveKey(keys config.SignatureInfo, signature interfaces.SignatureProvider,
+	items []contracts.Annotation, enc *Encodings) error {
+	if keys.Format != "" && keys.Format != contracts.RawFormat {
+		return fmt.Errorf("%w: batch signatures cannot be represented in %s format", contracts.ErrSigningFailed, keys.Format)
+	}
+	key := keys.ActivePrivateKey(time.Now())
+
+	contents := make([][]byte, len(items))
+	for i := range items {
+		items[i].KeyId = key.Id
+		b, err := SignatureBase(items[i], keys.Canonicalization)
+		if err != nil {
+			return err
+		}
+		contents[i] = b
+	}
+
+	signed, err := signature.SignBatch(key, contents)
+	if err != nil {
+		return fmt.Errorf("%w: %w", contracts.ErrSigningFailed, err)
+	}
+	for i := range items {
+		items[i].Signature = signed[i]
+		if keys.Canonicalization != contracts.JCSCanonicalization {
+			enc.keep(signed[i], contents[i])
+		}
+	}
+	return nil
+}
+
+// AnnotateBatch creates the annotation of each piece of data with annotate, then signs the annotations together with a
+// single batch signature. Formats that cannot represent batch signatures, such as JWS, and chained annotations, whose
+// PrevHash depends on the annotation before, are signed one annotation at a time.
+func AnnotateBatch(ctx context.Context, keys config.SignatureInfo, signature interfaces.SignatureProvider, data [][]byte,
+	annotate func(ctx context.Context, data []byte) (contracts.Annotation, error)) ([]contracts.Annotation, error) {
+	items := make([]contracts.Annotation, len(data))
+	for i := range data {
+		annotation, err := annotate(ctx, data[i])
+		if err != nil {
+			return nil, err
+		}
+		items[i] = annotation
+	}
+
+	_, chained := ctx.Value(contracts.ChainKey).(*Chain)
+	if chained || (keys.Format != "" && keys.Format != contracts.RawFormat) {
+		for i := range items {
+			if err := SignAndLink(ctx, keys, signature, &items[i]); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	if err := signBatchWithActiveKey(keys, signature, items, EncodingsFrom(ctx)); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// SignatureBase returns the content over which the signature of the annotation is made: its JSON encoding without
+// the signature. With JCS canonicalization, the encoding is instead a canonical form (RFC 8785) that does not depend
+// on how the annotation was encoded when received, so that an annotation parsed and encoded again yields the same
+// bytes. Its properties are ordered as RFC 8785 requires, the empty ones, which encoders may give as null, are left
+// out, and the timestamp is given in UTC with as many fractional digits as it needs.
+func SignatureBase(a contracts.Annotation, c contracts.Canonicalization) ([]byte, error) {
+	return appendSignatureBase(nil, a, c)
+}
+
+// appendSignatureBase appends the signature base of the annotation to b, see SignatureBase
+func appendSignatureBase(b []byte, a contracts.Annotation, c contracts.Canonicalization) ([]byte, error) {
+	a.Signature = ""
+	if c != contracts.JCSCanonicalization {
+		return a.AppendJSON(b)
+	}
+	a.Timestamp = a.Timestamp.UTC()
+	encoded, err := json.Marshal(a)
+	if err != nil {
+		return b, err
+	}
+	transformed, err := canonical.Transform(encoded)
+	if err != nil {
+		return b, err
+	}
+	return append(b, transformed...), nil
+}
+
 func SignAnnotation(key config.KeyInfo, signature interfaces.SignatureProvider, a contracts.Annotation) (string, error) {
 	b, err := json.Marshal(a)
 	if err != nil {
@@ -30,18 +243,26 @@ func SignAnnotation(key config.KeyInfo, signature interfaces.SignatureProvider,
 	return signature.Sign(key, b)
 }
 
-// VerifySignature will validate the signature on an Annotation
+// VerifySignature will validate the signature on an Annotation made over the signature base of the given
+// canonicalization, see config.SignatureInfo.Canonicalization
 //
-// Currently (29-Mar-2024) this method is only used by unit tests
-func VerifySignature(key config.KeyInfo, signature interfaces.SignatureProvider, src contracts.Annotation) (bool, error) {
-	// Annotations are signed based on their JSON representation prior to populating the Signature property.
-	// Thus we need to reflect that prior state by setting the Signature property to empty before marshalling
+// Consumers outside the SDK should use the verification package, which resolves the key and provider for them
+func VerifySignature(key config.KeyInfo, signature interfaces.SignatureProvider, src contracts.Annotation,
+	c contracts.Canonicalization) (bool, error) {
 	verifiable := src.Signature
-	src.Signature = ""
-	b, err := json.Marshal(src)
+	b, err := SignatureBase(src, c)
 	if err != nil {
 		return false, err
 	}
 
+	if jws.IsCompact(verifiable) {
+		return jws.Verify(signature, key, verifiable, b)
+	}
+	if cose.IsSign1(verifiable) {
+		return cose.Verify(signature, key, verifiable, b)
+	}
+	if merklesig.IsBatch(verifiable) {
+		return merklesig.Verify(signature, key, verifiable, b)
+	}
 	return signature.Verify(key, b, []byte(verifiable))
 }
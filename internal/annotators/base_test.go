@@ -14,17 +14,24 @@
 package annotators
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/md5"
 	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/none"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/salted"
 	sha2562 "github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/sha256"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/merklesig"
 	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
 	"github.com/project-alvarium/alvarium-sdk-go/test"
 	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestDeriveHash(t *testing.T) {
@@ -40,20 +47,40 @@ func TestDeriveHash(t *testing.T) {
 	sha256Output := "fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"
 	sha256Hash := sha2562.New()
 
+	// A hash derived ahead of time, such as from a stream, takes precedence over the data
+	derived := context.WithValue(context.Background(), contracts.DataHashKey, sha256Output)
+
+	saltedHash, err := salted.New(config.SaltInfo{Value: "0102", Tenants: map[string]string{"tenant-a": "0a0b"}},
+		sha2562.New())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	tenant := context.WithValue(context.Background(), contracts.TenantKey, "tenant-a")
+
 	tests := []struct {
 		name         string
+		ctx          context.Context
 		hashType     contracts.HashType
 		hashProvider interfaces.HashProvider
 		input        []byte
 		output       string
 	}{
-		{"derive no hash", contracts.NoHash, noHash, []byte(noneInput), noneOutput},
-		{"derive md5 hash", contracts.MD5Hash, md5Hash, md5Input, md5Output},
-		{"derive sha256 hash", contracts.SHA256Hash, sha256Hash, sha256Input, sha256Output},
+		{"derive no hash", context.Background(), contracts.NoHash, noHash, []byte(noneInput), noneOutput},
+		{"derive md5 hash", context.Background(), contracts.MD5Hash, md5Hash, md5Input, md5Output},
+		{"derive sha256 hash", context.Background(), contracts.SHA256Hash, sha256Hash, sha256Input, sha256Output},
+		{"derived sha256 hash", derived, contracts.SHA256Hash, sha256Hash, nil, sha256Output},
+		{"default salted hash", context.Background(), contracts.SHA256Hash, saltedHash, []byte("on"),
+			"9367bdb334082a64e267a5766004d556d22719807e4aad4e9a8bb59cab56b6b1"},
+		{"tenant salted hash", tenant, contracts.SHA256Hash, saltedHash, []byte("on"),
+			"78ff79d156b195e8d31bc90b27fcec86a00742e0bc6bf82ac63418a95fe1f2cf"},
+		{"tenant without salts", tenant, contracts.SHA256Hash, sha256Hash, sha256Input, sha256Output},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := tt.hashProvider.Derive(tt.input)
+			result, err := DeriveHash(tt.ctx, tt.hashProvider, tt.input)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
 			assert.Equal(t, tt.output, result)
 		})
 	}
@@ -97,3 +124,261 @@ func TestSignAnnotation(t *testing.T) {
 		})
 	}
 }
+
+func TestSignWithActiveKey(t *testing.T) {
+	private := config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/private.key"}
+	public := config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/public.key"}
+
+	retired, current := private, private
+	retired.Id = "2024-01"
+	current.Id = "2024-06"
+	current.ActiveFrom = time.Now().Add(-time.Hour)
+	keys := config.SignatureInfo{
+		PrivateKey:  retired,
+		PrivateKeys: []config.KeyInfo{current},
+		PublicKey:   public,
+	}
+	keys.PublicKey.Id = "2024-06"
+	keys.PublicKeys = []config.KeyInfo{public}
+	keys.PublicKeys[0].Id = "2024-01"
+
+	signer := ed25519.New()
+	a := contracts.NewAnnotation("dummyKey", contracts.NoHash, "ubuntu", contracts.Host, contracts.AnnotationTPM, true)
+	err := SignWithActiveKey(keys, signer, &a)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-06", a.KeyId)
+
+	key, err := keys.VerificationKey(a.KeyId)
+	assert.NoError(t, err)
+	ok, err := VerifySignature(key, signer, a, keys.Canonicalization)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// The key id is covered by the signature
+	a.KeyId = "2024-01"
+	ok, err = VerifySignature(key, signer, a, keys.Canonicalization)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestSignWithActiveKey_Formats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format contracts.SignatureFormat
+		prefix string
+	}{
+		{"raw", contracts.RawFormat, ""},
+		{"jws", contracts.JWSFormat, "eyJ"},
+		{"cose", contracts.COSEFormat, "0o"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := config.SignatureInfo{
+				PrivateKey: config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/private.key", Id: "2024-06"},
+				PublicKey:  config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/public.key", Id: "2024-06"},
+				Format:     tt.format,
+			}
+
+			signer := ed25519.New()
+			a := contracts.NewAnnotation("dummyKey", contracts.NoHash, "ubuntu", contracts.Host, contracts.AnnotationTPM, true)
+			err := SignWithActiveKey(keys, signer, &a)
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(a.Signature, tt.prefix))
+
+			ok, err := VerifySignature(keys.PublicKey, signer, a, keys.Canonicalization)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			// The signature must cover the annotation it is attached to
+			a.IsSatisfied = false
+			ok, err = VerifySignature(keys.PublicKey, signer, a, keys.Canonicalization)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestSignBatchWithActiveKey(t *testing.T) {
+	private := config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/private.key", Id: "2024-06"}
+	public := config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/public.key", Id: "2024-06"}
+
+	tests := []struct {
+		name        string
+		format      contracts.SignatureFormat
+		count       int
+		expectError bool
+	}{
+		{"raw format", contracts.RawFormat, 3, false},
+		{"default format", "", 1, false},
+		{"jws format", contracts.JWSFormat, 3, true},
+		{"empty batch", contracts.RawFormat, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := config.SignatureInfo{PrivateKey: private, PublicKey: public, Format: tt.format}
+			items := make([]contracts.Annotation, tt.count)
+			for i := range items {
+				items[i] = contracts.NewAnnotation(fmt.Sprintf("key-%v", i), contracts.SHA256Hash, "ubuntu",
+					contracts.Host, contracts.AnnotationSource, true)
+			}
+
+			signer := ed25519.New()
+			err := SignBatchWithActiveKey(keys, signer, items)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+
+			for _, a := range items {
+				assert.Equal(t, "2024-06", a.KeyId)
+				ok, err := VerifySignature(public, signer, a, keys.Canonicalization)
+				assert.NoError(t, err)
+				assert.True(t, ok)
+
+				a.IsSatisfied = false
+				ok, err = VerifySignature(public, signer, a, keys.Canonicalization)
+				assert.NoError(t, err)
+				assert.False(t, ok)
+			}
+		})
+	}
+}
+
+func TestAnnotateBatch(t *testing.T) {
+	private := config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/private.key"}
+	public := config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/public.key"}
+	data := [][]byte{[]byte("reading-1"), []byte("reading-2"), []byte("reading-3")}
+
+	tests := []struct {
+		name   string
+		format contracts.SignatureFormat
+		batch  bool
+	}{
+		{"raw format", contracts.RawFormat, true},
+		{"jws format", contracts.JWSFormat, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.SdkInfo{
+				Hash:      config.HashInfo{Type: contracts.SHA256Hash},
+				Signature: config.SignatureInfo{PrivateKey: private, PublicKey: public, Format: tt.format},
+				Layer:     contracts.Host,
+			}
+			signer := ed25519.New()
+			a := NewSourceAnnotator(cfg, sha2562.New(), signer).(interfaces.BatchAnnotator)
+
+			items, err := a.DoBatch(context.Background(), data)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Len(t, items, len(data))
+			for i, item := range items {
+				assert.Equal(t, sha2562.New().Derive(data[i]), item.Key)
+				assert.Equal(t, tt.batch, merklesig.IsBatch(item.Signature))
+				ok, err := VerifySignature(public, signer, item, cfg.Signature.Canonicalization)
+				assert.NoError(t, err)
+				assert.True(t, ok)
+			}
+		})
+	}
+}
+
+func TestSignAndLink(t *testing.T) {
+	keys := config.SignatureInfo{
+		PrivateKey: config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/private.key"},
+	}
+	ctx := context.WithValue(context.Background(), contracts.ChainKey, NewChain())
+
+	// concurrent annotations are still appended one at a time, so every annotation is linked exactly once
+	items := make([]contracts.Annotation, 20)
+	var wg sync.WaitGroup
+	for i := range items {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			items[i] = contracts.NewAnnotation(fmt.Sprintf("key-%d", i), contracts.SHA256Hash, "host", contracts.Host,
+				contracts.AnnotationSource, true)
+			if err := SignAndLink(ctx, keys, ed25519.New(), &items[i]); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	links := make(map[string]int)
+	for _, a := range items {
+		links[a.PrevHash]++
+	}
+	for prev, n := range links {
+		assert.Equal(t, 1, n, "annotations fork from %q", prev)
+	}
+	for _, a := range items {
+		hash, err := ChainHash(a)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		delete(links, hash)
+	}
+	assert.Equal(t, map[string]int{"": 1}, links, "every other annotation links another one")
+
+	unchained := contracts.NewAnnotation("key", contracts.SHA256Hash, "host", contracts.Host,
+		contracts.AnnotationSource, true)
+	if err := SignAndLink(context.Background(), keys, ed25519.New(), &unchained); err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Empty(t, unchained.PrevHash)
+}
+
+func TestEncodings(t *testing.T) {
+	private := config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/private.key"}
+	tests := []struct {
+		name   string
+		keys   config.SignatureInfo
+		batch  bool
+		expect bool
+	}{
+		{"raw format", config.SignatureInfo{PrivateKey: private}, false, true},
+		{"jws format", config.SignatureInfo{PrivateKey: private, Format: contracts.JWSFormat}, false, true},
+		{"batch", config.SignatureInfo{PrivateKey: private}, true, true},
+		{"jcs canonicalization", config.SignatureInfo{PrivateKey: private,
+			Canonicalization: contracts.JCSCanonicalization}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := NewEncodings()
+			defer enc.Release()
+			ctx := context.WithValue(context.Background(), contracts.EncodingsKey, enc)
+
+			cfg := config.SdkInfo{Hash: config.HashInfo{Type: contracts.SHA256Hash}, Signature: tt.keys,
+				Layer: contracts.Host}
+			a := NewSourceAnnotator(cfg, sha2562.New(), ed25519.New())
+			var items []contracts.Annotation
+			if tt.batch {
+				var err error
+				items, err = a.(interfaces.BatchAnnotator).DoBatch(ctx, [][]byte{[]byte("a <b>"), []byte("c")})
+				if err != nil {
+					t.Fatalf(err.Error())
+				}
+			} else {
+				item, err := a.Do(ctx, []byte("a <b>"))
+				if err != nil {
+					t.Fatalf(err.Error())
+				}
+				items = append(items, item)
+			}
+
+			for _, item := range items {
+				encoded, ok := enc.Lookup(item)
+				assert.Equal(t, tt.expect, ok)
+				if ok {
+					expected, _ := json.Marshal(item)
+					assert.Equal(t, string(expected), string(encoded))
+				}
+			}
+			unsigned := items[0]
+			unsigned.Signature = ""
+			_, ok := enc.Lookup(unsigned)
+			assert.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package annotators
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// Chain links the annotations made by an SDK instance, each embedding the ChainHash of the one made before it as its
+// PrevHash, so that auditors can detect annotations that were removed or reordered.
+type Chain struct {
+	mu   sync.Mutex
+	last string // last is the ChainHash of the most recent annotation in the chain
+}
+
+// NewChain returns an empty Chain, whose first annotation has no PrevHash
+func NewChain() *Chain {
+	return &Chain{}
+}
+
+// ChainHash returns the hash of an annotation as linked by the next annotation in the chain. It covers every field
+// but the signature, which itself covers PrevHash.
+func ChainHash(a contracts.Annotation) (string, error) {
+	a.Signature = ""
+	buf := buffers.Get().(*[]byte)
+	defer buffers.Put(buf)
+	b, err := a.AppendJSON((*buf)[:0])
+	*buf = b
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SignAndLink signs the annotation as SignWithActiveKey does. If the context holds a Chain (see contracts.ChainKey),
+// the annotation is appended to it, the chain being held until it is signed so that PrevHash is covered by the
+// signature.
+func SignAndLink(ctx context.Context, keys config.SignatureInfo, signature interfaces.SignatureProvider,
+	a *contracts.Annotation) error {
+	chain, ok := ctx.Value(contracts.ChainKey).(*Chain)
+	if !ok {
+		return signWithActiveKey(keys, signature, a, EncodingsFrom(ctx))
+	}
+
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+	a.PrevHash = chain.last
+	if err := signWithActiveKey(keys, signature, a, EncodingsFrom(ctx)); err != nil {
+		return err
+	}
+	last, err := ChainHash(*a)
+	if err != nil {
+		return err
+	}
+	chain.last = last
+	return nil
+}
@@ -0,0 +1,107 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package annotators
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// buffers pools the buffers signature bases are encoded in, which are discarded once signed
+var buffers = sync.Pool{New: func() any {
+	b := make([]byte, 0, 1024)
+	return &b
+}}
+
+// encodings pools the Encodings of operations, see NewEncodings
+var encodings = sync.Pool{New: func() any { return &Encodings{} }}
+
+// Encodings keeps the JSON encodings of the annotations signed during an operation, so that publishing them does not
+// encode them again. The encoding of a signed annotation is its signature base with the signature set in place. It is
+// carried by the context of the operation, see contracts.EncodingsKey, and is safe for concurrent use by its
+// annotators.
+type Encodings struct {
+	mu    sync.Mutex
+	buf   []byte
+	spans []span
+}
+
+// span locates the encoding of the annotation with the given signature in the buffer of Encodings
+type span struct {
+	signature  string
+	start, end int
+}
+
+// NewEncodings returns empty Encodings, to be handed back with Release once the operation is done
+func NewEncodings() *Encodings {
+	return encodings.Get().(*Encodings)
+}
+
+// Release empties e for reuse by another operation. The encodings returned by Lookup are no longer valid.
+func (e *Encodings) Release() {
+	e.mu.Lock()
+	e.buf = e.buf[:0]
+	clear(e.spans)
+	e.spans = e.spans[:0]
+	e.mu.Unlock()
+	encodings.Put(e)
+}
+
+// Lookup returns the encoding of the signed annotation, if it was signed during the operation. The encoding is only
+// valid until e is released.
+func (e *Encodings) Lookup(a contracts.Annotation) ([]byte, bool) {
+	if e == nil || a.Signature == "" {
+		return nil, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, s := range e.spans {
+		if s.signature == a.Signature {
+			return e.buf[s.start:s.end:s.end], true
+		}
+	}
+	return nil, false
+}
+
+// keep records the encoding of an annotation signed with signature over base, a signature base without
+// canonicalization. The signature is set in place, before isSatisfied, following the order of the fields of
+// contracts.Annotation. isSatisfied cannot occur unescaped within a string, so the first occurrence is the field.
+func (e *Encodings) keep(signature string, base []byte) {
+	if e == nil {
+		return
+	}
+	i := bytes.Index(base, []byte(`"isSatisfied":`))
+	if i < 0 {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	start := len(e.buf)
+	e.buf = append(e.buf, base[:i]...)
+	e.buf = append(e.buf, `"signature":`...)
+	e.buf = contracts.AppendJSONString(e.buf, signature)
+	e.buf = append(e.buf, ',')
+	e.buf = append(e.buf, base[i:]...)
+	e.spans = append(e.spans, span{signature: signature, start: start, end: len(e.buf)})
+}
+
+// EncodingsFrom returns the Encodings held by the context (see contracts.EncodingsKey), nil if none
+func EncodingsFrom(ctx context.Context) *Encodings {
+	e, _ := ctx.Value(contracts.EncodingsKey).(*Encodings)
+	return e
+}
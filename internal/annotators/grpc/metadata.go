@@ -0,0 +1,121 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	handler "github.com/project-alvarium/alvarium-sdk-go/internal/annotators/http/handler"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// The metadata keys carrying the signature of a gRPC call, along with the parameters it covers
+const (
+	signatureKey = "alvarium-signature"
+	digestKey    = "alvarium-digest"
+	createdKey   = "alvarium-created"
+	keyidKey     = "alvarium-keyid"
+	algKey       = "alvarium-alg"
+)
+
+// parseResult holds the signature of a call found in its metadata, and the seed it was computed over
+type parseResult struct {
+	Seed      string
+	Signature string
+	Keyid     string
+	Algorithm string
+	Digest    string
+	Created   int64
+}
+
+// Marshal returns the wire form of a request message, which is hashed by the signature of a call and annotated on
+// receipt. Messages are marshalled deterministically for the client and the server to obtain the same bytes.
+func Marshal(msg any) ([]byte, error) {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("message of type %T is not a protocol buffer", msg)
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(m)
+}
+
+// Sign returns the metadata carrying the signature of a call to method, the full method name of the form
+// /package.Service/Method. The signature covers the method, the Content-Digest of the request message if data is
+// given, and its own parameters. Streams are signed without a message, their metadata being sent before any.
+func Sign(ticks time.Time, method string, data []byte, keys config.SignatureInfo,
+	signer interfaces.SignatureProvider) (metadata.MD, error) {
+	// calls are signed with the key annotations are, as rotated by keys.PrivateKeys
+	key := keys.ActivePrivateKey(time.Now())
+	keyid, alg := handler.KeyParams(keys, key)
+	md := metadata.Pairs(
+		createdKey, strconv.FormatInt(ticks.Unix(), 10),
+		keyidKey, keyid,
+		algKey, alg,
+	)
+	if data != nil {
+		md.Set(digestKey, handler.ContentDigest(data))
+	}
+
+	signature, err := signer.Sign(key, []byte(signatureBase(method, md)))
+	if err != nil {
+		return nil, err
+	}
+	md.Set(signatureKey, signature)
+	return md, nil
+}
+
+// parseSignature returns the signature of a call to method found in its metadata
+func parseSignature(method string, md metadata.MD) (parseResult, error) {
+	get := func(key string) string {
+		if values := md.Get(key); len(values) == 1 {
+			return values[0]
+		}
+		return ""
+	}
+	p := parseResult{
+		Seed:      signatureBase(method, md),
+		Signature: get(signatureKey),
+		Keyid:     get(keyidKey),
+		Algorithm: get(algKey),
+		Digest:    get(digestKey),
+	}
+	if p.Signature == "" {
+		return parseResult{}, errors.New("no signature found in the metadata of " + method)
+	}
+	created, err := strconv.ParseInt(get(createdKey), 10, 64)
+	if err != nil {
+		return parseResult{}, fmt.Errorf("invalid %s metadata: %w", createdKey, err)
+	}
+	p.Created = created
+	return p, nil
+}
+
+// signatureBase returns the content signed for a call, one line per covered value in a fixed order. A value given
+// more than once is covered with each of its occurrences, which then fail verification.
+func signatureBase(method string, md metadata.MD) string {
+	lines := []string{"method: " + method}
+	for _, key := range []string{digestKey, createdKey, keyidKey, algKey} {
+		for _, v := range md.Get(key) {
+			lines = append(lines, strings.TrimPrefix(key, "alvarium-")+": "+v)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
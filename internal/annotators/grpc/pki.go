@@ -0,0 +1,100 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	handler "github.com/project-alvarium/alvarium-sdk-go/internal/annotators/http/handler"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// GrpcPkiAnnotator is used to validate whether the signature on a given piece of data is valid, sent in the metadata
+// of the gRPC call found in the Context. The call is that served by the server whose interceptor annotates the data.
+type GrpcPkiAnnotator struct {
+	hash      interfaces.HashProvider
+	hashType  contracts.HashType
+	kind      contracts.AnnotationType
+	signature interfaces.SignatureProvider
+	keys      config.SignatureInfo
+	layer     contracts.LayerType
+}
+
+func NewGrpcPkiAnnotator(cfg config.SdkInfo, hash interfaces.HashProvider, sign interfaces.SignatureProvider) interfaces.Annotator {
+	a := GrpcPkiAnnotator{}
+	a.hash = hash
+	a.hashType = cfg.Hash.Type
+	a.kind = contracts.AnnotationPKIGrpc
+	a.signature = sign
+	a.keys = cfg.Signature
+	a.layer = cfg.Layer
+	return &a
+}
+
+// Kind returns the kind of annotation made by the annotator
+func (a *GrpcPkiAnnotator) Kind() contracts.AnnotationType {
+	return a.kind
+}
+
+func (a *GrpcPkiAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
+	key, err := annotators.DeriveHash(ctx, a.hash, data)
+	if err != nil {
+		return contracts.Annotation{}, err
+	}
+	hostname, _ := os.Hostname()
+
+	method, ok := grpc.Method(ctx)
+	md, hasMetadata := metadata.FromIncomingContext(ctx)
+	if !ok || !hasMetadata {
+		return contracts.Annotation{}, errors.New("no incoming gRPC call found in context")
+	}
+	parsed, err := parseSignature(method, md)
+	if err != nil {
+		return contracts.Annotation{}, err
+	}
+
+	// Use the parsed metadata to obtain the key name and type we should use to validate the signature
+	k, err := handler.VerificationKey(a.keys, parsed.Keyid, parsed.Algorithm)
+	if err != nil {
+		return contracts.Annotation{}, err
+	}
+	verified, err := a.signature.Verify(k, []byte(parsed.Seed), []byte(parsed.Signature))
+	if err != nil {
+		return contracts.Annotation{}, err
+	}
+	// the data must match the digest of the request message, which binds it to the signature. Messages of streams
+	// are not bound, the signature of a stream only covering its method.
+	if verified && parsed.Digest != "" {
+		verified = handler.VerifyContentDigest(parsed.Digest, data) == nil
+	}
+
+	annotation := contracts.NewAnnotation(string(key), a.hashType, hostname, a.layer, a.kind, verified)
+	annotation.Timestamp = annotators.Timestamp(ctx, annotation)
+	annotation.ParentKey = annotators.ParentKey(ctx)
+	annotation.Tag = annotators.TagValue(ctx, annotation)
+	annotation.HostInfo = annotators.HostInfo(ctx)
+	annotation.Id = annotators.NewId(ctx, annotation)
+	if err = annotators.SignAndLink(ctx, a.keys, a.signature, &annotation); err != nil {
+		return contracts.Annotation{}, err
+	}
+	return annotation, nil
+}
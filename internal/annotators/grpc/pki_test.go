@@ -0,0 +1,143 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	hash256 "github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/sha256"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const testMethod = "/test.Echo/Call"
+
+// serverStream is the transport stream of the call served, giving its method to grpc.Method
+type serverStream struct {
+	grpc.ServerTransportStream
+	method string
+}
+
+func (s serverStream) Method() string {
+	return s.method
+}
+
+// incomingContext returns the context of a server handling a call to testMethod with the given metadata
+func incomingContext(md metadata.MD) context.Context {
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), serverStream{method: testMethod})
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+func TestGrpcPkiAnnotator_Do(t *testing.T) {
+	b, err := os.ReadFile("./test/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	data, err := Marshal(wrapperspb.String("hello"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	signed := func(data []byte) metadata.MD {
+		md, err := Sign(time.Now(), testMethod, data, cfg.Signature, ed25519.New())
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		return md
+	}
+	with := func(md metadata.MD, key string, value ...string) metadata.MD {
+		md = md.Copy()
+		md.Set(key, value...)
+		return md
+	}
+	without := func(md metadata.MD, key string) metadata.MD {
+		md = md.Copy()
+		md.Delete(key)
+		return md
+	}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		data        []byte
+		satisfied   bool
+		expectError bool
+	}{
+		{"pki annotation OK", incomingContext(signed(data)), data, true, false},
+		{"pki stream", incomingContext(signed(nil)), data, true, false},
+		{"pki other data", incomingContext(signed(data)), []byte("other"), false, false},
+		{"pki invalid signature", incomingContext(with(signed(data), signatureKey, "invalid")), data, false, false},
+		{"pki other method", metadata.NewIncomingContext(grpc.NewContextWithServerTransportStream(context.Background(),
+			serverStream{method: "/test.Echo/Other"}), signed(data)), data, false, false},
+		{"pki digest removed", incomingContext(without(signed(data), digestKey)), data, false, false},
+		{"pki bad key type", incomingContext(with(signed(data), algKey, "invalid")), data, false, true},
+		{"pki key not found", incomingContext(with(signed(data), keyidKey, "invalid")), data, false, true},
+		{"pki keyid outside key directory", incomingContext(with(signed(data), keyidKey, "../public.key")), data, false, true},
+		{"pki no signature", incomingContext(metadata.MD{}), data, false, true},
+		{"pki invalid created", incomingContext(with(signed(data), createdKey, "now")), data, false, true},
+		{"pki no call", context.Background(), data, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := ed25519.New()
+			pki := NewGrpcPkiAnnotator(cfg, hash256.New(), s)
+			anno, err := pki.Do(tt.ctx, tt.data)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				result, err := annotators.VerifySignature(cfg.Signature.PublicKey, s, anno, cfg.Signature.Canonicalization)
+				if err != nil {
+					t.Error(err.Error())
+				} else if !result {
+					t.Error("signature not verified")
+				}
+				if anno.IsSatisfied != tt.satisfied {
+					t.Errorf("satisfied should be %v", tt.satisfied)
+				}
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	a, err := Marshal(wrapperspb.String("hello"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	b, err := Marshal(wrapperspb.String("hello"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if string(a) != string(b) {
+		t.Error("marshalling is not deterministic")
+	}
+	if _, err := Marshal("hello"); err == nil {
+		t.Error("expected error marshalling a string")
+	}
+}
@@ -18,45 +18,247 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 )
 
 type parseResult struct {
+	Label     string // Label identifies a countersignature added by an intermediary, the original one has none
 	Seed      string
 	Signature string
 	Keyid     string
 	Algorithm string
+	Created   int64  // Created is the creation time of the signature in Unix seconds, or zero if absent
+	Expires   int64  // Expires is the expiration time of the signature in Unix seconds, or zero if absent
+	Nonce     string // Nonce is the nonce of the signature, if any
+	// Components are the identifiers of the components covered, field names lowercased, such as "content-type"
+	Components []string
+}
+
+// message is an HTTP message whose signatures are parsed or added
+type message struct {
+	header  http.Header
+	request *http.Request // request is the request, or the one a response answers when known
+	status  int           // status is the status code of a response, zero for a request
+}
+
+func requestMessage(r *http.Request) message {
+	return message{header: r.Header, request: r}
+}
+
+func responseMessage(r *http.Response) message {
+	return message{header: r.Header, request: r.Request, status: r.StatusCode}
+}
+
+// component returns the values of a derived component in the format of earlier releases, where those derived from
+// the request do not apply to responses
+func (m message) component(component contracts.DerivedComponent) ([]string, error) {
+	if m.status == 0 {
+		return requestComponent(m.request, component)
+	}
+	if component == contracts.Status {
+		return []string{strconv.Itoa(m.status)}, nil
+	}
+	return nil, fmt.Errorf("Unhandled Specialty Component %s", component)
 }
 
 // ParseSignature returns an object that contains seed, signature, keyid and algorithm used in signing
 // builds the seed from the signatureInput header sent in the request,
 // extracts keyid and algorithm from the signatureInput, extracts the signature from the request.
+// The original signature of the request is parsed, see ParseSignatures for those added by intermediaries.
+// Signatures in the format of RFC 9421 and in that of earlier releases are both parsed, the seed being the signature
+// base of the RFC for the former.
 
 func ParseSignature(r *http.Request) (parseResult, error) {
-	//Signature Inputs extraction
-	signatureInput := r.Header.Get("Signature-Input")
-	signature := r.Header.Get("Signature")
+	return original(ParseSignatures(r))
+}
+
+// ParseSignatures parses each signature of a request: the original one and the countersignatures labeled by the
+// intermediaries the request went through.
+func ParseSignatures(r *http.Request) ([]parseResult, error) {
+	return parseRequest(r)
+}
+
+// parseRequest parses the signature of a request with the given label, or each signature if no label is given
+func parseRequest(r *http.Request, label ...string) ([]parseResult, error) {
+	if !r.URL.IsAbs() {
+		return nil, fmt.Errorf("URL is not absolute")
+	}
+	return parseSignatures(requestMessage(r), label...)
+}
+
+// ParseResponseSignature returns the seed, signature, keyid and algorithm of a signed response, as ParseSignature
+// does for a request. The status code is covered by the @status derived component, those derived from the request
+// only apply to responses in the format of RFC 9421, with the req parameter, when the request is known.
+func ParseResponseSignature(r *http.Response) (parseResult, error) {
+	return original(ParseResponseSignatures(r))
+}
+
+// ParseResponseSignatures parses each signature of a response, as ParseSignatures does for a request
+func ParseResponseSignatures(r *http.Response) ([]parseResult, error) {
+	return parseSignatures(responseMessage(r))
+}
+
+// ParseMessage parses the signature of message, either an *http.Request or an *http.Response
+func ParseMessage(message any) (parseResult, error) {
+	return original(ParseMessages(message))
+}
+
+// ParseMessages parses each signature of message, either an *http.Request or an *http.Response
+func ParseMessages(message any) ([]parseResult, error) {
+	switch m := message.(type) {
+	case *http.Request:
+		return ParseSignatures(m)
+	case *http.Response:
+		return ParseResponseSignatures(m)
+	}
+	return nil, fmt.Errorf("no HTTP message to parse, found %T", message)
+}
+
+// original returns the unlabeled signature among parsed, or the one labeled DefaultLabel, or else the first
+func original(parsed []parseResult, err error) (parseResult, error) {
+	if err != nil {
+		return parseResult{}, err
+	}
+	for _, p := range parsed {
+		if p.Label == "" || p.Label == DefaultLabel {
+			return p, nil
+		}
+	}
+	return parsed[0], nil
+}
+
+// labelPattern matches the labels of signatures, keys of a structured field dictionary
+var labelPattern = regexp.MustCompile(`^[a-z][a-z0-9_.-]*$`)
+
+// IsLabel reports whether label can identify a countersignature: a lowercase letter followed by lowercase letters,
+// digits, "_", "-" or "."
+func IsLabel(label string) bool {
+	return labelPattern.MatchString(label)
+}
+
+// members returns the members of the Signature-Input or Signature header in the format of earlier releases, each
+// holding one signature. The original signature is unlabeled, those added by intermediaries are of the form
+// label=value.
+func members(values []string) []string {
+	var list []string
+	for _, v := range values {
+		for _, m := range strings.Split(v, ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				list = append(list, m)
+			}
+		}
+	}
+	return list
+}
+
+// splitMember returns the label and value of a member of the Signature-Input or Signature header in the format of
+// earlier releases
+func splitMember(member string) (string, string) {
+	label, value, found := strings.Cut(member, "=")
+	// signature values, such as base64 ones, may contain = themselves
+	if found && labelPattern.MatchString(label) && value != "" && value[0] != '=' {
+		return label, value
+	}
+	return "", member
+}
 
+// setMember sets the value of the member of the Signature-Input or Signature header with the given label, in the
+// format of earlier releases, keeping the others. The original signature is kept first.
+func setMember(header http.Header, name string, label string, value string) {
+	var list []string
+	for _, m := range members(header.Values(name)) {
+		if l, _ := splitMember(m); l != label {
+			list = append(list, m)
+		}
+	}
+	if label == "" {
+		list = append([]string{value}, list...)
+	} else {
+		list = append(list, label+"="+value)
+	}
+	header.Set(name, strings.Join(list, ", "))
+}
+
+// parseSignatures parses the signature of a message with the given label, or each signature if no label is given
+func parseSignatures(msg message, label ...string) ([]parseResult, error) {
+	header := msg.header
+	if isStructured(header) {
+		return parseStructuredSignatures(msg, label...)
+	}
+
+	signatures := make(map[string]string)
+	for _, m := range members(header.Values("Signature")) {
+		l, v := splitMember(m)
+		signatures[l] = v
+	}
+
+	var parsed []parseResult
+	for _, m := range members(header.Values("Signature-Input")) {
+		l, input := splitMember(m)
+		if len(label) > 0 && l != label[0] {
+			continue
+		}
+		p, err := parseSignature(msg, input)
+		if err != nil {
+			return nil, err
+		}
+		p.Label = l
+		p.Signature = signatures[l]
+		parsed = append(parsed, p)
+	}
+	if len(parsed) == 0 {
+		return nil, fmt.Errorf("malformed Signature-Input %q", header.Get("Signature-Input"))
+	}
+	return parsed, nil
+}
+
+// parseSignature parses the signature input of one signature in the format of earlier releases, leaving its label
+// and signature to the caller
+func parseSignature(msg message, signatureInput string) (parseResult, error) {
+	header := msg.header
 	signatureInputList := strings.SplitN(signatureInput, ";", 2)
+	if len(signatureInputList) < 2 {
+		return parseResult{}, fmt.Errorf("malformed Signature-Input %q", signatureInput)
+	}
 
 	signatureInputHeader := strings.Fields(signatureInputList[0])
 	signatureInputTail := signatureInputList[1]
 
-	var keyid, algorithm string
+	var keyid, algorithm, nonce string
+	var created, expires int64
 
 	signatureInputParsedTail := strings.Split(signatureInputTail, ";")
 	for _, s := range signatureInputParsedTail {
-
-		if strings.Contains(s, "alg") {
-			raw := strings.Split(s, "=")[1]
-			algorithm = strings.Trim(raw, "\"")
+		// the list of parameters may end with a semicolon
+		if strings.TrimSpace(s) == "" {
+			continue
 		}
-
-		if strings.Contains(s, "keyid") {
-			raw := strings.Split(s, "=")[1]
-			keyid = strings.Trim(raw, "\"")
+		name, raw, found := strings.Cut(s, "=")
+		if !found {
+			return parseResult{}, fmt.Errorf("malformed parameter %q", s)
+		}
+		value, err := paramValue(raw)
+		if err != nil {
+			return parseResult{}, err
+		}
+		switch strings.TrimSpace(name) {
+		case "alg":
+			algorithm = value
+		case "keyid":
+			keyid = value
+		case "nonce":
+			nonce = value
+		case "created":
+			created, err = strconv.ParseInt(value, 10, 64)
+		case "expires":
+			expires, err = strconv.ParseInt(value, 10, 64)
+		}
+		if err != nil {
+			return parseResult{}, fmt.Errorf("malformed %s parameter %q", name, raw)
 		}
 	}
 
@@ -64,54 +266,27 @@ func ParseSignature(r *http.Request) (parseResult, error) {
 
 	var signatureInputBody strings.Builder
 	var s parseResult
-
-	if !r.URL.IsAbs() {
-		return s, fmt.Errorf("URL is not absolute")
-	}
+	var components []string
 
 	for _, field := range signatureInputHeader {
 		//remove double quotes from the field to access it directly in the header map
-		key := field[1 : len(field)-1]
-		if key[0:1] == "@" {
-			switch contracts.DerivedComponent(key) {
-			case contracts.Method:
-				signatureInputFields[key] = []string{r.Method}
-			case contracts.TargetURI:
-				signatureInputFields[key] = []string{r.URL.String()}
-			case contracts.Authority:
-				signatureInputFields[key] = []string{r.Host}
-			case contracts.Scheme:
-				signatureInputFields[key] = []string{r.URL.Scheme}
-			case contracts.Path:
-				signatureInputFields[key] = []string{r.URL.Path}
-			case contracts.Query:
-				var query string = "?"
-				query += r.URL.RawQuery
-				signatureInputFields[key] = []string{query}
-			case contracts.QueryParams:
-				rawQueryParams := strings.Split(r.URL.RawQuery, "&")
-				var queryParams []string
-				for _, rawQueryParam := range rawQueryParams {
-					if rawQueryParam != "" {
-						parameter := strings.Split(rawQueryParam, "=")
-						name := parameter[0]
-						value := parameter[1]
-						b := new(bytes.Buffer)
-						fmt.Fprintf(b, ";name=\"%s\": %s", name, value)
-						queryParams = append(queryParams, b.String())
-					}
-				}
-				signatureInputFields[key] = queryParams
-			default:
-				return s, fmt.Errorf("Unhandled Specialty Component %s", key)
+		key, err := componentName(field)
+		if err != nil {
+			return s, err
+		}
+		if key[0] == '@' {
+			values, err := msg.component(contracts.DerivedComponent(key))
+			if err != nil {
+				return s, err
 			}
+			signatureInputFields[key] = values
 		} else {
-			fieldValues := r.Header.Values(key)
+			fieldValues := header.Values(key)
 
 			if len(fieldValues) == 0 {
 				return s, fmt.Errorf("Header field not found %s", key)
 			} else if len(fieldValues) == 1 {
-				value := removeExtraSpaces(r.Header.Get(key))
+				value := removeExtraSpaces(header.Get(key))
 				signatureInputFields[key] = []string{value}
 
 			} else {
@@ -128,6 +303,7 @@ func ParseSignature(r *http.Request) (parseResult, error) {
 				signatureInputFields[key] = []string{fieldValue}
 			}
 		}
+		components = append(components, `"`+strings.ToLower(key)+`"`)
 		// Construct final output string
 		keyValues := signatureInputFields[key]
 		if len(keyValues) == 1 {
@@ -140,11 +316,77 @@ func ParseSignature(r *http.Request) (parseResult, error) {
 	}
 
 	parsedSignatureInput := fmt.Sprintf("%s;%s", signatureInputBody.String(), signatureInputTail)
-	s = parseResult{Seed: parsedSignatureInput, Signature: signature, Keyid: keyid, Algorithm: algorithm}
+	s = parseResult{Seed: parsedSignatureInput, Keyid: keyid, Algorithm: algorithm, Created: created,
+		Expires: expires, Nonce: nonce, Components: components}
 
 	return s, nil
 }
 
+// componentName returns the name of a component covered by a signature in the format of earlier releases, given as
+// a non-empty quoted string
+func componentName(field string) (string, error) {
+	if len(field) < 3 || field[0] != '"' || field[len(field)-1] != '"' {
+		return "", fmt.Errorf("malformed component %q", field)
+	}
+	name := field[1 : len(field)-1]
+	if strings.ContainsRune(name, '"') {
+		return "", fmt.Errorf("malformed component %q", field)
+	}
+	return name, nil
+}
+
+// paramValue returns the value of a signature parameter in the format of earlier releases, either a token or a
+// quoted string
+func paramValue(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("malformed parameter value %q", raw)
+	}
+	if raw[0] != '"' {
+		if strings.ContainsAny(raw, "\" \t") {
+			return "", fmt.Errorf("malformed parameter value %q", raw)
+		}
+		return raw, nil
+	}
+	if len(raw) < 2 || raw[len(raw)-1] != '"' || strings.ContainsRune(raw[1:len(raw)-1], '"') {
+		return "", fmt.Errorf("unterminated parameter value %q", raw)
+	}
+	return raw[1 : len(raw)-1], nil
+}
+
+// requestComponent returns the values of a component derived from the request
+func requestComponent(r *http.Request, component contracts.DerivedComponent) ([]string, error) {
+	switch component {
+	case contracts.Method:
+		return []string{r.Method}, nil
+	case contracts.TargetURI:
+		return []string{r.URL.String()}, nil
+	case contracts.Authority:
+		return []string{r.Host}, nil
+	case contracts.Scheme:
+		return []string{r.URL.Scheme}, nil
+	case contracts.Path:
+		return []string{r.URL.Path}, nil
+	case contracts.Query:
+		var query string = "?"
+		query += r.URL.RawQuery
+		return []string{query}, nil
+	case contracts.QueryParams:
+		rawQueryParams := strings.Split(r.URL.RawQuery, "&")
+		var queryParams []string
+		for _, rawQueryParam := range rawQueryParams {
+			if rawQueryParam != "" {
+				name, value, _ := strings.Cut(rawQueryParam, "=")
+				b := new(bytes.Buffer)
+				fmt.Fprintf(b, ";name=\"%s\": %s", name, value)
+				queryParams = append(queryParams, b.String())
+			}
+		}
+		return queryParams, nil
+	}
+	return nil, fmt.Errorf("Unhandled Specialty Component %s", component)
+}
+
 func removeExtraSpaces(s string) string {
 	return strings.Join(strings.Fields(s), " ")
 }
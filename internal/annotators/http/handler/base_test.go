@@ -88,3 +88,54 @@ func TestHttpPkiAnnotator_RequestParser(t *testing.T) {
 		assert.Equal(t, "ed25519", parsed.Algorithm)
 	})
 }
+
+func TestHttpPkiAnnotator_ResponseParser(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header: http.Header{
+			"Date":            []string{"Tue, 20 Apr 2021 02:07:55 GMT"},
+			"Content-Type":    []string{"application/json"},
+			"Signature-Input": []string{""},
+			"Signature":       []string{"whatever"},
+		},
+	}
+
+	seedTests := []struct {
+		name           string
+		signatureInput string
+		expectedSeed   string
+		expectError    bool
+	}{
+		{"testing @status with header fields", "\"@status\" \"content-type\" \"date\";created=1644758607;keyid=\"public.key\";alg=\"ed25519\";",
+			"\"@status\" 201\n\"content-type\" application/json\n\"date\" Tue, 20 Apr 2021 02:07:55 GMT\n;created=1644758607;keyid=\"public.key\";alg=\"ed25519\";", false},
+		{"testing request component", "\"@method\";", "", true},
+		{"testing non-existant header field", "\"x-test\";", "", true},
+		{"testing malformed signature input", "\"@status\"", "", true},
+	}
+
+	for _, tt := range seedTests {
+		resp.Header.Set("Signature-Input", tt.signatureInput)
+
+		t.Run(tt.name, func(t *testing.T) {
+			signatureInfo, err := ParseMessage(resp)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.Equal(t, tt.expectedSeed, signatureInfo.Seed)
+				assert.Equal(t, "whatever", signatureInfo.Signature)
+			}
+		})
+	}
+
+	t.Run("testing @status of a request", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://www.example.com/foo", nil)
+		req.Header.Set("Signature-Input", "\"@status\";")
+		_, err := ParseMessage(req)
+		assert.Error(t, err)
+	})
+
+	t.Run("testing missing message", func(t *testing.T) {
+		_, err := ParseMessage(nil)
+		assert.Error(t, err)
+	})
+}
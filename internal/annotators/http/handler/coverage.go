@@ -0,0 +1,132 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// DefaultResponseFields are the components covered by the signatures of responses when none are given
+var DefaultResponseFields = []string{string(contracts.Status), contracts.HttpContentType, contracts.ContentDigest}
+
+// policy returns the coverage policy of the signatures of requests, or of responses, set by keys.Http
+func policy(keys config.SignatureInfo, response bool) *config.HttpCoverageInfo {
+	if keys.Http == nil {
+		return nil
+	}
+	if response {
+		return keys.Http.Response
+	}
+	return keys.Http.Request
+}
+
+// coverage returns the components covered by default by the signatures of requests, or of responses, and those among
+// them that are required. Without a policy, the default components are covered when the message has them.
+func coverage(keys config.SignatureInfo, response bool) (fields []string, required []string) {
+	p := policy(keys, response)
+	if p == nil {
+		if response {
+			return DefaultResponseFields, nil
+		}
+		return DefaultFields, nil
+	}
+	return append(append([]string(nil), p.Required...), p.Optional...), p.Required
+}
+
+// presentFields returns the fields a message with the given header has, derived components always applying. An error
+// wrapping contracts.ErrComponentNotCovered is returned when a required header field is missing.
+func presentFields(header http.Header, fields []string, required []string) ([]string, error) {
+	present := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if strings.HasPrefix(f, "@") || strings.HasPrefix(f, `"`) || len(header.Values(f)) > 0 {
+			present = append(present, f)
+		} else if contains(required, f) {
+			return nil, fmt.Errorf("%w: required header field %s not found", contracts.ErrComponentNotCovered, f)
+		}
+	}
+	return present, nil
+}
+
+// VerifyCoverage returns an error wrapping contracts.ErrComponentNotCovered if a signature of message, either an
+// *http.Request or an *http.Response, does not cover each component required by keys.Http
+func VerifyCoverage(message any, signatures []parseResult, keys config.SignatureInfo) error {
+	var p *config.HttpCoverageInfo
+	var r *http.Request
+	switch m := message.(type) {
+	case *http.Request:
+		p, r = policy(keys, false), m
+	case *http.Response:
+		p = policy(keys, true)
+	}
+	if p == nil {
+		return nil
+	}
+	for _, parsed := range signatures {
+		if err := checkCoverage(parsed, p.Required, r); err != nil {
+			if parsed.Label != "" {
+				return fmt.Errorf("signature %s: %w", parsed.Label, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCoverage returns an error wrapping contracts.ErrComponentNotCovered naming the first required component that
+// a signature does not cover. @query-params is covered by a @query-param component for each query parameter of r.
+func checkCoverage(parsed parseResult, required []string, r *http.Request) error {
+	covered := make(map[string]bool, len(parsed.Components))
+	for _, c := range parsed.Components {
+		covered[c] = true
+	}
+	for _, f := range required {
+		if covered[identifier(f)] {
+			continue
+		}
+		if f == string(contracts.QueryParams) && r != nil {
+			missing := false
+			for _, pair := range strings.Split(r.URL.RawQuery, "&") {
+				k, _, _ := strings.Cut(pair, "=")
+				id := serializeItem(sfItem{value: string(contracts.QueryParam),
+					params: sfParams{{key: "name", value: encodeQueryComponent(k)}}})
+				missing = missing || (pair != "" && !covered[id])
+			}
+			if !missing {
+				continue
+			}
+		}
+		return fmt.Errorf("%w: %s", contracts.ErrComponentNotCovered, f)
+	}
+	return nil
+}
+
+// identifier returns the component identifier of a component name, field names being lowercase
+func identifier(name string) string {
+	return serializeItem(sfItem{value: strings.ToLower(name)})
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
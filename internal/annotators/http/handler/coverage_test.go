@@ -0,0 +1,171 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddSignatureHeaders_Coverage(t *testing.T) {
+	b, err := os.ReadFile("./test/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	withPolicy := func(request, response *config.HttpCoverageInfo) config.SignatureInfo {
+		keys := cfg.Signature
+		keys.Http = &config.HttpSignatureInfo{Request: request, Response: response}
+		return keys
+	}
+
+	tests := []struct {
+		name        string
+		keys        config.SignatureInfo
+		response    bool
+		covered     []string
+		expectError bool
+	}{
+		{"default request", cfg.Signature, false,
+			[]string{`"@method"`, `"@path"`, `"@authority"`, `"content-type"`}, false},
+		{"default response", cfg.Signature, true, []string{`"@status"`, `"content-type"`}, false},
+		{"request policy", withPolicy(&config.HttpCoverageInfo{Required: []string{"@method", "Content-Type"},
+			Optional: []string{"@query-params", "X-Missing"}}, nil), false,
+			[]string{`"@method"`, `"content-type"`, `"@query-param";name="var1"`, `"@query-param";name="var2"`}, false},
+		{"response policy", withPolicy(nil, &config.HttpCoverageInfo{Required: []string{"@status"}}), true,
+			[]string{`"@status"`}, false},
+		{"required field missing", withPolicy(&config.HttpCoverageInfo{Required: []string{"X-Missing"}}, nil), false,
+			nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var signatures []parseResult
+			if tt.response {
+				w := httptest.NewRecorder()
+				w.Header().Set("Content-Type", string(contracts.ContentTypeJSON))
+				err = NewEd25519ResponseHandler(w.Header(), http.StatusOK).AddSignatureHeaders(time.Now(), nil, tt.keys)
+				if err == nil {
+					w.WriteHeader(http.StatusOK)
+					signatures, err = ParseResponseSignatures(w.Result())
+				}
+			} else {
+				req := httptest.NewRequest("POST", "http://example.com/foo?var1=&var2=2", nil)
+				req.Header.Set("Content-Type", string(contracts.ContentTypeJSON))
+				err = NewEd25519RequestHandler(req).AddSignatureHeaders(time.Now(), nil, tt.keys)
+				if err == nil {
+					signatures, err = ParseSignatures(req)
+				}
+			}
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				assert.True(t, errors.Is(err, contracts.ErrComponentNotCovered))
+				return
+			}
+			assert.Equal(t, tt.covered, signatures[0].Components)
+		})
+	}
+}
+
+func TestVerifyCoverage(t *testing.T) {
+	b, err := os.ReadFile("./test/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	required := func(components ...string) config.SignatureInfo {
+		keys := cfg.Signature
+		keys.Http = &config.HttpSignatureInfo{Request: &config.HttpCoverageInfo{Required: components}}
+		return keys
+	}
+	signed := func(legacy bool, fields ...string) *http.Request {
+		req := httptest.NewRequest("POST", "http://example.com/foo?var1=&var2=2", nil)
+		req.Header.Set("Content-Type", string(contracts.ContentTypeJSON))
+		keys := cfg.Signature
+		keys.Http = &config.HttpSignatureInfo{Legacy: legacy}
+		if err := NewEd25519RequestHandler(req).AddSignatureHeaders(time.Now(), fields, keys); err != nil {
+			t.Fatalf(err.Error())
+		}
+		return req
+	}
+
+	tests := []struct {
+		name        string
+		req         *http.Request
+		keys        config.SignatureInfo
+		expectError bool
+	}{
+		{"no policy", signed(false, "@method"), cfg.Signature, false},
+		{"covered", signed(false, "@method", "Content-Type"), required("@method", "content-type"), false},
+		{"covered in legacy format", signed(true, "@method", "Content-Type"), required("@method", "Content-Type"), false},
+		{"not covered", signed(false, "@method"), required("@method", "Content-Type"), true},
+		{"not covered in legacy format", signed(true, "@method"), required("Content-Type"), true},
+		{"query parameters covered", signed(false, "@query-params"), required("@query-params"), false},
+		{"query parameter not covered", signed(false, `"@query-param";name="var1"`), required("@query-params"), true},
+		{"query parameters covered in legacy format", signed(true, "@query-params"), required("@query-params"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signatures, err := ParseSignatures(tt.req)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			err = VerifyCoverage(tt.req, signatures, tt.keys)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				assert.True(t, errors.Is(err, contracts.ErrComponentNotCovered))
+			}
+		})
+	}
+
+	t.Run("countersignature not covering", func(t *testing.T) {
+		req := signed(false, "@method", "Content-Type")
+		err := NewEd25519RequestHandlerWithLabel(req, "gateway").AddSignatureHeaders(time.Now(), []string{"@method"},
+			cfg.Signature)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		signatures, err := ParseSignatures(req)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		err = VerifyCoverage(req, signatures, required("Content-Type"))
+		if assert.Error(t, err) {
+			assert.True(t, strings.HasPrefix(err.Error(), "signature gateway: "))
+		}
+	})
+}
@@ -0,0 +1,99 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// digestAlgorithms are the algorithms of the Content-Digest header (RFC 9530) that are verified
+var digestAlgorithms = map[string]func() hash.Hash{
+	"sha-256": sha256.New,
+	"sha-512": sha512.New,
+}
+
+// ErrContentDigest is returned when the body of a message does not match its Content-Digest header
+var ErrContentDigest = errors.New("content digest mismatch")
+
+// ContentDigest returns the value of the Content-Digest header of a message with the given body, a sha-256 digest.
+// A signature covering the header binds the body, which Content-Length does not.
+func ContentDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+}
+
+// VerifyContentDigest checks body against the value of a Content-Digest header. Each digest made with a supported
+// algorithm must match, and at least one must be given.
+func VerifyContentDigest(value string, body []byte) error {
+	verified := 0
+	for _, member := range strings.Split(value, ",") {
+		alg, digest, found := strings.Cut(strings.TrimSpace(member), "=")
+		if !found {
+			return fmt.Errorf("malformed %s %q", contracts.ContentDigest, value)
+		}
+		newHash, ok := digestAlgorithms[strings.ToLower(alg)]
+		if !ok {
+			continue
+		}
+		// the digest is a structured field byte sequence, base64 between colons, possibly followed by parameters
+		digest, _, _ = strings.Cut(digest, ";")
+		if len(digest) < 2 || digest[0] != ':' || digest[len(digest)-1] != ':' {
+			return fmt.Errorf("malformed %s %q", contracts.ContentDigest, value)
+		}
+		expected, err := base64.StdEncoding.DecodeString(digest[1 : len(digest)-1])
+		if err != nil {
+			return fmt.Errorf("malformed %s %q: %w", contracts.ContentDigest, value, err)
+		}
+		h := newHash()
+		h.Write(body)
+		if subtle.ConstantTimeCompare(h.Sum(nil), expected) != 1 {
+			return fmt.Errorf("%w for %s", ErrContentDigest, alg)
+		}
+		verified++
+	}
+	if verified == 0 {
+		return fmt.Errorf("no supported algorithm in %s %q", contracts.ContentDigest, value)
+	}
+	return nil
+}
+
+// readBody returns the body of a request, which is replaced for it to be read again
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
@@ -0,0 +1,59 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyContentDigest(t *testing.T) {
+	body := []byte(`{"hello": "world"}`)
+	sum := sha512.Sum512(body)
+	sha512Digest := "sha-512=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+
+	// example of RFC 9530 section 2
+	assert.Equal(t, "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:", ContentDigest(body))
+
+	tests := []struct {
+		name        string
+		value       string
+		mismatch    bool
+		expectError bool
+	}{
+		{"sha-256", ContentDigest(body), false, false},
+		{"sha-512", sha512Digest, false, false},
+		{"several digests", ContentDigest(body) + ", " + sha512Digest, false, false},
+		{"unsupported digest ignored", "md5=:AAAA:, " + ContentDigest(body), false, false},
+		{"other body", ContentDigest([]byte("other")), true, true},
+		{"one digest not matching", ContentDigest(body) + ", " + "sha-512=:AAAA:", true, true},
+		{"no supported digest", "md5=:AAAA:", false, true},
+		{"not a byte sequence", "sha-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=", false, true},
+		{"malformed base64", "sha-256=:not base64:", false, true},
+		{"malformed member", "sha-256", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyContentDigest(tt.value, body)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.mismatch, errors.Is(err, ErrContentDigest))
+		})
+	}
+}
@@ -15,6 +15,8 @@
 package http
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"path/filepath"
@@ -29,18 +31,154 @@ import (
 
 type requestHandler struct {
 	Request *http.Request
+	Label   string
+	signer  interfaces.SignatureProvider
 }
 
 func NewEd25519RequestHandler(request *http.Request) interfaces.RequestHandler {
+	return NewRequestHandler(request, ed25519.New())
+}
+
+// NewEd25519RequestHandlerWithLabel returns a handler countersigning a request under the given label, as an
+// intermediary does, keeping the signatures the request already carries
+func NewEd25519RequestHandlerWithLabel(request *http.Request, label string) interfaces.RequestHandler {
+	return NewRequestHandlerWithLabel(request, label, ed25519.New())
+}
+
+// NewRequestHandler returns a handler signing a request with signer, the provider of the algorithm of the signing key
+func NewRequestHandler(request *http.Request, signer interfaces.SignatureProvider) interfaces.RequestHandler {
+	instance := requestHandler{
+		Request: request,
+		signer:  signer,
+	}
+	return &instance
+}
+
+// NewRequestHandlerWithLabel returns a handler countersigning a request under the given label with signer
+func NewRequestHandlerWithLabel(request *http.Request, label string,
+	signer interfaces.SignatureProvider) interfaces.RequestHandler {
 	instance := requestHandler{
 		Request: request,
+		Label:   label,
+		signer:  signer,
 	}
 	return &instance
 }
 
 func (h *requestHandler) AddSignatureHeaders(ticks time.Time, fields []string, keys config.SignatureInfo) error {
+	if !h.Request.URL.IsAbs() {
+		return fmt.Errorf("URL is not absolute")
+	}
+	return addSignatureHeaders(requestMessage(h.Request), h.Label, ticks, fields, keys, h.signer)
+}
+
+type responseHandler struct {
+	Header http.Header
+	Status int
+	Label  string
+	signer interfaces.SignatureProvider
+}
+
+// NewEd25519ResponseHandler returns a handler signing a response with the given status code and headers. On a
+// server, header is that of the http.ResponseWriter, and signature headers must be added before WriteHeader is called.
+func NewEd25519ResponseHandler(header http.Header, status int) interfaces.ResponseHandler {
+	return NewResponseHandler(header, status, ed25519.New())
+}
+
+// NewEd25519ResponseHandlerWithLabel returns a handler countersigning a response under the given label, as an
+// intermediary does, keeping the signatures the response already carries
+func NewEd25519ResponseHandlerWithLabel(header http.Header, status int, label string) interfaces.ResponseHandler {
+	return NewResponseHandlerWithLabel(header, status, label, ed25519.New())
+}
+
+// NewResponseHandler returns a handler signing a response with signer, as NewEd25519ResponseHandler does with ed25519
+func NewResponseHandler(header http.Header, status int, signer interfaces.SignatureProvider) interfaces.ResponseHandler {
+	instance := responseHandler{
+		Header: header,
+		Status: status,
+		signer: signer,
+	}
+	return &instance
+}
+
+// NewResponseHandlerWithLabel returns a handler countersigning a response under the given label with signer
+func NewResponseHandlerWithLabel(header http.Header, status int, label string,
+	signer interfaces.SignatureProvider) interfaces.ResponseHandler {
+	instance := responseHandler{
+		Header: header,
+		Status: status,
+		Label:  label,
+		signer: signer,
+	}
+	return &instance
+}
+
+func (h *responseHandler) AddSignatureHeaders(ticks time.Time, fields []string, keys config.SignatureInfo) error {
+	return addSignatureHeaders(message{header: h.Header, status: h.Status}, h.Label, ticks, fields, keys, h.signer)
+}
+
+// addSignatureHeaders signs a message under label with signer, in the format of RFC 9421 unless legacyFormat says
+// otherwise. The signature is labeled DefaultLabel in the format of RFC 9421 when no label is given.
+func addSignatureHeaders(m message, label string, ticks time.Time, fields []string, keys config.SignatureInfo,
+	signer interfaces.SignatureProvider) error {
+	var err error
+	if len(fields) == 0 {
+		// the signature covers the components set by the policy of keys.Http, or the default ones
+		covered, required := coverage(keys, m.status != 0)
+		fields, err = presentFields(m.header, covered, required)
+		if err != nil {
+			return err
+		}
+	}
+
+	// messages are signed with the key annotations are, as rotated by keys.PrivateKeys
+	key := keys.ActivePrivateKey(time.Now())
+	legacy := legacyFormat(m.header, keys)
+	var input string
+	if legacy {
+		input, err = signatureInput(ticks, fields, keys, key)
+		if err != nil {
+			return err
+		}
+		setMember(m.header, "Signature-Input", label, input)
+	} else {
+		if label == "" {
+			label = DefaultLabel
+		}
+		input, err = structuredInput(ticks, fields, keys, key, m)
+		if err != nil {
+			return err
+		}
+		setStructuredMember(m.header, "Signature-Input", label, input)
+	}
+
+	parsed, err := parseSignatures(m, label)
+	if err != nil {
+		return err
+	}
+
+	signature, err := signer.Sign(key, []byte(parsed[0].Seed))
+	if err != nil {
+		return err
+	}
+
+	if legacy {
+		setMember(m.header, "Signature", label, signature)
+		return nil
+	}
+	value, err := signatureValue(signature)
+	if err != nil {
+		return err
+	}
+	setStructuredMember(m.header, "Signature", label, value)
+	return nil
+}
+
+// signatureInput returns the value of the Signature-Input header covering fields, in the format of earlier releases,
+// for a signature made with key. The expires and nonce parameters are added as set by keys.Http.
+func signatureInput(ticks time.Time, fields []string, keys config.SignatureInfo, key config.KeyInfo) (string,
+	error) {
 	var headerValue strings.Builder //This will be the value returned for populating the Signature-Input header
-	inputValue := ""                //This will be the value used as input for the signature
 
 	for i, f := range fields {
 		headerValue.WriteString(fmt.Sprintf("\"%s\"", f))
@@ -49,22 +187,43 @@ func (h *requestHandler) AddSignatureHeaders(ticks time.Time, fields []string, k
 		}
 	}
 
-	tail := fmt.Sprintf(";created=%s;keyid=\"%s\";alg=\"%s\";", strconv.FormatInt(ticks.Unix(), 10),
-		filepath.Base(keys.PublicKey.Path), keys.PublicKey.Type)
+	headerValue.WriteString(";created=" + strconv.FormatInt(ticks.Unix(), 10))
+	if keys.Http != nil && keys.Http.Expires > 0 {
+		expires := ticks.Add(time.Duration(keys.Http.Expires) * time.Second)
+		headerValue.WriteString(";expires=" + strconv.FormatInt(expires.Unix(), 10))
+	}
+	if keys.Http != nil && keys.Http.Nonce {
+		nonce, err := newNonce()
+		if err != nil {
+			return "", err
+		}
+		headerValue.WriteString(fmt.Sprintf(";nonce=\"%s\"", nonce))
+	}
+	keyid, alg := KeyParams(keys, key)
+	tail := fmt.Sprintf(";keyid=\"%s\";alg=\"%s\";", keyid, alg)
 
 	headerValue.WriteString(tail)
+	return headerValue.String(), nil
+}
 
-	h.Request.Header.Set("Signature-Input", headerValue.String())
-
-	parsed, err := ParseSignature(h.Request)
-	if err != nil {
-		return err
+// KeyParams returns the keyid and alg parameters of a signature made with key: its Id, or else the name of the file of
+// keys.PublicKey as in earlier releases, and its algorithm
+func KeyParams(keys config.SignatureInfo, key config.KeyInfo) (string, string) {
+	keyid, alg := key.Id, key.Type
+	if keyid == "" {
+		keyid = filepath.Base(keys.PublicKey.Path)
 	}
-	inputValue = parsed.Seed
-
-	p := ed25519.New()
-	signature, err := p.Sign(keys.PrivateKey, []byte(inputValue))
+	if alg == "" {
+		alg = keys.PublicKey.Type
+	}
+	return keyid, string(alg)
+}
 
-	h.Request.Header.Set("Signature", signature)
-	return nil
+// newNonce returns a random nonce for the nonce parameter of a signature
+func newNonce() (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(nonce), nil
 }
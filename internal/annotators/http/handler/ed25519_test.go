@@ -15,6 +15,9 @@
 package http
 
 import (
+	stded25519 "crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -22,9 +25,11 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 	"github.com/stretchr/testify/assert"
@@ -53,6 +58,7 @@ func TestHttpPkiAnnotator_AddSignatureHeaders(t *testing.T) {
 
 	fields := []string{string(contracts.Method), string(contracts.Path), string(contracts.Authority), contracts.HttpContentType, contracts.ContentLength}
 	keys := cfg.Signature
+	keys.Http = &config.HttpSignatureInfo{Legacy: true}
 	instance := NewEd25519RequestHandler(req)
 	err = instance.AddSignatureHeaders(ticks, fields, keys)
 	if err != nil {
@@ -64,4 +70,261 @@ func TestHttpPkiAnnotator_AddSignatureHeaders(t *testing.T) {
 			strconv.FormatInt(ticks.Unix(), 10), filepath.Base(keys.PublicKey.Path), keys.PublicKey.Type)
 		assert.Equal(t, expectedSignatureInput, req.Header.Get("Signature-Input"))
 	})
+
+	t.Run("testing structured signature input construction", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "http://www.example.com/foo?var1=&var2=2", nil)
+		req.Header.Set("Content-Type", string(contracts.ContentTypeJSON))
+		req.Header.Set("Content-Length", "0")
+		err := NewEd25519RequestHandler(req).AddSignatureHeaders(ticks, fields, cfg.Signature)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		expectedSignatureInput := fmt.Sprintf("sig1=(\"@method\" \"@path\" \"@authority\" \"content-type\" \"content-length\");created=%d;keyid=\"%s\";alg=\"%s\"",
+			ticks.Unix(), filepath.Base(keys.PublicKey.Path), keys.PublicKey.Type)
+		assert.Equal(t, expectedSignatureInput, req.Header.Get("Signature-Input"))
+		assert.Regexp(t, `^sig1=:[A-Za-z0-9+/]+=*:$`, req.Header.Get("Signature"))
+	})
+}
+
+func TestHttpPkiAnnotator_AddResponseSignatureHeaders(t *testing.T) {
+	b, err := os.ReadFile("./test/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	ticks := time.Now()
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", string(contracts.ContentTypeJSON))
+
+	fields := []string{string(contracts.Status), contracts.HttpContentType}
+	keys := cfg.Signature
+	keys.Http = &config.HttpSignatureInfo{Legacy: true}
+	instance := NewEd25519ResponseHandler(w.Header(), http.StatusAccepted)
+	err = instance.AddSignatureHeaders(ticks, fields, keys)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	w.WriteHeader(http.StatusAccepted)
+
+	t.Run("testing assembler signature input construction", func(t *testing.T) {
+		expectedSignatureInput := fmt.Sprintf("\"@status\" \"Content-Type\";created=%s;keyid=\"%s\";alg=\"%s\";",
+			strconv.FormatInt(ticks.Unix(), 10), filepath.Base(keys.PublicKey.Path), keys.PublicKey.Type)
+		assert.Equal(t, expectedSignatureInput, w.Header().Get("Signature-Input"))
+	})
+
+	t.Run("testing signature verification", func(t *testing.T) {
+		resp := w.Result()
+		parsed, err := ParseResponseSignature(resp)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		ok, err := ed25519.New().Verify(keys.PublicKey, []byte(parsed.Seed), []byte(parsed.Signature))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		// the signature covers the status code
+		resp.StatusCode = http.StatusOK
+		parsed, err = ParseResponseSignature(resp)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		ok, _ = ed25519.New().Verify(keys.PublicKey, []byte(parsed.Seed), []byte(parsed.Signature))
+		assert.False(t, ok)
+	})
+}
+
+func TestHttpPkiAnnotator_Countersign(t *testing.T) {
+	b, err := os.ReadFile("./test/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	ticks := time.Now()
+
+	verify := func(t *testing.T, keys config.SignatureInfo, signatures []parseResult, labels ...string) {
+		if assert.Len(t, signatures, len(labels)) {
+			for i, parsed := range signatures {
+				assert.Equal(t, labels[i], parsed.Label)
+				ok, err := ed25519.New().Verify(keys.PublicKey, []byte(parsed.Seed), []byte(parsed.Signature))
+				assert.NoError(t, err)
+				assert.True(t, ok, parsed.Label)
+			}
+		}
+	}
+
+	tests := []struct {
+		name   string
+		legacy bool
+		label  string // label of the original signature
+	}{
+		{"structured", false, DefaultLabel},
+		{"legacy", true, ""},
+	}
+	for _, tt := range tests {
+		keys := cfg.Signature
+		keys.Http = &config.HttpSignatureInfo{Legacy: tt.legacy}
+
+		t.Run(tt.name+" request", func(t *testing.T) {
+			req := httptest.NewRequest("POST", "http://www.example.com/foo", nil)
+			req.Header.Set("Content-Type", string(contracts.ContentTypeJSON))
+
+			// the device signs the request, then the gateway countersigns it covering more fields
+			device := []string{string(contracts.Method), string(contracts.Path)}
+			if err := NewEd25519RequestHandler(req).AddSignatureHeaders(ticks, device, keys); err != nil {
+				t.Fatalf(err.Error())
+			}
+			original := req.Header.Get("Signature")
+			parsed, err := ParseSignature(req)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			// the gateway follows the format of the signature the request carries, whatever its keys say
+			gatewayKeys := cfg.Signature
+			gatewayKeys.Http = &config.HttpSignatureInfo{Legacy: !tt.legacy}
+			gateway := []string{string(contracts.Method), string(contracts.Authority), contracts.HttpContentType}
+			err = NewEd25519RequestHandlerWithLabel(req, "gateway").AddSignatureHeaders(ticks, gateway, gatewayKeys)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.True(t, strings.HasPrefix(req.Header.Get("Signature"), original+", gateway="))
+
+			signatures, err := ParseSignatures(req)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			verify(t, keys, signatures, tt.label, "gateway")
+
+			// the original signature is the one parsed by ParseSignature
+			countersigned, err := ParseSignature(req)
+			assert.NoError(t, err)
+			assert.Equal(t, parsed, countersigned)
+
+			// countersigning again under the same label replaces the countersignature
+			err = NewEd25519RequestHandlerWithLabel(req, "gateway").AddSignatureHeaders(ticks, gateway, gatewayKeys)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			signatures, err = ParseSignatures(req)
+			assert.NoError(t, err)
+			assert.Len(t, signatures, 2)
+		})
+
+		t.Run(tt.name+" response", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			fields := []string{string(contracts.Status)}
+			err := NewEd25519ResponseHandler(w.Header(), http.StatusOK).AddSignatureHeaders(ticks, fields, keys)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			proxy := NewEd25519ResponseHandlerWithLabel(w.Header(), http.StatusOK, "proxy")
+			if err := proxy.AddSignatureHeaders(ticks, fields, keys); err != nil {
+				t.Fatalf(err.Error())
+			}
+			w.WriteHeader(http.StatusOK)
+
+			signatures, err := ParseResponseSignatures(w.Result())
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			verify(t, keys, signatures, tt.label, "proxy")
+		})
+	}
+}
+
+// Messages are signed with the active key of a rotation, named by its Id, as annotations are
+func TestAddSignatureHeaders_Rotation(t *testing.T) {
+	b, err := os.ReadFile("./test/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	public, private, err := stded25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	dir := t.TempDir()
+	rotated := config.KeyInfo{Type: contracts.KeyEd25519, Path: filepath.Join(dir, "private.key"), Id: "2025-01",
+		ActiveFrom: time.Now().Add(-time.Hour)}
+	rotatedPublic := config.KeyInfo{Type: contracts.KeyEd25519, Path: filepath.Join(dir, "public.key"), Id: "2025-01"}
+	if err = os.WriteFile(rotated.Path, []byte(hex.EncodeToString(private)), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err = os.WriteFile(rotatedPublic.Path, []byte(hex.EncodeToString(public)), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	keys := cfg.Signature
+	keys.PrivateKey.Id = "2024-01"
+	keys.PublicKey.Id = "2024-01"
+	keys.PrivateKeys = []config.KeyInfo{rotated}
+	keys.PublicKeys = []config.KeyInfo{rotatedPublic}
+
+	fields := []string{string(contracts.Method), string(contracts.Path), string(contracts.Authority)}
+	for _, legacy := range []bool{false, true} {
+		t.Run(fmt.Sprintf("legacy %v", legacy), func(t *testing.T) {
+			keys := keys
+			keys.Http = &config.HttpSignatureInfo{Legacy: legacy}
+			req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+			if err := NewEd25519RequestHandler(req).AddSignatureHeaders(time.Now(), fields, keys); err != nil {
+				t.Fatalf(err.Error())
+			}
+			parsed, err := ParseSignature(req)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Equal(t, rotated.Id, parsed.Keyid)
+
+			k, err := VerificationKey(keys, parsed.Keyid, parsed.Algorithm)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Equal(t, rotatedPublic, k)
+			ok, err := ed25519.New().Verify(k, []byte(parsed.Seed), []byte(parsed.Signature))
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			// the key that was rotated out no longer signs
+			ok, _ = ed25519.New().Verify(keys.PublicKey, []byte(parsed.Seed), []byte(parsed.Signature))
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestSplitMember(t *testing.T) {
+	tests := []struct {
+		name   string
+		member string
+		label  string
+		value  string
+	}{
+		{"unlabeled input", `"@method";created=1;keyid="public.key";alg="ed25519";`, "",
+			`"@method";created=1;keyid="public.key";alg="ed25519";`},
+		{"labeled input", `gw="@method";created=1;`, "gw", `"@method";created=1;`},
+		{"unlabeled hex signature", "a1b2", "", "a1b2"},
+		{"labeled signature", "gw=a1b2", "gw", "a1b2"},
+		{"unlabeled base64 signature", "abc==", "", "abc=="},
+		{"unlabeled base64 signature with uppercase", "Ab+c=", "", "Ab+c="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, value := splitMember(tt.member)
+			assert.Equal(t, tt.label, label)
+			assert.Equal(t, tt.value, value)
+		})
+	}
 }
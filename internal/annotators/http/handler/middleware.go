@@ -0,0 +1,186 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+type middleware struct {
+	next        http.Handler
+	keys        config.SignatureInfo
+	newProvider func(contracts.KeyAlgorithm) (interfaces.SignatureProvider, error)
+	nonces      interfaces.NonceStore
+}
+
+// NewMiddleware returns a handler verifying the signatures of each request before it is served by next, the original
+// one and the countersignatures added by intermediaries. The keyid of a signature names a public key, see
+// VerificationKey, its alg the provider returned by newProvider. Requests with a signature that is not valid, or
+// whose body does not match their Content-Digest header or whose signatures do not cover the components required by
+// keys.Http.Request, are answered 401 Unauthorized, as are requests with a body whose original signature does not
+// cover their Content-Digest and replayed requests:
+// those with a signature that has expired, is older than keys.Http allows or carries a nonce recorded in nonces, by
+// default a NonceStore held in memory. Verified requests are served with their URL made absolute, and are found in the
+// context under contracts.HttpRequestKey for the pki-http annotator. Requests with a Content-Digest header whose body
+// is larger than keys.Http allows are answered 413 Request Entity Too Large.
+func NewMiddleware(next http.Handler, keys config.SignatureInfo,
+	newProvider func(contracts.KeyAlgorithm) (interfaces.SignatureProvider, error),
+	nonces interfaces.NonceStore) http.Handler {
+	if nonces == nil {
+		nonces = NewNonceStore()
+	}
+	return &middleware{next: next, keys: keys, newProvider: newProvider, nonces: nonces}
+}
+
+func (m *middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := AbsoluteRequest(r)
+	// the body is read whole to verify its digest
+	if req.Header.Get(contracts.ContentDigest) != "" && req.Body != nil {
+		req.Body = http.MaxBytesReader(w, req.Body, m.keys.Http.BodyLimit())
+	}
+	if err := m.verify(req); err != nil {
+		status := http.StatusUnauthorized
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	ctx := context.WithValue(req.Context(), contracts.HttpRequestKey, req)
+	m.next.ServeHTTP(w, req.WithContext(ctx))
+}
+
+func (m *middleware) verify(r *http.Request) error {
+	if r.Header.Get("Signature-Input") == "" || r.Header.Get("Signature") == "" {
+		return errors.New("request is not signed")
+	}
+	signatures, err := ParseSignatures(r)
+	if err != nil {
+		return err
+	}
+	if err = VerifyCoverage(r, signatures, m.keys); err != nil {
+		return err
+	}
+	// countersignatures added by intermediaries are verified along with the original signature
+	for _, parsed := range signatures {
+		if err = m.verifySignature(parsed); err != nil {
+			if parsed.Label != "" {
+				return fmt.Errorf("signature %s: %w", parsed.Label, err)
+			}
+			return err
+		}
+	}
+	// the signature binds the body through its digest, which must then be present and covered, lest a body be added
+	// to a signed request or replaced along with an unsigned digest
+	if hasBody(r) {
+		if r.Header.Get(contracts.ContentDigest) == "" {
+			return fmt.Errorf("%w: request has a body but no %s", contracts.ErrComponentNotCovered,
+				contracts.ContentDigest)
+		}
+		signed, _ := original(signatures, nil)
+		if err = checkCoverage(signed, []string{contracts.ContentDigest}, r); err != nil {
+			return err
+		}
+	}
+	if digest := r.Header.Get(contracts.ContentDigest); digest != "" {
+		body, err := readBody(r)
+		if err != nil {
+			return err
+		}
+		return VerifyContentDigest(digest, body)
+	}
+	return nil
+}
+
+func (m *middleware) verifySignature(parsed parseResult) error {
+	k, err := VerificationKey(m.keys, parsed.Keyid, parsed.Algorithm)
+	if err != nil {
+		return err
+	}
+	p, err := m.newProvider(k.Type)
+	if err != nil {
+		return err
+	}
+	ok, err := p.Verify(k, []byte(parsed.Seed), []byte(parsed.Signature))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("invalid signature")
+	}
+	// the nonce is only recorded for signatures that verify, which others cannot forge
+	return checkFreshness(parsed, m.keys.Http, m.nonces, time.Now())
+}
+
+// VerificationKey returns the public key verifying a signature with the given keyid and alg: the key of keys with
+// that Id, as the signatures made with a key listed in keys.PrivateKeys name it, or else the key file of that name in
+// the directory of keys.PublicKey. alg is optional in RFC 9421, the key then is of the configured type.
+func VerificationKey(keys config.SignatureInfo, keyid string, alg string) (config.KeyInfo, error) {
+	if keyid != "" {
+		if k, err := keys.VerificationKey(keyid); err == nil {
+			if alg != "" && contracts.KeyAlgorithm(alg) != k.Type {
+				return config.KeyInfo{}, fmt.Errorf("alg %s does not match key %s", alg, keyid)
+			}
+			return k, nil
+		}
+	}
+	// the keyid must name a file of the key directory
+	if keyid == "" || keyid != filepath.Base(keyid) {
+		return config.KeyInfo{}, fmt.Errorf("invalid keyid %q", keyid)
+	}
+	k := config.KeyInfo{
+		Type: contracts.KeyAlgorithm(alg),
+		Path: filepath.Join(filepath.Dir(keys.PublicKey.Path), keyid),
+	}
+	if alg == "" {
+		k.Type = keys.PublicKey.Type
+	}
+	if !k.Type.Validate() {
+		return config.KeyInfo{}, fmt.Errorf("invalid key type specified: %s", alg)
+	}
+	return k, nil
+}
+
+// hasBody reports whether a request carries a body, of known length or not
+func hasBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+}
+
+// AbsoluteRequest returns a shallow copy of a request received by a server with its URL made absolute, as
+// ParseSignature requires, from the Host header and whether the connection uses TLS.
+func AbsoluteRequest(r *http.Request) *http.Request {
+	req := r.WithContext(r.Context())
+	u := *r.URL
+	if !u.IsAbs() {
+		// the URL of a server request holds its path and query only
+		u.Scheme = "http"
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+		u.Host = r.Host
+	}
+	req.URL = &u
+	return req
+}
@@ -0,0 +1,238 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	b, err := os.ReadFile("./test/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	newProvider := func(contracts.KeyAlgorithm) (interfaces.SignatureProvider, error) {
+		return ed25519.New(), nil
+	}
+	var served *http.Request
+	h := NewMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served, _ = r.Context().Value(contracts.HttpRequestKey).(*http.Request)
+	}), cfg.Signature, newProvider, nil)
+
+	fields := []string{string(contracts.Method), string(contracts.Path), string(contracts.Authority)}
+	signed := func(keys config.SignatureInfo) *http.Request {
+		req := httptest.NewRequest("GET", "http://example.com/foo?var1=&var2=2", nil)
+		if err := NewEd25519RequestHandler(req).AddSignatureHeaders(time.Now(), fields, keys); err != nil {
+			t.Fatalf(err.Error())
+		}
+		// a server is given the path and query only
+		req.URL.Scheme = ""
+		req.URL.Host = ""
+		return req
+	}
+
+	tampered := signed(cfg.Signature)
+	tampered.Method = "POST"
+
+	escaped := signed(cfg.Signature)
+	escaped.Header.Set("Signature-Input",
+		`"@method";created=1;keyid="../ed25519/public.key";alg="ed25519";`)
+
+	malformed := func(input string) *http.Request {
+		req := signed(cfg.Signature)
+		req.Header.Set("Signature-Input", input)
+		return req
+	}
+
+	countersigned := func(label string) *http.Request {
+		req := httptest.NewRequest("GET", "http://example.com/foo?var1=&var2=2", nil)
+		if err := NewEd25519RequestHandler(req).AddSignatureHeaders(time.Now(), fields, cfg.Signature); err != nil {
+			t.Fatalf(err.Error())
+		}
+		gateway := NewEd25519RequestHandlerWithLabel(req, label)
+		if err := gateway.AddSignatureHeaders(time.Now(), fields[:1], cfg.Signature); err != nil {
+			t.Fatalf(err.Error())
+		}
+		req.URL.Scheme = ""
+		req.URL.Host = ""
+		return req
+	}
+	forged := countersigned("gateway")
+	forged.Header.Set("Signature", strings.Replace(forged.Header.Get("Signature"), "gateway=", "gateway=00", 1))
+
+	digested := func(signedBody, body string) *http.Request {
+		req := httptest.NewRequest("POST", "http://example.com/foo?var1=&var2=2", strings.NewReader(body))
+		req.Header.Set(contracts.ContentDigest, ContentDigest([]byte(signedBody)))
+		covered := append(fields, contracts.ContentDigest)
+		if err := NewEd25519RequestHandler(req).AddSignatureHeaders(time.Now(), covered, cfg.Signature); err != nil {
+			t.Fatalf(err.Error())
+		}
+		req.URL.Scheme = ""
+		req.URL.Host = ""
+		return req
+	}
+
+	// a body added to a signed request, and one replaced along with a digest the signature does not cover
+	undigested := func(body string) *http.Request {
+		req := signed(cfg.Signature)
+		req.Body = io.NopCloser(strings.NewReader(body))
+		req.ContentLength = int64(len(body))
+		return req
+	}
+	uncovered := func(body string) *http.Request {
+		req := undigested(body)
+		req.Header.Set(contracts.ContentDigest, ContentDigest([]byte(body)))
+		return req
+	}
+
+	// a middleware requiring Content-Type to be covered, which the signatures of fields do not
+	policy := cfg.Signature
+	policy.Http = &config.HttpSignatureInfo{Request: &config.HttpCoverageInfo{Required: []string{contracts.HttpContentType}}}
+	strict := NewMiddleware(h, policy, newProvider, nil)
+	covering := func() *http.Request {
+		req := httptest.NewRequest("GET", "http://example.com/foo?var1=&var2=2", nil)
+		req.Header.Set(contracts.HttpContentType, string(contracts.ContentTypeJSON))
+		err := NewEd25519RequestHandler(req).AddSignatureHeaders(time.Now(), append(fields, contracts.HttpContentType),
+			cfg.Signature)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		req.URL.Scheme = ""
+		req.URL.Host = ""
+		return req
+	}
+
+	// a middleware reading no more of a body than the digested ones hold
+	small := cfg.Signature
+	small.Http = &config.HttpSignatureInfo{MaxBodySize: int64(len(`{"key":"keyA"}`))}
+	limited := NewMiddleware(h, small, newProvider, nil)
+
+	tests := []struct {
+		name   string
+		h      http.Handler
+		req    *http.Request
+		status int
+	}{
+		{"valid signature", h, signed(cfg.Signature), http.StatusOK},
+		{"unsigned request", h, httptest.NewRequest("GET", "/foo", nil), http.StatusUnauthorized},
+		{"tampered request", h, tampered, http.StatusUnauthorized},
+		{"keyid outside key directory", h, escaped, http.StatusUnauthorized},
+		{"unquoted component", h, malformed("x;a"), http.StatusUnauthorized},
+		{"empty component", h, malformed(`"";created=1`), http.StatusUnauthorized},
+		{"body matching digest", h, digested(`{"key":"keyA"}`, `{"key":"keyA"}`), http.StatusOK},
+		{"body not matching digest", h, digested(`{"key":"keyA"}`, `{"key":"keyB"}`), http.StatusUnauthorized},
+		{"body but no digest", h, undigested(`{"key":"keyB"}`), http.StatusUnauthorized},
+		{"digest not covered", h, uncovered(`{"key":"keyB"}`), http.StatusUnauthorized},
+		{"body within limit", limited, digested(`{"key":"keyA"}`, `{"key":"keyA"}`), http.StatusOK},
+		{"body over limit", limited, digested(`{"key":"keyAB"}`, `{"key":"keyAB"}`), http.StatusRequestEntityTooLarge},
+		{"countersigned request", h, countersigned("gateway"), http.StatusOK},
+		{"invalid countersignature", h, forged, http.StatusUnauthorized},
+		{"required component covered", strict, covering(), http.StatusOK},
+		{"required component not covered", strict, signed(cfg.Signature), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			served = nil
+			w := httptest.NewRecorder()
+			tt.h.ServeHTTP(w, tt.req)
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status != http.StatusOK {
+				assert.Nil(t, served)
+				return
+			}
+			if assert.NotNil(t, served) {
+				assert.Equal(t, "http://example.com/foo?var1=&var2=2", served.URL.String())
+				// the body is left for the next handler
+				body, _ := io.ReadAll(served.Body)
+				if digest := served.Header.Get(contracts.ContentDigest); digest != "" {
+					assert.NoError(t, VerifyContentDigest(digest, body))
+				}
+				// the pki-http annotator parses the request found in the context
+				_, err := ParseSignature(served)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMiddleware_Replay(t *testing.T) {
+	b, err := os.ReadFile("./test/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	keys := cfg.Signature
+	keys.Http = &config.HttpSignatureInfo{Expires: 60, MaxAge: 60, Nonce: true}
+	newProvider := func(contracts.KeyAlgorithm) (interfaces.SignatureProvider, error) {
+		return ed25519.New(), nil
+	}
+	h := NewMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), keys, newProvider, nil)
+
+	signed := func(ticks time.Time, keys config.SignatureInfo) *http.Request {
+		req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+		err := NewEd25519RequestHandler(req).AddSignatureHeaders(ticks, []string{string(contracts.Method)}, keys)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		return req
+	}
+	fresh := signed(time.Now(), keys)
+	replayed := httptest.NewRequest("GET", "http://example.com/foo", nil)
+	replayed.Header = fresh.Header.Clone()
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"fresh request", fresh, http.StatusOK},
+		{"replayed request", replayed, http.StatusUnauthorized},
+		{"stale request", signed(time.Now().Add(-2*time.Minute), keys), http.StatusUnauthorized},
+		{"request without nonce", signed(time.Now(), cfg.Signature), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, tt.req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
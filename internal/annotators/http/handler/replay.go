@@ -0,0 +1,112 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+const (
+	// clockSkew is how far ahead of the verifier's clock the clock of a signer may be
+	clockSkew = time.Minute
+	// nonceTTL is how long the nonce of a signature that neither expires nor has a maximum age is remembered
+	nonceTTL = 24 * time.Hour
+)
+
+// checkFreshness rejects signatures that have expired, are older than cfg allows, were created in the future or carry
+// a nonce seen before. Nonces are remembered by nonces until the signature would be rejected as stale anyway, or for
+// nonceTTL if it would not.
+func checkFreshness(parsed parseResult, cfg *config.HttpSignatureInfo, nonces interfaces.NonceStore, now time.Time) error {
+	if cfg == nil {
+		cfg = &config.HttpSignatureInfo{}
+	}
+	if parsed.Created != 0 {
+		if created := time.Unix(parsed.Created, 0); created.After(now.Add(clockSkew)) {
+			return fmt.Errorf("%w: created in the future at %s", contracts.ErrSignatureExpired,
+				created.UTC().Format(time.RFC3339))
+		}
+	}
+	var until time.Time
+	if parsed.Expires != 0 {
+		until = time.Unix(parsed.Expires, 0)
+		if now.After(until) {
+			return fmt.Errorf("%w at %s", contracts.ErrSignatureExpired, until.UTC().Format(time.RFC3339))
+		}
+	}
+	if cfg.MaxAge > 0 {
+		if parsed.Created == 0 {
+			return fmt.Errorf("%w: no created parameter", contracts.ErrSignatureExpired)
+		}
+		stale := time.Unix(parsed.Created, 0).Add(time.Duration(cfg.MaxAge) * time.Second)
+		if now.After(stale) {
+			return fmt.Errorf("%w: created more than %ds ago", contracts.ErrSignatureExpired, cfg.MaxAge)
+		}
+		if until.IsZero() || stale.Before(until) {
+			until = stale
+		}
+	}
+	if parsed.Nonce == "" {
+		if cfg.Nonce {
+			return fmt.Errorf("%w: no nonce parameter", contracts.ErrSignatureReplayed)
+		}
+		return nil
+	}
+	if until.IsZero() {
+		until = now.Add(nonceTTL)
+	}
+	if nonces.Seen(parsed.Nonce, until) {
+		return fmt.Errorf("%w: nonce %s", contracts.ErrSignatureReplayed, parsed.Nonce)
+	}
+	return nil
+}
+
+// nonceStore is a NonceStore held in memory, which protects a single instance of a service
+type nonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+	purged time.Time
+}
+
+// NewNonceStore returns a NonceStore held in memory. Nonces recorded until a given time are forgotten once it passes.
+func NewNonceStore() interfaces.NonceStore {
+	return &nonceStore{nonces: make(map[string]time.Time)}
+}
+
+func (s *nonceStore) Seen(nonce string, until time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	// forget the nonces of signatures that can no longer be accepted, at most once a minute
+	if now.Sub(s.purged) > time.Minute {
+		for n, u := range s.nonces {
+			if !u.IsZero() && now.After(u) {
+				delete(s.nonces, n)
+			}
+		}
+		s.purged = now
+	}
+	if u, ok := s.nonces[nonce]; ok && (u.IsZero() || !now.After(u)) {
+		return true
+	}
+	s.nonces[nonce] = until
+	return false
+}
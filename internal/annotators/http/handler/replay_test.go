@@ -0,0 +1,129 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFreshness(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) int64 {
+		return now.Add(-d).Unix()
+	}
+
+	tests := []struct {
+		name   string
+		parsed parseResult
+		cfg    *config.HttpSignatureInfo
+		expect error
+	}{
+		{"no replay protection", parseResult{}, nil, nil},
+		{"not expired", parseResult{Created: ago(time.Minute), Expires: ago(-time.Minute)}, nil, nil},
+		{"expired", parseResult{Created: ago(time.Minute), Expires: ago(time.Second)}, nil,
+			contracts.ErrSignatureExpired},
+		{"within max age", parseResult{Created: ago(time.Minute)}, &config.HttpSignatureInfo{MaxAge: 120}, nil},
+		{"older than max age", parseResult{Created: ago(time.Minute)}, &config.HttpSignatureInfo{MaxAge: 30},
+			contracts.ErrSignatureExpired},
+		{"max age without created", parseResult{}, &config.HttpSignatureInfo{MaxAge: 30},
+			contracts.ErrSignatureExpired},
+		{"nonce", parseResult{Nonce: "abc"}, &config.HttpSignatureInfo{Nonce: true}, nil},
+		{"nonce required", parseResult{}, &config.HttpSignatureInfo{Nonce: true}, contracts.ErrSignatureReplayed},
+		{"nonce seen", parseResult{Nonce: "seen"}, nil, contracts.ErrSignatureReplayed},
+		{"created within clock skew", parseResult{Created: ago(-clockSkew / 2)}, nil, nil},
+		{"created in the future", parseResult{Created: ago(-time.Hour)}, nil, contracts.ErrSignatureExpired},
+		{"created in the future within max age", parseResult{Created: ago(-time.Hour)},
+			&config.HttpSignatureInfo{MaxAge: 30}, contracts.ErrSignatureExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonces := NewNonceStore()
+			nonces.Seen("seen", time.Time{})
+			err := checkFreshness(tt.parsed, tt.cfg, nonces, now)
+			test.CheckError(err, tt.expect != nil, tt.name, t)
+			if tt.expect != nil {
+				assert.True(t, errors.Is(err, tt.expect), err)
+			}
+		})
+	}
+}
+
+func TestCheckFreshness_NonceTTL(t *testing.T) {
+	now := time.Now()
+	nonces := &recordingNonces{}
+	if err := checkFreshness(parseResult{Nonce: "abc"}, nil, nonces, now); err != nil {
+		t.Fatalf(err.Error())
+	}
+	// a signature that neither expires nor has a maximum age does not hold its nonce forever
+	assert.Equal(t, now.Add(nonceTTL), nonces.until)
+}
+
+// recordingNonces is a NonceStore recording until when the last nonce was to be remembered
+type recordingNonces struct {
+	until time.Time
+}
+
+func (r *recordingNonces) Seen(nonce string, until time.Time) bool {
+	r.until = until
+	return false
+}
+
+func TestNonceStore(t *testing.T) {
+	s := NewNonceStore()
+	assert.False(t, s.Seen("a", time.Now().Add(time.Minute)))
+	assert.True(t, s.Seen("a", time.Now().Add(time.Minute)))
+	// nonces of signatures that can no longer be accepted are forgotten
+	assert.False(t, s.Seen("b", time.Now().Add(-time.Second)))
+	assert.False(t, s.Seen("b", time.Now().Add(time.Minute)))
+	assert.False(t, s.Seen("c", time.Time{}))
+	assert.True(t, s.Seen("c", time.Time{}))
+}
+
+func TestSignatureInput_Replay(t *testing.T) {
+	keys := config.SignatureInfo{
+		PublicKey:  config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../../../test/keys/ed25519/public.key"},
+		PrivateKey: config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../../../test/keys/ed25519/private.key"},
+		Http:       &config.HttpSignatureInfo{Expires: 60, Nonce: true},
+	}
+	ticks := time.Now()
+
+	var nonces []string
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+		err := NewEd25519RequestHandler(req).AddSignatureHeaders(ticks, []string{string(contracts.Method)}, keys)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		parsed, err := ParseSignature(req)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		assert.Equal(t, ticks.Unix(), parsed.Created)
+		assert.Equal(t, ticks.Unix()+60, parsed.Expires)
+		assert.Equal(t, "public.key", parsed.Keyid)
+		assert.Equal(t, string(contracts.KeyEd25519), parsed.Algorithm)
+		assert.NotEmpty(t, parsed.Nonce)
+		nonces = append(nonces, parsed.Nonce)
+	}
+	assert.NotEqual(t, nonces[0], nonces[1])
+}
@@ -0,0 +1,396 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// DefaultLabel labels the signature added by a handler given no label, in the format of RFC 9421 where each
+// signature has one
+const DefaultLabel = "sig1"
+
+// signatureParams is the component identifier of the signature parameters, which ends each signature base
+const signatureParams = "@signature-params"
+
+// isStructured reports whether the Signature-Input header of a message holds signatures in the format of RFC 9421, a
+// dictionary of inner lists, rather than in that of earlier releases
+func isStructured(header http.Header) bool {
+	d, err := parseDictionary(strings.Join(header.Values("Signature-Input"), ", "))
+	if err != nil || len(d) == 0 {
+		return false
+	}
+	for _, m := range d {
+		if _, ok := m.value.(sfInnerList); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// legacyFormat reports whether a signature is added to a message in the format of earlier releases: when the
+// signatures it carries are, or else when keys.Http asks for it
+func legacyFormat(header http.Header, keys config.SignatureInfo) bool {
+	if len(header.Values("Signature-Input")) > 0 {
+		return !isStructured(header)
+	}
+	return keys.Http != nil && keys.Http.Legacy
+}
+
+// parseStructuredSignatures parses the signatures of a message in the format of RFC 9421, the one with the given
+// label or each of them if no label is given
+func parseStructuredSignatures(m message, label ...string) ([]parseResult, error) {
+	inputs, err := parseDictionary(strings.Join(m.header.Values("Signature-Input"), ", "))
+	if err != nil {
+		return nil, err
+	}
+	var signatures sfDictionary
+	if values := m.header.Values("Signature"); len(values) > 0 {
+		if signatures, err = parseDictionary(strings.Join(values, ", ")); err != nil {
+			return nil, err
+		}
+	}
+
+	var parsed []parseResult
+	for _, member := range inputs {
+		if len(label) > 0 && member.key != label[0] {
+			continue
+		}
+		input := member.value.(sfInnerList)
+		base, err := signatureBase(m, input)
+		if err != nil {
+			return nil, err
+		}
+		p := parseResult{Label: member.key, Seed: base}
+		for _, id := range input.items {
+			p.Components = append(p.Components, serializeItem(id))
+		}
+		for _, param := range input.params {
+			var ok bool
+			switch param.key {
+			case "created":
+				p.Created, ok = param.value.(int64)
+			case "expires":
+				p.Expires, ok = param.value.(int64)
+			case "nonce":
+				p.Nonce, ok = param.value.(string)
+			case "keyid":
+				p.Keyid, ok = param.value.(string)
+			case "alg":
+				p.Algorithm, ok = param.value.(string)
+			default:
+				ok = true
+			}
+			if !ok {
+				return nil, fmt.Errorf("invalid %s parameter of signature %s", param.key, member.key)
+			}
+		}
+		// signatures are byte sequences, hex encoded as signature providers expect them
+		for _, s := range signatures {
+			if s.key != member.key {
+				continue
+			}
+			item, ok := s.value.(sfItem)
+			raw, isBytes := item.value.([]byte)
+			if !ok || !isBytes {
+				return nil, fmt.Errorf("signature %s is not a byte sequence", member.key)
+			}
+			p.Signature = hex.EncodeToString(raw)
+		}
+		parsed = append(parsed, p)
+	}
+	if len(parsed) == 0 {
+		return nil, fmt.Errorf("malformed Signature-Input %q", m.header.Get("Signature-Input"))
+	}
+	return parsed, nil
+}
+
+// signatureBase returns the signature base of RFC 9421 for the components covered by input, followed by the
+// signature parameters
+func signatureBase(m message, input sfInnerList) (string, error) {
+	var b strings.Builder
+	seen := make(map[string]bool)
+	for _, id := range input.items {
+		name, ok := id.value.(string)
+		if !ok || name == signatureParams {
+			return "", fmt.Errorf("invalid component identifier %s", serializeItem(id))
+		}
+		identifier := serializeItem(id)
+		if seen[identifier] {
+			return "", fmt.Errorf("component %s is covered twice", identifier)
+		}
+		seen[identifier] = true
+
+		values, err := componentValues(m, name, id.params)
+		if err != nil {
+			return "", err
+		}
+		for _, v := range values {
+			b.WriteString(identifier + ": " + v + "\n")
+		}
+	}
+	b.WriteString(`"` + signatureParams + `": ` + serializeInnerList(input))
+	return b.String(), nil
+}
+
+// componentValues returns the values of the component with the given name and parameters. Derived components
+// other than @query-param have a single value.
+func componentValues(m message, name string, params sfParams) ([]string, error) {
+	if _, ok := params.get("tr"); ok {
+		return nil, fmt.Errorf("trailer fields are not supported, found %s", name)
+	}
+	if _, ok := params.get("req"); ok {
+		// the component is that of the request a response answers
+		if m.status == 0 || m.request == nil {
+			return nil, fmt.Errorf("component %s of the request is not available", name)
+		}
+		m = requestMessage(m.request)
+	}
+	if strings.HasPrefix(name, "@") {
+		return derivedValues(m, contracts.DerivedComponent(name), params)
+	}
+	if name != strings.ToLower(name) {
+		return nil, fmt.Errorf("field name %s is not lowercase", name)
+	}
+	v, err := fieldValue(m.header, name, params)
+	if err != nil {
+		return nil, err
+	}
+	return []string{v}, nil
+}
+
+func derivedValues(m message, component contracts.DerivedComponent, params sfParams) ([]string, error) {
+	if component == contracts.Status {
+		if m.status == 0 {
+			return nil, fmt.Errorf("component %s only applies to responses", component)
+		}
+		return []string{strconv.Itoa(m.status)}, nil
+	}
+	if m.status != 0 || m.request == nil {
+		return nil, fmt.Errorf("component %s only applies to requests", component)
+	}
+
+	r := m.request
+	switch component {
+	case contracts.Method:
+		return []string{r.Method}, nil
+	case contracts.TargetURI:
+		return []string{r.URL.String()}, nil
+	case contracts.Authority:
+		return []string{authority(r)}, nil
+	case contracts.Scheme:
+		return []string{strings.ToLower(r.URL.Scheme)}, nil
+	case contracts.RequestTarget:
+		return []string{r.URL.RequestURI()}, nil
+	case contracts.Path:
+		path := r.URL.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		return []string{path}, nil
+	case contracts.Query:
+		return []string{"?" + r.URL.RawQuery}, nil
+	case contracts.QueryParam:
+		name, ok := params.get("name")
+		if s, isString := name.(string); ok && isString {
+			return queryParamValues(r.URL.RawQuery, s)
+		}
+		return nil, fmt.Errorf("component %s requires a name parameter", component)
+	}
+	return nil, fmt.Errorf("Unhandled Specialty Component %s", component)
+}
+
+// authority returns the host of a request in lowercase, without the default port of its scheme
+func authority(r *http.Request) string {
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+	host = strings.ToLower(host)
+	switch strings.ToLower(r.URL.Scheme) {
+	case "http":
+		host = strings.TrimSuffix(host, ":80")
+	case "https":
+		host = strings.TrimSuffix(host, ":443")
+	}
+	return host
+}
+
+// queryParamValues returns the values of the query parameter whose encoded name is given, in order, decoded then
+// percent-encoded again for their form not to depend on the encoding chosen by the sender
+func queryParamValues(rawQuery string, name string) ([]string, error) {
+	var values []string
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(pair, "=")
+		if encodeQueryComponent(k) != name {
+			continue
+		}
+		values = append(values, encodeQueryComponent(v))
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("query parameter not found %s", name)
+	}
+	return values, nil
+}
+
+// encodeQueryComponent decodes a query parameter name or value as application/x-www-form-urlencoded, then
+// percent-encodes it, spaces included
+func encodeQueryComponent(s string) string {
+	if decoded, err := url.QueryUnescape(s); err == nil {
+		s = decoded
+	}
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// obsFold matches the obsolete line folding of field values
+var obsFold = regexp.MustCompile(`\r?\n[ \t]+`)
+
+// fieldValue returns the canonical value of a header field: the values of its lines without leading and trailing
+// whitespace, joined by ", ". With the sf parameter, the value is reserialized as a structured field. With the key
+// parameter, it is the serialized member of a dictionary field, and with the bs parameter, each line is wrapped in a
+// byte sequence.
+func fieldValue(header http.Header, name string, params sfParams) (string, error) {
+	lines := append([]string(nil), header.Values(name)...)
+	if len(lines) == 0 {
+		return "", fmt.Errorf("Header field not found %s", name)
+	}
+	for i, l := range lines {
+		lines[i] = strings.Trim(obsFold.ReplaceAllString(l, " "), " \t")
+	}
+	_, sf := params.get("sf")
+	key, hasKey := params.get("key")
+	_, bs := params.get("bs")
+
+	switch {
+	case bs:
+		if sf || hasKey {
+			return "", fmt.Errorf("component %s cannot combine bs with sf or key", name)
+		}
+		encoded := make([]string, len(lines))
+		for i, l := range lines {
+			encoded[i] = serializeBareItem([]byte(l))
+		}
+		return strings.Join(encoded, ", "), nil
+	case hasKey:
+		k, ok := key.(string)
+		if !ok {
+			return "", fmt.Errorf("component %s has an invalid key parameter", name)
+		}
+		d, err := parseDictionary(strings.Join(lines, ", "))
+		if err != nil {
+			return "", err
+		}
+		for _, m := range d {
+			if m.key == k {
+				return serializeMember(m.value), nil
+			}
+		}
+		return "", fmt.Errorf("dictionary member not found %s in %s", k, name)
+	case sf:
+		value := strings.Join(lines, ", ")
+		if d, err := parseDictionary(value); err == nil {
+			return serializeDictionary(d), nil
+		}
+		l, err := parseList(value)
+		if err != nil {
+			return "", err
+		}
+		return serializeList(l), nil
+	}
+	return strings.Join(lines, ", "), nil
+}
+
+// structuredInput returns the member of the Signature-Input header, in the format of RFC 9421, of a signature made
+// with key covering fields. Fields are component names, or serialized component identifiers when they have
+// parameters. @query-params is expanded to a @query-param component for each query parameter of the request.
+func structuredInput(ticks time.Time, fields []string, keys config.SignatureInfo, key config.KeyInfo,
+	m message) (string, error) {
+	var input sfInnerList
+	for _, f := range fields {
+		switch {
+		case strings.HasPrefix(f, `"`):
+			id, err := parseItem(f)
+			if err != nil {
+				return "", err
+			}
+			input.items = append(input.items, id)
+		case f == string(contracts.QueryParams) && m.request != nil:
+			seen := make(map[string]bool)
+			for _, pair := range strings.Split(m.request.URL.RawQuery, "&") {
+				k, _, _ := strings.Cut(pair, "=")
+				if name := encodeQueryComponent(k); pair != "" && !seen[name] {
+					seen[name] = true
+					input.items = append(input.items, sfItem{value: string(contracts.QueryParam),
+						params: sfParams{{key: "name", value: name}}})
+				}
+			}
+		default:
+			input.items = append(input.items, sfItem{value: strings.ToLower(f)})
+		}
+	}
+
+	input.params = append(input.params, sfParam{key: "created", value: ticks.Unix()})
+	if keys.Http != nil && keys.Http.Expires > 0 {
+		expires := ticks.Add(time.Duration(keys.Http.Expires) * time.Second)
+		input.params = append(input.params, sfParam{key: "expires", value: expires.Unix()})
+	}
+	if keys.Http != nil && keys.Http.Nonce {
+		nonce, err := newNonce()
+		if err != nil {
+			return "", err
+		}
+		input.params = append(input.params, sfParam{key: "nonce", value: nonce})
+	}
+	keyid, alg := KeyParams(keys, key)
+	input.params = append(input.params, sfParam{key: "keyid", value: keyid}, sfParam{key: "alg", value: alg})
+	return serializeInnerList(input), nil
+}
+
+// setStructuredMember sets the member of the Signature-Input or Signature header with the given label, in the format
+// of RFC 9421, keeping the others
+func setStructuredMember(header http.Header, name string, label string, value string) {
+	var list []string
+	if d, err := parseDictionary(strings.Join(header.Values(name), ", ")); err == nil {
+		for _, m := range d {
+			if m.key != label {
+				list = append(list, serializeDictionary(sfDictionary{m}))
+			}
+		}
+	}
+	list = append(list, label+"="+value)
+	header.Set(name, strings.Join(list, ", "))
+}
+
+// signatureValue returns the member of the Signature header, in the format of RFC 9421, of a hex encoded signature
+func signatureValue(signature string) (string, error) {
+	raw, err := hex.DecodeString(signature)
+	if err != nil {
+		return "", err
+	}
+	return serializeBareItem(raw), nil
+}
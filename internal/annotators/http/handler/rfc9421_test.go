@@ -0,0 +1,295 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// The expected values below are the examples of RFC 9421, section 2 and appendix B.
+
+func TestFieldValue(t *testing.T) {
+	header := http.Header{
+		"X-Ows-Header":      {"   Leading and trailing whitespace.   "},
+		"X-Obs-Fold-Header": {"Obsolete\n    line folding."},
+		"Cache-Control":     {"max-age=60", "   must-revalidate"},
+		"Example-Dict":      {" a=1,    b=2;x=1;y=2,   c=(a   b   c)"},
+		"X-Empty-Header":    {""},
+		"Example-Header":    {"value, with, lots", "of, commas"},
+	}
+
+	tests := []struct {
+		name        string
+		identifier  string
+		expected    string
+		expectError bool
+	}{
+		{"whitespace", `"x-ows-header"`, "Leading and trailing whitespace.", false},
+		{"obsolete line folding", `"x-obs-fold-header"`, "Obsolete line folding.", false},
+		{"repeated field", `"cache-control"`, "max-age=60, must-revalidate", false},
+		{"dictionary", `"example-dict"`, "a=1,    b=2;x=1;y=2,   c=(a   b   c)", false},
+		{"empty field", `"x-empty-header"`, "", false},
+		{"strict serialization", `"example-dict";sf`, "a=1, b=2;x=1;y=2, c=(a b c)", false},
+		{"dictionary member", `"example-dict";key="a"`, "1", false},
+		{"dictionary member with parameters", `"example-dict";key="b"`, "2;x=1;y=2", false},
+		{"dictionary inner list member", `"example-dict";key="c"`, "(a b c)", false},
+		{"byte sequences", `"example-header";bs`, ":dmFsdWUsIHdpdGgsIGxvdHM=:, :b2YsIGNvbW1hcw==:", false},
+		{"missing field", `"x-missing"`, "", true},
+		{"missing dictionary member", `"example-dict";key="d"`, "", true},
+		{"bs combined with sf", `"example-dict";bs;sf`, "", true},
+		{"not a dictionary", `"cache-control";key="a"`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := parseItem(tt.identifier)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			v, err := fieldValue(header, id.value.(string), id.params)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestDerivedComponents(t *testing.T) {
+	req := httptest.NewRequest("POST", "https://www.example.com/path?param=value", nil)
+	w := httptest.NewRecorder()
+	w.WriteHeader(http.StatusOK)
+	resp := w.Result()
+	resp.Request = req
+
+	tests := []struct {
+		name        string
+		msg         message
+		identifier  string
+		expected    []string
+		expectError bool
+	}{
+		{"method", requestMessage(req), `"@method"`, []string{"POST"}, false},
+		{"target uri", requestMessage(req), `"@target-uri"`, []string{"https://www.example.com/path?param=value"}, false},
+		{"authority", requestMessage(req), `"@authority"`, []string{"www.example.com"}, false},
+		{"scheme", requestMessage(req), `"@scheme"`, []string{"https"}, false},
+		{"request target", requestMessage(req), `"@request-target"`, []string{"/path?param=value"}, false},
+		{"path", requestMessage(req), `"@path"`, []string{"/path"}, false},
+		{"query", requestMessage(req), `"@query"`, []string{"?param=value"}, false},
+		{"status", responseMessage(resp), `"@status"`, []string{"200"}, false},
+		{"request component of a response", responseMessage(resp), `"@method";req`, []string{"POST"}, false},
+		{"status of a request", requestMessage(req), `"@status"`, nil, true},
+		{"method of a response", responseMessage(resp), `"@method"`, nil, true},
+		{"query param without name", requestMessage(req), `"@query-param"`, nil, true},
+		{"unknown component", requestMessage(req), `"@unknown"`, nil, true},
+		{"trailer", requestMessage(req), `"content-type";tr`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := parseItem(tt.identifier)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			values, err := componentValues(tt.msg, id.value.(string), id.params)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.expected, values)
+		})
+	}
+
+	t.Run("authority without default port", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://WWW.Example.com:80/", nil)
+		assert.Equal(t, "www.example.com", authority(req))
+	})
+	t.Run("empty query", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://www.example.com/path", nil)
+		values, err := componentValues(requestMessage(req), string(contracts.Query), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"?"}, values)
+	})
+}
+
+func TestQueryParamValues(t *testing.T) {
+	query := "var=this%20is%20a%20big%0Avalue&bar=with+plus+whitespace&fa%C3%A7ade%22%3A%20=something&bar=again"
+
+	tests := []struct {
+		name        string
+		param       string
+		expected    []string
+		expectError bool
+	}{
+		{"encoded value", "var", []string{"this%20is%20a%20big%0Avalue"}, false},
+		{"plus as space, repeated", "bar", []string{"with%20plus%20whitespace", "again"}, false},
+		{"encoded name", "fa%C3%A7ade%22%3A%20", []string{"something"}, false},
+		{"missing", "baz", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := queryParamValues(query, tt.param)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.expected, values)
+		})
+	}
+}
+
+// newTestRequest returns the request of RFC 9421, appendix B.2
+func newTestRequest() *http.Request {
+	r := httptest.NewRequest("POST", "http://example.com/foo?param=Value&Pet=dog", strings.NewReader(`{"hello": "world"}`))
+	r.Header.Set("Date", "Tue, 20 Apr 2021 02:07:55 GMT")
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Content-Digest", "sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:")
+	r.Header.Set("Content-Length", "18")
+	return r
+}
+
+func TestSignatureBase(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"minimal", `sig-b21=();created=1618884473;keyid="test-key-rsa-pss";nonce="b3k2pp5k7z-50gnwp.yemd"`,
+			`"@signature-params": ();created=1618884473;keyid="test-key-rsa-pss";nonce="b3k2pp5k7z-50gnwp.yemd"`},
+		{"selective", `sig-b22=("@authority" "content-digest" "@query-param";name="Pet");created=1618884473;keyid="test-key-rsa-pss";tag="header-example"`,
+			`"@authority": example.com
+"content-digest": sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:
+"@query-param";name="Pet": dog
+"@signature-params": ("@authority" "content-digest" "@query-param";name="Pet");created=1618884473;keyid="test-key-rsa-pss";tag="header-example"`},
+		{"full coverage", `sig-b23=("date" "@method" "@path" "@query" "@authority" "content-type" "content-digest" "content-length");created=1618884473;keyid="test-key-rsa-pss"`,
+			`"date": Tue, 20 Apr 2021 02:07:55 GMT
+"@method": POST
+"@path": /foo
+"@query": ?param=Value&Pet=dog
+"@authority": example.com
+"content-type": application/json
+"content-digest": sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:
+"content-length": 18
+"@signature-params": ("date" "@method" "@path" "@query" "@authority" "content-type" "content-digest" "content-length");created=1618884473;keyid="test-key-rsa-pss"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRequest()
+			r.Header.Set("Signature-Input", tt.input)
+			signatures, err := ParseSignatures(r)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Equal(t, tt.expected, signatures[0].Seed)
+		})
+	}
+
+	t.Run("component covered twice", func(t *testing.T) {
+		r := newTestRequest()
+		r.Header.Set("Signature-Input", `sig1=("@method" "@method");created=1618884473`)
+		_, err := ParseSignatures(r)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseStructuredSignatures_Ed25519(t *testing.T) {
+	// signature of appendix B.2.6, made with the test-key-ed25519 key of appendix B.1.4
+	r := newTestRequest()
+	r.Header.Set("Signature-Input", `sig-b26=("date" "@method" "@path" "@authority" "content-type" "content-length");created=1618884473;keyid="test-key-ed25519"`)
+	r.Header.Set("Signature", "sig-b26=:wqcAqbmYJ2ji2glfAMaRy4gruYYnx2nEFN2HN6jrnDnQCK1u02Gb04v9EDgwUPiu4A0w6vuQv5lIp5WPpBKRCw==:")
+
+	signatures, err := ParseSignatures(r)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if !assert.Len(t, signatures, 1) {
+		return
+	}
+	parsed := signatures[0]
+	assert.Equal(t, "sig-b26", parsed.Label)
+	assert.Equal(t, "test-key-ed25519", parsed.Keyid)
+	assert.Equal(t, "", parsed.Algorithm)
+	assert.Equal(t, int64(1618884473), parsed.Created)
+
+	block, _ := pem.Decode([]byte("-----BEGIN PUBLIC KEY-----\nMCowBQYDK2VwAyEAJrQLj5P/89iXES9+vFgrIy29clF9CC/oPPsw3c5D0bs=\n-----END PUBLIC KEY-----\n"))
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	signature, err := hex.DecodeString(parsed.Signature)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.True(t, ed25519.Verify(key.(ed25519.PublicKey), []byte(parsed.Seed), signature))
+
+	t.Run("signature not a byte sequence", func(t *testing.T) {
+		r.Header.Set("Signature", "sig-b26="+base64.StdEncoding.EncodeToString(signature))
+		_, err := ParseSignatures(r)
+		assert.Error(t, err)
+	})
+}
+
+func TestStructuredInput(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://example.com/foo?b=1&a=2&b=3&c%20d=", nil)
+	ticks := time.Unix(1618884473, 0)
+	keys := config.SignatureInfo{
+		PublicKey: config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../../../test/keys/ed25519/public.key"},
+	}
+
+	fields := []string{string(contracts.Method), "Content-Type", `"example-dict";key="a"`, string(contracts.QueryParams)}
+	input, err := structuredInput(ticks, fields, keys, keys.PrivateKey, requestMessage(r))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	expected := `("@method" "content-type" "example-dict";key="a" "@query-param";name="b" "@query-param";name="a" ` +
+		`"@query-param";name="c%20d");created=1618884473;keyid="public.key";alg="ed25519"`
+	assert.Equal(t, expected, input)
+
+	_, err = structuredInput(ticks, []string{`"unterminated`}, keys, keys.PrivateKey,
+		requestMessage(r))
+	assert.Error(t, err)
+}
+
+// Signature-Input headers that are neither dictionaries of RFC 9421 nor inputs in the format of earlier releases are
+// rejected rather than parsed in part
+func TestParseSignatures_Malformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"unquoted component", "x;a"},
+		{"empty component", `"";created=1`},
+		{"blank component", `" ";created=1`},
+		{"unterminated component", `"@method;created=1`},
+		{"unterminated parameter", `"@method";keyid="public.key`},
+		{"parameter without value", `"@method";keyid`},
+		{"unterminated inner list", `sig1=("@method" "@path";created=1`},
+		{"empty structured component", `sig1=("");created=1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRequest()
+			r.Header.Set("Signature-Input", tt.input)
+			r.Header.Set("Signature", "whatever")
+			_, err := ParseSignatures(r)
+			assert.Error(t, err)
+		})
+	}
+}
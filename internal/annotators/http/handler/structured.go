@@ -0,0 +1,517 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// This file implements the parsing and serialization of structured field values (RFC 8941), of which the
+// Signature-Input and Signature headers and the component identifiers they hold are made.
+
+// sfToken is a token bare item, distinguished from a string
+type sfToken string
+
+// sfParam is a parameter of an item or inner list, whose value is a bare item
+type sfParam struct {
+	key   string
+	value any
+}
+
+type sfParams []sfParam
+
+// get returns the value of the parameter with the given key
+func (p sfParams) get(key string) (any, bool) {
+	for _, param := range p {
+		if param.key == key {
+			return param.value, true
+		}
+	}
+	return nil, false
+}
+
+// sfItem is a bare item, one of int64, float64, string, sfToken, []byte and bool, with its parameters
+type sfItem struct {
+	value  any
+	params sfParams
+}
+
+type sfInnerList struct {
+	items  []sfItem
+	params sfParams
+}
+
+// sfMember is a member of a dictionary, whose value is an sfItem or an sfInnerList
+type sfMember struct {
+	key   string
+	value any
+}
+
+type sfDictionary []sfMember
+
+// sfList holds sfItem and sfInnerList members
+type sfList []any
+
+type sfParser struct {
+	s   string
+	pos int
+}
+
+func (p *sfParser) errorf(format string, a ...any) error {
+	return fmt.Errorf("invalid structured field %q at %d: %s", p.s, p.pos, fmt.Sprintf(format, a...))
+}
+
+func (p *sfParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *sfParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *sfParser) skipSP() {
+	for !p.eof() && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *sfParser) skipOWS() {
+	for !p.eof() && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// parse parses the whole field value with fn, allowing surrounding spaces only
+func (p *sfParser) parse(fn func() (any, error)) (any, error) {
+	p.skipSP()
+	v, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSP()
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", p.peek())
+	}
+	return v, nil
+}
+
+func parseDictionary(s string) (sfDictionary, error) {
+	p := &sfParser{s: s}
+	v, err := p.parse(func() (any, error) { return p.dictionary() })
+	if err != nil {
+		return nil, err
+	}
+	return v.(sfDictionary), nil
+}
+
+func parseList(s string) (sfList, error) {
+	p := &sfParser{s: s}
+	v, err := p.parse(func() (any, error) { return p.list() })
+	if err != nil {
+		return nil, err
+	}
+	return v.(sfList), nil
+}
+
+func parseItem(s string) (sfItem, error) {
+	p := &sfParser{s: s}
+	v, err := p.parse(func() (any, error) { return p.item() })
+	if err != nil {
+		return sfItem{}, err
+	}
+	return v.(sfItem), nil
+}
+
+func parseInnerList(s string) (sfInnerList, error) {
+	p := &sfParser{s: s}
+	v, err := p.parse(func() (any, error) { return p.innerList() })
+	if err != nil {
+		return sfInnerList{}, err
+	}
+	return v.(sfInnerList), nil
+}
+
+func (p *sfParser) dictionary() (sfDictionary, error) {
+	var d sfDictionary
+	for !p.eof() {
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		if p.peek() == '=' {
+			p.pos++
+			if value, err = p.itemOrInnerList(); err != nil {
+				return nil, err
+			}
+		} else {
+			params, err := p.params()
+			if err != nil {
+				return nil, err
+			}
+			value = sfItem{value: true, params: params}
+		}
+		// a key given again overrides its value, keeping its position
+		replaced := false
+		for i := range d {
+			if d[i].key == key {
+				d[i].value = value
+				replaced = true
+			}
+		}
+		if !replaced {
+			d = append(d, sfMember{key: key, value: value})
+		}
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func (p *sfParser) list() (sfList, error) {
+	var l sfList
+	for !p.eof() {
+		v, err := p.itemOrInnerList()
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, v)
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// next moves past the comma separating the members of a list or dictionary
+func (p *sfParser) next() error {
+	p.skipOWS()
+	if p.eof() {
+		return nil
+	}
+	if p.peek() != ',' {
+		return p.errorf("expected ','")
+	}
+	p.pos++
+	p.skipOWS()
+	if p.eof() {
+		return p.errorf("trailing ','")
+	}
+	return nil
+}
+
+func (p *sfParser) itemOrInnerList() (any, error) {
+	if p.peek() == '(' {
+		return p.innerList()
+	}
+	return p.item()
+}
+
+func (p *sfParser) innerList() (sfInnerList, error) {
+	if p.peek() != '(' {
+		return sfInnerList{}, p.errorf("expected '('")
+	}
+	p.pos++
+	var l sfInnerList
+	for !p.eof() {
+		p.skipSP()
+		if p.peek() == ')' {
+			p.pos++
+			params, err := p.params()
+			if err != nil {
+				return sfInnerList{}, err
+			}
+			l.params = params
+			return l, nil
+		}
+		item, err := p.item()
+		if err != nil {
+			return sfInnerList{}, err
+		}
+		l.items = append(l.items, item)
+		if c := p.peek(); c != ' ' && c != ')' {
+			return sfInnerList{}, p.errorf("expected ' ' or ')'")
+		}
+	}
+	return sfInnerList{}, p.errorf("unterminated inner list")
+}
+
+func (p *sfParser) item() (sfItem, error) {
+	value, err := p.bareItem()
+	if err != nil {
+		return sfItem{}, err
+	}
+	params, err := p.params()
+	if err != nil {
+		return sfItem{}, err
+	}
+	return sfItem{value: value, params: params}, nil
+}
+
+func (p *sfParser) params() (sfParams, error) {
+	var params sfParams
+	for p.peek() == ';' {
+		p.pos++
+		p.skipSP()
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		var value any = true
+		if p.peek() == '=' {
+			p.pos++
+			if value, err = p.bareItem(); err != nil {
+				return nil, err
+			}
+		}
+		replaced := false
+		for i := range params {
+			if params[i].key == key {
+				params[i].value = value
+				replaced = true
+			}
+		}
+		if !replaced {
+			params = append(params, sfParam{key: key, value: value})
+		}
+	}
+	return params, nil
+}
+
+func (p *sfParser) key() (string, error) {
+	start := p.pos
+	if c := p.peek(); !isLcAlpha(c) && c != '*' {
+		return "", p.errorf("expected key")
+	}
+	for !p.eof() {
+		c := p.peek()
+		if !isLcAlpha(c) && !isDigit(c) && c != '_' && c != '-' && c != '.' && c != '*' {
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos], nil
+}
+
+func (p *sfParser) bareItem() (any, error) {
+	switch c := p.peek(); {
+	case c == '-' || isDigit(c):
+		return p.number()
+	case c == '"':
+		return p.string()
+	case c == ':':
+		return p.byteSequence()
+	case c == '?':
+		return p.boolean()
+	case isAlpha(c) || c == '*':
+		return p.token(), nil
+	}
+	return nil, p.errorf("expected bare item")
+}
+
+func (p *sfParser) number() (any, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	if !isDigit(p.peek()) {
+		return nil, p.errorf("expected digit")
+	}
+	decimal := false
+	for !p.eof() {
+		c := p.peek()
+		if c == '.' && !decimal {
+			decimal = true
+		} else if !isDigit(c) {
+			break
+		}
+		p.pos++
+	}
+	num := p.s[start:p.pos]
+	digits := strings.TrimPrefix(num, "-")
+	if !decimal {
+		if len(digits) > 15 {
+			return nil, p.errorf("integer too long")
+		}
+		return strconv.ParseInt(num, 10, 64)
+	}
+	whole, frac, _ := strings.Cut(digits, ".")
+	if len(whole) > 12 || len(frac) == 0 || len(frac) > 3 {
+		return nil, p.errorf("invalid decimal")
+	}
+	return strconv.ParseFloat(num, 64)
+}
+
+func (p *sfParser) string() (string, error) {
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == '\\':
+			if p.eof() || (p.peek() != '"' && p.peek() != '\\') {
+				return "", p.errorf("invalid escape")
+			}
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		case c == '"':
+			return b.String(), nil
+		case c < 0x20 || c > 0x7e:
+			return "", p.errorf("invalid character in string")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *sfParser) token() sfToken {
+	start := p.pos
+	for !p.eof() && (isTchar(p.peek()) || p.peek() == ':' || p.peek() == '/') {
+		p.pos++
+	}
+	return sfToken(p.s[start:p.pos])
+}
+
+func (p *sfParser) byteSequence() ([]byte, error) {
+	p.pos++
+	end := strings.IndexByte(p.s[p.pos:], ':')
+	if end < 0 {
+		return nil, p.errorf("unterminated byte sequence")
+	}
+	encoded := p.s[p.pos : p.pos+end]
+	p.pos += end + 1
+	b, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, p.errorf("invalid byte sequence: %v", err)
+	}
+	return b, nil
+}
+
+func (p *sfParser) boolean() (bool, error) {
+	p.pos++
+	switch p.peek() {
+	case '1':
+		p.pos++
+		return true, nil
+	case '0':
+		p.pos++
+		return false, nil
+	}
+	return false, p.errorf("invalid boolean")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLcAlpha(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func isAlpha(c byte) bool {
+	return isLcAlpha(c) || (c >= 'A' && c <= 'Z')
+}
+
+func isTchar(c byte) bool {
+	return isAlpha(c) || isDigit(c) || strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0
+}
+
+// serializeDictionary serializes a dictionary in its canonical form
+func serializeDictionary(d sfDictionary) string {
+	members := make([]string, 0, len(d))
+	for _, m := range d {
+		if item, ok := m.value.(sfItem); ok && item.value == true {
+			members = append(members, m.key+serializeParams(item.params))
+			continue
+		}
+		members = append(members, m.key+"="+serializeMember(m.value))
+	}
+	return strings.Join(members, ", ")
+}
+
+// serializeList serializes a list in its canonical form
+func serializeList(l sfList) string {
+	members := make([]string, 0, len(l))
+	for _, m := range l {
+		members = append(members, serializeMember(m))
+	}
+	return strings.Join(members, ", ")
+}
+
+// serializeMember serializes an sfItem or an sfInnerList
+func serializeMember(v any) string {
+	if l, ok := v.(sfInnerList); ok {
+		return serializeInnerList(l)
+	}
+	return serializeItem(v.(sfItem))
+}
+
+func serializeInnerList(l sfInnerList) string {
+	items := make([]string, 0, len(l.items))
+	for _, item := range l.items {
+		items = append(items, serializeItem(item))
+	}
+	return "(" + strings.Join(items, " ") + ")" + serializeParams(l.params)
+}
+
+func serializeItem(item sfItem) string {
+	return serializeBareItem(item.value) + serializeParams(item.params)
+}
+
+func serializeParams(params sfParams) string {
+	var b strings.Builder
+	for _, param := range params {
+		b.WriteString(";" + param.key)
+		if param.value != true {
+			b.WriteString("=" + serializeBareItem(param.value))
+		}
+	}
+	return b.String()
+}
+
+func serializeBareItem(v any) string {
+	switch v := v.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		s := strconv.FormatFloat(v, 'f', 3, 64)
+		s = strings.TrimRight(s, "0")
+		if strings.HasSuffix(s, ".") {
+			s += "0"
+		}
+		return s
+	case string:
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
+	case sfToken:
+		return string(v)
+	case []byte:
+		return ":" + base64.StdEncoding.EncodeToString(v) + ":"
+	case bool:
+		if v {
+			return "?1"
+		}
+		return "?0"
+	}
+	panic(fmt.Sprintf("not a structured field bare item: %T", v))
+}
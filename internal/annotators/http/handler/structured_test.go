@@ -0,0 +1,95 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDictionary(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		serialized  string // expected serialization of the parsed dictionary
+		expectError bool
+	}{
+		{"items", `a=1, b=2;x=1;y=2, c=(a b c)`, `a=1, b=2;x=1;y=2, c=(a b c)`, false},
+		{"whitespace", `a=1,    b=2;x=1;y=2,   c=(a   b   c)`, `a=1, b=2;x=1;y=2, c=(a b c)`, false},
+		{"boolean members", `a, b=?0, c;x`, `a, b=?0, c;x`, false},
+		{"bare items", `s="quoted \"x\"", t=tok/en, d=-1.50, b=:AQID:`, `s="quoted \"x\"", t=tok/en, d=-1.5, b=:AQID:`, false},
+		{"duplicate key keeps last value", `a=1, b=2, a=3`, `a=3, b=2`, false},
+		{"signature input", `sig1=("@method" "@query-param";name="Pet");created=1618884473;keyid="k"`,
+			`sig1=("@method" "@query-param";name="Pet");created=1618884473;keyid="k"`, false},
+		{"empty", ``, ``, false},
+		{"uppercase key", `A=1`, ``, true},
+		{"trailing comma", `a=1,`, ``, true},
+		{"unterminated string", `a="x`, ``, true},
+		{"unterminated inner list", `a=(1 2`, ``, true},
+		{"invalid byte sequence", `a=:not base64:`, ``, true},
+		{"integer too long", `a=1234567890123456`, ``, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := parseDictionary(tt.value)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.serialized, serializeDictionary(d))
+			}
+		})
+	}
+}
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		serialized  string
+		expectError bool
+	}{
+		{"tokens", `sugar, tea, rum`, `sugar, tea, rum`, false},
+		{"inner lists", `("foo" "bar");lvl=5, ("baz");lvl=1, ()`, `("foo" "bar");lvl=5, ("baz");lvl=1, ()`, false},
+		{"parameters", `abc;a=1;b=2; cde_456, (ghi;jk=4 l);q="9";r=w`, `abc;a=1;b=2;cde_456, (ghi;jk=4 l);q="9";r=w`, false},
+		{"decimal", `4.5, -0.25`, `4.5, -0.25`, false},
+		{"trailing garbage", `a b`, ``, true},
+		{"non ascii string", "\"café\"", ``, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := parseList(tt.value)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.serialized, serializeList(l))
+			}
+		})
+	}
+}
+
+func TestParseItem(t *testing.T) {
+	item, err := parseItem(`"@query-param";name="var"`)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Equal(t, "@query-param", item.value)
+	name, ok := item.params.get("name")
+	assert.True(t, ok)
+	assert.Equal(t, "var", name)
+	assert.Equal(t, `"@query-param";name="var"`, serializeItem(item))
+
+	_, err = parseItem(`"a" "b"`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// DefaultFields are the components covered by the signatures of a transport when none are given
+var DefaultFields = []string{string(contracts.Method), string(contracts.Path), string(contracts.Authority),
+	contracts.HttpContentType, contracts.ContentDigest}
+
+type transport struct {
+	base       http.RoundTripper
+	keys       config.SignatureInfo
+	fields     []string
+	newHandler func(*http.Request) interfaces.RequestHandler
+}
+
+// NewTransport returns a RoundTripper adding Signature-Input and Signature headers to each request, with the handler
+// returned by newHandler, before it is sent by base. Without fields, the components set by keys.Http.Request are
+// covered, or else DefaultFields. Header fields that a request lacks are not covered by its signature, unless required
+// by keys.Http.Request, in which case the request fails. When Content-Digest is covered, the header is set from the
+// body of requests that have one.
+func NewTransport(base http.RoundTripper, keys config.SignatureInfo, fields []string,
+	newHandler func(*http.Request) interfaces.RequestHandler) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base, keys: keys, fields: fields, newHandler: newHandler}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request it is given
+	signed := req.Clone(req.Context())
+	if signed.Host == "" {
+		signed.Host = signed.URL.Host
+	}
+	if signed.ContentLength > 0 && signed.Header.Get(contracts.ContentLength) == "" {
+		// the length is sent from ContentLength rather than the header, which is set for it to be covered
+		signed.Header.Set(contracts.ContentLength, strconv.FormatInt(signed.ContentLength, 10))
+	}
+
+	fields, required := t.fields, []string(nil)
+	if len(fields) == 0 {
+		fields, required = coverage(t.keys, false)
+	}
+	if contains(fields, contracts.ContentDigest) && signed.Header.Get(contracts.ContentDigest) == "" {
+		body, err := readBody(signed)
+		if err != nil {
+			return nil, err
+		}
+		// a required digest binds the absence of a body
+		if body != nil || contains(required, contracts.ContentDigest) {
+			signed.Header.Set(contracts.ContentDigest, ContentDigest(body))
+		}
+	}
+	covered, err := presentFields(signed.Header, fields, required)
+	if err == nil {
+		err = t.newHandler(signed).AddSignatureHeaders(time.Now(), covered, t.keys)
+	}
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.base.RoundTrip(signed)
+}
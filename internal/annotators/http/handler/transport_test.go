@@ -0,0 +1,163 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransport(t *testing.T) {
+	b, err := os.ReadFile("./test/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var seeds []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the request as the client sent it
+		r.URL.Scheme = "http"
+		r.URL.Host = r.Host
+		parsed, err := ParseSignature(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		ok, err := ed25519.New().Verify(cfg.Signature.PublicKey, []byte(parsed.Seed), []byte(parsed.Signature))
+		if err != nil || !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if digest := r.Header.Get("Content-Digest"); digest != "" {
+			body, _ := io.ReadAll(r.Body)
+			if VerifyContentDigest(digest, body) != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		seeds = append(seeds, parsed.Seed)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, cfg.Signature, nil, NewEd25519RequestHandler)}
+
+	tests := []struct {
+		name    string
+		method  string
+		body    []byte
+		covered []string
+	}{
+		{"get", "GET", nil, []string{`"@method": GET`, `"@path": /foo`}},
+		{"post", "POST", []byte(`{"key":"keyA"}`), []string{`"@method": POST`, `"content-type": application/json`,
+			`"content-digest": ` + ContentDigest([]byte(`{"key":"keyA"}`))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+"/foo", bytes.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			if assert.NotEmpty(t, seeds) {
+				for _, c := range tt.covered {
+					assert.True(t, strings.Contains(seeds[len(seeds)-1], c), c)
+				}
+			}
+			// the request given to the client is left unsigned
+			assert.Empty(t, req.Header.Get("Signature"))
+		})
+	}
+}
+
+func TestTransport_Coverage(t *testing.T) {
+	b, err := os.ReadFile("./test/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var covered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.URL.Scheme = "http"
+		r.URL.Host = r.Host
+		parsed, err := ParseSignature(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		covered = parsed.Components
+	}))
+	defer server.Close()
+
+	keys := cfg.Signature
+	keys.Http = &config.HttpSignatureInfo{Request: &config.HttpCoverageInfo{
+		Required: []string{"@method", "Content-Digest"},
+		Optional: []string{"X-Trace"},
+	}}
+	client := &http.Client{Transport: NewTransport(nil, keys, nil, NewEd25519RequestHandler)}
+
+	// a required digest is set for requests without a body
+	resp, err := client.Get(server.URL + "/foo")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{`"@method"`, `"content-digest"`}, covered)
+
+	req, _ := http.NewRequest("GET", server.URL+"/foo", nil)
+	req.Header.Set("X-Trace", "abc")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	resp.Body.Close()
+	assert.Equal(t, []string{`"@method"`, `"content-digest"`, `"x-trace"`}, covered)
+
+	// a required header field that a request lacks fails it
+	keys.Http = &config.HttpSignatureInfo{Request: &config.HttpCoverageInfo{Required: []string{"X-Trace"}}}
+	client = &http.Client{Transport: NewTransport(nil, keys, nil, NewEd25519RequestHandler)}
+	_, err = client.Get(server.URL + "/foo")
+	assert.Error(t, err)
+}
@@ -16,26 +16,24 @@ package http
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"net/http"
 	"os"
-	"path/filepath"
-	"strings"
 
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
 	handler "github.com/project-alvarium/alvarium-sdk-go/internal/annotators/http/handler"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
 )
 
-// HttpPkiAnnotator is used to validate whether the signature on a given piece of data is valid, both sent in the HTTP message
+// HttpPkiAnnotator is used to validate whether the signature on a given piece of data is valid, both sent in the HTTP message.
+// The message is a request, or a response when one is found in the Context under contracts.HttpResponseKey.
 type HttpPkiAnnotator struct {
 	hash      interfaces.HashProvider
 	hashType  contracts.HashType
 	kind      contracts.AnnotationType
 	signature interfaces.SignatureProvider
-	privKey   config.KeyInfo
+	keys      config.SignatureInfo
 	pubKey    config.KeyInfo
 	layer     contracts.LayerType
 }
@@ -46,52 +44,92 @@ func NewHttpPkiAnnotator(cfg config.SdkInfo, hash interfaces.HashProvider, sign
 	a.hashType = cfg.Hash.Type
 	a.kind = contracts.AnnotationPKIHttp
 	a.signature = sign
-	a.privKey = cfg.Signature.PrivateKey
+	a.keys = cfg.Signature
 	a.pubKey = cfg.Signature.PublicKey
 	a.layer = cfg.Layer
 	return &a
 }
 
-func (a *HttpPkiAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
-	key := a.hash.Derive(data)
-	hostname, _ := os.Hostname()
-
-	//Call parser on request
-	req := ctx.Value(contracts.HttpRequestKey)
-	parsed, err := handler.ParseSignature(req.(*http.Request))
+// Kind returns the kind of annotation made by the annotator
+func (a *HttpPkiAnnotator) Kind() contracts.AnnotationType {
+	return a.kind
+}
 
+func (a *HttpPkiAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
+	key, err := annotators.DeriveHash(ctx, a.hash, data)
 	if err != nil {
 		return contracts.Annotation{}, err
 	}
-	var sig signable
-	sig.Seed = parsed.Seed
-	sig.Signature = parsed.Signature
+	hostname, _ := os.Hostname()
 
-	// Use the parsed request to obtain the key name and type we should use to validate the signature
-	var k config.KeyInfo
-	directory := filepath.Dir(a.pubKey.Path)
-	k.Path = strings.Join([]string{directory, parsed.Keyid}, "/")
-	k.Type = contracts.KeyAlgorithm(parsed.Algorithm)
-	if !(k.Type.Validate()) {
-		return contracts.Annotation{}, errors.New("invalid key type specified: " + parsed.Algorithm)
+	//Call parser on the response if one is given, otherwise on the request
+	message := ctx.Value(contracts.HttpRequestKey)
+	if resp, ok := ctx.Value(contracts.HttpResponseKey).(*http.Response); ok {
+		message = resp
 	}
-
-	ok, err := sig.verifySignature(k, a.signature)
+	ok, err := verifyMessage(message, data, a.keys, a.signature)
 	if err != nil {
 		return contracts.Annotation{}, err
 	}
 
 	annotation := contracts.NewAnnotation(string(key), a.hashType, hostname, a.layer, a.kind, ok)
-	b, err := json.Marshal(annotation)
-	if err != nil {
+	annotation.Timestamp = annotators.Timestamp(ctx, annotation)
+	annotation.ParentKey = annotators.ParentKey(ctx)
+	annotation.Tag = annotators.TagValue(ctx, annotation)
+	annotation.HostInfo = annotators.HostInfo(ctx)
+	annotation.Id = annotators.NewId(ctx, annotation)
+	if err = annotators.SignAndLink(ctx, a.keys, a.signature, &annotation); err != nil {
 		return contracts.Annotation{}, err
 	}
-	signed, err := a.signature.Sign(a.privKey, b)
+	return annotation, nil
+}
+
+// verifyMessage verifies the signatures of an HTTP message with the keys named by their keyid, see
+// handler.VerificationKey, that they cover the components required by keys.Http, and that data matches the
+// Content-Digest of the message if it has one
+func verifyMessage(message any, data []byte, keys config.SignatureInfo, signature interfaces.SignatureProvider) (bool, error) {
+	signatures, err := handler.ParseMessages(message)
 	if err != nil {
-		return contracts.Annotation{}, err
+		return false, err
 	}
-	annotation.Signature = signed
-	return annotation, nil
+	if err = handler.VerifyCoverage(message, signatures, keys); err != nil {
+		return false, nil
+	}
+	// The countersignatures added by intermediaries must be valid along with the original signature
+	ok := true
+	for _, parsed := range signatures {
+		var sig signable
+		sig.Seed = parsed.Seed
+		sig.Signature = parsed.Signature
+
+		// Use the parsed request to obtain the key name and type we should use to validate the signature
+		k, err := handler.VerificationKey(keys, parsed.Keyid, parsed.Algorithm)
+		if err != nil {
+			return false, err
+		}
+
+		verified, err := sig.verifySignature(k, signature)
+		if err != nil {
+			return false, err
+		}
+		ok = ok && verified
+	}
+	// the data must match the Content-Digest of the message, which binds it to the signature
+	if digest := messageHeader(message).Get(contracts.ContentDigest); ok && digest != "" {
+		ok = handler.VerifyContentDigest(digest, data) == nil
+	}
+	return ok, nil
+}
+
+// messageHeader returns the header of the HTTP message parsed by the annotator
+func messageHeader(message any) http.Header {
+	switch m := message.(type) {
+	case *http.Request:
+		return m.Header
+	case *http.Response:
+		return m.Header
+	}
+	return http.Header{}
 }
 
 // TODO: At this point this type has converged with the one defined in annotators/pki.go. Eliminate duplicate definition
@@ -75,6 +75,21 @@ func TestHttpPkiAnnotator_Do(t *testing.T) {
 	t5 := t1
 	t5.Signature = "invalid"
 
+	// Tests a countersignature added by a gateway to the original signature
+	countersigned := req.Clone(req.Context())
+	gateway := handler.NewEd25519RequestHandlerWithLabel(countersigned, "gateway")
+	err = gateway.AddSignatureHeaders(time.Now(), []string{string(contracts.Method)}, cfg.Signature)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	t6 := testData{
+		SignatureInput: countersigned.Header.Get("Signature-Input"),
+		Signature:      countersigned.Header.Get("Signature"),
+	}
+
+	t7 := t6
+	t7.Signature = t1.Signature + ", gateway=:aW52YWxpZA==:"
+
 	tests := []struct {
 		name        string
 		expectError bool
@@ -85,6 +100,8 @@ func TestHttpPkiAnnotator_Do(t *testing.T) {
 		{"pki key not found", true, t3},
 		{"pki empty signature", false, t4},
 		{"pki invalid signature", false, t5},
+		{"pki countersigned", false, t6},
+		{"pki invalid countersignature", false, t7},
 	}
 
 	for _, tt := range tests {
@@ -100,17 +117,18 @@ func TestHttpPkiAnnotator_Do(t *testing.T) {
 			anno, err := pki.Do(ctx, data)
 			test.CheckError(err, tt.expectError, tt.name, t)
 			if err == nil {
-				result, err := annotators.VerifySignature(cfg.Signature.PublicKey, s, anno)
+				result, err := annotators.VerifySignature(cfg.Signature.PublicKey, s, anno, cfg.Signature.Canonicalization)
 				if err != nil {
 					t.Error(err.Error())
 				} else if !result {
 					t.Error("signature not verified")
 				}
-				if tt.name == "pki empty signature" || tt.name == "pki invalid signature" {
+				if tt.name == "pki empty signature" || tt.name == "pki invalid signature" ||
+					tt.name == "pki invalid countersignature" {
 					if anno.IsSatisfied {
 						t.Errorf("satisfied should be false")
 					}
-				} else if tt.name == "pki annotation OK" {
+				} else if tt.name == "pki annotation OK" || tt.name == "pki countersigned" {
 					if !anno.IsSatisfied {
 						t.Errorf("satisfied should be true")
 					}
@@ -120,6 +138,56 @@ func TestHttpPkiAnnotator_Do(t *testing.T) {
 	}
 }
 
+func TestHttpPkiAnnotator_DoResponse(t *testing.T) {
+	b, err := os.ReadFile("./test/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	req, _, err := buildRequest(cfg.Signature)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	resp, data, err := buildResponse(cfg.Signature)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name      string
+		status    int
+		data      []byte
+		satisfied bool
+	}{
+		{"pki response OK", http.StatusOK, data, true},
+		{"pki response status changed", http.StatusNotFound, data, false},
+		{"pki response body changed", http.StatusOK, []byte(`{"key":"keyB"}`), false},
+	}
+
+	for _, tt := range tests {
+		resp.StatusCode = tt.status
+		// the response is verified rather than the request it answers
+		ctx := context.WithValue(req.Context(), contracts.HttpRequestKey, req)
+		ctx = context.WithValue(ctx, contracts.HttpResponseKey, resp)
+
+		t.Run(tt.name, func(t *testing.T) {
+			s := ed25519.New()
+			pki := NewHttpPkiAnnotator(cfg, hash256.New(), s)
+			anno, err := pki.Do(ctx, tt.data)
+			test.CheckError(err, false, tt.name, t)
+			if err == nil && anno.IsSatisfied != tt.satisfied {
+				t.Errorf("satisfied should be %v", tt.satisfied)
+			}
+		})
+	}
+}
+
 func buildRequest(keys config.SignatureInfo) (*http.Request, []byte, error) {
 	type sample struct {
 		Key   string `json:"key"`
@@ -146,3 +214,23 @@ func buildRequest(keys config.SignatureInfo) (*http.Request, []byte, error) {
 	}
 	return req, b, nil
 }
+
+func buildResponse(keys config.SignatureInfo) (*http.Response, []byte, error) {
+	b := []byte(`{"key":"keyA","value":"This is some test data"}`)
+
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", string(contracts.ContentTypeJSON))
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	w.Header().Set("Content-Digest", handler.ContentDigest(b))
+
+	fields := []string{string(contracts.Status), contracts.HttpContentType, contracts.ContentLength,
+		contracts.ContentDigest}
+	handler := handler.NewEd25519ResponseHandler(w.Header(), http.StatusOK)
+	err := handler.AddSignatureHeaders(time.Now(), fields, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+	return w.Result(), b, nil
+}
@@ -0,0 +1,87 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// HttpSourceAnnotator is used to provide the provenance of data fetched from an upstream API, the body of the HTTP
+// response found in the Context under contracts.HttpResponseKey. The data is satisfied when the response is signed by
+// the upstream, its signatures verifying and its Content-Digest matching the data. Unlike the pki-http annotator, an
+// unsigned response makes an unsatisfied annotation rather than an error.
+type HttpSourceAnnotator struct {
+	hash      interfaces.HashProvider
+	hashType  contracts.HashType
+	kind      contracts.AnnotationType
+	signature interfaces.SignatureProvider
+	keys      config.SignatureInfo
+	layer     contracts.LayerType
+}
+
+func NewHttpSourceAnnotator(cfg config.SdkInfo, hash interfaces.HashProvider, sign interfaces.SignatureProvider) interfaces.Annotator {
+	a := HttpSourceAnnotator{}
+	a.hash = hash
+	a.hashType = cfg.Hash.Type
+	a.kind = contracts.AnnotationSourceHttp
+	a.signature = sign
+	a.keys = cfg.Signature
+	a.layer = cfg.Layer
+	return &a
+}
+
+// Kind returns the kind of annotation made by the annotator
+func (a *HttpSourceAnnotator) Kind() contracts.AnnotationType {
+	return a.kind
+}
+
+func (a *HttpSourceAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
+	key, err := annotators.DeriveHash(ctx, a.hash, data)
+	if err != nil {
+		return contracts.Annotation{}, err
+	}
+	hostname, _ := os.Hostname()
+
+	resp, ok := ctx.Value(contracts.HttpResponseKey).(*http.Response)
+	if !ok || resp == nil {
+		return contracts.Annotation{}, errors.New("no HTTP response found in context")
+	}
+	ok = false
+	if len(resp.Header.Values("Signature-Input")) > 0 {
+		ok, err = verifyMessage(resp, data, a.keys, a.signature)
+		if err != nil {
+			return contracts.Annotation{}, err
+		}
+	}
+
+	annotation := contracts.NewAnnotation(string(key), a.hashType, hostname, a.layer, a.kind, ok)
+	annotation.Timestamp = annotators.Timestamp(ctx, annotation)
+	annotation.ParentKey = annotators.ParentKey(ctx)
+	annotation.Tag = annotators.TagValue(ctx, annotation)
+	annotation.HostInfo = annotators.HostInfo(ctx)
+	annotation.Id = annotators.NewId(ctx, annotation)
+	if err = annotators.SignAndLink(ctx, a.keys, a.signature, &annotation); err != nil {
+		return contracts.Annotation{}, err
+	}
+	return annotation, nil
+}
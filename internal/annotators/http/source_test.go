@@ -0,0 +1,108 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	hash256 "github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/sha256"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+)
+
+func TestHttpSourceAnnotator_Do(t *testing.T) {
+	b, err := os.ReadFile("./test/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	resp, data, err := buildResponse(cfg.Signature)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	unsigned := *resp
+	unsigned.Header = resp.Header.Clone()
+	unsigned.Header.Del("Signature-Input")
+	unsigned.Header.Del("Signature")
+	badKey := *resp
+	badKey.Header = resp.Header.Clone()
+	badKey.Header.Set("Signature-Input", `sig1=("@status");created=1646146637;keyid="invalid";alg="ed25519"`)
+
+	tests := []struct {
+		name        string
+		resp        *http.Response
+		data        []byte
+		satisfied   bool
+		expectError bool
+	}{
+		{"src-http OK", resp, data, true, false},
+		{"src-http body changed", resp, []byte(`{"key":"keyB"}`), false, false},
+		{"src-http unsigned response", &unsigned, data, false, false},
+		{"src-http key not found", &badKey, data, false, true},
+		{"src-http no response", nil, data, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.resp != nil {
+				ctx = context.WithValue(ctx, contracts.HttpResponseKey, tt.resp)
+			}
+			s := ed25519.New()
+			src := NewHttpSourceAnnotator(cfg, hash256.New(), s)
+			anno, err := src.Do(ctx, tt.data)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				result, err := annotators.VerifySignature(cfg.Signature.PublicKey, s, anno, cfg.Signature.Canonicalization)
+				if err != nil {
+					t.Error(err.Error())
+				} else if !result {
+					t.Error("signature not verified")
+				}
+				if anno.Kind != contracts.AnnotationSourceHttp {
+					t.Errorf("unexpected kind %s", anno.Kind)
+				}
+				if anno.IsSatisfied != tt.satisfied {
+					t.Errorf("satisfied should be %v", tt.satisfied)
+				}
+			}
+		})
+	}
+
+	t.Run("src-http required component not covered", func(t *testing.T) {
+		strict := cfg
+		strict.Signature.Http = &config.HttpSignatureInfo{Response: &config.HttpCoverageInfo{Required: []string{"X-Request-Id"}}}
+		ctx := context.WithValue(context.Background(), contracts.HttpResponseKey, resp)
+		anno, err := NewHttpSourceAnnotator(strict, hash256.New(), ed25519.New()).Do(ctx, data)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		if anno.IsSatisfied {
+			t.Errorf("satisfied should be false")
+		}
+	})
+}
@@ -30,7 +30,7 @@ type PkiAnnotator struct {
 	hashType  contracts.HashType
 	kind      contracts.AnnotationType
 	signature interfaces.SignatureProvider
-	privKey   config.KeyInfo
+	keys      config.SignatureInfo
 	pubKey    config.KeyInfo
 	layer     contracts.LayerType
 }
@@ -41,37 +41,57 @@ func NewPkiAnnotator(cfg config.SdkInfo, hash interfaces.HashProvider, sign inte
 	a.hashType = cfg.Hash.Type
 	a.kind = contracts.AnnotationPKI
 	a.signature = sign
-	a.privKey = cfg.Signature.PrivateKey
+	a.keys = cfg.Signature
 	a.pubKey = cfg.Signature.PublicKey
 	a.layer = cfg.Layer
 	return &a
 }
 
-func (a *PkiAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
-	key := a.hash.Derive(data)
-	hostname, _ := os.Hostname()
+// Kind returns the kind of annotation made by the annotator
+func (a *PkiAnnotator) Kind() contracts.AnnotationType {
+	return a.kind
+}
 
-	var sig signable
-	err := json.Unmarshal(data, &sig)
+func (a *PkiAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
+	annotation, err := a.annotate(ctx, data)
 	if err != nil {
 		return contracts.Annotation{}, err
 	}
+	if err = SignAndLink(ctx, a.keys, a.signature, &annotation); err != nil {
+		return contracts.Annotation{}, err
+	}
+	return annotation, nil
+}
 
-	ok, err := sig.verifySignature(a.pubKey, a.signature)
+// DoBatch annotates each piece of data, signing the annotations together, see AnnotateBatch
+func (a *PkiAnnotator) DoBatch(ctx context.Context, data [][]byte) ([]contracts.Annotation, error) {
+	return AnnotateBatch(ctx, a.keys, a.signature, data, a.annotate)
+}
+
+// annotate creates the unsigned annotation of the data
+func (a *PkiAnnotator) annotate(ctx context.Context, data []byte) (contracts.Annotation, error) {
+	key, err := DeriveHash(ctx, a.hash, data)
 	if err != nil {
 		return contracts.Annotation{}, err
 	}
-	annotation := contracts.NewAnnotation(string(key), a.hashType, hostname, a.layer, a.kind, ok)
+	hostname, _ := os.Hostname()
 
-	b, err := json.Marshal(annotation)
+	var sig signable
+	err = json.Unmarshal(data, &sig)
 	if err != nil {
 		return contracts.Annotation{}, err
 	}
-	signed, err := a.signature.Sign(a.privKey, b)
+
+	ok, err := sig.verifySignature(a.pubKey, a.signature)
 	if err != nil {
 		return contracts.Annotation{}, err
 	}
-	annotation.Signature = signed
+	annotation := contracts.NewAnnotation(string(key), a.hashType, hostname, a.layer, a.kind, ok)
+	annotation.Timestamp = Timestamp(ctx, annotation)
+	annotation.ParentKey = ParentKey(ctx)
+	annotation.Tag = TagValue(ctx, annotation)
+	annotation.HostInfo = HostInfo(ctx)
+	annotation.Id = NewId(ctx, annotation)
 	return annotation, nil
 }
 
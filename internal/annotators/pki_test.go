@@ -85,7 +85,7 @@ func TestPkiAnnotator_Do(t *testing.T) {
 			anno, err := tpm.Do(context.Background(), b)
 			test.CheckError(err, tt.expectError, tt.name, t)
 			if err == nil {
-				result, err := VerifySignature(tt.cfg.Signature.PublicKey, tt.s, anno)
+				result, err := VerifySignature(tt.cfg.Signature.PublicKey, tt.s, anno, tt.cfg.Signature.Canonicalization)
 				if err != nil {
 					t.Error(err.Error())
 				} else if !result {
@@ -16,7 +16,6 @@ package annotators
 
 import (
 	"context"
-	"encoding/json"
 	"os"
 
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
@@ -30,7 +29,7 @@ type SourceAnnotator struct {
 	hashType  contracts.HashType
 	kind      contracts.AnnotationType
 	signature interfaces.SignatureProvider
-	privKey   config.KeyInfo
+	keys      config.SignatureInfo
 	layer     contracts.LayerType
 }
 
@@ -40,24 +39,45 @@ func NewSourceAnnotator(cfg config.SdkInfo, hash interfaces.HashProvider, sign i
 	a.hashType = cfg.Hash.Type
 	a.kind = contracts.AnnotationSource
 	a.signature = sign
-	a.privKey = cfg.Signature.PrivateKey
+	a.keys = cfg.Signature
 	a.layer = cfg.Layer
 	return &a
 }
 
-func (a *SourceAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
-	key := a.hash.Derive(data)
-	hostname, _ := os.Hostname()
+// Kind returns the kind of annotation made by the annotator
+func (a *SourceAnnotator) Kind() contracts.AnnotationType {
+	return a.kind
+}
 
-	annotation := contracts.NewAnnotation(key, a.hashType, hostname, a.layer, a.kind, true)
-	b, err := json.Marshal(annotation)
+func (a *SourceAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
+	annotation, err := a.annotate(ctx, data)
 	if err != nil {
 		return contracts.Annotation{}, err
 	}
-	sig, err := a.signature.Sign(a.privKey, b)
+	if err = SignAndLink(ctx, a.keys, a.signature, &annotation); err != nil {
+		return contracts.Annotation{}, err
+	}
+	return annotation, nil
+}
+
+// DoBatch annotates each piece of data, signing the annotations together, see AnnotateBatch
+func (a *SourceAnnotator) DoBatch(ctx context.Context, data [][]byte) ([]contracts.Annotation, error) {
+	return AnnotateBatch(ctx, a.keys, a.signature, data, a.annotate)
+}
+
+// annotate creates the unsigned annotation of the data
+func (a *SourceAnnotator) annotate(ctx context.Context, data []byte) (contracts.Annotation, error) {
+	key, err := DeriveHash(ctx, a.hash, data)
 	if err != nil {
 		return contracts.Annotation{}, err
 	}
-	annotation.Signature = sig
+	hostname, _ := os.Hostname()
+
+	annotation := contracts.NewAnnotation(key, a.hashType, hostname, a.layer, a.kind, true)
+	annotation.Timestamp = Timestamp(ctx, annotation)
+	annotation.ParentKey = ParentKey(ctx)
+	annotation.Tag = TagValue(ctx, annotation)
+	annotation.HostInfo = HostInfo(ctx)
+	annotation.Id = NewId(ctx, annotation)
 	return annotation, nil
 }
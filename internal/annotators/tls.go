@@ -17,7 +17,6 @@ package annotators
 import (
 	"context"
 	"crypto/tls"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -32,7 +31,7 @@ type TlsAnnotator struct {
 	hashType  contracts.HashType
 	kind      contracts.AnnotationType
 	signature interfaces.SignatureProvider
-	privKey   config.KeyInfo
+	keys      config.SignatureInfo
 	layer     contracts.LayerType
 }
 
@@ -42,13 +41,38 @@ func NewTlsAnnotator(cfg config.SdkInfo, hash interfaces.HashProvider, sign inte
 	a.hashType = cfg.Hash.Type
 	a.kind = contracts.AnnotationTLS
 	a.signature = sign
-	a.privKey = cfg.Signature.PrivateKey
+	a.keys = cfg.Signature
 	a.layer = cfg.Layer
 	return &a
 }
 
+// Kind returns the kind of annotation made by the annotator
+func (a *TlsAnnotator) Kind() contracts.AnnotationType {
+	return a.kind
+}
+
 func (a *TlsAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
-	key := a.hash.Derive(data)
+	annotation, err := a.annotate(ctx, data)
+	if err != nil {
+		return contracts.Annotation{}, err
+	}
+	if err = SignAndLink(ctx, a.keys, a.signature, &annotation); err != nil {
+		return contracts.Annotation{}, err
+	}
+	return annotation, nil
+}
+
+// DoBatch annotates each piece of data, signing the annotations together, see AnnotateBatch
+func (a *TlsAnnotator) DoBatch(ctx context.Context, data [][]byte) ([]contracts.Annotation, error) {
+	return AnnotateBatch(ctx, a.keys, a.signature, data, a.annotate)
+}
+
+// annotate creates the unsigned annotation of the data
+func (a *TlsAnnotator) annotate(ctx context.Context, data []byte) (contracts.Annotation, error) {
+	key, err := DeriveHash(ctx, a.hash, data)
+	if err != nil {
+		return contracts.Annotation{}, err
+	}
 	hostname, _ := os.Hostname()
 	isSatisfied := false
 
@@ -71,15 +95,10 @@ func (a *TlsAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotatio
 		}
 	}
 	annotation := contracts.NewAnnotation(key, a.hashType, hostname, a.layer, a.kind, isSatisfied)
-
-	b, err := json.Marshal(annotation)
-	if err != nil {
-		return contracts.Annotation{}, err
-	}
-	signed, err := a.signature.Sign(a.privKey, b)
-	if err != nil {
-		return contracts.Annotation{}, err
-	}
-	annotation.Signature = signed
+	annotation.Timestamp = Timestamp(ctx, annotation)
+	annotation.ParentKey = ParentKey(ctx)
+	annotation.Tag = TagValue(ctx, annotation)
+	annotation.HostInfo = HostInfo(ctx)
+	annotation.Id = NewId(ctx, annotation)
 	return annotation, nil
 }
@@ -73,7 +73,7 @@ func TestTlsAnnotator_Base(t *testing.T) {
 			anno, err := tls.Do(context.Background(), []byte(tt.data))
 			test.CheckError(err, tt.expectError, tt.name, t)
 			if err == nil {
-				result, err := VerifySignature(tt.cfg.Signature.PublicKey, tt.s, anno)
+				result, err := VerifySignature(tt.cfg.Signature.PublicKey, tt.s, anno, tt.cfg.Signature.Canonicalization)
 				if err != nil {
 					t.Error(err.Error())
 				} else if !result {
@@ -1,3 +1,5 @@
+//go:build !alvarium_core && !js && !wasip1 && !tinygo
+
 /*******************************************************************************
  * Copyright 2024 Dell Inc.
  *
@@ -16,64 +18,113 @@ package annotators
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"os"
 
+	"github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
 )
 
-// Default path to a TPM 2.0 device (https://wiki.archlinux.org/title/Trusted_Platform_Module)
-const tpmPath string = "/dev/tpm0"
+// tpmFamily is the value of TPM_PT_FAMILY_INDICATOR reported by a TPM 2.0, "2.0" as a null terminated string
+const tpmFamily uint32 = 0x322e3000
 
-// TpmAnnotator is used to attest whether or not the host machine has TPM capability for managing secrets
+// TpmAnnotator is used to attest whether or not the host machine has TPM capability for managing secrets. The TPM is
+// reached through the kernel's resource manager or the device on Linux, and through TBS on Windows, and must answer
+// as a TPM 2.0.
 type TpmAnnotator struct {
+	open      func() (transport.TPMCloser, error)
 	hash      interfaces.HashProvider
 	hashType  contracts.HashType
 	kind      contracts.AnnotationType
 	signature interfaces.SignatureProvider
-	privKey   config.KeyInfo
+	keys      config.SignatureInfo
 	layer     contracts.LayerType
 }
 
 func NewTpmAnnotator(cfg config.SdkInfo, hash interfaces.HashProvider, sign interfaces.SignatureProvider) interfaces.Annotator {
 	a := TpmAnnotator{}
+	a.open = openTPM
 	a.hash = hash
 	a.hashType = cfg.Hash.Type
 	a.kind = contracts.AnnotationTPM
 	a.signature = sign
-	a.privKey = cfg.Signature.PrivateKey
+	a.keys = cfg.Signature
 	a.layer = cfg.Layer
 	return &a
 }
 
-func (a *TpmAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
-	key := a.hash.Derive(data)
-	hostname, _ := os.Hostname()
-	isSatisfied := false
+// Kind returns the kind of annotation made by the annotator
+func (a *TpmAnnotator) Kind() contracts.AnnotationType {
+	return a.kind
+}
 
-	// If mounted path exists, check that it's either a device or a socket (emulator)
-	// This logic based on code found in gp-tpm module.
-	// https://github.com/google/go-tpm/blob/b3942ee5b15a7bd19e6419d5903e6e64fbb3d4ba/tpmutil/run_other.go#L29
-	fi, err := os.Stat(tpmPath)
-	if err == nil {
-		// TPM mounted at default path
-		if fi.Mode()&os.ModeDevice != 0 || fi.Mode()&os.ModeSocket != 0 {
-			isSatisfied = true
-		}
+func (a *TpmAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
+	annotation, err := a.annotate(ctx, data)
+	if err != nil {
+		return contracts.Annotation{}, err
 	}
+	if err = SignAndLink(ctx, a.keys, a.signature, &annotation); err != nil {
+		return contracts.Annotation{}, err
+	}
+	return annotation, nil
+}
 
-	annotation := contracts.NewAnnotation(key, a.hashType, hostname, a.layer, a.kind, isSatisfied)
+// DoBatch annotates each piece of data, signing the annotations together, see AnnotateBatch
+func (a *TpmAnnotator) DoBatch(ctx context.Context, data [][]byte) ([]contracts.Annotation, error) {
+	return AnnotateBatch(ctx, a.keys, a.signature, data, a.annotate)
+}
 
-	b, err := json.Marshal(annotation)
+// annotate creates the unsigned annotation of the data
+func (a *TpmAnnotator) annotate(ctx context.Context, data []byte) (contracts.Annotation, error) {
+	key, err := DeriveHash(ctx, a.hash, data)
 	if err != nil {
 		return contracts.Annotation{}, err
 	}
-	signed, err := a.signature.Sign(a.privKey, b)
+	hostname, _ := os.Hostname()
+	isSatisfied, err := a.probe()
+	annotation := contracts.NewAnnotation(key, a.hashType, hostname, a.layer, a.kind, isSatisfied)
 	if err != nil {
-		return contracts.Annotation{}, err
+		annotation.Error = err.Error()
 	}
-	annotation.Signature = signed
+	annotation.Timestamp = Timestamp(ctx, annotation)
+	annotation.ParentKey = ParentKey(ctx)
+	annotation.Tag = TagValue(ctx, annotation)
+	annotation.HostInfo = HostInfo(ctx)
+	annotation.Id = NewId(ctx, annotation)
 	return annotation, nil
 }
+
+// probe reports whether the host has a TPM 2.0 answering commands. A host without a TPM is not an error, whereas a TPM
+// that cannot be opened, for lack of permissions for instance, or that does not answer as a TPM 2.0 is.
+func (a *TpmAnnotator) probe() (bool, error) {
+	t, err := a.open()
+	if err != nil {
+		if noTPM(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("could not open the TPM: %w", err)
+	}
+	defer t.Close()
+
+	rsp, err := tpm2.GetCapability{
+		Capability:    tpm2.TPMCapTPMProperties,
+		Property:      uint32(tpm2.TPMPTFamilyIndicator),
+		PropertyCount: 1,
+	}.Execute(t)
+	if err != nil {
+		return false, fmt.Errorf("the TPM did not answer: %w", err)
+	}
+	props, err := rsp.CapabilityData.Data.TPMProperties()
+	if err != nil {
+		return false, err
+	}
+	for _, p := range props.TPMProperty {
+		if p.Property == tpm2.TPMPTFamilyIndicator && p.Value == tpmFamily {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,78 @@
+//go:build cgo && !alvarium_core && !js && !wasip1 && !tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package annotators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/google/go-tpm/tpm2/transport"
+	"github.com/google/go-tpm/tpm2/transport/simulator"
+	hash256 "github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/sha256"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+// silentTPM accepts commands but never answers them, as a device that is not a TPM 2.0
+type silentTPM struct{}
+
+func (silentTPM) Send([]byte) ([]byte, error) { return nil, errors.New("no response") }
+func (silentTPM) Close() error                { return nil }
+
+func TestTpmAnnotator_Probe(t *testing.T) {
+	cfg := config.SdkInfo{
+		Hash: config.HashInfo{Type: contracts.SHA256Hash},
+		Signature: config.SignatureInfo{
+			PrivateKey: config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/private.key"},
+		},
+		Layer: contracts.Host,
+	}
+
+	tests := []struct {
+		name          string
+		open          func() (transport.TPMCloser, error)
+		expectSatisfy bool
+		expectError   bool
+	}{
+		{"simulator", func() (transport.TPMCloser, error) { return simulator.OpenSimulator() }, true, false},
+		{"no tpm", func() (transport.TPMCloser, error) {
+			return nil, fmt.Errorf("open /dev/tpm0: %w", fs.ErrNotExist)
+		}, false, false},
+		{"permission denied", func() (transport.TPMCloser, error) {
+			return nil, fmt.Errorf("open /dev/tpmrm0: %w", fs.ErrPermission)
+		}, false, true},
+		{"not a tpm 2.0", func() (transport.TPMCloser, error) { return silentTPM{}, nil }, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewTpmAnnotator(cfg, hash256.New(), ed25519.New()).(*TpmAnnotator)
+			a.open = tt.open
+
+			annotation, err := a.Do(context.Background(), []byte("reading"))
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Equal(t, tt.expectSatisfy, annotation.IsSatisfied)
+			assert.Equal(t, tt.expectError, annotation.Error != "", annotation.Error)
+		})
+	}
+}
@@ -1,3 +1,5 @@
+//go:build !alvarium_core && !js && !wasip1 && !tinygo
+
 /*******************************************************************************
  * Copyright 2024 Dell Inc.
  *
@@ -61,7 +63,7 @@ func TestTpmAnnotator_Do(t *testing.T) {
 			anno, err := tpm.Do(context.Background(), []byte(tt.data))
 			test.CheckError(err, tt.expectError, tt.name, t)
 			if err == nil {
-				result, err := VerifySignature(tt.cfg.Signature.PublicKey, tt.s, anno)
+				result, err := VerifySignature(tt.cfg.Signature.PublicKey, tt.s, anno, tt.cfg.Signature.Canonicalization)
 				if err != nil {
 					t.Error(err.Error())
 				} else if !result {
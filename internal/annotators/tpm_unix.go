@@ -0,0 +1,35 @@
+//go:build !windows && !alvarium_core && !js && !wasip1 && !tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package annotators
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/google/go-tpm/tpm2/transport"
+)
+
+// openTPM opens the kernel's TPM 2.0 resource manager, /dev/tpmrm0, which lets processes share the TPM, or else the
+// device itself, /dev/tpm0
+func openTPM() (transport.TPMCloser, error) {
+	return transport.OpenTPM()
+}
+
+// noTPM reports whether the TPM could not be opened because the host has none
+func noTPM(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
@@ -0,0 +1,34 @@
+//go:build windows && !alvarium_core && !tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package annotators
+
+import (
+	"errors"
+
+	"github.com/google/go-tpm/tpm2/transport"
+	"github.com/google/go-tpm/tpmutil/tbs"
+)
+
+// openTPM opens the TPM through TBS, the TPM Base Services of Windows, which only opens a TPM 2.0
+func openTPM() (transport.TPMCloser, error) {
+	return transport.OpenTPM()
+}
+
+// noTPM reports whether the TPM could not be opened because the host has none, or TBS has it disabled
+func noTPM(err error) bool {
+	return errors.Is(err, tbs.ErrTPMNotFound) || errors.Is(err, tbs.ErrServiceDisabled)
+}
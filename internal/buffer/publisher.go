@@ -0,0 +1,429 @@
+//go:build !alvarium_core && !js && !wasip1 && !tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package buffer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	defaultRetryInterval = 5 * time.Second
+	openTimeout          = time.Second
+)
+
+var (
+	bucketName = []byte("messages")
+
+	errNotOpen   = errors.New("stream buffer is not open")
+	errMalformed = errors.New("malformed buffered message")
+)
+
+// bufferedPublisher wraps a stream provider, queueing publishes on disk while the provider is unreachable and
+// draining them in order once it recovers
+type bufferedPublisher struct {
+	cfg      config.BufferInfo
+	provider interfaces.StreamProvider
+	logger   interfaces.Logger
+	interval time.Duration
+
+	mu        sync.Mutex
+	db        *bolt.DB
+	count     int    // count is the number of buffered messages
+	connected bool   // connected indicates the provider has connected at least once
+	inflight  []byte // inflight is the key of the buffered message being published by drain, it is never dropped
+	// confirms confirm the delivery of the messages buffered in this session by key, see message.DeferDelivery
+	confirms map[string]func(err error)
+
+	// ctx bounds the connects and publishes made while draining, it is canceled on Close
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error // err is the result of the first Close
+}
+
+func NewBufferedPublisher(cfg config.BufferInfo, provider interfaces.StreamProvider,
+	logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("buffer path is required")
+	}
+	if cfg.Capacity < 0 {
+		return nil, fmt.Errorf("invalid buffer capacity %v", cfg.Capacity)
+	}
+	if cfg.DropPolicy == "" {
+		cfg.DropPolicy = contracts.DropOldest
+	}
+	if !cfg.DropPolicy.Validate() {
+		return nil, fmt.Errorf("invalid DropPolicy value provided %s", cfg.DropPolicy)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := bufferedPublisher{
+		cfg:      cfg,
+		provider: provider,
+		logger:   logger,
+		interval: defaultRetryInterval,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		confirms: make(map[string]func(err error)),
+	}
+	if cfg.RetryInterval > 0 {
+		p.interval = time.Duration(cfg.RetryInterval) * time.Second
+	}
+	return &p, nil
+}
+
+// Connect opens the buffer and attempts to connect the underlying provider. An unreachable provider is not an error,
+// publishes are buffered until a later reconnect attempt succeeds.
+func (p *bufferedPublisher) Connect(ctx context.Context) error {
+	db, err := bolt.Open(p.cfg.Path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(bucketName)
+		if err != nil {
+			return err
+		}
+		p.count = b.Stats().KeyN
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return err
+	}
+	p.mu.Lock()
+	p.db = db
+	p.mu.Unlock()
+
+	if err = p.provider.Connect(ctx); err != nil {
+		p.logger.Error(fmt.Sprintf("stream provider unreachable, buffering publishes %s", err.Error()))
+	} else {
+		p.mu.Lock()
+		p.connected = true
+		p.mu.Unlock()
+	}
+	if p.count > 0 {
+		p.logger.Write(slog.LevelInfo, fmt.Sprintf("stream buffer holds %v messages from a previous session", p.count))
+	}
+
+	p.wg.Add(1)
+	go p.run()
+	return nil
+}
+
+// Publish hands the message directly to the provider unless earlier messages are still waiting to be delivered, in
+// which case it is queued behind them to preserve ordering. Publishing before Connect or after Close is an error.
+func (p *bufferedPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	p.mu.Lock()
+	if p.db == nil {
+		p.mu.Unlock()
+		return errNotOpen
+	}
+	direct := p.connected && p.count == 0
+	p.mu.Unlock()
+
+	if direct {
+		err := p.provider.Publish(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		p.logger.Error(fmt.Sprintf("publish failed, buffering message %s", err.Error()))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.db == nil {
+		return errNotOpen
+	}
+	return p.enqueue(msg, message.DeferDelivery(ctx))
+}
+
+// Healthy reports the publisher as unhealthy while the bbolt store holding the buffer is unavailable, such as before
+// Connect or after Close, and while the wrapped provider has not connected. Publishes are still accepted while the
+// provider is unhealthy, but are held in the buffer.
+func (p *bufferedPublisher) Healthy(ctx context.Context) error {
+	p.mu.Lock()
+	db, connected, count := p.db, p.connected, p.count
+	p.mu.Unlock()
+	if db == nil {
+		return errNotOpen
+	}
+	err := db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(bucketName) == nil {
+			return errors.New("stream buffer bucket is missing")
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("stream buffer unavailable %w", err)
+	}
+	if !connected {
+		return fmt.Errorf("stream provider unreachable, %v messages buffered", count)
+	}
+	if checker, ok := p.provider.(interfaces.HealthChecker); ok {
+		return checker.Healthy(ctx)
+	}
+	return nil
+}
+
+// Close stops draining and closes the provider and the buffer, messages still buffered are delivered by the next
+// session. Closing more than once returns the result of the first Close.
+func (p *bufferedPublisher) Close() error {
+	p.once.Do(func() {
+		p.err = p.close()
+	})
+	return p.err
+}
+
+func (p *bufferedPublisher) close() error {
+	close(p.done)
+	p.cancel()
+	p.wg.Wait()
+
+	p.mu.Lock()
+	for key, confirm := range p.confirms {
+		confirm(errors.New("stream buffer closed before the message was delivered"))
+		delete(p.confirms, key)
+	}
+	p.mu.Unlock()
+
+	err := p.provider.Close()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.db != nil {
+		if dbErr := p.db.Close(); err == nil {
+			err = dbErr
+		}
+		p.db = nil
+	}
+	return err
+}
+
+// run periodically attempts to deliver buffered messages until the publisher is closed
+func (p *bufferedPublisher) run() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.drain()
+		}
+	}
+}
+
+// drain delivers buffered messages oldest first, stopping at the first failure so ordering is preserved. p.mu is
+// released while the provider is called, so publishes are queued rather than blocked while it is slow.
+func (p *bufferedPublisher) drain() {
+	p.mu.Lock()
+	count, connected := p.count, p.connected
+	p.mu.Unlock()
+
+	if count == 0 {
+		return
+	}
+	if !connected {
+		if err := p.provider.Connect(p.ctx); err != nil {
+			p.logger.Write(slog.LevelDebug, fmt.Sprintf("stream provider still unreachable %s", err.Error()))
+			return
+		}
+		p.mu.Lock()
+		p.connected = true
+		p.mu.Unlock()
+	}
+
+	delivered := 0
+	defer func() {
+		if delivered > 0 {
+			p.mu.Lock()
+			count := p.count
+			p.mu.Unlock()
+			p.logger.Write(slog.LevelInfo, fmt.Sprintf("delivered %v buffered messages, %v remaining", delivered, count))
+		}
+	}()
+	for {
+		select {
+		case <-p.done:
+			return
+		default:
+		}
+
+		p.mu.Lock()
+		if p.count == 0 {
+			p.mu.Unlock()
+			return
+		}
+		key, msg, err := p.peek()
+		if errors.Is(err, errMalformed) {
+			// A message that cannot be read back would otherwise block every message queued behind it
+			p.logger.Error(fmt.Sprintf("%s, message discarded", err.Error()))
+			if confirm, ok := p.confirms[string(key)]; ok {
+				delete(p.confirms, string(key))
+				confirm(err)
+			}
+			err = p.delete(key)
+		}
+		if err != nil {
+			p.mu.Unlock()
+			p.logger.Error(err.Error())
+			return
+		}
+		if msg == nil {
+			p.mu.Unlock()
+			continue
+		}
+		p.inflight = key
+		confirm := p.confirms[string(key)]
+		p.mu.Unlock()
+
+		ctx, d := message.WithDelivery(p.ctx, func(err error) {
+			if confirm != nil {
+				confirm(err)
+			}
+		})
+		err = p.provider.Publish(ctx, *msg)
+
+		p.mu.Lock()
+		p.inflight = nil
+		if err != nil {
+			p.mu.Unlock()
+			p.logger.Write(slog.LevelDebug, fmt.Sprintf("buffered publish failed %s", err.Error()))
+			return
+		}
+		delete(p.confirms, string(key))
+		d.Sent(nil)
+		err = p.delete(key)
+		p.mu.Unlock()
+		if err != nil {
+			p.logger.Error(err.Error())
+			return
+		}
+		delivered++
+	}
+}
+
+// enqueue appends a message to the buffer, applying the drop policy when it is full. Confirm is called once the
+// message is delivered or discarded. The message being published by drain is never dropped. Callers must hold p.mu.
+func (p *bufferedPublisher) enqueue(msg message.PublishWrapper, confirm func(err error)) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	dropped := false
+	var droppedKey, key []byte
+	err = p.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketName)
+		if p.cfg.Capacity > 0 && p.count >= p.cfg.Capacity {
+			if p.cfg.DropPolicy == contracts.DropNewest {
+				return errors.New("stream buffer full, message discarded")
+			}
+			c := bucket.Cursor()
+			k, _ := c.First()
+			if p.inflight != nil && bytes.Equal(k, p.inflight) {
+				k, _ = c.Next()
+			}
+			if k == nil {
+				return errors.New("stream buffer full, message discarded")
+			}
+			droppedKey = append([]byte{}, k...)
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+			dropped = true
+		}
+
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		key = make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		return bucket.Put(key, b)
+	})
+	if err != nil {
+		return err
+	}
+
+	p.confirms[string(key)] = confirm
+	if dropped {
+		p.logger.Write(slog.LevelWarn, "stream buffer full, oldest message discarded")
+		if discarded, ok := p.confirms[string(droppedKey)]; ok {
+			delete(p.confirms, string(droppedKey))
+			discarded(errors.New("stream buffer full, message discarded"))
+		}
+	} else {
+		p.count++
+	}
+	return nil
+}
+
+// peek returns the oldest buffered message, along with an error wrapping errMalformed and the key of the message
+// when it cannot be unmarshaled. Callers must hold p.mu.
+func (p *bufferedPublisher) peek() ([]byte, *message.PublishWrapper, error) {
+	var key []byte
+	var msg message.PublishWrapper
+	err := p.db.View(func(tx *bolt.Tx) error {
+		k, v := tx.Bucket(bucketName).Cursor().First()
+		if k == nil {
+			return errors.New("stream buffer is empty")
+		}
+		key = append([]byte{}, k...)
+		if err := json.Unmarshal(v, &msg); err != nil {
+			return fmt.Errorf("%w %x: %s", errMalformed, key, err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return key, nil, err
+	}
+	return key, &msg, nil
+}
+
+// delete removes a message from the buffer, one dropped while it was being published is not counted twice. Callers
+// must hold p.mu.
+func (p *bufferedPublisher) delete(key []byte) error {
+	return p.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketName)
+		if bucket.Get(key) == nil {
+			return nil
+		}
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+		p.count--
+		return nil
+	})
+}
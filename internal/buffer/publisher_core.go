@@ -0,0 +1,31 @@
+//go:build alvarium_core || js || wasip1 || tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package buffer
+
+import (
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// NewBufferedPublisher fails in the core build, which leaves out the embedded database holding the buffer
+func NewBufferedPublisher(cfg config.BufferInfo, provider interfaces.StreamProvider,
+	logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	return nil, fmt.Errorf("%w: the on-disk buffer is not available in the core build", contracts.ErrConfigInvalid)
+}
@@ -0,0 +1,391 @@
+//go:build !alvarium_core && !js && !wasip1 && !tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package buffer
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
+)
+
+// flakyProvider records published messages and fails every call while offline
+type flakyProvider struct {
+	mu        sync.Mutex
+	offline   bool
+	published []string
+}
+
+func (f *flakyProvider) Connect(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.offline {
+		return errors.New("offline")
+	}
+	return nil
+}
+
+func (f *flakyProvider) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.offline {
+		return errors.New("offline")
+	}
+	f.published = append(f.published, string(msg.Content))
+	return nil
+}
+
+func (f *flakyProvider) Close() error {
+	return nil
+}
+
+func (f *flakyProvider) setOffline(offline bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.offline = offline
+}
+
+func (f *flakyProvider) received() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.published...)
+}
+
+// blockingProvider holds every publish until it is released
+type blockingProvider struct {
+	flakyProvider
+	release chan struct{}
+}
+
+func (b *blockingProvider) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	<-b.release
+	return b.flakyProvider.Publish(ctx, msg)
+}
+
+func TestNewBufferedPublisher(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	tests := []struct {
+		name        string
+		cfg         config.BufferInfo
+		expectError bool
+	}{
+		{"valid default policy", config.BufferInfo{Path: "buffer.db"}, false},
+		{"valid newest policy", config.BufferInfo{Path: "buffer.db", Capacity: 10, DropPolicy: contracts.DropNewest}, false},
+		{"missing path", config.BufferInfo{}, true},
+		{"invalid capacity", config.BufferInfo{Path: "buffer.db", Capacity: -1}, true},
+		{"invalid policy", config.BufferInfo{Path: "buffer.db", DropPolicy: "random"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBufferedPublisher(tt.cfg, &flakyProvider{}, logger)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestBufferedPublisher_Drain(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+
+	tests := []struct {
+		name        string
+		capacity    int
+		policy      contracts.DropPolicy
+		expected    []string
+		expectError bool
+	}{
+		{"unbounded", 0, contracts.DropOldest, []string{"0", "1", "2", "3", "4"}, false},
+		{"drop oldest", 3, contracts.DropOldest, []string{"2", "3", "4"}, false},
+		{"drop newest", 3, contracts.DropNewest, []string{"0", "1", "2"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &flakyProvider{offline: true}
+			cfg := config.BufferInfo{
+				Path:       filepath.Join(t.TempDir(), "buffer.db"),
+				Capacity:   tt.capacity,
+				DropPolicy: tt.policy,
+			}
+			p, err := NewBufferedPublisher(cfg, provider, logger)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			p.(*bufferedPublisher).interval = 10 * time.Millisecond
+			if err = p.Connect(context.Background()); err != nil {
+				t.Fatalf(err.Error())
+			}
+			defer p.Close()
+
+			var publishErr error
+			for i := 0; i < 5; i++ {
+				err = p.Publish(context.Background(),
+					message.PublishWrapper{Action: message.ActionCreate, Content: []byte(strconv.Itoa(i))})
+				if err != nil {
+					publishErr = err
+				}
+			}
+			test.CheckError(publishErr, tt.expectError, tt.name, t)
+			assert.Empty(t, provider.received())
+			assert.Error(t, p.(interfaces.HealthChecker).Healthy(context.Background()))
+
+			provider.setOffline(false)
+			deadline := time.Now().Add(5 * time.Second)
+			for len(provider.received()) < len(tt.expected) && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			assert.Equal(t, tt.expected, provider.received())
+
+			// Once drained, publishes go straight to the provider
+			if err = p.Publish(context.Background(),
+				message.PublishWrapper{Action: message.ActionCreate, Content: []byte("5")}); err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Equal(t, append(tt.expected, "5"), provider.received())
+			assert.NoError(t, p.(interfaces.HealthChecker).Healthy(context.Background()))
+		})
+	}
+}
+
+func TestBufferedPublisher_Persistence(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	cfg := config.BufferInfo{Path: filepath.Join(t.TempDir(), "buffer.db")}
+
+	offline := &flakyProvider{offline: true}
+	p, err := NewBufferedPublisher(cfg, offline, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err = p.Publish(context.Background(),
+		message.PublishWrapper{Action: message.ActionCreate, Content: []byte("0")}); err != nil {
+		t.Fatalf(err.Error())
+	}
+	p.Close()
+
+	// Messages buffered by a previous session are delivered ahead of new ones
+	online := &flakyProvider{}
+	p, err = NewBufferedPublisher(cfg, online, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	p.(*bufferedPublisher).interval = 10 * time.Millisecond
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer p.Close()
+	if err = p.Publish(context.Background(),
+		message.PublishWrapper{Action: message.ActionCreate, Content: []byte("1")}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(online.received()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, []string{"0", "1"}, online.received())
+}
+
+func TestBufferedPublisher_Delivery(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	provider := &flakyProvider{offline: true}
+	cfg := config.BufferInfo{Path: filepath.Join(t.TempDir(), "buffer.db"), Capacity: 2}
+	p, err := NewBufferedPublisher(cfg, provider, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	p.(*bufferedPublisher).interval = 10 * time.Millisecond
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var mu sync.Mutex
+	confirmed := make(map[string]error)
+	publish := func(content string) {
+		ctx, d := message.WithDelivery(context.Background(), func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			confirmed[content] = err
+		})
+		err := p.Publish(ctx, message.PublishWrapper{Action: message.ActionCreate, Content: []byte(content)})
+		d.Sent(err)
+	}
+	delivered := func() map[string]error {
+		mu.Lock()
+		defer mu.Unlock()
+		return maps.Clone(confirmed)
+	}
+
+	// Buffered messages are confirmed once drained, the oldest one is lost when the buffer overflows
+	for i := 0; i < 3; i++ {
+		publish(strconv.Itoa(i))
+	}
+	assert.Len(t, delivered(), 1)
+	assert.Error(t, delivered()["0"])
+
+	provider.setOffline(false)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(delivered()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.NoError(t, delivered()["1"])
+	assert.NoError(t, delivered()["2"])
+
+	// Messages still buffered on Close are lost
+	provider.setOffline(true)
+	publish("3")
+	p.Close()
+	assert.Error(t, delivered()["3"])
+}
+
+func TestBufferedPublisher_Lifecycle(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	cfg := config.BufferInfo{Path: filepath.Join(t.TempDir(), "buffer.db")}
+	p, err := NewBufferedPublisher(cfg, &flakyProvider{}, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	checker := p.(interfaces.HealthChecker)
+	msg := message.PublishWrapper{Action: message.ActionCreate, Content: []byte("0")}
+	assert.ErrorIs(t, p.Publish(context.Background(), msg), errNotOpen)
+	assert.ErrorIs(t, checker.Healthy(context.Background()), errNotOpen)
+
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.NoError(t, checker.Healthy(context.Background()))
+
+	assert.NoError(t, p.Close())
+	assert.NoError(t, p.Close())
+	assert.ErrorIs(t, p.Publish(context.Background(), msg), errNotOpen)
+	assert.ErrorIs(t, checker.Healthy(context.Background()), errNotOpen)
+}
+
+func TestBufferedPublisher_Malformed(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	provider := &flakyProvider{offline: true}
+	cfg := config.BufferInfo{Path: filepath.Join(t.TempDir(), "buffer.db")}
+	p, err := NewBufferedPublisher(cfg, provider, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	p.(*bufferedPublisher).interval = 10 * time.Millisecond
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer p.Close()
+
+	// A stored message that can no longer be unmarshaled is discarded instead of blocking those behind it
+	buffered := p.(*bufferedPublisher)
+	buffered.mu.Lock()
+	err = buffered.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketName)
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		return bucket.Put(key, []byte("{"))
+	})
+	buffered.count++
+	buffered.mu.Unlock()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err = p.Publish(context.Background(),
+		message.PublishWrapper{Action: message.ActionCreate, Content: []byte("0")}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	provider.setOffline(false)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(provider.received()) < 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, []string{"0"}, provider.received())
+	buffered.mu.Lock()
+	defer buffered.mu.Unlock()
+	assert.Equal(t, 0, buffered.count)
+}
+
+func TestBufferedPublisher_SlowProvider(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	provider := &blockingProvider{flakyProvider: flakyProvider{offline: true}, release: make(chan struct{})}
+	cfg := config.BufferInfo{Path: filepath.Join(t.TempDir(), "buffer.db")}
+	p, err := NewBufferedPublisher(cfg, provider, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	p.(*bufferedPublisher).interval = 10 * time.Millisecond
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer p.Close()
+
+	if err = p.Publish(context.Background(),
+		message.PublishWrapper{Action: message.ActionCreate, Content: []byte("0")}); err != nil {
+		t.Fatalf(err.Error())
+	}
+	buffered := p.(*bufferedPublisher)
+	inflight := func() bool {
+		buffered.mu.Lock()
+		defer buffered.mu.Unlock()
+		return buffered.inflight != nil
+	}
+	provider.setOffline(false)
+	deadline := time.Now().Add(5 * time.Second)
+	for !inflight() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Publishes are queued while drain waits on the provider
+	published := make(chan error)
+	go func() {
+		published <- p.Publish(context.Background(),
+			message.PublishWrapper{Action: message.ActionCreate, Content: []byte("1")})
+	}()
+	select {
+	case err = <-published:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("publish blocked by a pending buffered publish")
+	}
+
+	close(provider.release)
+	deadline = time.Now().Add(5 * time.Second)
+	for len(provider.received()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, []string{"0", "1"}, provider.received())
+}
@@ -0,0 +1,127 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// codec compresses content with a single encoding
+type codec interface {
+	compress(content []byte) ([]byte, error)
+}
+
+func newCodec(encoding contracts.ContentEncoding, level int) (codec, error) {
+	switch encoding {
+	case contracts.GzipEncoding:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+			return nil, fmt.Errorf("invalid gzip level %v", level)
+		}
+		return gzipCodec{level: level}, nil
+	case contracts.ZstdEncoding:
+		opts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+		if level != 0 {
+			if level < 1 || level > 22 {
+				return nil, fmt.Errorf("invalid zstd level %v", level)
+			}
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		encoder, err := zstd.NewWriter(nil, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return zstdCodec{encoder: encoder}, nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %s", encoding)
+	}
+}
+
+type gzipCodec struct {
+	level int
+}
+
+func (c gzipCodec) compress(content []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, c.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(content); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type zstdCodec struct {
+	encoder *zstd.Encoder
+}
+
+func (c zstdCodec) compress(content []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(content, nil), nil
+}
+
+// MaxDecompressedSize bounds the size Decompress expands content to, so that a small message cannot exhaust memory
+const MaxDecompressedSize = 256 << 20
+
+// Decompress reverses the named content encoding. Content without an encoding is returned unchanged. Content
+// expanding beyond MaxDecompressedSize is rejected.
+func Decompress(encoding string, content []byte) ([]byte, error) {
+	return DecompressLimit(encoding, content, MaxDecompressedSize)
+}
+
+// DecompressLimit is Decompress rejecting content that expands beyond limit bytes
+func DecompressLimit(encoding string, content []byte, limit int64) ([]byte, error) {
+	var r io.Reader
+	switch contracts.ContentEncoding(encoding) {
+	case "":
+		return content, nil
+	case contracts.GzipEncoding:
+		gz, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	case contracts.ZstdEncoding:
+		d, err := zstd.NewReader(bytes.NewReader(content), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer d.Close()
+		r = d
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %s", encoding)
+	}
+	decompressed, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(decompressed)) > limit {
+		return nil, fmt.Errorf("%s content expands beyond %d bytes", encoding, limit)
+	}
+	return decompressed, nil
+}
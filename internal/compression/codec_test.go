@@ -0,0 +1,57 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package compression
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecompressLimit(t *testing.T) {
+	content := bytes.Repeat([]byte("alvarium"), 128)
+	for _, encoding := range []contracts.ContentEncoding{contracts.GzipEncoding, contracts.ZstdEncoding} {
+		c, err := newCodec(encoding, 0)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		compressed, err := c.compress(content)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		tests := []struct {
+			name        string
+			limit       int64
+			expectError bool
+		}{
+			{"within the limit", int64(len(content)) + 1, false},
+			{"at the limit", int64(len(content)), false},
+			{"beyond the limit", int64(len(content)) - 1, true},
+		}
+		for _, tt := range tests {
+			t.Run(string(encoding)+" "+tt.name, func(t *testing.T) {
+				decompressed, err := DecompressLimit(string(encoding), compressed, tt.limit)
+				test.CheckError(err, tt.expectError, tt.name, t)
+				if err == nil {
+					assert.Equal(t, content, decompressed)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package compression
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+// compressingPublisher wraps a stream provider, compressing wrapper content and recording the encoding in the
+// wrapper's ContentEncoding so that subscribers know how to reverse it
+type compressingPublisher struct {
+	cfg      config.CompressionInfo
+	provider interfaces.StreamProvider
+	logger   interfaces.Logger
+	codec    codec
+}
+
+// NewCompressingPublisher wraps provider, compressing content with the configured encoding
+func NewCompressingPublisher(cfg config.CompressionInfo, provider interfaces.StreamProvider,
+	logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	if cfg.MinBytes < 0 {
+		return nil, fmt.Errorf("invalid compression min bytes %v", cfg.MinBytes)
+	}
+	c, err := newCodec(cfg.Encoding, cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	p := compressingPublisher{
+		cfg:      cfg,
+		provider: provider,
+		logger:   logger,
+		codec:    c,
+	}
+	return &p, nil
+}
+
+func (p *compressingPublisher) Connect(ctx context.Context) error {
+	return p.provider.Connect(ctx)
+}
+
+func (p *compressingPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	// Content that is already encoded or too small to benefit is passed through untouched
+	if msg.ContentEncoding != "" || len(msg.Content) < p.cfg.MinBytes {
+		return p.provider.Publish(ctx, msg)
+	}
+
+	compressed, err := p.codec.compress(msg.Content)
+	if err != nil {
+		return err
+	}
+	p.logger.Write(slog.LevelDebug, fmt.Sprintf("compressed content with %s from %v to %v bytes", p.cfg.Encoding,
+		len(msg.Content), len(compressed)))
+
+	msg.Content = compressed
+	msg.ContentEncoding = string(p.cfg.Encoding)
+	return p.provider.Publish(ctx, msg)
+}
+
+// Healthy reports the health of the wrapped provider, compressing content has no state of its own that can fail
+func (p *compressingPublisher) Healthy(ctx context.Context) error {
+	if checker, ok := p.provider.(interfaces.HealthChecker); ok {
+		return checker.Healthy(ctx)
+	}
+	return nil
+}
+
+func (p *compressingPublisher) Close() error {
+	return p.provider.Close()
+}
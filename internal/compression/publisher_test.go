@@ -0,0 +1,116 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package compression
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingProvider keeps the wrappers it is asked to publish
+type recordingProvider struct {
+	published []message.PublishWrapper
+}
+
+func (r *recordingProvider) Connect(ctx context.Context) error {
+	return nil
+}
+
+func (r *recordingProvider) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	r.published = append(r.published, msg)
+	return nil
+}
+
+func (r *recordingProvider) Close() error {
+	return nil
+}
+
+func TestNewCompressingPublisher(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	tests := []struct {
+		name        string
+		cfg         config.CompressionInfo
+		expectError bool
+	}{
+		{"gzip default level", config.CompressionInfo{Encoding: contracts.GzipEncoding}, false},
+		{"zstd with level", config.CompressionInfo{Encoding: contracts.ZstdEncoding, Level: 19}, false},
+		{"invalid gzip level", config.CompressionInfo{Encoding: contracts.GzipEncoding, Level: 12}, true},
+		{"unsupported encoding", config.CompressionInfo{Encoding: "brotli"}, true},
+		{"negative min bytes", config.CompressionInfo{Encoding: contracts.GzipEncoding, MinBytes: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCompressingPublisher(tt.cfg, &recordingProvider{}, logger)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestCompressingPublisher_Publish(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	content := bytes.Repeat([]byte(`{"key":"value","isSatisfied":true}`), 100)
+
+	tests := []struct {
+		name             string
+		cfg              config.CompressionInfo
+		msg              message.PublishWrapper
+		expectedEncoding string
+	}{
+		{"gzip", config.CompressionInfo{Encoding: contracts.GzipEncoding},
+			message.PublishWrapper{Action: message.ActionCreate, Content: content}, "gzip"},
+		{"zstd", config.CompressionInfo{Encoding: contracts.ZstdEncoding},
+			message.PublishWrapper{Action: message.ActionCreate, Content: content}, "zstd"},
+		{"below min bytes", config.CompressionInfo{Encoding: contracts.ZstdEncoding, MinBytes: 8192},
+			message.PublishWrapper{Action: message.ActionCreate, Content: content}, ""},
+		{"already encoded", config.CompressionInfo{Encoding: contracts.ZstdEncoding},
+			message.PublishWrapper{Action: message.ActionCreate, Content: content, ContentEncoding: "identity"}, "identity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &recordingProvider{}
+			p, err := NewCompressingPublisher(tt.cfg, provider, logger)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			if err = p.Publish(context.Background(), tt.msg); err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			assert.Len(t, provider.published, 1)
+			published := provider.published[0]
+			assert.Equal(t, tt.msg.Action, published.Action)
+			assert.Equal(t, tt.expectedEncoding, published.ContentEncoding)
+			if published.ContentEncoding != tt.msg.ContentEncoding {
+				assert.Less(t, len(published.Content), len(content))
+				result, err := Decompress(published.ContentEncoding, published.Content)
+				if err != nil {
+					t.Fatalf(err.Error())
+				}
+				assert.Equal(t, content, result)
+			} else {
+				assert.Equal(t, content, published.Content)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package console
 
 import (
+	"context"
 	"fmt"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
@@ -16,11 +17,11 @@ func NewConsolePublisher(logger interfaces.Logger) interfaces.StreamProvider {
 	}
 }
 
-func (p *consolePublisher) Connect() error {
+func (p *consolePublisher) Connect(ctx context.Context) error {
 	return nil
 }
 
-func (p *consolePublisher) Publish(msg message.PublishWrapper) error {
+func (p *consolePublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
 	fmt.Printf("action: %s, messageType: %s, %v\n", msg.Action, msg.MessageType, string(msg.Content))
 	return nil
 }
@@ -0,0 +1,174 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package cose
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	gocose "github.com/veraison/go-cose"
+)
+
+// p256Size is the length in bytes of a P-256 scalar
+const p256Size = 32
+
+// Algorithm returns the COSE algorithm matching signatures made with the given key type. Only key types whose
+// providers sign the content with Ed25519, or with ECDSA P-256 over its SHA-256 digest, can be represented.
+func Algorithm(k contracts.KeyAlgorithm) (gocose.Algorithm, error) {
+	switch k {
+	case contracts.KeyEd25519:
+		return gocose.AlgorithmEd25519, nil
+	case contracts.KeyEcdsaP256, contracts.KeyPkcs11, contracts.KeyTpm, contracts.KeyAzureKeyVault, contracts.KeyGcpKms:
+		return gocose.AlgorithmES256, nil
+	}
+	return 0, fmt.Errorf("KeyAlgorithm %s cannot produce COSE signatures", k)
+}
+
+// IsSign1 reports whether a signature is a base64url encoded, tagged COSE_Sign1 message
+func IsSign1(signature string) bool {
+	b, err := base64.RawURLEncoding.DecodeString(signature)
+	// CBOR tag 18 followed by an array of four items
+	return err == nil && len(b) > 2 && b[0] == 0xd2 && b[1] == 0x84
+}
+
+// Sign produces a tagged COSE_Sign1 message (RFC 9052) over the payload using the signature provider, encoded as
+// base64url. The payload is detached to keep the message small on constrained transports, so verifiers supply it
+// again. The key's Id is carried in the unprotected kid header.
+func Sign(signer interfaces.SignatureProvider, key config.KeyInfo, payload []byte) (string, error) {
+	alg, err := Algorithm(key.Type)
+	if err != nil {
+		return "", err
+	}
+	msg := gocose.NewSign1Message()
+	msg.Headers.Protected.SetAlgorithm(alg)
+	if key.Id != "" {
+		msg.Headers.Unprotected[gocose.HeaderLabelKeyID] = []byte(key.Id)
+	}
+	msg.Payload = payload
+	if err = msg.Sign(rand.Reader, nil, &adapter{alg: alg, provider: signer, key: key}); err != nil {
+		return "", err
+	}
+
+	msg.Payload = nil
+	b, err := msg.MarshalCBOR()
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Verify checks a COSE_Sign1 message produced by Sign against the detached payload. The message algorithm must match
+// the key.
+func Verify(verifier interfaces.SignatureProvider, key config.KeyInfo, signature string, payload []byte) (bool, error) {
+	alg, err := Algorithm(key.Type)
+	if err != nil {
+		return false, err
+	}
+	b, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false, nil
+	}
+	var msg gocose.Sign1Message
+	if err = msg.UnmarshalCBOR(b); err != nil {
+		return false, nil
+	}
+	if msgAlg, err := msg.Headers.Protected.Algorithm(); err != nil || msgAlg != alg {
+		return false, nil
+	}
+
+	msg.Payload = payload
+	a := &adapter{alg: alg, provider: verifier, key: key}
+	if err = msg.Verify(nil, a); err != nil {
+		if errors.Is(err, gocose.ErrVerification) {
+			return false, a.err
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// adapter presents a signature provider as a go-cose Signer and Verifier, converting between the provider's hex
+// encoded signatures and the fixed length forms of RFC 8152 section 8
+type adapter struct {
+	alg      gocose.Algorithm
+	provider interfaces.SignatureProvider
+	key      config.KeyInfo
+	err      error // err is a provider failure hidden behind ErrVerification
+}
+
+func (a *adapter) Algorithm() gocose.Algorithm {
+	return a.alg
+}
+
+func (a *adapter) Sign(_ io.Reader, content []byte) ([]byte, error) {
+	signed, err := a.provider.Sign(a.key, content)
+	if err != nil {
+		return nil, err
+	}
+	b, err := hex.DecodeString(signed)
+	if err != nil {
+		return nil, err
+	}
+	if a.alg != gocose.AlgorithmES256 {
+		return b, nil
+	}
+
+	var sig struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(b, &sig); err != nil || len(rest) > 0 {
+		return nil, errors.New("malformed ECDSA signature from provider")
+	}
+	if sig.R.BitLen() > 8*p256Size || sig.S.BitLen() > 8*p256Size {
+		return nil, errors.New("ECDSA signature is not a P-256 signature")
+	}
+	raw := make([]byte, 2*p256Size)
+	sig.R.FillBytes(raw[:p256Size])
+	sig.S.FillBytes(raw[p256Size:])
+	return raw, nil
+}
+
+func (a *adapter) Verify(content, signature []byte) error {
+	sig := signature
+	if a.alg == gocose.AlgorithmES256 {
+		if len(signature) != 2*p256Size {
+			return gocose.ErrVerification
+		}
+		der, err := asn1.Marshal(struct{ R, S *big.Int }{
+			R: new(big.Int).SetBytes(signature[:p256Size]),
+			S: new(big.Int).SetBytes(signature[p256Size:]),
+		})
+		if err != nil {
+			return err
+		}
+		sig = der
+	}
+
+	ok, err := a.provider.Verify(a.key, content, []byte(hex.EncodeToString(sig)))
+	if err != nil {
+		a.err = err
+		return gocose.ErrVerification
+	}
+	if !ok {
+		return gocose.ErrVerification
+	}
+	return nil
+}
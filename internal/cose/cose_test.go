@@ -0,0 +1,146 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package cose
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"testing"
+
+	ecdsaprovider "github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ecdsa"
+	ed25519provider "github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/secp256k1"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+	gocose "github.com/veraison/go-cose"
+)
+
+const keyPath = "../../test/keys/"
+
+func readPem(t *testing.T, path string) []byte {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	block, _ := pem.Decode(b)
+	return block.Bytes
+}
+
+func TestSign(t *testing.T) {
+	edPublic, _ := os.ReadFile(keyPath + "ed25519/public.key")
+	edKey, _ := hex.DecodeString(string(edPublic))
+	ecKey, _ := x509.ParsePKIXPublicKey(readPem(t, keyPath+"ecdsa-p256/public.pem"))
+
+	tests := []struct {
+		name        string
+		signer      interfaces.SignatureProvider
+		private     config.KeyInfo
+		public      config.KeyInfo
+		alg         gocose.Algorithm
+		coseKey     crypto.PublicKey
+		expectError bool
+	}{
+		{"ed25519", ed25519provider.New(),
+			config.KeyInfo{Type: contracts.KeyEd25519, Path: keyPath + "ed25519/private.key", Id: "2024-06"},
+			config.KeyInfo{Type: contracts.KeyEd25519, Path: keyPath + "ed25519/public.key"},
+			gocose.AlgorithmEd25519, ed25519.PublicKey(edKey), false},
+		{"ecdsa-p256", ecdsaprovider.New(),
+			config.KeyInfo{Type: contracts.KeyEcdsaP256, Path: keyPath + "ecdsa-p256/private.pem", Id: "2024-06"},
+			config.KeyInfo{Type: contracts.KeyEcdsaP256, Path: keyPath + "ecdsa-p256/public.pem"},
+			gocose.AlgorithmES256, ecKey, false},
+		{"secp256k1 not supported", secp256k1.New(),
+			config.KeyInfo{Type: contracts.KeyEcdsaSecp256k1, Path: keyPath + "secp256k1/private.key"},
+			config.KeyInfo{}, 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := []byte(`{"key":"dummyKey","hash":"sha256"}`)
+			signed, err := Sign(tt.signer, tt.private, payload)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+			assert.True(t, IsSign1(signed))
+
+			ok, err := Verify(tt.signer, tt.public, signed, payload)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = Verify(tt.signer, tt.public, signed, []byte(`{"key":"otherKey","hash":"sha256"}`))
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			// Standard COSE libraries verify the message given the detached payload
+			b, _ := base64.RawURLEncoding.DecodeString(signed)
+			var msg gocose.Sign1Message
+			if !assert.NoError(t, msg.UnmarshalCBOR(b)) {
+				return
+			}
+			assert.Nil(t, msg.Payload)
+			assert.Equal(t, []byte("2024-06"), msg.Headers.Unprotected[gocose.HeaderLabelKeyID])
+			verifier, err := gocose.NewVerifier(tt.alg, tt.coseKey)
+			if !assert.NoError(t, err) {
+				return
+			}
+			msg.Payload = payload
+			assert.NoError(t, msg.Verify(nil, verifier))
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ecPrivate, err := x509.ParsePKCS8PrivateKey(readPem(t, keyPath+"ecdsa-p256/private.pem"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	payload := []byte(`{"key":"dummyKey","hash":"sha256"}`)
+
+	// Messages produced by other COSE implementations are accepted
+	signer, _ := gocose.NewSigner(gocose.AlgorithmES256, ecPrivate.(crypto.Signer))
+	headers := gocose.Headers{Protected: gocose.ProtectedHeader{gocose.HeaderLabelAlgorithm: gocose.AlgorithmES256}}
+	b, _ := gocose.Sign1(rand.Reader, signer, headers, payload, nil)
+	foreign := base64.RawURLEncoding.EncodeToString(b)
+
+	ecPublic := config.KeyInfo{Type: contracts.KeyEcdsaP256, Path: keyPath + "ecdsa-p256/public.pem"}
+	edPublic := config.KeyInfo{Type: contracts.KeyEd25519, Path: keyPath + "ed25519/public.key"}
+	tests := []struct {
+		name      string
+		verifier  interfaces.SignatureProvider
+		key       config.KeyInfo
+		signature string
+		expected  bool
+	}{
+		{"foreign ES256 message", ecdsaprovider.New(), ecPublic, foreign, true},
+		{"algorithm mismatch", ed25519provider.New(), edPublic, foreign, false},
+		{"not a COSE message", ecdsaprovider.New(), ecPublic, "deadbeef", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := Verify(tt.verifier, tt.key, tt.signature, payload)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+	// Hex encoded provider signatures are never mistaken for COSE messages
+	assert.False(t, IsSign1("d2840000"))
+}
@@ -0,0 +1,77 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package dedup
+
+import (
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// identity is the part of an annotation compared to detect duplicates
+type identity struct {
+	key       string
+	kind      contracts.AnnotationType
+	layer     contracts.LayerType
+	satisfied bool
+}
+
+// Filter suppresses annotations identical to one admitted within the window. An annotation is admitted again once
+// the window has elapsed since its last admission, so unchanged criteria are still reported once per window.
+type Filter struct {
+	window time.Duration
+
+	mu     sync.Mutex
+	seen   map[identity]time.Time // seen holds when each annotation was last admitted
+	pruned time.Time              // pruned is when expired entries were last removed from seen
+}
+
+// NewFilter returns a Filter suppressing duplicates within window
+func NewFilter(window time.Duration) *Filter {
+	return &Filter{
+		window: window,
+		seen:   make(map[identity]time.Time),
+		pruned: time.Now(),
+	}
+}
+
+// Filter returns the annotations from items that are not duplicates of one admitted within the window, admitting
+// them in turn. Duplicates within items are suppressed as well.
+func (f *Filter) Filter(items []contracts.Annotation) []contracts.Annotation {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(f.pruned) >= f.window {
+		for id, admitted := range f.seen {
+			if now.Sub(admitted) >= f.window {
+				delete(f.seen, id)
+			}
+		}
+		f.pruned = now
+	}
+
+	admitted := make([]contracts.Annotation, 0, len(items))
+	for _, a := range items {
+		id := identity{key: a.Key, kind: a.Kind, layer: a.Layer, satisfied: a.IsSatisfied}
+		if last, ok := f.seen[id]; ok && now.Sub(last) < f.window {
+			continue
+		}
+		f.seen[id] = now
+		admitted = append(admitted, a)
+	}
+	return admitted
+}
@@ -0,0 +1,52 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package dedup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	annotation := func(key string, kind contracts.AnnotationType, satisfied bool) contracts.Annotation {
+		return contracts.Annotation{Key: key, Kind: kind, Layer: contracts.Host, IsSatisfied: satisfied}
+	}
+	tpm := annotation("a", contracts.AnnotationTPM, true)
+
+	f := NewFilter(50 * time.Millisecond)
+	tests := []struct {
+		name     string
+		items    []contracts.Annotation
+		expected int
+	}{
+		{"first seen", []contracts.Annotation{tpm}, 1},
+		{"duplicate", []contracts.Annotation{tpm}, 0},
+		{"duplicate within items", []contracts.Annotation{annotation("b", contracts.AnnotationTPM, true),
+			annotation("b", contracts.AnnotationTPM, true)}, 1},
+		{"different kind", []contracts.Annotation{annotation("a", contracts.AnnotationPKI, true)}, 1},
+		{"different outcome", []contracts.Annotation{annotation("a", contracts.AnnotationTPM, false)}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, f.Filter(tt.items), tt.expected)
+		})
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Len(t, f.Filter([]contracts.Annotation{tpm}), 1, "admitted again after the window")
+}
@@ -0,0 +1,210 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// EnvelopeEncoding is recorded as the ContentEncoding of wrappers whose content is an encrypted envelope
+	EnvelopeEncoding = "x25519-aes256gcm"
+
+	envelopeVersion = 1
+	keySize         = 32
+	kdfInfo         = "alvarium-envelope-v1"
+)
+
+// envelope carries content encrypted under a random content key, along with a copy of that key wrapped for each
+// recipient
+type envelope struct {
+	Version    int       `json:"version"`
+	Recipients []keySlot `json:"recipients"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+	// ContentEncoding is the encoding the content had before encryption, such as compression
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+}
+
+// keySlot holds the content key encrypted for one recipient with a key derived from an ephemeral X25519 exchange
+type keySlot struct {
+	Id           string `json:"id"`
+	EphemeralKey []byte `json:"ephemeralKey"`
+	Nonce        []byte `json:"nonce"`
+	EncryptedKey []byte `json:"encryptedKey"`
+}
+
+// recipient is a party that content is encrypted for
+type recipient struct {
+	id  string
+	key *ecdh.PublicKey
+}
+
+// seal encrypts the wrapper content for the recipients. The action, message type and content type stay readable so
+// that subscribers can route messages, and are bound to the ciphertext as additional data along with the encoding of
+// the content.
+func seal(msg message.PublishWrapper, recipients []recipient) (message.PublishWrapper, error) {
+	contentKey := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, contentKey); err != nil {
+		return message.PublishWrapper{}, err
+	}
+
+	env := envelope{Version: envelopeVersion, ContentEncoding: msg.ContentEncoding}
+	var err error
+	env.Nonce, env.Ciphertext, err = encrypt(contentKey, msg.Content, additionalData(msg))
+	if err != nil {
+		return message.PublishWrapper{}, err
+	}
+
+	for _, r := range recipients {
+		ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return message.PublishWrapper{}, err
+		}
+		shared, err := ephemeral.ECDH(r.key)
+		if err != nil {
+			return message.PublishWrapper{}, err
+		}
+		kek, err := deriveKey(shared, ephemeral.PublicKey().Bytes(), r.key.Bytes())
+		if err != nil {
+			return message.PublishWrapper{}, err
+		}
+		slot := keySlot{Id: r.id, EphemeralKey: ephemeral.PublicKey().Bytes()}
+		slot.Nonce, slot.EncryptedKey, err = encrypt(kek, contentKey, []byte(r.id))
+		if err != nil {
+			return message.PublishWrapper{}, err
+		}
+		env.Recipients = append(env.Recipients, slot)
+	}
+
+	b, err := json.Marshal(env)
+	if err != nil {
+		return message.PublishWrapper{}, err
+	}
+	msg.Content = b
+	msg.ContentEncoding = EnvelopeEncoding
+	return msg, nil
+}
+
+// Open decrypts a wrapper sealed for the recipient with the given id and private key, restoring the content and the
+// encoding it had before encryption
+func Open(msg message.PublishWrapper, id string, key *ecdh.PrivateKey) (message.PublishWrapper, error) {
+	if msg.ContentEncoding != EnvelopeEncoding {
+		return message.PublishWrapper{}, fmt.Errorf("unexpected content encoding %s", msg.ContentEncoding)
+	}
+	var env envelope
+	if err := json.Unmarshal(msg.Content, &env); err != nil {
+		return message.PublishWrapper{}, err
+	}
+	if env.Version != envelopeVersion {
+		return message.PublishWrapper{}, fmt.Errorf("unsupported envelope version %v", env.Version)
+	}
+
+	for _, slot := range env.Recipients {
+		if slot.Id != id {
+			continue
+		}
+		ephemeral, err := ecdh.X25519().NewPublicKey(slot.EphemeralKey)
+		if err != nil {
+			return message.PublishWrapper{}, err
+		}
+		shared, err := key.ECDH(ephemeral)
+		if err != nil {
+			return message.PublishWrapper{}, err
+		}
+		kek, err := deriveKey(shared, slot.EphemeralKey, key.PublicKey().Bytes())
+		if err != nil {
+			return message.PublishWrapper{}, err
+		}
+		contentKey, err := decrypt(kek, slot.Nonce, slot.EncryptedKey, []byte(slot.Id))
+		if err != nil {
+			return message.PublishWrapper{}, err
+		}
+		msg.ContentEncoding = env.ContentEncoding
+		content, err := decrypt(contentKey, env.Nonce, env.Ciphertext, additionalData(msg))
+		if err != nil {
+			return message.PublishWrapper{}, err
+		}
+		msg.Content = content
+		return msg, nil
+	}
+	return message.PublishWrapper{}, fmt.Errorf("no key slot for recipient %s", id)
+}
+
+// Fingerprint identifies a public key when a recipient is not given an explicit id
+func Fingerprint(key *ecdh.PublicKey) string {
+	sum := sha256.Sum256(key.Bytes())
+	return hex.EncodeToString(sum[:8])
+}
+
+// deriveKey computes the key encrypting the content key for a recipient from an X25519 shared secret. The salt binds
+// the derived key to both the ephemeral and the recipient's public key.
+func deriveKey(shared, ephemeralKey, recipientKey []byte) ([]byte, error) {
+	salt := append(append([]byte{}, ephemeralKey...), recipientKey...)
+	kek := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(kdfInfo)), kek); err != nil {
+		return nil, err
+	}
+	return kek, nil
+}
+
+func encrypt(key, plaintext, additional []byte) ([]byte, []byte, error) {
+	aead, err := newAead(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, err
+	}
+	return nonce, aead.Seal(nil, nonce, plaintext, additional), nil
+}
+
+func decrypt(key, nonce, ciphertext, additional []byte) ([]byte, error) {
+	aead, err := newAead(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce length")
+	}
+	return aead.Open(nil, nonce, ciphertext, additional)
+}
+
+func newAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// additionalData binds the fields of the wrapper telling subscribers how to route and decode the content, including
+// its encoding before encryption, which the envelope carries in the clear
+func additionalData(msg message.PublishWrapper) []byte {
+	return []byte(string(msg.Action) + "\n" + msg.MessageType + "\n" + msg.ContentType + "\n" + msg.ContentEncoding)
+}
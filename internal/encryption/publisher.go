@@ -0,0 +1,105 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package encryption
+
+import (
+	"context"
+	"crypto/ecdh"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+// encryptingPublisher wraps a stream provider, replacing wrapper content with an envelope only the configured
+// recipients can open
+type encryptingPublisher struct {
+	provider   interfaces.StreamProvider
+	logger     interfaces.Logger
+	recipients []recipient
+}
+
+// NewEncryptingPublisher wraps provider, encrypting content for the configured recipients
+func NewEncryptingPublisher(cfg config.EncryptionInfo, provider interfaces.StreamProvider,
+	logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	if len(cfg.Recipients) == 0 {
+		return nil, errors.New("at least one encryption recipient is required")
+	}
+
+	p := encryptingPublisher{
+		provider: provider,
+		logger:   logger,
+	}
+	seen := map[string]bool{}
+	for _, r := range cfg.Recipients {
+		key, err := readPublicKey(r.PublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		id := r.Id
+		if id == "" {
+			id = Fingerprint(key)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate encryption recipient %s", id)
+		}
+		seen[id] = true
+		p.recipients = append(p.recipients, recipient{id: id, key: key})
+	}
+	return &p, nil
+}
+
+func (p *encryptingPublisher) Connect(ctx context.Context) error {
+	return p.provider.Connect(ctx)
+}
+
+func (p *encryptingPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	sealed, err := seal(msg, p.recipients)
+	if err != nil {
+		return err
+	}
+	return p.provider.Publish(ctx, sealed)
+}
+
+// Healthy reports the health of the wrapped provider. Recipient keys are loaded by NewEncryptingPublisher, so they
+// cannot become unavailable afterwards.
+func (p *encryptingPublisher) Healthy(ctx context.Context) error {
+	if checker, ok := p.provider.(interfaces.HealthChecker); ok {
+		return checker.Healthy(ctx)
+	}
+	return nil
+}
+
+func (p *encryptingPublisher) Close() error {
+	return p.provider.Close()
+}
+
+// readPublicKey loads a hex encoded X25519 public key
+func readPublicKey(path string) (*ecdh.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key %s %w", path, err)
+	}
+	return ecdh.X25519().NewPublicKey(raw)
+}
@@ -0,0 +1,159 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingProvider keeps the wrappers it is asked to publish
+type recordingProvider struct {
+	published []message.PublishWrapper
+}
+
+func (r *recordingProvider) Connect(ctx context.Context) error {
+	return nil
+}
+
+func (r *recordingProvider) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	r.published = append(r.published, msg)
+	return nil
+}
+
+func (r *recordingProvider) Close() error {
+	return nil
+}
+
+func TestNewEncryptingPublisher(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	dir := t.TempDir()
+	_, path := writeKey(t, dir, "recipient")
+	invalidPath := filepath.Join(dir, "invalid.pub")
+	if err := os.WriteFile(invalidPath, []byte("not a key"), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		cfg         config.EncryptionInfo
+		expectError bool
+	}{
+		{"valid recipient", config.EncryptionInfo{Recipients: []config.RecipientInfo{{PublicKeyPath: path}}}, false},
+		{"no recipients", config.EncryptionInfo{}, true},
+		{"missing key", config.EncryptionInfo{Recipients: []config.RecipientInfo{{PublicKeyPath: "./missing.pub"}}}, true},
+		{"invalid key", config.EncryptionInfo{Recipients: []config.RecipientInfo{{PublicKeyPath: invalidPath}}}, true},
+		{"duplicate recipient", config.EncryptionInfo{Recipients: []config.RecipientInfo{{PublicKeyPath: path},
+			{PublicKeyPath: path}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEncryptingPublisher(tt.cfg, &recordingProvider{}, logger)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestEncryptingPublisher_Publish(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	dir := t.TempDir()
+	gateway, gatewayPath := writeKey(t, dir, "gateway")
+	auditor, auditorPath := writeKey(t, dir, "auditor")
+	outsider, _ := writeKey(t, dir, "outsider")
+
+	provider := &recordingProvider{}
+	cfg := config.EncryptionInfo{Recipients: []config.RecipientInfo{
+		{Id: "gateway", PublicKeyPath: gatewayPath},
+		{PublicKeyPath: auditorPath},
+	}}
+	p, err := NewEncryptingPublisher(cfg, provider, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	msg := message.PublishWrapper{Action: message.ActionCreate, MessageType: "contracts.AnnotationList",
+		Content: []byte(`{"items":[]}`), ContentType: string(contracts.ContentTypeJSON), ContentEncoding: "gzip"}
+	if err = p.Publish(context.Background(), msg); err != nil {
+		t.Fatalf(err.Error())
+	}
+	sealed := provider.published[0]
+	assert.Equal(t, EnvelopeEncoding, sealed.ContentEncoding)
+	assert.Equal(t, msg.Action, sealed.Action)
+	assert.NotContains(t, string(sealed.Content), "items")
+
+	tampered := func(change func(msg *message.PublishWrapper)) message.PublishWrapper {
+		msg := sealed
+		change(&msg)
+		return msg
+	}
+
+	tests := []struct {
+		name        string
+		msg         message.PublishWrapper
+		id          string
+		key         *ecdh.PrivateKey
+		expectError bool
+	}{
+		{"named recipient", sealed, "gateway", gateway, false},
+		{"fingerprint recipient", sealed, Fingerprint(auditor.PublicKey()), auditor, false},
+		{"unknown recipient", sealed, "outsider", outsider, true},
+		{"wrong key", sealed, "gateway", outsider, true},
+		{"tampered action", tampered(func(msg *message.PublishWrapper) { msg.Action = message.ActionMutate }),
+			"gateway", gateway, true},
+		{"tampered content type", tampered(func(msg *message.PublishWrapper) {
+			msg.ContentType = string(contracts.ContentTypeCBOR)
+		}), "gateway", gateway, true},
+		{"tampered content encoding", tampered(func(msg *message.PublishWrapper) {
+			msg.Content = bytes.Replace(msg.Content, []byte(`"contentEncoding":"gzip"`),
+				[]byte(`"contentEncoding":"zstd"`), 1)
+		}), "gateway", gateway, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Open(tt.msg, tt.id, tt.key)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, msg, result)
+			}
+		})
+	}
+}
+
+// writeKey generates an X25519 key pair, writing the hex encoded public key to a file whose path is returned
+func writeKey(t *testing.T, dir, name string) (*ecdh.PrivateKey, string) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	path := filepath.Join(dir, name+".pub")
+	if err = os.WriteFile(path, []byte(hex.EncodeToString(key.PublicKey().Bytes())+"\n"), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+	return key, path
+}
@@ -0,0 +1,300 @@
+//go:build !alvarium_core && !js && !wasip1 && !tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package ethereum
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+const requestTimeout = 10 * time.Second
+
+var gwei = big.NewFloat(1e9)
+
+type ethereumPublisher struct {
+	cfg      config.EthereumConfig
+	logger   interfaces.Logger
+	client   *ethclient.Client
+	key      *ecdsa.PrivateKey
+	from     common.Address
+	contract common.Address
+	signer   types.Signer
+
+	// nonce is managed locally so that consecutive publishes do not have to wait for the previous transaction to be
+	// mined. It is resynchronized from the node on connect and whenever the node rejects a transaction's nonce.
+	mu    sync.Mutex
+	nonce uint64
+}
+
+func NewEthereumPublisher(cfg config.EthereumConfig, logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	if !common.IsHexAddress(cfg.ContractAddress) {
+		return nil, fmt.Errorf("invalid contract address %s", cfg.ContractAddress)
+	}
+	if cfg.Method == "" {
+		return nil, errors.New("contract method not specified")
+	}
+	if cfg.AnchorMode == "" {
+		cfg.AnchorMode = contracts.AnchorHash
+	}
+	if !cfg.AnchorMode.Validate() {
+		return nil, fmt.Errorf("invalid AnchorMode value provided %s", cfg.AnchorMode)
+	}
+	if cfg.GasPrice.Strategy == "" {
+		cfg.GasPrice.Strategy = contracts.GasPriceNetwork
+	}
+	if !cfg.GasPrice.Strategy.Validate() {
+		return nil, fmt.Errorf("invalid GasPriceStrategy value provided %s", cfg.GasPrice.Strategy)
+	}
+
+	key, err := readPrivateKey(cfg.PrivateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	p := ethereumPublisher{
+		cfg:      cfg,
+		logger:   logger,
+		key:      key,
+		from:     crypto.PubkeyToAddress(key.PublicKey),
+		contract: common.HexToAddress(cfg.ContractAddress),
+		signer:   types.LatestSignerForChainID(big.NewInt(cfg.ChainId)),
+	}
+	return &p, nil
+}
+
+func (p *ethereumPublisher) Connect(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	client, err := ethclient.DialContext(ctx, p.cfg.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return err
+	}
+	if chainId.Int64() != p.cfg.ChainId {
+		client.Close()
+		return fmt.Errorf("endpoint chain ID %s does not match configured chain ID %v", chainId, p.cfg.ChainId)
+	}
+	p.client = client
+
+	return p.syncNonce(ctx)
+}
+
+func (p *ethereumPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	b, _ := message.Marshal(msg)
+	data, err := packCalldata(p.cfg.Method, p.cfg.AnchorMode, b)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.logger.Write(slog.LevelDebug, fmt.Sprintf("attempting publish, contract %s nonce %v %s", p.contract, p.nonce, string(b)))
+	err = p.send(ctx, data)
+	if err != nil && isNonceError(err) {
+		// Another party (or a previous run of this application) has used the account. Resync and try once more.
+		if err = p.syncNonce(ctx); err != nil {
+			return err
+		}
+		err = p.send(ctx, data)
+	}
+	return err
+}
+
+// Healthy verifies that the node is still answering JSON-RPC requests
+func (p *ethereumPublisher) Healthy(ctx context.Context) error {
+	if p.client == nil {
+		return fmt.Errorf("%w to %s", contracts.ErrNotConnected, p.cfg.Endpoint)
+	}
+	_, err := p.client.BlockNumber(ctx)
+	return err
+}
+
+func (p *ethereumPublisher) Close() error {
+	if p.client != nil {
+		p.client.Close()
+	}
+	return nil
+}
+
+// send builds, signs and submits a transaction using the current nonce. Callers must hold p.mu.
+func (p *ethereumPublisher) send(ctx context.Context, data []byte) error {
+	gasLimit := p.cfg.GasLimit
+	if gasLimit == 0 {
+		estimate, err := p.client.EstimateGas(ctx, eth.CallMsg{From: p.from, To: &p.contract, Data: data})
+		if err != nil {
+			return err
+		}
+		gasLimit = estimate
+	}
+
+	txData, err := p.txData(ctx, gasLimit, data)
+	if err != nil {
+		return err
+	}
+
+	tx, err := types.SignNewTx(p.key, p.signer, txData)
+	if err != nil {
+		return err
+	}
+
+	err = p.client.SendTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+	p.logger.Write(slog.LevelDebug, fmt.Sprintf("transaction submitted %s", tx.Hash().Hex()))
+	p.nonce++
+	return nil
+}
+
+// txData prices the transaction according to the configured GasPriceStrategy
+func (p *ethereumPublisher) txData(ctx context.Context, gasLimit uint64, data []byte) (types.TxData, error) {
+	switch p.cfg.GasPrice.Strategy {
+	case contracts.GasPriceFixed:
+		price := toWei(p.cfg.GasPrice.Fixed)
+		if err := p.checkMax(price); err != nil {
+			return nil, err
+		}
+		return &types.LegacyTx{Nonce: p.nonce, GasPrice: price, Gas: gasLimit, To: &p.contract, Data: data}, nil
+	case contracts.GasPriceDynamic:
+		tip, err := p.client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, err
+		}
+		head, err := p.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if head.BaseFee == nil {
+			return nil, errors.New("endpoint does not support EIP-1559 transactions")
+		}
+		tip = p.applyMultiplier(tip)
+		// Leave headroom for the base fee to double before the transaction becomes unmineable
+		feeCap := new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tip)
+		if err = p.checkMax(feeCap); err != nil {
+			return nil, err
+		}
+		return &types.DynamicFeeTx{Nonce: p.nonce, GasTipCap: tip, GasFeeCap: feeCap, Gas: gasLimit, To: &p.contract,
+			Data: data}, nil
+	default:
+		price, err := p.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		price = p.applyMultiplier(price)
+		if err = p.checkMax(price); err != nil {
+			return nil, err
+		}
+		return &types.LegacyTx{Nonce: p.nonce, GasPrice: price, Gas: gasLimit, To: &p.contract, Data: data}, nil
+	}
+}
+
+func (p *ethereumPublisher) applyMultiplier(price *big.Int) *big.Int {
+	if p.cfg.GasPrice.Multiplier <= 0 {
+		return price
+	}
+	f := new(big.Float).Mul(new(big.Float).SetInt(price), big.NewFloat(p.cfg.GasPrice.Multiplier))
+	result, _ := f.Int(nil)
+	return result
+}
+
+func (p *ethereumPublisher) checkMax(price *big.Int) error {
+	if p.cfg.GasPrice.Max <= 0 {
+		return nil
+	}
+	max := toWei(p.cfg.GasPrice.Max)
+	if price.Cmp(max) > 0 {
+		return fmt.Errorf("gas price %s wei exceeds configured maximum %s wei", price, max)
+	}
+	return nil
+}
+
+func (p *ethereumPublisher) syncNonce(ctx context.Context) error {
+	nonce, err := p.client.PendingNonceAt(ctx, p.from)
+	if err != nil {
+		return err
+	}
+	p.nonce = nonce
+	return nil
+}
+
+// packCalldata ABI encodes a call to the configured contract method. In AnchorHash mode the method is expected to
+// accept a single bytes32 argument holding the keccak256 digest of the content. In AnchorEnvelope mode the method
+// is expected to accept a single dynamic bytes argument holding the content itself.
+func packCalldata(method string, mode contracts.AnchorMode, content []byte) ([]byte, error) {
+	switch mode {
+	case contracts.AnchorHash:
+		selector := crypto.Keccak256([]byte(method + "(bytes32)"))[:4]
+		return append(selector, crypto.Keccak256(content)...), nil
+	case contracts.AnchorEnvelope:
+		selector := crypto.Keccak256([]byte(method + "(bytes)"))[:4]
+		// Head holds the offset to the dynamic argument, tail holds its length followed by the right padded content
+		data := append(selector, common.LeftPadBytes(big.NewInt(32).Bytes(), 32)...)
+		data = append(data, common.LeftPadBytes(big.NewInt(int64(len(content))).Bytes(), 32)...)
+		padded := make([]byte, (len(content)+31)/32*32)
+		copy(padded, content)
+		return append(data, padded...), nil
+	default:
+		return nil, fmt.Errorf("invalid AnchorMode value provided %s", mode)
+	}
+}
+
+func readPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(string(b)), "0x"))
+}
+
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "nonce too high") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
+
+func toWei(value float64) *big.Int {
+	result, _ := new(big.Float).Mul(big.NewFloat(value), gwei).Int(nil)
+	return result
+}
@@ -0,0 +1,68 @@
+//go:build !alvarium_core && !js && !wasip1 && !tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package ethereum
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const anchorAbi = `[
+  {"type":"function","name":"anchorHash","inputs":[{"name":"digest","type":"bytes32"}]},
+  {"type":"function","name":"anchorEnvelope","inputs":[{"name":"envelope","type":"bytes"}]}
+]`
+
+func TestPackCalldata(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(anchorAbi))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	content := []byte(test.FactoryRandomFixedLengthString(100, test.AlphanumericCharset))
+	var digest [32]byte
+	copy(digest[:], crypto.Keccak256(content))
+
+	expectHash, _ := parsed.Pack("anchorHash", digest)
+	expectEnvelope, _ := parsed.Pack("anchorEnvelope", content)
+
+	tests := []struct {
+		name        string
+		method      string
+		mode        contracts.AnchorMode
+		expected    []byte
+		expectError bool
+	}{
+		{"hash anchor", "anchorHash", contracts.AnchorHash, expectHash, false},
+		{"envelope anchor", "anchorEnvelope", contracts.AnchorEnvelope, expectEnvelope, false},
+		{"invalid anchor mode", "anchorHash", "invalid", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := packCalldata(tt.method, tt.mode, content)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package fluentd
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// This file implements the parts of the Fluentd Forward Protocol v1
+// (https://github.com/fluent/fluentd/wiki/Forward-Protocol-Specification-v1) used by the publisher.
+
+const eventTimeExtension = 0
+
+// eventTime is encoded as the EventTime extension, preserving nanosecond precision
+type eventTime time.Time
+
+func (t eventTime) EncodeMsgpack(enc *msgpack.Encoder) error {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b[:4], uint32(time.Time(t).Unix()))
+	binary.BigEndian.PutUint32(b[4:], uint32(time.Time(t).Nanosecond()))
+	if err := enc.EncodeExtHeader(eventTimeExtension, len(b)); err != nil {
+		return err
+	}
+	_, err := enc.Writer().Write(b)
+	return err
+}
+
+// entry is a single event of a forward mode message
+type entry struct {
+	_msgpack struct{} `msgpack:",as_array"`
+	Time     eventTime
+	Record   map[string]interface{}
+}
+
+// forwardMessage carries several events sharing a tag in a single message
+type forwardMessage struct {
+	_msgpack struct{} `msgpack:",as_array"`
+	Tag      string
+	Entries  []entry
+	Option   map[string]interface{}
+}
+
+// helo is sent by a collector requiring authentication as soon as a client connects
+type helo struct {
+	nonce     []byte
+	auth      []byte
+	keepalive bool
+}
+
+func decodeHelo(v []interface{}) (helo, error) {
+	if len(v) != 2 || v[0] != "HELO" {
+		return helo{}, errors.New("expected HELO from collector")
+	}
+	options, ok := v[1].(map[string]interface{})
+	if !ok {
+		return helo{}, errors.New("malformed HELO options")
+	}
+	h := helo{keepalive: true}
+	h.nonce = toBytes(options["nonce"])
+	h.auth = toBytes(options["auth"])
+	if keepalive, ok := options["keepalive"].(bool); ok {
+		h.keepalive = keepalive
+	}
+	return h, nil
+}
+
+// ping answers a HELO, proving knowledge of the shared key and optionally supplying user credentials
+func ping(h helo, hostname, sharedKey, username, password string) ([]interface{}, []byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+	userDigest := ""
+	if len(h.auth) > 0 {
+		userDigest = digest(h.auth, []byte(username), []byte(password))
+	}
+	return []interface{}{"PING", hostname, salt, digest(salt, []byte(hostname), h.nonce, []byte(sharedKey)),
+		username, userDigest}, salt, nil
+}
+
+// verifyPong checks the collector accepted the PING and that it also knows the shared key
+func verifyPong(v []interface{}, h helo, salt []byte, sharedKey string) error {
+	if len(v) != 5 || v[0] != "PONG" {
+		return errors.New("expected PONG from collector")
+	}
+	if authenticated, _ := v[1].(bool); !authenticated {
+		return fmt.Errorf("collector rejected authentication %v", v[2])
+	}
+	hostname := string(toBytes(v[3]))
+	if string(toBytes(v[4])) != digest(salt, []byte(hostname), h.nonce, []byte(sharedKey)) {
+		return errors.New("collector shared key digest mismatch")
+	}
+	return nil
+}
+
+// chunkId creates the identifier a collector echoes back when acknowledging a message
+func chunkId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func digest(parts ...[]byte) string {
+	h := sha512.New()
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// toBytes normalizes values collectors may send as either str or bin
+func toBytes(v interface{}) []byte {
+	switch t := v.(type) {
+	case []byte:
+		return t
+	case string:
+		return []byte(t)
+	}
+	return nil
+}
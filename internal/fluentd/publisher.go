@@ -0,0 +1,288 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package fluentd
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/tlsconfig"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	dialTimeout    = 5 * time.Second
+	publishTimeout = 5 * time.Second
+
+	defaultTag = "alvarium"
+)
+
+type fluentdPublisher struct {
+	cfg       config.FluentdConfig
+	logger    interfaces.Logger
+	tlsConfig *tls.Config
+	hostname  string
+
+	mu          sync.Mutex
+	conn        net.Conn
+	enc         *msgpack.Encoder
+	dec         *msgpack.Decoder
+	dialed      bool   // dialed is set once the first connection has been established
+	onReconnect func() // onReconnect is called when a later connection is established
+}
+
+func NewFluentdPublisher(cfg config.FluentdConfig, logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	if cfg.Provider.Protocol != "tcp" && cfg.Provider.Protocol != "tls" {
+		return nil, fmt.Errorf("unsupported fluentd transport %s", cfg.Provider.Protocol)
+	}
+	if cfg.Tag == "" {
+		cfg.Tag = defaultTag
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+	p := fluentdPublisher{
+		cfg:      cfg,
+		logger:   logger,
+		hostname: hostname,
+	}
+
+	if cfg.Provider.Protocol == "tls" {
+		t, err := tlsconfig.New(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		if t.ServerName == "" {
+			t.ServerName = cfg.Provider.Host
+		}
+		p.tlsConfig = t
+	}
+	return &p, nil
+}
+
+func (p *fluentdPublisher) Connect(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.reconnect(ctx)
+}
+
+// Publish forwards the wrapper as a single forward mode message holding one event per annotation, so collectors can
+// route and filter on annotation properties. Wrappers not carrying annotations are forwarded as a single event.
+func (p *fluentdPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	fm := forwardMessage{Tag: p.cfg.Tag, Entries: entries(msg, time.Now())}
+	if p.cfg.RequireAck {
+		chunk, err := chunkId()
+		if err != nil {
+			return err
+		}
+		fm.Option = map[string]interface{}{"chunk": chunk}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.logger.Write(slog.LevelDebug, fmt.Sprintf("attempting publish, tag %s entries %v", p.cfg.Tag, len(fm.Entries)))
+	err := p.send(ctx, fm)
+	if err != nil {
+		// The collector may have dropped an idle connection. Attempt one reconnect before giving up
+		p.disconnect()
+		if err = p.send(ctx, fm); err != nil {
+			p.disconnect()
+			return err
+		}
+	}
+	return nil
+}
+
+// OnReconnect registers handler to be called whenever a dropped connection is re-established
+func (p *fluentdPublisher) OnReconnect(handler func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onReconnect = handler
+}
+
+// Healthy reports whether a connection is currently established. A dropped connection is only detected once a
+// write to it fails.
+func (p *fluentdPublisher) Healthy(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		return fmt.Errorf("%w to %s", contracts.ErrNotConnected, p.cfg.Provider.Uri())
+	}
+	return nil
+}
+
+func (p *fluentdPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.disconnect()
+}
+
+// send writes a message and waits for its acknowledgement when required. Callers must hold p.mu.
+func (p *fluentdPublisher) send(ctx context.Context, fm forwardMessage) error {
+	if err := p.reconnect(ctx); err != nil {
+		return err
+	}
+	p.conn.SetDeadline(time.Now().Add(publishTimeout))
+	defer p.conn.SetDeadline(time.Time{})
+
+	if err := p.enc.Encode(fm); err != nil {
+		return err
+	}
+	if fm.Option == nil {
+		return nil
+	}
+
+	resp, err := p.dec.DecodeMap()
+	if err != nil {
+		return err
+	}
+	if string(toBytes(resp["ack"])) != fm.Option["chunk"] {
+		return errors.New("collector acknowledged an unexpected chunk")
+	}
+	return nil
+}
+
+// reconnect dials the collector and authenticates if there is no open connection, giving up when ctx is done.
+// Callers must hold p.mu.
+func (p *fluentdPublisher) reconnect(ctx context.Context) error {
+	if p.conn != nil {
+		return nil
+	}
+
+	address := net.JoinHostPort(p.cfg.Provider.Host, strconv.Itoa(p.cfg.Provider.Port))
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var conn net.Conn
+	var err error
+	if p.tlsConfig != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: p.tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+	p.conn = conn
+	p.enc = msgpack.NewEncoder(conn)
+	p.dec = msgpack.NewDecoder(conn)
+
+	if p.cfg.SharedKey != "" {
+		if err = p.authenticate(); err != nil {
+			p.disconnect()
+			return err
+		}
+	}
+	if p.dialed && p.onReconnect != nil {
+		p.onReconnect()
+	}
+	p.dialed = true
+	return nil
+}
+
+// authenticate performs the HELO/PING/PONG handshake. Callers must hold p.mu.
+func (p *fluentdPublisher) authenticate() error {
+	p.conn.SetDeadline(time.Now().Add(dialTimeout))
+	defer p.conn.SetDeadline(time.Time{})
+
+	v, err := p.dec.DecodeSlice()
+	if err != nil {
+		return err
+	}
+	h, err := decodeHelo(v)
+	if err != nil {
+		return err
+	}
+
+	pingMessage, salt, err := ping(h, p.hostname, p.cfg.SharedKey, p.cfg.Username, p.cfg.Password)
+	if err != nil {
+		return err
+	}
+	if err = p.enc.Encode(pingMessage); err != nil {
+		return err
+	}
+
+	if v, err = p.dec.DecodeSlice(); err != nil {
+		return err
+	}
+	return verifyPong(v, h, salt, p.cfg.SharedKey)
+}
+
+// disconnect closes the current connection if there is one. Callers must hold p.mu.
+func (p *fluentdPublisher) disconnect() error {
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	p.enc = nil
+	p.dec = nil
+	return err
+}
+
+// entries maps a publish wrapper to forward mode events
+func entries(msg message.PublishWrapper, now time.Time) []entry {
+	var list contracts.AnnotationList
+	if err := json.Unmarshal(msg.Content, &list); err != nil || len(list.Items) == 0 {
+		return []entry{{
+			Time: eventTime(now),
+			Record: map[string]interface{}{
+				"action":      string(msg.Action),
+				"messageType": msg.MessageType,
+				"content":     string(msg.Content),
+			},
+		}}
+	}
+
+	result := make([]entry, 0, len(list.Items))
+	for _, a := range list.Items {
+		ts := a.Timestamp
+		if ts.IsZero() {
+			ts = now
+		}
+		result = append(result, entry{
+			Time: eventTime(ts),
+			Record: map[string]interface{}{
+				"action":      string(msg.Action),
+				"messageType": msg.MessageType,
+				"id":          a.Id.String(),
+				"key":         a.Key,
+				"hash":        string(a.Hash),
+				"host":        a.Host,
+				"tag":         a.Tag.String(),
+				"layer":       string(a.Layer),
+				"kind":        string(a.Kind),
+				"signature":   a.Signature,
+				"isSatisfied": a.IsSatisfied,
+				"timestamp":   a.Timestamp.Format(time.RFC3339Nano),
+			},
+		})
+	}
+	return result
+}
@@ -0,0 +1,181 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package fluentd
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// receivedTime decodes the EventTime extension on the collector side
+type receivedTime time.Time
+
+func (t *receivedTime) DecodeMsgpack(dec *msgpack.Decoder) error {
+	_, size, err := dec.DecodeExtHeader()
+	if err != nil {
+		return err
+	}
+	b := make([]byte, size)
+	if err = dec.ReadFull(b); err != nil {
+		return err
+	}
+	*t = receivedTime(time.Unix(int64(binary.BigEndian.Uint32(b[:4])), int64(binary.BigEndian.Uint32(b[4:]))))
+	return nil
+}
+
+type receivedMessage struct {
+	_msgpack struct{} `msgpack:",as_array"`
+	Tag      string
+	Entries  []struct {
+		_msgpack struct{} `msgpack:",as_array"`
+		Time     receivedTime
+		Record   map[string]interface{}
+	}
+	Option map[string]interface{}
+}
+
+func TestNewFluentdPublisher(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	tests := []struct {
+		name        string
+		cfg         config.FluentdConfig
+		expectError bool
+	}{
+		{"valid tcp", config.FluentdConfig{Provider: config.ServiceInfo{Protocol: "tcp"}}, false},
+		{"valid tls", config.FluentdConfig{Provider: config.ServiceInfo{Protocol: "tls"}}, false},
+		{"invalid transport", config.FluentdConfig{Provider: config.ServiceInfo{Protocol: "udp"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFluentdPublisher(tt.cfg, logger)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestFluentdPublisher_Publish(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	satisfied := contracts.NewAnnotation("key", contracts.SHA256Hash, "host", contracts.Application, contracts.AnnotationTPM, true)
+	b, _ := json.Marshal(contracts.AnnotationList{Items: []contracts.Annotation{satisfied}})
+
+	tests := []struct {
+		name        string
+		sharedKey   string
+		clientKey   string
+		requireAck  bool
+		expectError bool
+	}{
+		{"no authentication", "", "", false, false},
+		{"shared key with ack", "secret", "secret", true, false},
+		{"shared key mismatch", "secret", "wrong", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			defer listener.Close()
+			received := make(chan receivedMessage, 1)
+			go serve(listener, tt.sharedKey, received)
+
+			_, port, _ := net.SplitHostPort(listener.Addr().String())
+			portNum, _ := strconv.Atoi(port)
+			cfg := config.FluentdConfig{
+				Provider:   config.ServiceInfo{Host: "127.0.0.1", Port: portNum, Protocol: "tcp"},
+				Tag:        "alvarium.test",
+				SharedKey:  tt.clientKey,
+				RequireAck: tt.requireAck,
+			}
+			p, err := NewFluentdPublisher(cfg, logger)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			err = p.Connect(context.Background())
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+			defer p.Close()
+
+			msg := message.PublishWrapper{Action: message.ActionCreate, MessageType: "AnnotationList", Content: b}
+			if err = p.Publish(context.Background(), msg); err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			select {
+			case m := <-received:
+				assert.Equal(t, "alvarium.test", m.Tag)
+				assert.Len(t, m.Entries, 1)
+				assert.Equal(t, satisfied.Id.String(), m.Entries[0].Record["id"])
+				assert.Equal(t, true, m.Entries[0].Record["isSatisfied"])
+				assert.Equal(t, "create", m.Entries[0].Record["action"])
+				assert.True(t, time.Time(m.Entries[0].Time).Equal(satisfied.Timestamp))
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for forwarded message")
+			}
+		})
+	}
+}
+
+// serve emulates a forward input, authenticating the client when a shared key is configured
+func serve(listener net.Listener, sharedKey string, received chan receivedMessage) {
+	nc, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer nc.Close()
+	enc := msgpack.NewEncoder(nc)
+	dec := msgpack.NewDecoder(nc)
+
+	if sharedKey != "" {
+		nonce := []byte("0123456789abcdef")
+		enc.Encode([]interface{}{"HELO", map[string]interface{}{"nonce": nonce, "auth": []byte{}, "keepalive": true}})
+		v, err := dec.DecodeSlice()
+		if err != nil || len(v) != 6 {
+			return
+		}
+		hostname := v[1].(string)
+		salt := toBytes(v[2])
+		if v[3] != digest(salt, []byte(hostname), nonce, []byte(sharedKey)) {
+			enc.Encode([]interface{}{"PONG", false, "shared key mismatch", "", ""})
+			return
+		}
+		enc.Encode([]interface{}{"PONG", true, "", "collector", digest(salt, []byte("collector"), nonce, []byte(sharedKey))})
+	}
+
+	var m receivedMessage
+	if err = dec.Decode(&m); err != nil {
+		return
+	}
+	if chunk, ok := m.Option["chunk"]; ok {
+		enc.Encode(map[string]interface{}{"ack": chunk})
+	}
+	received <- m
+}
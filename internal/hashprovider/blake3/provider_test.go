@@ -0,0 +1,74 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package blake3
+
+import (
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSUT returns a new system under test.
+func newSUT() *provider {
+	return New()
+}
+
+// TestProvider_Derive tests provider.Derive.
+func TestProvider_Derive(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{
+			name:     "text variation 1",
+			data:     []byte("foo"),
+			expected: "04e0bb39f30b1a3feb89f536c93be15055482df748674b00d26e5a75777702e9",
+		},
+		{
+			name:     "text variation 2",
+			data:     []byte("bar"),
+			expected: "f2e897eed7d206cd855d441598fa521abc75aa96953e97c030c9612c30c1293d",
+		},
+		{
+			name:     "text variation 3",
+			data:     []byte("baz"),
+			expected: "9624faa79d245cea9c345474fdb1a863b75921a8dd7aff3d84b22c65d1fc0847",
+		},
+		{
+			name:     "byte sequence",
+			data:     []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 0},
+			expected: "630f4fa2652c66ec4190623a829bd17a0d862f5f4e6a0ae4ece5c29f454bcf7c",
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := newSUT()
+
+				result := sut.Derive(cases[i].data)
+
+				assert.Equal(t, cases[i].expected, result)
+			},
+		)
+	}
+}
+
+// TestProvider_DeriveFromReader tests provider.DeriveFromReader.
+func TestProvider_DeriveFromReader(t *testing.T) {
+	test.CheckDeriveFromReader(t, newSUT())
+}
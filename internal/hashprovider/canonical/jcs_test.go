@@ -0,0 +1,49 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package canonical
+
+import (
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expected    string
+		expectError bool
+	}{
+		{"whitespace and property order", `{ "b": [1, 2, {"d": true, "c": null}],
+			"a": "x" }`, `{"a":"x","b":[1,2,{"c":null,"d":true}]}`, false},
+		{"rfc 8785 numbers", `[0, -0, 1E30, 4.50, 2e-3, 0.000000000000000000000000001, 333333333.33333329, 1e21, 1e-7]`,
+			`[0,0,1e+30,4.5,0.002,1e-27,333333333.3333333,1e+21,1e-7]`, false},
+		{"rfc 8785 property sorting", `{"\u20ac":1,"\r":2,"\ufb33":3,"1":4,"\ud83d\ude00":5,"\u0080":6,"\u00f6":7}`,
+			"{\"\\r\":2,\"1\":4,\"\u0080\":6,\"\u00f6\":7,\"\u20ac\":1,\"\U0001F600\":5,\"\ufb33\":3}", false},
+		{"string escaping", `"\u0041\u001f\/<>&\u2028\t"`, "\"A\\u001f/<>&\u2028\\t\"", false},
+		{"invalid json", `{"a":`, "", true},
+		{"trailing data", `{"a":1} {"b":2}`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Transform([]byte(tt.data))
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.expected, string(result))
+			}
+		})
+	}
+}
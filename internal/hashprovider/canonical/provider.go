@@ -0,0 +1,61 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package canonical
+
+import (
+	"io"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct {
+	provider interfaces.HashProvider
+}
+
+// New is a factory function that returns a provider canonicalizing JSON payloads before they are hashed by p.
+func New(p interfaces.HashProvider) *provider {
+	return &provider{provider: p}
+}
+
+// Derive converts data to an identity value. Payloads that are not valid JSON are hashed as they are.
+func (p *provider) Derive(data []byte) string {
+	if canonical, err := Transform(data); err == nil {
+		data = canonical
+	}
+	return p.provider.Derive(data)
+}
+
+// DeriveFromReader converts the data read from r to an identity value. The document must be read fully before it
+// can be canonicalized, so streaming offers no memory savings in this mode.
+func (p *provider) DeriveFromReader(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return p.Derive(data), nil
+}
+
+// ForTenant returns a provider canonicalizing payloads for the tenant's underlying hash provider.
+func (p *provider) ForTenant(tenant string) (interfaces.HashProvider, error) {
+	t, ok := p.provider.(interfaces.TenantHashProvider)
+	if !ok {
+		return p, nil
+	}
+	h, err := t.ForTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return New(h), nil
+}
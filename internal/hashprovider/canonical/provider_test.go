@@ -0,0 +1,87 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package canonical
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/salted"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/sha256"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProvider_Derive tests provider.Derive.
+func TestProvider_Derive(t *testing.T) {
+	canonical := sha256.New().Derive([]byte(`{"a":1,"b":"x"}`))
+	cases := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{
+			name:     "canonical document",
+			data:     []byte(`{"a":1,"b":"x"}`),
+			expected: canonical,
+		},
+		{
+			name:     "equivalent document",
+			data:     []byte(`{ "b" : "x", "a" : 1.0 }`),
+			expected: canonical,
+		},
+		{
+			name:     "not json",
+			data:     []byte("foo"),
+			expected: "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := New(sha256.New())
+
+				result := sut.Derive(cases[i].data)
+
+				assert.Equal(t, cases[i].expected, result)
+
+				streamed, err := sut.DeriveFromReader(bytes.NewReader(cases[i].data))
+				assert.NoError(t, err)
+				assert.Equal(t, cases[i].expected, streamed)
+			},
+		)
+	}
+}
+
+// TestProvider_ForTenant tests provider.ForTenant.
+func TestProvider_ForTenant(t *testing.T) {
+	s, err := salted.New(config.SaltInfo{Value: "0102", Tenants: map[string]string{"tenant-a": "0a0b"}}, sha256.New())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	tenant, err := s.ForTenant("tenant-a")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	sut, err := New(s).ForTenant("tenant-a")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// The tenant's salt is applied to the canonical form
+	assert.Equal(t, tenant.Derive([]byte(`{"a":1}`)), sut.Derive([]byte(`{ "a": 1 }`)))
+}
@@ -0,0 +1,77 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package hmac
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+)
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct {
+	key []byte
+}
+
+// New is a factory function that returns a provider initialized with the secret key referenced by cfg.
+func New(cfg config.HashInfo) (*provider, error) {
+	var encoded string
+	switch {
+	case cfg.KeyEnv != "":
+		encoded = os.Getenv(cfg.KeyEnv)
+		if encoded == "" {
+			return nil, fmt.Errorf("hash key environment variable %s is not set", cfg.KeyEnv)
+		}
+	case cfg.KeyPath != "":
+		b, err := os.ReadFile(cfg.KeyPath)
+		if err != nil {
+			return nil, err
+		}
+		encoded = string(b)
+	default:
+		return nil, errors.New("no hash key configured")
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hash key: %w", err)
+	}
+	if len(key) == 0 {
+		return nil, errors.New("hash key is empty")
+	}
+	return &provider{key: key}, nil
+}
+
+// Derive converts data to an identity value that can only be reproduced by holders of the key.
+func (p *provider) Derive(data []byte) string {
+	h := hmac.New(sha256.New, p.key)
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// DeriveFromReader converts the data read from r to an identity value without buffering it.
+func (p *provider) DeriveFromReader(r io.Reader) (string, error) {
+	h := hmac.New(sha256.New, p.key)
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
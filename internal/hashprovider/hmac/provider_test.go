@@ -0,0 +1,116 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package hmac
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const testKey = "000102030405060708090a0b0c0d0e0f"
+
+// newSUT returns a new system under test.
+func newSUT(t *testing.T) *provider {
+	t.Setenv("ALVARIUM_HASH_KEY", testKey)
+	p, err := New(config.HashInfo{Type: contracts.HMACHash, KeyEnv: "ALVARIUM_HASH_KEY"})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	return p
+}
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "hash.key")
+	if err := os.WriteFile(keyPath, []byte(testKey+"\n"), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+	invalidPath := filepath.Join(dir, "invalid.key")
+	if err := os.WriteFile(invalidPath, []byte("not hex"), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+	t.Setenv("ALVARIUM_HASH_KEY", testKey)
+
+	tests := []struct {
+		name        string
+		cfg         config.HashInfo
+		expectError bool
+	}{
+		{"key from file", config.HashInfo{KeyPath: keyPath}, false},
+		{"key from environment", config.HashInfo{KeyEnv: "ALVARIUM_HASH_KEY"}, false},
+		{"environment takes precedence", config.HashInfo{KeyEnv: "ALVARIUM_HASH_KEY", KeyPath: invalidPath}, false},
+		{"unset environment variable", config.HashInfo{KeyEnv: "ALVARIUM_UNSET_HASH_KEY"}, true},
+		{"missing file", config.HashInfo{KeyPath: filepath.Join(dir, "missing.key")}, true},
+		{"invalid key", config.HashInfo{KeyPath: invalidPath}, true},
+		{"no key", config.HashInfo{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Type = contracts.HMACHash
+			_, err := New(tt.cfg)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+// TestProvider_Derive tests provider.Derive.
+func TestProvider_Derive(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{
+			name:     "text variation 1",
+			data:     []byte("foo"),
+			expected: "62b3fe48116963b7dbf2070b2ef5d110a8bd2951c04c185256d95f6609f6d9d7",
+		},
+		{
+			name:     "text variation 2",
+			data:     []byte("bar"),
+			expected: "bf2793e0a4d8a6fa301b3c3b57069e895ff092393983e533586ba89c50ac5a81",
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := newSUT(t)
+
+				result := sut.Derive(cases[i].data)
+
+				assert.Equal(t, cases[i].expected, result)
+			},
+		)
+	}
+}
+
+// TestProvider_DeriveFromReader tests provider.DeriveFromReader.
+func TestProvider_DeriveFromReader(t *testing.T) {
+	sut := newSUT(t)
+	test.CheckDeriveFromReader(t, sut)
+
+	// The streamed hash value is keyed like that of Derive
+	result, err := sut.DeriveFromReader(bytes.NewReader([]byte("foo")))
+	assert.NoError(t, err)
+	assert.Equal(t, "62b3fe48116963b7dbf2070b2ef5d110a8bd2951c04c185256d95f6609f6d9d7", result)
+}
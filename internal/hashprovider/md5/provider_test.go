@@ -14,8 +14,10 @@
 package md5
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
 )
 
 // newSUT returns a new system under test.
@@ -65,3 +67,8 @@ func TestProvider_Derive(t *testing.T) {
 		)
 	}
 }
+
+// TestProvider_DeriveFromReader tests provider.DeriveFromReader.
+func TestProvider_DeriveFromReader(t *testing.T) {
+	test.CheckDeriveFromReader(t, newSUT())
+}
@@ -0,0 +1,44 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package merkle
+
+import (
+	"encoding/hex"
+	"io"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/merkle"
+)
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct{}
+
+// New is a factory function that returns an initialized provider.
+func New() *provider {
+	return &provider{}
+}
+
+// Derive converts data to an identity value. A single payload is identified by the root of a tree containing only
+// that payload, which is its leaf hash.
+func (*provider) Derive(data []byte) string {
+	return hex.EncodeToString(merkle.LeafHash(data))
+}
+
+// DeriveFromReader converts the data read from r to an identity value without buffering it.
+func (*provider) DeriveFromReader(r io.Reader) (string, error) {
+	h := merkle.NewLeafHasher()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,74 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package merkle
+
+import (
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSUT returns a new system under test.
+func newSUT() *provider {
+	return New()
+}
+
+// TestProvider_Derive tests provider.Derive.
+func TestProvider_Derive(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{
+			name:     "text variation 1",
+			data:     []byte("foo"),
+			expected: "1d2039fa7971f4bf01a1c20cb2a3fe7af46865ca9cd9b840c2063df8fec4ff75",
+		},
+		{
+			name:     "text variation 2",
+			data:     []byte("bar"),
+			expected: "485904129bdda5d1b5fbc6bc4a82959ecfb9042db44dc08fe87e360b0a3f2501",
+		},
+		{
+			name:     "text variation 3",
+			data:     []byte("baz"),
+			expected: "b06d695869f105fffa5f68c4b9628d58a1aff469a3c62c8c74ddb2af47b178ef",
+		},
+		{
+			name:     "byte sequence",
+			data:     []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 0},
+			expected: "b343accf5ba77fea3d9915759cdfdfb5275db7d3023664f8b3dcad15d514aecc",
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := newSUT()
+
+				result := sut.Derive(cases[i].data)
+
+				assert.Equal(t, cases[i].expected, result)
+			},
+		)
+	}
+}
+
+// TestProvider_DeriveFromReader tests provider.DeriveFromReader.
+func TestProvider_DeriveFromReader(t *testing.T) {
+	test.CheckDeriveFromReader(t, newSUT())
+}
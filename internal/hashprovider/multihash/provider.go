@@ -0,0 +1,90 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package multihash
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// codes maps hash types to their multicodec identifiers (https://github.com/multiformats/multicodec)
+var codes = map[contracts.HashType]uint64{
+	contracts.NoHash:     0x00,
+	contracts.MD5Hash:    0xd5,
+	contracts.SHA256Hash: 0x12,
+	contracts.SHA3Hash:   0x16,
+	contracts.BLAKE3Hash: 0x1e,
+}
+
+// multibase prefix identifying lowercase, unpadded base32
+const base32Prefix = "b"
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct {
+	code     uint64
+	hashType contracts.HashType
+	provider interfaces.HashProvider
+}
+
+// New is a factory function that returns a provider encoding the hashes derived by p, which must be of the given
+// type, as multihashes.
+func New(hashType contracts.HashType, p interfaces.HashProvider) (*provider, error) {
+	code, ok := codes[hashType]
+	if !ok {
+		return nil, fmt.Errorf("%w: hash type %s has no multihash code", contracts.ErrHashUnsupported, hashType)
+	}
+	return &provider{code: code, hashType: hashType, provider: p}, nil
+}
+
+// Derive converts data to a self-describing identity value.
+func (p *provider) Derive(data []byte) string {
+	return p.encode(p.provider.Derive(data))
+}
+
+// DeriveFromReader converts the data read from r to a self-describing identity value.
+func (p *provider) DeriveFromReader(r io.Reader) (string, error) {
+	stream, ok := p.provider.(interfaces.HashProviderStream)
+	if !ok {
+		return "", fmt.Errorf("%w: hash type %s does not support streaming", contracts.ErrHashUnsupported, p.hashType)
+	}
+	value, err := stream.DeriveFromReader(r)
+	if err != nil {
+		return "", err
+	}
+	return p.encode(value), nil
+}
+
+// encode converts a value derived by the underlying provider to a multibase encoded multihash
+func (p *provider) encode(value string) string {
+	digest := []byte(value)
+	if p.hashType != contracts.NoHash {
+		if decoded, err := hex.DecodeString(value); err == nil {
+			digest = decoded
+		}
+	}
+
+	b := binary.AppendUvarint(nil, p.code)
+	b = binary.AppendUvarint(b, uint64(len(digest)))
+	b = append(b, digest...)
+	return base32Prefix + strings.ToLower(encoding.EncodeToString(b))
+}
@@ -0,0 +1,116 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package multihash
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/md5"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/none"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/sha256"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// plainProvider is a hash provider that does not support streaming
+type plainProvider struct {
+	interfaces.HashProvider
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		hashType    contracts.HashType
+		expectError bool
+	}{
+		{"sha256", contracts.SHA256Hash, false},
+		{"no multihash code", contracts.MerkleHash, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.hashType, sha256.New())
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+// TestProvider_Derive tests provider.Derive.
+func TestProvider_Derive(t *testing.T) {
+	cases := []struct {
+		name     string
+		hashType contracts.HashType
+		provider interfaces.HashProvider
+		expected string
+	}{
+		{
+			name:     "sha256",
+			hashType: contracts.SHA256Hash,
+			provider: sha256.New(),
+			expected: "bciqcyjvunnup7rup7gnukpa5gbatie2cfvygja57ud4yuxuimjtoplq",
+		},
+		{
+			name:     "md5",
+			hashType: contracts.MD5Hash,
+			provider: md5.New(),
+			expected: "b2uarblf5ddnuzqxyltw66zkpztckjwa",
+		},
+		{
+			name:     "identity",
+			hashType: contracts.NoHash,
+			provider: none.New(),
+			expected: "baabwm33p",
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut, err := New(cases[i].hashType, cases[i].provider)
+				if err != nil {
+					t.Fatalf(err.Error())
+				}
+
+				result := sut.Derive([]byte("foo"))
+
+				assert.Equal(t, cases[i].expected, result)
+			},
+		)
+	}
+}
+
+// TestProvider_DeriveFromReader tests provider.DeriveFromReader.
+func TestProvider_DeriveFromReader(t *testing.T) {
+	sut, err := New(contracts.SHA256Hash, sha256.New())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	test.CheckDeriveFromReader(t, sut)
+
+	// The streamed hash value carries the multihash prefix of the wrapped hash type
+	result, err := sut.DeriveFromReader(bytes.NewReader([]byte("foo")))
+	assert.NoError(t, err)
+	assert.Equal(t, "bciqcyjvunnup7rup7gnukpa5gbatie2cfvygja57ud4yuxuimjtoplq", result)
+
+	// A multihash can only be streamed when the wrapped provider can stream
+	plain, err := New(contracts.SHA256Hash, plainProvider{sha256.New()})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	_, err = plain.DeriveFromReader(bytes.NewReader([]byte("foo")))
+	assert.Error(t, err)
+}
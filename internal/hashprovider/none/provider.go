@@ -13,6 +13,8 @@
  *******************************************************************************/
 package none
 
+import "io"
+
 // provider is a receiver that encapsulates required dependencies.
 type provider struct{}
 
@@ -25,3 +27,13 @@ func New() *provider {
 func (*provider) Derive(data []byte) string {
 	return string(data)
 }
+
+// DeriveFromReader converts the data read from r to an identity value. Since the identity of the data is the data
+// itself, it is read fully into memory.
+func (*provider) DeriveFromReader(r io.Reader) (string, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
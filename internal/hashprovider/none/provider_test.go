@@ -15,9 +15,10 @@ package none
 
 import (
 	"fmt"
+	"testing"
+
 	"github.com/project-alvarium/alvarium-sdk-go/test"
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
 // newSUT returns a new system under test.
@@ -41,3 +42,8 @@ func TestProvider_Derive(t *testing.T) {
 		)
 	}
 }
+
+// TestProvider_DeriveFromReader tests provider.DeriveFromReader.
+func TestProvider_DeriveFromReader(t *testing.T) {
+	test.CheckDeriveFromReader(t, newSUT())
+}
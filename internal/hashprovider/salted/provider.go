@@ -0,0 +1,84 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package salted
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct {
+	salt     []byte
+	tenants  map[string][]byte
+	provider interfaces.HashProvider
+}
+
+// New is a factory function that returns a provider prefixing data with the configured salt before it is hashed
+// by p.
+func New(cfg config.SaltInfo, p interfaces.HashProvider) (*provider, error) {
+	value := cfg.Value
+	if cfg.Env != "" {
+		value = os.Getenv(cfg.Env)
+	}
+	// Falling back to an unsalted hash would silently expose the data to dictionary attacks
+	if value == "" {
+		return nil, errors.New("a default salt is required")
+	}
+	salt, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default salt: %w", err)
+	}
+
+	tenants := make(map[string][]byte, len(cfg.Tenants))
+	for tenant, value := range cfg.Tenants {
+		if tenants[tenant], err = hex.DecodeString(value); err != nil || len(tenants[tenant]) == 0 {
+			return nil, fmt.Errorf("invalid salt for tenant %s", tenant)
+		}
+	}
+	return &provider{salt: salt, tenants: tenants, provider: p}, nil
+}
+
+// Derive converts the salted data to an identity value.
+func (p *provider) Derive(data []byte) string {
+	salted := make([]byte, 0, len(p.salt)+len(data))
+	salted = append(salted, p.salt...)
+	return p.provider.Derive(append(salted, data...))
+}
+
+// DeriveFromReader converts the salted data read from r to an identity value.
+func (p *provider) DeriveFromReader(r io.Reader) (string, error) {
+	stream, ok := p.provider.(interfaces.HashProviderStream)
+	if !ok {
+		return "", fmt.Errorf("%w: hash provider does not support streaming", contracts.ErrHashUnsupported)
+	}
+	return stream.DeriveFromReader(io.MultiReader(bytes.NewReader(p.salt), r))
+}
+
+// ForTenant returns a provider salting data with the tenant's salt, or the default salt for unknown tenants.
+func (p *provider) ForTenant(tenant string) (interfaces.HashProvider, error) {
+	salt, ok := p.tenants[tenant]
+	if !ok {
+		return p, nil
+	}
+	return &provider{salt: salt, provider: p.provider}, nil
+}
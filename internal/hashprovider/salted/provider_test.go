@@ -0,0 +1,120 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package salted
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/sha256"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	unsalted      = "b8d31e852725afb1e26d53bab6095b2bff1749c9275be13ed1c05a56ed31ec09"
+	defaultSalted = "9367bdb334082a64e267a5766004d556d22719807e4aad4e9a8bb59cab56b6b1"
+	tenantSalted  = "78ff79d156b195e8d31bc90b27fcec86a00742e0bc6bf82ac63418a95fe1f2cf"
+)
+
+// newSUT returns a new system under test.
+func newSUT(t *testing.T) *provider {
+	p, err := New(config.SaltInfo{Value: "0102", Tenants: map[string]string{"tenant-a": "0a0b"}}, sha256.New())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	return p
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("ALVARIUM_HASH_SALT", "0102")
+
+	tests := []struct {
+		name        string
+		cfg         config.SaltInfo
+		expectError bool
+	}{
+		{"static salt", config.SaltInfo{Value: "0102"}, false},
+		{"salt from environment", config.SaltInfo{Env: "ALVARIUM_HASH_SALT"}, false},
+		{"tenant salts", config.SaltInfo{Value: "0102", Tenants: map[string]string{"tenant-a": "0a0b"}}, false},
+		{"no default salt", config.SaltInfo{Tenants: map[string]string{"tenant-a": "0a0b"}}, true},
+		{"unset environment variable", config.SaltInfo{Env: "ALVARIUM_UNSET_HASH_SALT"}, true},
+		{"invalid default salt", config.SaltInfo{Value: "salt"}, true},
+		{"invalid tenant salt", config.SaltInfo{Value: "0102", Tenants: map[string]string{"tenant-a": ""}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg, sha256.New())
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+// TestProvider_Derive tests provider.Derive.
+func TestProvider_Derive(t *testing.T) {
+	sut := newSUT(t)
+
+	result := sut.Derive([]byte("on"))
+
+	assert.Equal(t, defaultSalted, result)
+	assert.NotEqual(t, unsalted, result)
+}
+
+// TestProvider_ForTenant tests provider.ForTenant.
+func TestProvider_ForTenant(t *testing.T) {
+	cases := []struct {
+		name     string
+		tenant   string
+		expected string
+	}{
+		{
+			name:     "known tenant",
+			tenant:   "tenant-a",
+			expected: tenantSalted,
+		},
+		{
+			name:     "unknown tenant",
+			tenant:   "tenant-b",
+			expected: defaultSalted,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut, err := newSUT(t).ForTenant(cases[i].tenant)
+				if err != nil {
+					t.Fatalf(err.Error())
+				}
+
+				result := sut.Derive([]byte("on"))
+
+				assert.Equal(t, cases[i].expected, result)
+			},
+		)
+	}
+}
+
+// TestProvider_DeriveFromReader tests provider.DeriveFromReader.
+func TestProvider_DeriveFromReader(t *testing.T) {
+	sut := newSUT(t)
+	test.CheckDeriveFromReader(t, sut)
+
+	// The streamed data is salted with the default salt
+	result, err := sut.DeriveFromReader(bytes.NewReader([]byte("on")))
+	assert.NoError(t, err)
+	assert.Equal(t, defaultSalted, result)
+}
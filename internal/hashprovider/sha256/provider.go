@@ -16,6 +16,7 @@ package sha256
 import (
 	crypto "crypto/sha256"
 	"encoding/hex"
+	"io"
 )
 
 // provider is a receiver that encapsulates required dependencies.
@@ -33,3 +34,12 @@ func (*provider) Derive(data []byte) string {
 	hex.Encode(hashEncoded, h[:])
 	return string(hashEncoded)
 }
+
+// DeriveFromReader converts the data read from r to an identity value without buffering it.
+func (*provider) DeriveFromReader(r io.Reader) (string, error) {
+	h := crypto.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,131 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package tree
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/merkle"
+)
+
+const defaultChunkSize = 1 << 20
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct {
+	chunkSize int
+	workers   int
+	threshold int
+}
+
+// New is a factory function that returns an initialized provider. The chunk size determines the resulting hash
+// values, whereas the workers and threshold only affect how quickly they are derived.
+func New(cfg config.TreeHashInfo) (*provider, error) {
+	if cfg.ChunkSize < 0 || cfg.Workers < 0 || cfg.Threshold < 0 {
+		return nil, fmt.Errorf("invalid tree hash chunk size %v, workers %v or threshold %v",
+			cfg.ChunkSize, cfg.Workers, cfg.Threshold)
+	}
+	p := provider{chunkSize: cfg.ChunkSize, workers: cfg.Workers, threshold: cfg.Threshold}
+	if p.chunkSize == 0 {
+		p.chunkSize = defaultChunkSize
+	}
+	if p.workers == 0 {
+		p.workers = runtime.NumCPU()
+	}
+	return &p, nil
+}
+
+// Derive converts data to an identity value, hashing its chunks concurrently when the data is above the threshold.
+func (p *provider) Derive(data []byte) string {
+	var chunks [][]byte
+	for i := 0; i < len(data); i += p.chunkSize {
+		chunks = append(chunks, data[i:min(i+p.chunkSize, len(data))])
+	}
+	if len(chunks) == 0 {
+		chunks = [][]byte{data}
+	}
+
+	leaves := make([][]byte, len(chunks))
+	if len(chunks) == 1 || p.workers == 1 || len(data) < p.threshold {
+		for i, chunk := range chunks {
+			leaves[i] = merkle.LeafHash(chunk)
+		}
+		return root(leaves)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(p.workers, len(chunks)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				leaves[i] = merkle.LeafHash(chunks[i])
+			}
+		}()
+	}
+	for i := range chunks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return root(leaves)
+}
+
+// DeriveFromReader converts the data read from r to an identity value. Chunks are hashed concurrently as they are
+// read, holding at most one chunk per worker in memory.
+func (p *provider) DeriveFromReader(r io.Reader) (string, error) {
+	var leaves []*[]byte
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, p.workers)
+	for {
+		buf := make([]byte, p.chunkSize)
+		n, err := io.ReadFull(r, buf)
+		// An empty payload is hashed as a single empty chunk
+		if n > 0 || len(leaves) == 0 {
+			leaf := new([]byte)
+			leaves = append(leaves, leaf)
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(chunk []byte) {
+				defer wg.Done()
+				*leaf = merkle.LeafHash(chunk)
+				<-sem
+			}(buf[:n])
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			wg.Wait()
+			return "", err
+		}
+	}
+	wg.Wait()
+
+	hashes := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		hashes[i] = *leaf
+	}
+	return root(hashes), nil
+}
+
+// root combines the chunk hashes, of which there is always at least one
+func root(leaves [][]byte) string {
+	t, _ := merkle.NewFromLeaves(leaves)
+	return t.Root()
+}
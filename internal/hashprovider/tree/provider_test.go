@@ -0,0 +1,155 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package tree
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.TreeHashInfo
+		expectError bool
+	}{
+		{"defaults", config.TreeHashInfo{}, false},
+		{"tuned", config.TreeHashInfo{ChunkSize: 4096, Workers: 4, Threshold: 1 << 20}, false},
+		{"invalid chunk size", config.TreeHashInfo{ChunkSize: -1}, true},
+		{"invalid workers", config.TreeHashInfo{Workers: -1}, true},
+		{"invalid threshold", config.TreeHashInfo{Threshold: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+// TestProvider_Derive tests provider.Derive.
+func TestProvider_Derive(t *testing.T) {
+	cases := []struct {
+		name     string
+		cfg      config.TreeHashInfo
+		data     []byte
+		expected string
+	}{
+		{
+			name:     "single chunk",
+			data:     []byte("foo"),
+			expected: "1d2039fa7971f4bf01a1c20cb2a3fe7af46865ca9cd9b840c2063df8fec4ff75",
+		},
+		{
+			name:     "multiple chunks",
+			cfg:      config.TreeHashInfo{ChunkSize: 1},
+			data:     []byte("foo"),
+			expected: "4c6c7390ac57bba5a65130c3a7a4edef1b011407fc84dce0d11f648815f29d4e",
+		},
+		{
+			name:     "empty",
+			data:     []byte{},
+			expected: "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut, err := New(cases[i].cfg)
+				if err != nil {
+					t.Fatalf(err.Error())
+				}
+
+				result := sut.Derive(cases[i].data)
+
+				assert.Equal(t, cases[i].expected, result)
+			},
+		)
+	}
+}
+
+// TestProvider_DeriveFromReader tests provider.DeriveFromReader.
+func TestProvider_DeriveFromReader(t *testing.T) {
+	sut, err := New(config.TreeHashInfo{ChunkSize: 4096, Workers: 4})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	test.CheckDeriveFromReader(t, sut)
+}
+
+// TestProvider_Concurrency verifies that the hash value does not depend on how the chunks are scheduled.
+func TestProvider_Concurrency(t *testing.T) {
+	data := make([]byte, 1<<20+123)
+	rand.Read(data)
+
+	sequential, err := New(config.TreeHashInfo{ChunkSize: 4096, Workers: 1})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	expected := sequential.Derive(data)
+
+	tests := []struct {
+		name string
+		cfg  config.TreeHashInfo
+	}{
+		{"parallel", config.TreeHashInfo{ChunkSize: 4096, Workers: 8}},
+		{"below threshold", config.TreeHashInfo{ChunkSize: 4096, Workers: 8, Threshold: 2 << 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sut, err := New(tt.cfg)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Equal(t, expected, sut.Derive(data))
+
+			result, err := sut.DeriveFromReader(bytes.NewReader(data))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, result)
+		})
+	}
+}
+
+func BenchmarkProvider_Derive(b *testing.B) {
+	data := make([]byte, 64<<20)
+	rand.Read(data)
+
+	b.Run("sha256", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			sha256.Sum256(data)
+		}
+	})
+	for _, workers := range []int{1, 2, 4, 8} {
+		sut, err := New(config.TreeHashInfo{Workers: workers})
+		if err != nil {
+			b.Fatalf(err.Error())
+		}
+		b.Run(fmt.Sprintf("tree-%v-workers", workers), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				sut.Derive(data)
+			}
+		})
+	}
+}
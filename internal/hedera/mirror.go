@@ -0,0 +1,174 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package hedera
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+)
+
+const (
+	defaultMirrorTimeout      = 30 * time.Second
+	defaultMirrorPollInterval = 500 * time.Millisecond
+)
+
+// consensusRecord describes where a submitted message was placed in its topic once it reached consensus
+type consensusRecord struct {
+	TopicId            string
+	SequenceNumber     uint64
+	RunningHash        []byte
+	ConsensusTimestamp time.Time
+}
+
+// topicMessage is the subset of the mirror node REST representation of a topic message used for verification
+type topicMessage struct {
+	ConsensusTimestamp string `json:"consensus_timestamp"`
+	RunningHash        []byte `json:"running_hash"`
+	SequenceNumber     uint64 `json:"sequence_number"`
+	TopicId            string `json:"topic_id"`
+}
+
+// mirrorClient queries a mirror node to confirm that messages submitted to the consensus service were accepted
+type mirrorClient struct {
+	url          string
+	timeout      time.Duration
+	pollInterval time.Duration
+	client       *http.Client
+}
+
+func newMirrorClient(cfg config.HederaMirrorInfo) (*mirrorClient, error) {
+	if cfg.Timeout < 0 || cfg.PollInterval < 0 {
+		return nil, fmt.Errorf("invalid mirror node timeout %v or poll interval %v", cfg.Timeout, cfg.PollInterval)
+	}
+	m := mirrorClient{
+		url:          strings.TrimSuffix(cfg.Url, "/"),
+		timeout:      defaultMirrorTimeout,
+		pollInterval: defaultMirrorPollInterval,
+		client:       &http.Client{},
+	}
+	if cfg.Timeout > 0 {
+		m.timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	if cfg.PollInterval > 0 {
+		m.pollInterval = time.Duration(cfg.PollInterval) * time.Millisecond
+	}
+	return &m, nil
+}
+
+// verify polls the mirror node until the message with the given sequence number is available, then checks that it
+// matches the running hash returned in the transaction receipt. Mirror nodes lag consensus by a few seconds so a
+// missing message is retried until the timeout elapses or the parent context is done.
+func (m *mirrorClient) verify(parent context.Context, topicId string, sequenceNumber uint64,
+	runningHash []byte) (consensusRecord, error) {
+	ctx, cancel := context.WithTimeout(parent, m.timeout)
+	defer cancel()
+
+	for {
+		msg, found, err := m.topicMessage(ctx, topicId, sequenceNumber)
+		if err != nil {
+			return consensusRecord{}, err
+		}
+		if found {
+			if msg.SequenceNumber != sequenceNumber {
+				return consensusRecord{}, fmt.Errorf("mirror node returned sequence number %v, expected %v",
+					msg.SequenceNumber, sequenceNumber)
+			}
+			if !bytes.Equal(msg.RunningHash, runningHash) {
+				return consensusRecord{}, fmt.Errorf("running hash mismatch for topic %s sequence number %v",
+					topicId, sequenceNumber)
+			}
+			timestamp, err := parseConsensusTimestamp(msg.ConsensusTimestamp)
+			if err != nil {
+				return consensusRecord{}, err
+			}
+			return consensusRecord{
+				TopicId:            topicId,
+				SequenceNumber:     sequenceNumber,
+				RunningHash:        runningHash,
+				ConsensusTimestamp: timestamp,
+			}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err := parent.Err(); err != nil {
+				return consensusRecord{}, err
+			}
+			return consensusRecord{}, fmt.Errorf("message %v on topic %s not found on mirror node within %s",
+				sequenceNumber, topicId, m.timeout)
+		case <-time.After(m.pollInterval):
+		}
+	}
+}
+
+// topicMessage fetches a single topic message, reporting whether the mirror node has it yet
+func (m *mirrorClient) topicMessage(ctx context.Context, topicId string, sequenceNumber uint64) (topicMessage, bool, error) {
+	url := fmt.Sprintf("%s/api/v1/topics/%s/messages/%v", m.url, topicId, sequenceNumber)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return topicMessage{}, false, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		// The deadline is reported by the caller
+		if ctx.Err() != nil {
+			return topicMessage{}, false, nil
+		}
+		return topicMessage{}, false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return topicMessage{}, false, nil
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return topicMessage{}, false, fmt.Errorf("mirror node returned %s %s", resp.Status, string(body))
+	}
+
+	var msg topicMessage
+	if err = json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		return topicMessage{}, false, err
+	}
+	return msg, true, nil
+}
+
+// parseConsensusTimestamp converts the mirror node's seconds.nanoseconds representation to a time
+func parseConsensusTimestamp(value string) (time.Time, error) {
+	seconds, nanos, _ := strings.Cut(value, ".")
+	s, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid consensus timestamp %s", value)
+	}
+	var n int64
+	if nanos != "" {
+		if n, err = strconv.ParseInt(nanos, 10, 64); err != nil {
+			return time.Time{}, fmt.Errorf("invalid consensus timestamp %s", value)
+		}
+	}
+	return time.Unix(s, n).UTC(), nil
+}
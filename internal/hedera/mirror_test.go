@@ -0,0 +1,104 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package hedera
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirrorClient_Verify(t *testing.T) {
+	runningHash := []byte("running-hash")
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first query lands before the mirror node has ingested the message
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/topics/0.0.1234/messages/7":
+			json.NewEncoder(w).Encode(topicMessage{
+				ConsensusTimestamp: "1700000000.000000123",
+				RunningHash:        runningHash,
+				SequenceNumber:     7,
+				TopicId:            "0.0.1234",
+			})
+		case "/api/v1/topics/0.0.1234/messages/8":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		sequenceNumber uint64
+		runningHash    []byte
+		expectError    bool
+	}{
+		{"verified", 7, runningHash, false},
+		{"running hash mismatch", 7, []byte("other"), true},
+		{"server error", 8, runningHash, true},
+		{"not found", 9, runningHash, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			m, err := newMirrorClient(config.HederaMirrorInfo{Url: server.URL + "/", Timeout: 1, PollInterval: 10})
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			record, err := m.verify(context.Background(), "0.0.1234", tt.sequenceNumber, tt.runningHash)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, "0.0.1234", record.TopicId)
+			assert.Equal(t, tt.sequenceNumber, record.SequenceNumber)
+			assert.Equal(t, time.Unix(1700000000, 123).UTC(), record.ConsensusTimestamp)
+		})
+	}
+}
+
+func TestParseConsensusTimestamp(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{"seconds and nanoseconds", "1700000000.000000001", time.Unix(1700000000, 1).UTC(), false},
+		{"seconds only", "1700000000", time.Unix(1700000000, 0).UTC(), false},
+		{"invalid", "yesterday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseConsensusTimestamp(tt.value)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -15,11 +15,14 @@
 package hedera
 
 import (
-	"encoding/json"
+	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/hashgraph/hedera-sdk-go/v2"
 	"github.com/project-alvarium/alvarium-sdk-go/internal/mqtt"
@@ -34,12 +37,25 @@ type HederaPublisher struct {
 	logger          interfaces.Logger
 	hederaClient    *hedera.Client
 	broadcastStream interfaces.StreamProvider
+	mirror          *mirrorClient
+	adminKey        *hedera.PrivateKey
+	submitKey       *hedera.PrivateKey
 }
 
 func NewHederaPublisher(
 	cfg config.HederaConfig,
 	logger interfaces.Logger,
 ) (interfaces.StreamProvider, error) {
+	if cfg.MaxSubmitFee < 0 || cfg.Topic.MaxCreateFee < 0 {
+		return nil, errors.New("max fees cannot be negative")
+	}
+	if cfg.MaxMessageSize < 0 || cfg.MaxChunks < 0 {
+		return nil, fmt.Errorf("invalid max message size %v or max chunks %v", cfg.MaxMessageSize, cfg.MaxChunks)
+	}
+	if cfg.MaxMessageSize == 0 {
+		cfg.MaxMessageSize = defaultMaxMessageSize
+	}
+
 	client, err := initHederaClient(cfg)
 	if err != nil {
 		return nil, err
@@ -50,17 +66,45 @@ func NewHederaPublisher(
 		logger:       logger,
 		hederaClient: client,
 	}
+	if cfg.MirrorNode.Url != "" {
+		p.mirror, err = newMirrorClient(cfg.MirrorNode)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Topic.AdminKeyPath != "" {
+		key, err := readPrivateKey(cfg.Topic.AdminKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		p.adminKey = &key
+	}
+	if cfg.Topic.SubmitKeyPath != "" {
+		key, err := readPrivateKey(cfg.Topic.SubmitKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		p.submitKey = &key
+	}
 	return &p, nil
 }
 
 // hedera client implicitly connects to the hedera net.
 // no need for manual initiation. Instead, topics used to
-// publish annotations will be broadcasted according to
-// configuration
-func (p *HederaPublisher) Connect() error {
+// publish annotations will be created and broadcasted
+// according to configuration
+func (p *HederaPublisher) Connect(ctx context.Context) error {
+	if p.cfg.Topic.AutoCreate && len(p.cfg.Topics) == 0 {
+		topicId, err := p.createTopic()
+		if err != nil {
+			return err
+		}
+		p.cfg.Topics = []string{topicId.String()}
+	}
+
 	if p.cfg.ShouldBroadcastTopic {
 
-		stream, err := initBroadcastStream(p.cfg, p.logger)
+		stream, err := initBroadcastStream(ctx, p.cfg, p.logger)
 		if err != nil {
 			return err
 		}
@@ -72,7 +116,7 @@ func (p *HederaPublisher) Connect() error {
 				MessageType: fmt.Sprintf("%T", topic),
 				Content:     []byte(topic),
 			}
-			err := p.broadcastStream.Publish(msg)
+			err := p.broadcastStream.Publish(ctx, msg)
 			if err != nil {
 				return err
 			}
@@ -82,30 +126,127 @@ func (p *HederaPublisher) Connect() error {
 	return nil
 }
 
-func (p *HederaPublisher) Publish(msg message.PublishWrapper) error {
-	b, _ := json.Marshal(msg)
+func (p *HederaPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	payloads, err := splitMessage(msg, p.cfg.MaxMessageSize)
+	if err != nil {
+		return err
+	}
 
 	// publish to all topic IDs
 	for _, topic := range p.cfg.Topics {
-		p.logger.Write(
-			slog.LevelDebug,
-			fmt.Sprintf("attempting publish, topic %s %s", topic, string(b)),
-		)
 		topicId, err := hedera.TopicIDFromString(topic)
 		if err != nil {
 			return err
 		}
 
-		// submit message to consensus service
-		_, err = hedera.NewTopicMessageSubmitTransaction().
-			SetMessage(b).
-			SetTopicID(topicId).
-			Execute(p.hederaClient)
+		for _, b := range payloads {
+			p.logger.Write(
+				slog.LevelDebug,
+				fmt.Sprintf("attempting publish, topic %s %s", topic, string(b)),
+			)
+			err = p.submit(ctx, topicId, b)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// submit sends a single message to the consensus service, signing it with the topic's submit key if one is
+// configured. The Hedera client cannot be interrupted, so the context is checked before submitting and bounds the
+// mirror node verification.
+func (p *HederaPublisher) submit(ctx context.Context, topicId hedera.TopicID, b []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx := hedera.NewTopicMessageSubmitTransaction().
+		SetMessage(b).
+		SetTopicID(topicId)
+	if p.cfg.MaxSubmitFee > 0 {
+		tx.SetMaxTransactionFee(hedera.HbarFrom(p.cfg.MaxSubmitFee, hedera.HbarUnits.Hbar))
+	}
+	if p.cfg.MaxChunks > 0 {
+		tx.SetMaxChunks(uint64(p.cfg.MaxChunks))
+	}
+	if p.submitKey != nil {
+		frozen, err := tx.FreezeWith(p.hederaClient)
 		if err != nil {
 			return err
 		}
+		tx = frozen.Sign(*p.submitKey)
 	}
 
+	// submit message to consensus service
+	resp, err := tx.Execute(p.hederaClient)
+	if err != nil {
+		return err
+	}
+
+	if p.mirror != nil {
+		return p.verify(ctx, topicId, resp)
+	}
+	return nil
+}
+
+// createTopic creates a consensus topic governed by the configured admin and submit keys
+func (p *HederaPublisher) createTopic() (hedera.TopicID, error) {
+	tx := hedera.NewTopicCreateTransaction().
+		SetTopicMemo(p.cfg.Topic.Memo)
+	if p.cfg.Topic.MaxCreateFee > 0 {
+		tx.SetMaxTransactionFee(hedera.HbarFrom(p.cfg.Topic.MaxCreateFee, hedera.HbarUnits.Hbar))
+	}
+	if p.submitKey != nil {
+		tx.SetSubmitKey(p.submitKey.PublicKey())
+	}
+	// The admin key must sign the creation in addition to the operator
+	if p.adminKey != nil {
+		frozen, err := tx.SetAdminKey(p.adminKey.PublicKey()).FreezeWith(p.hederaClient)
+		if err != nil {
+			return hedera.TopicID{}, err
+		}
+		tx = frozen.Sign(*p.adminKey)
+	}
+
+	resp, err := tx.Execute(p.hederaClient)
+	if err != nil {
+		return hedera.TopicID{}, err
+	}
+	receipt, err := resp.GetReceipt(p.hederaClient)
+	if err != nil {
+		return hedera.TopicID{}, err
+	}
+	if receipt.TopicID == nil {
+		return hedera.TopicID{}, errors.New("topic create receipt did not include a topic ID")
+	}
+
+	p.logger.Write(slog.LevelInfo, fmt.Sprintf("created topic %s", receipt.TopicID.String()))
+	return *receipt.TopicID, nil
+}
+
+// verify confirms through the mirror node that the submitted message reached consensus with the sequence number
+// and running hash from its receipt. The consensus timestamp is logged alongside the transaction ID so that
+// applications can correlate annotations with the ledger in their audit records.
+func (p *HederaPublisher) verify(ctx context.Context, topicId hedera.TopicID, resp hedera.TransactionResponse) error {
+	receipt, err := resp.GetReceipt(p.hederaClient)
+	if err != nil {
+		return err
+	}
+
+	record, err := p.mirror.verify(ctx, topicId.String(), receipt.TopicSequenceNumber, receipt.TopicRunningHash)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Write(
+		slog.LevelInfo,
+		fmt.Sprintf("message reached consensus, topic %s sequence number %v", record.TopicId, record.SequenceNumber),
+		slog.String("transaction-id", resp.TransactionID.String()),
+		slog.String("consensus-timestamp", record.ConsensusTimestamp.Format(time.RFC3339Nano)),
+		slog.String("running-hash", hex.EncodeToString(record.RunningHash)),
+	)
 	return nil
 }
 
@@ -119,7 +260,7 @@ func (p *HederaPublisher) Close() error {
 				MessageType: fmt.Sprintf("%T", topic),
 				Content:     []byte(topic),
 			}
-			err := p.broadcastStream.Publish(msg)
+			err := p.broadcastStream.Publish(context.Background(), msg)
 			if err != nil {
 				return err
 			}
@@ -149,7 +290,12 @@ func initHederaClient(cfg config.HederaConfig) (*hedera.Client, error) {
 		return nil, err
 	}
 
-	privateKey, err := readPrivateKey(cfg)
+	var privateKey hedera.PrivateKey
+	if cfg.PrivateKey != "" {
+		privateKey, err = hedera.PrivateKeyFromStringDer(strings.TrimSpace(cfg.PrivateKey))
+	} else {
+		privateKey, err = readPrivateKey(cfg.PrivateKeyPath)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -164,8 +310,8 @@ func initHederaClient(cfg config.HederaConfig) (*hedera.Client, error) {
 	return client, nil
 }
 
-func readPrivateKey(cfg config.HederaConfig) (hedera.PrivateKey, error) {
-	b, err := os.ReadFile(cfg.PrivateKeyPath)
+func readPrivateKey(path string) (hedera.PrivateKey, error) {
+	b, err := os.ReadFile(path)
 	if err != nil {
 		return hedera.PrivateKey{}, err
 	}
@@ -207,12 +353,16 @@ func setDefaultValues(client *hedera.Client, cfg config.HederaConfig) error {
 }
 
 func initBroadcastStream(
+	ctx context.Context,
 	cfg config.HederaConfig,
 	logger interfaces.Logger,
 ) (interfaces.StreamProvider, error) {
-	stream := mqtt.NewMqttPublisher(cfg.BroadcastStream, logger)
+	stream, err := mqtt.NewMqttPublisher(cfg.BroadcastStream, logger)
+	if err != nil {
+		return nil, err
+	}
 
-	err := stream.Connect()
+	err = stream.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,72 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package hedera
+
+import (
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+// defaultMaxMessageSize matches the chunk size of the consensus service
+const defaultMaxMessageSize = 1024
+
+// splitMessage serializes the wrapper, dividing an annotation list that would exceed maxSize across several
+// wrappers that each fit. Every resulting message is self-contained so that subscribers do not need to reassemble
+// chunks. A single annotation that is still too large is left to the consensus service's own chunking.
+func splitMessage(msg message.PublishWrapper, maxSize int) ([][]byte, error) {
+	b, err := message.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) <= maxSize {
+		return [][]byte{b}, nil
+	}
+
+	var list contracts.AnnotationList
+	if err = message.UnmarshalContent(msg.Content, msg.ContentType, &list); err != nil || len(list.Items) <= 1 {
+		return [][]byte{b}, nil
+	}
+
+	var payloads [][]byte
+	var current []contracts.Annotation
+	var last []byte
+	for _, item := range list.Items {
+		candidate, err := marshalList(msg, append(current, item))
+		if err != nil {
+			return nil, err
+		}
+		if len(candidate) > maxSize && len(current) > 0 {
+			payloads = append(payloads, last)
+			current = []contracts.Annotation{item}
+			if candidate, err = marshalList(msg, current); err != nil {
+				return nil, err
+			}
+		} else {
+			current = append(current, item)
+		}
+		last = candidate
+	}
+	return append(payloads, last), nil
+}
+
+// marshalList serializes a copy of the wrapper carrying the given annotations
+func marshalList(msg message.PublishWrapper, items []contracts.Annotation) ([]byte, error) {
+	content, err := message.MarshalContent(contracts.AnnotationList{Items: items}, msg.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	msg.Content = content
+	return message.Marshal(msg)
+}
@@ -0,0 +1,81 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package hedera
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitMessage(t *testing.T) {
+	var items []contracts.Annotation
+	for i := 0; i < 10; i++ {
+		items = append(items, contracts.NewAnnotation(fmt.Sprintf("key-%v", i), contracts.SHA256Hash, "host",
+			contracts.Application, contracts.AnnotationTPM, true))
+	}
+	list, _ := json.Marshal(contracts.AnnotationList{Items: items})
+	single, _ := json.Marshal(contracts.AnnotationList{Items: items[:1]})
+
+	tests := []struct {
+		name          string
+		content       []byte
+		maxSize       int
+		expectedCount int
+	}{
+		{"fits", list, 64 * 1024, 1},
+		{"split list", list, defaultMaxMessageSize, 4},
+		{"single oversized annotation", single, 64, 1},
+		{"not an annotation list", make([]byte, 2048), defaultMaxMessageSize, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := message.PublishWrapper{Action: message.ActionCreate, MessageType: "contracts.AnnotationList",
+				Content: tt.content}
+			payloads, err := splitMessage(msg, tt.maxSize)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Len(t, payloads, tt.expectedCount)
+
+			// Every annotation should be delivered exactly once and in order
+			if tt.expectedCount == 1 {
+				return
+			}
+			var received []contracts.Annotation
+			for _, b := range payloads {
+				assert.LessOrEqual(t, len(b), tt.maxSize)
+				var wrapper message.PublishWrapper
+				if err = json.Unmarshal(b, &wrapper); err != nil {
+					t.Fatalf(err.Error())
+				}
+				assert.Equal(t, msg.Action, wrapper.Action)
+				var l contracts.AnnotationList
+				if err = json.Unmarshal(wrapper.Content, &l); err != nil {
+					t.Fatalf(err.Error())
+				}
+				received = append(received, l.Items...)
+			}
+			assert.Equal(t, len(items), len(received))
+			for i := range items {
+				assert.Equal(t, items[i].Id, received[i].Id)
+			}
+		})
+	}
+}
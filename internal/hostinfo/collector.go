@@ -0,0 +1,185 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package hostinfo
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/tags"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// osReleasePath is the file identifying the operating system, see os-release(5)
+const osReleasePath = "/etc/os-release"
+
+// Collector gathers the attributes of the host attached to annotations or publish wrappers. The attributes of the
+// local host are read once, the region and instance are read from the metadata service of the cloud until they are
+// read successfully.
+type Collector struct {
+	fields   config.EnrichmentInfo
+	region   interfaces.TagValueGetter
+	instance interfaces.TagValueGetter
+	osPath   string
+
+	once  sync.Once
+	local contracts.HostInfo
+}
+
+// NewCollector returns a Collector gathering the attributes configured in cfg
+func NewCollector(cfg config.EnrichmentInfo) (*Collector, error) {
+	c := Collector{fields: cfg, osPath: osReleasePath}
+	if cfg.Includes(contracts.EnrichRegion) {
+		region, err := regionTag(cfg.Cloud)
+		if err != nil {
+			return nil, err
+		}
+		c.region = region
+	}
+	if cfg.Includes(contracts.EnrichInstance) {
+		instance, err := tags.NewMetadataTag(cfg.Cloud, "")
+		if err != nil {
+			return nil, err
+		}
+		c.instance = instance
+	}
+	return &c, nil
+}
+
+// regionTag reads the region of the instance from the metadata service of the cloud
+func regionTag(cloud contracts.TagSourceType) (interfaces.TagValueGetter, error) {
+	switch cloud {
+	case contracts.TagSourceEC2:
+		return tags.NewMetadataTag(cloud, "placement/region")
+	case contracts.TagSourceAzure:
+		return tags.NewMetadataTag(cloud, "location")
+	}
+	// GCE only exposes the zone, as projects/<number>/zones/<region>-<zone>
+	zone, err := tags.NewMetadataTag(cloud, "zone")
+	if err != nil {
+		return nil, err
+	}
+	return interfaces.TagValueGetterFunc(func(layer contracts.LayerType) contracts.TagList {
+		var regions contracts.TagList
+		for _, z := range zone.GetTagValue(layer) {
+			regions = append(regions, zoneRegion(z))
+		}
+		return regions
+	}), nil
+}
+
+// zoneRegion returns the region of a GCE zone, such as us-central1 for projects/42/zones/us-central1-a
+func zoneRegion(zone string) string {
+	zone = zone[strings.LastIndex(zone, "/")+1:]
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+	return zone
+}
+
+// HostInfo returns the configured attributes of the host, those that could not be read are left empty
+func (c *Collector) HostInfo() *contracts.HostInfo {
+	c.once.Do(c.readLocal)
+	info := c.local
+	if c.region != nil {
+		info.Region = first(c.region.GetTagValue(contracts.Host))
+	}
+	if c.instance != nil {
+		info.InstanceId = first(c.instance.GetTagValue(contracts.Host))
+	}
+	return &info
+}
+
+func first(values contracts.TagList) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// readLocal reads the attributes of the local host
+func (c *Collector) readLocal() {
+	if c.fields.Includes(contracts.EnrichAddresses) || c.fields.Includes(contracts.EnrichMACs) {
+		addresses, macs := interfaceAddresses()
+		if c.fields.Includes(contracts.EnrichAddresses) {
+			c.local.Addresses = addresses
+		}
+		if c.fields.Includes(contracts.EnrichMACs) {
+			c.local.MACs = macs
+		}
+	}
+	if c.fields.Includes(contracts.EnrichOs) {
+		c.local.OsRelease = osRelease(c.osPath)
+	}
+}
+
+// interfaceAddresses returns the IP and hardware addresses of the network interfaces that are up, loopback excluded
+func interfaceAddresses() (addresses []string, macs []string) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, nil
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if len(iface.HardwareAddr) > 0 {
+			macs = append(macs, iface.HardwareAddr.String())
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ip, ok := addr.(*net.IPNet); ok {
+				addresses = append(addresses, ip.IP.String())
+			}
+		}
+	}
+	return addresses, macs
+}
+
+// osRelease returns the PRETTY_NAME of the os-release file at path, or the operating system Go reports without it
+func osRelease(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return runtime.GOOS
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "PRETTY_NAME=")
+		if !ok {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `'"`)
+		}
+		if value != "" {
+			return value
+		}
+	}
+	return runtime.GOOS
+}
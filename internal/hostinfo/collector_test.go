@@ -0,0 +1,94 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package hostinfo
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/tags"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector_HostInfo(t *testing.T) {
+	osPath := filepath.Join(t.TempDir(), "os-release")
+	err := os.WriteFile(osPath, []byte("NAME=\"Alpine Linux\"\nPRETTY_NAME=\"Alpine Linux v3.20\"\n"), 0644)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name     string
+		fields   []contracts.EnrichmentField
+		expected contracts.HostInfo
+	}{
+		{"os", []contracts.EnrichmentField{contracts.EnrichOs}, contracts.HostInfo{OsRelease: "Alpine Linux v3.20"}},
+		{"cloud", []contracts.EnrichmentField{contracts.EnrichRegion, contracts.EnrichInstance},
+			contracts.HostInfo{Region: "us-east-1", InstanceId: "i-0abc"}},
+		{"none", nil, contracts.HostInfo{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Collector{fields: config.EnrichmentInfo{Fields: tt.fields}, osPath: osPath}
+			if c.fields.Includes(contracts.EnrichRegion) {
+				c.region = tags.StaticTag{"us-east-1"}
+			}
+			if c.fields.Includes(contracts.EnrichInstance) {
+				c.instance = tags.StaticTag{"i-0abc"}
+			}
+			assert.Equal(t, &tt.expected, c.HostInfo())
+		})
+	}
+}
+
+func TestNewCollector(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.EnrichmentInfo
+		expectError bool
+	}{
+		{"local", config.EnrichmentInfo{Fields: []contracts.EnrichmentField{contracts.EnrichAddresses,
+			contracts.EnrichMACs}}, false},
+		{"gce", config.EnrichmentInfo{Fields: []contracts.EnrichmentField{contracts.EnrichRegion,
+			contracts.EnrichInstance}, Cloud: contracts.TagSourceGCE}, false},
+		{"cloud missing", config.EnrichmentInfo{Fields: []contracts.EnrichmentField{contracts.EnrichRegion}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCollector(tt.cfg)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestZoneRegion(t *testing.T) {
+	assert.Equal(t, "us-central1", zoneRegion("projects/42/zones/us-central1-a"))
+	assert.Equal(t, "europe-west4", zoneRegion("europe-west4-b"))
+}
+
+func TestOsRelease(t *testing.T) {
+	dir := t.TempDir()
+	unquoted := filepath.Join(dir, "unquoted")
+	_ = os.WriteFile(unquoted, []byte("ID=debian\nPRETTY_NAME=Debian\n"), 0644)
+	missing := filepath.Join(dir, "missing")
+
+	assert.Equal(t, "Debian", osRelease(unquoted))
+	assert.Equal(t, runtime.GOOS, osRelease(missing))
+}
@@ -0,0 +1,71 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package ids
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// ULIDGenerator generates ULIDs with monotonic entropy, see contracts.NewULID
+type ULIDGenerator struct{}
+
+func (g ULIDGenerator) NewId(contracts.Annotation) ulid.ULID {
+	return contracts.NewULID()
+}
+
+// UUIDv7Generator generates version 7 UUIDs as laid out by RFC 9562. Their first 48 bits are a millisecond timestamp,
+// as in a ULID, so they sort by time in either text form.
+type UUIDv7Generator struct{}
+
+func (g UUIDv7Generator) NewId(contracts.Annotation) ulid.ULID {
+	var id ulid.ULID
+	ms := uint64(time.Now().UnixMilli())
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], ms)
+	copy(id[:6], ts[2:])
+	_, _ = rand.Read(id[6:])
+	id[6] = 0x70 | (id[6] & 0x0f) // version 7
+	id[8] = 0x80 | (id[8] & 0x3f) // RFC 9562 variant
+	return id
+}
+
+// DeterministicGenerator derives the identifier of an annotation from what it attests to, so that annotating the same
+// data again, such as when republishing it, yields the same identifier and consumers can tell the copies apart from
+// new annotations. The timestamp is deliberately left out.
+type DeterministicGenerator struct{}
+
+func (g DeterministicGenerator) NewId(a contracts.Annotation) ulid.ULID {
+	b, _ := json.Marshal(struct {
+		Key       string
+		ParentKey string
+		Hash      contracts.HashType
+		Host      string
+		Tag       contracts.TagList
+		Layer     contracts.LayerType
+		Kind      contracts.AnnotationType
+		Satisfied bool
+	}{a.Key, a.ParentKey, a.Hash, a.Host, a.Tag, a.Layer, a.Kind, a.IsSatisfied})
+	sum := sha256.Sum256(b)
+
+	var id ulid.ULID
+	copy(id[:], sum[:])
+	return id
+}
@@ -0,0 +1,76 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package ids
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestULIDGenerator(t *testing.T) {
+	a := contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationSource, true)
+
+	// ULIDs made within the same millisecond sort in the order they were made
+	var g ULIDGenerator
+	previous := g.NewId(a)
+	for i := 0; i < 1000; i++ {
+		id := g.NewId(a)
+		assert.Equal(t, 1, id.Compare(previous))
+		previous = id
+	}
+}
+
+func TestUUIDv7Generator(t *testing.T) {
+	a := contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationSource, true)
+
+	var g UUIDv7Generator
+	before := time.Now().UnixMilli()
+	id := g.NewId(a)
+	assert.Equal(t, byte(0x70), id[6]&0xf0, "version")
+	assert.Equal(t, byte(0x80), id[8]&0xc0, "variant")
+	assert.GreaterOrEqual(t, int64(id.Time()), before)
+	assert.LessOrEqual(t, int64(id.Time()), time.Now().UnixMilli())
+	assert.NotEqual(t, id, g.NewId(a))
+
+	parsed, err := ulid.ParseStrict(id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, id, parsed)
+}
+
+func TestDeterministicGenerator(t *testing.T) {
+	a := contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationSource, true)
+	republished := a
+	republished.Timestamp = a.Timestamp.Add(time.Minute)
+	other := a
+	other.Key = "bar"
+
+	tests := []struct {
+		name       string
+		annotation contracts.Annotation
+		same       bool
+	}{
+		{"republished", republished, true},
+		{"other data", other, false},
+	}
+	var g DeterministicGenerator
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.same, g.NewId(a) == g.NewId(tt.annotation))
+		})
+	}
+}
@@ -0,0 +1,194 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package iota
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+const (
+	infoRoute      = "/api/core/v2/info"
+	blocksRoute    = "/api/core/v2/blocks"
+	requestTimeout = 10 * time.Second
+
+	// Limits defined by the Stardust protocol for tagged data payloads
+	taggedDataType = 5
+	maxTagLength   = 64
+	maxBlockLength = 32768
+)
+
+type iotaPublisher struct {
+	cfg             config.IotaConfig
+	logger          interfaces.Logger
+	client          *http.Client
+	protocolVersion int
+}
+
+// nodeInfo contains the subset of the node's info response used by the publisher
+type nodeInfo struct {
+	Status struct {
+		IsHealthy bool `json:"isHealthy"`
+	} `json:"status"`
+	Protocol struct {
+		Version int `json:"version"`
+	} `json:"protocol"`
+}
+
+// block is a block submitted without parents or nonce. The node is responsible for tip selection and proof of work.
+type block struct {
+	ProtocolVersion int        `json:"protocolVersion"`
+	Payload         taggedData `json:"payload"`
+}
+
+type taggedData struct {
+	Type int    `json:"type"`
+	Tag  string `json:"tag"`
+	Data string `json:"data"`
+}
+
+func NewIotaPublisher(cfg config.IotaConfig, logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	if len(cfg.Tag) > maxTagLength {
+		return nil, fmt.Errorf("tag exceeds maximum length of %v bytes", maxTagLength)
+	}
+
+	p := iotaPublisher{
+		cfg:    cfg,
+		logger: logger,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+	return &p, nil
+}
+
+// Connect verifies the node is healthy and retrieves the protocol version that blocks must be submitted with
+func (p *iotaPublisher) Connect(ctx context.Context) error {
+	info, err := p.nodeInfo(ctx)
+	if err != nil {
+		return err
+	}
+	p.protocolVersion = info.Protocol.Version
+	return nil
+}
+
+// Healthy queries the node info, which reports whether the node is synced and able to accept blocks
+func (p *iotaPublisher) Healthy(ctx context.Context) error {
+	_, err := p.nodeInfo(ctx)
+	return err
+}
+
+func (p *iotaPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	b, _ := message.Marshal(msg)
+	if len(b) > maxBlockLength {
+		return fmt.Errorf("publish wrapper of %v bytes exceeds maximum block size", len(b))
+	}
+
+	blk := block{
+		ProtocolVersion: p.protocolVersion,
+		Payload: taggedData{
+			Type: taggedDataType,
+			Tag:  encodeHex([]byte(p.cfg.Tag)),
+			Data: encodeHex(b),
+		},
+	}
+	body, err := json.Marshal(blk)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Write(slog.LevelDebug, fmt.Sprintf("attempting publish, tag %s %s", p.cfg.Tag, string(b)))
+	req, err := p.newRequest(ctx, http.MethodPost, blocksRoute, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		detail, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected response from node %s %s", resp.Status, string(detail))
+	}
+
+	var created struct {
+		BlockId string `json:"blockId"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&created); err == nil {
+		p.logger.Write(slog.LevelDebug, fmt.Sprintf("block attached %s", created.BlockId))
+	}
+	return nil
+}
+
+func (p *iotaPublisher) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
+
+func (p *iotaPublisher) nodeInfo(ctx context.Context) (nodeInfo, error) {
+	req, err := p.newRequest(ctx, http.MethodGet, infoRoute, nil)
+	if err != nil {
+		return nodeInfo{}, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nodeInfo{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nodeInfo{}, fmt.Errorf("unexpected response from node info %s", resp.Status)
+	}
+
+	var info nodeInfo
+	if err = json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nodeInfo{}, err
+	}
+	if !info.Status.IsHealthy {
+		return nodeInfo{}, errors.New("iota node reports unhealthy status")
+	}
+	return info, nil
+}
+
+func (p *iotaPublisher) newRequest(ctx context.Context, method, route string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, p.cfg.Provider.Uri()+route, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if p.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.cfg.Token)
+	}
+	return req, nil
+}
+
+func encodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
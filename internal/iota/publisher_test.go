@@ -0,0 +1,97 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package iota
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIotaPublisher_Publish(t *testing.T) {
+	var received block
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case infoRoute:
+			w.Write([]byte(`{"status":{"isHealthy":true},"protocol":{"version":2}}`))
+		case blocksRoute:
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"blockId":"0x01"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	host, port, _ := net.SplitHostPort(u.Host)
+	portNum, _ := strconv.Atoi(port)
+	cfg := config.IotaConfig{
+		Provider: config.ServiceInfo{Host: host, Port: portNum, Protocol: u.Scheme},
+		Tag:      "alvarium",
+	}
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+
+	tests := []struct {
+		name        string
+		cfg         config.IotaConfig
+		expectError bool
+	}{
+		{"valid tag", cfg, false},
+		{"tag too long", config.IotaConfig{Tag: strings.Repeat("a", maxTagLength+1)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewIotaPublisher(tt.cfg, logger)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+			if err = p.Connect(context.Background()); err != nil {
+				t.Fatalf(err.Error())
+			}
+			defer p.Close()
+
+			msg := message.PublishWrapper{Action: message.ActionCreate, MessageType: "test", Content: []byte("data")}
+			if err = p.Publish(context.Background(), msg); err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			assert.Equal(t, 2, received.ProtocolVersion)
+			assert.Equal(t, taggedDataType, received.Payload.Type)
+			assert.Equal(t, "0x"+hex.EncodeToString([]byte("alvarium")), received.Payload.Tag)
+
+			b, _ := hex.DecodeString(strings.TrimPrefix(received.Payload.Data, "0x"))
+			var result message.PublishWrapper
+			json.Unmarshal(b, &result)
+			assert.Equal(t, msg, result)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package jws
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+const (
+	algEdDSA = "EdDSA"
+	algES256 = "ES256"
+	// p256Size is the length in bytes of a P-256 scalar
+	p256Size = 32
+)
+
+// header is the JOSE header of an annotation signature
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// Algorithm returns the JWS algorithm matching signatures made with the given key type. Only key types whose
+// providers sign the content with Ed25519, or with ECDSA P-256 over its SHA-256 digest, can be represented.
+func Algorithm(k contracts.KeyAlgorithm) (string, error) {
+	switch k {
+	case contracts.KeyEd25519:
+		return algEdDSA, nil
+	case contracts.KeyEcdsaP256, contracts.KeyPkcs11, contracts.KeyTpm, contracts.KeyAzureKeyVault, contracts.KeyGcpKms:
+		return algES256, nil
+	}
+	return "", fmt.Errorf("KeyAlgorithm %s cannot produce JWS signatures", k)
+}
+
+// IsCompact reports whether a signature is in JWS compact serialization
+func IsCompact(signature string) bool {
+	return strings.Count(signature, ".") == 2
+}
+
+// Sign produces a compact JWS over the payload using the signature provider. The header carries the key's Id as kid.
+func Sign(signer interfaces.SignatureProvider, key config.KeyInfo, payload []byte) (string, error) {
+	alg, err := Algorithm(key.Type)
+	if err != nil {
+		return "", err
+	}
+	h, err := json.Marshal(header{Alg: alg, Kid: key.Id})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signed, err := signer.Sign(key, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	sig, err := toJWS(alg, signed)
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// Verify checks a compact JWS produced by Sign, which must carry the given payload and an algorithm matching the key
+func Verify(verifier interfaces.SignatureProvider, key config.KeyInfo, token string, payload []byte) (bool, error) {
+	alg, err := Algorithm(key.Type)
+	if err != nil {
+		return false, err
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false, errors.New("signature is not a compact JWS")
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return false, nil
+	}
+	var h header
+	if err = json.Unmarshal(b, &h); err != nil || h.Alg != alg {
+		return false, nil
+	}
+	if b, err = base64.RawURLEncoding.DecodeString(parts[1]); err != nil || !bytes.Equal(b, payload) {
+		return false, nil
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false, nil
+	}
+	signature, err := fromJWS(alg, sig)
+	if err != nil {
+		return false, nil
+	}
+	return verifier.Verify(key, []byte(parts[0]+"."+parts[1]), []byte(signature))
+}
+
+// toJWS converts a hex encoded provider signature to its JWS form, which is raw r || s for ECDSA rather than DER
+func toJWS(alg string, signed string) ([]byte, error) {
+	b, err := hex.DecodeString(signed)
+	if err != nil {
+		return nil, err
+	}
+	if alg != algES256 {
+		return b, nil
+	}
+
+	var sig struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(b, &sig); err != nil || len(rest) > 0 {
+		return nil, errors.New("malformed ECDSA signature from provider")
+	}
+	if sig.R.BitLen() > 8*p256Size || sig.S.BitLen() > 8*p256Size {
+		return nil, errors.New("ECDSA signature is not a P-256 signature")
+	}
+	raw := make([]byte, 2*p256Size)
+	sig.R.FillBytes(raw[:p256Size])
+	sig.S.FillBytes(raw[p256Size:])
+	return raw, nil
+}
+
+// fromJWS is the inverse of toJWS
+func fromJWS(alg string, sig []byte) (string, error) {
+	if alg != algES256 {
+		return hex.EncodeToString(sig), nil
+	}
+	if len(sig) != 2*p256Size {
+		return "", errors.New("invalid ES256 signature length")
+	}
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{
+		R: new(big.Int).SetBytes(sig[:p256Size]),
+		S: new(big.Int).SetBytes(sig[p256Size:]),
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(der), nil
+}
@@ -0,0 +1,135 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package jws
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	ecdsaprovider "github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ecdsa"
+	ed25519provider "github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/secp256k1"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const keyPath = "../../test/keys/"
+
+func readPem(t *testing.T, path string) []byte {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	block, _ := pem.Decode(b)
+	return block.Bytes
+}
+
+func TestSign(t *testing.T) {
+	edPublic, _ := os.ReadFile(keyPath + "ed25519/public.key")
+	edKey, _ := hex.DecodeString(string(edPublic))
+	ecKey, _ := x509.ParsePKIXPublicKey(readPem(t, keyPath+"ecdsa-p256/public.pem"))
+
+	tests := []struct {
+		name        string
+		signer      interfaces.SignatureProvider
+		private     config.KeyInfo
+		public      config.KeyInfo
+		joseKey     interface{}
+		expectError bool
+	}{
+		{"ed25519", ed25519provider.New(),
+			config.KeyInfo{Type: contracts.KeyEd25519, Path: keyPath + "ed25519/private.key", Id: "2024-06"},
+			config.KeyInfo{Type: contracts.KeyEd25519, Path: keyPath + "ed25519/public.key"},
+			ed25519.PublicKey(edKey), false},
+		{"ecdsa-p256", ecdsaprovider.New(),
+			config.KeyInfo{Type: contracts.KeyEcdsaP256, Path: keyPath + "ecdsa-p256/private.pem", Id: "2024-06"},
+			config.KeyInfo{Type: contracts.KeyEcdsaP256, Path: keyPath + "ecdsa-p256/public.pem"},
+			ecKey.(*ecdsa.PublicKey), false},
+		{"secp256k1 not supported", secp256k1.New(),
+			config.KeyInfo{Type: contracts.KeyEcdsaSecp256k1, Path: keyPath + "secp256k1/private.key"},
+			config.KeyInfo{}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := []byte(`{"key":"dummyKey","hash":"sha256"}`)
+			token, err := Sign(tt.signer, tt.private, payload)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+			assert.True(t, IsCompact(token))
+
+			ok, err := Verify(tt.signer, tt.public, token, payload)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = Verify(tt.signer, tt.public, token, []byte(`{"key":"otherKey","hash":"sha256"}`))
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			// Standard JOSE libraries verify the token directly
+			parsed, err := jose.ParseSigned(token)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, "2024-06", parsed.Signatures[0].Header.KeyID)
+			verified, err := parsed.Verify(tt.joseKey)
+			assert.NoError(t, err)
+			assert.Equal(t, payload, verified)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ecPrivate, err := x509.ParsePKCS8PrivateKey(readPem(t, keyPath+"ecdsa-p256/private.pem"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	payload := []byte(`{"key":"dummyKey","hash":"sha256"}`)
+
+	// Tokens produced by other JOSE implementations are accepted
+	signer, _ := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: ecPrivate}, nil)
+	signed, _ := signer.Sign(payload)
+	foreign, _ := signed.CompactSerialize()
+
+	ecPublic := config.KeyInfo{Type: contracts.KeyEcdsaP256, Path: keyPath + "ecdsa-p256/public.pem"}
+	edPublic := config.KeyInfo{Type: contracts.KeyEd25519, Path: keyPath + "ed25519/public.key"}
+	tests := []struct {
+		name     string
+		verifier interfaces.SignatureProvider
+		key      config.KeyInfo
+		token    string
+		expected bool
+	}{
+		{"foreign ES256 token", ecdsaprovider.New(), ecPublic, foreign, true},
+		{"algorithm mismatch", ed25519provider.New(), edPublic, foreign, false},
+		{"malformed signature", ecdsaprovider.New(), ecPublic, foreign[:len(foreign)-4], false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := Verify(tt.verifier, tt.key, tt.token, payload)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package merklesig
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/merkle"
+)
+
+// prefix distinguishes batch signatures from the raw, JWS and COSE forms
+const prefix = "merkle."
+
+// signature is a batch signature as carried by a single item of the batch
+type signature struct {
+	merkle.Proof
+	Signature string `json:"signature"` // Signature is the provider's signature over the root of the batch
+}
+
+// IsBatch reports whether a signature was produced by Sign
+func IsBatch(sig string) bool {
+	return strings.HasPrefix(sig, prefix)
+}
+
+// Sign builds a Merkle tree over the contents and signs its root with the provider in a single operation. One
+// signature is returned per content, in order. Each carries the signed root along with the inclusion proof of its
+// content so that it can be verified without the rest of the batch.
+func Sign(signer interfaces.SignatureProvider, key config.KeyInfo, contents [][]byte) ([]string, error) {
+	tree, err := merkle.New(contents)
+	if err != nil {
+		return nil, err
+	}
+	root, err := hex.DecodeString(tree.Root())
+	if err != nil {
+		return nil, err
+	}
+	signed, err := signer.Sign(key, root)
+	if err != nil {
+		return nil, err
+	}
+
+	sigs := make([]string, tree.Len())
+	for i := range sigs {
+		proof, err := tree.Proof(i)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(signature{Proof: proof, Signature: signed})
+		if err != nil {
+			return nil, err
+		}
+		sigs[i] = prefix + base64.RawURLEncoding.EncodeToString(b)
+	}
+	return sigs, nil
+}
+
+// Verify checks a signature produced by Sign, requiring both that the content is included in the batch and that the
+// root of the batch was signed with the key. A malformed signature is reported as invalid rather than as an error.
+func Verify(verifier interfaces.SignatureProvider, key config.KeyInfo, sig string, content []byte) (bool, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(sig, prefix))
+	if err != nil {
+		return false, nil
+	}
+	var s signature
+	if err = json.Unmarshal(b, &s); err != nil {
+		return false, nil
+	}
+	if !merkle.Verify(content, s.Proof) {
+		return false, nil
+	}
+
+	root, err := hex.DecodeString(s.Root)
+	if err != nil {
+		return false, nil
+	}
+	return verifier.Verify(key, root, []byte(s.Signature))
+}
@@ -0,0 +1,135 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package merklesig
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// keyProvider signs with an Ed25519 key held in memory, counting the signatures requested of it. Providers cannot be
+// imported here since they depend on this package.
+type keyProvider struct {
+	private ed25519.PrivateKey
+	calls   int
+}
+
+func newKeyProvider() *keyProvider {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	return &keyProvider{private: private}
+}
+
+func (p *keyProvider) Sign(key config.KeyInfo, content []byte) (string, error) {
+	p.calls++
+	return hex.EncodeToString(ed25519.Sign(p.private, content)), nil
+}
+
+func (p *keyProvider) Verify(key config.KeyInfo, content, signed []byte) (bool, error) {
+	sig, err := hex.DecodeString(string(signed))
+	if err != nil {
+		return false, nil
+	}
+	return ed25519.Verify(p.private.Public().(ed25519.PublicKey), content, sig), nil
+}
+
+func (p *keyProvider) SignBatch(key config.KeyInfo, contents [][]byte) ([]string, error) {
+	return Sign(p, key, contents)
+}
+
+func TestSignVerify(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents [][]byte
+	}{
+		{"single item", [][]byte{[]byte("reading-1")}},
+		{"even batch", [][]byte{[]byte("reading-1"), []byte("reading-2")}},
+		{"odd batch", [][]byte{[]byte("reading-1"), []byte("reading-2"), []byte("reading-3")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := newKeyProvider()
+			sigs, err := signer.SignBatch(config.KeyInfo{}, tt.contents)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, signer.calls)
+			assert.Len(t, sigs, len(tt.contents))
+
+			for i, sig := range sigs {
+				assert.True(t, IsBatch(sig))
+				ok, err := Verify(signer, config.KeyInfo{}, sig, tt.contents[i])
+				assert.NoError(t, err)
+				assert.True(t, ok)
+
+				// Each signature only covers its own content
+				ok, err = Verify(signer, config.KeyInfo{}, sig, []byte("other"))
+				assert.NoError(t, err)
+				assert.False(t, ok)
+			}
+
+			// Another key must not verify the batch
+			ok, err := Verify(newKeyProvider(), config.KeyInfo{}, sigs[0], tt.contents[0])
+			assert.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestSign_Empty(t *testing.T) {
+	_, err := Sign(newKeyProvider(), config.KeyInfo{}, nil)
+	assert.Error(t, err)
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	signer := newKeyProvider()
+	content := []byte("reading-1")
+	sigs, err := Sign(signer, config.KeyInfo{}, [][]byte{content, []byte("reading-2")})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// A forged root with a matching proof must fail on the root signature
+	var forged signature
+	b, _ := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(sigs[0], prefix))
+	json.Unmarshal(b, &forged)
+	forged.Root = "00" + forged.Root[2:]
+	forged.Path = nil
+	b, _ = json.Marshal(forged)
+
+	tests := []struct {
+		name        string
+		signature   string
+		expectError bool
+	}{
+		{"valid signature", sigs[0], false},
+		{"not base64", prefix + "!!", false},
+		{"not json", prefix + base64.RawURLEncoding.EncodeToString([]byte("foo")), false},
+		{"forged root", prefix + base64.RawURLEncoding.EncodeToString(b), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := Verify(signer, config.KeyInfo{}, tt.signature, content)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.signature == sigs[0], ok)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package message
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	sdkMessage "github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+const defaultFlushInterval = time.Second
+
+var annotationListType = fmt.Sprintf("%T", contracts.AnnotationList{})
+
+// batchingPublisher wraps a stream provider, coalescing the annotations of consecutive publishes sharing an action
+// into a single AnnotationList envelope
+type batchingPublisher struct {
+	cfg      config.BatchInfo
+	provider interfaces.StreamProvider
+	logger   interfaces.Logger
+	interval time.Duration
+
+	mu          sync.Mutex
+	action      sdkMessage.SdkAction
+	contentType string              // contentType is the serialization of the pending batch, shared by the wrappers it coalesces
+	version     int                 // version is the schema of the wrappers coalesced in the pending batch
+	hostInfo    *contracts.HostInfo // hostInfo describes the host publishing the wrappers, if attached to them
+	pending     []contracts.Annotation
+	size        int               // size is the encoded size of the pending annotations
+	timer       *time.Timer       // timer flushes the pending batch once the flush interval elapses
+	generation  int               // generation identifies the pending batch so stale timers can be ignored
+	confirms    []func(err error) // confirms confirm the delivery of the publishes coalesced in the pending batch
+}
+
+func NewBatchingPublisher(cfg config.BatchInfo, provider interfaces.StreamProvider,
+	logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	if cfg.MaxCount < 0 || cfg.MaxBytes < 0 || cfg.FlushInterval < 0 {
+		return nil, fmt.Errorf("invalid batch limits count=%v bytes=%v interval=%v", cfg.MaxCount, cfg.MaxBytes,
+			cfg.FlushInterval)
+	}
+
+	p := batchingPublisher{
+		cfg:      cfg,
+		provider: provider,
+		logger:   logger,
+		interval: defaultFlushInterval,
+	}
+	if cfg.FlushInterval > 0 {
+		p.interval = time.Duration(cfg.FlushInterval) * time.Millisecond
+	}
+	return &p, nil
+}
+
+func (p *batchingPublisher) Connect(ctx context.Context) error {
+	return p.provider.Connect(ctx)
+}
+
+// Publish adds the annotations carried by the wrapper to the pending batch. Wrappers not carrying an AnnotationList
+// are published immediately after the pending batch, preserving ordering.
+func (p *batchingPublisher) Publish(ctx context.Context, msg sdkMessage.PublishWrapper) error {
+	var list contracts.AnnotationList
+	isList := msg.MessageType == annotationListType &&
+		sdkMessage.UnmarshalContent(msg.Content, msg.ContentType, &list) == nil
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !isList {
+		if err := p.flush(ctx); err != nil {
+			return err
+		}
+		return p.provider.Publish(ctx, msg)
+	}
+
+	if len(p.pending) > 0 && (msg.Action != p.action || msg.ContentType != p.contentType) {
+		if err := p.flush(ctx); err != nil {
+			return err
+		}
+	}
+	p.action = msg.Action
+	p.contentType = msg.ContentType
+	p.version = msg.Version
+	p.hostInfo = msg.HostInfo
+
+	var confirm func(err error)
+	if len(list.Items) > 0 {
+		confirm = sdkMessage.DeferDelivery(ctx)
+	}
+	for i, a := range list.Items {
+		b, _ := sdkMessage.MarshalContent(a, p.contentType)
+		if p.cfg.MaxBytes > 0 && len(p.pending) > 0 && p.size+len(b) > p.cfg.MaxBytes {
+			if err := p.flush(ctx); err != nil {
+				return err
+			}
+		}
+		p.pending = append(p.pending, a)
+		p.size += len(b)
+		if i == len(list.Items)-1 {
+			// the publish is delivered along with the batch holding its last annotation
+			p.confirms = append(p.confirms, confirm)
+		}
+		if p.cfg.MaxCount > 0 && len(p.pending) >= p.cfg.MaxCount {
+			if err := p.flush(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(p.pending) > 0 && p.timer == nil {
+		generation := p.generation
+		p.timer = time.AfterFunc(p.interval, func() { p.timedFlush(generation) })
+	}
+	return nil
+}
+
+// Close publishes any pending batch before closing the underlying provider
+// Healthy reports the health of the wrapped provider
+func (p *batchingPublisher) Healthy(ctx context.Context) error {
+	if checker, ok := p.provider.(interfaces.HealthChecker); ok {
+		return checker.Healthy(ctx)
+	}
+	return nil
+}
+
+func (p *batchingPublisher) Close() error {
+	p.mu.Lock()
+	err := p.flush(context.Background())
+	p.mu.Unlock()
+	if err != nil {
+		p.logger.Error(err.Error())
+	}
+	return p.provider.Close()
+}
+
+func (p *batchingPublisher) timedFlush(generation int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if generation != p.generation {
+		return
+	}
+	if err := p.flush(context.Background()); err != nil {
+		p.logger.Error(err.Error())
+	}
+}
+
+// flush publishes the pending batch as a single AnnotationList. Callers must hold p.mu.
+func (p *batchingPublisher) flush(ctx context.Context) error {
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	if len(p.pending) == 0 {
+		return nil
+	}
+
+	list := contracts.AnnotationList{Items: p.pending}
+	p.pending = nil
+	p.size = 0
+	p.generation++
+
+	b, _ := sdkMessage.MarshalContent(list, p.contentType)
+	wrap := sdkMessage.PublishWrapper{
+		Version:     p.version,
+		Action:      p.action,
+		MessageType: annotationListType,
+		Content:     b,
+		ContentType: p.contentType,
+		HostInfo:    p.hostInfo,
+	}
+	confirms := p.confirms
+	p.confirms = nil
+	ctx, d := sdkMessage.WithDelivery(ctx, func(err error) {
+		for _, confirm := range confirms {
+			confirm(err)
+		}
+	})
+	err := p.provider.Publish(ctx, wrap)
+	d.Sent(err)
+	return err
+}
@@ -0,0 +1,201 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	sdkMessage "github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingProvider struct {
+	mu        sync.Mutex
+	published []sdkMessage.PublishWrapper
+}
+
+func (r *recordingProvider) Connect(ctx context.Context) error {
+	return nil
+}
+
+func (r *recordingProvider) Publish(ctx context.Context, msg sdkMessage.PublishWrapper) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.published = append(r.published, msg)
+	return nil
+}
+
+func (r *recordingProvider) Close() error {
+	return nil
+}
+
+// batchSizes returns the number of annotations in each published wrapper, or -1 for other content
+func (r *recordingProvider) batchSizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sizes []int
+	for _, msg := range r.published {
+		var list contracts.AnnotationList
+		if msg.MessageType != annotationListType || json.Unmarshal(msg.Content, &list) != nil {
+			sizes = append(sizes, -1)
+			continue
+		}
+		sizes = append(sizes, len(list.Items))
+	}
+	return sizes
+}
+
+var batchTimestamp = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func annotations(action sdkMessage.SdkAction, count int) sdkMessage.PublishWrapper {
+	var list contracts.AnnotationList
+	for i := 0; i < count; i++ {
+		a := contracts.NewAnnotation("key", contracts.SHA256Hash, "host", contracts.Application,
+			contracts.AnnotationTPM, true)
+		// a fixed timestamp keeps the encoded annotations the same size
+		a.Timestamp = batchTimestamp
+		list.Items = append(list.Items, a)
+	}
+	b, _ := json.Marshal(list)
+	return sdkMessage.PublishWrapper{Action: action, MessageType: annotationListType, Content: b}
+}
+
+func TestNewBatchingPublisher(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	tests := []struct {
+		name        string
+		cfg         config.BatchInfo
+		expectError bool
+	}{
+		{"valid limits", config.BatchInfo{MaxCount: 10, MaxBytes: 1024, FlushInterval: 100}, false},
+		{"invalid count", config.BatchInfo{MaxCount: -1}, true},
+		{"invalid interval", config.BatchInfo{FlushInterval: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBatchingPublisher(tt.cfg, &recordingProvider{}, logger)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestBatchingPublisher_Publish(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	// MaxBytes limits the encoded annotations, not the list holding them, so twice the size of one holds two
+	var list contracts.AnnotationList
+	if err := json.Unmarshal(annotations(sdkMessage.ActionCreate, 1).Content, &list); err != nil {
+		t.Fatalf(err.Error())
+	}
+	item, err := json.Marshal(list.Items[0])
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	raw := sdkMessage.PublishWrapper{Action: sdkMessage.ActionPublish, MessageType: "string", Content: []byte("data")}
+	tests := []struct {
+		name     string
+		cfg      config.BatchInfo
+		msgs     []sdkMessage.PublishWrapper
+		expected []int
+	}{
+		{"count limit", config.BatchInfo{MaxCount: 3, FlushInterval: 60000},
+			[]sdkMessage.PublishWrapper{annotations(sdkMessage.ActionCreate, 2), annotations(sdkMessage.ActionCreate, 2),
+				annotations(sdkMessage.ActionCreate, 2)}, []int{3, 3}},
+		{"size limit", config.BatchInfo{MaxBytes: 2 * len(item), FlushInterval: 60000},
+			[]sdkMessage.PublishWrapper{annotations(sdkMessage.ActionCreate, 5)}, []int{2, 2}},
+		{"action change", config.BatchInfo{MaxCount: 10, FlushInterval: 60000},
+			[]sdkMessage.PublishWrapper{annotations(sdkMessage.ActionCreate, 2), annotations(sdkMessage.ActionTransit, 1)},
+			[]int{2}},
+		{"passthrough", config.BatchInfo{MaxCount: 10, FlushInterval: 60000},
+			[]sdkMessage.PublishWrapper{annotations(sdkMessage.ActionCreate, 2), raw}, []int{2, -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &recordingProvider{}
+			p, err := NewBatchingPublisher(tt.cfg, provider, logger)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			for _, msg := range tt.msgs {
+				if err = p.Publish(context.Background(), msg); err != nil {
+					t.Fatalf(err.Error())
+				}
+			}
+			assert.Equal(t, tt.expected, provider.batchSizes())
+		})
+	}
+}
+
+func TestBatchingPublisher_Flush(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+
+	provider := &recordingProvider{}
+	p, err := NewBatchingPublisher(config.BatchInfo{MaxCount: 100, FlushInterval: 20}, provider, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err = p.Publish(context.Background(), annotations(sdkMessage.ActionCreate, 2)); err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Empty(t, provider.batchSizes())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(provider.batchSizes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, []int{2}, provider.batchSizes())
+
+	// Close publishes whatever is pending
+	if err = p.Publish(context.Background(), annotations(sdkMessage.ActionCreate, 1)); err != nil {
+		t.Fatalf(err.Error())
+	}
+	p.Close()
+	assert.Equal(t, []int{2, 1}, provider.batchSizes())
+}
+
+func TestBatchingPublisher_Delivery(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+
+	provider := &recordingProvider{}
+	p, err := NewBatchingPublisher(config.BatchInfo{MaxCount: 3, FlushInterval: 60000}, provider, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	var confirmed []int
+	for i, count := range []int{2, 2, 1} {
+		i := i
+		ctx, d := sdkMessage.WithDelivery(context.Background(), func(err error) {
+			assert.NoError(t, err)
+			confirmed = append(confirmed, i)
+		})
+		err = p.Publish(ctx, annotations(sdkMessage.ActionCreate, count))
+		d.Sent(err)
+	}
+	// A publish is confirmed with the batch holding its last annotation
+	assert.Equal(t, []int{3}, provider.batchSizes())
+	assert.Equal(t, []int{0}, confirmed)
+
+	p.Close()
+	assert.Equal(t, []int{0, 1, 2}, confirmed)
+}
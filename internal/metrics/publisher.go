@@ -0,0 +1,70 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+// meteredPublisher wraps a stream provider, reporting every publish and reconnect to a metrics sink
+type meteredPublisher struct {
+	stream   contracts.StreamType
+	provider interfaces.StreamProvider
+	metrics  interfaces.PublishMetrics
+}
+
+// NewMeteredPublisher wraps provider, reporting its activity to metrics under the given stream type
+func NewMeteredPublisher(stream contracts.StreamType, provider interfaces.StreamProvider,
+	metrics interfaces.PublishMetrics) interfaces.StreamProvider {
+	p := meteredPublisher{
+		stream:   stream,
+		provider: provider,
+		metrics:  metrics,
+	}
+	if reporter, ok := provider.(interfaces.ReconnectReporter); ok {
+		reporter.OnReconnect(func() {
+			metrics.Reconnected(stream)
+		})
+	}
+	return &p
+}
+
+func (p *meteredPublisher) Connect(ctx context.Context) error {
+	return p.provider.Connect(ctx)
+}
+
+func (p *meteredPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	start := time.Now()
+	err := p.provider.Publish(ctx, msg)
+	p.metrics.Published(p.stream, len(msg.Content), time.Since(start), err)
+	return err
+}
+
+// Healthy reports the health of the wrapped provider, health checks are not reported to the metrics sink
+func (p *meteredPublisher) Healthy(ctx context.Context) error {
+	if checker, ok := p.provider.(interfaces.HealthChecker); ok {
+		return checker.Healthy(ctx)
+	}
+	return nil
+}
+
+func (p *meteredPublisher) Close() error {
+	return p.provider.Close()
+}
@@ -0,0 +1,88 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/stretchr/testify/assert"
+)
+
+// reconnectingProvider fails publishes of empty content and reconnects before every publish
+type reconnectingProvider struct {
+	onReconnect func()
+}
+
+func (r *reconnectingProvider) Connect(ctx context.Context) error {
+	return nil
+}
+
+func (r *reconnectingProvider) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	r.onReconnect()
+	if len(msg.Content) == 0 {
+		return errors.New("empty content")
+	}
+	return nil
+}
+
+func (r *reconnectingProvider) Close() error {
+	return nil
+}
+
+func (r *reconnectingProvider) OnReconnect(handler func()) {
+	r.onReconnect = handler
+}
+
+// recordingMetrics keeps the measurements it receives
+type recordingMetrics struct {
+	mu         sync.Mutex
+	bytes      []int
+	errors     int
+	reconnects int
+}
+
+func (m *recordingMetrics) Published(stream contracts.StreamType, bytes int, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bytes = append(m.bytes, bytes)
+	if err != nil {
+		m.errors++
+	}
+}
+
+func (m *recordingMetrics) Reconnected(stream contracts.StreamType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reconnects++
+}
+
+func TestMeteredPublisher_Publish(t *testing.T) {
+	metrics := &recordingMetrics{}
+	p := NewMeteredPublisher(contracts.MockStream, &reconnectingProvider{}, metrics)
+
+	assert.NoError(t, p.Publish(context.Background(),
+		message.PublishWrapper{Action: message.ActionCreate, Content: []byte("content")}))
+	assert.Error(t, p.Publish(context.Background(), message.PublishWrapper{Action: message.ActionCreate}))
+
+	assert.Equal(t, []int{7, 0}, metrics.bytes)
+	assert.Equal(t, 1, metrics.errors)
+	assert.Equal(t, 2, metrics.reconnects)
+}
@@ -0,0 +1,52 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// meteredSigner wraps a signature provider, reporting every signature to a metrics sink
+type meteredSigner struct {
+	provider interfaces.SignatureProvider
+	metrics  interfaces.SignMetrics
+}
+
+// NewMeteredSignatureProvider wraps provider, reporting the latency and outcome of its signatures to metrics
+func NewMeteredSignatureProvider(provider interfaces.SignatureProvider,
+	metrics interfaces.SignMetrics) interfaces.SignatureProvider {
+	return &meteredSigner{provider: provider, metrics: metrics}
+}
+
+func (s *meteredSigner) Sign(key config.KeyInfo, content []byte) (string, error) {
+	start := time.Now()
+	signed, err := s.provider.Sign(key, content)
+	s.metrics.Signed(key.Type, time.Since(start), err)
+	return signed, err
+}
+
+func (s *meteredSigner) SignBatch(key config.KeyInfo, contents [][]byte) ([]string, error) {
+	start := time.Now()
+	signed, err := s.provider.SignBatch(key, contents)
+	s.metrics.Signed(key.Type, time.Since(start), err)
+	return signed, err
+}
+
+func (s *meteredSigner) Verify(key config.KeyInfo, content, signed []byte) (bool, error) {
+	return s.provider.Verify(key, content, signed)
+}
@@ -0,0 +1,81 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubSigner fails to sign empty content
+type stubSigner struct{}
+
+func (s stubSigner) Sign(key config.KeyInfo, content []byte) (string, error) {
+	if len(content) == 0 {
+		return "", errors.New("empty content")
+	}
+	return "signed", nil
+}
+
+func (s stubSigner) SignBatch(key config.KeyInfo, contents [][]byte) ([]string, error) {
+	signed := make([]string, len(contents))
+	for i := range contents {
+		signed[i] = "signed"
+	}
+	return signed, nil
+}
+
+func (s stubSigner) Verify(key config.KeyInfo, content, signed []byte) (bool, error) {
+	return true, nil
+}
+
+// recordingSignMetrics keeps the algorithms signed with and the number of errors
+type recordingSignMetrics struct {
+	algorithms []contracts.KeyAlgorithm
+	errors     int
+}
+
+func (m *recordingSignMetrics) Signed(algorithm contracts.KeyAlgorithm, latency time.Duration, err error) {
+	m.algorithms = append(m.algorithms, algorithm)
+	if err != nil {
+		m.errors++
+	}
+}
+
+func TestMeteredSignatureProvider(t *testing.T) {
+	metrics := &recordingSignMetrics{}
+	p := NewMeteredSignatureProvider(stubSigner{}, metrics)
+	key := config.KeyInfo{Type: contracts.KeyEd25519}
+
+	_, err := p.Sign(key, []byte("content"))
+	assert.NoError(t, err)
+	_, err = p.Sign(key, nil)
+	assert.Error(t, err)
+	signed, err := p.SignBatch(key, [][]byte{[]byte("a"), []byte("b")})
+	assert.NoError(t, err)
+	assert.Len(t, signed, 2)
+	_, err = p.Verify(key, []byte("content"), []byte("signed"))
+	assert.NoError(t, err)
+
+	// A batch counts once and verification is not measured
+	assert.Equal(t, []contracts.KeyAlgorithm{contracts.KeyEd25519, contracts.KeyEd25519, contracts.KeyEd25519},
+		metrics.algorithms)
+	assert.Equal(t, 1, metrics.errors)
+}
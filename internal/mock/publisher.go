@@ -15,6 +15,7 @@
 package mock
 
 import (
+	"context"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
@@ -32,11 +33,11 @@ func NewMockPublisher(cfg config.MockStreamConfig, logger interfaces.Logger) int
 	}
 }
 
-func (p *mockPublisher) Connect() error {
+func (p *mockPublisher) Connect(ctx context.Context) error {
 	return nil
 }
 
-func (p *mockPublisher) Publish(msg message.PublishWrapper) error {
+func (p *mockPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
 	return nil
 }
 
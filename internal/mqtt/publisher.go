@@ -14,13 +14,17 @@
 package mqtt
 
 import (
-	"encoding/json"
+	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/tlsconfig"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
 )
@@ -30,47 +34,100 @@ const (
 	publishTimeout time.Duration = 2000
 )
 
+// secureSchemes are the broker URI schemes for which the client negotiates TLS
+var secureSchemes = map[string]bool{"ssl": true, "tls": true, "mqtts": true, "wss": true}
+
 type mqttPublisher struct {
 	endpoint   config.MqttConfig
 	logger     interfaces.Logger
 	mqttClient MQTT.Client
+
+	mu          sync.Mutex
+	dialed      bool   // dialed is set once the first connection has been established
+	onReconnect func() // onReconnect is called when a later connection is established
 }
 
-func NewMqttPublisher(cfg config.MqttConfig, logger interfaces.Logger) interfaces.StreamProvider {
+func NewMqttPublisher(cfg config.MqttConfig, logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	if err := validateQos(cfg); err != nil {
+		return nil, err
+	}
+
+	var tlsConfig *tls.Config
+	if secureSchemes[cfg.Provider.Protocol] {
+		t, err := tlsconfig.New(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		if t.ServerName == "" {
+			t.ServerName = cfg.Provider.Host
+		}
+		tlsConfig = t
+	}
+
+	switch cfg.ProtocolVersion {
+	case 0, 3, 4:
+	case 5:
+		return newMqtt5Publisher(cfg, tlsConfig, logger)
+	default:
+		return nil, fmt.Errorf("unsupported MQTT protocol version %v", cfg.ProtocolVersion)
+	}
+
 	opts := MQTT.NewClientOptions()
 	opts.AddBroker(cfg.Provider.Uri())
 	opts.SetClientID(cfg.ClientId)
 	opts.SetUsername(cfg.User)
 	opts.SetPassword(cfg.Password)
 	opts.SetCleanSession(cfg.Cleanness)
+	if cfg.ProtocolVersion != 0 {
+		opts.SetProtocolVersion(uint(cfg.ProtocolVersion))
+	}
+	if tlsConfig != nil {
+		opts.SetTLSConfig(tlsConfig)
+	}
+	if cfg.Will != nil {
+		opts.SetWill(cfg.Will.Topic, cfg.Will.Payload, byte(cfg.Will.Qos), cfg.Will.Retained)
+	}
 
 	p := mqttPublisher{
-		endpoint:   cfg,
-		logger:     logger,
-		mqttClient: MQTT.NewClient(opts),
+		endpoint: cfg,
+		logger:   logger,
 	}
+	opts.SetOnConnectHandler(func(MQTT.Client) {
+		p.connected()
+	})
+	p.mqttClient = MQTT.NewClient(opts)
 
-	return &p
+	return &p, nil
 }
 
-func (p *mqttPublisher) Connect() error {
+func (p *mqttPublisher) Connect(ctx context.Context) error {
 	// It would be highly odd if the publisher were to be already connected here, but check anyway
-	return p.reconnect()
+	return p.reconnect(ctx)
 }
 
-func (p *mqttPublisher) Publish(msg message.PublishWrapper) error {
+func (p *mqttPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
 	// Verify connectivity first. If it's been dropped, this will attempt one reconnect before publish
-	err := p.reconnect()
+	err := p.reconnect(ctx)
 	if err != nil {
 		return err
 	}
 
-	b, _ := json.Marshal(msg)
+	b, _ := message.Marshal(msg)
+	qos := byte(publishQos(p.endpoint, msg.Action))
 	// publish to all topics
 	for _, topic := range p.endpoint.Topics {
 		p.logger.Write(slog.LevelDebug, fmt.Sprintf("attempting publish, topic %s %s", topic, string(b)))
-		token := p.mqttClient.Publish(topic, byte(p.endpoint.Qos), false, b)
-		token.WaitTimeout(time.Millisecond * publishTimeout)
+		token := p.mqttClient.Publish(topic, qos, p.endpoint.Retained, b)
+		select {
+		case <-token.Done():
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Millisecond * publishTimeout):
+			return fmt.Errorf("%w, topic %s", contracts.ErrPublishTimeout, topic)
+		}
+		if token.Error() != nil {
+			return token.Error()
+		}
 	}
 	return nil
 }
@@ -80,12 +137,67 @@ func (p *mqttPublisher) Close() error {
 	return nil
 }
 
-func (p *mqttPublisher) reconnect() error {
+// OnReconnect registers handler to be called whenever a dropped connection is re-established
+func (p *mqttPublisher) OnReconnect(handler func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onReconnect = handler
+}
+
+// connected is invoked by the client for every established connection, including automatic reconnects
+func (p *mqttPublisher) connected() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.dialed && p.onReconnect != nil {
+		p.onReconnect()
+	}
+	p.dialed = true
+}
+
+// Healthy reports whether the client currently holds an open connection to the broker
+func (p *mqttPublisher) Healthy(ctx context.Context) error {
+	if !p.mqttClient.IsConnectionOpen() {
+		return fmt.Errorf("%w to mqtt broker %s", contracts.ErrNotConnected, p.endpoint.Provider.Uri())
+	}
+	return nil
+}
+
+// reconnect connects the client if it is not connected, giving up waiting when ctx is done
+func (p *mqttPublisher) reconnect(ctx context.Context) error {
 	if !p.mqttClient.IsConnected() {
 		token := p.mqttClient.Connect()
-		if token.Wait() && token.Error() != nil {
+		select {
+		case <-token.Done():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if token.Error() != nil {
 			return token.Error()
 		}
 	}
 	return nil
 }
+
+// publishQos returns the QoS level for a publish of the given action
+func publishQos(cfg config.MqttConfig, action message.SdkAction) int {
+	if qos, ok := cfg.ActionQos[string(action)]; ok {
+		return qos
+	}
+	return cfg.Qos
+}
+
+func validateQos(cfg config.MqttConfig) error {
+	levels := map[string]int{"qos": cfg.Qos}
+	for action, qos := range cfg.ActionQos {
+		levels[action] = qos
+	}
+	if cfg.Will != nil {
+		levels["will"] = cfg.Will.Qos
+	}
+	for name, qos := range levels {
+		if qos < 0 || qos > 2 {
+			return fmt.Errorf("invalid QoS level %v for %s", qos, name)
+		}
+	}
+	return nil
+}
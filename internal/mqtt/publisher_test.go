@@ -0,0 +1,166 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMqttPublisher(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	provider := config.ServiceInfo{Host: "localhost", Port: 8883, Protocol: "ssl"}
+
+	tests := []struct {
+		name        string
+		cfg         config.MqttConfig
+		expectError bool
+	}{
+		{"plaintext", config.MqttConfig{Provider: config.ServiceInfo{Host: "localhost", Port: 1883, Protocol: "tcp"}}, false},
+		{"tls with system roots", config.MqttConfig{Provider: provider}, false},
+		{"tls with missing ca", config.MqttConfig{Provider: provider, TLS: config.TLSInfo{CaPath: "./missing.pem"}}, true},
+		{"mtls without key", config.MqttConfig{Provider: provider, TLS: config.TLSInfo{CertPath: "./cert.pem"}}, true},
+		{"mqtt 5", config.MqttConfig{Provider: provider, ProtocolVersion: 5}, false},
+		{"mqtt 5 over websocket", config.MqttConfig{Provider: config.ServiceInfo{Protocol: "ws"}, ProtocolVersion: 5}, true},
+		{"unsupported version", config.MqttConfig{Provider: provider, ProtocolVersion: 6}, true},
+		{"invalid qos", config.MqttConfig{Provider: provider, Qos: 3}, true},
+		{"invalid action qos", config.MqttConfig{Provider: provider, ActionQos: map[string]int{"create": -1}}, true},
+		{"invalid will qos", config.MqttConfig{Provider: provider, Will: &config.MqttWillInfo{Topic: "lwt", Qos: 5}}, true},
+		{"negative session expiry", config.MqttConfig{Provider: provider, ProtocolVersion: 5, SessionExpiry: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMqttPublisher(tt.cfg, logger)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestMqtt5Publisher_Publish(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer listener.Close()
+	connected := make(chan *packets.Connect, 1)
+	received := make(chan *packets.Publish, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			cp, err := packets.ReadPacket(conn)
+			if err != nil {
+				return
+			}
+			switch c := cp.Content.(type) {
+			case *packets.Connect:
+				connected <- c
+				connack := packets.Connack{ReasonCode: packets.ConnackSuccess, Properties: &packets.Properties{}}
+				connack.WriteTo(conn)
+			case *packets.Publish:
+				received <- c
+				if c.QoS > 0 {
+					puback := packets.Puback{PacketID: c.PacketID, ReasonCode: packets.PubackSuccess,
+						Properties: &packets.Properties{}}
+					puback.WriteTo(conn)
+				}
+			}
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	cfg := config.MqttConfig{
+		ClientId:        "sdk-test",
+		Provider:        config.ServiceInfo{Host: "127.0.0.1", Port: portNum, Protocol: "tcp"},
+		Topics:          []string{"alvarium"},
+		ProtocolVersion: 5,
+		MessageExpiry:   60,
+		SessionExpiry:   300,
+		Retained:        true,
+		ActionQos:       map[string]int{string(message.ActionCreate): 1},
+		Will:            &config.MqttWillInfo{Topic: "alvarium/status", Payload: "offline", Qos: 1},
+	}
+	p, err := NewMqttPublisher(cfg, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer p.Close()
+	assert.NoError(t, p.(interfaces.HealthChecker).Healthy(context.Background()))
+
+	select {
+	case c := <-connected:
+		assert.True(t, c.WillFlag)
+		assert.Equal(t, "alvarium/status", c.WillTopic)
+		assert.Equal(t, []byte("offline"), c.WillMessage)
+		assert.Equal(t, byte(1), c.WillQOS)
+		assert.Equal(t, uint32(300), *c.Properties.SessionExpiryInterval)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for connect")
+	}
+
+	tpm := contracts.NewAnnotation("key", contracts.SHA256Hash, "host", contracts.Application, contracts.AnnotationTPM, true)
+	pki := contracts.NewAnnotation("key", contracts.SHA256Hash, "host", contracts.Application, contracts.AnnotationPKI, true)
+	b, _ := json.Marshal(contracts.AnnotationList{Items: []contracts.Annotation{tpm, pki}})
+	msg := message.PublishWrapper{Action: message.ActionCreate, MessageType: "contracts.AnnotationList", Content: b}
+	if err = p.Publish(context.Background(), msg); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	select {
+	case pub := <-received:
+		assert.Equal(t, "alvarium", pub.Topic)
+		assert.Equal(t, byte(1), pub.QoS)
+		assert.True(t, pub.Retain)
+		assert.Equal(t, defaultContentType, pub.Properties.ContentType)
+		assert.Equal(t, uint32(60), *pub.Properties.MessageExpiry)
+		assert.Equal(t, []packets.User{
+			{Key: "action", Value: "create"},
+			{Key: "messageType", Value: "contracts.AnnotationList"},
+			{Key: "layer", Value: "app"},
+			{Key: "kind", Value: "tpm"},
+			{Key: "hash", Value: "sha256"},
+			{Key: "kind", Value: "pki"},
+		}, pub.Properties.User)
+
+		var result message.PublishWrapper
+		json.Unmarshal(pub.Payload, &result)
+		assert.Equal(t, msg, result)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for publish")
+	}
+}
@@ -0,0 +1,257 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+const (
+	connectTimeout     = 5 * time.Second
+	keepAlive          = 30
+	defaultContentType = "application/json"
+)
+
+// mqtt5Publisher publishes over MQTT 5, attaching annotation properties as user properties so that subscribers can
+// filter messages without deserializing the payload
+type mqtt5Publisher struct {
+	cfg       config.MqttConfig
+	logger    interfaces.Logger
+	tlsConfig *tls.Config
+
+	mu          sync.Mutex
+	client      *paho.Client
+	dialed      bool   // dialed is set once the first session has been established
+	onReconnect func() // onReconnect is called when a later session is established
+}
+
+func newMqtt5Publisher(cfg config.MqttConfig, tlsConfig *tls.Config,
+	logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	switch cfg.Provider.Protocol {
+	case "tcp", "mqtt", "ssl", "tls", "mqtts":
+	default:
+		return nil, fmt.Errorf("unsupported MQTT 5 transport %s", cfg.Provider.Protocol)
+	}
+	if cfg.MessageExpiry < 0 || cfg.SessionExpiry < 0 {
+		return nil, fmt.Errorf("invalid expiry, message %v session %v", cfg.MessageExpiry, cfg.SessionExpiry)
+	}
+	if cfg.ContentType == "" {
+		cfg.ContentType = defaultContentType
+	}
+
+	p := mqtt5Publisher{
+		cfg:       cfg,
+		logger:    logger,
+		tlsConfig: tlsConfig,
+	}
+	return &p, nil
+}
+
+func (p *mqtt5Publisher) Connect(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.reconnect(ctx)
+}
+
+func (p *mqtt5Publisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Verify connectivity first. If it's been dropped, this will attempt one reconnect before publish
+	if err := p.reconnect(ctx); err != nil {
+		return err
+	}
+
+	b, _ := message.Marshal(msg)
+	properties := &paho.PublishProperties{
+		ContentType: p.cfg.ContentType,
+		User:        userProperties(msg),
+	}
+	if p.cfg.MessageExpiry > 0 {
+		expiry := uint32(p.cfg.MessageExpiry)
+		properties.MessageExpiry = &expiry
+	}
+
+	qos := byte(publishQos(p.cfg, msg.Action))
+	for _, topic := range p.cfg.Topics {
+		p.logger.Write(slog.LevelDebug, fmt.Sprintf("attempting publish, topic %s %s", topic, string(b)))
+		publishCtx, cancel := context.WithTimeout(ctx, time.Millisecond*publishTimeout)
+		resp, err := p.client.Publish(publishCtx, &paho.Publish{
+			Topic:      topic,
+			QoS:        qos,
+			Retain:     p.cfg.Retained,
+			Payload:    b,
+			Properties: properties,
+		})
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				return fmt.Errorf("%w, topic %s: %w", contracts.ErrPublishTimeout, topic, err)
+			}
+			return err
+		}
+		if resp != nil && resp.ReasonCode >= packets.PubackUnspecifiedError {
+			return fmt.Errorf("broker rejected publish to %s, reason code %v", topic, resp.ReasonCode)
+		}
+	}
+	return nil
+}
+
+func (p *mqtt5Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client == nil {
+		return nil
+	}
+	err := p.client.Disconnect(&paho.Disconnect{ReasonCode: packets.DisconnectNormalDisconnection})
+	p.client = nil
+	return err
+}
+
+// OnReconnect registers handler to be called whenever a dropped connection is re-established
+func (p *mqtt5Publisher) OnReconnect(handler func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onReconnect = handler
+}
+
+// Healthy reports whether the session with the broker is still open
+func (p *mqtt5Publisher) Healthy(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client == nil {
+		return fmt.Errorf("%w to mqtt broker %s", contracts.ErrNotConnected, p.cfg.Provider.Uri())
+	}
+	select {
+	case <-p.client.Done():
+		return fmt.Errorf("connection to mqtt broker %s was lost", p.cfg.Provider.Uri())
+	default:
+		return nil
+	}
+}
+
+// reconnect establishes a new session if there is no live connection, giving up when ctx is done. Callers must hold
+// p.mu.
+func (p *mqtt5Publisher) reconnect(ctx context.Context) error {
+	if p.client != nil {
+		select {
+		case <-p.client.Done():
+			p.client = nil
+		default:
+			return nil
+		}
+	}
+
+	address := net.JoinHostPort(p.cfg.Provider.Host, strconv.Itoa(p.cfg.Provider.Port))
+	dialer := &net.Dialer{Timeout: connectTimeout}
+	var conn net.Conn
+	var err error
+	if p.tlsConfig != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: p.tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+
+	client := paho.NewClient(paho.ClientConfig{
+		ClientID: p.cfg.ClientId,
+		Conn:     packets.NewThreadSafeConn(conn),
+		OnClientError: func(err error) {
+			p.logger.Error(fmt.Sprintf("mqtt client error %s", err.Error()))
+		},
+	})
+	cp := &paho.Connect{
+		ClientID:     p.cfg.ClientId,
+		CleanStart:   p.cfg.Cleanness,
+		KeepAlive:    keepAlive,
+		Username:     p.cfg.User,
+		UsernameFlag: p.cfg.User != "",
+		Password:     []byte(p.cfg.Password),
+		PasswordFlag: p.cfg.Password != "",
+	}
+	if p.cfg.SessionExpiry > 0 {
+		expiry := uint32(p.cfg.SessionExpiry)
+		cp.Properties = &paho.ConnectProperties{SessionExpiryInterval: &expiry}
+	}
+	if p.cfg.Will != nil {
+		cp.WillMessage = &paho.WillMessage{
+			Topic:   p.cfg.Will.Topic,
+			Payload: []byte(p.cfg.Will.Payload),
+			QoS:     byte(p.cfg.Will.Qos),
+			Retain:  p.cfg.Will.Retained,
+		}
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+	_, err = client.Connect(connectCtx, cp)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	p.client = client
+	if p.dialed && p.onReconnect != nil {
+		p.onReconnect()
+	}
+	p.dialed = true
+	return nil
+}
+
+// userProperties exposes the wrapper metadata and the distinct layer, kind and hash values of any annotations
+// carried by the wrapper. MQTT 5 allows a key to appear more than once.
+func userProperties(msg message.PublishWrapper) paho.UserProperties {
+	props := paho.UserProperties{
+		{Key: "action", Value: string(msg.Action)},
+		{Key: "messageType", Value: msg.MessageType},
+	}
+
+	var list contracts.AnnotationList
+	if err := json.Unmarshal(msg.Content, &list); err != nil {
+		return props
+	}
+	seen := map[paho.UserProperty]bool{}
+	add := func(key, value string) {
+		prop := paho.UserProperty{Key: key, Value: value}
+		if value == "" || seen[prop] {
+			return
+		}
+		seen[prop] = true
+		props = append(props, prop)
+	}
+	for _, a := range list.Items {
+		add("layer", string(a.Layer))
+		add("kind", string(a.Kind))
+		add("hash", string(a.Hash))
+	}
+	return props
+}
@@ -0,0 +1,175 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package otel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/tlsconfig"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+const (
+	logsRoute      = "/v1/logs"
+	requestTimeout = 10 * time.Second
+
+	defaultServiceName = "alvarium"
+	scopeName          = "github.com/project-alvarium/alvarium-sdk-go"
+)
+
+type otelPublisher struct {
+	cfg    config.OtelConfig
+	logger interfaces.Logger
+	client *http.Client
+}
+
+func NewOtelPublisher(cfg config.OtelConfig, logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	if cfg.Provider.Protocol != "http" && cfg.Provider.Protocol != "https" {
+		return nil, fmt.Errorf("unsupported OTLP transport %s", cfg.Provider.Protocol)
+	}
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = defaultServiceName
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.Provider.Protocol == "https" {
+		t, err := tlsconfig.New(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = t
+	}
+
+	p := otelPublisher{
+		cfg:    cfg,
+		logger: logger,
+		client: &http.Client{Timeout: requestTimeout, Transport: transport},
+	}
+	return &p, nil
+}
+
+// Connect is a no-op. OTLP/HTTP is stateless and collectors expose no standard health route, so reachability is
+// established on the first export.
+func (p *otelPublisher) Connect(ctx context.Context) error {
+	return nil
+}
+
+// Publish exports one log record per annotation carried by the wrapper. Annotation properties are exposed as record
+// attributes so backends can query on them. Wrappers not carrying annotations are exported as a single record.
+func (p *otelPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	req := exportLogsRequest{
+		ResourceLogs: []resourceLogs{{
+			Resource: resource{Attributes: []keyValue{stringAttribute("service.name", p.cfg.ServiceName)}},
+			ScopeLogs: []scopeLogs{{
+				Scope:      scope{Name: scopeName},
+				LogRecords: records(msg, time.Now()),
+			}},
+		}},
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Write(slog.LevelDebug, fmt.Sprintf("attempting publish, collector %s %s", p.cfg.Provider.Uri(), string(body)))
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.Provider.Uri()+logsRoute, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	for k, v := range p.cfg.Headers {
+		r.Header.Set(k, v)
+	}
+	resp, err := p.client.Do(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected response from collector %s %s", resp.Status, string(detail))
+	}
+
+	var result exportLogsResponse
+	if err = json.NewDecoder(resp.Body).Decode(&result); err == nil && result.PartialSuccess != nil &&
+		result.PartialSuccess.RejectedLogRecords != "" && result.PartialSuccess.RejectedLogRecords != "0" {
+		return fmt.Errorf("collector rejected %s log records %s", result.PartialSuccess.RejectedLogRecords,
+			result.PartialSuccess.ErrorMessage)
+	}
+	return nil
+}
+
+func (p *otelPublisher) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
+
+// records maps a publish wrapper to OTLP log records
+func records(msg message.PublishWrapper, observed time.Time) []logRecord {
+	wrapperAttributes := []keyValue{
+		stringAttribute("alvarium.action", string(msg.Action)),
+		stringAttribute("alvarium.message_type", msg.MessageType),
+	}
+
+	var list contracts.AnnotationList
+	if err := json.Unmarshal(msg.Content, &list); err != nil || len(list.Items) == 0 {
+		return []logRecord{{
+			ObservedTimeUnixNano: unixNano(observed),
+			SeverityNumber:       severityInfo,
+			SeverityText:         "INFO",
+			Body:                 stringValue(string(msg.Content)),
+			Attributes:           wrapperAttributes,
+		}}
+	}
+
+	result := make([]logRecord, 0, len(list.Items))
+	for _, a := range list.Items {
+		severity, severityText := severityInfo, "INFO"
+		if !a.IsSatisfied {
+			severity, severityText = severityWarn, "WARN"
+		}
+		b, _ := json.Marshal(a)
+		attributes := append([]keyValue{
+			stringAttribute("alvarium.annotation.id", a.Id.String()),
+			stringAttribute("alvarium.annotation.key", a.Key),
+			stringAttribute("alvarium.annotation.hash", string(a.Hash)),
+			stringAttribute("alvarium.annotation.host", a.Host),
+			stringAttribute("alvarium.annotation.tag", a.Tag.String()),
+			stringAttribute("alvarium.annotation.layer", string(a.Layer)),
+			stringAttribute("alvarium.annotation.kind", string(a.Kind)),
+			boolAttribute("alvarium.annotation.is_satisfied", a.IsSatisfied),
+		}, wrapperAttributes...)
+		result = append(result, logRecord{
+			TimeUnixNano:         unixNano(a.Timestamp),
+			ObservedTimeUnixNano: unixNano(observed),
+			SeverityNumber:       severity,
+			SeverityText:         severityText,
+			Body:                 stringValue(string(b)),
+			Attributes:           attributes,
+		})
+	}
+	return result
+}
@@ -0,0 +1,131 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOtelPublisher_Publish(t *testing.T) {
+	var received exportLogsRequest
+	var authorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != logsRoute {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		authorization = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	host, port, _ := net.SplitHostPort(u.Host)
+	portNum, _ := strconv.Atoi(port)
+	cfg := config.OtelConfig{
+		Provider:    config.ServiceInfo{Host: host, Port: portNum, Protocol: u.Scheme},
+		ServiceName: "sensor",
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+	}
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+
+	satisfied := contracts.NewAnnotation("key", contracts.SHA256Hash, "host", contracts.Application, contracts.AnnotationTPM, true)
+	unsatisfied := contracts.NewAnnotation("key", contracts.SHA256Hash, "host", contracts.Application, contracts.AnnotationPKI, false)
+	b, _ := json.Marshal(contracts.AnnotationList{Items: []contracts.Annotation{satisfied, unsatisfied}})
+
+	tests := []struct {
+		name        string
+		cfg         config.OtelConfig
+		msg         message.PublishWrapper
+		severities  []int
+		expectError bool
+	}{
+		{"annotation list", cfg, message.PublishWrapper{Action: message.ActionCreate, MessageType: "AnnotationList", Content: b},
+			[]int{severityInfo, severityWarn}, false},
+		{"raw content", cfg, message.PublishWrapper{Action: message.ActionPublish, MessageType: "string", Content: []byte("data")},
+			[]int{severityInfo}, false},
+		{"invalid transport", config.OtelConfig{Provider: config.ServiceInfo{Protocol: "grpc"}}, message.PublishWrapper{}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewOtelPublisher(tt.cfg, logger)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+			if err = p.Connect(context.Background()); err != nil {
+				t.Fatalf(err.Error())
+			}
+			defer p.Close()
+
+			if err = p.Publish(context.Background(), tt.msg); err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Equal(t, "Bearer token", authorization)
+			assert.Len(t, received.ResourceLogs, 1)
+			assert.Equal(t, "sensor", *received.ResourceLogs[0].Resource.Attributes[0].Value.StringValue)
+
+			records := received.ResourceLogs[0].ScopeLogs[0].LogRecords
+			assert.Len(t, records, len(tt.severities))
+			for i, r := range records {
+				assert.Equal(t, tt.severities[i], r.SeverityNumber)
+			}
+			if len(tt.severities) == 1 {
+				assert.Equal(t, "data", *records[0].Body.StringValue)
+				return
+			}
+			var a contracts.Annotation
+			json.Unmarshal([]byte(*records[0].Body.StringValue), &a)
+			assert.Equal(t, satisfied.Id, a.Id)
+			assert.Equal(t, "alvarium.annotation.id", records[0].Attributes[0].Key)
+			assert.Equal(t, satisfied.Id.String(), *records[0].Attributes[0].Value.StringValue)
+		})
+	}
+}
+
+func TestOtelPublisher_Rejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"partialSuccess":{"rejectedLogRecords":"1","errorMessage":"invalid record"}}`))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	host, port, _ := net.SplitHostPort(u.Host)
+	portNum, _ := strconv.Atoi(port)
+	cfg := config.OtelConfig{Provider: config.ServiceInfo{Host: host, Port: portNum, Protocol: u.Scheme}}
+	p, err := NewOtelPublisher(cfg, logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo}))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	err = p.Publish(context.Background(),
+		message.PublishWrapper{Action: message.ActionCreate, MessageType: "test", Content: []byte("data")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"golang.org/x/time/rate"
+)
+
+// ErrRateLimited is returned by publishes rejected under the drop policy
+var ErrRateLimited = errors.New("publish rate limit exceeded")
+
+// rateLimitedPublisher wraps a stream provider with a token bucket, either delaying or rejecting publishes that
+// exceed the configured rate
+type rateLimitedPublisher struct {
+	cfg      config.RateLimitInfo
+	provider interfaces.StreamProvider
+	logger   interfaces.Logger
+	limiter  *rate.Limiter
+
+	// ctx is cancelled on Close to release publishes waiting for a token
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewRateLimitedPublisher wraps provider with the supplied rate limit
+func NewRateLimitedPublisher(cfg config.RateLimitInfo, provider interfaces.StreamProvider,
+	logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	if cfg.Rate <= 0 {
+		return nil, fmt.Errorf("invalid rate limit %v", cfg.Rate)
+	}
+	if cfg.Burst < 0 {
+		return nil, fmt.Errorf("invalid rate limit burst %v", cfg.Burst)
+	}
+	if cfg.Burst == 0 {
+		cfg.Burst = 1
+	}
+	if cfg.Policy == "" {
+		cfg.Policy = contracts.RateLimitBlock
+	}
+	if !cfg.Policy.Validate() {
+		return nil, fmt.Errorf("invalid RateLimitPolicy value provided %s", cfg.Policy)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := rateLimitedPublisher{
+		cfg:      cfg,
+		provider: provider,
+		logger:   logger,
+		limiter:  rate.NewLimiter(rate.Limit(cfg.Rate), cfg.Burst),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	return &p, nil
+}
+
+func (p *rateLimitedPublisher) Connect(ctx context.Context) error {
+	return p.provider.Connect(ctx)
+}
+
+func (p *rateLimitedPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	if p.cfg.Policy == contracts.RateLimitDrop {
+		if !p.limiter.Allow() {
+			p.logger.Error(fmt.Sprintf("dropping %s message, rate of %v per second exceeded", msg.Action, p.cfg.Rate))
+			return ErrRateLimited
+		}
+	} else if err := p.wait(ctx); err != nil {
+		return err
+	}
+	return p.provider.Publish(ctx, msg)
+}
+
+// wait blocks until the limiter allows a publish, returning early when either ctx is done or the publisher is closed
+func (p *rateLimitedPublisher) wait(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(p.ctx, cancel)
+	defer stop()
+	return p.limiter.Wait(ctx)
+}
+
+// Healthy reports the health of the wrapped provider. Publishes delayed or dropped by the rate limit do not make the
+// publisher unhealthy.
+func (p *rateLimitedPublisher) Healthy(ctx context.Context) error {
+	if checker, ok := p.provider.(interfaces.HealthChecker); ok {
+		return checker.Healthy(ctx)
+	}
+	return nil
+}
+
+func (p *rateLimitedPublisher) Close() error {
+	p.cancel()
+	return p.provider.Close()
+}
@@ -0,0 +1,136 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingProvider counts the publishes it receives
+type countingProvider struct {
+	mu        sync.Mutex
+	published int
+}
+
+func (c *countingProvider) Connect(ctx context.Context) error {
+	return nil
+}
+
+func (c *countingProvider) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.published++
+	return nil
+}
+
+func (c *countingProvider) Close() error {
+	return nil
+}
+
+func TestNewRateLimitedPublisher(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	tests := []struct {
+		name        string
+		cfg         config.RateLimitInfo
+		expectError bool
+	}{
+		{"defaults", config.RateLimitInfo{Rate: 10}, false},
+		{"drop policy", config.RateLimitInfo{Rate: 0.5, Burst: 5, Policy: contracts.RateLimitDrop}, false},
+		{"missing rate", config.RateLimitInfo{Burst: 5}, true},
+		{"negative burst", config.RateLimitInfo{Rate: 10, Burst: -1}, true},
+		{"invalid policy", config.RateLimitInfo{Rate: 10, Policy: "queue"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRateLimitedPublisher(tt.cfg, &countingProvider{}, logger)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestRateLimitedPublisher_Drop(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	provider := &countingProvider{}
+	p, err := NewRateLimitedPublisher(config.RateLimitInfo{Rate: 0.1, Burst: 3, Policy: contracts.RateLimitDrop},
+		provider, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	dropped := 0
+	for i := 0; i < 5; i++ {
+		err = p.Publish(context.Background(), message.PublishWrapper{Action: message.ActionCreate})
+		if errors.Is(err, ErrRateLimited) {
+			dropped++
+		}
+	}
+	assert.Equal(t, 3, provider.published)
+	assert.Equal(t, 2, dropped)
+}
+
+func TestRateLimitedPublisher_Block(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	provider := &countingProvider{}
+	p, err := NewRateLimitedPublisher(config.RateLimitInfo{Rate: 20, Burst: 1}, provider, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err = p.Publish(context.Background(), message.PublishWrapper{Action: message.ActionCreate}); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+	assert.Equal(t, 3, provider.published)
+	// The first token is available immediately, the next two arrive at 50ms intervals
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	// Closing releases a publish that is waiting for a token
+	slow, err := NewRateLimitedPublisher(config.RateLimitInfo{Rate: 0.01}, provider, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	slow.Publish(context.Background(), message.PublishWrapper{Action: message.ActionCreate})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		slow.Close()
+	}()
+	assert.Error(t, slow.Publish(context.Background(), message.PublishWrapper{Action: message.ActionCreate}))
+
+	// A publish waiting for a token gives up once its context is done
+	limited, err := NewRateLimitedPublisher(config.RateLimitInfo{Rate: 0.01}, provider, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	limited.Publish(context.Background(), message.PublishWrapper{Action: message.ActionCreate})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = limited.Publish(ctx, message.PublishWrapper{Action: message.ActionCreate})
+	assert.Error(t, err)
+	limited.Close()
+}
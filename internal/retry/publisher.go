@@ -0,0 +1,186 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+const (
+	defaultInitialInterval = 100 * time.Millisecond
+	defaultMaxInterval     = 10 * time.Second
+	defaultMultiplier      = 2.0
+)
+
+// retryingPublisher wraps a stream provider, retrying failed publishes with exponential backoff. Messages exhausting
+// their retries are handed to the dead letter provider when one is supplied.
+type retryingPublisher struct {
+	cfg        config.RetryInfo
+	provider   interfaces.StreamProvider
+	deadLetter interfaces.StreamProvider
+	logger     interfaces.Logger
+	// deadLettering indicates the dead letter provider has connected, messages exhausting their retries are lost
+	// until it does
+	deadLettering atomic.Bool
+
+	initial time.Duration
+	max     time.Duration
+	done    chan struct{}
+	once    sync.Once
+	err     error // err is the result of the first Close
+}
+
+// NewRetryingPublisher wraps provider with the supplied retry policy. deadLetter is optional.
+func NewRetryingPublisher(cfg config.RetryInfo, provider interfaces.StreamProvider,
+	deadLetter interfaces.StreamProvider, logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	if cfg.MaxAttempts < 1 {
+		return nil, fmt.Errorf("invalid retry max attempts %v", cfg.MaxAttempts)
+	}
+	if cfg.InitialInterval < 0 || cfg.MaxInterval < 0 || cfg.Multiplier < 0 {
+		return nil, errors.New("retry intervals and multiplier must not be negative")
+	}
+	if cfg.Jitter < 0 || cfg.Jitter > 1 {
+		return nil, fmt.Errorf("invalid retry jitter %v", cfg.Jitter)
+	}
+	if cfg.Multiplier == 0 {
+		cfg.Multiplier = defaultMultiplier
+	}
+
+	p := retryingPublisher{
+		cfg:        cfg,
+		provider:   provider,
+		deadLetter: deadLetter,
+		logger:     logger,
+		initial:    defaultInitialInterval,
+		max:        defaultMaxInterval,
+		done:       make(chan struct{}),
+	}
+	if cfg.InitialInterval > 0 {
+		p.initial = time.Duration(cfg.InitialInterval) * time.Millisecond
+	}
+	if cfg.MaxInterval > 0 {
+		p.max = time.Duration(cfg.MaxInterval) * time.Millisecond
+	}
+	return &p, nil
+}
+
+func (p *retryingPublisher) Connect(ctx context.Context) error {
+	if p.deadLetter != nil {
+		if err := p.deadLetter.Connect(ctx); err != nil {
+			return fmt.Errorf("failed to connect dead letter provider %w", err)
+		}
+		p.deadLettering.Store(true)
+	}
+	return p.provider.Connect(ctx)
+}
+
+// Publish retries failed publishes with backoff until the configured attempts are exhausted, handing the message to
+// the dead letter provider if one is configured. Retrying stops when ctx is done.
+func (p *retryingPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	var err error
+	for attempt := 1; attempt <= p.cfg.MaxAttempts; attempt++ {
+		if err = p.provider.Publish(ctx, msg); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w while retrying, last error %s", ctx.Err(), err.Error())
+		}
+		if attempt == p.cfg.MaxAttempts {
+			break
+		}
+
+		delay := p.backoff(attempt)
+		p.logger.Write(slog.LevelDebug, fmt.Sprintf("publish attempt %v failed, retrying in %s %s", attempt, delay,
+			err.Error()))
+		select {
+		case <-time.After(delay):
+		case <-p.done:
+			return fmt.Errorf("publisher closed while retrying %w", err)
+		case <-ctx.Done():
+			return fmt.Errorf("%w while retrying, last error %s", ctx.Err(), err.Error())
+		}
+	}
+
+	if p.deadLetter == nil {
+		return err
+	}
+	p.logger.Error(fmt.Sprintf("publish failed after %v attempts, sending to dead letter %s", p.cfg.MaxAttempts,
+		err.Error()))
+	if dlErr := p.deadLetter.Publish(message.WithoutDelivery(ctx), msg); dlErr != nil {
+		return fmt.Errorf("dead letter publish failed %s, original error %w", dlErr.Error(), err)
+	}
+	// the message was set aside rather than delivered
+	message.DeferDelivery(ctx)(fmt.Errorf("publish sent to dead letter after %v attempts %w", p.cfg.MaxAttempts, err))
+	return nil
+}
+
+// Healthy reports the publisher as unhealthy while the dead letter provider, if one is configured, is not connected or
+// reports itself unhealthy, since messages exhausting their retries would be lost. Otherwise it reports the health of
+// the wrapped provider.
+func (p *retryingPublisher) Healthy(ctx context.Context) error {
+	if p.deadLetter != nil {
+		if !p.deadLettering.Load() {
+			return errors.New("dead letter provider is not connected")
+		}
+		if checker, ok := p.deadLetter.(interfaces.HealthChecker); ok {
+			if err := checker.Healthy(ctx); err != nil {
+				return fmt.Errorf("dead letter provider unhealthy %w", err)
+			}
+		}
+	}
+	if checker, ok := p.provider.(interfaces.HealthChecker); ok {
+		return checker.Healthy(ctx)
+	}
+	return nil
+}
+
+// Close stops pending retries and closes the providers. Closing more than once returns the result of the first Close.
+func (p *retryingPublisher) Close() error {
+	p.once.Do(func() {
+		close(p.done)
+		p.deadLettering.Store(false)
+		p.err = p.provider.Close()
+		if p.deadLetter != nil {
+			if dlErr := p.deadLetter.Close(); p.err == nil {
+				p.err = dlErr
+			}
+		}
+	})
+	return p.err
+}
+
+// backoff returns the delay preceding the next attempt after the given number of failed attempts
+func (p *retryingPublisher) backoff(attempt int) time.Duration {
+	delay := float64(p.initial) * math.Pow(p.cfg.Multiplier, float64(attempt-1))
+	if delay > float64(p.max) {
+		delay = float64(p.max)
+	}
+	if p.cfg.Jitter > 0 {
+		delay *= 1 + p.cfg.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(delay)
+}
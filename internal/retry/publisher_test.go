@@ -0,0 +1,187 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingProvider fails the given number of publishes before succeeding
+type failingProvider struct {
+	failures int
+	attempts int
+}
+
+func (f *failingProvider) Connect(ctx context.Context) error {
+	return nil
+}
+
+func (f *failingProvider) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	f.attempts++
+	if f.attempts <= f.failures {
+		return errors.New("unavailable")
+	}
+	return nil
+}
+
+func (f *failingProvider) Close() error {
+	return nil
+}
+
+func TestNewRetryingPublisher(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	tests := []struct {
+		name        string
+		cfg         config.RetryInfo
+		expectError bool
+	}{
+		{"valid policy", config.RetryInfo{MaxAttempts: 3, InitialInterval: 10, Multiplier: 1.5, Jitter: 0.2}, false},
+		{"missing attempts", config.RetryInfo{}, true},
+		{"negative interval", config.RetryInfo{MaxAttempts: 3, InitialInterval: -1}, true},
+		{"invalid jitter", config.RetryInfo{MaxAttempts: 3, Jitter: 1.5}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRetryingPublisher(tt.cfg, &failingProvider{}, nil, logger)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestRetryingPublisher_Publish(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	cfg := config.RetryInfo{MaxAttempts: 3, InitialInterval: 1, MaxInterval: 5}
+
+	tests := []struct {
+		name             string
+		failures         int
+		deadLetter       *failingProvider
+		expectedAttempts int
+		deadLettered     int
+		expectError      bool
+	}{
+		{"first attempt", 0, nil, 1, 0, false},
+		{"succeeds on retry", 2, nil, 3, 0, false},
+		{"exhausted", 5, nil, 3, 0, true},
+		{"exhausted with dead letter", 5, &failingProvider{}, 3, 1, false},
+		{"dead letter failure", 5, &failingProvider{failures: 1}, 3, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &failingProvider{failures: tt.failures}
+			var deadLetter interfaces.StreamProvider
+			if tt.deadLetter != nil {
+				deadLetter = tt.deadLetter
+			}
+			p, err := NewRetryingPublisher(cfg, provider, deadLetter, logger)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			err = p.Publish(context.Background(),
+				message.PublishWrapper{Action: message.ActionCreate, Content: []byte("data")})
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.expectedAttempts, provider.attempts)
+			if tt.deadLetter != nil {
+				assert.Equal(t, tt.deadLettered, tt.deadLetter.attempts)
+			}
+		})
+	}
+}
+
+func TestRetryingPublisher_Canceled(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	deadLetter := &failingProvider{}
+	provider := &failingProvider{failures: 5}
+	p, err := NewRetryingPublisher(config.RetryInfo{MaxAttempts: 5, InitialInterval: 1000}, provider, deadLetter,
+		logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = p.Publish(ctx, message.PublishWrapper{Action: message.ActionCreate, Content: []byte("data")})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, provider.attempts)
+	assert.Equal(t, 0, deadLetter.attempts)
+}
+
+func TestRetryingPublisher_Backoff(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	p, err := NewRetryingPublisher(config.RetryInfo{MaxAttempts: 10, InitialInterval: 100, MaxInterval: 1000,
+		Multiplier: 3}, &failingProvider{}, nil, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	r := p.(*retryingPublisher)
+	assert.Equal(t, 100*time.Millisecond, r.backoff(1))
+	assert.Equal(t, 300*time.Millisecond, r.backoff(2))
+	assert.Equal(t, 900*time.Millisecond, r.backoff(3))
+	assert.Equal(t, 1000*time.Millisecond, r.backoff(4))
+
+	r.cfg.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := r.backoff(1)
+		assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		assert.LessOrEqual(t, delay, 150*time.Millisecond)
+	}
+}
+
+func TestRetryingPublisher_Close(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	p, err := NewRetryingPublisher(config.RetryInfo{MaxAttempts: 3}, &failingProvider{}, &failingProvider{}, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.NoError(t, p.Close())
+	assert.NoError(t, p.Close())
+}
+
+func TestRetryingPublisher_Healthy(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	cfg := config.RetryInfo{MaxAttempts: 3}
+
+	p, err := NewRetryingPublisher(cfg, &failingProvider{}, nil, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.NoError(t, p.(interfaces.HealthChecker).Healthy(context.Background()))
+
+	// Messages exhausting their retries are lost while the dead letter provider is not connected
+	p, err = NewRetryingPublisher(cfg, &failingProvider{}, &failingProvider{}, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	checker := p.(interfaces.HealthChecker)
+	assert.Error(t, checker.Healthy(context.Background()))
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.NoError(t, checker.Healthy(context.Background()))
+	p.Close()
+	assert.Error(t, checker.Healthy(context.Background()))
+}
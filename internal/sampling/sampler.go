@@ -0,0 +1,75 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package sampling
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"golang.org/x/time/rate"
+)
+
+// Sampler decides which data is annotated under a sampling policy and which annotators may run within their
+// per-kind rate limits
+type Sampler struct {
+	cfg      config.SamplingInfo
+	count    atomic.Uint64
+	limiters map[contracts.AnnotationType]*rate.Limiter
+}
+
+// NewSampler returns a Sampler applying the supplied policy
+func NewSampler(cfg config.SamplingInfo) *Sampler {
+	s := Sampler{
+		cfg:      cfg,
+		limiters: make(map[contracts.AnnotationType]*rate.Limiter, len(cfg.Limits)),
+	}
+	for kind, limit := range cfg.Limits {
+		s.limiters[kind] = rate.NewLimiter(rate.Limit(limit), int(math.Max(1, math.Ceil(limit))))
+	}
+	return &s
+}
+
+// NeedsKey reports whether Sample requires the hash of the data to make its decision
+func (s *Sampler) NeedsKey() bool {
+	return s.cfg.Ratio > 0 && s.cfg.Ratio < 1
+}
+
+// Sample reports whether the data identified by key is annotated. Ratio decisions depend only on the key, so every
+// host sampling the same data agrees on it.
+func (s *Sampler) Sample(key string) bool {
+	if s.cfg.Every > 1 && (s.count.Add(1)-1)%uint64(s.cfg.Every) != 0 {
+		return false
+	}
+	if s.NeedsKey() {
+		sum := sha256.Sum256([]byte(key))
+		return float64(binary.BigEndian.Uint64(sum[:8]))/math.MaxUint64 < s.cfg.Ratio
+	}
+	return true
+}
+
+// Allow reports whether n annotations of the given kind may be made without exceeding its rate limit. Kinds without
+// a limit are always allowed.
+func (s *Sampler) Allow(kind contracts.AnnotationType, n int) bool {
+	l, ok := s.limiters[kind]
+	if !ok {
+		return true
+	}
+	return l.AllowN(time.Now(), n)
+}
@@ -0,0 +1,75 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package sampling
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler_Sample(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.SamplingInfo
+		expected int
+	}{
+		{"disabled", config.SamplingInfo{}, 100},
+		{"every nth", config.SamplingInfo{Every: 10}, 10},
+		{"ratio of all", config.SamplingInfo{Ratio: 1}, 100},
+		{"every nth and limits", config.SamplingInfo{Every: 4,
+			Limits: map[contracts.AnnotationType]float64{contracts.AnnotationTPM: 1}}, 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSampler(tt.cfg)
+			sampled := 0
+			for i := 0; i < 100; i++ {
+				if s.Sample(fmt.Sprintf("key-%d", i)) {
+					sampled++
+				}
+			}
+			assert.Equal(t, tt.expected, sampled)
+		})
+	}
+}
+
+func TestSampler_Ratio(t *testing.T) {
+	a := NewSampler(config.SamplingInfo{Ratio: 0.3})
+	b := NewSampler(config.SamplingInfo{Ratio: 0.3})
+
+	sampled := 0
+	for i := 0; i < 10000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		decision := a.Sample(key)
+		// samplers on different hosts agree on the same data
+		assert.Equal(t, decision, b.Sample(key))
+		if decision {
+			sampled++
+		}
+	}
+	assert.InDelta(t, 3000, sampled, 300)
+}
+
+func TestSampler_Allow(t *testing.T) {
+	s := NewSampler(config.SamplingInfo{Limits: map[contracts.AnnotationType]float64{contracts.AnnotationTPM: 2}})
+
+	assert.True(t, s.Allow(contracts.AnnotationTPM, 2))
+	assert.False(t, s.Allow(contracts.AnnotationTPM, 1))
+	assert.True(t, s.Allow(contracts.AnnotationSource, 100))
+}
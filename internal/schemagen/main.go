@@ -0,0 +1,39 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Command schemagen writes the JSON Schema of the SDK configuration, see config.Schema, to the file named by its
+// argument. It is run by go generate in pkg/config.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: schemagen <file>")
+		os.Exit(2)
+	}
+	b, err := config.Schema()
+	if err == nil {
+		err = os.WriteFile(os.Args[1], append(b, '\n'), 0644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
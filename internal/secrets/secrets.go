@@ -0,0 +1,139 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package secrets resolves references to secrets held outside the configuration, so that sensitive values such as
+// passwords and keys need not be written in it in plain text. A reference is a value made of a scheme and a locator:
+//
+//	env:NAME              the value of the environment variable NAME
+//	file:PATH             the content of the file at PATH, less a trailing line break
+//	vault:PATH#FIELD      the field FIELD of the secret at PATH of a HashiCorp Vault server
+//
+// The Vault server is located by the VAULT_ADDR environment variable and the request is authorized with VAULT_TOKEN,
+// in VAULT_NAMESPACE if set. Secrets of both versions of the key/value engine are read, the path of a version 2
+// engine including its data/ segment, e.g. vault:secret/data/mqtt#password.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+const (
+	schemeEnv   = "env:"
+	schemeFile  = "file:"
+	schemeVault = "vault:"
+
+	// vaultTimeout bounds each request to the Vault server
+	vaultTimeout = 10 * time.Second
+	// maxSecretSize bounds the secrets read
+	maxSecretSize = 1 << 20
+)
+
+// IsReference reports whether value is a reference to a secret. Values of the form file://, which are URLs rather
+// than references, are left alone.
+func IsReference(value string) bool {
+	if strings.HasPrefix(value, schemeFile) {
+		return !strings.HasPrefix(value, schemeFile+"//")
+	}
+	return strings.HasPrefix(value, schemeEnv) || strings.HasPrefix(value, schemeVault)
+}
+
+// Resolve returns the secret ref refers to
+func Resolve(ctx context.Context, ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, schemeEnv):
+		name := strings.TrimPrefix(ref, schemeEnv)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("%w: secret environment variable %s is not set", contracts.ErrConfigInvalid, name)
+		}
+		return value, nil
+	case strings.HasPrefix(ref, schemeFile) && IsReference(ref):
+		path := strings.TrimPrefix(ref, schemeFile)
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("%w: secret file cannot be read: %w", contracts.ErrConfigInvalid, err)
+		}
+		return string(bytes.TrimRight(b, "\r\n")), nil
+	case strings.HasPrefix(ref, schemeVault):
+		path, field, ok := strings.Cut(strings.TrimPrefix(ref, schemeVault), "#")
+		if !ok || path == "" || field == "" {
+			return "", fmt.Errorf("%w: vault secret reference %s must be of the form vault:PATH#FIELD",
+				contracts.ErrConfigInvalid, ref)
+		}
+		return readVault(ctx, strings.Trim(path, "/"), field)
+	}
+	return "", fmt.Errorf("%w: %s is not a secret reference", contracts.ErrConfigInvalid, ref)
+}
+
+// readVault reads a field of the secret at path from the Vault server given by the environment
+func readVault(ctx context.Context, path, field string) (string, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return "", fmt.Errorf("%w: VAULT_ADDR is required to read vault secret %s", contracts.ErrConfigInvalid, path)
+	}
+	ctx, cancel := context.WithTimeout(ctx, vaultTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: vault secret %s cannot be read: %w", contracts.ErrConfigInvalid, path, err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxSecretSize))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: vault returned %s reading secret %s", contracts.ErrConfigInvalid, resp.Status, path)
+	}
+
+	var secret struct {
+		Data map[string]any `json:"data"`
+	}
+	if err = json.Unmarshal(b, &secret); err != nil {
+		return "", fmt.Errorf("%w: malformed vault secret %s: %w", contracts.ErrConfigInvalid, path, err)
+	}
+	data := secret.Data
+	// version 2 of the key/value engine nests the fields in data, alongside metadata
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, ok = data["metadata"]; ok {
+			data = nested
+		}
+	}
+	value, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: vault secret %s has no field %s", contracts.ErrConfigInvalid, path, field)
+	}
+	return value, nil
+}
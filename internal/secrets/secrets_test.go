@@ -0,0 +1,108 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReference(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{"env:MQTT_PASSWORD", true},
+		{"file:/run/secrets/key", true},
+		{"vault:secret/data/mqtt#password", true},
+		{"file:///etc/alvarium/jwks.json", false},
+		{"password", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsReference(tt.value))
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/mqtt": // version 2 of the key/value engine
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+				"data":     map[string]any{"password": "kv2-secret"},
+				"metadata": map[string]any{"version": 3},
+			}})
+		case "/v1/kv/hedera": // version 1
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"key": "kv1-secret"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer vault.Close()
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN", "root")
+	t.Setenv("MQTT_PASSWORD", "env-secret")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "key")
+	if err := os.WriteFile(path, []byte("file-secret\n"), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		ref         string
+		expected    string
+		expectError bool
+	}{
+		{"env", "env:MQTT_PASSWORD", "env-secret", false},
+		{"env unset", "env:MISSING_PASSWORD", "", true},
+		{"file", "file:" + path, "file-secret", false},
+		{"file missing", "file:" + filepath.Join(dir, "missing"), "", true},
+		{"vault kv2", "vault:secret/data/mqtt#password", "kv2-secret", false},
+		{"vault kv1", "vault:/kv/hedera#key", "kv1-secret", false},
+		{"vault missing field", "vault:kv/hedera#password", "", true},
+		{"vault missing secret", "vault:kv/missing#key", "", true},
+		{"vault no field", "vault:kv/hedera", "", true},
+		{"not a reference", "password", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := Resolve(context.Background(), tt.ref)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				assert.True(t, errors.Is(err, contracts.ErrConfigInvalid))
+				return
+			}
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
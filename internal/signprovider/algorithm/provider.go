@@ -0,0 +1,95 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package algorithm adapts an interfaces.KeyAlgorithm to an interfaces.SignatureProvider, taking care of reading and
+// caching keys and of encoding signatures so that an algorithm only deals with crypto.Signer and crypto.PublicKey.
+package algorithm
+
+import (
+	"crypto"
+	"encoding/hex"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/merklesig"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/keycache"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// Provider is a SignatureProvider over a KeyAlgorithm.
+type Provider struct {
+	alg     interfaces.KeyAlgorithm
+	private *keycache.Cache[crypto.Signer]
+	public  *keycache.Cache[crypto.PublicKey]
+}
+
+// New returns a provider signing with the given algorithm. Key files are checked for changes at most once per
+// interval, an interval of zero disables reloading.
+func New(alg interfaces.KeyAlgorithm, interval time.Duration) *Provider {
+	return &Provider{
+		alg:     alg,
+		private: keycache.New(alg.ParsePrivateKey, interval),
+		public:  keycache.New(alg.ParsePublicKey, interval),
+	}
+}
+
+// LoadKeys reads the configured signing keys ahead of their first use
+func (p *Provider) LoadKeys(keys config.SignatureInfo) error {
+	return p.private.Load(keys)
+}
+
+// Sign signs the content with the key's Signer if given, otherwise with the private key it describes. The signature
+// is returned hex encoded.
+func (p *Provider) Sign(key config.KeyInfo, content []byte) (string, error) {
+	signer := key.Signer
+	if signer == nil {
+		prv, err := p.private.Get(key)
+		if err != nil {
+			return "", err
+		}
+		signer = prv
+	}
+
+	signed, err := p.alg.Sign(signer, content)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(signed), nil
+}
+
+// SignBatch signs the Merkle root of the contents once, returning a signature for each content
+func (p *Provider) SignBatch(key config.KeyInfo, contents [][]byte) ([]string, error) {
+	return merklesig.Sign(p, key, contents)
+}
+
+// Verify checks a signature produced by Sign with the public key the key describes, or else with the public key of
+// the key's Signer. A signature that is not hex encoded does not verify.
+func (p *Provider) Verify(key config.KeyInfo, content, signature []byte) (bool, error) {
+	var pub crypto.PublicKey
+	if key.Signer != nil && !key.HasKey() {
+		pub = key.Signer.Public()
+	} else {
+		parsed, err := p.public.Get(key)
+		if err != nil {
+			return false, err
+		}
+		pub = parsed
+	}
+
+	sigDecoded, err := hex.DecodeString(string(signature))
+	if err != nil {
+		return false, nil
+	}
+	return p.alg.Verify(pub, content, sigDecoded)
+}
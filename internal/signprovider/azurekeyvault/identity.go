@@ -0,0 +1,125 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package azurekeyvault
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// imdsUrl is the Azure Instance Metadata Service token endpoint available to virtual machines
+	imdsUrl = "http://169.254.169.254/metadata/identity/oauth2/token"
+	// tokenRefreshMargin renews tokens this long before they expire so in-flight requests are not rejected
+	tokenRefreshMargin = 5 * time.Minute
+)
+
+// accessToken is an OAuth2 bearer token issued for a managed identity
+type accessToken struct {
+	value   string
+	expires time.Time
+}
+
+// managedIdentity obtains tokens for the host's managed identity. App Service, Container Apps and Functions publish
+// their token endpoint through IDENTITY_ENDPOINT and IDENTITY_HEADER; elsewhere the instance metadata service is used.
+type managedIdentity struct {
+	client  *http.Client
+	imdsUrl string
+
+	mu     sync.Mutex
+	tokens map[string]accessToken // keyed by resource and client id
+}
+
+func newManagedIdentity(client *http.Client) *managedIdentity {
+	return &managedIdentity{
+		client:  client,
+		imdsUrl: imdsUrl,
+		tokens:  make(map[string]accessToken),
+	}
+}
+
+// token returns a cached token for the resource if it is still valid, otherwise it requests a new one
+func (m *managedIdentity) token(resource, clientId string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cacheKey := resource + "|" + clientId
+	if t, ok := m.tokens[cacheKey]; ok && time.Now().Add(tokenRefreshMargin).Before(t.expires) {
+		return t.value, nil
+	}
+	t, err := m.request(resource, clientId)
+	if err != nil {
+		return "", err
+	}
+	m.tokens[cacheKey] = t
+	return t.value, nil
+}
+
+func (m *managedIdentity) request(resource, clientId string) (accessToken, error) {
+	q := url.Values{}
+	q.Set("resource", resource)
+	if clientId != "" {
+		q.Set("client_id", clientId)
+	}
+
+	endpoint, secret := os.Getenv("IDENTITY_ENDPOINT"), os.Getenv("IDENTITY_HEADER")
+	var req *http.Request
+	var err error
+	if endpoint != "" && secret != "" {
+		q.Set("api-version", "2019-08-01")
+		req, err = http.NewRequest(http.MethodGet, endpoint+"?"+q.Encode(), nil)
+		if err != nil {
+			return accessToken{}, err
+		}
+		req.Header.Set("X-IDENTITY-HEADER", secret)
+	} else {
+		q.Set("api-version", "2018-02-01")
+		req, err = http.NewRequest(http.MethodGet, m.imdsUrl+"?"+q.Encode(), nil)
+		if err != nil {
+			return accessToken{}, err
+		}
+		req.Header.Set("Metadata", "true")
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return accessToken{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return accessToken{}, fmt.Errorf("managed identity token request returned %s %s", resp.Status, string(body))
+	}
+
+	// expires_on is a string of epoch seconds from both endpoints
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresOn   string `json:"expires_on"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return accessToken{}, err
+	}
+	expires, err := strconv.ParseInt(body.ExpiresOn, 10, 64)
+	if err != nil {
+		return accessToken{}, fmt.Errorf("invalid managed identity token expiry %s", body.ExpiresOn)
+	}
+	return accessToken{value: body.AccessToken, expires: time.Unix(expires, 0)}, nil
+}
@@ -0,0 +1,221 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package azurekeyvault
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/merklesig"
+	ecdsaprovider "github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ecdsa"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+)
+
+const (
+	apiVersion     = "7.4"
+	requestTimeout = 30 * time.Second
+	// p256Size is the length in bytes of a P-256 scalar
+	p256Size = 32
+)
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct {
+	client   *http.Client
+	identity *managedIdentity
+
+	mu   sync.Mutex
+	keys map[string]*ecdsa.PublicKey // public keys fetched for verification, keyed by key URL
+}
+
+// New is a factory function that returns an initialized provider.
+func New() *provider {
+	client := &http.Client{Timeout: requestTimeout}
+	return &provider{
+		client:   client,
+		identity: newManagedIdentity(client),
+		keys:     make(map[string]*ecdsa.PublicKey),
+	}
+}
+
+// Sign has Key Vault sign the SHA-256 digest of the content with the ES256 key given by KeyInfo.AzureKeyVault. The
+// private key never leaves the vault. The signature is returned as hex encoded ASN.1 DER so that it is
+// interchangeable with the ecdsa-p256 provider.
+func (p *provider) Sign(key config.KeyInfo, content []byte) (string, error) {
+	if key.AzureKeyVault == nil {
+		return "", errors.New("azureKeyVault settings are required")
+	}
+	digest := sha256.Sum256(content)
+	body, _ := json.Marshal(map[string]string{
+		"alg":   "ES256",
+		"value": base64.RawURLEncoding.EncodeToString(digest[:]),
+	})
+
+	var result struct {
+		Value string `json:"value"`
+	}
+	err := p.do(*key.AzureKeyVault, http.MethodPost, "/sign", body, &result)
+	if err != nil {
+		return "", err
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(result.Value)
+	if err != nil {
+		return "", err
+	}
+	if len(raw) != 2*p256Size {
+		return "", errors.New("unexpected ES256 signature length from key vault")
+	}
+	signed, err := asn1.Marshal(struct{ R, S *big.Int }{
+		R: new(big.Int).SetBytes(raw[:p256Size]),
+		S: new(big.Int).SetBytes(raw[p256Size:]),
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(signed), nil
+}
+
+// SignBatch signs the Merkle root of the contents with a single request to the vault, returning a signature for
+// each content
+func (p *provider) SignBatch(key config.KeyInfo, contents [][]byte) ([]string, error) {
+	return merklesig.Sign(p, key, contents)
+}
+
+// Verify checks a signature produced by Sign. When KeyInfo has a Path or Material the public key is read from PEM, as
+// for the ecdsa-p256 provider, so that verifiers need no access to the vault. Otherwise the public key is fetched
+// from the vault once and cached. Verification happens locally in either case.
+func (p *provider) Verify(key config.KeyInfo, content, signature []byte) (bool, error) {
+	if key.HasKey() {
+		return ecdsaprovider.New().Verify(key, content, signature)
+	}
+	if key.AzureKeyVault == nil {
+		return false, errors.New("azureKeyVault settings or a public key path are required")
+	}
+	pub, err := p.publicKey(*key.AzureKeyVault)
+	if err != nil {
+		return false, err
+	}
+
+	sigDecoded, err := hex.DecodeString(string(signature))
+	if err != nil {
+		return false, nil
+	}
+	digest := sha256.Sum256(content)
+	return ecdsa.VerifyASN1(pub, digest[:], sigDecoded), nil
+}
+
+func (p *provider) publicKey(info config.AzureKeyVaultInfo) (*ecdsa.PublicKey, error) {
+	keyUrl := keyUrl(info)
+	p.mu.Lock()
+	pub, ok := p.keys[keyUrl]
+	p.mu.Unlock()
+	if ok {
+		return pub, nil
+	}
+
+	var result struct {
+		Key struct {
+			Kty string `json:"kty"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"key"`
+	}
+	if err := p.do(info, http.MethodGet, "", nil, &result); err != nil {
+		return nil, err
+	}
+	if (result.Key.Kty != "EC" && result.Key.Kty != "EC-HSM") || result.Key.Crv != "P-256" {
+		return nil, fmt.Errorf("key vault key %s is not an EC P-256 key", info.KeyName)
+	}
+	x, errX := base64.RawURLEncoding.DecodeString(result.Key.X)
+	y, errY := base64.RawURLEncoding.DecodeString(result.Key.Y)
+	if errX != nil || errY != nil {
+		return nil, fmt.Errorf("invalid public key for key vault key %s", info.KeyName)
+	}
+	pub = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, fmt.Errorf("invalid public key for key vault key %s", info.KeyName)
+	}
+
+	p.mu.Lock()
+	p.keys[keyUrl] = pub
+	p.mu.Unlock()
+	return pub, nil
+}
+
+// do sends an authorized request for an operation on the key and decodes the JSON response into result
+func (p *provider) do(info config.AzureKeyVaultInfo, method, operation string, body []byte, result interface{}) error {
+	resource, err := resourceFor(info.VaultUrl)
+	if err != nil {
+		return err
+	}
+	token, err := p.identity.token(resource, info.ClientId)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, keyUrl(info)+operation+"?api-version="+apiVersion, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("key vault returned %s %s", resp.Status, string(b))
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func keyUrl(info config.AzureKeyVaultInfo) string {
+	u := strings.TrimSuffix(info.VaultUrl, "/") + "/keys/" + url.PathEscape(info.KeyName)
+	if info.KeyVersion != "" {
+		u += "/" + url.PathEscape(info.KeyVersion)
+	}
+	return u
+}
+
+// resourceFor derives the token audience from the vault's DNS suffix so sovereign clouds, whose vaults live under
+// e.g. vault.azure.cn, are supported as well as the public cloud.
+func resourceFor(vaultUrl string) (string, error) {
+	u, err := url.Parse(vaultUrl)
+	if err != nil {
+		return "", err
+	}
+	_, suffix, found := strings.Cut(u.Hostname(), ".")
+	if !found {
+		return "", fmt.Errorf("invalid key vault url %s", vaultUrl)
+	}
+	return "https://" + suffix, nil
+}
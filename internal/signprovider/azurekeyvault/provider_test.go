@@ -0,0 +1,168 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package azurekeyvault
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVault serves the token and key endpoints used by the provider
+type fakeVault struct {
+	key          *ecdsa.PrivateKey
+	tokens       atomic.Int32
+	keyRequests  atomic.Int32
+	identityAuth string
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/token", "/appservice":
+		if r.Header.Get(v.identityAuth) == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		v.tokens.Add(1)
+		expires := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "token", "expires_on": expires})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch r.URL.Path {
+	case "/keys/signer/sign":
+		var req struct{ Alg, Value string }
+		json.NewDecoder(r.Body).Decode(&req)
+		digest, _ := base64.RawURLEncoding.DecodeString(req.Value)
+		sr, ss, _ := ecdsa.Sign(rand.Reader, v.key, digest)
+		raw := make([]byte, 2*p256Size)
+		sr.FillBytes(raw[:p256Size])
+		ss.FillBytes(raw[p256Size:])
+		json.NewEncoder(w).Encode(map[string]string{"value": base64.RawURLEncoding.EncodeToString(raw)})
+	case "/keys/signer":
+		v.keyRequests.Add(1)
+		x := make([]byte, p256Size)
+		y := make([]byte, p256Size)
+		v.key.X.FillBytes(x)
+		v.key.Y.FillBytes(y)
+		json.NewEncoder(w).Encode(map[string]interface{}{"key": map[string]string{
+			"kty": "EC",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(x),
+			"y":   base64.RawURLEncoding.EncodeToString(y),
+		}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newSUT(t *testing.T, identityAuth string) (*provider, *fakeVault, string) {
+	k, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	vault := &fakeVault{key: k, identityAuth: identityAuth}
+	server := httptest.NewServer(vault)
+	t.Cleanup(server.Close)
+
+	sut := New()
+	sut.identity.imdsUrl = server.URL + "/token"
+	return sut, vault, server.URL
+}
+
+func TestProvider_SignVerify(t *testing.T) {
+	sut, vault, vaultUrl := newSUT(t, "Metadata")
+
+	tests := []struct {
+		name        string
+		keyName     string
+		expectError bool
+	}{
+		{"valid key", "signer", false},
+		{"missing key", "missing", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := config.KeyInfo{
+				Type:          contracts.KeyAzureKeyVault,
+				AzureKeyVault: &config.AzureKeyVaultInfo{VaultUrl: vaultUrl, KeyName: tt.keyName},
+			}
+			content := []byte("foo")
+			signed, err := sut.Sign(key, content)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+
+			ok, err := sut.Verify(key, content, []byte(signed))
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = sut.Verify(key, []byte("bar"), []byte(signed))
+			assert.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+
+	// Tokens and public keys are fetched once and reused
+	assert.Equal(t, int32(1), vault.tokens.Load())
+	assert.Equal(t, int32(1), vault.keyRequests.Load())
+}
+
+func TestProvider_AppServiceIdentity(t *testing.T) {
+	sut, vault, vaultUrl := newSUT(t, "X-IDENTITY-HEADER")
+	t.Setenv("IDENTITY_ENDPOINT", vaultUrl+"/appservice")
+	t.Setenv("IDENTITY_HEADER", "secret")
+
+	key := config.KeyInfo{
+		Type:          contracts.KeyAzureKeyVault,
+		AzureKeyVault: &config.AzureKeyVaultInfo{VaultUrl: vaultUrl, KeyName: "signer"},
+	}
+	_, err := sut.Sign(key, []byte("foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), vault.tokens.Load())
+}
+
+func TestResourceFor(t *testing.T) {
+	tests := []struct {
+		name        string
+		vaultUrl    string
+		expected    string
+		expectError bool
+	}{
+		{"public cloud", "https://alvarium.vault.azure.net", "https://vault.azure.net", false},
+		{"sovereign cloud", "https://alvarium.vault.azure.cn/", "https://vault.azure.cn", false},
+		{"no suffix", "https://localhost", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, err := resourceFor(tt.vaultUrl)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.expected, resource)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package ecdsa
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/algorithm"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/jwk"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/youmark/pkcs8"
+)
+
+// keyAlgorithm implements interfaces.KeyAlgorithm for P-256 keys
+type keyAlgorithm struct{}
+
+// New is a factory function that returns an initialized provider. Key files are read once and never reloaded.
+func New() *algorithm.Provider {
+	return NewWithReload(0)
+}
+
+// NewWithReload returns a provider that checks key files for changes at most once per interval
+func NewWithReload(interval time.Duration) *algorithm.Provider {
+	return algorithm.New(keyAlgorithm{}, interval)
+}
+
+// Sign signs the SHA-256 digest of the content. The signature is ASN.1 DER.
+func (keyAlgorithm) Sign(signer crypto.Signer, content []byte) ([]byte, error) {
+	digest := sha256.Sum256(content)
+	return signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// Verify checks a signature produced by Sign with a P-256 public key
+func (keyAlgorithm) Verify(key crypto.PublicKey, content, signature []byte) (bool, error) {
+	pub, err := toPublicKey(key, "signer")
+	if err != nil {
+		return false, err
+	}
+	digest := sha256.Sum256(content)
+	return ecdsa.VerifyASN1(pub, digest[:], signature), nil
+}
+
+// ParsePrivateKey parses a P-256 private key from PEM in either PKCS#8 or SEC 1 form, or from a JWK. A PKCS#8 key may
+// be encrypted with the key's passphrase.
+func (keyAlgorithm) ParsePrivateKey(b []byte, key config.KeyInfo) (crypto.Signer, error) {
+	return parsePrivateKey(b, key)
+}
+
+// ParsePublicKey parses a P-256 public key from PEM in PKIX form or from a JWK
+func (keyAlgorithm) ParsePublicKey(b []byte, key config.KeyInfo) (crypto.PublicKey, error) {
+	return parsePublicKey(b, key)
+}
+
+func parsePrivateKey(b []byte, key config.KeyInfo) (*ecdsa.PrivateKey, error) {
+	path := key.Source()
+	parsed, err := parsePrivatePem(b, key)
+	if err != nil {
+		return nil, err
+	}
+
+	prv, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok || prv.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("%s is not a P-256 private key", path)
+	}
+	return prv, nil
+}
+
+// parsePrivatePem parses a private key from PEM, or from a JWK
+func parsePrivatePem(b []byte, key config.KeyInfo) (interface{}, error) {
+	if jwk.IsJWK(b) {
+		return jwk.Parse(b, key)
+	}
+	path := key.Source()
+	block, err := decodePem(b, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "ENCRYPTED PRIVATE KEY":
+		var passphrase []byte
+		if passphrase, err = key.ReadPassphrase(); err == nil {
+			parsed, err = pkcs8.ParsePKCS8PrivateKey(block.Bytes, passphrase)
+		}
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unexpected PEM block %s in %s", block.Type, path)
+	}
+	return parsed, err
+}
+
+func parsePublicKey(b []byte, key config.KeyInfo) (*ecdsa.PublicKey, error) {
+	path := key.Source()
+	if jwk.IsJWK(b) {
+		parsed, err := jwk.Parse(b, key)
+		if err != nil {
+			return nil, err
+		}
+		return toPublicKey(parsed, path)
+	}
+	block, err := decodePem(b, path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("unexpected PEM block %s in %s", block.Type, path)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return toPublicKey(parsed, path)
+}
+
+func toPublicKey(parsed crypto.PublicKey, path string) (*ecdsa.PublicKey, error) {
+	pub, ok := parsed.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("%s is not a P-256 public key", path)
+	}
+	return pub, nil
+}
+
+func decodePem(b []byte, path string) (*pem.Block, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no PEM data found in " + path)
+	}
+	return block, nil
+}
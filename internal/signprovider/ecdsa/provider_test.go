@@ -0,0 +1,178 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/youmark/pkcs8"
+)
+
+const keyPath = "../../../test/keys/ecdsa-p256"
+
+func TestProvider_SignVerify(t *testing.T) {
+	// A P-384 key must be rejected rather than silently used
+	k, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	b, _ := x509.MarshalPKCS8PrivateKey(k)
+	p384 := filepath.Join(t.TempDir(), "p384.pem")
+	if err := os.WriteFile(p384, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: b}), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	public := config.KeyInfo{Type: contracts.KeyEcdsaP256, Path: filepath.Join(keyPath, "public.pem")}
+	tests := []struct {
+		name        string
+		private     string
+		expectError bool
+	}{
+		{"pkcs8 private key", filepath.Join(keyPath, "private.pem"), false},
+		{"sec1 private key", filepath.Join(keyPath, "private-sec1.pem"), false},
+		{"public key in place of private", filepath.Join(keyPath, "public.pem"), true},
+		{"wrong curve", p384, true},
+		{"missing key", filepath.Join(keyPath, "missing.pem"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sut := New()
+			content := []byte("foo")
+			signed, err := sut.Sign(config.KeyInfo{Type: contracts.KeyEcdsaP256, Path: tt.private}, content)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+
+			ok, err := sut.Verify(public, content, []byte(signed))
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = sut.Verify(public, []byte("bar"), []byte(signed))
+			assert.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestProvider_VerifyInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		public      string
+		signature   string
+		expectError bool
+	}{
+		{"malformed signature", filepath.Join(keyPath, "public.pem"), "not hex", false},
+		{"private key in place of public", filepath.Join(keyPath, "private.pem"), "00", true},
+		{"missing key", filepath.Join(keyPath, "missing.pem"), "00", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := New().Verify(config.KeyInfo{Type: contracts.KeyEcdsaP256, Path: tt.public}, []byte("foo"),
+				[]byte(tt.signature))
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestProvider_InMemoryKeys(t *testing.T) {
+	private, err := os.ReadFile(filepath.Join(keyPath, "private.pem"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	public, err := os.ReadFile(filepath.Join(keyPath, "public.pem"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	signer, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	tests := []struct {
+		name    string
+		private config.KeyInfo
+		public  config.KeyInfo
+	}{
+		{"material", config.KeyInfo{Type: contracts.KeyEcdsaP256, Material: private},
+			config.KeyInfo{Type: contracts.KeyEcdsaP256, Material: public}},
+		{"signer", config.KeyInfo{Type: contracts.KeyEcdsaP256, Signer: signer},
+			config.KeyInfo{Type: contracts.KeyEcdsaP256, Signer: signer}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sut := New()
+			signed, err := sut.Sign(tt.private, []byte("foo"))
+			assert.NoError(t, err)
+
+			ok, err := sut.Verify(tt.public, []byte("foo"), []byte(signed))
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+
+	// A signer holding a key on another curve must be rejected for verification
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	_, err = New().Verify(config.KeyInfo{Type: contracts.KeyEcdsaP256, Signer: p384}, []byte("foo"), []byte("00"))
+	assert.Error(t, err)
+}
+
+func TestProvider_EncryptedKey(t *testing.T) {
+	k, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, err := pkcs8.MarshalPrivateKey(k, []byte("secret"), nil)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "encrypted.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+	passphraseFile := filepath.Join(dir, "passphrase")
+	if err := os.WriteFile(passphraseFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+	t.Setenv("ALVARIUM_TEST_PASSPHRASE", "guess")
+
+	tests := []struct {
+		name        string
+		key         config.KeyInfo
+		expectError bool
+	}{
+		{"passphrase file", config.KeyInfo{Path: path, PassphraseFile: passphraseFile}, false},
+		{"wrong passphrase", config.KeyInfo{Path: path, PassphraseEnv: "ALVARIUM_TEST_PASSPHRASE"}, true},
+		{"missing passphrase", config.KeyInfo{Path: path}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sut := New()
+			tt.key.Type = contracts.KeyEcdsaP256
+			signed, err := sut.Sign(tt.key, []byte("foo"))
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+
+			ok, err := sut.Verify(config.KeyInfo{Type: contracts.KeyEcdsaP256, Signer: k}, []byte("foo"), []byte(signed))
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+}
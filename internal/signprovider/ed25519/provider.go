@@ -15,43 +15,100 @@
 package ed25519
 
 import (
+	"crypto"
 	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
 	"encoding/hex"
+	"encoding/pem"
 	"fmt"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/algorithm"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/jwk"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
-	"os"
+	"github.com/youmark/pkcs8"
 )
 
-// provider is a receiver that encapsulates required dependencies.
-type provider struct{}
+// keyAlgorithm implements interfaces.KeyAlgorithm for ed25519 keys
+type keyAlgorithm struct{}
 
-// New is a factory function that returns an initialized provider.
-func New() *provider {
-	return &provider{}
+// New is a factory function that returns an initialized provider. Key files are read once and never reloaded.
+func New() *algorithm.Provider {
+	return NewWithReload(0)
 }
 
-func (p *provider) Sign(key config.KeyInfo, content []byte) (string, error) {
-	prv, err := os.ReadFile(key.Path)
-	if err != nil {
-		return "", err
+// NewWithReload returns a provider that checks key files for changes at most once per interval
+func NewWithReload(interval time.Duration) *algorithm.Provider {
+	return algorithm.New(keyAlgorithm{}, interval)
+}
+
+// Sign signs the content as is, ed25519 hashing it internally
+func (keyAlgorithm) Sign(signer crypto.Signer, content []byte) ([]byte, error) {
+	return signer.Sign(rand.Reader, content, crypto.Hash(0))
+}
+
+// Verify checks the signature with an ed25519 public key
+func (keyAlgorithm) Verify(key crypto.PublicKey, content, signature []byte) (bool, error) {
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return false, fmt.Errorf("%T is not an ed25519 public key", key)
 	}
+	return ed25519.Verify(pub, content, signature), nil
+}
 
-	keyDecoded := make([]byte, hex.DecodedLen(len(prv)))
-	hex.Decode(keyDecoded, prv)
-	signed := ed25519.Sign(keyDecoded, content)
-	return fmt.Sprintf("%x", signed), nil
+// ParsePrivateKey parses a hex encoded private key, a JWK or PKCS#8 PEM which may be encrypted with the key's
+// passphrase
+func (keyAlgorithm) ParsePrivateKey(b []byte, key config.KeyInfo) (crypto.Signer, error) {
+	parsed, err := decodeKey(b, key)
+	if err != nil {
+		return nil, err
+	}
+	prv, ok := parsed.(ed25519.PrivateKey)
+	if !ok || len(prv) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("%s is not an ed25519 private key", key.Source())
+	}
+	return prv, nil
 }
 
-func (p *provider) Verify(key config.KeyInfo, content, signature []byte) (bool, error) {
-	pub, err := os.ReadFile(key.Path)
+// ParsePublicKey parses a hex encoded public key or a JWK
+func (keyAlgorithm) ParsePublicKey(b []byte, key config.KeyInfo) (crypto.PublicKey, error) {
+	parsed, err := decodeKey(b, key)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
+	pub, ok := parsed.(ed25519.PublicKey)
+	if !ok || len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%s is not an ed25519 public key", key.Source())
+	}
+	return pub, nil
+}
 
-	keyDecoded := make([]byte, hex.DecodedLen(len(pub)))
-	hex.Decode(keyDecoded, pub)
+// decodeKey decodes a JWK or PKCS#8 PEM into the key it holds. Anything else is taken as a hex encoded key whose
+// length tells a private key from a public one.
+func decodeKey(b []byte, key config.KeyInfo) (interface{}, error) {
+	if jwk.IsJWK(b) {
+		return jwk.Parse(b, key)
+	}
+	if block, _ := pem.Decode(b); block != nil {
+		switch block.Type {
+		case "PRIVATE KEY":
+			return x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "ENCRYPTED PRIVATE KEY":
+			passphrase, err := key.ReadPassphrase()
+			if err != nil {
+				return nil, err
+			}
+			return pkcs8.ParsePKCS8PrivateKey(block.Bytes, passphrase)
+		default:
+			return nil, fmt.Errorf("unexpected PEM block %s in %s", block.Type, key.Source())
+		}
+	}
 
-	sigDecoded := make([]byte, hex.DecodedLen(len(signature)))
-	hex.Decode(sigDecoded, signature)
-	return ed25519.Verify(keyDecoded, content, sigDecoded), nil
+	keyDecoded := make([]byte, hex.DecodedLen(len(b)))
+	hex.Decode(keyDecoded, b)
+	if len(keyDecoded) == ed25519.PublicKeySize {
+		return ed25519.PublicKey(keyDecoded), nil
+	}
+	return ed25519.PrivateKey(keyDecoded), nil
 }
@@ -0,0 +1,136 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package ed25519
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/youmark/pkcs8"
+)
+
+const keyPath = "../../../test/keys/ed25519"
+
+func TestProvider_SignVerify(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(keyPath, "private.key"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	prv, _ := hex.DecodeString(strings.TrimSpace(string(b)))
+
+	// The repository key is written in the PKCS#8 forms, both plain and encrypted
+	dir := t.TempDir()
+	plain, _ := x509.MarshalPKCS8PrivateKey(ed25519.PrivateKey(prv))
+	encrypted, err := pkcs8.MarshalPrivateKey(ed25519.PrivateKey(prv), []byte("secret"), nil)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	writePem := func(name, blockType string, der []byte) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+			t.Fatalf(err.Error())
+		}
+		return p
+	}
+	plainPath := writePem("private.pem", "PRIVATE KEY", plain)
+	encryptedPath := writePem("encrypted.pem", "ENCRYPTED PRIVATE KEY", encrypted)
+	passphrase := func(p string) func() ([]byte, error) {
+		return func() ([]byte, error) { return []byte(p), nil }
+	}
+
+	public := config.KeyInfo{Type: contracts.KeyEd25519, Path: filepath.Join(keyPath, "public.key")}
+	tests := []struct {
+		name        string
+		private     config.KeyInfo
+		expectError bool
+	}{
+		{"hex private key", config.KeyInfo{Path: filepath.Join(keyPath, "private.key")}, false},
+		{"pkcs8 private key", config.KeyInfo{Path: plainPath}, false},
+		{"encrypted private key", config.KeyInfo{Path: encryptedPath, Passphrase: passphrase("secret")}, false},
+		{"wrong passphrase", config.KeyInfo{Path: encryptedPath, Passphrase: passphrase("guess")}, true},
+		{"missing passphrase", config.KeyInfo{Path: encryptedPath}, true},
+		{"public key in place of private", config.KeyInfo{Path: filepath.Join(keyPath, "public.key")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sut := New()
+			tt.private.Type = contracts.KeyEd25519
+			signed, err := sut.Sign(tt.private, []byte("foo"))
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+
+			ok, err := sut.Verify(public, []byte("foo"), []byte(signed))
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func TestProvider_JWK(t *testing.T) {
+	pub, prv, _ := ed25519.GenerateKey(nil)
+	private, _ := json.Marshal(jose.JSONWebKey{Key: prv, KeyID: "2024-06"})
+	set, _ := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: pub, KeyID: "2024-06"},
+		{Key: prv.Public(), KeyID: "2024-01"},
+	}})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(set)
+	}))
+	defer server.Close()
+
+	privatePath := filepath.Join(t.TempDir(), "private.json")
+	if err := os.WriteFile(privatePath, private, 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	sut := New()
+	signed, err := sut.Sign(config.KeyInfo{Type: contracts.KeyEd25519, Path: privatePath}, []byte("foo"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		id          string
+		expectError bool
+	}{
+		{"key in set", "2024-06", false},
+		{"key missing from set", "2023-01", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			public := config.KeyInfo{Type: contracts.KeyEd25519, Url: server.URL, Id: tt.id}
+			ok, err := sut.Verify(public, []byte("foo"), []byte(signed))
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, !tt.expectError, ok)
+		})
+	}
+}
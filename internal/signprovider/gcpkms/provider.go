@@ -0,0 +1,283 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package gcpkms
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/merklesig"
+	ecdsaprovider "github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ecdsa"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	defaultEndpoint        = "https://cloudkms.googleapis.com"
+	cloudKmsScope          = "https://www.googleapis.com/auth/cloudkms"
+	requestTimeout         = 30 * time.Second
+	defaultMaxAttempts     = 5
+	defaultInitialInterval = 100 * time.Millisecond
+	defaultMaxInterval     = 10 * time.Second
+	defaultMultiplier      = 2.0
+	defaultJitter          = 0.2
+	p256Algorithm          = "EC_SIGN_P256_SHA256"
+)
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct {
+	endpoint    string
+	tokenSource func(ctx context.Context, credentialsFile string) (oauth2.TokenSource, error)
+
+	mu      sync.Mutex
+	clients map[string]*http.Client     // authorized clients, keyed by credentials file
+	keys    map[string]*ecdsa.PublicKey // public keys fetched for verification, keyed by key name
+}
+
+// New is a factory function that returns an initialized provider.
+func New() *provider {
+	return &provider{
+		endpoint:    defaultEndpoint,
+		tokenSource: tokenSource,
+		clients:     make(map[string]*http.Client),
+		keys:        make(map[string]*ecdsa.PublicKey),
+	}
+}
+
+// Sign has Cloud KMS sign the SHA-256 digest of the content with the key version given by KeyInfo.GcpKms. The
+// private key never leaves KMS. The signature is returned as hex encoded ASN.1 DER so that it is interchangeable
+// with the ecdsa-p256 provider. CRC32C checksums are exchanged with KMS to detect corruption in transit.
+func (p *provider) Sign(key config.KeyInfo, content []byte) (string, error) {
+	if key.GcpKms == nil {
+		return "", errors.New("gcpKms settings are required")
+	}
+	digest := sha256.Sum256(content)
+	body, _ := json.Marshal(map[string]interface{}{
+		"digest":       map[string]string{"sha256": base64.StdEncoding.EncodeToString(digest[:])},
+		"digestCrc32c": strconv.FormatUint(uint64(crc32.Checksum(digest[:], crc32c)), 10),
+	})
+
+	var result struct {
+		Signature            []byte `json:"signature"`
+		SignatureCrc32c      string `json:"signatureCrc32c"`
+		VerifiedDigestCrc32c bool   `json:"verifiedDigestCrc32c"`
+	}
+	err := p.do(*key.GcpKms, http.MethodPost, ":asymmetricSign", body, &result)
+	if err != nil {
+		return "", err
+	}
+	if !result.VerifiedDigestCrc32c {
+		return "", errors.New("cloud kms did not verify the digest checksum")
+	}
+	if result.SignatureCrc32c != strconv.FormatUint(uint64(crc32.Checksum(result.Signature, crc32c)), 10) {
+		return "", errors.New("signature checksum mismatch in cloud kms response")
+	}
+	return hex.EncodeToString(result.Signature), nil
+}
+
+// SignBatch signs the Merkle root of the contents with a single request to KMS, returning a signature for each
+// content
+func (p *provider) SignBatch(key config.KeyInfo, contents [][]byte) ([]string, error) {
+	return merklesig.Sign(p, key, contents)
+}
+
+// Verify checks a signature produced by Sign. When KeyInfo has a Path or Material the public key is read from PEM, as
+// for the ecdsa-p256 provider, so that verifiers need no access to KMS. Otherwise the public key is fetched from KMS
+// once and cached. Verification happens locally in either case.
+func (p *provider) Verify(key config.KeyInfo, content, signature []byte) (bool, error) {
+	if key.HasKey() {
+		return ecdsaprovider.New().Verify(key, content, signature)
+	}
+	if key.GcpKms == nil {
+		return false, errors.New("gcpKms settings or a public key path are required")
+	}
+	pub, err := p.publicKey(*key.GcpKms)
+	if err != nil {
+		return false, err
+	}
+
+	sigDecoded, err := hex.DecodeString(string(signature))
+	if err != nil {
+		return false, nil
+	}
+	digest := sha256.Sum256(content)
+	return ecdsa.VerifyASN1(pub, digest[:], sigDecoded), nil
+}
+
+func (p *provider) publicKey(info config.GcpKmsInfo) (*ecdsa.PublicKey, error) {
+	p.mu.Lock()
+	pub, ok := p.keys[info.KeyName]
+	p.mu.Unlock()
+	if ok {
+		return pub, nil
+	}
+
+	var result struct {
+		Pem       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+	}
+	if err := p.do(info, http.MethodGet, "/publicKey", nil, &result); err != nil {
+		return nil, err
+	}
+	if result.Algorithm != p256Algorithm {
+		return nil, fmt.Errorf("cloud kms key algorithm %s is not %s", result.Algorithm, p256Algorithm)
+	}
+	block, _ := pem.Decode([]byte(result.Pem))
+	if block == nil {
+		return nil, errors.New("no PEM data found in cloud kms public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok = key.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("%s is not a P-256 public key", info.KeyName)
+	}
+
+	p.mu.Lock()
+	p.keys[info.KeyName] = pub
+	p.mu.Unlock()
+	return pub, nil
+}
+
+// do sends an authorized request for a method on the key version, retrying with exponential backoff when quotas are
+// exceeded or the service is unavailable, and decodes the JSON response into result
+func (p *provider) do(info config.GcpKmsInfo, method, operation string, body []byte, result interface{}) error {
+	client, err := p.client(info.CredentialsFile)
+	if err != nil {
+		return err
+	}
+	retry := info.Retry
+	if retry.MaxAttempts < 1 {
+		retry.MaxAttempts = defaultMaxAttempts
+		retry.Jitter = defaultJitter
+	}
+
+	url := p.endpoint + "/v1/" + info.KeyName + operation
+	for attempt := 1; ; attempt++ {
+		var retryable bool
+		retryable, err = p.attempt(client, method, url, body, result)
+		if err == nil || !retryable || attempt >= retry.MaxAttempts {
+			return err
+		}
+		time.Sleep(backoff(retry, attempt))
+	}
+}
+
+// attempt makes a single request, reporting whether a failure may succeed if retried
+func (p *provider) attempt(client *http.Client, method, url string, body []byte, result interface{}) (bool, error) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return false, json.NewDecoder(resp.Body).Decode(result)
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		b, _ := io.ReadAll(resp.Body)
+		return true, fmt.Errorf("cloud kms returned %s %s", resp.Status, string(b))
+	default:
+		b, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("cloud kms returned %s %s", resp.Status, string(b))
+	}
+}
+
+// client returns an HTTP client authorized with the given credentials, creating it on first use so that tokens are
+// cached and refreshed across requests
+func (p *provider) client(credentialsFile string) (*http.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.clients[credentialsFile]; ok {
+		return c, nil
+	}
+	ts, err := p.tokenSource(context.Background(), credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+	c := oauth2.NewClient(context.Background(), ts)
+	c.Timeout = requestTimeout
+	p.clients[credentialsFile] = c
+	return c, nil
+}
+
+func tokenSource(ctx context.Context, credentialsFile string) (oauth2.TokenSource, error) {
+	if credentialsFile == "" {
+		return google.DefaultTokenSource(ctx, cloudKmsScope)
+	}
+	b, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := google.CredentialsFromJSON(ctx, b, cloudKmsScope)
+	if err != nil {
+		return nil, err
+	}
+	return creds.TokenSource, nil
+}
+
+// backoff returns the delay preceding the next attempt after the given number of failed attempts
+func backoff(cfg config.RetryInfo, attempt int) time.Duration {
+	initial, max, multiplier := defaultInitialInterval, defaultMaxInterval, defaultMultiplier
+	if cfg.InitialInterval > 0 {
+		initial = time.Duration(cfg.InitialInterval) * time.Millisecond
+	}
+	if cfg.MaxInterval > 0 {
+		max = time.Duration(cfg.MaxInterval) * time.Millisecond
+	}
+	if cfg.Multiplier > 0 {
+		multiplier = cfg.Multiplier
+	}
+
+	delay := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if delay > float64(max) {
+		delay = float64(max)
+	}
+	if cfg.Jitter > 0 {
+		delay *= 1 + cfg.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(delay)
+}
@@ -0,0 +1,163 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package gcpkms
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+const keyName = "projects/alvarium/locations/global/keyRings/gateway/cryptoKeys/signer/cryptoKeyVersions/1"
+
+// fakeKms serves the key version endpoints used by the provider, failing the first requests with the configured
+// status to exercise retries
+type fakeKms struct {
+	key         *ecdsa.PrivateKey
+	failures    atomic.Int32
+	failStatus  int
+	requests    atomic.Int32
+	keyRequests atomic.Int32
+}
+
+func (k *fakeKms) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	k.requests.Add(1)
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if k.failures.Add(-1) >= 0 {
+		w.WriteHeader(k.failStatus)
+		return
+	}
+
+	name, operation, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/"), ":")
+	if name == keyName+"/publicKey" {
+		k.keyRequests.Add(1)
+		b, _ := x509.MarshalPKIXPublicKey(&k.key.PublicKey)
+		json.NewEncoder(w).Encode(map[string]string{
+			"pem":       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b})),
+			"algorithm": p256Algorithm,
+		})
+		return
+	}
+	if name != keyName || operation != "asymmetricSign" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var req struct {
+		Digest struct {
+			Sha256 []byte `json:"sha256"`
+		} `json:"digest"`
+		DigestCrc32c string `json:"digestCrc32c"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	signature, _ := ecdsa.SignASN1(rand.Reader, k.key, req.Digest.Sha256)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"signature":            signature,
+		"signatureCrc32c":      strconv.FormatUint(uint64(crc32.Checksum(signature, crc32c)), 10),
+		"verifiedDigestCrc32c": req.DigestCrc32c == strconv.FormatUint(uint64(crc32.Checksum(req.Digest.Sha256, crc32c)), 10),
+	})
+}
+
+func newSUT(t *testing.T, failures int32, failStatus int) (*provider, *fakeKms) {
+	k, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	kms := &fakeKms{key: k, failStatus: failStatus}
+	kms.failures.Store(failures)
+	server := httptest.NewServer(kms)
+	t.Cleanup(server.Close)
+
+	sut := New()
+	sut.endpoint = server.URL
+	sut.tokenSource = func(context.Context, string) (oauth2.TokenSource, error) {
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), nil
+	}
+	return sut, kms
+}
+
+func TestProvider_SignVerify(t *testing.T) {
+	retry := config.RetryInfo{MaxAttempts: 3, InitialInterval: 1}
+	tests := []struct {
+		name             string
+		keyName          string
+		failures         int32
+		failStatus       int
+		expectedRequests int32
+		expectError      bool
+	}{
+		{"valid key", keyName, 0, 0, 1, false},
+		{"quota exceeded then recovered", keyName, 2, http.StatusTooManyRequests, 3, false},
+		{"unavailable beyond retries", keyName, 3, http.StatusServiceUnavailable, 3, true},
+		{"permission denied not retried", keyName, 1, http.StatusForbidden, 1, true},
+		{"missing key", strings.Replace(keyName, "signer", "missing", 1), 0, 0, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sut, kms := newSUT(t, tt.failures, tt.failStatus)
+			key := config.KeyInfo{
+				Type:   contracts.KeyGcpKms,
+				GcpKms: &config.GcpKmsInfo{KeyName: tt.keyName, Retry: retry},
+			}
+			content := []byte("foo")
+			signed, err := sut.Sign(key, content)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.expectedRequests, kms.requests.Load())
+			if err != nil {
+				return
+			}
+
+			ok, err := sut.Verify(key, content, []byte(signed))
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = sut.Verify(key, []byte("bar"), []byte(signed))
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			// The public key is fetched once and cached
+			assert.Equal(t, int32(1), kms.keyRequests.Load())
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cfg := config.RetryInfo{InitialInterval: 100, MaxInterval: 300}
+	assert.Equal(t, int64(100), backoff(cfg, 1).Milliseconds())
+	assert.Equal(t, int64(200), backoff(cfg, 2).Milliseconds())
+	assert.Equal(t, int64(300), backoff(cfg, 3).Milliseconds())
+
+	cfg.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		d := backoff(cfg, 1).Milliseconds()
+		assert.True(t, d >= 50 && d <= 150, "delay %v outside jitter range", d)
+	}
+}
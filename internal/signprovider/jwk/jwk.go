@@ -0,0 +1,61 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package jwk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+)
+
+// IsJWK reports whether key content is JSON, and so holds a JWK or a JWK set rather than a PEM or hex encoded key
+func IsJWK(b []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(b), []byte("{"))
+}
+
+// Parse returns the key held by a JWK (RFC 7517), or by the member of a JWK set whose kid matches KeyInfo.Id. A set
+// holding a single key may be used without an Id. Keys are returned in the forms of the standard library, such as
+// ed25519.PrivateKey or *ecdsa.PublicKey.
+func Parse(b []byte, key config.KeyInfo) (interface{}, error) {
+	id, source := key.Id, key.Source()
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(b, &set); err == nil && len(set.Keys) > 0 {
+		if id == "" && len(set.Keys) == 1 {
+			return valid(set.Keys[0], source)
+		}
+		for _, k := range set.Keys {
+			if k.KeyID == id {
+				return valid(k, source)
+			}
+		}
+		return nil, fmt.Errorf("no key with kid %q in JWK set %s", id, source)
+	}
+
+	var k jose.JSONWebKey
+	if err := json.Unmarshal(b, &k); err != nil {
+		return nil, fmt.Errorf("invalid JWK in %s: %w", source, err)
+	}
+	return valid(k, source)
+}
+
+func valid(k jose.JSONWebKey, source string) (interface{}, error) {
+	if !k.Valid() {
+		return nil, fmt.Errorf("invalid JWK in %s", source)
+	}
+	return k.Key, nil
+}
@@ -0,0 +1,80 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func marshal(t *testing.T, v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	return b
+}
+
+func TestParse(t *testing.T) {
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	ecPrivate, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	edKey := jose.JSONWebKey{Key: edPublic, KeyID: "2024-01", Algorithm: "EdDSA"}
+	ecKey := jose.JSONWebKey{Key: &ecPrivate.PublicKey, KeyID: "2024-06", Algorithm: "ES256"}
+	set := marshal(t, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{edKey, ecKey}})
+	single := marshal(t, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{ecKey}})
+
+	tests := []struct {
+		name        string
+		content     []byte
+		id          string
+		expected    interface{}
+		expectError bool
+	}{
+		{"ed25519 private key", marshal(t, jose.JSONWebKey{Key: edPrivate}), "", edPrivate, false},
+		{"ed25519 public key", marshal(t, edKey), "", edPublic, false},
+		{"ecdsa private key", marshal(t, jose.JSONWebKey{Key: ecPrivate}), "", ecPrivate, false},
+		{"set member by kid", set, "2024-01", edPublic, false},
+		{"other set member by kid", set, "2024-06", &ecPrivate.PublicKey, false},
+		{"single member set without kid", single, "", &ecPrivate.PublicKey, false},
+		{"set without kid", set, "", nil, true},
+		{"unknown kid", set, "2023-01", nil, true},
+		{"malformed key", []byte(`{"kty":"EC","crv":"P-256"}`), "", nil, true},
+		{"not json", []byte("{"), "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, IsJWK(tt.content))
+			k, err := Parse(tt.content, config.KeyInfo{Id: tt.id, Path: "keys.json"})
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.expected, k)
+		})
+	}
+}
+
+func TestIsJWK(t *testing.T) {
+	assert.True(t, IsJWK([]byte("\n  {\"keys\":[]}")))
+	assert.False(t, IsJWK([]byte("-----BEGIN PUBLIC KEY-----")))
+	assert.False(t, IsJWK([]byte("8f0a1c")))
+}
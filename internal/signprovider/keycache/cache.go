@@ -0,0 +1,131 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package keycache
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// ParseFunc parses the content of a key file. The key is given for its passphrase, if encrypted, and for KeyInfo.Source
+// to describe the key in error messages.
+type ParseFunc[T any] func(b []byte, key config.KeyInfo) (T, error)
+
+// Cache holds keys parsed from files, keyed by path and Id, so that a file is read and parsed once rather than for
+// every signature. When an interval is given a file is checked for modification at most once per interval and
+// reparsed if it has changed. A key that fails to reparse, for example while its file is being rewritten, is kept in
+// use until the file is valid again. Keys fetched from a URL are cached by URL in the same way, see fetch.
+type Cache[T any] struct {
+	parse    ParseFunc[T]
+	interval time.Duration
+
+	mu      sync.Mutex
+	entries map[entryKey]*entry[T]
+}
+
+// entryKey identifies a cached key by its location and Id, since a JWK set holds several keys selected by Id
+type entryKey struct {
+	location string
+	id       string
+}
+
+type entry[T any] struct {
+	value   T
+	modTime time.Time
+	checked time.Time // checked is when the file was last examined for modification
+	expires time.Time // expires is when a fetched key is next fetched again
+}
+
+// New returns a cache parsing keys with the given function. An interval of zero disables reloading.
+func New[T any](parse ParseFunc[T], interval time.Duration) *Cache[T] {
+	return &Cache[T]{
+		parse:    parse,
+		interval: interval,
+		entries:  make(map[entryKey]*entry[T]),
+	}
+}
+
+// Get returns the parsed key. Keys supplied in KeyInfo.Material are parsed on every call since they are not read from
+// a file.
+func (c *Cache[T]) Get(key config.KeyInfo) (T, error) {
+	value, err := c.get(key)
+	if err != nil {
+		return value, fmt.Errorf("%w: %w", contracts.ErrKeyUnavailable, err)
+	}
+	return value, nil
+}
+
+func (c *Cache[T]) get(key config.KeyInfo) (T, error) {
+	if key.Material != nil {
+		return c.parse(key.Material, key)
+	}
+	if key.Url != "" {
+		return c.fetch(key)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	name := entryKey{location: key.Path, id: key.Id}
+	e, ok := c.entries[name]
+	if ok && (c.interval == 0 || now.Sub(e.checked) < c.interval) {
+		return e.value, nil
+	}
+
+	info, err := os.Stat(key.Path)
+	if ok && (err != nil || info.ModTime().Equal(e.modTime)) {
+		e.checked = now
+		return e.value, nil
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	b, err := os.ReadFile(key.Path)
+	if err == nil {
+		var value T
+		if value, err = c.parse(b, key); err == nil {
+			c.entries[name] = &entry[T]{value: value, modTime: info.ModTime(), checked: now}
+			return value, nil
+		}
+	}
+	if ok {
+		e.checked = now
+		return e.value, nil
+	}
+	var zero T
+	return zero, err
+}
+
+// Load reads and parses the signing keys of the configuration that are held in files or at URLs, so that they are
+// ready before the first signature and any error surfaces early
+func (c *Cache[T]) Load(keys config.SignatureInfo) error {
+	for _, key := range append([]config.KeyInfo{keys.PrivateKey}, keys.PrivateKeys...) {
+		if (key.Path == "" && key.Url == "") || key.Material != nil || key.Signer != nil {
+			continue
+		}
+		if _, err := c.Get(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package keycache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingParser returns the content as a string, failing on content "invalid", and counts its calls
+type countingParser struct {
+	calls int
+}
+
+func (c *countingParser) parse(b []byte, key config.KeyInfo) (string, error) {
+	c.calls++
+	if string(b) == "invalid" {
+		return "", errors.New("invalid key in " + key.Source())
+	}
+	return string(b), nil
+}
+
+func writeKey(t *testing.T, path string, content string, modTime time.Time) {
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf(err.Error())
+	}
+}
+
+func TestCache_Get(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "private.key")
+	writeKey(t, path, "first", time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		replace  string
+		expected string
+	}{
+		{"without reload", 0, "second", "first"},
+		{"reload changed file", time.Nanosecond, "second", "second"},
+		{"keep key that fails to reload", time.Nanosecond, "invalid", "first"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeKey(t, path, "first", time.Now().Add(-time.Hour))
+			parser := countingParser{}
+			sut := New(parser.parse, tt.interval)
+			key := config.KeyInfo{Path: path}
+
+			for i := 0; i < 3; i++ {
+				v, err := sut.Get(key)
+				assert.NoError(t, err)
+				assert.Equal(t, "first", v)
+			}
+			assert.Equal(t, 1, parser.calls)
+
+			writeKey(t, path, tt.replace, time.Now())
+			time.Sleep(tt.interval)
+			v, err := sut.Get(key)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestCache_GetMaterial(t *testing.T) {
+	parser := countingParser{}
+	sut := New(parser.parse, 0)
+
+	v, err := sut.Get(config.KeyInfo{Material: []byte("in memory")})
+	assert.NoError(t, err)
+	assert.Equal(t, "in memory", v)
+
+	_, err = sut.Get(config.KeyInfo{Material: []byte("invalid")})
+	assert.Error(t, err)
+	assert.Equal(t, 2, parser.calls)
+}
+
+func TestCache_Load(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.key")
+	invalid := filepath.Join(dir, "invalid.key")
+	writeKey(t, valid, "valid", time.Now())
+	writeKey(t, invalid, "invalid", time.Now())
+
+	tests := []struct {
+		name        string
+		keys        config.SignatureInfo
+		expectError bool
+	}{
+		{"valid key", config.SignatureInfo{PrivateKey: config.KeyInfo{Path: valid}}, false},
+		{"invalid key", config.SignatureInfo{PrivateKey: config.KeyInfo{Path: invalid}}, true},
+		{"missing key", config.SignatureInfo{PrivateKey: config.KeyInfo{Path: filepath.Join(dir, "missing.key")}}, true},
+		{"invalid staged key", config.SignatureInfo{PrivateKey: config.KeyInfo{Path: valid},
+			PrivateKeys: []config.KeyInfo{{Path: invalid}}}, true},
+		{"public key not loaded", config.SignatureInfo{PrivateKey: config.KeyInfo{Path: valid},
+			PublicKey: config.KeyInfo{Path: invalid}}, false},
+		{"key without file", config.SignatureInfo{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := countingParser{}
+			err := New(parser.parse, 0).Load(tt.keys)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package keycache
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+)
+
+const (
+	fetchTimeout = 10 * time.Second
+	// maxKeySize bounds the response read when fetching a key
+	maxKeySize = 1 << 20
+	// defaultLifetime applies to fetched keys when the server does not give a max-age
+	defaultLifetime = time.Hour
+	// minLifetime keeps a key from being fetched more often than this, including after a failed fetch
+	minLifetime = time.Minute
+)
+
+var httpClient = &http.Client{Timeout: fetchTimeout}
+
+// fetch returns the key at KeyInfo.Url, which is fetched again once the max-age given in the response's Cache-Control
+// header has passed. A key that cannot be fetched again is kept in use and retried later.
+func (c *Cache[T]) fetch(key config.KeyInfo) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	name := entryKey{location: key.Url, id: key.Id}
+	e, ok := c.entries[name]
+	if ok && now.Before(e.expires) {
+		return e.value, nil
+	}
+
+	value, lifetime, err := c.download(key)
+	if err != nil {
+		if ok {
+			e.expires = now.Add(minLifetime)
+			return e.value, nil
+		}
+		var zero T
+		return zero, err
+	}
+	c.entries[name] = &entry[T]{value: value, expires: now.Add(lifetime)}
+	return value, nil
+}
+
+func (c *Cache[T]) download(key config.KeyInfo) (T, time.Duration, error) {
+	var zero T
+	resp, err := httpClient.Get(key.Url)
+	if err != nil {
+		return zero, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return zero, 0, fmt.Errorf("fetching key from %s returned status %v", key.Url, resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxKeySize))
+	if err != nil {
+		return zero, 0, err
+	}
+	value, err := c.parse(b, key)
+	if err != nil {
+		return zero, 0, err
+	}
+	return value, lifetime(resp.Header.Get("Cache-Control")), nil
+}
+
+// lifetime returns how long a fetched key may be used according to the max-age directive of a Cache-Control header
+func lifetime(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if err != nil {
+			break
+		}
+		if d := time.Duration(seconds) * time.Second; d > minLifetime {
+			return d
+		}
+		return minLifetime
+	}
+	return defaultLifetime
+}
@@ -0,0 +1,93 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package keycache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Fetch(t *testing.T) {
+	content := "first"
+	status := http.StatusOK
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.WriteHeader(status)
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	parser := countingParser{}
+	sut := New(parser.parse, 0)
+	key := config.KeyInfo{Url: server.URL + "/keys.json"}
+
+	for i := 0; i < 3; i++ {
+		v, err := sut.Get(key)
+		assert.NoError(t, err)
+		assert.Equal(t, "first", v)
+	}
+	assert.Equal(t, 1, requests)
+
+	// Once expired the key is fetched again, but kept in use if that fails
+	content = "second"
+	status = http.StatusServiceUnavailable
+	sut.entries[entryKey{location: key.Url}].expires = time.Now()
+	v, err := sut.Get(key)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", v)
+	assert.Equal(t, 2, requests)
+
+	status = http.StatusOK
+	sut.entries[entryKey{location: key.Url}].expires = time.Now()
+	v, err = sut.Get(key)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", v)
+
+	// A key that has never been fetched cannot be used
+	status = http.StatusNotFound
+	_, err = New(parser.parse, 0).Get(key)
+	assert.Error(t, err)
+
+	status = http.StatusOK
+	content = "invalid"
+	_, err = New(parser.parse, 0).Get(key)
+	assert.Error(t, err)
+}
+
+func TestLifetime(t *testing.T) {
+	tests := []struct {
+		name         string
+		cacheControl string
+		expected     time.Duration
+	}{
+		{"max-age", "public, max-age=300", 5 * time.Minute},
+		{"short max-age", "max-age=5", minLifetime},
+		{"no-cache", "no-cache", defaultLifetime},
+		{"malformed max-age", "max-age=soon", defaultLifetime},
+		{"missing header", "", defaultLifetime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, lifetime(tt.cacheControl))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package pkcs11
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"math/big"
+)
+
+// p256Size is the length in bytes of a P-256 scalar
+const p256Size = 32
+
+// rawToDER converts the r||s signature returned by CKM_ECDSA to ASN.1 DER.
+func rawToDER(raw []byte) ([]byte, error) {
+	if len(raw) != 2*p256Size {
+		return nil, errors.New("unexpected ECDSA signature length from token")
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{
+		R: new(big.Int).SetBytes(raw[:p256Size]),
+		S: new(big.Int).SetBytes(raw[p256Size:]),
+	})
+}
+
+// parseECPoint reads a P-256 public key from a CKA_EC_POINT value. The point should be wrapped in a DER octet
+// string but some tokens return it bare, so both are accepted.
+func parseECPoint(value []byte) (*ecdsa.PublicKey, error) {
+	point := value
+	var wrapped []byte
+	// a bare point whose second byte is 0x3f also reads as an octet string, so it is only unwrapped when it is not
+	// an uncompressed point itself
+	bare := len(value) == 1+2*p256Size && value[0] == 4
+	if rest, err := asn1.Unmarshal(value, &wrapped); err == nil && len(rest) == 0 && !bare {
+		point = wrapped
+	}
+	// ecdh validates that the point is uncompressed and on the curve
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, err
+	}
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(point[1 : 1+p256Size]),
+		Y:     new(big.Int).SetBytes(point[1+p256Size:]),
+	}, nil
+}
+
+func verify(pub *ecdsa.PublicKey, content, signature []byte) bool {
+	sigDecoded, err := hex.DecodeString(string(signature))
+	if err != nil {
+		return false
+	}
+	digest := sha256.Sum256(content)
+	return ecdsa.VerifyASN1(pub, digest[:], sigDecoded)
+}
@@ -0,0 +1,103 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package pkcs11
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/hex"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawToDER(t *testing.T) {
+	k, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	content := []byte("foo")
+	digest := sha256.Sum256(content)
+	r, s, _ := ecdsa.Sign(rand.Reader, k, digest[:])
+
+	// Tokens return fixed width scalars, so short values are left padded
+	raw := make([]byte, 2*p256Size)
+	r.FillBytes(raw[:p256Size])
+	s.FillBytes(raw[p256Size:])
+
+	tests := []struct {
+		name        string
+		raw         []byte
+		expectError bool
+	}{
+		{"valid signature", raw, false},
+		{"truncated signature", raw[:p256Size], true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			der, err := rawToDER(tt.raw)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+			assert.True(t, verify(&k.PublicKey, content, []byte(hex.EncodeToString(der))))
+			assert.False(t, verify(&k.PublicKey, []byte("bar"), []byte(hex.EncodeToString(der))))
+		})
+	}
+}
+
+func TestParseECPoint(t *testing.T) {
+	k, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	pub, _ := k.PublicKey.ECDH()
+	point := pub.Bytes()
+	wrapped, _ := asn1.Marshal(point)
+
+	other, _ := ecdh.X25519().GenerateKey(rand.Reader)
+
+	// a point whose X coordinate starts with 0x3f is also a DER octet string of 63 bytes
+	var ambiguous []byte
+	var ambiguousKey *ecdsa.PrivateKey
+	for ambiguous == nil {
+		ambiguousKey, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		pub, _ := ambiguousKey.PublicKey.ECDH()
+		if b := pub.Bytes(); b[1] == 0x3f {
+			ambiguous = b
+		}
+	}
+
+	tests := []struct {
+		name        string
+		value       []byte
+		expected    *ecdsa.PublicKey
+		expectError bool
+	}{
+		{"octet string", wrapped, &k.PublicKey, false},
+		{"bare point", point, &k.PublicKey, false},
+		{"bare point reading as an octet string", ambiguous, &ambiguousKey.PublicKey, false},
+		{"wrong curve", other.PublicKey().Bytes(), nil, true},
+		{"empty", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseECPoint(tt.value)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+			assert.True(t, parsed.Equal(tt.expected))
+		})
+	}
+}
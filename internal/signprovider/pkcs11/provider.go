@@ -0,0 +1,214 @@
+//go:build cgo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package pkcs11
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/merklesig"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ecdsa"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+)
+
+var (
+	modulesMu sync.Mutex
+	modules   = map[string]*pkcs11.Ctx{}
+)
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct{}
+
+// New is a factory function that returns an initialized provider.
+func New() *provider {
+	return &provider{}
+}
+
+// Sign computes the SHA-256 digest of the content and has the token sign it with the ECDSA P-256 private key
+// identified by KeyInfo.Pkcs11. The private key never leaves the token. The signature is returned as hex encoded
+// ASN.1 DER so that it is interchangeable with the ecdsa-p256 provider.
+func (p *provider) Sign(key config.KeyInfo, content []byte) (string, error) {
+	if key.Pkcs11 == nil {
+		return "", errors.New("pkcs11 settings are required")
+	}
+	s, err := openSession(*key.Pkcs11)
+	if err != nil {
+		return "", err
+	}
+	defer s.close()
+
+	obj, err := s.findKey(pkcs11.CKO_PRIVATE_KEY, key.Pkcs11.KeyLabel)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(content)
+	if err = s.ctx.SignInit(s.handle, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, obj); err != nil {
+		return "", err
+	}
+	raw, err := s.ctx.Sign(s.handle, digest[:])
+	if err != nil {
+		return "", err
+	}
+	signed, err := rawToDER(raw)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(signed), nil
+}
+
+// SignBatch signs the Merkle root of the contents on the token in a single session, returning a signature for each
+// content
+func (p *provider) SignBatch(key config.KeyInfo, contents [][]byte) ([]string, error) {
+	return merklesig.Sign(p, key, contents)
+}
+
+// Verify checks a signature produced by Sign. When KeyInfo has a Path or Material the public key is read from PEM, as
+// for the ecdsa-p256 provider, so that verifiers need no access to the token. Otherwise the public key labelled
+// KeyInfo.Pkcs11.KeyLabel is read from the token.
+func (p *provider) Verify(key config.KeyInfo, content, signature []byte) (bool, error) {
+	if key.HasKey() {
+		return ecdsa.New().Verify(key, content, signature)
+	}
+	if key.Pkcs11 == nil {
+		return false, errors.New("pkcs11 settings or a public key path are required")
+	}
+	s, err := openSession(*key.Pkcs11)
+	if err != nil {
+		return false, err
+	}
+	defer s.close()
+
+	obj, err := s.findKey(pkcs11.CKO_PUBLIC_KEY, key.Pkcs11.KeyLabel)
+	if err != nil {
+		return false, err
+	}
+	attrs, err := s.ctx.GetAttributeValue(s.handle, obj, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil)})
+	if err != nil {
+		return false, err
+	}
+	pub, err := parseECPoint(attrs[0].Value)
+	if err != nil {
+		return false, err
+	}
+	return verify(pub, content, signature), nil
+}
+
+// session is an authenticated session with the token holding the key.
+type session struct {
+	ctx    *pkcs11.Ctx
+	handle pkcs11.SessionHandle
+}
+
+func openSession(info config.Pkcs11Info) (*session, error) {
+	ctx, err := loadModule(info.Module)
+	if err != nil {
+		return nil, err
+	}
+	slot, err := findSlot(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return nil, err
+	}
+	s := &session{ctx: ctx, handle: handle}
+
+	pin := info.Pin
+	if info.PinEnv != "" {
+		pin = os.Getenv(info.PinEnv)
+	}
+	err = ctx.Login(handle, pkcs11.CKU_USER, pin)
+	// Login state is shared by all sessions with the token, so another session may already have logged in
+	if err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+		ctx.CloseSession(handle)
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *session) close() {
+	s.ctx.CloseSession(s.handle)
+}
+
+func (s *session) findKey(class uint, label string) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := s.ctx.FindObjectsInit(s.handle, template); err != nil {
+		return 0, err
+	}
+	defer s.ctx.FindObjectsFinal(s.handle)
+
+	objs, _, err := s.ctx.FindObjects(s.handle, 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(objs) == 0 {
+		return 0, fmt.Errorf("no EC key labelled %s found on token", label)
+	}
+	return objs[0], nil
+}
+
+// loadModule returns the initialized context for a module. Modules may only be initialized once per process, so
+// contexts are kept for its lifetime.
+func loadModule(path string) (*pkcs11.Ctx, error) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	if ctx, ok := modules[path]; ok {
+		return ctx, nil
+	}
+	ctx := pkcs11.New(path)
+	if ctx == nil {
+		return nil, fmt.Errorf("unable to load PKCS#11 module %s", path)
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, err
+	}
+	modules[path] = ctx
+	return ctx, nil
+}
+
+func findSlot(ctx *pkcs11.Ctx, info config.Pkcs11Info) (uint, error) {
+	if info.TokenLabel == "" {
+		return info.Slot, nil
+	}
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, err
+	}
+	for _, slot := range slots {
+		token, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, err
+		}
+		if token.Label == info.TokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("no token labelled %s found", info.TokenLabel)
+}
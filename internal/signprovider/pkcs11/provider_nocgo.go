@@ -0,0 +1,46 @@
+//go:build !cgo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package pkcs11
+
+import (
+	"errors"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+)
+
+var errNoCgo = errors.New("PKCS#11 support requires a build with cgo enabled")
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct{}
+
+// New is a factory function that returns an initialized provider. Without cgo the PKCS#11 module cannot be loaded,
+// so every operation fails.
+func New() *provider {
+	return &provider{}
+}
+
+func (p *provider) Sign(key config.KeyInfo, content []byte) (string, error) {
+	return "", errNoCgo
+}
+
+func (p *provider) Verify(key config.KeyInfo, content, signature []byte) (bool, error) {
+	return false, errNoCgo
+}
+
+func (p *provider) SignBatch(key config.KeyInfo, contents [][]byte) ([]string, error) {
+	return nil, errNoCgo
+}
@@ -0,0 +1,67 @@
+//go:build cgo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package pkcs11
+
+import (
+	"os"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProvider_SignVerify runs against a real token, for example one initialized with SoftHSM:
+//
+//	softhsm2-util --init-token --free --label alvarium --pin 1234 --so-pin 1234
+//	pkcs11-tool --module $ALVARIUM_PKCS11_MODULE --token-label alvarium --login --pin 1234 \
+//	  --keypairgen --key-type EC:prime256v1 --label signer
+func TestProvider_SignVerify(t *testing.T) {
+	module := os.Getenv("ALVARIUM_PKCS11_MODULE")
+	if module == "" {
+		t.Skip("ALVARIUM_PKCS11_MODULE not set")
+	}
+	key := config.KeyInfo{
+		Type: contracts.KeyPkcs11,
+		Pkcs11: &config.Pkcs11Info{
+			Module:     module,
+			TokenLabel: "alvarium",
+			PinEnv:     "ALVARIUM_PKCS11_PIN",
+			KeyLabel:   "signer",
+		},
+	}
+
+	sut := New()
+	content := []byte("foo")
+	signed, err := sut.Sign(key, content)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ok, err := sut.Verify(key, content, []byte(signed))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = sut.Verify(key, []byte("bar"), []byte(signed))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	missing := *key.Pkcs11
+	missing.KeyLabel = "missing"
+	_, err = sut.Sign(config.KeyInfo{Type: contracts.KeyPkcs11, Pkcs11: &missing}, content)
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package secp256k1
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+)
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1      = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// ecPrivateKey is the SEC 1 (RFC 5915) private key structure
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// pkcs8 is the PKCS#8 private key structure, used by older Hedera SDKs for ECDSA keys
+type pkcs8 struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// parsePrivateKey parses a hex encoded private key, either as the raw 32 byte scalar or DER encoded in the SEC 1 or
+// PKCS#8 forms produced by the Hedera SDKs
+func parsePrivateKey(b []byte, key config.KeyInfo) (*secp256k1.PrivateKey, error) {
+	path := key.Source()
+	b, err := decodeHex(b, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == secp256k1.PrivKeyBytesLen {
+		return secp256k1.PrivKeyFromBytes(b), nil
+	}
+
+	var sec1 ecPrivateKey
+	if _, err = asn1.Unmarshal(b, &sec1); err == nil && sec1.Version == 1 {
+		if len(sec1.NamedCurveOID) > 0 && !sec1.NamedCurveOID.Equal(oidSecp256k1) {
+			return nil, fmt.Errorf("%s is not a secp256k1 private key", path)
+		}
+		return parseScalar(sec1.PrivateKey, path)
+	}
+
+	var p pkcs8
+	if _, err = asn1.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("unrecognized private key format in %s", path)
+	}
+	if !p.Algorithm.Algorithm.Equal(oidSecp256k1) && !p.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, fmt.Errorf("%s is not a secp256k1 private key", path)
+	}
+	// The key is either a bare octet string or a nested SEC 1 structure
+	var inner []byte
+	if _, err = asn1.Unmarshal(p.PrivateKey, &inner); err == nil {
+		return parseScalar(inner, path)
+	}
+	if _, err = asn1.Unmarshal(p.PrivateKey, &sec1); err == nil {
+		return parseScalar(sec1.PrivateKey, path)
+	}
+	return nil, fmt.Errorf("unrecognized private key format in %s", path)
+}
+
+// parsePublicKey parses a hex encoded public key, either as a raw compressed or uncompressed point or DER encoded as a
+// SubjectPublicKeyInfo as produced by the Hedera SDKs
+func parsePublicKey(b []byte, key config.KeyInfo) (*secp256k1.PublicKey, error) {
+	path := key.Source()
+	b, err := decodeHex(b, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == secp256k1.PubKeyBytesLenCompressed || len(b) == secp256k1.PubKeyBytesLenUncompressed {
+		return secp256k1.ParsePubKey(b)
+	}
+
+	var spki subjectPublicKeyInfo
+	if _, err = asn1.Unmarshal(b, &spki); err != nil {
+		return nil, fmt.Errorf("unrecognized public key format in %s", path)
+	}
+	var curve asn1.ObjectIdentifier
+	if _, err = asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidSecp256k1) {
+		return nil, fmt.Errorf("%s is not a secp256k1 public key", path)
+	}
+	return secp256k1.ParsePubKey(spki.PublicKey.Bytes)
+}
+
+func parseScalar(b []byte, path string) (*secp256k1.PrivateKey, error) {
+	if len(b) != secp256k1.PrivKeyBytesLen {
+		return nil, fmt.Errorf("invalid private key length %v in %s", len(b), path)
+	}
+	return secp256k1.PrivKeyFromBytes(b), nil
+}
+
+func decodeHex(b []byte, path string) ([]byte, error) {
+	// Hedera tooling commonly prefixes keys with 0x
+	decoded, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(b)), "0x"))
+	if err != nil {
+		return nil, errors.New("key in " + path + " is not hex encoded")
+	}
+	return decoded, nil
+}
@@ -0,0 +1,140 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package secp256k1
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/merklesig"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/keycache"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	compactLen     = 64
+	recoverableLen = 65
+	// compactHeader is the offset of the recovery code in the header of a compact signature for a compressed key
+	compactHeader = 27 + 4
+)
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct {
+	private *keycache.Cache[*secp256k1.PrivateKey]
+	public  *keycache.Cache[*secp256k1.PublicKey]
+}
+
+// New is a factory function that returns an initialized provider. Key files are read once and never reloaded.
+func New() *provider {
+	return NewWithReload(0)
+}
+
+// NewWithReload returns a provider that checks key files for changes at most once per interval
+func NewWithReload(interval time.Duration) *provider {
+	return &provider{
+		private: keycache.New(parsePrivateKey, interval),
+		public:  keycache.New(parsePublicKey, interval),
+	}
+}
+
+// LoadKeys reads the configured signing keys ahead of their first use
+func (p *provider) LoadKeys(keys config.SignatureInfo) error {
+	return p.private.Load(keys)
+}
+
+// Sign signs the Keccak-256 digest of the content, as Hedera and Ethereum do, returning the hex encoded signature in
+// the encoding configured for the key. A crypto.Signer cannot produce these signatures and is rejected.
+func (p *provider) Sign(key config.KeyInfo, content []byte) (string, error) {
+	if key.Signer != nil {
+		return "", errors.New("secp256k1 keys cannot be supplied as a crypto.Signer")
+	}
+	prv, err := p.private.Get(key)
+	if err != nil {
+		return "", err
+	}
+
+	digest := keccak256(content)
+	var signed []byte
+	switch key.Encoding {
+	case "", contracts.DERSignature:
+		signed = ecdsa.Sign(prv, digest).Serialize()
+	case contracts.CompactSignature, contracts.RecoverableSignature:
+		// The compact form is prefixed with a header carrying the recovery code
+		c := ecdsa.SignCompact(prv, digest, true)
+		signed = c[1:]
+		if key.Encoding == contracts.RecoverableSignature {
+			signed = append(signed, c[0]-compactHeader)
+		}
+	default:
+		return "", fmt.Errorf("unsupported signature encoding %s", key.Encoding)
+	}
+	return hex.EncodeToString(signed), nil
+}
+
+// SignBatch signs the Merkle root of the contents once in the key's configured encoding, returning a signature
+// for each content
+func (p *provider) SignBatch(key config.KeyInfo, contents [][]byte) ([]string, error) {
+	return merklesig.Sign(p, key, contents)
+}
+
+// Verify checks a hex encoded signature in any of the supported encodings, which are distinguished by their length.
+// A recoverable signature is only valid if the public key recovered from it is the configured one.
+func (p *provider) Verify(key config.KeyInfo, content, signature []byte) (bool, error) {
+	pub, err := p.public.Get(key)
+	if err != nil {
+		return false, err
+	}
+	sig, err := hex.DecodeString(string(signature))
+	if err != nil {
+		return false, nil
+	}
+
+	digest := keccak256(content)
+	switch len(sig) {
+	case compactLen:
+		var r, s secp256k1.ModNScalar
+		if r.SetByteSlice(sig[:32]) || s.SetByteSlice(sig[32:]) {
+			return false, nil
+		}
+		return ecdsa.NewSignature(&r, &s).Verify(digest, pub), nil
+	case recoverableLen:
+		if sig[64] > 3 {
+			return false, nil
+		}
+		compact := append([]byte{sig[64] + compactHeader}, sig[:64]...)
+		recovered, _, err := ecdsa.RecoverCompact(compact, digest)
+		if err != nil {
+			return false, nil
+		}
+		return recovered.IsEqual(pub), nil
+	default:
+		parsed, err := ecdsa.ParseDERSignature(sig)
+		if err != nil {
+			return false, nil
+		}
+		return parsed.Verify(digest, pub), nil
+	}
+}
+
+func keccak256(content []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(content)
+	return h.Sum(nil)
+}
@@ -0,0 +1,155 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package secp256k1
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const keyPath = "../../../test/keys/secp256k1"
+
+func keyInfo(name string, encoding contracts.SignatureEncoding) config.KeyInfo {
+	return config.KeyInfo{Type: contracts.KeyEcdsaSecp256k1, Path: filepath.Join(keyPath, name), Encoding: encoding}
+}
+
+func TestProvider_SignVerify(t *testing.T) {
+	// A signature from another key must not verify, in particular a recoverable one
+	other, _ := secp256k1.GeneratePrivateKey()
+	otherPath := filepath.Join(t.TempDir(), "other.key")
+	if err := os.WriteFile(otherPath, []byte(hex.EncodeToString(other.PubKey().SerializeCompressed())), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		private     config.KeyInfo
+		expectError bool
+	}{
+		{"hedera der key", keyInfo("private.key", ""), false},
+		{"raw key", keyInfo("private-raw.key", ""), false},
+		{"pkcs8 key", keyInfo("private-pkcs8.key", ""), false},
+		{"der encoding", keyInfo("private.key", contracts.DERSignature), false},
+		{"compact encoding", keyInfo("private.key", contracts.CompactSignature), false},
+		{"recoverable encoding", keyInfo("private.key", contracts.RecoverableSignature), false},
+		{"invalid encoding", keyInfo("private.key", "base64"), true},
+		{"public key in place of private", keyInfo("public.key", ""), true},
+		{"missing key", keyInfo("missing.key", ""), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sut := New()
+			content := []byte("foo")
+			signed, err := sut.Sign(tt.private, content)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+
+			for _, public := range []string{"public.key", "public-raw.key"} {
+				ok, err := sut.Verify(keyInfo(public, ""), content, []byte(signed))
+				assert.NoError(t, err)
+				assert.True(t, ok, public)
+			}
+
+			ok, err := sut.Verify(keyInfo("public.key", ""), []byte("bar"), []byte(signed))
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			ok, err = sut.Verify(config.KeyInfo{Type: contracts.KeyEcdsaSecp256k1, Path: otherPath}, content,
+				[]byte(signed))
+			assert.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+// TestProvider_Hedera verifies interoperability with signatures produced and verified by the Hedera SDK
+func TestProvider_Hedera(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(keyPath, "private.key"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	key, err := hedera.PrivateKeyFromStringECDSA(string(b))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	content := []byte("foo")
+	sut := New()
+
+	ok, err := sut.Verify(keyInfo("public.key", ""), content, []byte(hex.EncodeToString(key.Sign(content))))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	signed, err := sut.Sign(keyInfo("private.key", contracts.CompactSignature), content)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	sig, _ := hex.DecodeString(signed)
+	// PublicKey.Verify in the Hedera SDK does not hash the message, so verify as consensus nodes do
+	assert.True(t, crypto.VerifySignature(key.PublicKey().BytesRaw(), crypto.Keccak256(content), sig))
+}
+
+// TestProvider_Ethereum verifies interoperability with recoverable signatures produced by go-ethereum
+func TestProvider_Ethereum(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(keyPath, "private-raw.key"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	key, err := crypto.HexToECDSA(string(b))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	content := []byte("foo")
+
+	sig, err := crypto.Sign(crypto.Keccak256(content), key)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	ok, err := New().Verify(keyInfo("public.key", ""), content, []byte(hex.EncodeToString(sig)))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestProvider_VerifyInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		public      config.KeyInfo
+		signature   string
+		expectError bool
+	}{
+		{"malformed signature", keyInfo("public.key", ""), "not hex", false},
+		{"malformed der signature", keyInfo("public.key", ""), "3006020101020101", false},
+		{"invalid recovery code", keyInfo("public.key", ""), hex.EncodeToString(make([]byte, 64)) + "07", false},
+		{"private key in place of public", keyInfo("private-raw.key", ""), "00", true},
+		{"missing key", keyInfo("missing.key", ""), "00", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := New().Verify(tt.public, []byte("foo"), []byte(tt.signature))
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.False(t, ok)
+		})
+	}
+}
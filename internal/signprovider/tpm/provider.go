@@ -0,0 +1,172 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package tpm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/merklesig"
+	ecdsaprovider "github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ecdsa"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+)
+
+// Default path to the kernel's TPM 2.0 resource manager, which allows the device to be shared between processes
+const defaultDevice string = "/dev/tpmrm0"
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct {
+	open func(device string) (transport.TPMCloser, error)
+}
+
+// New is a factory function that returns an initialized provider.
+func New() *provider {
+	return &provider{open: openTPM}
+}
+
+// Sign has the TPM sign the SHA-256 digest of the content with the ECDSA P-256 key at the persistent handle given
+// by KeyInfo.Tpm. The private key never leaves the TPM. The signature is returned as hex encoded ASN.1 DER so that it
+// is interchangeable with the ecdsa-p256 provider.
+func (p *provider) Sign(key config.KeyInfo, content []byte) (string, error) {
+	if key.Tpm == nil {
+		return "", errors.New("tpm settings are required")
+	}
+	t, err := p.open(device(*key.Tpm))
+	if err != nil {
+		return "", err
+	}
+	defer t.Close()
+
+	handle := tpm2.TPMHandle(key.Tpm.Handle)
+	pub, err := tpm2.ReadPublic{ObjectHandle: handle}.Execute(t)
+	if err != nil {
+		return "", err
+	}
+
+	auth := key.Tpm.Auth
+	if key.Tpm.AuthEnv != "" {
+		auth = os.Getenv(key.Tpm.AuthEnv)
+	}
+	digest := sha256.Sum256(content)
+	rsp, err := tpm2.Sign{
+		KeyHandle: tpm2.AuthHandle{Handle: handle, Name: pub.Name, Auth: tpm2.PasswordAuth([]byte(auth))},
+		Digest:    tpm2.TPM2BDigest{Buffer: digest[:]},
+		InScheme: tpm2.TPMTSigScheme{
+			Scheme:  tpm2.TPMAlgECDSA,
+			Details: tpm2.NewTPMUSigScheme(tpm2.TPMAlgECDSA, &tpm2.TPMSSchemeHash{HashAlg: tpm2.TPMAlgSHA256}),
+		},
+		Validation: tpm2.TPMTTKHashCheck{Tag: tpm2.TPMSTHashCheck},
+	}.Execute(t)
+	if err != nil {
+		return "", err
+	}
+
+	sig, err := rsp.Signature.Signature.ECDSA()
+	if err != nil {
+		return "", err
+	}
+	signed, err := asn1.Marshal(struct{ R, S *big.Int }{
+		R: new(big.Int).SetBytes(sig.SignatureR.Buffer),
+		S: new(big.Int).SetBytes(sig.SignatureS.Buffer),
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(signed), nil
+}
+
+// SignBatch signs the Merkle root of the contents with a single TPM command, returning a signature for each content
+func (p *provider) SignBatch(key config.KeyInfo, contents [][]byte) ([]string, error) {
+	return merklesig.Sign(p, key, contents)
+}
+
+// Verify checks a signature produced by Sign. When KeyInfo has a Path or Material the public key is read from PEM, as
+// for the ecdsa-p256 provider, so that verifiers need no access to the TPM. Otherwise the public area of the key is
+// read from the TPM.
+func (p *provider) Verify(key config.KeyInfo, content, signature []byte) (bool, error) {
+	if key.HasKey() {
+		return ecdsaprovider.New().Verify(key, content, signature)
+	}
+	if key.Tpm == nil {
+		return false, errors.New("tpm settings or a public key path are required")
+	}
+	t, err := p.open(device(*key.Tpm))
+	if err != nil {
+		return false, err
+	}
+	defer t.Close()
+
+	rsp, err := tpm2.ReadPublic{ObjectHandle: tpm2.TPMHandle(key.Tpm.Handle)}.Execute(t)
+	if err != nil {
+		return false, err
+	}
+	pub, err := publicKey(rsp.OutPublic)
+	if err != nil {
+		return false, err
+	}
+
+	sigDecoded, err := hex.DecodeString(string(signature))
+	if err != nil {
+		return false, nil
+	}
+	digest := sha256.Sum256(content)
+	return ecdsa.VerifyASN1(pub, digest[:], sigDecoded), nil
+}
+
+// publicKey extracts a P-256 public key from the public area of a TPM object
+func publicKey(public tpm2.TPM2BPublic) (*ecdsa.PublicKey, error) {
+	contents, err := public.Contents()
+	if err != nil {
+		return nil, err
+	}
+	if contents.Type != tpm2.TPMAlgECC {
+		return nil, errors.New("tpm key is not an ECC key")
+	}
+	detail, err := contents.Parameters.ECCDetail()
+	if err != nil {
+		return nil, err
+	}
+	if detail.CurveID != tpm2.TPMECCNistP256 {
+		return nil, fmt.Errorf("tpm key curve %v is not P-256", detail.CurveID)
+	}
+	point, err := contents.Unique.ECC()
+	if err != nil {
+		return nil, err
+	}
+	pub := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(point.X.Buffer),
+		Y:     new(big.Int).SetBytes(point.Y.Buffer),
+	}
+	if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, errors.New("tpm key is not a valid P-256 point")
+	}
+	return pub, nil
+}
+
+func device(info config.TpmInfo) string {
+	if info.Device == "" {
+		return defaultDevice
+	}
+	return info.Device
+}
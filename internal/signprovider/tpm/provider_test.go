@@ -0,0 +1,143 @@
+//go:build cgo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package tpm
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+	"github.com/google/go-tpm/tpm2/transport/simulator"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// sharedTPM keeps the simulator, and the keys loaded in it, alive when the provider closes its connection
+type sharedTPM struct {
+	transport.TPM
+}
+
+func (sharedTPM) Close() error { return nil }
+
+func newSUT(t *testing.T) (*provider, transport.TPMCloser) {
+	sim, err := simulator.OpenSimulator()
+	if err != nil {
+		t.Fatalf("could not open TPM simulator: %v", err)
+	}
+	t.Cleanup(func() { sim.Close() })
+	return &provider{open: func(string) (transport.TPMCloser, error) { return sharedTPM{sim}, nil }}, sim
+}
+
+func createKey(t *testing.T, tpm transport.TPM, auth []byte) tpm2.TPMHandle {
+	rsp, err := tpm2.CreatePrimary{
+		PrimaryHandle: tpm2.TPMRHOwner,
+		InSensitive: tpm2.TPM2BSensitiveCreate{
+			Sensitive: &tpm2.TPMSSensitiveCreate{UserAuth: tpm2.TPM2BAuth{Buffer: auth}},
+		},
+		InPublic: tpm2.New2B(tpm2.TPMTPublic{
+			Type:    tpm2.TPMAlgECC,
+			NameAlg: tpm2.TPMAlgSHA256,
+			ObjectAttributes: tpm2.TPMAObject{
+				SignEncrypt:         true,
+				FixedTPM:            true,
+				FixedParent:         true,
+				SensitiveDataOrigin: true,
+				UserWithAuth:        true,
+			},
+			Parameters: tpm2.NewTPMUPublicParms(tpm2.TPMAlgECC, &tpm2.TPMSECCParms{
+				Scheme:  tpm2.TPMTECCScheme{Scheme: tpm2.TPMAlgNull},
+				CurveID: tpm2.TPMECCNistP256,
+			}),
+		}),
+	}.Execute(tpm)
+	if err != nil {
+		t.Fatalf("could not create key: %v", err)
+	}
+	return rsp.ObjectHandle
+}
+
+func TestProvider_SignVerify(t *testing.T) {
+	sut, sim := newSUT(t)
+	handle := createKey(t, sim, []byte("secret"))
+	t.Setenv("ALVARIUM_TPM_AUTH", "secret")
+
+	tests := []struct {
+		name        string
+		key         config.TpmInfo
+		expectError bool
+	}{
+		{"auth value", config.TpmInfo{Handle: uint32(handle), Auth: "secret"}, false},
+		{"auth from environment", config.TpmInfo{Handle: uint32(handle), AuthEnv: "ALVARIUM_TPM_AUTH"}, false},
+		{"wrong auth", config.TpmInfo{Handle: uint32(handle), Auth: "guess"}, true},
+		{"missing key", config.TpmInfo{Handle: 0x81000100}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := config.KeyInfo{Type: contracts.KeyTpm, Tpm: &tt.key}
+			content := []byte("foo")
+			signed, err := sut.Sign(key, content)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+
+			ok, err := sut.Verify(key, content, []byte(signed))
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = sut.Verify(key, []byte("bar"), []byte(signed))
+			assert.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestProvider_VerifyWithPublicKeyFile(t *testing.T) {
+	sut, sim := newSUT(t)
+	handle := createKey(t, sim, nil)
+
+	rsp, err := tpm2.ReadPublic{ObjectHandle: handle}.Execute(sim)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	pub, err := publicKey(rsp.OutPublic)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	b, _ := x509.MarshalPKIXPublicKey(pub)
+	path := filepath.Join(t.TempDir(), "public.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	content := []byte("foo")
+	signed, err := sut.Sign(config.KeyInfo{Type: contracts.KeyTpm, Tpm: &config.TpmInfo{Handle: uint32(handle)}}, content)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Verifiers holding only the exported public key have no TPM settings
+	ok, err := sut.Verify(config.KeyInfo{Type: contracts.KeyTpm, Path: path}, content, []byte(signed))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
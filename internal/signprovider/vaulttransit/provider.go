@@ -0,0 +1,247 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package vaulttransit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/merklesig"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/tlsconfig"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+)
+
+const (
+	defaultMount        = "transit"
+	defaultAppRoleMount = "approle"
+	requestTimeout      = 30 * time.Second
+	// tokenRefreshMargin renews AppRole tokens this long before their lease ends so in-flight requests are not
+	// rejected
+	tokenRefreshMargin = 30 * time.Second
+)
+
+// vaultToken is a client token obtained by AppRole login
+type vaultToken struct {
+	value   string
+	expires time.Time // zero for tokens without a lease
+}
+
+// provider is a receiver that encapsulates required dependencies.
+type provider struct {
+	clientsMu sync.Mutex
+	clients   map[string]*http.Client // keyed by server address
+
+	// tokensMu is held across AppRole logins so that concurrent signers share a single login
+	tokensMu sync.Mutex
+	tokens   map[string]vaultToken // keyed by server address, namespace and role
+}
+
+// New is a factory function that returns an initialized provider.
+func New() *provider {
+	return &provider{
+		clients: make(map[string]*http.Client),
+		tokens:  make(map[string]vaultToken),
+	}
+}
+
+// Sign has the transit engine sign the content with the key given by KeyInfo.VaultTransit, using SHA-256 where the
+// key type takes a hash. The signature is returned in Vault's own vault:v{version}:{base64} form, which records the
+// key version so that signatures remain verifiable after the key is rotated in Vault.
+func (p *provider) Sign(key config.KeyInfo, content []byte) (string, error) {
+	if key.VaultTransit == nil {
+		return "", errors.New("vaultTransit settings are required")
+	}
+	req := map[string]interface{}{
+		"input":          base64.StdEncoding.EncodeToString(content),
+		"hash_algorithm": "sha2-256",
+	}
+	if key.VaultTransit.KeyVersion > 0 {
+		req["key_version"] = key.VaultTransit.KeyVersion
+	}
+
+	var result struct {
+		Data struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}
+	if err := p.do(*key.VaultTransit, "sign", req, &result); err != nil {
+		return "", err
+	}
+	return result.Data.Signature, nil
+}
+
+// SignBatch signs the Merkle root of the contents with a single request to the transit engine, returning a
+// signature for each content
+func (p *provider) SignBatch(key config.KeyInfo, contents [][]byte) ([]string, error) {
+	return merklesig.Sign(p, key, contents)
+}
+
+// Verify has the transit engine check a signature produced by Sign. Vault selects the key version recorded in the
+// signature.
+func (p *provider) Verify(key config.KeyInfo, content, signature []byte) (bool, error) {
+	if key.VaultTransit == nil {
+		return false, errors.New("vaultTransit settings are required")
+	}
+	if !strings.HasPrefix(string(signature), "vault:v") {
+		return false, nil
+	}
+	req := map[string]interface{}{
+		"input":          base64.StdEncoding.EncodeToString(content),
+		"signature":      string(signature),
+		"hash_algorithm": "sha2-256",
+	}
+
+	var result struct {
+		Data struct {
+			Valid bool `json:"valid"`
+		} `json:"data"`
+	}
+	if err := p.do(*key.VaultTransit, "verify", req, &result); err != nil {
+		return false, err
+	}
+	return result.Data.Valid, nil
+}
+
+// do calls a transit endpoint for the key and decodes the JSON response into result
+func (p *provider) do(info config.VaultTransitInfo, operation string, body interface{}, result interface{}) error {
+	mount := info.Mount
+	if mount == "" {
+		mount = defaultMount
+	}
+	path := fmt.Sprintf("/v1/%s/%s/%s", strings.Trim(mount, "/"), operation, url.PathEscape(info.KeyName))
+
+	token, err := p.token(info)
+	if err != nil {
+		return err
+	}
+	return p.request(info, path, token, body, result)
+}
+
+func (p *provider) request(info config.VaultTransitInfo, path, token string, body interface{}, result interface{}) error {
+	client, err := p.client(info)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(address(info), "/")+path, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if info.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", info.Namespace)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("vault returned %s %s", resp.Status, string(b))
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// token returns the configured token, or logs in with the AppRole and caches the issued token for its lease
+func (p *provider) token(info config.VaultTransitInfo) (string, error) {
+	if info.RoleId == "" {
+		if info.TokenEnv != "" {
+			return os.Getenv(info.TokenEnv), nil
+		}
+		if info.Token != "" {
+			return info.Token, nil
+		}
+		return os.Getenv("VAULT_TOKEN"), nil
+	}
+
+	p.tokensMu.Lock()
+	defer p.tokensMu.Unlock()
+
+	cacheKey := address(info) + "|" + info.Namespace + "|" + info.RoleId
+	if t, ok := p.tokens[cacheKey]; ok && (t.expires.IsZero() || time.Now().Add(tokenRefreshMargin).Before(t.expires)) {
+		return t.value, nil
+	}
+
+	secretId := info.SecretId
+	if info.SecretIdEnv != "" {
+		secretId = os.Getenv(info.SecretIdEnv)
+	}
+	mount := info.AppRoleMount
+	if mount == "" {
+		mount = defaultAppRoleMount
+	}
+	var result struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	body := map[string]string{"role_id": info.RoleId, "secret_id": secretId}
+	err := p.request(info, "/v1/auth/"+strings.Trim(mount, "/")+"/login", "", body, &result)
+	if err != nil {
+		return "", fmt.Errorf("vault approle login failed %w", err)
+	}
+
+	t := vaultToken{value: result.Auth.ClientToken}
+	if result.Auth.LeaseDuration > 0 {
+		t.expires = time.Now().Add(time.Duration(result.Auth.LeaseDuration) * time.Second)
+	}
+	p.tokens[cacheKey] = t
+	return t.value, nil
+}
+
+func (p *provider) client(info config.VaultTransitInfo) (*http.Client, error) {
+	p.clientsMu.Lock()
+	defer p.clientsMu.Unlock()
+
+	addr := address(info)
+	if c, ok := p.clients[addr]; ok {
+		return c, nil
+	}
+	c := &http.Client{Timeout: requestTimeout}
+	if info.TLS != nil {
+		tlsCfg, err := tlsconfig.New(*info.TLS)
+		if err != nil {
+			return nil, err
+		}
+		c.Transport = &http.Transport{TLSClientConfig: tlsCfg}
+	}
+	p.clients[addr] = c
+	return c, nil
+}
+
+func address(info config.VaultTransitInfo) string {
+	if info.Address == "" {
+		return os.Getenv("VAULT_ADDR")
+	}
+	return info.Address
+}
@@ -0,0 +1,179 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package vaulttransit
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVault implements the transit sign and verify endpoints for an ed25519 key named signer, along with AppRole
+// login
+type fakeVault struct {
+	mu       sync.Mutex
+	versions []ed25519.PrivateKey
+	logins   atomic.Int32
+}
+
+func (v *fakeVault) rotate() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	_, k, _ := ed25519.GenerateKey(rand.Reader)
+	v.versions = append(v.versions, k)
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	var req map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	if r.URL.Path == "/v1/auth/approle/login" {
+		if req["role_id"] != "gateway" || req["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		v.logins.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": "approle-token", "lease_duration": 3600},
+		})
+		return
+	}
+	if token := r.Header.Get("X-Vault-Token"); token != "root" && token != "approle-token" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	input, _ := base64.StdEncoding.DecodeString(req["input"].(string))
+	switch r.URL.Path {
+	case "/v1/transit/sign/signer":
+		version := len(v.versions)
+		if kv, ok := req["key_version"].(float64); ok {
+			version = int(kv)
+		}
+		sig := ed25519.Sign(v.versions[version-1], input)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]string{"signature": fmt.Sprintf("vault:v%d:%s", version, base64.StdEncoding.EncodeToString(sig))},
+		})
+	case "/v1/transit/verify/signer":
+		parts := strings.SplitN(req["signature"].(string), ":", 3)
+		version, _ := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+		sig, _ := base64.StdEncoding.DecodeString(parts[2])
+		valid := version > 0 && version <= len(v.versions) &&
+			ed25519.Verify(v.versions[version-1].Public().(ed25519.PublicKey), input, sig)
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]bool{"valid": valid}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newSUT(t *testing.T) (*provider, *fakeVault, string) {
+	vault := &fakeVault{}
+	vault.rotate()
+	server := httptest.NewServer(vault)
+	t.Cleanup(server.Close)
+	return New(), vault, server.URL
+}
+
+func TestProvider_SignVerify(t *testing.T) {
+	sut, vault, addr := newSUT(t)
+	t.Setenv("ALVARIUM_VAULT_TOKEN", "root")
+	t.Setenv("ALVARIUM_VAULT_SECRET", "secret")
+
+	tests := []struct {
+		name        string
+		info        config.VaultTransitInfo
+		expectError bool
+	}{
+		{"token", config.VaultTransitInfo{Address: addr, KeyName: "signer", Token: "root"}, false},
+		{"token from environment", config.VaultTransitInfo{Address: addr, KeyName: "signer", TokenEnv: "ALVARIUM_VAULT_TOKEN"}, false},
+		{"approle", config.VaultTransitInfo{Address: addr, KeyName: "signer", RoleId: "gateway", SecretIdEnv: "ALVARIUM_VAULT_SECRET"}, false},
+		{"approle unknown role", config.VaultTransitInfo{Address: addr, KeyName: "signer", RoleId: "unknown", SecretId: "secret"}, true},
+		{"invalid token", config.VaultTransitInfo{Address: addr, KeyName: "signer", Token: "guess"}, true},
+		{"missing key", config.VaultTransitInfo{Address: addr, KeyName: "missing", Token: "root"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := config.KeyInfo{Type: contracts.KeyVaultTransit, VaultTransit: &tt.info}
+			content := []byte("foo")
+			signed, err := sut.Sign(key, content)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+
+			ok, err := sut.Verify(key, content, []byte(signed))
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = sut.Verify(key, []byte("bar"), []byte(signed))
+			assert.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+
+	// The AppRole token is reused until its lease ends
+	assert.Equal(t, int32(1), vault.logins.Load())
+}
+
+func TestProvider_Rotation(t *testing.T) {
+	sut, vault, addr := newSUT(t)
+	key := config.KeyInfo{
+		Type:         contracts.KeyVaultTransit,
+		VaultTransit: &config.VaultTransitInfo{Address: addr, KeyName: "signer", Token: "root"},
+	}
+	content := []byte("foo")
+
+	before, err := sut.Sign(key, content)
+	assert.NoError(t, err)
+	vault.rotate()
+	after, err := sut.Sign(key, content)
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(before, "vault:v1:"))
+	assert.True(t, strings.HasPrefix(after, "vault:v2:"))
+	for _, signed := range []string{before, after} {
+		ok, err := sut.Verify(key, content, []byte(signed))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	// Pinning a version keeps signing with it after rotation
+	pinned := *key.VaultTransit
+	pinned.KeyVersion = 1
+	signed, err := sut.Sign(config.KeyInfo{Type: contracts.KeyVaultTransit, VaultTransit: &pinned}, content)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(signed, "vault:v1:"))
+
+	// Signatures not produced by Vault are rejected without a round trip
+	ok, err := sut.Verify(key, content, []byte("deadbeef"))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,154 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package strictjson checks JSON documents against the Go types they are decoded into, rejecting what encoding/json
+// silently tolerates: fields the type does not define, field names differing in case, duplicate fields and data
+// trailing the document.
+package strictjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Check returns an error if data is not a single JSON document whose objects only have the fields defined by the
+// corresponding structs of t, each at most once. Values are not otherwise checked, that is left to decoding.
+func Check(data []byte, t reflect.Type) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var doc json.RawMessage
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("data trailing the document")
+	}
+	return check(doc, t, "")
+}
+
+// check checks the value raw of type t found at path
+func check(raw json.RawMessage, t reflect.Type, path string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if t == timeType {
+			return nil
+		}
+		return checkObject(raw, t, path)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 || raw[0] != '[' { // bytes, or a type accepting other forms
+			return nil
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return err
+		}
+		for i, item := range items {
+			if err := check(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if raw[0] != '{' {
+			return nil
+		}
+		return eachField(raw, path, func(name string, value json.RawMessage) error {
+			return check(value, t.Elem(), join(path, name))
+		})
+	}
+	return nil
+}
+
+// checkObject checks that the object raw only has fields of struct t
+func checkObject(raw json.RawMessage, t reflect.Type, path string) error {
+	if raw[0] != '{' {
+		return nil
+	}
+	fields := fieldsOf(t)
+	return eachField(raw, path, func(name string, value json.RawMessage) error {
+		f, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("unknown field %s", join(path, name))
+		}
+		return check(value, f, join(path, name))
+	})
+}
+
+// eachField calls fn with each field of the object raw, failing on duplicate fields
+func eachField(raw json.RawMessage, path string, fn func(name string, value json.RawMessage) error) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name := tok.(string)
+		if seen[name] {
+			return fmt.Errorf("duplicate field %s", join(path, name))
+		}
+		seen[name] = true
+		var value json.RawMessage
+		if err = dec.Decode(&value); err != nil {
+			return err
+		}
+		if err = fn(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldsOf returns the types of the fields of struct t by their JSON names
+func fieldsOf(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,67 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package strictjson
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+)
+
+type inner struct {
+	Name string `json:"name"`
+}
+
+type outer struct {
+	Id      string           `json:"id,omitempty"`
+	At      time.Time        `json:"at"`
+	Inner   *inner           `json:"inner,omitempty"`
+	Items   []inner          `json:"items"`
+	Labels  map[string]inner `json:"labels"`
+	Data    []byte           `json:"data"`
+	Skipped string           `json:"-"`
+	Plain   string
+	Meta    map[string]string `json:"meta"`
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expectError bool
+	}{
+		{"valid", `{"id":"a","at":"2024-03-01T12:00:00Z","inner":{"name":"b"},"items":[{"name":"c"}],` +
+			`"labels":{"x":{"name":"d"}},"data":"ZQ==","Plain":"e","meta":{"k":"v"}}`, false},
+		{"null values", `{"inner":null,"items":null}`, false},
+		{"whitespace", " {\"id\" : \"a\"}\n", false},
+		{"unknown field", `{"id":"a","extra":1}`, true},
+		{"field of other case", `{"ID":"a"}`, true},
+		{"ignored field", `{"Skipped":"a"}`, true},
+		{"duplicate field", `{"id":"a","id":"b"}`, true},
+		{"unknown nested field", `{"inner":{"name":"b","extra":1}}`, true},
+		{"unknown field in array", `{"items":[{"name":"c"},{"nom":"c"}]}`, true},
+		{"unknown field in map", `{"labels":{"x":{"nom":"d"}}}`, true},
+		{"trailing data", `{"id":"a"}{"id":"b"}`, true},
+		{"invalid", `{"id":`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check([]byte(tt.data), reflect.TypeOf(outer{}))
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package syslog
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/tlsconfig"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+const (
+	dialTimeout    = 5 * time.Second
+	publishTimeout = 2 * time.Second
+
+	defaultFacility = 16 // local0
+	defaultAppName  = "alvarium"
+
+	severityNotice = 5
+	severityInfo   = 6
+
+	// SD-IDs are qualified with the private enterprise number reserved for documentation (RFC 5612)
+	wrapperSdId    = "alvarium@32473"
+	annotationSdId = "annotation@32473"
+
+	timestampFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+type syslogPublisher struct {
+	cfg       config.SyslogConfig
+	logger    interfaces.Logger
+	tlsConfig *tls.Config
+	hostname  string
+	procId    string
+
+	mu          sync.Mutex
+	conn        net.Conn
+	dialed      bool   // dialed is set once the first connection has been established
+	onReconnect func() // onReconnect is called when a later connection is established
+}
+
+func NewSyslogPublisher(cfg config.SyslogConfig, logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	switch cfg.Provider.Protocol {
+	case "tcp", "udp", "tls":
+	default:
+		return nil, fmt.Errorf("unsupported syslog transport %s", cfg.Provider.Protocol)
+	}
+	if cfg.Facility < 0 || cfg.Facility > 23 {
+		return nil, fmt.Errorf("invalid syslog facility %v", cfg.Facility)
+	}
+	if cfg.Facility == 0 {
+		cfg.Facility = defaultFacility
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = defaultAppName
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	p := syslogPublisher{
+		cfg:      cfg,
+		logger:   logger,
+		hostname: hostname,
+		procId:   strconv.Itoa(os.Getpid()),
+	}
+
+	if cfg.Provider.Protocol == "tls" {
+		t, err := tlsconfig.New(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		if t.ServerName == "" {
+			t.ServerName = cfg.Provider.Host
+		}
+		p.tlsConfig = t
+	}
+	return &p, nil
+}
+
+func (p *syslogPublisher) Connect(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.reconnect(ctx)
+}
+
+// Publish emits one syslog message per annotation carried by the wrapper, with the annotation's properties exposed as
+// structured data so collectors can filter on them. Wrappers not carrying annotations are emitted as a single message.
+func (p *syslogPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	messages := p.format(msg, time.Now())
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, m := range messages {
+		p.logger.Write(slog.LevelDebug, fmt.Sprintf("attempting publish, collector %s %s", p.cfg.Provider.Uri(), m))
+		err := p.write(ctx, m)
+		if err != nil {
+			// The collector may have dropped an idle connection. Attempt one reconnect before giving up
+			p.conn.Close()
+			p.conn = nil
+			if err = p.write(ctx, m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// OnReconnect registers handler to be called whenever a dropped connection is re-established
+func (p *syslogPublisher) OnReconnect(handler func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onReconnect = handler
+}
+
+// Healthy reports whether a connection is currently established. A dropped connection is only detected once a
+// write to it fails.
+func (p *syslogPublisher) Healthy(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		return fmt.Errorf("%w to %s", contracts.ErrNotConnected, p.cfg.Provider.Uri())
+	}
+	return nil
+}
+
+func (p *syslogPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
+
+// format renders the RFC 5424 messages for a publish wrapper
+func (p *syslogPublisher) format(msg message.PublishWrapper, ts time.Time) []string {
+	wrapperSd := sdElement(wrapperSdId, [][2]string{{"action", string(msg.Action)}, {"messageType", msg.MessageType}})
+
+	var list contracts.AnnotationList
+	if err := json.Unmarshal(msg.Content, &list); err != nil || len(list.Items) == 0 {
+		return []string{p.header(severityInfo, msg.Action, ts) + " " + wrapperSd + " " + string(msg.Content)}
+	}
+
+	result := make([]string, 0, len(list.Items))
+	for _, a := range list.Items {
+		severity := severityInfo
+		if !a.IsSatisfied {
+			severity = severityNotice
+		}
+		annotationSd := sdElement(annotationSdId, [][2]string{
+			{"id", a.Id.String()},
+			{"key", a.Key},
+			{"hash", string(a.Hash)},
+			{"host", a.Host},
+			{"tag", a.Tag.String()},
+			{"layer", string(a.Layer)},
+			{"kind", string(a.Kind)},
+			{"isSatisfied", strconv.FormatBool(a.IsSatisfied)},
+			{"signature", a.Signature},
+		})
+		b, _ := json.Marshal(a)
+		result = append(result, p.header(severity, msg.Action, ts)+" "+wrapperSd+annotationSd+" "+string(b))
+	}
+	return result
+}
+
+// header renders PRI VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID
+func (p *syslogPublisher) header(severity int, action message.SdkAction, ts time.Time) string {
+	return fmt.Sprintf("<%d>1 %s %s %s %s %s", p.cfg.Facility*8+severity, ts.Format(timestampFormat), p.hostname,
+		p.cfg.AppName, p.procId, action)
+}
+
+// write sends a single message, framed with octet counting (RFC 6587) for stream transports. Callers must hold p.mu.
+func (p *syslogPublisher) write(ctx context.Context, m string) error {
+	if err := p.reconnect(ctx); err != nil {
+		return err
+	}
+	if p.cfg.Provider.Protocol != "udp" {
+		m = strconv.Itoa(len(m)) + " " + m
+	}
+	p.conn.SetWriteDeadline(time.Now().Add(publishTimeout))
+	_, err := p.conn.Write([]byte(m))
+	return err
+}
+
+// reconnect dials the collector if there is no open connection, giving up when ctx is done. Callers must hold p.mu.
+func (p *syslogPublisher) reconnect(ctx context.Context) error {
+	if p.conn != nil {
+		return nil
+	}
+
+	address := net.JoinHostPort(p.cfg.Provider.Host, strconv.Itoa(p.cfg.Provider.Port))
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var conn net.Conn
+	var err error
+	switch p.cfg.Provider.Protocol {
+	case "tls":
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: p.tlsConfig}).DialContext(ctx, "tcp", address)
+	default:
+		conn, err = dialer.DialContext(ctx, p.cfg.Provider.Protocol, address)
+	}
+	if err != nil {
+		return err
+	}
+	p.conn = conn
+	if p.dialed && p.onReconnect != nil {
+		p.onReconnect()
+	}
+	p.dialed = true
+	return nil
+}
+
+// sdElement renders an SD-ELEMENT, escaping parameter values as required by RFC 5424 section 6.3.3
+func sdElement(id string, params [][2]string) string {
+	var sb strings.Builder
+	sb.WriteString("[" + id)
+	for _, param := range params {
+		if param[1] == "" {
+			continue
+		}
+		sb.WriteString(" " + param[0] + "=\"" + sdEscaper.Replace(param[1]) + "\"")
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+var sdEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
@@ -0,0 +1,139 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package syslog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSyslogPublisher(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	tests := []struct {
+		name        string
+		cfg         config.SyslogConfig
+		expectError bool
+	}{
+		{"valid tcp", config.SyslogConfig{Provider: config.ServiceInfo{Protocol: "tcp"}}, false},
+		{"valid udp", config.SyslogConfig{Provider: config.ServiceInfo{Protocol: "udp"}}, false},
+		{"valid tls", config.SyslogConfig{Provider: config.ServiceInfo{Protocol: "tls"}}, false},
+		{"invalid transport", config.SyslogConfig{Provider: config.ServiceInfo{Protocol: "http"}}, true},
+		{"invalid facility", config.SyslogConfig{Provider: config.ServiceInfo{Protocol: "tcp"}, Facility: 24}, true},
+		{"tls missing key", config.SyslogConfig{Provider: config.ServiceInfo{Protocol: "tls"},
+			TLS: config.TLSInfo{CertPath: "./cert.pem"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSyslogPublisher(tt.cfg, logger)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestSyslogPublisher_Publish(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer listener.Close()
+	received := make(chan string, 3)
+	go func() {
+		nc, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer nc.Close()
+		r := bufio.NewReader(nc)
+		for {
+			// Octet counted framing, MSG-LEN SP SYSLOG-MSG
+			size, err := r.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(size))
+			b := make([]byte, n)
+			if _, err = io.ReadFull(r, b); err != nil {
+				return
+			}
+			received <- string(b)
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	cfg := config.SyslogConfig{Provider: config.ServiceInfo{Host: "127.0.0.1", Port: portNum, Protocol: "tcp"}}
+	p, err := NewSyslogPublisher(cfg, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer p.Close()
+
+	satisfied := contracts.NewAnnotation("key", contracts.SHA256Hash, "host", contracts.Application, contracts.AnnotationTPM, true)
+	unsatisfied := contracts.NewAnnotation("key", contracts.SHA256Hash, "host", contracts.Application, contracts.AnnotationPKI, false)
+	unsatisfied.Tag = contracts.TagList{`a"b]c\d`}
+	b, _ := json.Marshal(contracts.AnnotationList{Items: []contracts.Annotation{satisfied, unsatisfied}})
+	msg := message.PublishWrapper{Action: message.ActionCreate, MessageType: "AnnotationList", Content: b}
+	if err = p.Publish(context.Background(), msg); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err = p.Publish(context.Background(),
+		message.PublishWrapper{Action: message.ActionPublish, MessageType: "string", Content: []byte("data")}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name     string
+		prefix   string
+		contains []string
+	}{
+		{"satisfied annotation", "<134>1 ", []string{" alvarium ", " create [alvarium@32473 action=\"create\"",
+			"[annotation@32473 id=\"" + satisfied.Id.String() + "\"", "isSatisfied=\"true\""}},
+		{"unsatisfied annotation", "<133>1 ", []string{"kind=\"pki\"", `tag="a\"b\]c\\d"`, "isSatisfied=\"false\""}},
+		{"raw content", "<134>1 ", []string{" publish [alvarium@32473 action=\"publish\" messageType=\"string\"] data"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			select {
+			case m := <-received:
+				assert.True(t, strings.HasPrefix(m, tt.prefix), m)
+				for _, c := range tt.contains {
+					assert.Contains(t, m, c)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for syslog message")
+			}
+		})
+	}
+}
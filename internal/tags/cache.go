@@ -0,0 +1,42 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package tags
+
+import (
+	"sync"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// cached keeps the tags of a source once resolved, for sources that are costly to read and do not change while the
+// process runs. A failed resolution is attempted again on the next read.
+type cached struct {
+	mu   sync.Mutex
+	tags contracts.TagList
+	done bool
+}
+
+func (c *cached) get(resolve func() (contracts.TagList, error)) contracts.TagList {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.done {
+		return c.tags
+	}
+	tags, err := resolve()
+	if err != nil {
+		return nil
+	}
+	c.tags, c.done = tags, true
+	return c.tags
+}
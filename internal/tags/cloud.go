@@ -0,0 +1,148 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package tags
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+const (
+	// metadataTimeout bounds each request to the instance metadata service, which only answers on cloud instances
+	metadataTimeout = 2 * time.Second
+	// maxMetadataSize bounds the metadata item read
+	maxMetadataSize = 4096
+
+	ec2Endpoint   = "http://169.254.169.254"
+	gceEndpoint   = "http://metadata.google.internal"
+	azureEndpoint = "http://169.254.169.254"
+)
+
+// MetadataTag reads an item of the metadata of the cloud instance the process runs on, such as its instance ID, from
+// the metadata service of the cloud provider. The item is read until it succeeds once, it is then kept.
+type MetadataTag struct {
+	cached
+	source   contracts.TagSourceType
+	item     string
+	endpoint string // endpoint is the base URL of the metadata service
+	client   *http.Client
+}
+
+// NewMetadataTag reads item of the metadata of the instance from the service of the provider identified by source,
+// one of contracts.TagSourceEC2, contracts.TagSourceGCE or contracts.TagSourceAzure. The item is a path relative to
+// the instance metadata of the provider: meta-data/ on EC2, instance/ on GCE and instance/compute/ on Azure. It
+// defaults to the identifier of the instance.
+func NewMetadataTag(source contracts.TagSourceType, item string) (*MetadataTag, error) {
+	m := MetadataTag{source: source, item: item, client: &http.Client{Timeout: metadataTimeout}}
+	switch source {
+	case contracts.TagSourceEC2:
+		m.endpoint, m.item = ec2Endpoint, defaultItem(item, "instance-id")
+	case contracts.TagSourceGCE:
+		m.endpoint, m.item = gceEndpoint, defaultItem(item, "id")
+	case contracts.TagSourceAzure:
+		m.endpoint, m.item = azureEndpoint, defaultItem(item, "vmId")
+	default:
+		return nil, fmt.Errorf("%w: %s is not a cloud metadata tag source", contracts.ErrConfigInvalid, source)
+	}
+	return &m, nil
+}
+
+func defaultItem(item, fallback string) string {
+	if item == "" {
+		return fallback
+	}
+	return item
+}
+
+func (m *MetadataTag) GetTagValue(contracts.LayerType) contracts.TagList {
+	return m.get(func() (contracts.TagList, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), metadataTimeout)
+		defer cancel()
+		value, err := m.fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return contracts.TagList{value}, nil
+	})
+}
+
+// fetch reads the item from the metadata service
+func (m *MetadataTag) fetch(ctx context.Context) (string, error) {
+	item := strings.TrimPrefix(m.item, "/")
+	var req *http.Request
+	var err error
+	switch m.source {
+	case contracts.TagSourceEC2:
+		// IMDSv2 requires a session token
+		token, err := m.ec2Token(ctx)
+		if err != nil {
+			return "", err
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, m.endpoint+"/latest/meta-data/"+item, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("X-aws-ec2-metadata-token", token)
+	case contracts.TagSourceGCE:
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, m.endpoint+"/computeMetadata/v1/instance/"+item, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+	case contracts.TagSourceAzure:
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet,
+			m.endpoint+"/metadata/instance/compute/"+item+"?api-version=2021-02-01&format=text", nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata", "true")
+	}
+	value, err := m.do(req)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// ec2Token requests a session token from the EC2 metadata service
+func (m *MetadataTag) ec2Token(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, m.endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	return m.do(req)
+}
+
+func (m *MetadataTag) do(req *http.Request) (string, error) {
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s metadata request %s failed with status %v", m.source, req.URL.Path, resp.StatusCode)
+	}
+	return string(b), nil
+}
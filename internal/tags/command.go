@@ -0,0 +1,53 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package tags
+
+import (
+	"context"
+	"os/exec"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// commandTimeout bounds the run of the command reading the tags
+const commandTimeout = 5 * time.Second
+
+// CommandTag reads the tags from the output of a command, such as git rev-parse HEAD, separated by commas or
+// newlines. The command is run until it succeeds once, its output is then kept, as the artifacts of the layer below do
+// not change while the process runs.
+type CommandTag struct {
+	cached
+	command []string
+}
+
+// NewCommandTag reads the tags from the output of command, its name followed by its arguments
+func NewCommandTag(command []string) *CommandTag {
+	return &CommandTag{command: command}
+}
+
+func (c *CommandTag) GetTagValue(contracts.LayerType) contracts.TagList {
+	if len(c.command) == 0 {
+		return nil
+	}
+	return c.get(func() (contracts.TagList, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, c.command[0], c.command[1:]...).Output()
+		if err != nil {
+			return nil, err
+		}
+		return contracts.ParseTagList(string(out)), nil
+	})
+}
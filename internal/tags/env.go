@@ -0,0 +1,43 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package tags
+
+import (
+	"context"
+	"os"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// EnvTag exchanges the tags through an environment variable, separated by commas. The tags it writes are inherited by
+// the processes started afterwards, such as the workload launched by a CI/CD runner.
+type EnvTag struct {
+	key string
+}
+
+// NewEnvTag exchanges the tag through the environment variable key, contracts.TagEnvKey if empty
+func NewEnvTag(key string) EnvTag {
+	if key == "" {
+		key = contracts.TagEnvKey
+	}
+	return EnvTag{key: key}
+}
+
+func (e EnvTag) WriteTag(_ context.Context, tags ...string) error {
+	return os.Setenv(e.key, contracts.TagList(tags).String())
+}
+
+func (e EnvTag) GetTagValue(contracts.LayerType) contracts.TagList {
+	return contracts.ParseTagList(os.Getenv(e.key))
+}
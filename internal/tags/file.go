@@ -0,0 +1,69 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package tags
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// FileTag exchanges the tags through a file, one per line, such as one on a volume shared by the layers of a node
+type FileTag struct {
+	path string
+}
+
+// NewFileTag exchanges the tag through the file at path, contracts.TagFilePath if empty
+func NewFileTag(path string) FileTag {
+	if path == "" {
+		path = contracts.TagFilePath
+	}
+	return FileTag{path: path}
+}
+
+// WriteTag replaces the file atomically, so that readers never see partially written tags
+func (f FileTag) WriteTag(_ context.Context, tags ...string) error {
+	dir := filepath.Dir(f.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(f.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.WriteString(strings.Join(tags, "\n") + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// GetTagValue returns the tags in the file, empty if it has not been written
+func (f FileTag) GetTagValue(contracts.LayerType) contracts.TagList {
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil
+	}
+	return contracts.ParseTagList(string(b))
+}
@@ -0,0 +1,148 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package tags
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// maxSymbolicRefs bounds the chain of symbolic references followed when resolving HEAD
+const maxSymbolicRefs = 5
+
+// GitTag reads the commit SHA that HEAD of a git repository points to. The repository is read directly, so that git
+// need not be installed where the workload runs. HEAD is resolved on every read, following a checkout.
+type GitTag struct {
+	dir string
+}
+
+// NewGitTag reads HEAD of the repository holding dir, the working directory if empty
+func NewGitTag(dir string) GitTag {
+	if dir == "" {
+		dir = "."
+	}
+	return GitTag{dir: dir}
+}
+
+func (g GitTag) GetTagValue(contracts.LayerType) contracts.TagList {
+	sha, err := g.head()
+	if err != nil {
+		return nil
+	}
+	return contracts.TagList{sha}
+}
+
+// head resolves HEAD to the SHA of a commit
+func (g GitTag) head() (string, error) {
+	gitDir, commonDir, err := g.gitDirs()
+	if err != nil {
+		return "", err
+	}
+	ref := "HEAD"
+	for i := 0; i < maxSymbolicRefs; i++ {
+		value, err := readRef(gitDir, commonDir, ref)
+		if err != nil {
+			return "", err
+		}
+		target, symbolic := strings.CutPrefix(value, "ref: ")
+		if !symbolic {
+			return value, nil
+		}
+		ref = strings.TrimSpace(target)
+	}
+	return "", fmt.Errorf("too many symbolic references resolving HEAD in %s", gitDir)
+}
+
+// gitDirs locates the git directory of the repository holding g.dir, and the directory it shares with the other
+// worktrees of the repository, if any
+func (g GitTag) gitDirs() (gitDir, commonDir string, err error) {
+	dir, err := filepath.Abs(g.dir)
+	if err != nil {
+		return "", "", err
+	}
+	for {
+		candidate := filepath.Join(dir, ".git")
+		if info, err := os.Stat(candidate); err == nil {
+			gitDir = candidate
+			if !info.IsDir() {
+				// worktrees and submodules hold a link to their git directory
+				if gitDir, err = linkedDir(candidate, dir); err != nil {
+					return "", "", err
+				}
+			}
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", fmt.Errorf("%s is not in a git repository", g.dir)
+		}
+		dir = parent
+	}
+
+	commonDir = gitDir
+	if b, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(b))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+	return gitDir, commonDir, nil
+}
+
+// linkedDir reads the git directory a .git file links to, relative to dir
+func linkedDir(path, dir string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	linked, ok := strings.CutPrefix(strings.TrimSpace(string(b)), "gitdir: ")
+	if !ok {
+		return "", fmt.Errorf("invalid git link %s", path)
+	}
+	if !filepath.IsAbs(linked) {
+		linked = filepath.Join(dir, linked)
+	}
+	return linked, nil
+}
+
+// readRef reads the value of ref, a SHA or a symbolic reference, from the loose references of the worktree, then
+// those shared by the repository, then its packed references
+func readRef(gitDir, commonDir, ref string) (string, error) {
+	for _, dir := range []string{gitDir, commonDir} {
+		if b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(ref))); err == nil {
+			return strings.TrimSpace(string(b)), nil
+		}
+	}
+	f, err := os.Open(filepath.Join(commonDir, "packed-refs"))
+	if err != nil {
+		return "", fmt.Errorf("reference %s not found", ref)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		sha, name, ok := strings.Cut(scanner.Text(), " ")
+		if ok && name == ref {
+			return sha, nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("reference %s not found", ref)
+}
@@ -0,0 +1,190 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package tags
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+const (
+	// defaultAnnotationsPath is where the pod annotations are conventionally exposed through the downward API
+	defaultAnnotationsPath = "/etc/podinfo/annotations"
+	// defaultLabelsPath is where the pod labels are conventionally exposed through the downward API
+	defaultLabelsPath = "/etc/podinfo/labels"
+	// serviceAccountPath holds the credentials of the service account the pod runs as
+	serviceAccountPath = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
+// KubernetesTag exchanges the tags through an annotation of the pod, separated by commas. The tags are written by
+// patching the pod through the API server, using the service account of the pod which needs to be allowed to patch
+// it, and read from the pod annotations or labels exposed through the downward API, which the kubelet refreshes after
+// a change. The pod is identified by the POD_NAME and POD_NAMESPACE environment variables, defaulting to the hostname
+// and the namespace of the service account.
+type KubernetesTag struct {
+	key            string
+	paths          []string     // paths are the files the downward API exposes the pod annotations or labels in
+	serviceAccount string       // serviceAccount is the directory holding the token, CA and namespace of the pod
+	api            string       // api is the base URL of the API server, derived from the environment if empty
+	client         *http.Client // client calls the API server, trusting the CA of the service account if nil
+}
+
+// NewKubernetesTag exchanges the tag through the pod annotation key, contracts.TagAnnotationKey if empty. The tag is
+// read from the pod annotations or labels exposed at path, or when empty from the annotations at
+// /etc/podinfo/annotations then the labels at /etc/podinfo/labels.
+func NewKubernetesTag(key, path string) *KubernetesTag {
+	if key == "" {
+		key = contracts.TagAnnotationKey
+	}
+	paths := []string{path}
+	if path == "" {
+		paths = []string{defaultAnnotationsPath, defaultLabelsPath}
+	}
+	return &KubernetesTag{key: key, paths: paths, serviceAccount: serviceAccountPath}
+}
+
+func (k *KubernetesTag) WriteTag(ctx context.Context, tags ...string) error {
+	namespace, pod, err := k.pod()
+	if err != nil {
+		return err
+	}
+	api, err := k.apiServer()
+	if err != nil {
+		return err
+	}
+	client, err := k.httpClient()
+	if err != nil {
+		return err
+	}
+	token, err := os.ReadFile(filepath.Join(k.serviceAccount, "token"))
+	if err != nil {
+		return fmt.Errorf("failed to read service account token %w", err)
+	}
+
+	patch := map[string]any{"metadata": map[string]any{"annotations": map[string]string{k.key: contracts.TagList(tags).String()}}}
+	b, _ := json.Marshal(patch)
+	target := fmt.Sprintf("%s/api/v1/namespaces/%s/pods/%s", api, url.PathEscape(namespace), url.PathEscape(pod))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, target, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to annotate pod %s/%s, status %v %s", namespace, pod, resp.StatusCode,
+			strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// GetTagValue returns the tags in the annotation or label as exposed by the downward API, one key="value" pair per
+// line, empty if the pod carries neither
+func (k *KubernetesTag) GetTagValue(contracts.LayerType) contracts.TagList {
+	for _, path := range k.paths {
+		if tags := k.read(path); len(tags) > 0 {
+			return tags
+		}
+	}
+	return nil
+}
+
+// read returns the tags in the key="value" pairs exposed at path
+func (k *KubernetesTag) read(path string) contracts.TagList {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok || key != k.key {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		return contracts.ParseTagList(value)
+	}
+	return nil
+}
+
+// pod identifies the pod to annotate
+func (k *KubernetesTag) pod() (namespace, pod string, err error) {
+	if pod = os.Getenv("POD_NAME"); pod == "" {
+		if pod, err = os.Hostname(); err != nil {
+			return "", "", err
+		}
+	}
+	if namespace = os.Getenv("POD_NAMESPACE"); namespace == "" {
+		b, err := os.ReadFile(filepath.Join(k.serviceAccount, "namespace"))
+		if err != nil {
+			return "", "", fmt.Errorf("failed to determine the namespace of the pod %w", err)
+		}
+		namespace = strings.TrimSpace(string(b))
+	}
+	return namespace, pod, nil
+}
+
+// apiServer returns the base URL of the API server
+func (k *KubernetesTag) apiServer() (string, error) {
+	if k.api != "" {
+		return k.api, nil
+	}
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return "", errors.New("not running in a Kubernetes cluster, KUBERNETES_SERVICE_HOST is not set")
+	}
+	return "https://" + net.JoinHostPort(host, port), nil
+}
+
+// httpClient returns the client calling the API server
+func (k *KubernetesTag) httpClient() (*http.Client, error) {
+	if k.client != nil {
+		return k.client, nil
+	}
+	ca, err := os.ReadFile(filepath.Join(k.serviceAccount, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account CA %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("invalid service account CA")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,50 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package tags
+
+import (
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// StaticTag holds tags set in the configuration
+type StaticTag contracts.TagList
+
+func (s StaticTag) GetTagValue(contracts.LayerType) contracts.TagList {
+	return contracts.TagList(s)
+}
+
+// LayerTag reads the tags of each layer from the sources of that layer, in order
+type LayerTag struct {
+	layers map[contracts.LayerType][]interfaces.TagValueGetter
+}
+
+// NewLayerTag reads the tags of each layer of layers from its sources. Layers without sources are not tagged.
+func NewLayerTag(layers map[contracts.LayerType][]interfaces.TagValueGetter) LayerTag {
+	return LayerTag{layers: layers}
+}
+
+func (l LayerTag) GetTagValue(layer contracts.LayerType) contracts.TagList {
+	var tags contracts.TagList
+	for _, source := range l.layers[layer] {
+		tags = append(tags, source.GetTagValue(layer)...)
+	}
+	return tags
+}
+
+// Default reads the tags of the app layer from the TAG environment variable, the sources of the tags when none are
+// configured
+var Default = NewLayerTag(map[contracts.LayerType][]interfaces.TagValueGetter{
+	contracts.Application: {NewEnvTag(contracts.TagEnvKey)},
+})
@@ -0,0 +1,225 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package tags
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTag_WriteTag(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		tag  interface {
+			interfaces.TagWriter
+			interfaces.TagValueGetter
+		}
+	}{
+		{"env", NewEnvTag("ALVARIUM_TEST_TAG")},
+		{"file", NewFileTag(filepath.Join(dir, "alvarium", "tag"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ALVARIUM_TEST_TAG", "")
+			assert.Empty(t, tt.tag.GetTagValue(contracts.Application))
+
+			for _, tags := range []contracts.TagList{{"4b825dc"}, {"e69de29", "sha256:e3b0c442"}} {
+				if err := tt.tag.WriteTag(context.Background(), tags...); err != nil {
+					t.Fatalf(err.Error())
+				}
+				assert.Equal(t, tags, tt.tag.GetTagValue(contracts.Application))
+			}
+		})
+	}
+}
+
+func TestKubernetesTag(t *testing.T) {
+	var patched map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/v1/namespaces/dcf/pods/workload-0" ||
+			r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		assert.Equal(t, "application/merge-patch+json", r.Header.Get("Content-Type"))
+		b, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &patched)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"token":       "secret\n",
+		"namespace":   "dcf",
+		"annotations": "kubernetes.io/config.seen=\"2024-01-01T00:00:00Z\"\nalvarium.project-alvarium.io/tag=\"4b825dc\"\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+	t.Setenv("POD_NAME", "workload-0")
+	t.Setenv("POD_NAMESPACE", "")
+
+	tag := NewKubernetesTag("", filepath.Join(dir, "annotations"))
+	tag.serviceAccount = dir
+	tag.api = server.URL
+	tag.client = server.Client()
+
+	assert.Equal(t, contracts.TagList{"4b825dc"}, tag.GetTagValue(contracts.Application))
+	if err := tag.WriteTag(context.Background(), "e69de29", "sha256:e3b0c442"); err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Equal(t, map[string]any{"metadata": map[string]any{
+		"annotations": map[string]any{contracts.TagAnnotationKey: "e69de29,sha256:e3b0c442"}}}, patched)
+
+	// a pod that cannot be patched is reported
+	t.Setenv("POD_NAME", "other-0")
+	assert.Error(t, tag.WriteTag(context.Background(), "e69de29"))
+
+	other := NewKubernetesTag("example.com/tag", filepath.Join(dir, "annotations"))
+	assert.Empty(t, other.GetTagValue(contracts.Application))
+
+	// the tag is read from the labels of a pod that is not annotated
+	if err := os.WriteFile(filepath.Join(dir, "labels"), []byte("example.com/tag=\"deploy-7\"\n"), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+	other.paths = []string{filepath.Join(dir, "annotations"), filepath.Join(dir, "labels")}
+	assert.Equal(t, contracts.TagList{"deploy-7"}, other.GetTagValue(contracts.Application))
+}
+
+func TestCommandTag(t *testing.T) {
+	tag := NewCommandTag([]string{"echo", "4b825dc,sha256:e3b0c442"})
+	assert.Equal(t, contracts.TagList{"4b825dc", "sha256:e3b0c442"}, tag.GetTagValue(contracts.CiCd))
+
+	// a failing command is run again on the next read
+	missing := NewCommandTag([]string{filepath.Join(t.TempDir(), "missing")})
+	assert.Empty(t, missing.GetTagValue(contracts.CiCd))
+	assert.False(t, missing.done)
+}
+
+func TestLayerTag(t *testing.T) {
+	t.Setenv(contracts.TagEnvKey, "4b825dc")
+	assert.Equal(t, contracts.TagList{"4b825dc"}, Default.GetTagValue(contracts.Application))
+	assert.Empty(t, Default.GetTagValue(contracts.Host))
+
+	layers := NewLayerTag(map[contracts.LayerType][]interfaces.TagValueGetter{
+		contracts.Os: {NewEnvTag(""), StaticTag{"deploy-7"}},
+	})
+	assert.Equal(t, contracts.TagList{"4b825dc", "deploy-7"}, layers.GetTagValue(contracts.Os))
+	assert.Empty(t, layers.GetTagValue(contracts.Application))
+}
+
+func TestGitTag(t *testing.T) {
+	const sha = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+	tests := []struct {
+		name  string
+		files map[string]string // files of the repository, relative to its root
+		dir   string            // dir is the directory read from, relative to the root of the repository
+	}{
+		{"branch", map[string]string{".git/HEAD": "ref: refs/heads/main\n", ".git/refs/heads/main": sha + "\n"}, ""},
+		{"detached", map[string]string{".git/HEAD": sha + "\n"}, ""},
+		{"packed", map[string]string{".git/HEAD": "ref: refs/heads/main\n",
+			".git/packed-refs": "# pack-refs with: peeled fully-peeled sorted\n" + sha + " refs/heads/main\n"}, ""},
+		{"subdirectory", map[string]string{".git/HEAD": "ref: refs/heads/main\n", ".git/refs/heads/main": sha,
+			"cmd/app/main.go": ""}, "cmd/app"},
+		{"worktree", map[string]string{"repo/.git/refs/heads/feature": sha,
+			"repo/.git/worktrees/feature/HEAD":      "ref: refs/heads/feature\n",
+			"repo/.git/worktrees/feature/commondir": "../..\n",
+			"feature/.git":                          "gitdir: ../repo/.git/worktrees/feature\n"}, "feature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(root, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf(err.Error())
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf(err.Error())
+				}
+			}
+			tag := NewGitTag(filepath.Join(root, filepath.FromSlash(tt.dir)))
+			assert.Equal(t, contracts.TagList{sha}, tag.GetTagValue(contracts.CiCd))
+		})
+	}
+
+	assert.Empty(t, NewGitTag(t.TempDir()).GetTagValue(contracts.CiCd))
+}
+
+func TestMetadataTag(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			assert.Equal(t, "60", r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds"))
+			_, _ = w.Write([]byte("session"))
+		case r.URL.Path == "/latest/meta-data/instance-id" && r.Header.Get("X-aws-ec2-metadata-token") == "session":
+			_, _ = w.Write([]byte("i-0abc"))
+		case r.URL.Path == "/computeMetadata/v1/instance/image" && r.Header.Get("Metadata-Flavor") == "Google":
+			_, _ = w.Write([]byte("projects/debian-cloud/global/images/debian-12\n"))
+		case r.URL.Path == "/metadata/instance/compute/vmId" && r.Header.Get("Metadata") == "true" &&
+			r.URL.Query().Get("format") == "text":
+			_, _ = w.Write([]byte("02aab8a4-74ef-476e-8182-f6d2ba4166a6"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		source   contracts.TagSourceType
+		item     string
+		expected contracts.TagList
+	}{
+		{"ec2", contracts.TagSourceEC2, "", contracts.TagList{"i-0abc"}},
+		{"gce", contracts.TagSourceGCE, "image", contracts.TagList{"projects/debian-cloud/global/images/debian-12"}},
+		{"azure", contracts.TagSourceAzure, "", contracts.TagList{"02aab8a4-74ef-476e-8182-f6d2ba4166a6"}},
+		{"missing item", contracts.TagSourceGCE, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, err := NewMetadataTag(tt.source, tt.item)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			tag.endpoint = server.URL
+
+			requests = 0
+			assert.Equal(t, tt.expected, tag.GetTagValue(contracts.Host))
+			made := requests
+			// the item is kept once read
+			assert.Equal(t, tt.expected, tag.GetTagValue(contracts.Host))
+			if tt.expected != nil {
+				assert.Equal(t, made, requests)
+			}
+		})
+	}
+
+	_, err := NewMetadataTag(contracts.TagSourceFile, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+)
+
+// New builds a tls.Config from the supplied TLSInfo. When no CA bundle is provided the system roots are used.
+func New(info config.TLSInfo) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         info.ServerName,
+		InsecureSkipVerify: info.InsecureSkipVerify,
+	}
+
+	if info.CaPath != "" {
+		b, err := os.ReadFile(info.CaPath)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found in %s", info.CaPath)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if info.CertPath != "" || info.KeyPath != "" {
+		if info.CertPath == "" || info.KeyPath == "" {
+			return nil, errors.New("both certPath and keyPath are required for mutual TLS")
+		}
+		cert, err := tls.LoadX509KeyPair(info.CertPath, info.KeyPath)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
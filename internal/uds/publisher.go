@@ -0,0 +1,133 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package uds
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+const (
+	dialTimeout    = 2 * time.Second
+	publishTimeout = 2 * time.Second
+)
+
+// udsPublisher writes publish wrappers as newline delimited JSON to a local socket so that a co-resident agent can
+// forward them. This keeps broker credentials off the device running the SDK.
+type udsPublisher struct {
+	cfg    config.UdsConfig
+	logger interfaces.Logger
+
+	mu          sync.Mutex
+	conn        net.Conn
+	dialed      bool   // dialed is set once the first connection has been established
+	onReconnect func() // onReconnect is called when a later connection is established
+}
+
+func NewUdsPublisher(cfg config.UdsConfig, logger interfaces.Logger) interfaces.StreamProvider {
+	return &udsPublisher{
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+func (p *udsPublisher) Connect(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.reconnect()
+}
+
+func (p *udsPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	b, _ := message.Marshal(msg)
+	b = append(b, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.logger.Write(slog.LevelDebug, fmt.Sprintf("attempting publish, path %s %s", p.cfg.Path, string(b)))
+	err := p.write(b)
+	if err != nil {
+		// The agent may have restarted. Attempt one reconnect before giving up
+		p.conn.Close()
+		p.conn = nil
+		err = p.write(b)
+	}
+	return err
+}
+
+// OnReconnect registers handler to be called whenever a dropped connection is re-established
+func (p *udsPublisher) OnReconnect(handler func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onReconnect = handler
+}
+
+// Healthy reports whether a connection is currently established. A dropped connection is only detected once a
+// write to it fails.
+func (p *udsPublisher) Healthy(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		return fmt.Errorf("%w to %s", contracts.ErrNotConnected, p.cfg.Path)
+	}
+	return nil
+}
+
+func (p *udsPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
+
+// write sends b over the current connection, establishing one if necessary. Callers must hold p.mu.
+func (p *udsPublisher) write(b []byte) error {
+	if err := p.reconnect(); err != nil {
+		return err
+	}
+	p.conn.SetWriteDeadline(time.Now().Add(publishTimeout))
+	_, err := p.conn.Write(b)
+	return err
+}
+
+// reconnect dials the socket if there is no open connection. Callers must hold p.mu.
+func (p *udsPublisher) reconnect() error {
+	if p.conn != nil {
+		return nil
+	}
+	conn, err := dial(p.cfg.Path, dialTimeout)
+	if err != nil {
+		return err
+	}
+	p.conn = conn
+	if p.dialed && p.onReconnect != nil {
+		p.onReconnect()
+	}
+	p.dialed = true
+	return nil
+}
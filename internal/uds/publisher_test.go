@@ -0,0 +1,146 @@
+//go:build !windows
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package uds
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUdsPublisher_Publish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alvarium.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer listener.Close()
+
+	lines := make(chan []byte, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- append([]byte{}, scanner.Bytes()...)
+				}
+			}()
+		}
+	}()
+
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	p := NewUdsPublisher(config.UdsConfig{Path: path}, logger)
+	checker := p.(interfaces.HealthChecker)
+	assert.Error(t, checker.Healthy(context.Background()))
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer p.Close()
+	assert.NoError(t, checker.Healthy(context.Background()))
+
+	msgs := []message.PublishWrapper{
+		{Action: message.ActionCreate, MessageType: "test", Content: []byte("first")},
+		{Action: message.ActionMutate, MessageType: "test", Content: []byte("second")},
+	}
+	for _, msg := range msgs {
+		if err = p.Publish(context.Background(), msg); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+	for _, msg := range msgs {
+		var result message.PublishWrapper
+		json.Unmarshal(<-lines, &result)
+		assert.Equal(t, msg, result)
+	}
+}
+
+func TestUdsPublisher_ConnectFailure(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	p := NewUdsPublisher(config.UdsConfig{Path: filepath.Join(t.TempDir(), "missing.sock")}, logger)
+	assert.Error(t, p.Connect(context.Background()))
+}
+
+func TestUdsPublisher_Reconnect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alvarium.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer listener.Close()
+
+	// The first connection is dropped by the agent as soon as it is accepted
+	dropped := make(chan struct{})
+	lines := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		conn.Close()
+		close(dropped)
+
+		conn, err = listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- append([]byte{}, scanner.Bytes()...)
+		}
+	}()
+
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	p := NewUdsPublisher(config.UdsConfig{Path: path}, logger)
+	reconnects := 0
+	p.(interfaces.ReconnectReporter).OnReconnect(func() {
+		reconnects++
+	})
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer p.Close()
+	<-dropped
+
+	msg := message.PublishWrapper{Action: message.ActionCreate, MessageType: "test", Content: []byte("content")}
+	// The first write after the drop may still be accepted by the socket buffer, so publish until it is noticed
+	for i := 0; i < 10 && reconnects == 0; i++ {
+		if err = p.Publish(context.Background(), msg); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+	assert.Equal(t, 1, reconnects)
+
+	var result message.PublishWrapper
+	json.Unmarshal(<-lines, &result)
+	assert.Equal(t, msg, result)
+}
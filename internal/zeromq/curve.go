@@ -0,0 +1,313 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package zeromq
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// This file implements the CURVE security mechanism (https://rfc.zeromq.org/spec/26/)
+const (
+	helloLength    = 2 + 72 + 32 + 8 + 80
+	welcomeLength  = 16 + 144
+	cookieLength   = 96
+	vouchLength    = 96
+	messagePrefix  = "\x07MESSAGE"
+	shortNonceSize = 8
+	longNonceSize  = 16
+)
+
+// curveKeys holds the permanent key material of the local socket
+type curveKeys struct {
+	server    bool
+	public    [32]byte
+	secret    [32]byte
+	serverKey [32]byte // serverKey is the server's permanent public key, only used by clients
+	allowed   map[[32]byte]struct{}
+}
+
+// curveSession encrypts and decrypts messages once the handshake has completed
+type curveSession struct {
+	key        [32]byte // key is precomputed from the transient key pairs
+	sendPrefix string
+	recvPrefix string
+	sendNonce  uint64
+	recvNonce  uint64
+}
+
+func loadCurveKeys(cfg config.ZmqCurveConfig) (*curveKeys, error) {
+	k := curveKeys{server: cfg.Server, allowed: map[[32]byte]struct{}{}}
+
+	var err error
+	if k.public, err = decodeKey(cfg.PublicKey); err != nil {
+		return nil, fmt.Errorf("invalid CURVE public key: %w", err)
+	}
+	b, err := os.ReadFile(cfg.SecretKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	if k.secret, err = decodeKey(strings.TrimSpace(string(b))); err != nil {
+		return nil, fmt.Errorf("invalid CURVE secret key: %w", err)
+	}
+
+	if !k.server {
+		if k.serverKey, err = decodeKey(cfg.ServerKey); err != nil {
+			return nil, fmt.Errorf("invalid CURVE server key: %w", err)
+		}
+	}
+	for _, allowed := range cfg.AllowedClientKeys {
+		key, err := decodeKey(allowed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CURVE client key: %w", err)
+		}
+		k.allowed[key] = struct{}{}
+	}
+	return &k, nil
+}
+
+func decodeKey(s string) ([32]byte, error) {
+	var key [32]byte
+	b, err := z85Decode(s)
+	if err != nil {
+		return key, err
+	}
+	if len(b) != len(key) {
+		return key, fmt.Errorf("unexpected key length %v", len(b))
+	}
+	copy(key[:], b)
+	return key, nil
+}
+
+func (c *conn) curveClientHandshake(socketType string, keys *curveKeys) (map[string]string, error) {
+	transientPub, transientSec, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	s := &curveSession{sendPrefix: "CurveZMQMESSAGEC", recvPrefix: "CurveZMQMESSAGES"}
+
+	// HELLO proves the client knows the server's public key
+	s.sendNonce++
+	nonce := shortNonce("CurveZMQHELLO---", s.sendNonce)
+	hello := make([]byte, 0, helloLength)
+	hello = append(hello, 1, 0)
+	hello = append(hello, make([]byte, 72)...)
+	hello = append(hello, transientPub[:]...)
+	hello = append(hello, nonce[longNonceSize:]...)
+	hello = box.Seal(hello, make([]byte, 64), &nonce, &keys.serverKey, transientSec)
+	if err = c.writeCommand("HELLO", hello); err != nil {
+		return nil, err
+	}
+
+	welcome, err := c.readCommand("WELCOME")
+	if err != nil {
+		return nil, err
+	}
+	if len(welcome) != welcomeLength {
+		return nil, errors.New("malformed WELCOME command")
+	}
+	nonce = longNonce("WELCOME-", welcome[:longNonceSize])
+	plain, ok := box.Open(nil, welcome[longNonceSize:], &nonce, &keys.serverKey, transientSec)
+	if !ok {
+		return nil, errors.New("unable to open WELCOME box")
+	}
+	var serverTransient [32]byte
+	copy(serverTransient[:], plain[:32])
+	cookie := plain[32:]
+
+	// INITIATE vouches for the transient key with the client's permanent key
+	vouchNonce := make([]byte, longNonceSize)
+	if _, err = io.ReadFull(rand.Reader, vouchNonce); err != nil {
+		return nil, err
+	}
+	nonce = longNonce("VOUCH---", vouchNonce)
+	vouch := box.Seal(vouchNonce, append(transientPub[:], keys.serverKey[:]...), &nonce, &serverTransient, &keys.secret)
+
+	initiatePlain := append(append(keys.public[:], vouch...), encodeMetadata(map[string]string{propertySocketType: socketType})...)
+	s.sendNonce++
+	nonce = shortNonce("CurveZMQINITIATE", s.sendNonce)
+	initiate := append(append([]byte{}, cookie...), nonce[longNonceSize:]...)
+	initiate = box.Seal(initiate, initiatePlain, &nonce, &serverTransient, transientSec)
+	if err = c.writeCommand("INITIATE", initiate); err != nil {
+		return nil, err
+	}
+
+	ready, err := c.readCommand("READY")
+	if err != nil {
+		return nil, err
+	}
+	if len(ready) < shortNonceSize+box.Overhead {
+		return nil, errors.New("malformed READY command")
+	}
+	s.recvNonce = binary.BigEndian.Uint64(ready[:shortNonceSize])
+	nonce = shortNonce("CurveZMQREADY---", s.recvNonce)
+	metadata, ok := box.Open(nil, ready[shortNonceSize:], &nonce, &serverTransient, transientSec)
+	if !ok {
+		return nil, errors.New("unable to open READY box")
+	}
+
+	box.Precompute(&s.key, &serverTransient, transientSec)
+	c.curve = s
+	return decodeMetadata(metadata)
+}
+
+func (c *conn) curveServerHandshake(socketType string, keys *curveKeys) (map[string]string, error) {
+	hello, err := c.readCommand("HELLO")
+	if err != nil {
+		return nil, err
+	}
+	if len(hello) != helloLength || hello[0] != 1 {
+		return nil, errors.New("malformed HELLO command")
+	}
+	var clientTransient [32]byte
+	copy(clientTransient[:], hello[74:106])
+	nonce := shortNonce("CurveZMQHELLO---", binary.BigEndian.Uint64(hello[106:114]))
+	if _, ok := box.Open(nil, hello[114:], &nonce, &clientTransient, &keys.secret); !ok {
+		return nil, errors.New("unable to open HELLO box")
+	}
+
+	transientPub, transientSec, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// The cookie is opaque to the client. Since state is held per connection it is only compared on return.
+	random := make([]byte, 2*longNonceSize+32)
+	if _, err = io.ReadFull(rand.Reader, random); err != nil {
+		return nil, err
+	}
+	var cookieKey [32]byte
+	copy(cookieKey[:], random[2*longNonceSize:])
+	cookieNonce := longNonce("COOKIE--", random[:longNonceSize])
+	cookie := secretbox.Seal(append([]byte{}, random[:longNonceSize]...), append(clientTransient[:], transientSec[:]...),
+		&cookieNonce, &cookieKey)
+
+	nonce = longNonce("WELCOME-", random[longNonceSize:2*longNonceSize])
+	welcome := box.Seal(append([]byte{}, random[longNonceSize:2*longNonceSize]...), append(transientPub[:], cookie...),
+		&nonce, &clientTransient, &keys.secret)
+	if err = c.writeCommand("WELCOME", welcome); err != nil {
+		return nil, err
+	}
+
+	initiate, err := c.readCommand("INITIATE")
+	if err != nil {
+		return nil, err
+	}
+	if len(initiate) < cookieLength+shortNonceSize+box.Overhead+32+vouchLength {
+		return nil, errors.New("malformed INITIATE command")
+	}
+	if !bytes.Equal(initiate[:cookieLength], cookie) {
+		return nil, errors.New("invalid INITIATE cookie")
+	}
+	recvNonce := binary.BigEndian.Uint64(initiate[cookieLength : cookieLength+shortNonceSize])
+	nonce = shortNonce("CurveZMQINITIATE", recvNonce)
+	plain, ok := box.Open(nil, initiate[cookieLength+shortNonceSize:], &nonce, &clientTransient, transientSec)
+	if !ok {
+		return nil, errors.New("unable to open INITIATE box")
+	}
+
+	var clientKey [32]byte
+	copy(clientKey[:], plain[:32])
+	vouch := plain[32 : 32+vouchLength]
+	nonce = longNonce("VOUCH---", vouch[:longNonceSize])
+	vouched, ok := box.Open(nil, vouch[longNonceSize:], &nonce, &clientKey, transientSec)
+	if !ok || !bytes.Equal(vouched, append(clientTransient[:], keys.public[:]...)) {
+		return nil, errors.New("invalid INITIATE vouch")
+	}
+	if len(keys.allowed) > 0 {
+		if _, ok := keys.allowed[clientKey]; !ok {
+			c.writeCommand("ERROR", append([]byte{byte(len("unauthorized"))}, "unauthorized"...))
+			return nil, errors.New("client key not authorized")
+		}
+	}
+
+	s := &curveSession{sendPrefix: "CurveZMQMESSAGES", recvPrefix: "CurveZMQMESSAGEC", recvNonce: recvNonce}
+	s.sendNonce++
+	nonce = shortNonce("CurveZMQREADY---", s.sendNonce)
+	ready := box.Seal(append([]byte{}, nonce[longNonceSize:]...),
+		encodeMetadata(map[string]string{propertySocketType: socketType}), &nonce, &clientTransient, transientSec)
+	if err = c.writeCommand("READY", ready); err != nil {
+		return nil, err
+	}
+
+	box.Precompute(&s.key, &clientTransient, transientSec)
+	c.curve = s
+	return decodeMetadata(plain[32+vouchLength:])
+}
+
+// encrypt produces the body of a MESSAGE command carrying payload
+func (s *curveSession) encrypt(flags byte, payload []byte) []byte {
+	s.sendNonce++
+	nonce := shortNonce(s.sendPrefix, s.sendNonce)
+
+	plain := make([]byte, 0, 1+len(payload))
+	plain = append(plain, flags&flagMore)
+	plain = append(plain, payload...)
+
+	out := make([]byte, 0, len(messagePrefix)+shortNonceSize+len(plain)+box.Overhead)
+	out = append(out, messagePrefix...)
+	out = append(out, nonce[longNonceSize:]...)
+	return box.SealAfterPrecomputation(out, plain, &nonce, &s.key)
+}
+
+// decrypt opens the body of a MESSAGE command, rejecting replayed or reordered nonces
+func (s *curveSession) decrypt(body []byte) (frame, error) {
+	if len(body) < len(messagePrefix)+shortNonceSize+box.Overhead+1 || string(body[:len(messagePrefix)]) != messagePrefix {
+		return frame{}, errors.New("malformed MESSAGE command")
+	}
+	n := binary.BigEndian.Uint64(body[len(messagePrefix) : len(messagePrefix)+shortNonceSize])
+	if n <= s.recvNonce {
+		return frame{}, errors.New("invalid MESSAGE nonce")
+	}
+	nonce := shortNonce(s.recvPrefix, n)
+	plain, ok := box.OpenAfterPrecomputation(nil, body[len(messagePrefix)+shortNonceSize:], &nonce, &s.key)
+	if !ok {
+		return frame{}, errors.New("unable to open MESSAGE box")
+	}
+	s.recvNonce = n
+
+	var flags byte
+	if plain[0]&0x01 != 0 {
+		flags |= flagMore
+	}
+	if plain[0]&0x02 != 0 {
+		flags |= flagCommand
+	}
+	return frame{flags: flags, body: plain[1:]}, nil
+}
+
+func shortNonce(prefix string, n uint64) [24]byte {
+	var nonce [24]byte
+	copy(nonce[:longNonceSize], prefix)
+	binary.BigEndian.PutUint64(nonce[longNonceSize:], n)
+	return nonce
+}
+
+func longNonce(prefix string, n []byte) [24]byte {
+	var nonce [24]byte
+	copy(nonce[:8], prefix)
+	copy(nonce[8:], n)
+	return nonce
+}
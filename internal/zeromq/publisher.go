@@ -0,0 +1,292 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package zeromq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+const publishTimeout = 2 * time.Second
+
+type zmqPublisher struct {
+	cfg        config.ZmqConfig
+	logger     interfaces.Logger
+	socketType string
+	keys       *curveKeys
+	listener   net.Listener
+
+	mu          sync.Mutex
+	peers       []*conn
+	next        int    // next is the index of the peer that receives the next PUSH message
+	onReconnect func() // onReconnect is called when a dropped connection to the peer is re-established
+}
+
+func NewZmqPublisher(cfg config.ZmqConfig, logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	p := zmqPublisher{
+		cfg:    cfg,
+		logger: logger,
+	}
+
+	switch cfg.SocketType {
+	case contracts.ZmqPub:
+		p.socketType = socketPub
+	case contracts.ZmqPush:
+		p.socketType = socketPush
+	default:
+		return nil, fmt.Errorf("invalid ZmqSocketType value provided %s", cfg.SocketType)
+	}
+
+	if cfg.Provider.Protocol != "tcp" {
+		return nil, fmt.Errorf("unsupported ZeroMQ transport %s", cfg.Provider.Protocol)
+	}
+
+	if cfg.Curve.Enabled {
+		keys, err := loadCurveKeys(cfg.Curve)
+		if err != nil {
+			return nil, err
+		}
+		p.keys = keys
+	}
+	return &p, nil
+}
+
+// Connect either starts listening for peers or establishes a connection to the configured peer
+func (p *zmqPublisher) Connect(ctx context.Context) error {
+	if !p.cfg.Bind {
+		return p.dial()
+	}
+
+	listener, err := net.Listen("tcp", p.address())
+	if err != nil {
+		return err
+	}
+	p.listener = listener
+	go p.accept()
+	return nil
+}
+
+func (p *zmqPublisher) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	// Verify connectivity first. If it's been dropped, this will attempt one reconnect before publish
+	if !p.cfg.Bind {
+		if err := p.reconnect(); err != nil {
+			return err
+		}
+	}
+
+	b, _ := message.Marshal(msg)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.socketType == socketPush {
+		p.logger.Write(slog.LevelDebug, fmt.Sprintf("attempting push %s", string(b)))
+		return p.push(b)
+	}
+
+	if len(p.cfg.Topics) == 0 {
+		p.broadcast([]byte{}, b)
+		return nil
+	}
+	for _, topic := range p.cfg.Topics {
+		p.logger.Write(slog.LevelDebug, fmt.Sprintf("attempting publish, topic %s %s", topic, string(b)))
+		p.broadcast([]byte(topic), []byte(topic), b)
+	}
+	return nil
+}
+
+// OnReconnect registers handler to be called whenever a dropped connection to the peer is re-established
+func (p *zmqPublisher) OnReconnect(handler func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onReconnect = handler
+}
+
+// Healthy reports whether a bound socket is listening, or a connecting socket has a live peer
+func (p *zmqPublisher) Healthy(ctx context.Context) error {
+	if p.cfg.Bind {
+		if p.listener == nil {
+			return fmt.Errorf("not listening on %s", p.address())
+		}
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.peers) == 0 {
+		return fmt.Errorf("%w to %s", contracts.ErrNotConnected, p.address())
+	}
+	return nil
+}
+
+func (p *zmqPublisher) Close() error {
+	if p.listener != nil {
+		p.listener.Close()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, c := range p.peers {
+		c.close()
+	}
+	p.peers = nil
+	return nil
+}
+
+// broadcast sends a message to every peer subscribed to topic. As with a native PUB socket, the message is dropped
+// when there are no matching subscribers. Callers must hold p.mu.
+func (p *zmqPublisher) broadcast(topic []byte, parts ...[]byte) {
+	var failed []*conn
+	for _, c := range p.peers {
+		if !c.subscribed(topic) {
+			continue
+		}
+		c.nc.SetWriteDeadline(time.Now().Add(publishTimeout))
+		if err := c.send(parts...); err != nil {
+			p.logger.Error(fmt.Sprintf("zeromq subscriber dropped %s", err.Error()))
+			failed = append(failed, c)
+		}
+	}
+	for _, c := range failed {
+		p.removeLocked(c)
+	}
+}
+
+// push sends a message to the next available peer in round robin order. Callers must hold p.mu.
+func (p *zmqPublisher) push(b []byte) error {
+	for len(p.peers) > 0 {
+		if p.next >= len(p.peers) {
+			p.next = 0
+		}
+		c := p.peers[p.next]
+		c.nc.SetWriteDeadline(time.Now().Add(publishTimeout))
+		err := c.send(b)
+		if err == nil {
+			p.next++
+			return nil
+		}
+		p.logger.Error(fmt.Sprintf("zeromq peer dropped %s", err.Error()))
+		p.removeLocked(c)
+	}
+	return errors.New("no connected zeromq peers")
+}
+
+func (p *zmqPublisher) accept() {
+	for {
+		nc, err := p.listener.Accept()
+		if err != nil {
+			// Listener has been closed
+			return
+		}
+		go func() {
+			if err := p.attach(nc); err != nil {
+				p.logger.Error(fmt.Sprintf("zeromq handshake failed %s", err.Error()))
+			}
+		}()
+	}
+}
+
+func (p *zmqPublisher) dial() error {
+	nc, err := net.DialTimeout("tcp", p.address(), handshakeTimeout)
+	if err != nil {
+		return err
+	}
+	return p.attach(nc)
+}
+
+func (p *zmqPublisher) reconnect() error {
+	p.mu.Lock()
+	connected := len(p.peers) > 0
+	p.mu.Unlock()
+	if connected {
+		return nil
+	}
+	if err := p.dial(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	handler := p.onReconnect
+	p.mu.Unlock()
+	if handler != nil {
+		handler()
+	}
+	return nil
+}
+
+// attach performs the handshake over a new network connection and starts reading from the resulting peer
+func (p *zmqPublisher) attach(nc net.Conn) error {
+	c, err := newConn(nc, p.socketType, p.keys)
+	if err != nil {
+		nc.Close()
+		return err
+	}
+
+	p.mu.Lock()
+	p.peers = append(p.peers, c)
+	p.mu.Unlock()
+
+	go p.receive(c)
+	return nil
+}
+
+// receive processes inbound frames until the peer disconnects. Only subscriptions are meaningful to a sending socket.
+func (p *zmqPublisher) receive(c *conn) {
+	for {
+		f, err := c.recv()
+		if err != nil {
+			p.remove(c)
+			return
+		}
+		if p.socketType == socketPub {
+			c.handleSubscription(f)
+		}
+	}
+}
+
+func (p *zmqPublisher) remove(c *conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.removeLocked(c)
+}
+
+func (p *zmqPublisher) removeLocked(c *conn) {
+	for i, peer := range p.peers {
+		if peer == c {
+			p.peers = append(p.peers[:i], p.peers[i+1:]...)
+			break
+		}
+	}
+	c.close()
+}
+
+func (p *zmqPublisher) address() string {
+	host := p.cfg.Provider.Host
+	if host == "*" {
+		host = ""
+	}
+	return net.JoinHostPort(host, strconv.Itoa(p.cfg.Provider.Port))
+}
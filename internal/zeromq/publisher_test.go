@@ -0,0 +1,278 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package zeromq
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/nacl/box"
+)
+
+func TestZ85(t *testing.T) {
+	// Test vector from https://rfc.zeromq.org/spec/32/
+	raw := []byte{0x86, 0x4F, 0xD2, 0x6F, 0xB5, 0x59, 0xF7, 0x5B}
+	encoded, err := z85Encode(raw)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Equal(t, "HelloWorld", encoded)
+
+	decoded, err := z85Decode(encoded)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Equal(t, raw, decoded)
+
+	_, err = z85Decode("Hello")
+	assert.NoError(t, err)
+	_, err = z85Decode("Hell")
+	assert.Error(t, err)
+	_, err = z85Decode("Hell~")
+	assert.Error(t, err)
+}
+
+func TestZmqPublisher_Pub(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+	dir := t.TempDir()
+	serverPub, serverSecretPath := generateKeys(t, dir, "server")
+	clientPub, clientSecretPath := generateKeys(t, dir, "client")
+
+	plain := config.ZmqConfig{
+		SocketType: contracts.ZmqPub,
+		Provider:   config.ServiceInfo{Host: "127.0.0.1", Protocol: "tcp"},
+		Bind:       true,
+		Topics:     []string{"alvarium"},
+	}
+	curve := plain
+	curve.Curve = config.ZmqCurveConfig{
+		Enabled:           true,
+		Server:            true,
+		PublicKey:         serverPub,
+		SecretKeyPath:     serverSecretPath,
+		AllowedClientKeys: []string{clientPub},
+	}
+	clientCurve := config.ZmqCurveConfig{PublicKey: clientPub, SecretKeyPath: clientSecretPath, ServerKey: serverPub}
+
+	tests := []struct {
+		name        string
+		cfg         config.ZmqConfig
+		client      *config.ZmqCurveConfig
+		expectError bool
+	}{
+		{"pub with null mechanism", plain, nil, false},
+		{"pub with curve mechanism", curve, &clientCurve, false},
+		{"invalid socket type", config.ZmqConfig{SocketType: "req", Provider: plain.Provider}, nil, true},
+		{"invalid transport", config.ZmqConfig{SocketType: contracts.ZmqPub}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewZmqPublisher(tt.cfg, logger)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+			if err = p.Connect(context.Background()); err != nil {
+				t.Fatalf(err.Error())
+			}
+			defer p.Close()
+			publisher := p.(*zmqPublisher)
+
+			var keys *curveKeys
+			if tt.client != nil {
+				if keys, err = loadCurveKeys(*tt.client); err != nil {
+					t.Fatalf(err.Error())
+				}
+			}
+			nc, err := net.Dial("tcp", publisher.listener.Addr().String())
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			sub, err := newConn(nc, socketSub, keys)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			defer sub.close()
+
+			if err = sub.send(append([]byte{1}, "alva"...)); err != nil {
+				t.Fatalf(err.Error())
+			}
+			waitForSubscription(t, publisher, []byte("alvarium"))
+
+			msg := message.PublishWrapper{Action: message.ActionCreate, MessageType: "test", Content: []byte("data")}
+			if err = p.Publish(context.Background(), msg); err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			topic, err := sub.recv()
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Equal(t, "alvarium", string(topic.body))
+			assert.True(t, topic.more())
+
+			content, err := sub.recv()
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.False(t, content.more())
+			var result message.PublishWrapper
+			json.Unmarshal(content.body, &result)
+			assert.Equal(t, msg, result)
+		})
+	}
+}
+
+func TestZmqPublisher_Push(t *testing.T) {
+	logger := logging.NewConsoleLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer listener.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		nc, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		pull, err := newConn(nc, socketPull, nil)
+		if err != nil {
+			nc.Close()
+			return
+		}
+		defer pull.close()
+		f, err := pull.recv()
+		if err == nil {
+			received <- f.body
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	cfg := config.ZmqConfig{
+		SocketType: contracts.ZmqPush,
+		Provider:   config.ServiceInfo{Host: "127.0.0.1", Port: portNum, Protocol: "tcp"},
+	}
+	p, err := NewZmqPublisher(cfg, logger)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer p.Close()
+
+	msg := message.PublishWrapper{Action: message.ActionCreate, MessageType: "test", Content: []byte("data")}
+	if err = p.Publish(context.Background(), msg); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	select {
+	case b := <-received:
+		var result message.PublishWrapper
+		json.Unmarshal(b, &result)
+		assert.Equal(t, msg, result)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for pushed message")
+	}
+}
+
+func TestCurveUnauthorizedClient(t *testing.T) {
+	dir := t.TempDir()
+	serverPub, serverSecretPath := generateKeys(t, dir, "server")
+	clientPub, clientSecretPath := generateKeys(t, dir, "client")
+	allowedPub, _ := generateKeys(t, dir, "allowed")
+
+	serverKeys, err := loadCurveKeys(config.ZmqCurveConfig{Server: true, PublicKey: serverPub,
+		SecretKeyPath: serverSecretPath, AllowedClientKeys: []string{allowedPub}})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	clientKeys, err := loadCurveKeys(config.ZmqCurveConfig{PublicKey: clientPub, SecretKeyPath: clientSecretPath,
+		ServerKey: serverPub})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer listener.Close()
+	serverErr := make(chan error, 1)
+	go func() {
+		nc, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer nc.Close()
+		_, err = newConn(nc, socketPub, serverKeys)
+		serverErr <- err
+	}()
+
+	nc, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer nc.Close()
+	_, err = newConn(nc, socketSub, clientKeys)
+	assert.Error(t, err)
+	assert.Error(t, <-serverErr)
+}
+
+func generateKeys(t *testing.T, dir, name string) (string, string) {
+	pub, sec, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	pubEncoded, _ := z85Encode(pub[:])
+	secEncoded, _ := z85Encode(sec[:])
+	path := filepath.Join(dir, name+".key")
+	if err = os.WriteFile(path, []byte(secEncoded+"\n"), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+	return pubEncoded, path
+}
+
+func waitForSubscription(t *testing.T, p *zmqPublisher, topic []byte) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		p.mu.Lock()
+		subscribed := len(p.peers) > 0 && p.peers[0].subscribed(topic)
+		p.mu.Unlock()
+		if subscribed {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for subscription")
+}
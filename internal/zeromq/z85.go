@@ -0,0 +1,64 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package zeromq
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Z85 encoding as specified by https://rfc.zeromq.org/spec/32/ is used by ZeroMQ to represent CURVE keys as text
+const z85Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+
+func z85Encode(data []byte) (string, error) {
+	if len(data)%4 != 0 {
+		return "", fmt.Errorf("z85 input length %v is not a multiple of 4", len(data))
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(data); i += 4 {
+		value := uint32(data[i])<<24 | uint32(data[i+1])<<16 | uint32(data[i+2])<<8 | uint32(data[i+3])
+		chunk := make([]byte, 5)
+		for j := 4; j >= 0; j-- {
+			chunk[j] = z85Alphabet[value%85]
+			value /= 85
+		}
+		sb.Write(chunk)
+	}
+	return sb.String(), nil
+}
+
+func z85Decode(s string) ([]byte, error) {
+	if len(s)%5 != 0 {
+		return nil, fmt.Errorf("z85 input length %v is not a multiple of 5", len(s))
+	}
+
+	result := make([]byte, 0, len(s)*4/5)
+	for i := 0; i < len(s); i += 5 {
+		var value uint64
+		for j := 0; j < 5; j++ {
+			index := strings.IndexByte(z85Alphabet, s[i+j])
+			if index < 0 {
+				return nil, fmt.Errorf("invalid z85 character %q", s[i+j])
+			}
+			value = value*85 + uint64(index)
+		}
+		if value > 0xFFFFFFFF {
+			return nil, fmt.Errorf("invalid z85 chunk %s", s[i:i+5])
+		}
+		result = append(result, byte(value>>24), byte(value>>16), byte(value>>8), byte(value))
+	}
+	return result, nil
+}
@@ -0,0 +1,376 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package zeromq
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// This file implements the subset of ZMTP 3.0 (https://rfc.zeromq.org/spec/23/) needed for a socket that only sends
+// messages, allowing interoperability with libzmq peers without requiring cgo.
+const (
+	flagMore    byte = 0x01
+	flagLong    byte = 0x02
+	flagCommand byte = 0x04
+
+	greetingLength = 64
+	versionMajor   = 3
+	versionMinor   = 0
+
+	mechanismNull  = "NULL"
+	mechanismCurve = "CURVE"
+
+	socketPub  = "PUB"
+	socketSub  = "SUB"
+	socketPush = "PUSH"
+	socketPull = "PULL"
+
+	propertySocketType = "Socket-Type"
+
+	handshakeTimeout = 5 * time.Second
+	// Peers of a sending socket only ever send subscriptions, so anything large is treated as a protocol violation
+	maxFrameSize = 1 << 16
+)
+
+// frame is a single ZMTP frame. Only the MORE and COMMAND flags are retained after decoding.
+type frame struct {
+	flags byte
+	body  []byte
+}
+
+func (f frame) more() bool {
+	return f.flags&flagMore != 0
+}
+
+func (f frame) command() bool {
+	return f.flags&flagCommand != 0
+}
+
+// conn is an established ZMTP connection to a single peer
+type conn struct {
+	nc       net.Conn
+	r        *bufio.Reader
+	curve    *curveSession // curve is nil when the NULL mechanism is in use
+	peerType string
+
+	mu   sync.Mutex
+	subs map[string]struct{}
+}
+
+// newConn performs the greeting and security handshake over nc. A nil keys parameter selects the NULL mechanism.
+func newConn(nc net.Conn, socketType string, keys *curveKeys) (*conn, error) {
+	c := &conn{nc: nc, r: bufio.NewReader(nc), subs: map[string]struct{}{}}
+
+	nc.SetDeadline(time.Now().Add(handshakeTimeout))
+	defer nc.SetDeadline(time.Time{})
+
+	mechanism := mechanismNull
+	asServer := false
+	if keys != nil {
+		mechanism = mechanismCurve
+		asServer = keys.server
+	}
+	if err := c.greet(mechanism, asServer); err != nil {
+		return nil, err
+	}
+
+	var metadata map[string]string
+	var err error
+	switch {
+	case keys == nil:
+		metadata, err = c.nullHandshake(socketType)
+	case keys.server:
+		metadata, err = c.curveServerHandshake(socketType, keys)
+	default:
+		metadata, err = c.curveClientHandshake(socketType, keys)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.peerType = strings.ToUpper(metadata[strings.ToLower(propertySocketType)])
+	if !compatible(socketType, c.peerType) {
+		return nil, fmt.Errorf("incompatible peer socket type %s for %s", c.peerType, socketType)
+	}
+	return c, nil
+}
+
+func (c *conn) greet(mechanism string, asServer bool) error {
+	g := make([]byte, greetingLength)
+	g[0] = 0xFF
+	g[9] = 0x7F
+	g[10] = versionMajor
+	g[11] = versionMinor
+	copy(g[12:32], mechanism)
+	if asServer {
+		g[32] = 1
+	}
+	if _, err := c.nc.Write(g); err != nil {
+		return err
+	}
+
+	peer := make([]byte, greetingLength)
+	if _, err := io.ReadFull(c.r, peer); err != nil {
+		return err
+	}
+	if peer[0] != 0xFF || peer[9]&0x01 != 0x01 {
+		return errors.New("invalid ZMTP greeting signature")
+	}
+	if peer[10] < versionMajor {
+		return fmt.Errorf("unsupported ZMTP version %v.%v", peer[10], peer[11])
+	}
+	if peerMechanism := string(bytes.TrimRight(peer[12:32], "\x00")); peerMechanism != mechanism {
+		return fmt.Errorf("security mechanism mismatch, peer uses %s", peerMechanism)
+	}
+	return nil
+}
+
+func (c *conn) nullHandshake(socketType string) (map[string]string, error) {
+	err := c.writeCommand("READY", encodeMetadata(map[string]string{propertySocketType: socketType}))
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.readCommand("READY")
+	if err != nil {
+		return nil, err
+	}
+	return decodeMetadata(data)
+}
+
+// send writes a multipart message to the peer
+func (c *conn) send(parts ...[]byte) error {
+	for i, part := range parts {
+		var flags byte
+		if i < len(parts)-1 {
+			flags = flagMore
+		}
+		if c.curve != nil {
+			// Encrypted frames travel as single data frames, the original flags are carried inside the box
+			part = c.curve.encrypt(flags, part)
+			flags = 0
+		}
+		if err := c.writeFrame(flags, part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recv reads the next frame from the peer, decrypting it if necessary
+func (c *conn) recv() (frame, error) {
+	f, err := c.readFrame()
+	if err != nil || c.curve == nil {
+		return f, err
+	}
+	return c.curve.decrypt(f.body)
+}
+
+// handleSubscription updates the subscriptions of a peer from either ZMTP 3.0 style subscription messages or ZMTP
+// 3.1 SUBSCRIBE/CANCEL commands. It reports whether the frame was recognized as a subscription.
+func (c *conn) handleSubscription(f frame) bool {
+	var subscribe bool
+	var topic []byte
+	if f.command() {
+		name, data, err := parseCommand(f.body)
+		if err != nil {
+			return false
+		}
+		switch name {
+		case "SUBSCRIBE":
+			subscribe = true
+		case "CANCEL":
+		default:
+			return false
+		}
+		topic = data
+	} else {
+		if len(f.body) == 0 || f.body[0] > 1 {
+			return false
+		}
+		subscribe = f.body[0] == 1
+		topic = f.body[1:]
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if subscribe {
+		c.subs[string(topic)] = struct{}{}
+	} else {
+		delete(c.subs, string(topic))
+	}
+	return true
+}
+
+// subscribed reports whether the peer has a subscription that is a prefix of topic
+func (c *conn) subscribed(topic []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for sub := range c.subs {
+		if bytes.HasPrefix(topic, []byte(sub)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *conn) close() error {
+	return c.nc.Close()
+}
+
+func (c *conn) writeFrame(flags byte, body []byte) error {
+	var header []byte
+	if len(body) > 255 {
+		header = make([]byte, 9)
+		header[0] = flags | flagLong
+		binary.BigEndian.PutUint64(header[1:], uint64(len(body)))
+	} else {
+		header = []byte{flags, byte(len(body))}
+	}
+	_, err := c.nc.Write(append(header, body...))
+	return err
+}
+
+func (c *conn) readFrame() (frame, error) {
+	flags, err := c.r.ReadByte()
+	if err != nil {
+		return frame{}, err
+	}
+
+	var size uint64
+	if flags&flagLong != 0 {
+		b := make([]byte, 8)
+		if _, err = io.ReadFull(c.r, b); err != nil {
+			return frame{}, err
+		}
+		size = binary.BigEndian.Uint64(b)
+	} else {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return frame{}, err
+		}
+		size = uint64(b)
+	}
+	if size > maxFrameSize {
+		return frame{}, fmt.Errorf("frame size %v exceeds maximum", size)
+	}
+
+	body := make([]byte, size)
+	if _, err = io.ReadFull(c.r, body); err != nil {
+		return frame{}, err
+	}
+	return frame{flags: flags & (flagMore | flagCommand), body: body}, nil
+}
+
+func (c *conn) writeCommand(name string, data []byte) error {
+	body := make([]byte, 0, 1+len(name)+len(data))
+	body = append(body, byte(len(name)))
+	body = append(body, name...)
+	body = append(body, data...)
+	return c.writeFrame(flagCommand, body)
+}
+
+// readCommand reads the next frame and returns its data, failing if it is not the expected command
+func (c *conn) readCommand(expected string) ([]byte, error) {
+	f, err := c.readFrame()
+	if err != nil {
+		return nil, err
+	}
+	if !f.command() {
+		return nil, fmt.Errorf("expected %s command, received message", expected)
+	}
+	name, data, err := parseCommand(f.body)
+	if err != nil {
+		return nil, err
+	}
+	if name == "ERROR" && len(data) > 0 {
+		return nil, fmt.Errorf("peer rejected handshake: %s", string(data[1:]))
+	}
+	if name != expected {
+		return nil, fmt.Errorf("expected %s command, received %s", expected, name)
+	}
+	return data, nil
+}
+
+func parseCommand(body []byte) (string, []byte, error) {
+	if len(body) == 0 || len(body) < 1+int(body[0]) {
+		return "", nil, errors.New("malformed command frame")
+	}
+	size := int(body[0])
+	return string(body[1 : 1+size]), body[1+size:], nil
+}
+
+// encodeMetadata serializes connection properties. Keys are sorted to keep the encoding deterministic.
+func encodeMetadata(props map[string]string) []byte {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, k := range keys {
+		buf.WriteByte(byte(len(k)))
+		buf.WriteString(k)
+		size := make([]byte, 4)
+		binary.BigEndian.PutUint32(size, uint32(len(props[k])))
+		buf.Write(size)
+		buf.WriteString(props[k])
+	}
+	return buf.Bytes()
+}
+
+// decodeMetadata parses connection properties. Property names are case-insensitive so keys are returned lower cased.
+func decodeMetadata(b []byte) (map[string]string, error) {
+	props := map[string]string{}
+	for len(b) > 0 {
+		nameSize := int(b[0])
+		if len(b) < 1+nameSize+4 {
+			return nil, errors.New("malformed metadata")
+		}
+		name := strings.ToLower(string(b[1 : 1+nameSize]))
+		b = b[1+nameSize:]
+		valueSize := int(binary.BigEndian.Uint32(b[:4]))
+		if len(b) < 4+valueSize {
+			return nil, errors.New("malformed metadata")
+		}
+		props[name] = string(b[4 : 4+valueSize])
+		b = b[4+valueSize:]
+	}
+	return props, nil
+}
+
+func compatible(local, peer string) bool {
+	switch local {
+	case socketPub:
+		return peer == socketSub || peer == "XSUB"
+	case socketSub:
+		return peer == socketPub || peer == "XPUB"
+	case socketPush:
+		return peer == socketPull
+	case socketPull:
+		return peer == socketPush
+	}
+	return false
+}
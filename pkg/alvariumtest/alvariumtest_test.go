@@ -0,0 +1,129 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package alvariumtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+	cfg := Config()
+	assert.NoError(t, cfg.Validate())
+	assert.NotEqual(t, Config().Signature.PrivateKey.Material, cfg.Signature.PrivateKey.Material)
+}
+
+func TestStart(t *testing.T) {
+	tpm := NewFakeAnnotator(contracts.AnnotationTPM, false)
+	tls := NewFakeAnnotator(contracts.AnnotationTLS, true)
+	sdk, rec := Start(t, Config(), []interfaces.Annotator{tpm, tls})
+
+	sdk.Create(context.Background(), []byte("foo"))
+	sdk.Create(context.Background(), []byte("bar"))
+
+	rec.AssertAnnotated(t, contracts.AnnotationTPM, false)
+	rec.AssertAnnotated(t, contracts.AnnotationTLS, true)
+	rec.AssertCount(t, contracts.AnnotationTPM, 2)
+	assert.Equal(t, [][]byte{[]byte("foo"), []byte("bar")}, tpm.Calls())
+
+	items, err := rec.Annotations()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Len(t, items, 4)
+	for _, a := range items {
+		assert.Equal(t, FakeHost, a.Host)
+		assert.Equal(t, contracts.Application, a.Layer)
+	}
+	assert.Equal(t, "00000000000000000000000001", items[0].Id.String())
+
+	rec.Reset()
+	rec.AssertNone(t)
+}
+
+func TestStart_BuiltIn(t *testing.T) {
+	cfg := Config()
+	src, err := factories.NewAnnotator(contracts.AnnotationSource, cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	clock := NewClock(time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC))
+	sdk, rec := Start(t, cfg, []interfaces.Annotator{src}, pkg.WithClock(clock))
+
+	sdk.Create(context.Background(), []byte("foo"))
+	clock.Advance(time.Minute)
+	sdk.Create(context.Background(), []byte("foo"))
+
+	items, err := rec.Annotations()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Len(t, items, 2)
+	assert.True(t, clock.Now().Add(-time.Minute).Equal(items[0].Timestamp))
+	assert.True(t, clock.Now().Equal(items[1].Timestamp))
+	assert.NotEmpty(t, items[0].Signature)
+}
+
+func TestFakeAnnotator_Failing(t *testing.T) {
+	cause := errors.New("boom")
+	a := NewFakeAnnotator(contracts.AnnotationSource, true).Failing(cause)
+	_, err := a.Do(context.Background(), []byte("foo"))
+	assert.ErrorIs(t, err, cause)
+	assert.Len(t, a.Calls(), 1)
+}
+
+func TestRecorder_Fail(t *testing.T) {
+	rec := NewRecorder()
+	cause := errors.New("unreachable")
+	rec.Fail(cause)
+	assert.ErrorIs(t, rec.Publish(context.Background(), message.PublishWrapper{}), cause)
+	rec.AssertNone(t)
+
+	rec.Fail(nil)
+	send := rec.Intercept(func(context.Context, message.PublishWrapper) error {
+		t.Fatalf("the wrapper was passed on")
+		return nil
+	})
+	assert.NoError(t, send(context.Background(), message.PublishWrapper{}))
+	assert.Len(t, rec.Messages(), 1)
+}
+
+func TestRecorder_Assertions(t *testing.T) {
+	rec := NewRecorder()
+	mock := &testing.T{}
+	rec.AssertAnnotated(mock, contracts.AnnotationSource, true)
+	assert.True(t, mock.Failed())
+
+	mock = &testing.T{}
+	rec.AssertCount(mock, contracts.AnnotationSource, 0)
+	assert.False(t, mock.Failed())
+}
+
+func TestSequence(t *testing.T) {
+	seq := NewSequence()
+	first := seq.NewId(contracts.Annotation{})
+	second := seq.NewId(contracts.Annotation{})
+	assert.Equal(t, "00000000000000000000000001", first.String())
+	assert.Equal(t, "00000000000000000000000002", second.String())
+}
@@ -0,0 +1,95 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package alvariumtest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+)
+
+// FakeHost is the host recorded by the annotations of a FakeAnnotator
+const FakeHost = "alvariumtest"
+
+// FakeAnnotator makes annotations of a configured kind and outcome without inspecting the data beyond hashing it with
+// sha256. The annotations are not signed. It records the data it is called with and is safe for concurrent use.
+type FakeAnnotator struct {
+	kind      contracts.AnnotationType
+	satisfied bool
+	layer     contracts.LayerType
+	err       error
+
+	mu    sync.Mutex
+	calls [][]byte
+}
+
+// NewFakeAnnotator returns an annotator making annotations of kind at the app layer, satisfied as given
+func NewFakeAnnotator(kind contracts.AnnotationType, satisfied bool) *FakeAnnotator {
+	return &FakeAnnotator{kind: kind, satisfied: satisfied, layer: contracts.Application}
+}
+
+// WithLayer makes the annotations at layer
+func (a *FakeAnnotator) WithLayer(layer contracts.LayerType) *FakeAnnotator {
+	a.layer = layer
+	return a
+}
+
+// Failing makes the annotator return err in place of an annotation
+func (a *FakeAnnotator) Failing(err error) *FakeAnnotator {
+	a.err = err
+	return a
+}
+
+// Kind returns the kind of annotation made by the annotator
+func (a *FakeAnnotator) Kind() contracts.AnnotationType {
+	return a.kind
+}
+
+// Do annotates data, reading the identifier, timestamp, parent key, tag and host attributes from ctx as the built-in
+// annotators do
+func (a *FakeAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
+	a.mu.Lock()
+	a.calls = append(a.calls, append([]byte(nil), data...))
+	a.mu.Unlock()
+	if a.err != nil {
+		return contracts.Annotation{}, a.err
+	}
+
+	hash, err := factories.NewHashProvider(contracts.SHA256Hash)
+	if err != nil {
+		return contracts.Annotation{}, err
+	}
+	key, err := annotators.DeriveHash(ctx, hash, data)
+	if err != nil {
+		return contracts.Annotation{}, err
+	}
+	annotation := contracts.NewAnnotation(key, contracts.SHA256Hash, FakeHost, a.layer, a.kind, a.satisfied)
+	annotation.Timestamp = annotators.Timestamp(ctx, annotation)
+	annotation.ParentKey = annotators.ParentKey(ctx)
+	annotation.Tag = annotators.TagValue(ctx, annotation)
+	annotation.HostInfo = annotators.HostInfo(ctx)
+	annotation.Id = annotators.NewId(ctx, annotation)
+	return annotation, nil
+}
+
+// Calls returns the data the annotator was called with, in order
+func (a *FakeAnnotator) Calls() [][]byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([][]byte(nil), a.calls...)
+}
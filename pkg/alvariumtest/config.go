@@ -0,0 +1,43 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package alvariumtest
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// Config returns a valid configuration making source annotations at the app layer, hashed with sha256 and signed
+// with an ed25519 key pair generated for the call and held in memory, published to the mock stream. Each call
+// generates new keys.
+func Config() config.SdkInfo {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil { // the system random source never fails
+		panic(err)
+	}
+	return config.SdkInfo{
+		Annotators: []contracts.AnnotationType{contracts.AnnotationSource},
+		Hash:       config.HashInfo{Type: contracts.SHA256Hash},
+		Signature: config.SignatureInfo{
+			PublicKey:  config.KeyInfo{Type: contracts.KeyEd25519, Material: []byte(hex.EncodeToString(public))},
+			PrivateKey: config.KeyInfo{Type: contracts.KeyEd25519, Material: []byte(hex.EncodeToString(private))},
+		},
+		Stream: config.StreamInfo{Type: contracts.MockStream, Config: config.MockStreamConfig{}},
+		Layer:  contracts.Application,
+	}
+}
@@ -0,0 +1,79 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package alvariumtest
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// Sequence identifies annotations with consecutive identifiers, 00000000000000000000000001 first, so that tests can
+// compare them to known values, see pkg.WithIdGenerator. It is safe for concurrent use.
+type Sequence struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+// NewSequence returns a Sequence starting at 1
+func NewSequence() *Sequence {
+	return &Sequence{next: 1}
+}
+
+// NewId returns the next identifier of the sequence, whatever the annotation
+func (s *Sequence) NewId(contracts.Annotation) ulid.ULID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var id ulid.ULID
+	binary.BigEndian.PutUint64(id[8:], s.next)
+	s.next++
+	return id
+}
+
+// Clock tells a time set by the test, which only changes when told to, see pkg.WithClock. It is safe for concurrent
+// use.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a Clock telling now
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the time of the clock
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to now
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock forward by d
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
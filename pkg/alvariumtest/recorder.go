@@ -0,0 +1,183 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package alvariumtest helps applications unit test their integration of the SDK without a broker, a ledger or key
+// files. A Recorder stands in for the stream provider and asserts what was published, a FakeAnnotator makes
+// annotations of a chosen kind and outcome, Sequence and Clock make identifiers and timestamps predictable, and Config
+// returns a complete configuration with keys generated in memory. Start puts them together:
+//
+//	tpm := alvariumtest.NewFakeAnnotator(contracts.AnnotationTPM, false)
+//	sdk, rec := alvariumtest.Start(t, alvariumtest.Config(), []interfaces.Annotator{tpm})
+//	sdk.Create(ctx, data)
+//	rec.AssertAnnotated(t, contracts.AnnotationTPM, false)
+package alvariumtest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/verification"
+)
+
+// Recorder is an in-memory stream provider keeping every wrapper published to it. It is safe for concurrent use, so
+// the SDK may publish to it from asynchronous workers.
+type Recorder struct {
+	mu       sync.Mutex
+	messages []message.PublishWrapper
+	err      error
+}
+
+// NewRecorder returns an empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Connect does nothing, the recorder is always connected
+func (r *Recorder) Connect(ctx context.Context) error {
+	return nil
+}
+
+// Close does nothing, the recorder keeps what was published to it
+func (r *Recorder) Close() error {
+	return nil
+}
+
+// Publish records msg, or returns the error set by Fail
+func (r *Recorder) Publish(ctx context.Context, msg message.PublishWrapper) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.messages = append(r.messages, msg)
+	return nil
+}
+
+// Intercept records the wrappers published by the SDK in place of its stream provider, for SDKs that publish to a
+// configured provider, see pkg.WithPublishInterceptors. The wrappers are not passed on to the provider.
+func (r *Recorder) Intercept(next interfaces.PublishFunc) interfaces.PublishFunc {
+	return r.Publish
+}
+
+// Fail makes the following publishes return err, so that the handling of failures can be tested. A nil err makes
+// them succeed again.
+func (r *Recorder) Fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+// Reset forgets what was published so far
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = nil
+}
+
+// Messages returns the wrappers published so far, in order
+func (r *Recorder) Messages() []message.PublishWrapper {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]message.PublishWrapper(nil), r.messages...)
+}
+
+// Annotations returns the annotations carried by the wrappers published so far, in order. Their signatures are not
+// verified.
+func (r *Recorder) Annotations() ([]contracts.Annotation, error) {
+	var items []contracts.Annotation
+	for i, msg := range r.Messages() {
+		list, err := verification.Decode(msg)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		items = append(items, list.Items...)
+	}
+	return items, nil
+}
+
+// AssertAnnotated fails t unless an annotation of the given kind and outcome was published
+func (r *Recorder) AssertAnnotated(t testing.TB, kind contracts.AnnotationType, satisfied bool) {
+	t.Helper()
+	items, err := r.Annotations()
+	if err != nil {
+		t.Errorf("decoding published annotations: %v", err)
+		return
+	}
+	for _, a := range items {
+		if a.Kind == kind && a.IsSatisfied == satisfied {
+			return
+		}
+	}
+	t.Errorf("no %s annotation with isSatisfied %t among %d published", kind, satisfied, len(items))
+}
+
+// AssertCount fails t unless exactly n annotations of the given kind were published
+func (r *Recorder) AssertCount(t testing.TB, kind contracts.AnnotationType, n int) {
+	t.Helper()
+	items, err := r.Annotations()
+	if err != nil {
+		t.Errorf("decoding published annotations: %v", err)
+		return
+	}
+	count := 0
+	for _, a := range items {
+		if a.Kind == kind {
+			count++
+		}
+	}
+	if count != n {
+		t.Errorf("%d %s annotations published, expected %d", count, kind, n)
+	}
+}
+
+// AssertNone fails t if anything was published
+func (r *Recorder) AssertNone(t testing.TB) {
+	t.Helper()
+	if n := len(r.Messages()); n != 0 {
+		t.Errorf("%d messages published, expected none", n)
+	}
+}
+
+// Start creates an SDK applying annotators with cfg, such as the configuration returned by Config, publishing to a
+// Recorder, and bootstraps it. Identifiers are drawn from a Sequence unless opts say otherwise. The SDK is shut down
+// when the test completes.
+func Start(t testing.TB, cfg config.SdkInfo, annotators []interfaces.Annotator,
+	opts ...pkg.Option) (interfaces.Sdk, *Recorder) {
+	t.Helper()
+	rec := NewRecorder()
+	logger := factories.NewLogger(config.LoggingInfo{MinLogLevel: slog.LevelWarn})
+	opts = append([]pkg.Option{pkg.WithIdGenerator(NewSequence()), pkg.WithStreamProvider(rec)}, opts...)
+	sdk := pkg.NewSdk(annotators, cfg, logger, opts...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	if !sdk.BootstrapHandler(ctx, &wg) {
+		cancel()
+		t.Fatalf("bootstrapping the sdk failed")
+	}
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+	return sdk, rec
+}
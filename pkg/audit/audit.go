@@ -0,0 +1,188 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package audit reads annotations back from where they were published, such as a Hedera consensus topic, and checks
+// that they are what was published: that every signature verifies, that no annotation was recorded twice, and that
+// the annotations chained by each host (see config.SdkInfo.Chain) follow each other without gaps. Publishers only
+// learn that a message was accepted; an audit confirms what the ledger actually holds.
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/verification"
+)
+
+// Record is a message read back from a Source
+type Record struct {
+	Position string // Position locates the record in its source, such as the sequence number of a topic message
+	Payload  []byte // Payload is the publish wrapper as published, in JSON or CBOR
+}
+
+// Source reads back the messages published to a ledger or topic
+type Source interface {
+	// Read hands the records of the source to fn in the order they were recorded, stopping at the first error
+	// returned by fn or once ctx is done
+	Read(ctx context.Context, fn func(Record) error) error
+}
+
+// FindingKind classifies the problems found by an audit
+type FindingKind string
+
+const (
+	FindingMalformed        FindingKind = "malformed"         // the record is not a readable publish wrapper
+	FindingInvalidSignature FindingKind = "invalid-signature" // the annotation does not match its signature
+	FindingDuplicate        FindingKind = "duplicate"         // the annotation was already recorded
+	FindingGap              FindingKind = "gap"               // the annotation before it in its host's chain is missing
+	FindingFork             FindingKind = "fork"              // another annotation already follows the same annotation
+)
+
+// Finding is a problem with a record, or with an annotation it carries
+type Finding struct {
+	Kind     FindingKind
+	Position string // Position locates the record in its source
+	Host     string // Host is the host that made the annotation, if the finding concerns one
+	Id       string // Id identifies the annotation, if the finding concerns one
+	Err      error  // Err details the problem, if any
+}
+
+func (f Finding) String() string {
+	s := fmt.Sprintf("%s at %s", f.Kind, f.Position)
+	if f.Id != "" {
+		s += fmt.Sprintf(": annotation %s of host %s", f.Id, f.Host)
+	}
+	if f.Err != nil {
+		s += fmt.Sprintf(": %v", f.Err)
+	}
+	return s
+}
+
+// Report summarizes an audit
+type Report struct {
+	Records     int            // Records is the number of records read
+	Annotations int            // Annotations is the number of annotations the records carried
+	Hosts       map[string]int // Hosts gives the number of annotations made by each host
+	Findings    []Finding      // Findings lists the problems found, in the order of the records
+}
+
+// OK reports whether the audit found no problem
+func (r Report) OK() bool {
+	return len(r.Findings) == 0
+}
+
+// chain follows the annotations chained by a host. Several SDK instances on the same host each extend their own
+// chain, whose most recent annotations are the tips.
+type chain struct {
+	tips map[string]bool // tips holds the chain hashes of the annotations no other has followed yet
+	seen map[string]bool // seen holds the chain hashes of all annotations of the host
+}
+
+// auditor accumulates the report of an audit
+type auditor struct {
+	keys   verification.KeyResolver
+	report Report
+	ids    map[string]bool
+	chains map[string]*chain
+}
+
+// Audit reads every record of src and checks the annotations they carry, verifying their signatures with the keys
+// supplied by keys. Records carrying other messages than annotation lists are counted but not otherwise checked.
+// The returned error reports a failure to read src; the problems found in what was read are listed by the report.
+//
+// The first annotation read of each host may follow annotations before those held by src, so it is not reported as a
+// gap. Reading a topic from its beginning leaves no such blind spot.
+func Audit(ctx context.Context, src Source, keys verification.KeyResolver) (Report, error) {
+	a := auditor{
+		keys:   keys,
+		report: Report{Hosts: make(map[string]int)},
+		ids:    make(map[string]bool),
+		chains: make(map[string]*chain),
+	}
+	err := src.Read(ctx, func(r Record) error {
+		a.record(r)
+		return nil
+	})
+	return a.report, err
+}
+
+func (a *auditor) record(r Record) {
+	a.report.Records++
+	var msg message.PublishWrapper
+	if err := message.Unmarshal(r.Payload, &msg); err != nil {
+		a.find(Finding{Kind: FindingMalformed, Position: r.Position, Err: err})
+		return
+	}
+	if msg.MessageType != fmt.Sprintf("%T", contracts.AnnotationList{}) {
+		return
+	}
+	list, err := verification.Decode(msg)
+	if err != nil {
+		a.find(Finding{Kind: FindingMalformed, Position: r.Position, Err: err})
+		return
+	}
+	for _, item := range list.Items {
+		a.annotation(r.Position, item)
+	}
+}
+
+func (a *auditor) annotation(position string, item contracts.Annotation) {
+	a.report.Annotations++
+	a.report.Hosts[item.Host]++
+	finding := Finding{Position: position, Host: item.Host, Id: item.Id.String()}
+
+	if a.ids[finding.Id] {
+		finding.Kind = FindingDuplicate
+		a.find(finding)
+		return
+	}
+	a.ids[finding.Id] = true
+
+	if err := verification.VerifyAnnotation(item, a.keys); err != nil {
+		finding.Kind, finding.Err = FindingInvalidSignature, err
+		a.find(finding)
+	}
+
+	c, started := a.chains[item.Host]
+	if !started {
+		c = &chain{tips: make(map[string]bool), seen: make(map[string]bool)}
+		a.chains[item.Host] = c
+	}
+	if prev := item.PrevHash; prev != "" {
+		switch {
+		case c.tips[prev]:
+			delete(c.tips, prev)
+		case c.seen[prev]:
+			finding.Kind, finding.Err = FindingFork, fmt.Errorf("%w, the annotation it follows was already followed",
+				verification.ErrBrokenChain)
+			a.find(finding)
+		case started:
+			finding.Kind, finding.Err = FindingGap, fmt.Errorf("%w, the annotation it follows was not recorded",
+				verification.ErrBrokenChain)
+			a.find(finding)
+		}
+	}
+	// the hash covers every field but the signature, so a tampered annotation breaks the chain after it as well
+	if h, err := annotators.ChainHash(item); err == nil {
+		c.tips[h] = true
+		c.seen[h] = true
+	}
+}
+
+func (a *auditor) find(f Finding) {
+	a.report.Findings = append(a.report.Findings, f)
+}
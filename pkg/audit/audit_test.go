@@ -0,0 +1,237 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/verification"
+	"github.com/stretchr/testify/assert"
+)
+
+var testKeys = config.SignatureInfo{
+	PrivateKey: config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/private.key"},
+	PublicKey:  config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/public.key"},
+}
+
+// sliceSource reads records from memory
+type sliceSource []Record
+
+func (s sliceSource) Read(ctx context.Context, fn func(Record) error) error {
+	for _, r := range s {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chained returns n annotations made by host, chained in order
+func chained(t *testing.T, host string, n int) []contracts.Annotation {
+	ctx := context.WithValue(context.Background(), contracts.ChainKey, annotators.NewChain())
+	items := make([]contracts.Annotation, n)
+	for i := range items {
+		items[i] = contracts.NewAnnotation(fmt.Sprintf("key-%d", i), contracts.SHA256Hash, host, contracts.Host,
+			contracts.AnnotationSource, true)
+		if err := annotators.SignAndLink(ctx, testKeys, ed25519.New(), &items[i]); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+	return items
+}
+
+// record wraps the annotations as published
+func record(t *testing.T, position int, items ...contracts.Annotation) Record {
+	list := contracts.AnnotationList{Items: items}
+	content, _ := json.Marshal(list)
+	b, err := message.Marshal(message.PublishWrapper{
+		Version:     message.WrapperVersion,
+		Action:      message.ActionCreate,
+		MessageType: fmt.Sprintf("%T", list),
+		Content:     content,
+	})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	return Record{Position: fmt.Sprint(position), Payload: b}
+}
+
+func kinds(findings []Finding) []FindingKind {
+	var k []FindingKind
+	for _, f := range findings {
+		k = append(k, f.Kind)
+	}
+	return k
+}
+
+func TestAudit(t *testing.T) {
+	a := chained(t, "edge-01", 4)
+	b := chained(t, "edge-02", 2)
+
+	tampered := a[1]
+	tampered.IsSatisfied = false
+	forked := a[2]
+	forked.Id = contracts.NewULID()
+	if err := annotators.SignWithActiveKey(testKeys, ed25519.New(), &forked); err != nil {
+		t.Fatalf(err.Error())
+	}
+	broadcast, _ := message.Marshal(message.PublishWrapper{Action: message.ActionBroadcast, MessageType: "string",
+		Content: []byte("0.0.1234")})
+
+	tests := []struct {
+		name     string
+		records  sliceSource
+		expected []FindingKind
+	}{
+		{"intact", sliceSource{record(t, 1, a[0], a[1]), record(t, 2, b[0]), record(t, 3, a[2], a[3]),
+			record(t, 4, b[1])}, nil},
+		{"other messages", sliceSource{{Position: "1", Payload: broadcast}, record(t, 2, a[0])}, nil},
+		{"window starting mid chain", sliceSource{record(t, 1, a[2], a[3])}, nil},
+		{"missing record", sliceSource{record(t, 1, a[0]), record(t, 3, a[2], a[3])},
+			[]FindingKind{FindingGap}},
+		{"tampered annotation", sliceSource{record(t, 1, a[0], tampered, a[2])},
+			[]FindingKind{FindingInvalidSignature, FindingGap}},
+		{"duplicate record", sliceSource{record(t, 1, a[0], a[1]), record(t, 2, a[0], a[1])},
+			[]FindingKind{FindingDuplicate, FindingDuplicate}},
+		{"fork", sliceSource{record(t, 1, a[0], a[1], a[2]), record(t, 2, forked)}, []FindingKind{FindingFork}},
+		{"malformed record", sliceSource{{Position: "1", Payload: []byte("{")}, record(t, 2, a[0])},
+			[]FindingKind{FindingMalformed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Audit(context.Background(), tt.records, verification.NewConfigResolver(testKeys))
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Equal(t, tt.expected, kinds(report.Findings))
+			assert.Equal(t, len(tt.records), report.Records)
+			assert.Equal(t, len(tt.expected) == 0, report.OK())
+		})
+	}
+
+	t.Run("report", func(t *testing.T) {
+		records := sliceSource{record(t, 1, a[0]), record(t, 2, b[0], b[1]), record(t, 3, a[2])}
+		report, err := Audit(context.Background(), records, verification.NewConfigResolver(testKeys))
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		assert.Equal(t, 4, report.Annotations)
+		assert.Equal(t, map[string]int{"edge-01": 2, "edge-02": 2}, report.Hosts)
+		assert.Len(t, report.Findings, 1)
+		f := report.Findings[0]
+		assert.Equal(t, Finding{Kind: FindingGap, Position: "3", Host: "edge-01", Id: a[2].Id.String(), Err: f.Err}, f)
+		assert.ErrorIs(t, f.Err, verification.ErrBrokenChain)
+		assert.Contains(t, f.String(), "gap at 3: annotation "+a[2].Id.String()+" of host edge-01")
+	})
+}
+
+func TestHederaSource(t *testing.T) {
+	a := chained(t, "edge-01", 3)
+	whole := record(t, 0, a[0]).Payload
+	chunked := record(t, 0, a[1], a[2]).Payload
+	half := len(chunked) / 2
+	chunk := func(seq, number int, part []byte) map[string]any {
+		return map[string]any{
+			"sequence_number": seq,
+			"message":         part,
+			"chunk_info": map[string]any{
+				"initial_transaction_id": map[string]any{"account_id": "0.0.2", "nonce": 0,
+					"transaction_valid_start": "1718090092.391862000"},
+				"number": number,
+				"total":  2,
+			},
+		}
+	}
+
+	pages := map[string]any{
+		"/api/v1/topics/0.0.1234/messages?order=asc&limit=100": map[string]any{
+			"messages": []any{
+				map[string]any{"sequence_number": 1, "message": whole},
+				chunk(2, 1, chunked[:half]),
+			},
+			"links": map[string]any{"next": "/api/v1/topics/0.0.1234/messages?order=asc&limit=100&sequencenumber=gt:2"},
+		},
+		"/api/v1/topics/0.0.1234/messages?order=asc&limit=100&sequencenumber=gt:2": map[string]any{
+			"messages": []any{chunk(3, 2, chunked[half:])},
+			"links":    map[string]any{"next": nil},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	src, err := NewHederaSource(config.HederaMirrorInfo{Url: server.URL + "/"}, "0.0.1234")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	var records []Record
+	err = src.Read(context.Background(), func(r Record) error {
+		records = append(records, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Equal(t, []Record{{Position: "sequence 1", Payload: whole}, {Position: "sequence 2", Payload: chunked}},
+		records)
+
+	report, err := Audit(context.Background(), src, verification.NewConfigResolver(testKeys))
+	assert.NoError(t, err)
+	assert.True(t, report.OK(), "%v", report.Findings)
+	assert.Equal(t, 3, report.Annotations)
+
+	_, err = Audit(context.Background(), &hederaSource{url: server.URL, topic: "0.0.9999", client: http.DefaultClient},
+		verification.NewConfigResolver(testKeys))
+	assert.Error(t, err)
+
+	_, err = NewHederaSource(config.HederaMirrorInfo{}, "0.0.1234")
+	assert.ErrorIs(t, err, contracts.ErrConfigInvalid)
+}
+
+func TestReaderSource(t *testing.T) {
+	a := chained(t, "edge-01", 2)
+	input := string(record(t, 0, a[0]).Payload) + "\n\n" + string(record(t, 0, a[1]).Payload) + "\n"
+
+	var positions []string
+	err := NewReaderSource(strings.NewReader(input)).Read(context.Background(), func(r Record) error {
+		positions = append(positions, r.Position)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"line 1", "line 3"}, positions)
+
+	report, err := Audit(context.Background(), NewReaderSource(strings.NewReader(input)),
+		verification.NewConfigResolver(testKeys))
+	assert.NoError(t, err)
+	assert.True(t, report.OK(), "%v", report.Findings)
+	assert.Equal(t, 2, report.Annotations)
+}
@@ -0,0 +1,142 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// mirrorPageSize is the number of topic messages requested at once, the most mirror nodes return
+const mirrorPageSize = 100
+
+// mirrorMessages is the subset of the mirror node REST representation of a page of topic messages used by the audit
+type mirrorMessages struct {
+	Messages []mirrorMessage `json:"messages"`
+	Links    struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+type mirrorMessage struct {
+	Message        []byte `json:"message"`
+	SequenceNumber uint64 `json:"sequence_number"`
+	ChunkInfo      *struct {
+		// InitialTransactionId identifies the transaction of the first chunk, shared by all chunks of a message
+		InitialTransactionId json.RawMessage `json:"initial_transaction_id"`
+		Number               int             `json:"number"`
+		Total                int             `json:"total"`
+	} `json:"chunk_info"`
+}
+
+// hederaSource reads the messages of a consensus topic from a mirror node
+type hederaSource struct {
+	url    string
+	topic  string
+	client *http.Client
+}
+
+// NewHederaSource returns a Source reading the messages of a consensus topic, as published by the hedera stream
+// provider, through the REST API of the mirror node at cfg.Url. Messages the consensus service divided into chunks
+// are reassembled. A publisher writing to several topics writes the same messages to each, so a single topic is
+// read.
+func NewHederaSource(cfg config.HederaMirrorInfo, topic string) (Source, error) {
+	if cfg.Url == "" {
+		return nil, fmt.Errorf("%w: no mirror node url", contracts.ErrConfigInvalid)
+	}
+	if cfg.Timeout < 0 {
+		return nil, fmt.Errorf("%w: invalid mirror node timeout %v", contracts.ErrConfigInvalid, cfg.Timeout)
+	}
+	return &hederaSource{
+		url:    strings.TrimSuffix(cfg.Url, "/"),
+		topic:  topic,
+		client: &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+	}, nil
+}
+
+func (s *hederaSource) Read(ctx context.Context, fn func(Record) error) error {
+	chunks := make(map[string][]mirrorMessage)
+	next := fmt.Sprintf("/api/v1/topics/%s/messages?order=asc&limit=%d", s.topic, mirrorPageSize)
+	for next != "" {
+		page, err := s.page(ctx, next)
+		if err != nil {
+			return err
+		}
+		for _, msg := range page.Messages {
+			position := "sequence " + strconv.FormatUint(msg.SequenceNumber, 10)
+			if msg.ChunkInfo == nil || msg.ChunkInfo.Total <= 1 {
+				if err = fn(Record{Position: position, Payload: msg.Message}); err != nil {
+					return err
+				}
+				continue
+			}
+
+			id := string(msg.ChunkInfo.InitialTransactionId)
+			chunks[id] = append(chunks[id], msg)
+			if len(chunks[id]) < msg.ChunkInfo.Total {
+				continue
+			}
+			parts := chunks[id]
+			delete(chunks, id)
+			sort.Slice(parts, func(i, j int) bool { return parts[i].ChunkInfo.Number < parts[j].ChunkInfo.Number })
+			var payload []byte
+			for _, part := range parts {
+				payload = append(payload, part.Message...)
+			}
+			// the message is located by its first chunk
+			position = "sequence " + strconv.FormatUint(parts[0].SequenceNumber, 10)
+			if err = fn(Record{Position: position, Payload: payload}); err != nil {
+				return err
+			}
+		}
+		next = page.Links.Next
+	}
+	return nil
+}
+
+// page fetches the page of topic messages at path, relative to the mirror node url as the links it returns are
+func (s *hederaSource) page(ctx context.Context, path string) (mirrorMessages, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+path, nil)
+	if err != nil {
+		return mirrorMessages{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return mirrorMessages{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return mirrorMessages{}, fmt.Errorf("mirror node returned %s %s", resp.Status, string(body))
+	}
+
+	var page mirrorMessages
+	if err = json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return mirrorMessages{}, err
+	}
+	return page, nil
+}
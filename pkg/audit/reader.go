@@ -0,0 +1,58 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+)
+
+// maxLineSize bounds the records read by a reader source, large enough for the wrappers of big annotation lists
+const maxLineSize = 16 * 1024 * 1024
+
+// readerSource reads records from lines of text
+type readerSource struct {
+	r io.Reader
+}
+
+// NewReaderSource returns a Source reading JSON publish wrappers from r, one per line, such as the output of
+// kafka-console-consumer or of mosquitto_sub for the topic the annotations were published to. Blank lines are
+// skipped and records are located by their line number.
+func NewReaderSource(r io.Reader) Source {
+	return &readerSource{r: r}
+}
+
+func (s *readerSource) Read(ctx context.Context, fn func(Record) error) error {
+	scanner := bufio.NewScanner(s.r)
+	scanner.Buffer(nil, maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		// the scanner reuses its buffer
+		payload := append([]byte(nil), b...)
+		if err := fn(Record{Position: "line " + strconv.Itoa(line), Payload: payload}); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
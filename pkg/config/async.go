@@ -0,0 +1,41 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// AsyncInfo configures the SDK to annotate, sign and publish in the background, so that Create, Mutate, Transit and
+// Publish return as soon as their work is queued. Asynchronous mode is enabled by supplying a number of Workers.
+type AsyncInfo struct {
+	Workers   int                          `json:"workers,omitempty" yaml:"workers"`     // Workers is the number of goroutines processing queued work
+	QueueSize int                          `json:"queueSize,omitempty" yaml:"queueSize"` // QueueSize bounds the queued work, defaults to 100
+	Policy    contracts.BackpressurePolicy `json:"policy,omitempty" yaml:"policy"`       // Policy applies once the queue is full, defaults to block
+}
+
+// validate checks the asynchronous settings, allowing the zero value which disables asynchronous mode
+func (a AsyncInfo) validate() error {
+	if a.Workers < 0 || a.QueueSize < 0 {
+		return fmt.Errorf("%w: invalid async settings workers=%v queueSize=%v",
+			contracts.ErrConfigInvalid, a.Workers, a.QueueSize)
+	}
+	if a.Policy != "" && !a.Policy.Validate() {
+		return fmt.Errorf("%w: invalid BackpressurePolicy value provided %s", contracts.ErrConfigInvalid, a.Policy)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+// BatchInfo configures coalescing of annotations into larger AnnotationList envelopes before they are published. A
+// batch is flushed as soon as any of the limits is reached. Batching is enabled by setting any limit.
+type BatchInfo struct {
+	MaxCount      int `json:"maxCount,omitempty" yaml:"maxCount"`           // MaxCount is the maximum number of annotations per batch
+	MaxBytes      int `json:"maxBytes,omitempty" yaml:"maxBytes"`           // MaxBytes is the maximum encoded size of the annotations in a batch
+	FlushInterval int `json:"flushInterval,omitempty" yaml:"flushInterval"` // FlushInterval is the maximum number of milliseconds a batch is held
+}
+
+// Enabled reports whether any batching limit has been configured
+func (b BatchInfo) Enabled() bool {
+	return b.MaxCount > 0 || b.MaxBytes > 0 || b.FlushInterval > 0
+}
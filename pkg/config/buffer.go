@@ -0,0 +1,26 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import "github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+
+// BufferInfo configures an on-disk queue that holds publishes while the stream provider is unreachable. Buffering
+// is enabled by supplying a Path.
+type BufferInfo struct {
+	Path          string               `json:"path,omitempty" yaml:"path"`                   // Path is the location of the queue database file
+	Capacity      int                  `json:"capacity,omitempty" yaml:"capacity"`           // Capacity is the maximum number of buffered messages
+	DropPolicy    contracts.DropPolicy `json:"dropPolicy,omitempty" yaml:"dropPolicy"`       // DropPolicy applies once Capacity is reached
+	RetryInterval int                  `json:"retryInterval,omitempty" yaml:"retryInterval"` // RetryInterval is the number of seconds between reconnect attempts
+}
@@ -0,0 +1,217 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// Builder assembles the SDK configuration in code, for applications deriving it from their own settings, e.g.
+//
+//	cfg, err := config.NewBuilder().
+//		WithMqtt(mqtt).
+//		WithHash(contracts.SHA256Hash).
+//		WithAnnotators(contracts.AnnotationPKI, contracts.AnnotationTLS).
+//		Build()
+//
+// A Builder starts from the defaults of a loaded configuration, see Default, and Build checks the result as a
+// loaded configuration is checked.
+type Builder struct {
+	cfg SdkInfo
+}
+
+// NewBuilder returns a Builder starting from the default configuration
+func NewBuilder() *Builder {
+	return &Builder{cfg: Default()}
+}
+
+// WithAnnotators sets the kinds of annotation made
+func (b *Builder) WithAnnotators(kinds ...contracts.AnnotationType) *Builder {
+	b.cfg.Annotators = append([]contracts.AnnotationType(nil), kinds...)
+	return b
+}
+
+// WithLayer sets the layer of the stack the annotations are made in
+func (b *Builder) WithLayer(layer contracts.LayerType) *Builder {
+	b.cfg.Layer = layer
+	return b
+}
+
+// WithHash sets the hash type, keeping the other hash settings
+func (b *Builder) WithHash(hash contracts.HashType) *Builder {
+	b.cfg.Hash.Type = hash
+	return b
+}
+
+// WithHashInfo replaces the hash settings
+func (b *Builder) WithHashInfo(hash HashInfo) *Builder {
+	b.cfg.Hash = hash
+	return b
+}
+
+// WithKeys sets the public key verifying annotations and the private key signing them
+func (b *Builder) WithKeys(public, private KeyInfo) *Builder {
+	b.cfg.Signature.PublicKey = public
+	b.cfg.Signature.PrivateKey = private
+	return b
+}
+
+// WithSignature replaces the signature settings
+func (b *Builder) WithSignature(signature SignatureInfo) *Builder {
+	b.cfg.Signature = signature
+	return b
+}
+
+// WithStream replaces the stream settings. A nil Config stands for the empty configuration of the stream type.
+func (b *Builder) WithStream(stream StreamInfo) *Builder {
+	b.cfg.Stream = stream
+	return b
+}
+
+// WithConsole writes the annotations to the console, keeping the other stream settings
+func (b *Builder) WithConsole() *Builder {
+	return b.stream(contracts.ConsoleStream, MockStreamConfig{})
+}
+
+// WithMqtt publishes the annotations to an MQTT broker, keeping the other stream settings
+func (b *Builder) WithMqtt(mqtt MqttConfig) *Builder {
+	return b.stream(contracts.MqttStream, mqtt)
+}
+
+// WithHedera submits the annotations to the Hedera consensus service, keeping the other stream settings
+func (b *Builder) WithHedera(hedera HederaConfig) *Builder {
+	return b.stream(contracts.HederaStream, hedera)
+}
+
+// WithEthereum anchors the annotations to an EVM compatible chain, keeping the other stream settings
+func (b *Builder) WithEthereum(ethereum EthereumConfig) *Builder {
+	return b.stream(contracts.EthereumStream, ethereum)
+}
+
+// WithIota posts the annotations to an IOTA node, keeping the other stream settings
+func (b *Builder) WithIota(iota IotaConfig) *Builder {
+	return b.stream(contracts.IotaStream, iota)
+}
+
+// WithZmq distributes the annotations over ZeroMQ, keeping the other stream settings
+func (b *Builder) WithZmq(zmq ZmqConfig) *Builder {
+	return b.stream(contracts.ZmqStream, zmq)
+}
+
+// WithUds hands the annotations to an agent over a local socket, keeping the other stream settings
+func (b *Builder) WithUds(uds UdsConfig) *Builder {
+	return b.stream(contracts.UdsStream, uds)
+}
+
+// WithSyslog emits the annotations to a syslog collector, keeping the other stream settings
+func (b *Builder) WithSyslog(syslog SyslogConfig) *Builder {
+	return b.stream(contracts.SyslogStream, syslog)
+}
+
+// WithOtel exports the annotations to an OpenTelemetry collector, keeping the other stream settings
+func (b *Builder) WithOtel(otel OtelConfig) *Builder {
+	return b.stream(contracts.OtelStream, otel)
+}
+
+// WithFluentd forwards the annotations to a Fluentd collector, keeping the other stream settings
+func (b *Builder) WithFluentd(fluentd FluentdConfig) *Builder {
+	return b.stream(contracts.FluentdStream, fluentd)
+}
+
+func (b *Builder) stream(t contracts.StreamType, cfg any) *Builder {
+	b.cfg.Stream.Type = t
+	b.cfg.Stream.Config = cfg
+	return b
+}
+
+// WithChain links each annotation to the previous one
+func (b *Builder) WithChain(chain bool) *Builder {
+	b.cfg.Chain = chain
+	return b
+}
+
+// WithIdType selects the generator of annotation identifiers
+func (b *Builder) WithIdType(id contracts.IdType) *Builder {
+	b.cfg.IdType = id
+	return b
+}
+
+// With applies fn to the configuration, for the settings without a method of their own
+func (b *Builder) With(fn func(cfg *SdkInfo)) *Builder {
+	fn(&b.cfg)
+	return b
+}
+
+// Build returns the assembled configuration, defaulted and checked as an unmarshalled configuration is. Every
+// problem found is returned, joined. SdkInfo.Validate makes the further checks requiring the key files and
+// environment.
+func (b *Builder) Build() (SdkInfo, error) {
+	cfg := b.cfg
+	cfg.Annotators = append([]contracts.AnnotationType(nil), cfg.Annotators...)
+	cfg.Layers = append([]contracts.LayerType(nil), cfg.Layers...)
+	if cfg.Hash.Type == "" {
+		cfg.Hash.Type = contracts.SHA256Hash
+	}
+	cfg.Signature = cfg.Signature.withDefaults()
+	cfg.Signature.PrivateKeys = defaultKeyTypes(cfg.Signature.PrivateKeys)
+	cfg.Signature.PublicKeys = defaultKeyTypes(cfg.Signature.PublicKeys)
+	if cfg.Stream.Type == "" {
+		cfg.Stream.Type = contracts.ConsoleStream
+	}
+	want, typed := streamConfigs[cfg.Stream.Type]
+	if typed && cfg.Stream.Config == nil {
+		cfg.Stream.Config = want
+	}
+
+	errs := cfg.settings()
+	errs = appendErr(errs, cfg.Hash.validate())
+	errs = appendErr(errs, cfg.Signature.validate())
+	errs = appendErr(errs, cfg.Signature.PublicKey.validate())
+	errs = appendErr(errs, cfg.Signature.PrivateKey.validate())
+	for _, k := range cfg.Signature.PrivateKeys {
+		errs = appendErr(errs, k.validate())
+	}
+	for _, k := range cfg.Signature.PublicKeys {
+		errs = appendErr(errs, k.validate())
+	}
+	errs = appendErr(errs, cfg.Stream.validate())
+	if typed && reflect.TypeOf(cfg.Stream.Config) != reflect.TypeOf(want) {
+		errs = append(errs, fmt.Errorf("%w: stream %s requires a %T config, not %T", contracts.ErrConfigInvalid,
+			cfg.Stream.Type, want, cfg.Stream.Config))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return SdkInfo{}, err
+	}
+	return cfg, nil
+}
+
+// defaultKeyTypes returns a copy of keys in which the keys without algorithm are ed25519 keys, as when unmarshalled
+func defaultKeyTypes(keys []KeyInfo) []KeyInfo {
+	if keys == nil {
+		return nil
+	}
+	defaulted := make([]KeyInfo, len(keys))
+	for i, k := range keys {
+		if k.Type == "" {
+			k.Type = contracts.KeyEd25519
+		}
+		defaulted[i] = k
+	}
+	return defaulted
+}
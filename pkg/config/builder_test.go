@@ -0,0 +1,81 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	mqtt := MqttConfig{ClientId: "sdk", Provider: ServiceInfo{Host: "localhost", Protocol: "tcp", Port: 1883},
+		Topics: []string{"annotations"}}
+
+	cfg, err := NewBuilder().
+		WithMqtt(mqtt).
+		WithHash(contracts.SHA256Hash).
+		WithAnnotators(contracts.AnnotationPKI, contracts.AnnotationTLS).
+		WithLayer(contracts.Host).
+		WithChain(true).
+		With(func(cfg *SdkInfo) { cfg.Stream.Retry.MaxAttempts = 3 }).
+		Build()
+	test.CheckError(err, false, "build", t)
+	assert.Equal(t, []contracts.AnnotationType{contracts.AnnotationPKI, contracts.AnnotationTLS}, cfg.Annotators)
+	assert.Equal(t, contracts.Host, cfg.Layer)
+	assert.Equal(t, contracts.SHA256Hash, cfg.Hash.Type)
+	assert.Equal(t, contracts.MqttStream, cfg.Stream.Type)
+	assert.Equal(t, mqtt, cfg.Stream.Config)
+	assert.Equal(t, 3, cfg.Stream.Retry.MaxAttempts)
+	assert.True(t, cfg.Chain)
+	assert.Equal(t, Default().Signature, cfg.Signature)
+
+	// a built configuration matches the one loaded from the equivalent document
+	loaded, err := Parse([]byte(`{"annotators":["pki","tls"],"layer":"host","chain":true,
+		"stream":{"type":"mqtt","retry":{"maxAttempts":3},"config":{"clientId":"sdk","topics":["annotations"],
+		"provider":{"host":"localhost","protocol":"tcp","port":1883}}}}`), contracts.ConfigJSON)
+	test.CheckError(err, false, "parse", t)
+	assert.Equal(t, loaded, cfg)
+}
+
+func TestBuilder_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *Builder
+		problems int
+	}{
+		{"defaults", NewBuilder(), 0},
+		{"stream without config", NewBuilder().WithStream(StreamInfo{Type: contracts.ConsoleStream}), 0},
+		{"keys without type", NewBuilder().WithKeys(KeyInfo{Path: "public.key"}, KeyInfo{Path: "private.key"}), 0},
+		{"invalid annotator", NewBuilder().WithAnnotators("bogus"), 1},
+		{"invalid hash", NewBuilder().WithHash("sha1"), 1},
+		{"invalid key", NewBuilder().WithKeys(KeyInfo{Type: "rsa"}, KeyInfo{Type: "rsa"}), 2},
+		{"config mismatch", NewBuilder().WithStream(StreamInfo{Type: contracts.MqttStream, Config: HederaConfig{}}), 1},
+		{"several", NewBuilder().WithLayer("kernel").WithIdType("serial").WithHash("sha1"), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			test.CheckError(err, tt.problems > 0, tt.name, t)
+			if err != nil {
+				assert.True(t, errors.Is(err, contracts.ErrConfigInvalid))
+				assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), tt.problems)
+			}
+		})
+	}
+}
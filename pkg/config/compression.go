@@ -0,0 +1,25 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import "github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+
+// CompressionInfo configures compression of publish wrapper content before it is handed to the stream provider.
+// Compression is enabled by supplying an Encoding, which is recorded in the wrapper so subscribers can reverse it.
+type CompressionInfo struct {
+	Encoding contracts.ContentEncoding `json:"encoding,omitempty" yaml:"encoding"`
+	Level    int                       `json:"level,omitempty" yaml:"level"`       // Level is encoding specific, zero selects the default
+	MinBytes int                       `json:"minBytes,omitempty" yaml:"minBytes"` // MinBytes is the content size below which compression is skipped
+}
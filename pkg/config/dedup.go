@@ -0,0 +1,40 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// DedupInfo configures the suppression of identical annotations, those sharing Key, Kind, Layer and IsSatisfied,
+// published within Window of each other. De-duplication is enabled by setting Window.
+type DedupInfo struct {
+	Window int `json:"window,omitempty" yaml:"window"` // Window is the number of milliseconds an annotation suppresses its duplicates
+}
+
+// Enabled reports whether annotations should be de-duplicated
+func (d DedupInfo) Enabled() bool {
+	return d.Window > 0
+}
+
+// validate checks the de-duplication settings, allowing the zero value which disables it
+func (d DedupInfo) validate() error {
+	if d.Window < 0 {
+		return fmt.Errorf("%w: invalid dedup window %v", contracts.ErrConfigInvalid, d.Window)
+	}
+	return nil
+}
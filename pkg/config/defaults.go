@@ -0,0 +1,67 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// DefaultKeyDir is the conventional directory holding the ed25519 key pair, as public.key and private.key, used
+// when the configuration does not locate the signature keys
+const DefaultKeyDir = "/etc/alvarium/keys"
+
+const (
+	defaultPublicKey  = "public.key"
+	defaultPrivateKey = "private.key"
+)
+
+// Default returns the configuration applied to each section left out of an unmarshalled configuration: source
+// annotations of the application layer, hashed with SHA-256, signed with the ed25519 key pair in DefaultKeyDir and
+// written to the console. A sufficient configuration may thus be as short as
+//
+//	{
+//	  "annotators": ["src", "tls"],
+//	  "stream": {"type": "mqtt", "config": {...}}
+//	}
+func Default() SdkInfo {
+	return SdkInfo{
+		Annotators: []contracts.AnnotationType{contracts.AnnotationSource},
+		Hash:       HashInfo{Type: contracts.SHA256Hash},
+		Signature:  SignatureInfo{}.withDefaults(),
+		Stream:     StreamInfo{Type: contracts.ConsoleStream, Config: MockStreamConfig{}},
+		Layer:      contracts.Application,
+	}
+}
+
+// withDefaults locates the public and private keys left unconfigured in DefaultKeyDir
+func (s SignatureInfo) withDefaults() SignatureInfo {
+	s.PublicKey = s.PublicKey.withDefault(defaultPublicKey)
+	s.PrivateKey = s.PrivateKey.withDefault(defaultPrivateKey)
+	return s
+}
+
+// withDefault makes an ed25519 key of a key without algorithm, read from the named file in DefaultKeyDir unless it is
+// located otherwise
+func (k KeyInfo) withDefault(name string) KeyInfo {
+	if k.Type == "" {
+		k.Type = contracts.KeyEd25519
+	}
+	if k.Type == contracts.KeyEd25519 && !k.HasKey() && k.Signer == nil {
+		k.Path = filepath.Join(DefaultKeyDir, name)
+	}
+	return k
+}
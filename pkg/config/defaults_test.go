@@ -0,0 +1,85 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefault(t *testing.T) {
+	cfg := Default()
+	assert.Equal(t, []contracts.AnnotationType{contracts.AnnotationSource}, cfg.Annotators)
+	assert.Equal(t, contracts.Application, cfg.Layer)
+	assert.Equal(t, contracts.SHA256Hash, cfg.Hash.Type)
+	assert.Equal(t, contracts.ConsoleStream, cfg.Stream.Type)
+	assert.Equal(t, KeyInfo{Type: contracts.KeyEd25519, Path: filepath.Join(DefaultKeyDir, "public.key")},
+		cfg.Signature.PublicKey)
+	assert.Equal(t, KeyInfo{Type: contracts.KeyEd25519, Path: filepath.Join(DefaultKeyDir, "private.key")},
+		cfg.Signature.PrivateKey)
+}
+
+func TestSdkInfo_Defaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format contracts.ConfigFormat
+	}{
+		{"empty json", `{}`, contracts.ConfigJSON},
+		{"empty yaml", "chain: false\n", contracts.ConfigYAML},
+		{"empty toml", "", contracts.ConfigTOML},
+		{"sections without type", `{"hash":{},"signature":{},"stream":{}}`, contracts.ConfigJSON},
+		{"keys without type", "signature:\n  public: {}\n  private: {}\nstream:\n  config: {}\n", contracts.ConfigYAML},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Parse([]byte(tt.data), tt.format)
+			test.CheckError(err, false, tt.name, t)
+			expected := Default()
+			assert.Equal(t, expected.Annotators, cfg.Annotators)
+			assert.Equal(t, expected.Layer, cfg.Layer)
+			assert.Equal(t, expected.Hash, cfg.Hash)
+			assert.Equal(t, expected.Signature, cfg.Signature)
+			assert.Equal(t, expected.Stream, cfg.Stream)
+		})
+	}
+}
+
+func TestSdkInfo_PartialDefaults(t *testing.T) {
+	data := `{
+  "annotators": ["src", "tls"],
+  "layer": "host",
+  "hash": {"encoding": "multihash"},
+  "signature": {"private": {"path": "/run/secrets/alvarium.key"}}
+}`
+	cfg, err := Parse([]byte(data), contracts.ConfigJSON)
+	test.CheckError(err, false, "partial", t)
+	assert.Equal(t, []contracts.AnnotationType{contracts.AnnotationSource, contracts.AnnotationTLS}, cfg.Annotators)
+	assert.Equal(t, contracts.Host, cfg.Layer)
+	assert.Equal(t, HashInfo{Type: contracts.SHA256Hash, Encoding: contracts.MultihashEncoding}, cfg.Hash)
+	assert.Equal(t, KeyInfo{Type: contracts.KeyEd25519, Path: "/run/secrets/alvarium.key"}, cfg.Signature.PrivateKey)
+	assert.Equal(t, filepath.Join(DefaultKeyDir, "public.key"), cfg.Signature.PublicKey.Path)
+	assert.Equal(t, contracts.ConsoleStream, cfg.Stream.Type)
+
+	// keys held outside of files are left alone
+	cfg, err = Parse([]byte(`{"signature":{"public":{"url":"https://keys.example.com/jwks.json"}}}`),
+		contracts.ConfigJSON)
+	test.CheckError(err, false, "url", t)
+	assert.Empty(t, cfg.Signature.PublicKey.Path)
+}
@@ -0,0 +1,27 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+// EncryptionInfo configures envelope encryption of publish wrapper content so that only the listed recipients can
+// read it. Encryption is enabled by listing at least one recipient.
+type EncryptionInfo struct {
+	Recipients []RecipientInfo `json:"recipients,omitempty" yaml:"recipients"`
+}
+
+// RecipientInfo identifies a party able to decrypt published content
+type RecipientInfo struct {
+	Id            string `json:"id,omitempty" yaml:"id"`                       // Id lets a recipient find its key slot, defaults to a key fingerprint
+	PublicKeyPath string `json:"publicKeyPath,omitempty" yaml:"publicKeyPath"` // PublicKeyPath is a hex encoded X25519 public key
+}
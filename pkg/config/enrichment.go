@@ -0,0 +1,82 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+// EnrichmentInfo configures the attributes of the host attached to the published annotations, letting consumers group
+// them by fleet attributes. Nothing is attached unless Fields is set.
+type EnrichmentInfo struct {
+	Fields []contracts.EnrichmentField `json:"fields,omitempty" yaml:"fields"` // Fields lists the attributes attached
+	Target contracts.EnrichmentTarget  `json:"target,omitempty" yaml:"target"` // Target is where they are attached, each annotation by default
+	// Cloud is the instance metadata service queried for the region and instance, one of the cloud tag sources
+	Cloud contracts.TagSourceType `json:"cloud,omitempty" yaml:"cloud"`
+}
+
+// Enabled reports whether any attribute of the host is attached
+func (e EnrichmentInfo) Enabled() bool {
+	return len(e.Fields) > 0
+}
+
+// Includes reports whether the given attribute is attached
+func (e EnrichmentInfo) Includes(field contracts.EnrichmentField) bool {
+	for _, f := range e.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// AttachTo returns where the attributes are attached
+func (e EnrichmentInfo) AttachTo() contracts.EnrichmentTarget {
+	if e.Target == "" {
+		return contracts.EnrichAnnotation
+	}
+	return e.Target
+}
+
+func (e EnrichmentInfo) validate() error {
+	for _, f := range e.Fields {
+		if !f.Validate() {
+			return fmt.Errorf("%w: invalid enrichment field %s", contracts.ErrConfigInvalid, f)
+		}
+	}
+	if e.Target != "" && !e.Target.Validate() {
+		return fmt.Errorf("%w: invalid enrichment target %s", contracts.ErrConfigInvalid, e.Target)
+	}
+	if e.Cloud != "" && !e.Cloud.IsCloud() {
+		return fmt.Errorf("%w: invalid enrichment cloud %s", contracts.ErrConfigInvalid, e.Cloud)
+	}
+	if e.Cloud == "" && (e.Includes(contracts.EnrichRegion) || e.Includes(contracts.EnrichInstance)) {
+		return fmt.Errorf("%w: the cloud is required to attach the region or instance", contracts.ErrConfigInvalid)
+	}
+	return nil
+}
+
+// validateEnrichment checks that the wrappers published can carry the attributes attached to them
+func validateEnrichment(e EnrichmentInfo, stream StreamInfo) error {
+	if e.Enabled() && e.AttachTo() == contracts.EnrichWrapper &&
+		stream.SchemaVersion != 0 && stream.SchemaVersion < message.WrapperVersion3 {
+		return fmt.Errorf("%w: attaching host info to the wrapper requires schema version %d",
+			contracts.ErrConfigInvalid, message.WrapperVersion3)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// ExpandEnv replaces the references to environment variables in a configuration, so one configuration can be
+// promoted across environments. A reference is written ${NAME}, ${NAME:-default} to use default when the variable is
+// unset or empty, or ${NAME-default} to use it only when the variable is unset. The values are substituted verbatim,
+// before the configuration is parsed, and $${ stands for a literal ${. Referencing a variable that is unset, without
+// a default, is an error. Load and Parse expand the configuration they read, ExpandEnv serves configurations embedded
+// in the file of an application.
+func ExpandEnv(data []byte) ([]byte, error) {
+	return expand(data, os.LookupEnv)
+}
+
+func expand(data []byte, lookup func(string) (string, bool)) ([]byte, error) {
+	if !bytes.Contains(data, []byte("${")) {
+		return data, nil
+	}
+	var out bytes.Buffer
+	for {
+		i := bytes.Index(data, []byte("${"))
+		if i < 0 {
+			out.Write(data)
+			return out.Bytes(), nil
+		}
+		if i > 0 && data[i-1] == '$' {
+			// $${ escapes the reference
+			out.Write(data[:i-1])
+			out.WriteString("${")
+			data = data[i+2:]
+			continue
+		}
+		out.Write(data[:i])
+		end := bytes.IndexByte(data[i:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unterminated environment variable reference", contracts.ErrConfigInvalid)
+		}
+		value, err := resolve(string(data[i+2:i+end]), lookup)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString(value)
+		data = data[i+end+1:]
+	}
+}
+
+// resolve returns the value of the reference ref, the text between ${ and }
+func resolve(ref string, lookup func(string) (string, bool)) (string, error) {
+	name, fallback, hasDefault, emptyIsUnset := ref, "", false, false
+	for i := 0; i < len(ref); i++ {
+		if ref[i] != '-' {
+			continue
+		}
+		name, fallback, hasDefault = ref[:i], ref[i+1:], true
+		if i > 0 && ref[i-1] == ':' {
+			name, emptyIsUnset = ref[:i-1], true
+		}
+		break
+	}
+	if !isEnvName(name) {
+		return "", fmt.Errorf("%w: invalid environment variable reference ${%s}", contracts.ErrConfigInvalid, ref)
+	}
+	value, ok := lookup(name)
+	if ok && (value != "" || !emptyIsUnset) {
+		return value, nil
+	}
+	if !hasDefault {
+		return "", fmt.Errorf("%w: environment variable %s is not set", contracts.ErrConfigInvalid, name)
+	}
+	return fallback, nil
+}
+
+// isEnvName reports whether name is a valid name of an environment variable
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		letter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if !letter && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
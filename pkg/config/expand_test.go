@@ -0,0 +1,76 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	env := map[string]string{"BROKER": "mqtt.prod", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		name        string
+		data        string
+		expected    string
+		expectError bool
+	}{
+		{"no reference", `{"host":"localhost"}`, `{"host":"localhost"}`, false},
+		{"set", `{"host":"${BROKER}"}`, `{"host":"mqtt.prod"}`, false},
+		{"default unused", `{"host":"${BROKER:-localhost}"}`, `{"host":"mqtt.prod"}`, false},
+		{"default when unset", `{"port":${PORT:-1883}}`, `{"port":1883}`, false},
+		{"default when empty", `{"host":"${EMPTY:-localhost}"}`, `{"host":"localhost"}`, false},
+		{"empty kept", `{"host":"${EMPTY-localhost}"}`, `{"host":""}`, false},
+		{"default with separators", `{"url":"${URL:-tcp://a-b:1883}"}`, `{"url":"tcp://a-b:1883"}`, false},
+		{"escaped", `{"password":"$${BROKER}"}`, `{"password":"${BROKER}"}`, false},
+		{"several", `${BROKER}:${PORT-1883}`, `mqtt.prod:1883`, false},
+		{"unset", `{"host":"${MISSING}"}`, "", true},
+		{"invalid name", `{"host":"${1HOST}"}`, "", true},
+		{"unterminated", `{"host":"${BROKER"}`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := expand([]byte(tt.data), lookup)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				assert.True(t, errors.Is(err, contracts.ErrConfigInvalid))
+				return
+			}
+			assert.Equal(t, tt.expected, string(b))
+		})
+	}
+}
+
+func TestParse_Env(t *testing.T) {
+	t.Setenv("ALVARIUM_TEST_ID_TYPE", "uuidv7")
+
+	cfg, err := Parse([]byte("idType = \"${ALVARIUM_TEST_ID_TYPE}\"\nchain = ${ALVARIUM_TEST_CHAIN:-true}\n"),
+		contracts.ConfigTOML)
+	test.CheckError(err, false, "toml", t)
+	assert.Equal(t, contracts.UUIDv7Id, cfg.IdType)
+	assert.True(t, cfg.Chain)
+
+	_, err = Parse([]byte(`{"idType":"${ALVARIUM_TEST_UNSET}"}`), contracts.ConfigJSON)
+	test.CheckError(err, true, "unset", t)
+}
@@ -23,11 +23,68 @@ import (
 
 type HashInfo struct {
 	Type contracts.HashType `json:"type,omitempty" yaml:"type"`
+	// KeyPath is the filesystem path to the hex encoded secret key used by keyed hash types
+	KeyPath string `json:"keyPath,omitempty" yaml:"keyPath"`
+	// KeyEnv names an environment variable holding the hex encoded secret key, taking precedence over KeyPath. This
+	// allows the key to be injected by a secret store rather than written to disk.
+	KeyEnv string `json:"keyEnv,omitempty" yaml:"keyEnv"`
+	// Encoding determines how derived hashes are represented in annotation Keys, defaulting to hex
+	Encoding contracts.HashEncoding `json:"encoding,omitempty" yaml:"encoding"`
+	// Salt is mixed into the data before hashing so that annotation Keys of small, enumerable payloads cannot be
+	// reversed by hashing every candidate value
+	Salt *SaltInfo `json:"salt,omitempty" yaml:"salt"`
+	// Tree tunes the chunking and parallelism of tree hash types
+	Tree *TreeHashInfo `json:"tree,omitempty" yaml:"tree"`
+	// Canonicalization normalizes payloads before hashing so that semantically identical documents produced by
+	// different implementations are given the same annotation Key
+	Canonicalization contracts.Canonicalization `json:"canonicalization,omitempty" yaml:"canonicalization"`
+}
+
+// TreeHashInfo tunes how tree hash types divide a payload into chunks whose hashes are derived concurrently
+type TreeHashInfo struct {
+	ChunkSize int `json:"chunkSize,omitempty" yaml:"chunkSize"` // ChunkSize is the number of bytes per leaf, defaulting to 1 MiB
+	Workers   int `json:"workers,omitempty" yaml:"workers"`     // Workers bounds the goroutines hashing chunks, defaulting to the number of CPUs
+	Threshold int `json:"threshold,omitempty" yaml:"threshold"` // Threshold is the payload size in bytes below which chunks are hashed sequentially
+}
+
+// SaltInfo provides the salts mixed into data hashing. A tenant's salt is selected through the contracts.TenantKey
+// Context value, falling back to the default salt for unknown tenants or when no tenant is given.
+type SaltInfo struct {
+	Value   string            `json:"value,omitempty" yaml:"value"`     // Value is the hex encoded default salt
+	Env     string            `json:"env,omitempty" yaml:"env"`         // Env names an environment variable holding the default salt, taking precedence over Value
+	Tenants map[string]string `json:"tenants,omitempty" yaml:"tenants"` // Tenants maps tenant identifiers to hex encoded salts
+}
+
+// validate checks the hash type and the settings that depend on it
+func (h HashInfo) validate() error {
+	if !h.Type.Validate() {
+		return fmt.Errorf("%w: invalid HashType value provided %s", contracts.ErrConfigInvalid, h.Type)
+	}
+	if h.Type.Keyed() && h.KeyPath == "" && h.KeyEnv == "" {
+		return fmt.Errorf("%w: HashType %s requires a keyPath or keyEnv", contracts.ErrConfigInvalid, h.Type)
+	}
+	if h.Encoding != "" && !h.Encoding.Validate() {
+		return fmt.Errorf("%w: invalid HashEncoding value provided %s", contracts.ErrConfigInvalid, h.Encoding)
+	}
+	if h.Salt != nil && h.Type == contracts.NoHash {
+		return fmt.Errorf("%w: HashType %s cannot be salted", contracts.ErrConfigInvalid, h.Type)
+	}
+	if h.Canonicalization != "" && !h.Canonicalization.Validate() {
+		return fmt.Errorf("%w: invalid Canonicalization value provided %s",
+			contracts.ErrConfigInvalid, h.Canonicalization)
+	}
+	return nil
 }
 
 func (h *HashInfo) UnmarshalJSON(data []byte) (err error) {
 	type Alias struct {
-		Type contracts.HashType
+		Type             contracts.HashType
+		KeyPath          string
+		KeyEnv           string
+		Encoding         contracts.HashEncoding
+		Salt             *SaltInfo
+		Tree             *TreeHashInfo
+		Canonicalization contracts.Canonicalization
 	}
 
 	a := Alias{}
@@ -35,16 +92,27 @@ func (h *HashInfo) UnmarshalJSON(data []byte) (err error) {
 		return err
 	}
 
-	if !a.Type.Validate() {
-		return fmt.Errorf("invalid HashType value provided %s", a.Type)
+	if a.Type == "" {
+		a.Type = contracts.SHA256Hash
 	}
-	h.Type = a.Type
+	x := HashInfo{Type: a.Type, KeyPath: a.KeyPath, KeyEnv: a.KeyEnv, Encoding: a.Encoding, Salt: a.Salt, Tree: a.Tree,
+		Canonicalization: a.Canonicalization}
+	if err = x.validate(); err != nil {
+		return err
+	}
+	*h = x
 	return nil
 }
 
 func (h *HashInfo) UnmarshalYAML(data *yaml.Node) (err error) {
 	type Alias struct {
-		Type contracts.HashType `yaml:"type"`
+		Type             contracts.HashType         `yaml:"type"`
+		KeyPath          string                     `yaml:"keyPath"`
+		KeyEnv           string                     `yaml:"keyEnv"`
+		Encoding         contracts.HashEncoding     `yaml:"encoding"`
+		Salt             *SaltInfo                  `yaml:"salt"`
+		Tree             *TreeHashInfo              `yaml:"tree"`
+		Canonicalization contracts.Canonicalization `yaml:"canonicalization"`
 	}
 
 	a := Alias{}
@@ -52,9 +120,14 @@ func (h *HashInfo) UnmarshalYAML(data *yaml.Node) (err error) {
 		return err
 	}
 
-	if !a.Type.Validate() {
-		return fmt.Errorf("invalid HashType value provided %s", a.Type)
+	if a.Type == "" {
+		a.Type = contracts.SHA256Hash
+	}
+	x := HashInfo{Type: a.Type, KeyPath: a.KeyPath, KeyEnv: a.KeyEnv, Encoding: a.Encoding, Salt: a.Salt, Tree: a.Tree,
+		Canonicalization: a.Canonicalization}
+	if err = x.validate(); err != nil {
+		return err
 	}
-	h.Type = a.Type
+	*h = x
 	return nil
 }
@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"reflect"
 	"testing"
 )
 
@@ -30,6 +31,9 @@ func TestHashTypeValues(t *testing.T) {
 		{"valid2", contracts.SHA256Hash, true},
 		{"valid3", contracts.NoHash, true},
 		{"valid4", "invalid", false},
+		{"valid5", contracts.HMACHash, true},
+		{"valid6", contracts.MerkleHash, true},
+		{"valid7", contracts.TreeHash, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -45,6 +49,16 @@ func TestHashTypeUnmarshal(t *testing.T) {
 	validSHA256 := HashInfo{Type: contracts.SHA256Hash}
 	validNone := HashInfo{Type: contracts.NoHash}
 	invalid := HashInfo{Type: "invalid"}
+	validHMAC := HashInfo{Type: contracts.HMACHash, KeyEnv: "ALVARIUM_HASH_KEY"}
+	missingKey := HashInfo{Type: contracts.HMACHash}
+	multihash := HashInfo{Type: contracts.SHA256Hash, Encoding: contracts.MultihashEncoding}
+	invalidEncoding := HashInfo{Type: contracts.SHA256Hash, Encoding: "base64"}
+	salted := HashInfo{Type: contracts.SHA256Hash, Salt: &SaltInfo{Value: "0102",
+		Tenants: map[string]string{"tenant-a": "0a0b"}}}
+	tree := HashInfo{Type: contracts.TreeHash, Tree: &TreeHashInfo{ChunkSize: 4096, Workers: 4, Threshold: 1 << 20}}
+	canonical := HashInfo{Type: contracts.SHA256Hash, Canonicalization: contracts.JCSCanonicalization}
+	invalidCanonicalization := HashInfo{Type: contracts.SHA256Hash, Canonicalization: "c14n"}
+	saltedNone := HashInfo{Type: contracts.NoHash, Salt: &SaltInfo{Value: "0102"}}
 
 	tests := []struct {
 		name        string
@@ -55,6 +69,15 @@ func TestHashTypeUnmarshal(t *testing.T) {
 		{"validSHA256", validSHA256, false},
 		{"validNone", validNone, false},
 		{"invalid hash type", invalid, true},
+		{"validHMAC", validHMAC, false},
+		{"keyed hash without key", missingKey, true},
+		{"multihash encoding", multihash, false},
+		{"invalid encoding", invalidEncoding, true},
+		{"salted", salted, false},
+		{"salted without hash", saltedNone, true},
+		{"tree", tree, false},
+		{"canonical", canonical, false},
+		{"invalid canonicalization", invalidCanonicalization, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -63,8 +86,8 @@ func TestHashTypeUnmarshal(t *testing.T) {
 			err := json.Unmarshal(b, &x)
 			test.CheckError(err, tt.expectError, tt.name, t)
 			if err == nil {
-				if x.Type != tt.info.Type {
-					t.Errorf("HashInfo type mismatch expected %s received %s", tt.info.Type, x.Type)
+				if !reflect.DeepEqual(x, tt.info) {
+					t.Errorf("HashInfo mismatch expected %v received %v", tt.info, x)
 				}
 			}
 		})
@@ -0,0 +1,20 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package config
+
+// JournalInfo configures the local journal of the annotations made by the SDK, see journal.Open
+type JournalInfo struct {
+	Path      string `json:"path,omitempty" yaml:"path"`           // Path is the location of the journal database file
+	Retention int    `json:"retention,omitempty" yaml:"retention"` // Retention is the number of milliseconds annotations are kept, forever if zero
+}
@@ -0,0 +1,158 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/secrets"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads the SDK configuration from the file at path, in the format given by its extension, see FormatOf
+func Load(path string) (SdkInfo, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return SdkInfo{}, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return SdkInfo{}, err
+	}
+	return Parse(b, format)
+}
+
+// FormatOf returns the format of the configuration file at path from its extension: .json, .yaml or .yml, or .toml
+func FormatOf(path string) (contracts.ConfigFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return contracts.ConfigJSON, nil
+	case ".yaml", ".yml":
+		return contracts.ConfigYAML, nil
+	case ".toml":
+		return contracts.ConfigTOML, nil
+	}
+	return "", fmt.Errorf("%w: unknown configuration format of %s", contracts.ErrConfigInvalid, path)
+}
+
+// Parse reads the SDK configuration from data serialized in the given format, once the references to environment
+// variables it holds are expanded, see ExpandEnv, the overrides of the profile selected by ProfileEnv are merged
+// over the base settings, and the settings given as secret references such as env:MQTT_PASSWORD,
+// file:/run/secrets/hedera.key or vault:secret/data/alvarium#key are resolved. Whatever the format, the settings are
+// named as in JSON and validated alike.
+//
+// Profiles adapt one configuration to several environments, e.g.
+//
+//	{
+//	  "stream": {"type": "mqtt", "config": {...}},
+//	  "profiles": {
+//	    "dev": {"stream": {"type": "console"}},
+//	    "prod": {"stream": {"config": {"provider": {"host": "mqtt.prod"}}}}
+//	  }
+//	}
+func Parse(data []byte, format contracts.ConfigFormat) (SdkInfo, error) {
+	if !format.Validate() {
+		return SdkInfo{}, fmt.Errorf("%w: invalid configuration format %s", contracts.ErrConfigInvalid, format)
+	}
+	data, err := ExpandEnv(data)
+	if err != nil {
+		return SdkInfo{}, err
+	}
+
+	var tree any
+	switch format {
+	case contracts.ConfigJSON:
+		err = json.Unmarshal(data, &tree)
+	case contracts.ConfigYAML:
+		err = yaml.Unmarshal(data, &tree)
+	case contracts.ConfigTOML:
+		var table map[string]any
+		err = toml.Unmarshal(data, &table)
+		tree = table
+	}
+	if err != nil {
+		return SdkInfo{}, err
+	}
+	tree, err = applyProfile(tree, profileName())
+	if err != nil {
+		return SdkInfo{}, err
+	}
+	tree, err = resolveSecrets(context.Background(), tree)
+	if err != nil {
+		return SdkInfo{}, err
+	}
+
+	b, err := json.Marshal(tree)
+	if err != nil {
+		return SdkInfo{}, err
+	}
+	var s SdkInfo
+	if err = json.Unmarshal(b, &s); err != nil {
+		return SdkInfo{}, err
+	}
+	return s, nil
+}
+
+// resolveSecrets replaces the secret references among the values of a decoded configuration with the secrets they
+// refer to, reporting every reference that cannot be resolved
+func resolveSecrets(ctx context.Context, value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if secrets.IsReference(v) {
+			return secrets.Resolve(ctx, v)
+		}
+	case map[string]any:
+		var errs []error
+		for key, item := range v {
+			resolved, err := resolveSecrets(ctx, item)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				continue
+			}
+			v[key] = resolved
+		}
+		return v, errors.Join(errs...)
+	case []any:
+		var errs []error
+		for i, item := range v {
+			resolved, err := resolveSecrets(ctx, item)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("[%d]: %w", i, err))
+				continue
+			}
+			v[i] = resolved
+		}
+		return v, errors.Join(errs...)
+	}
+	return value, nil
+}
+
+// UnmarshalTOML decodes the SDK configuration from a TOML table, as when it is embedded in the configuration of an
+// application. The table is re-encoded as JSON so the settings are validated as by UnmarshalJSON.
+func (s *SdkInfo) UnmarshalTOML(data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.UnmarshalJSON(b)
+}
@@ -0,0 +1,134 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoad(t *testing.T) {
+	expected, err := Load("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		expectError bool
+	}{
+		{"yaml", "../../test/res/config.yaml", false},
+		{"toml", "../../test/res/config.toml", false},
+		{"unknown extension", "../../test/res/config.ini", true},
+		{"missing file", filepath.Join(t.TempDir(), "config.toml"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(tt.path)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, expected.Annotators, cfg.Annotators)
+				assert.Equal(t, expected.Layer, cfg.Layer)
+				assert.Equal(t, expected.Hash, cfg.Hash)
+				assert.Equal(t, expected.Signature, cfg.Signature)
+				assert.Equal(t, expected.Stream, cfg.Stream)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		format      contracts.ConfigFormat
+		expectError bool
+	}{
+		{"json", `{"chain":true,"idType":"uuidv7"}`, contracts.ConfigJSON, false},
+		{"yaml", "chain: true\nidType: uuidv7\n", contracts.ConfigYAML, false},
+		{"toml", "chain = true\nidType = \"uuidv7\"\n", contracts.ConfigTOML, false},
+		{"invalid toml setting", "idType = \"serial\"\n", contracts.ConfigTOML, true},
+		{"malformed toml", "chain = \n", contracts.ConfigTOML, true},
+		{"unknown format", `{"chain":true}`, "ini", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Parse([]byte(tt.data), tt.format)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.True(t, cfg.Chain)
+				assert.Equal(t, contracts.UUIDv7Id, cfg.IdType)
+			}
+		})
+	}
+}
+
+func TestSdkInfo_Embedded(t *testing.T) {
+	type service struct {
+		Name string  `yaml:"name" toml:"name"`
+		Sdk  SdkInfo `yaml:"alvarium" toml:"alvarium"`
+	}
+
+	var fromYaml service
+	err := yaml.Unmarshal([]byte("name: ingest\nalvarium:\n  layer: host\n  chain: true\n"), &fromYaml)
+	test.CheckError(err, false, "yaml", t)
+	assert.Equal(t, contracts.Host, fromYaml.Sdk.Layer)
+	assert.True(t, fromYaml.Sdk.Chain)
+
+	var fromToml service
+	_, err = toml.Decode("name = \"ingest\"\n\n[alvarium]\nlayer = \"host\"\nchain = true\n", &fromToml)
+	test.CheckError(err, false, "toml", t)
+	assert.Equal(t, "ingest", fromToml.Name)
+	assert.True(t, fromToml.Sdk.Chain)
+
+	_, err = toml.Decode("[alvarium]\nidType = \"serial\"\n", &fromToml)
+	test.CheckError(err, true, "invalid toml", t)
+}
+
+func TestParse_Secrets(t *testing.T) {
+	t.Setenv("ALVARIUM_TEST_MQTT_PASSWORD", "s3cret")
+	data := `stream:
+  type: mqtt
+  config:
+    clientId: sdk-test
+    user: mosquitto
+    password: env:ALVARIUM_TEST_MQTT_PASSWORD
+    provider: {host: localhost, protocol: tcp, port: 1883}
+    topics: [sdk-test-topic]
+signature:
+  public:
+    type: ed25519
+    path: file:///etc/alvarium/public.key
+`
+	cfg, err := Parse([]byte(data), contracts.ConfigYAML)
+	test.CheckError(err, false, "resolved", t)
+	mqtt, ok := cfg.Stream.Config.(MqttConfig)
+	if !ok {
+		t.Fatalf("unexpected stream config %T", cfg.Stream.Config)
+	}
+	assert.Equal(t, "s3cret", mqtt.Password)
+	assert.Equal(t, "file:///etc/alvarium/public.key", cfg.Signature.PublicKey.Path)
+
+	_, err = Parse([]byte(`{"stream":{"type":"mqtt","config":{"password":"env:ALVARIUM_TEST_UNSET"}}}`),
+		contracts.ConfigJSON)
+	test.CheckError(err, true, "unresolved", t)
+}
@@ -0,0 +1,75 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package config
+
+import (
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// PipelineInfo configures how the SDK reacts to annotators that fail or make unsatisfied annotations. Annotators are
+// required unless listed as Optional. By default, a failing required annotator aborts the operation and unsatisfied
+// annotations are published. Annotators run one after the other unless Parallelism is set.
+type PipelineInfo struct {
+	OnError       contracts.PipelinePolicy   `json:"onError,omitempty" yaml:"onError"`             // OnError applies when a required annotator fails
+	OnUnsatisfied contracts.PipelinePolicy   `json:"onUnsatisfied,omitempty" yaml:"onUnsatisfied"` // OnUnsatisfied applies when a required annotation is not satisfied
+	Optional      []contracts.AnnotationType `json:"optional,omitempty" yaml:"optional"`           // Optional annotators are left out when they fail
+	Parallelism   int                        `json:"parallelism,omitempty" yaml:"parallelism"`     // Parallelism is the maximum number of annotators run at once per call
+	Timeout       int                        `json:"timeout,omitempty" yaml:"timeout"`             // Timeout is the number of milliseconds an annotator may run, unlimited if zero
+}
+
+// Parallel reports whether the annotators of a call are run concurrently
+func (p PipelineInfo) Parallel() bool {
+	return p.Parallelism > 1
+}
+
+// ErrorPolicy returns the policy applied when a required annotator fails
+func (p PipelineInfo) ErrorPolicy() contracts.PipelinePolicy {
+	if p.OnError == "" {
+		return contracts.PolicyAbort
+	}
+	return p.OnError
+}
+
+// UnsatisfiedPolicy returns the policy applied when a required annotation is not satisfied
+func (p PipelineInfo) UnsatisfiedPolicy() contracts.PipelinePolicy {
+	if p.OnUnsatisfied == "" {
+		return contracts.PolicyContinue
+	}
+	return p.OnUnsatisfied
+}
+
+// validate checks the pipeline policies, allowing the zero value which keeps the default behavior
+func (p PipelineInfo) validate() error {
+	if p.OnError != "" && !p.OnError.Validate() {
+		return fmt.Errorf("%w: invalid pipeline error policy %s", contracts.ErrConfigInvalid, p.OnError)
+	}
+	// an unsatisfied annotation already records the outcome, there is nothing further to annotate
+	if p.OnUnsatisfied != "" && (!p.OnUnsatisfied.Validate() || p.OnUnsatisfied == contracts.PolicyAnnotate) {
+		return fmt.Errorf("%w: invalid pipeline unsatisfied policy %s", contracts.ErrConfigInvalid, p.OnUnsatisfied)
+	}
+	if p.Parallelism < 0 {
+		return fmt.Errorf("%w: invalid pipeline parallelism %v", contracts.ErrConfigInvalid, p.Parallelism)
+	}
+	if p.Timeout < 0 {
+		return fmt.Errorf("%w: invalid pipeline timeout %v", contracts.ErrConfigInvalid, p.Timeout)
+	}
+	for _, kind := range p.Optional {
+		if !kind.Validate() {
+			return fmt.Errorf("%w: invalid optional AnnotationType received %s", contracts.ErrConfigInvalid, kind)
+		}
+	}
+	return nil
+}
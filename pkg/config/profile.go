@@ -0,0 +1,78 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// ProfileEnv names the environment variable selecting the profile of the configuration applied by Load and Parse
+const ProfileEnv = "ALVARIUM_PROFILE"
+
+// profilesKey is the setting holding the profiles of a configuration
+const profilesKey = "profiles"
+
+// applyProfile merges the overrides of the named profile, found under the profiles setting of a decoded
+// configuration, over the rest of it. Settings nested in objects are merged one by one, any other setting of the
+// profile replaces that of the base. Without a name, the profiles are only dropped.
+func applyProfile(tree any, name string) (any, error) {
+	base, ok := tree.(map[string]any)
+	if !ok {
+		if name != "" {
+			return nil, fmt.Errorf("%w: profile %s selected but the configuration has no profiles",
+				contracts.ErrConfigInvalid, name)
+		}
+		return tree, nil
+	}
+	profiles, _ := base[profilesKey].(map[string]any)
+	if base[profilesKey] != nil && profiles == nil {
+		return nil, fmt.Errorf("%w: profiles must be an object of named profiles", contracts.ErrConfigInvalid)
+	}
+	delete(base, profilesKey)
+	if name == "" {
+		return base, nil
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown profile %s", contracts.ErrConfigInvalid, name)
+	}
+	overrides, ok := profile.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: profile %s must be an object of settings", contracts.ErrConfigInvalid, name)
+	}
+	return merge(base, overrides), nil
+}
+
+// merge returns base with the settings of overrides merged over it
+func merge(base, overrides map[string]any) map[string]any {
+	for key, value := range overrides {
+		nested, ok := value.(map[string]any)
+		if current, isMap := base[key].(map[string]any); ok && isMap {
+			base[key] = merge(current, nested)
+			continue
+		}
+		base[key] = value
+	}
+	return base
+}
+
+// profileName returns the profile selected by the environment
+func profileName() string {
+	return os.Getenv(ProfileEnv)
+}
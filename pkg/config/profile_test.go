@@ -0,0 +1,110 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const profiled = `
+layer: app
+stream:
+  type: mqtt
+  config:
+    clientId: sdk
+    provider: {host: localhost, protocol: tcp, port: 1883}
+    topics: [annotations]
+profiles:
+  dev:
+    stream: {type: console}
+  prod:
+    layer: host
+    stream:
+      config:
+        password: env:ALVARIUM_TEST_PROD_PASSWORD
+        provider: {host: mqtt.prod}
+`
+
+func TestParse_Profiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		profile     string
+		layer       contracts.LayerType
+		stream      contracts.StreamType
+		host        string
+		expectError bool
+	}{
+		{"base", "", contracts.Application, contracts.MqttStream, "localhost", false},
+		{"dev", "dev", contracts.Application, contracts.ConsoleStream, "", false},
+		{"prod", "prod", contracts.Host, contracts.MqttStream, "mqtt.prod", false},
+		{"unknown", "staging", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ProfileEnv, tt.profile)
+			t.Setenv("ALVARIUM_TEST_PROD_PASSWORD", "s3cret")
+			cfg, err := Parse([]byte(profiled), contracts.ConfigYAML)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				assert.True(t, errors.Is(err, contracts.ErrConfigInvalid))
+				return
+			}
+			assert.Equal(t, tt.layer, cfg.Layer)
+			assert.Equal(t, tt.stream, cfg.Stream.Type)
+			if mqtt, ok := cfg.Stream.Config.(MqttConfig); ok {
+				assert.Equal(t, tt.host, mqtt.Provider.Host)
+				// merged settings keep the base settings they do not override
+				assert.Equal(t, 1883, mqtt.Provider.Port)
+				assert.Equal(t, []string{"annotations"}, mqtt.Topics)
+			}
+		})
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		tree        any
+		profile     string
+		expected    any
+		expectError bool
+	}{
+		{"no profiles", map[string]any{"chain": true}, "", map[string]any{"chain": true}, false},
+		{"profiles dropped", map[string]any{"chain": true, "profiles": map[string]any{}}, "",
+			map[string]any{"chain": true}, false},
+		{"override", map[string]any{"chain": true, "profiles": map[string]any{"a": map[string]any{"chain": false}}}, "a",
+			map[string]any{"chain": false}, false},
+		{"list replaced", map[string]any{"annotators": []any{"src", "tls"},
+			"profiles": map[string]any{"a": map[string]any{"annotators": []any{"tpm"}}}}, "a",
+			map[string]any{"annotators": []any{"tpm"}}, false},
+		{"selected without profiles", map[string]any{"chain": true}, "a", nil, true},
+		{"malformed profiles", map[string]any{"profiles": []any{"a"}}, "", nil, true},
+		{"malformed profile", map[string]any{"profiles": map[string]any{"a": "prod"}}, "a", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := applyProfile(tt.tree, tt.profile)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.expected, tree)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import "github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+
+// RateLimitInfo configures a token bucket limiting how quickly messages reach the stream provider. Rate limiting is
+// enabled by supplying a Rate.
+type RateLimitInfo struct {
+	Rate   float64                   `json:"rate,omitempty" yaml:"rate"`     // Rate is the sustained number of messages per second
+	Burst  int                       `json:"burst,omitempty" yaml:"burst"`   // Burst is the number of messages allowed at once, defaults to 1
+	Policy contracts.RateLimitPolicy `json:"policy,omitempty" yaml:"policy"` // Policy applies once the bucket is empty, defaults to block
+}
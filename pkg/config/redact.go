@@ -0,0 +1,277 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// RedactedSecret replaces the secrets of a configuration, such as passwords, tokens and keys, redacted for display
+const RedactedSecret = "[REDACTED]"
+
+// redact masks a secret value, an empty value is kept to show that it is not set
+func redact(secret string) string {
+	if secret == "" {
+		return secret
+	}
+	return RedactedSecret
+}
+
+// redactedJSON is a redacted configuration encoded as JSON, logged as an object by JSON handlers and as text by the
+// others
+type redactedJSON []byte
+
+func (r redactedJSON) MarshalJSON() ([]byte, error) {
+	return r, nil
+}
+
+func (r redactedJSON) MarshalText() ([]byte, error) {
+	return r, nil
+}
+
+// display encodes a redacted configuration as JSON
+func display(redacted any) redactedJSON {
+	b, err := json.Marshal(redacted)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("unprintable configuration: %s", err))
+	}
+	return b
+}
+
+// Redacted returns a copy of the configuration with its secrets masked, safe to be logged
+func (s SdkInfo) Redacted() SdkInfo {
+	s.Annotators = append(s.Annotators[:0:0], s.Annotators...)
+	s.Hash = s.Hash.Redacted()
+	s.Signature = s.Signature.Redacted()
+	s.Stream = s.Stream.Redacted()
+	return s
+}
+
+// String returns the configuration as JSON with its secrets masked
+func (s SdkInfo) String() string {
+	return string(display(s.Redacted()))
+}
+
+// LogValue logs the configuration with its secrets masked
+func (s SdkInfo) LogValue() slog.Value {
+	return slog.AnyValue(display(s.Redacted()))
+}
+
+// Redacted returns a copy of the hash settings with the salts masked
+func (h HashInfo) Redacted() HashInfo {
+	if h.Salt != nil {
+		salt := SaltInfo{Value: redact(h.Salt.Value), Env: h.Salt.Env}
+		if h.Salt.Tenants != nil {
+			salt.Tenants = make(map[string]string, len(h.Salt.Tenants))
+			for tenant, value := range h.Salt.Tenants {
+				salt.Tenants[tenant] = redact(value)
+			}
+		}
+		h.Salt = &salt
+	}
+	return h
+}
+
+// String returns the hash settings as JSON with the salts masked
+func (h HashInfo) String() string {
+	return string(display(h.Redacted()))
+}
+
+// LogValue logs the hash settings with the salts masked
+func (h HashInfo) LogValue() slog.Value {
+	return slog.AnyValue(display(h.Redacted()))
+}
+
+// Redacted returns a copy of the signature settings with the secrets of every key masked
+func (s SignatureInfo) Redacted() SignatureInfo {
+	s.PublicKey = s.PublicKey.Redacted()
+	s.PrivateKey = s.PrivateKey.Redacted()
+	s.PrivateKeys = redactKeys(s.PrivateKeys)
+	s.PublicKeys = redactKeys(s.PublicKeys)
+	return s
+}
+
+// String returns the signature settings as JSON with the secrets of every key masked
+func (s SignatureInfo) String() string {
+	return string(display(s.Redacted()))
+}
+
+// LogValue logs the signature settings with the secrets of every key masked
+func (s SignatureInfo) LogValue() slog.Value {
+	return slog.AnyValue(display(s.Redacted()))
+}
+
+func redactKeys(keys []KeyInfo) []KeyInfo {
+	if keys == nil {
+		return nil
+	}
+	redacted := make([]KeyInfo, len(keys))
+	for i, k := range keys {
+		redacted[i] = k.Redacted()
+	}
+	return redacted
+}
+
+// Redacted returns a copy of the key settings with the key material and the credentials of key stores masked
+func (k KeyInfo) Redacted() KeyInfo {
+	if k.Material != nil {
+		k.Material = []byte(RedactedSecret)
+	}
+	if k.Pkcs11 != nil {
+		pkcs11 := *k.Pkcs11
+		pkcs11.Pin = redact(pkcs11.Pin)
+		k.Pkcs11 = &pkcs11
+	}
+	if k.Tpm != nil {
+		tpm := *k.Tpm
+		tpm.Auth = redact(tpm.Auth)
+		k.Tpm = &tpm
+	}
+	if k.VaultTransit != nil {
+		vault := *k.VaultTransit
+		vault.Token = redact(vault.Token)
+		vault.SecretId = redact(vault.SecretId)
+		k.VaultTransit = &vault
+	}
+	return k
+}
+
+// String returns the key settings as JSON with the credentials masked
+func (k KeyInfo) String() string {
+	return string(display(k.Redacted()))
+}
+
+// LogValue logs the key settings with the credentials masked
+func (k KeyInfo) LogValue() slog.Value {
+	return slog.AnyValue(display(k.Redacted()))
+}
+
+// Redacted returns a copy of the stream settings with the credentials of the stream provider masked
+func (s StreamInfo) Redacted() StreamInfo {
+	switch c := s.Config.(type) {
+	case MqttConfig:
+		s.Config = c.Redacted()
+	case HederaConfig:
+		s.Config = c.Redacted()
+	case IotaConfig:
+		s.Config = c.Redacted()
+	case OtelConfig:
+		s.Config = c.Redacted()
+	case FluentdConfig:
+		s.Config = c.Redacted()
+	}
+	return s
+}
+
+// String returns the stream settings as JSON with the credentials masked
+func (s StreamInfo) String() string {
+	return string(display(s.Redacted()))
+}
+
+// LogValue logs the stream settings with the credentials masked
+func (s StreamInfo) LogValue() slog.Value {
+	return slog.AnyValue(display(s.Redacted()))
+}
+
+// Redacted returns a copy of the MQTT settings with the password masked
+func (m MqttConfig) Redacted() MqttConfig {
+	m.Password = redact(m.Password)
+	return m
+}
+
+// String returns the MQTT settings as JSON with the password masked
+func (m MqttConfig) String() string {
+	return string(display(m.Redacted()))
+}
+
+// LogValue logs the MQTT settings with the password masked
+func (m MqttConfig) LogValue() slog.Value {
+	return slog.AnyValue(display(m.Redacted()))
+}
+
+// Redacted returns a copy of the Hedera settings with the operator key and the broadcast password masked
+func (h HederaConfig) Redacted() HederaConfig {
+	h.PrivateKey = redact(h.PrivateKey)
+	h.BroadcastStream = h.BroadcastStream.Redacted()
+	return h
+}
+
+// String returns the Hedera settings as JSON with the secrets masked
+func (h HederaConfig) String() string {
+	return string(display(h.Redacted()))
+}
+
+// LogValue logs the Hedera settings with the secrets masked
+func (h HederaConfig) LogValue() slog.Value {
+	return slog.AnyValue(display(h.Redacted()))
+}
+
+// Redacted returns a copy of the IOTA settings with the token masked
+func (i IotaConfig) Redacted() IotaConfig {
+	i.Token = redact(i.Token)
+	return i
+}
+
+// String returns the IOTA settings as JSON with the token masked
+func (i IotaConfig) String() string {
+	return string(display(i.Redacted()))
+}
+
+// LogValue logs the IOTA settings with the token masked
+func (i IotaConfig) LogValue() slog.Value {
+	return slog.AnyValue(display(i.Redacted()))
+}
+
+// Redacted returns a copy of the OpenTelemetry settings with the values of the headers, which typically carry
+// credentials, masked
+func (o OtelConfig) Redacted() OtelConfig {
+	if o.Headers != nil {
+		headers := make(map[string]string, len(o.Headers))
+		for name, value := range o.Headers {
+			headers[name] = redact(value)
+		}
+		o.Headers = headers
+	}
+	return o
+}
+
+// String returns the OpenTelemetry settings as JSON with the header values masked
+func (o OtelConfig) String() string {
+	return string(display(o.Redacted()))
+}
+
+// LogValue logs the OpenTelemetry settings with the header values masked
+func (o OtelConfig) LogValue() slog.Value {
+	return slog.AnyValue(display(o.Redacted()))
+}
+
+// Redacted returns a copy of the Fluentd settings with the shared key and password masked
+func (f FluentdConfig) Redacted() FluentdConfig {
+	f.SharedKey = redact(f.SharedKey)
+	f.Password = redact(f.Password)
+	return f
+}
+
+// String returns the Fluentd settings as JSON with the shared key and password masked
+func (f FluentdConfig) String() string {
+	return string(display(f.Redacted()))
+}
+
+// LogValue logs the Fluentd settings with the shared key and password masked
+func (f FluentdConfig) LogValue() slog.Value {
+	return slog.AnyValue(display(f.Redacted()))
+}
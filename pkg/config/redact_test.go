@@ -0,0 +1,102 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSdkInfo_Redacted(t *testing.T) {
+	secrets := []string{"mqtt-password", "salt-value", "tenant-salt", "vault-token", "approle-secret", "1234"}
+	cfg := SdkInfo{
+		Annotators: []contracts.AnnotationType{contracts.AnnotationSource},
+		Hash: HashInfo{Type: contracts.SHA256Hash, Salt: &SaltInfo{Value: "salt-value",
+			Tenants: map[string]string{"acme": "tenant-salt"}}},
+		Signature: SignatureInfo{
+			PublicKey: KeyInfo{Type: contracts.KeyEd25519, Path: "/etc/alvarium/keys/public.key"},
+			PrivateKey: KeyInfo{Type: contracts.KeyVaultTransit, VaultTransit: &VaultTransitInfo{KeyName: "signer",
+				Token: "vault-token", SecretId: "approle-secret"}},
+			PrivateKeys: []KeyInfo{{Type: contracts.KeyPkcs11, Pkcs11: &Pkcs11Info{Module: "/usr/lib/softhsm.so",
+				KeyLabel: "alvarium", Pin: "1234"}}},
+		},
+		Stream: StreamInfo{Type: contracts.MqttStream, Config: MqttConfig{ClientId: "sdk", User: "mosquitto",
+			Password: "mqtt-password"}},
+		Layer: contracts.Application,
+	}
+
+	var text, structured bytes.Buffer
+	slog.New(slog.NewTextHandler(&text, nil)).Info("configuration loaded", "config", cfg)
+	slog.New(slog.NewJSONHandler(&structured, nil)).Info("configuration loaded", "config", cfg)
+
+	for name, out := range map[string]string{"string": cfg.String(), "format": fmt.Sprintf("%v", cfg),
+		"text handler": text.String(), "json handler": structured.String()} {
+		for _, secret := range secrets {
+			assert.NotContains(t, out, secret, name)
+		}
+		assert.Contains(t, out, RedactedSecret, name)
+		assert.Contains(t, out, "mosquitto", name)
+	}
+
+	// JSON handlers log the configuration as an object
+	var record struct {
+		Config SdkInfo `json:"config"`
+	}
+	if err := json.Unmarshal(structured.Bytes(), &record); err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Equal(t, contracts.MqttStream, record.Config.Stream.Type)
+	assert.Equal(t, RedactedSecret, record.Config.Stream.Config.(MqttConfig).Password)
+
+	// the configuration itself is left untouched
+	assert.Equal(t, "mqtt-password", cfg.Stream.Config.(MqttConfig).Password)
+	assert.Equal(t, "tenant-salt", cfg.Hash.Salt.Tenants["acme"])
+	assert.Equal(t, "vault-token", cfg.Signature.PrivateKey.VaultTransit.Token)
+	assert.Equal(t, "1234", cfg.Signature.PrivateKeys[0].Pkcs11.Pin)
+}
+
+func TestStreamInfo_Redacted(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  any
+		secrets []string
+	}{
+		{"hedera", HederaConfig{AccountId: "0.0.2", PrivateKey: "302e0201",
+			BroadcastStream: MqttConfig{Password: "broadcast-password"}}, []string{"302e0201", "broadcast-password"}},
+		{"iota", IotaConfig{Tag: "alvarium", Token: "jwt-token"}, []string{"jwt-token"}},
+		{"otel", OtelConfig{Headers: map[string]string{"Authorization": "Bearer otel-token"}}, []string{"otel-token"}},
+		{"fluentd", FluentdConfig{Username: "fluent", SharedKey: "shared-key", Password: "fluent-password"},
+			[]string{"shared-key", "fluent-password"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := StreamInfo{Config: tt.config}.String()
+			for _, secret := range tt.secrets {
+				assert.NotContains(t, out, secret)
+			}
+			assert.Contains(t, out, RedactedSecret)
+			assert.Contains(t, fmt.Sprintf("%v", tt.config), RedactedSecret)
+		})
+	}
+
+	// settings left empty are shown as not set
+	assert.NotContains(t, MqttConfig{ClientId: "sdk"}.String(), RedactedSecret)
+}
@@ -0,0 +1,305 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+const (
+	// defaultRemoteInterval is the delay between polls of a remote configuration when none is given
+	defaultRemoteInterval = 30 * time.Second
+	// remoteTimeout bounds each request for a remote configuration
+	remoteTimeout = 10 * time.Second
+	// maxRemoteSize bounds the size of a remote configuration document
+	maxRemoteSize = 1 << 20
+)
+
+// ApplyFunc puts a configuration into effect, such as the Reconfigure method of the SDK
+type ApplyFunc func(ctx context.Context, cfg SdkInfo) error
+
+// RemoteSource reads the SDK configuration from a remote store, an HTTP endpoint, an etcd key or a Consul key, so that
+// the configuration of a fleet of devices can be changed in one place. Watch polls the store and applies each new
+// revision of the configuration, e.g.
+//
+//	source := config.NewHTTPSource("https://config.example.com/alvarium.json", contracts.ConfigJSON)
+//	cfg, err := source.Load(ctx)
+//	...
+//	go source.Watch(ctx, sdk.Reconfigure)
+//
+// The documents are parsed as by Parse, so profiles, environment variables and secret references are handled alike.
+type RemoteSource struct {
+	fetch    remoteFetch
+	format   contracts.ConfigFormat
+	client   *http.Client
+	header   http.Header
+	interval time.Duration
+	onError  func(error)
+
+	mu      sync.Mutex
+	version string // version identifies the revision last loaded, it is empty until one is
+}
+
+// remoteFetch requests the document of a remote source. It returns a nil document when the source reports that the
+// revision identified by version is still current, and otherwise the document along with its own version.
+type remoteFetch func(ctx context.Context, s *RemoteSource, version string) (data []byte, next string, err error)
+
+// RemoteOption customizes a RemoteSource
+type RemoteOption func(*RemoteSource)
+
+// WithRemoteInterval sets the delay between polls of the source, 30 seconds by default
+func WithRemoteInterval(interval time.Duration) RemoteOption {
+	return func(s *RemoteSource) {
+		s.interval = interval
+	}
+}
+
+// WithRemoteClient sets the HTTP client requesting the source, for example to present a client certificate
+func WithRemoteClient(client *http.Client) RemoteOption {
+	return func(s *RemoteSource) {
+		s.client = client
+	}
+}
+
+// WithRemoteHeader adds a header to the requests for the source, such as an Authorization or X-Consul-Token header
+func WithRemoteHeader(name, value string) RemoteOption {
+	return func(s *RemoteSource) {
+		s.header.Add(name, value)
+	}
+}
+
+// WithRemoteErrorHandler registers a handler called by Watch with the errors fetching, parsing or applying a
+// configuration. Such errors leave the configuration in effect unchanged and are otherwise ignored.
+func WithRemoteErrorHandler(handler func(error)) RemoteOption {
+	return func(s *RemoteSource) {
+		s.onError = handler
+	}
+}
+
+// NewHTTPSource returns a source reading the configuration document served at url. Unchanged documents are
+// recognized by their ETag, or by their content when the server sends none.
+func NewHTTPSource(url string, format contracts.ConfigFormat, opts ...RemoteOption) *RemoteSource {
+	return newRemoteSource(func(ctx context.Context, s *RemoteSource, version string) ([]byte, string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		if strings.HasPrefix(version, `"`) || strings.HasPrefix(version, "W/") {
+			req.Header.Set("If-None-Match", version)
+		}
+		resp, b, err := s.do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		if resp.StatusCode == http.StatusNotModified {
+			return nil, version, nil
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("%w: configuration request to %s returned %s",
+				contracts.ErrConfigInvalid, url, resp.Status)
+		}
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			return b, etag, nil
+		}
+		return b, digest(b), nil
+	}, format, opts)
+}
+
+// NewEtcdSource returns a source reading the configuration document stored under key by the etcd v3 server at
+// endpoint, e.g. http://etcd:2379, through its JSON gateway. Revisions are recognized by the key's mod_revision.
+func NewEtcdSource(endpoint, key string, format contracts.ConfigFormat, opts ...RemoteOption) *RemoteSource {
+	return newRemoteSource(func(ctx context.Context, s *RemoteSource, version string) ([]byte, string, error) {
+		body, err := json.Marshal(map[string]string{"key": base64.StdEncoding.EncodeToString([]byte(key))})
+		if err != nil {
+			return nil, "", err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/v3/kv/range",
+			bytes.NewReader(body))
+		if err != nil {
+			return nil, "", err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, b, err := s.do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("%w: etcd range request for %s returned %s",
+				contracts.ErrConfigInvalid, key, resp.Status)
+		}
+
+		var rng struct {
+			Kvs []struct {
+				Value       []byte `json:"value"` // base64 encoded, as are all bytes fields of the gateway
+				ModRevision string `json:"mod_revision"`
+			} `json:"kvs"`
+		}
+		if err = json.Unmarshal(b, &rng); err != nil {
+			return nil, "", fmt.Errorf("%w: malformed etcd range response: %w", contracts.ErrConfigInvalid, err)
+		}
+		if len(rng.Kvs) == 0 {
+			return nil, "", fmt.Errorf("%w: etcd key %s not found", contracts.ErrConfigInvalid, key)
+		}
+		if rng.Kvs[0].ModRevision == version {
+			return nil, version, nil
+		}
+		return rng.Kvs[0].Value, rng.Kvs[0].ModRevision, nil
+	}, format, opts)
+}
+
+// NewConsulSource returns a source reading the configuration document stored under key in the key/value store of the
+// Consul agent at addr, e.g. http://consul:8500. Revisions are recognized by the X-Consul-Index of the key.
+func NewConsulSource(addr, key string, format contracts.ConfigFormat, opts ...RemoteOption) *RemoteSource {
+	return newRemoteSource(func(ctx context.Context, s *RemoteSource, version string) ([]byte, string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+			strings.TrimSuffix(addr, "/")+"/v1/kv/"+url.PathEscape(strings.Trim(key, "/"))+"?raw", nil)
+		if err != nil {
+			return nil, "", err
+		}
+		resp, b, err := s.do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("%w: consul request for key %s returned %s",
+				contracts.ErrConfigInvalid, key, resp.Status)
+		}
+		index := resp.Header.Get("X-Consul-Index")
+		if index == "" {
+			index = digest(b)
+		}
+		if index == version {
+			return nil, version, nil
+		}
+		return b, index, nil
+	}, format, opts)
+}
+
+func newRemoteSource(fetch remoteFetch, format contracts.ConfigFormat, opts []RemoteOption) *RemoteSource {
+	s := &RemoteSource{
+		fetch:    fetch,
+		format:   format,
+		client:   &http.Client{Timeout: remoteTimeout},
+		header:   make(http.Header),
+		interval: defaultRemoteInterval,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Load fetches and parses the current configuration, which Watch then applies again only once it has changed
+func (s *RemoteSource) Load(ctx context.Context) (SdkInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cfg, next, err := s.load(ctx, "")
+	if err != nil {
+		return SdkInfo{}, err
+	}
+	s.version = next
+	return cfg, nil
+}
+
+// Watch polls the source until ctx is done and passes each new revision of the configuration to apply. A revision
+// that cannot be fetched, parsed or applied is reported to the error handler, see WithRemoteErrorHandler, and tried
+// again at the next poll. Watch returns the error of ctx.
+func (s *RemoteSource) Watch(ctx context.Context, apply ApplyFunc) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if err := s.poll(ctx, apply); err != nil && s.onError != nil {
+			s.onError(err)
+		}
+	}
+}
+
+// poll applies the configuration of the source if it has changed since last applied
+func (s *RemoteSource) poll(ctx context.Context, apply ApplyFunc) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cfg, next, err := s.load(ctx, s.version)
+	if err != nil || next == s.version {
+		return err
+	}
+	if err = apply(ctx, cfg); err != nil {
+		return err
+	}
+	s.version = next
+	return nil
+}
+
+// load fetches and parses the configuration unless the source reports the revision identified by version to be
+// current, returning the version of the revision fetched. Callers must hold mu.
+func (s *RemoteSource) load(ctx context.Context, version string) (SdkInfo, string, error) {
+	data, next, err := s.fetch(ctx, s, version)
+	if err != nil {
+		return SdkInfo{}, "", err
+	}
+	if data == nil || next == version {
+		return SdkInfo{}, version, nil
+	}
+	cfg, err := Parse(data, s.format)
+	if err != nil {
+		return SdkInfo{}, "", err
+	}
+	return cfg, next, nil
+}
+
+// do sends req with the headers of the source and reads the response body
+func (s *RemoteSource) do(req *http.Request) (*http.Response, []byte, error) {
+	for name, values := range s.header {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, b, nil
+}
+
+// digest identifies a document by its content when the source gives no version
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
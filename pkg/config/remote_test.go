@@ -0,0 +1,212 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// remoteStore serves a configuration document the way an HTTP server, etcd and Consul do, numbering its revisions
+type remoteStore struct {
+	mu       sync.Mutex
+	doc      string
+	revision int
+}
+
+func (r *remoteStore) set(doc string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.doc = doc
+	r.revision++
+}
+
+func (r *remoteStore) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if req.Header.Get("Authorization") != "token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	revision := strconv.Itoa(r.revision)
+	switch req.URL.Path {
+	case "/alvarium.json":
+		etag := `"` + revision + `"`
+		if req.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(r.doc))
+	case "/v3/kv/range":
+		var rng struct {
+			Key string `json:"key"`
+		}
+		json.NewDecoder(req.Body).Decode(&rng)
+		if key, _ := base64.StdEncoding.DecodeString(rng.Key); string(key) != "/alvarium/config" {
+			json.NewEncoder(w).Encode(map[string]any{"header": map[string]any{}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"kvs": []any{map[string]any{
+			"value":        base64.StdEncoding.EncodeToString([]byte(r.doc)),
+			"mod_revision": revision,
+		}}})
+	case "/v1/kv/alvarium/config":
+		w.Header().Set("X-Consul-Index", revision)
+		w.Write([]byte(r.doc))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestRemoteSource(t *testing.T) {
+	store := &remoteStore{}
+	store.set(`{"layer":"host"}`)
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		source      *RemoteSource
+		expectError bool
+	}{
+		{"http", NewHTTPSource(server.URL+"/alvarium.json", contracts.ConfigJSON,
+			WithRemoteHeader("Authorization", "token")), false},
+		{"etcd", NewEtcdSource(server.URL, "/alvarium/config", contracts.ConfigJSON,
+			WithRemoteHeader("Authorization", "token")), false},
+		{"consul", NewConsulSource(server.URL, "alvarium/config", contracts.ConfigJSON,
+			WithRemoteHeader("Authorization", "token")), false},
+		{"unauthorized", NewHTTPSource(server.URL+"/alvarium.json", contracts.ConfigJSON), true},
+		{"etcd key missing", NewEtcdSource(server.URL, "/missing", contracts.ConfigJSON,
+			WithRemoteHeader("Authorization", "token")), true},
+		{"consul key missing", NewConsulSource(server.URL, "missing", contracts.ConfigJSON,
+			WithRemoteHeader("Authorization", "token")), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.source.Load(context.Background())
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, contracts.Host, cfg.Layer)
+
+			var applied []SdkInfo
+			apply := func(ctx context.Context, cfg SdkInfo) error {
+				applied = append(applied, cfg)
+				return nil
+			}
+			// an unchanged configuration is not applied again
+			test.CheckError(tt.source.poll(context.Background(), apply), false, "unchanged", t)
+			assert.Empty(t, applied)
+
+			store.set(`{"layer":"os"}`)
+			defer store.set(`{"layer":"host"}`)
+			test.CheckError(tt.source.poll(context.Background(), apply), false, "changed", t)
+			test.CheckError(tt.source.poll(context.Background(), apply), false, "applied", t)
+			if assert.Len(t, applied, 1) {
+				assert.Equal(t, contracts.Os, applied[0].Layer)
+			}
+		})
+	}
+}
+
+func TestRemoteSource_Watch(t *testing.T) {
+	store := &remoteStore{}
+	store.set(`{"layer":"host"}`)
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	var mu sync.Mutex
+	var errs []error
+	source := NewHTTPSource(server.URL+"/alvarium.json", contracts.ConfigJSON,
+		WithRemoteHeader("Authorization", "token"), WithRemoteInterval(5*time.Millisecond),
+		WithRemoteErrorHandler(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}))
+	if _, err := source.Load(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// the first attempt to apply the new configuration fails and is retried
+	applied := make(chan SdkInfo, 1)
+	attempts := 0
+	apply := func(ctx context.Context, cfg SdkInfo) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("stream unavailable")
+		}
+		applied <- cfg
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- source.Watch(ctx, apply)
+	}()
+
+	store.set(`{"layer":"os"}`)
+	select {
+	case cfg := <-applied:
+		assert.Equal(t, contracts.Os, cfg.Layer)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("configuration change not applied")
+	}
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, errs, 1)
+}
+
+func TestRemoteSource_Invalid(t *testing.T) {
+	store := &remoteStore{}
+	store.set(`{"layer":"host"}`)
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	source := NewConsulSource(server.URL, "alvarium/config", contracts.ConfigJSON,
+		WithRemoteHeader("Authorization", "token"))
+	if _, err := source.Load(context.Background()); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// an invalid revision is not applied and the next poll tries it again
+	store.set(`{"idType":"serial"}`)
+	apply := func(ctx context.Context, cfg SdkInfo) error {
+		t.Fatalf("invalid configuration applied")
+		return nil
+	}
+	err := source.poll(context.Background(), apply)
+	assert.ErrorIs(t, err, contracts.ErrConfigInvalid)
+	err = source.poll(context.Background(), apply)
+	assert.ErrorIs(t, err, contracts.ErrConfigInvalid)
+}
@@ -0,0 +1,31 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+// RetryInfo configures how failed publishes are retried. Delays grow exponentially from InitialInterval by
+// Multiplier up to MaxInterval, each randomized by +/- Jitter. Retrying is enabled by setting MaxAttempts.
+type RetryInfo struct {
+	MaxAttempts     int         `json:"maxAttempts,omitempty" yaml:"maxAttempts"`         // MaxAttempts includes the initial publish
+	InitialInterval int         `json:"initialInterval,omitempty" yaml:"initialInterval"` // InitialInterval is the first delay in milliseconds
+	MaxInterval     int         `json:"maxInterval,omitempty" yaml:"maxInterval"`         // MaxInterval caps the delay in milliseconds
+	Multiplier      float64     `json:"multiplier,omitempty" yaml:"multiplier"`
+	Jitter          float64     `json:"jitter,omitempty" yaml:"jitter"`         // Jitter is a fraction between 0 and 1
+	DeadLetter      *StreamInfo `json:"deadLetter,omitempty" yaml:"deadLetter"` // DeadLetter receives messages that exhaust their retries
+}
+
+// Enabled reports whether publishes should be retried
+func (r RetryInfo) Enabled() bool {
+	return r.MaxAttempts > 0
+}
@@ -0,0 +1,49 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// SamplingInfo limits the data that is annotated, for workloads where publishing the annotations of every reading is
+// too costly. Data left out is still hashed when Ratio is set, but no annotations are made or published for it. The
+// zero value annotates all data.
+type SamplingInfo struct {
+	Every  int                                  `json:"every,omitempty" yaml:"every"`   // Every annotates one in every Every pieces of data
+	Ratio  float64                              `json:"ratio,omitempty" yaml:"ratio"`   // Ratio is the fraction of data annotated, chosen by data hash
+	Limits map[contracts.AnnotationType]float64 `json:"limits,omitempty" yaml:"limits"` // Limits caps the annotations of each kind made per second
+}
+
+// Enabled reports whether any sampling policy is configured
+func (s SamplingInfo) Enabled() bool {
+	return s.Every > 1 || (s.Ratio > 0 && s.Ratio < 1) || len(s.Limits) > 0
+}
+
+// validate checks the sampling settings, allowing the zero value which disables sampling
+func (s SamplingInfo) validate() error {
+	if s.Every < 0 || s.Ratio < 0 || s.Ratio > 1 {
+		return fmt.Errorf("%w: invalid sampling settings every=%v ratio=%v", contracts.ErrConfigInvalid, s.Every, s.Ratio)
+	}
+	for kind, limit := range s.Limits {
+		if !kind.Validate() || limit <= 0 {
+			return fmt.Errorf("%w: invalid sampling limit %v for AnnotationType %s", contracts.ErrConfigInvalid, limit,
+				kind)
+		}
+	}
+	return nil
+}
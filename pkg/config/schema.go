@@ -0,0 +1,259 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+//go:generate go run ../../internal/schemagen sdkinfo.schema.json
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// schemaDraft is the JSON Schema dialect of the generated schema
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// streamConfigs gives the type of the config setting of each stream type, as unmarshalled by StreamInfo
+var streamConfigs = map[contracts.StreamType]any{
+	contracts.ConsoleStream:  MockStreamConfig{},
+	contracts.MockStream:     MockStreamConfig{},
+	contracts.MqttStream:     MqttConfig{},
+	contracts.HederaStream:   HederaConfig{},
+	contracts.EthereumStream: EthereumConfig{},
+	contracts.IotaStream:     IotaConfig{},
+	contracts.ZmqStream:      ZmqConfig{},
+	contracts.UdsStream:      UdsConfig{},
+	contracts.SyslogStream:   SyslogConfig{},
+	contracts.OtelStream:     OtelConfig{},
+	contracts.FluentdStream:  FluentdConfig{},
+}
+
+// schemaEnums lists the values of the enumerated settings
+var schemaEnums = enums(
+	values(contracts.AnchorHash, contracts.AnchorEnvelope),
+	values(contracts.AnnotationPKI, contracts.AnnotationPKIHttp, contracts.AnnotationSource, contracts.AnnotationTLS,
+		contracts.AnnotationTPM, contracts.AnnotationSourceCode, contracts.AnnotationChecksum,
+		contracts.AnnotationVulnerability, contracts.AnnotationPKIGrpc,
+		contracts.AnnotationSourceHttp),
+	values(contracts.BackpressureBlock, contracts.BackpressureDrop),
+	values(contracts.JCSCanonicalization),
+	values(contracts.ConfigJSON, contracts.ConfigYAML, contracts.ConfigTOML),
+	values(contracts.GzipEncoding, contracts.ZstdEncoding),
+	values(contracts.ContentTypeJSON, contracts.ContentTypeCBOR, contracts.ContentTypeProtobuf),
+	values(contracts.Method, contracts.TargetURI, contracts.Authority, contracts.Scheme, contracts.Path,
+		contracts.Query, contracts.QueryParams, contracts.Status, contracts.RequestTarget, contracts.QueryParam),
+	values(contracts.DropOldest, contracts.DropNewest),
+	values(contracts.EnrichAddresses, contracts.EnrichMACs, contracts.EnrichRegion, contracts.EnrichInstance,
+		contracts.EnrichOs),
+	values(contracts.EnrichAnnotation, contracts.EnrichWrapper),
+	values(contracts.GasPriceNetwork, contracts.GasPriceFixed, contracts.GasPriceDynamic),
+	values(contracts.HexEncoding, contracts.MultihashEncoding),
+	values(contracts.MD5Hash, contracts.SHA256Hash, contracts.SHA3Hash, contracts.BLAKE3Hash, contracts.HMACHash,
+		contracts.MerkleHash, contracts.TreeHash, contracts.NoHash),
+	values(contracts.ULIDId, contracts.UUIDv7Id, contracts.DeterministicId),
+	values(contracts.KeyEd25519, contracts.KeyEcdsaP256, contracts.KeyEcdsaSecp256k1, contracts.KeyPkcs11,
+		contracts.KeyTpm, contracts.KeyAzureKeyVault, contracts.KeyGcpKms, contracts.KeyVaultTransit),
+	values(contracts.Application, contracts.CiCd, contracts.Os, contracts.Host),
+	values(contracts.Mainnet, contracts.Testnet, contracts.Previewnet),
+	values(contracts.PolicyContinue, contracts.PolicyAbort, contracts.PolicyAnnotate),
+	values(contracts.RateLimitBlock, contracts.RateLimitDrop),
+	values(contracts.DERSignature, contracts.CompactSignature, contracts.RecoverableSignature),
+	values(contracts.RawFormat, contracts.JWSFormat, contracts.COSEFormat),
+	values(contracts.ConsoleStream, contracts.MockStream, contracts.MqttStream, contracts.PravegaStream,
+		contracts.HederaStream, contracts.EthereumStream, contracts.IotaStream, contracts.ZmqStream,
+		contracts.UdsStream, contracts.SyslogStream, contracts.OtelStream, contracts.FluentdStream),
+	values(contracts.TagSourceEnv, contracts.TagSourceFile, contracts.TagSourceKubernetes, contracts.TagSourceCommand,
+		contracts.TagSourceStatic, contracts.TagSourceGit, contracts.TagSourceEC2, contracts.TagSourceGCE,
+		contracts.TagSourceAzure),
+	values(contracts.ZmqPub, contracts.ZmqPush),
+)
+
+// schemaExtensible holds the enumerated types that modules can extend, whose values are given as examples rather
+// than as the only values allowed
+var schemaExtensible = map[reflect.Type]bool{
+	reflect.TypeOf(contracts.AnnotationType("")): true,
+	reflect.TypeOf(contracts.KeyAlgorithm("")):   true,
+	reflect.TypeOf(contracts.LayerType("")):      true,
+}
+
+// enumValues holds the values of an enumerated type
+type enumValues struct {
+	t      reflect.Type
+	values []string
+}
+
+func values[T ~string](v ...T) enumValues {
+	e := enumValues{t: reflect.TypeOf(v[0])}
+	for _, x := range v {
+		e.values = append(e.values, string(x))
+	}
+	return e
+}
+
+func enums(v ...enumValues) map[reflect.Type][]string {
+	m := make(map[reflect.Type][]string, len(v))
+	for _, e := range v {
+		m[e.t] = e.values
+	}
+	return m
+}
+
+// Schema returns a JSON Schema of the SDK configuration, describing SdkInfo and the configuration of every stream
+// provider, against which configuration documents can be checked by editors and in CI pipelines. Settings unknown to
+// this version of the SDK are reported. The schema is also found in sdkinfo.schema.json, generated by go generate.
+func Schema() ([]byte, error) {
+	g := schemaGenerator{defs: make(map[string]any), names: make(map[reflect.Type]string)}
+	root := g.object(reflect.TypeOf(SdkInfo{}))
+	properties := root["properties"].(map[string]any)
+	properties["$schema"] = map[string]any{"type": "string"}
+	properties[profilesKey] = map[string]any{
+		"type": "object",
+		// profiles hold partial configurations, merged over the base before they are validated
+		"additionalProperties": map[string]any{"type": "object"},
+	}
+	root["$schema"] = schemaDraft
+	root["title"] = "Alvarium SDK configuration"
+	root["$defs"] = g.defs
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// schemaGenerator derives the schema of Go types from their JSON encoding. Structs are defined once in defs and
+// referred to by name.
+type schemaGenerator struct {
+	defs  map[string]any
+	names map[reflect.Type]string
+}
+
+func (g *schemaGenerator) schemaOf(t reflect.Type) map[string]any {
+	if v, ok := schemaEnums[t]; ok {
+		if schemaExtensible[t] {
+			return map[string]any{"type": "string", "examples": v}
+		}
+		return map[string]any{"type": "string", "enum": v}
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaOf(t.Elem())
+	case reflect.Struct:
+		return g.ref(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": g.schemaOf(t.Elem())}
+	case reflect.Map:
+		s := map[string]any{"type": "object", "additionalProperties": g.schemaOf(t.Elem())}
+		if _, ok := schemaEnums[t.Key()]; ok {
+			s["propertyNames"] = g.schemaOf(t.Key())
+		}
+		return s
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+	// interfaces and the like accept any value
+	return map[string]any{}
+}
+
+// ref returns a reference to the definition of struct t, defining it on first use
+func (g *schemaGenerator) ref(t reflect.Type) map[string]any {
+	name, ok := g.names[t]
+	if !ok {
+		name = t.Name()
+		if _, taken := g.defs[name]; taken || name == "" {
+			name = path.Base(t.PkgPath()) + "." + t.Name()
+		}
+		g.names[t] = name
+		g.defs[name] = nil // reserved while the fields, which may refer to t, are defined
+		g.defs[name] = g.object(t)
+	}
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+// object returns the schema of the fields of struct t
+func (g *schemaGenerator) object(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	g.fields(t, properties)
+	s := map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+	if t == reflect.TypeOf(StreamInfo{}) {
+		s["allOf"] = g.streamConfigs()
+	}
+	return s
+}
+
+func (g *schemaGenerator) fields(t reflect.Type, properties map[string]any) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.fields(embedded, properties)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = g.schemaOf(f.Type)
+	}
+}
+
+// streamConfigs selects the schema of the config setting of a stream by its type
+func (g *schemaGenerator) streamConfigs() []any {
+	types := make([]string, 0, len(streamConfigs))
+	for t := range streamConfigs {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+
+	conditions := make([]any, len(types))
+	for i, t := range types {
+		conditions[i] = map[string]any{
+			"if": map[string]any{
+				"properties": map[string]any{"type": map[string]any{"const": t}},
+				"required":   []string{"type"},
+			},
+			"then": map[string]any{
+				"properties": map[string]any{
+					"config": g.schemaOf(reflect.TypeOf(streamConfigs[contracts.StreamType(t)])),
+				},
+			},
+		}
+	}
+	return conditions
+}
@@ -0,0 +1,177 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_Generated(t *testing.T) {
+	b, err := Schema()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	generated, err := os.ReadFile("sdkinfo.schema.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Equal(t, string(b)+"\n", string(generated), "sdkinfo.schema.json is out of date, run go generate")
+}
+
+func TestSchema_Enums(t *testing.T) {
+	for typ, values := range schemaEnums {
+		for _, v := range values {
+			valid := reflect.ValueOf(v).Convert(typ).MethodByName("Validate").Call(nil)[0].Bool()
+			assert.True(t, valid, "%s %s", typ, v)
+		}
+	}
+
+	// every enumerated setting has its values listed
+	seen := make(map[reflect.Type]bool)
+	var walk func(reflect.Type)
+	walk = func(typ reflect.Type) {
+		if seen[typ] {
+			return
+		}
+		seen[typ] = true
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			walk(typ.Elem())
+		case reflect.Map:
+			walk(typ.Key())
+			walk(typ.Elem())
+		case reflect.Struct:
+			for i := 0; i < typ.NumField(); i++ {
+				walk(typ.Field(i).Type)
+			}
+		case reflect.String:
+			if _, ok := typ.MethodByName("Validate"); ok {
+				_, listed := schemaEnums[typ]
+				assert.True(t, listed, "values of %s not listed", typ)
+			}
+		}
+	}
+	walk(reflect.TypeOf(SdkInfo{}))
+	for _, cfg := range streamConfigs {
+		walk(reflect.TypeOf(cfg))
+	}
+
+	for _, v := range schemaEnums[reflect.TypeOf(contracts.StreamType(""))] {
+		_, ok := streamConfigs[contracts.StreamType(v)]
+		assert.True(t, ok || v == string(contracts.PravegaStream), "no config of stream %s", v)
+	}
+}
+
+func TestSchema_Conforms(t *testing.T) {
+	b, err := Schema()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	var schema map[string]any
+	if err = json.Unmarshal(b, &schema); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for _, path := range []string{"../../test/res/config.json", "../../test/res/config-mqtt.json"} {
+		t.Run(path, func(t *testing.T) {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			var doc any
+			if err = json.Unmarshal(b, &doc); err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Empty(t, conform(schema, schema, doc, "$"))
+		})
+	}
+
+	var doc any
+	json.Unmarshal([]byte(`{"hash":{"type":"sha1"},"stream":{"type":"mqtt","config":{"hots":"localhost"}},"layr":"app"}`),
+		&doc)
+	assert.ElementsMatch(t, []string{"$.hash.type", "$.layr", "$.stream.config.hots"}, conform(schema, schema, doc, "$"))
+}
+
+// conform returns the locations where doc breaks the property names and enumerations of schema, which is as much of
+// JSON Schema as the generated schema relies on besides types
+func conform(root, schema map[string]any, doc any, at string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		def := root["$defs"].(map[string]any)[strings.TrimPrefix(ref, "#/$defs/")]
+		return conform(root, def.(map[string]any), doc, at)
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		for _, v := range enum {
+			if v == doc {
+				return nil
+			}
+		}
+		return []string{at}
+	}
+
+	var problems []string
+	switch v := doc.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		// properties selected by the conditions, such as the config of a stream by its type
+		conditional := make(map[string]any)
+		for _, c := range asList(schema["allOf"]) {
+			c := c.(map[string]any)
+			cond := c["if"].(map[string]any)["properties"].(map[string]any)
+			matched := true
+			for name, s := range cond {
+				matched = matched && v[name] == s.(map[string]any)["const"]
+			}
+			if matched {
+				for name, s := range c["then"].(map[string]any)["properties"].(map[string]any) {
+					conditional[name] = s
+				}
+			}
+		}
+		for name, item := range v {
+			s, ok := conditional[name]
+			if !ok {
+				s, ok = properties[name]
+			}
+			if !ok {
+				s, ok = schema["additionalProperties"].(map[string]any)
+			}
+			if !ok {
+				problems = append(problems, at+"."+name)
+				continue
+			}
+			problems = append(problems, conform(root, s.(map[string]any), item, at+"."+name)...)
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				problems = append(problems, conform(root, items, item, fmt.Sprintf("%s[%d]", at, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+func asList(v any) []any {
+	l, _ := v.([]any)
+	return l
+}
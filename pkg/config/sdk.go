@@ -16,8 +16,10 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 	"gopkg.in/yaml.v3"
@@ -29,62 +31,153 @@ type SdkInfo struct {
 	Signature  SignatureInfo              `json:"signature,omitempty" yaml:"signature"`
 	Stream     StreamInfo                 `json:"stream,omitempty" yaml:"stream"`
 	Layer      contracts.LayerType        `json:"layer,omitempty" yaml:"layer"`
+	Async      AsyncInfo                  `json:"async,omitempty" yaml:"async"`
+	Sampling   SamplingInfo               `json:"sampling,omitempty" yaml:"sampling"`
+	Dedup      DedupInfo                  `json:"dedup,omitempty" yaml:"dedup"`
+	Pipeline   PipelineInfo               `json:"pipeline,omitempty" yaml:"pipeline"`
+	Chain      bool                       `json:"chain,omitempty" yaml:"chain"` // Chain links each annotation to the previous one made by the SDK
+	// IdType selects the generator of annotation identifiers, ULIDs by default. A generator set with pkg.WithIdGenerator
+	// takes precedence.
+	IdType contracts.IdType `json:"idType,omitempty" yaml:"idType"`
+	Tag    TagInfo          `json:"tag,omitempty" yaml:"tag"` // Tag configures where the tag linking to the layer below is read
+	// Tags declares the sources of the tags of each layer, taking precedence over Tag, see TagSources
+	Tags map[contracts.LayerType][]TagInfo `json:"tags,omitempty" yaml:"tags"`
+	// Enrichment configures the attributes of the host attached to the published annotations
+	Enrichment EnrichmentInfo `json:"enrichment,omitempty" yaml:"enrichment"`
+	// Layers declares the layers specific to the deployment, such as a gateway or the network, accepted along with the
+	// built-in ones, see contracts.AddLayerType
+	Layers []contracts.LayerType `json:"layers,omitempty" yaml:"layers"`
 }
 
 type LoggingInfo struct {
 	MinLogLevel slog.Level `json:"minLogLevel,omitempty"`
+	// Components sets the minimum level of the entries written by a component of the SDK, in place of MinLogLevel.
+	// A level above ERROR, such as "ERROR+4", silences the component.
+	Components map[string]slog.Level `json:"components,omitempty"`
+}
+
+// settings returns the problems found checking each setting that is validated when the configuration is
+// unmarshalled, other than those of the hash, signature and stream which are validated by their own types
+func (s SdkInfo) settings() []error {
+	var errs []error
+	for _, l := range s.Layers {
+		if !isLayerName(l) {
+			errs = append(errs, fmt.Errorf("%w: invalid layer name %q, expected lower case letters, digits and dashes",
+				contracts.ErrConfigInvalid, l))
+		}
+	}
+	if len(s.Annotators) > 0 {
+		for _, x := range s.Annotators {
+			if !x.Validate() {
+				errs = append(errs, fmt.Errorf("%w: invalid AnnotationType received %s", contracts.ErrConfigInvalid, x))
+			}
+		}
+		if !s.validLayer(s.Layer) {
+			errs = append(errs, fmt.Errorf("%w: invalid Stack Layer received %s", contracts.ErrConfigInvalid,
+				string(s.Layer)))
+		}
+	}
+	errs = appendErr(errs, s.Async.validate())
+	errs = appendErr(errs, s.Sampling.validate())
+	errs = appendErr(errs, s.Dedup.validate())
+	errs = appendErr(errs, s.Pipeline.validate())
+	errs = appendErr(errs, s.Tag.validate())
+	errs = appendErr(errs, s.validateLayerTags())
+	errs = appendErr(errs, s.Enrichment.validate())
+	errs = appendErr(errs, validateEnrichment(s.Enrichment, s.Stream))
+	if s.Chain && s.Dedup.Enabled() {
+		errs = append(errs, fmt.Errorf("%w: chained annotations cannot be de-duplicated", contracts.ErrConfigInvalid))
+	}
+	if s.Chain && s.Pipeline.Parallel() {
+		// the annotations of a call would be chained in the order the annotators complete
+		errs = append(errs, fmt.Errorf("%w: chained annotations cannot be made in parallel", contracts.ErrConfigInvalid))
+	}
+	if s.IdType != "" && !s.IdType.Validate() {
+		errs = append(errs, fmt.Errorf("%w: invalid IdType received %s", contracts.ErrConfigInvalid, s.IdType))
+	}
+	return errs
+}
+
+// isLayerName reports whether l is a valid name for a layer declared in configuration
+func isLayerName(l contracts.LayerType) bool {
+	if l == "" || len(l) > 64 || l[0] < 'a' || l[0] > 'z' {
+		return false
+	}
+	for _, c := range []byte(l) {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// validLayer reports whether l is a built-in layer, one added with contracts.AddLayerType or one declared in Layers
+func (s SdkInfo) validLayer(l contracts.LayerType) bool {
+	return l.Validate() || slices.Contains(s.Layers, l)
+}
+
+// AddLayers adds the layers declared in Layers with contracts.AddLayerType, so that annotations made in them are
+// accepted. Unmarshalling or building the configuration does not add them; it is called by pkg.NewSdk and
+// Sdk.Reconfigure, and by programs decoding annotations with the configuration but without an SDK.
+func (s SdkInfo) AddLayers() {
+	for _, l := range s.Layers {
+		contracts.AddLayerType(l)
+	}
+}
+
+// appendErr appends err to errs unless it is nil
+func appendErr(errs []error, err error) []error {
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errs
 }
 
 func (s *SdkInfo) UnmarshalJSON(data []byte) (err error) {
 	type Alias SdkInfo
-	a := &Alias{}
+	// sections left out keep their defaults
+	a := Alias(Default())
 
-	if err = json.Unmarshal(data, a); err != nil {
+	if err = json.Unmarshal(data, &a); err != nil {
 		return err
 	}
 
-	if len(a.Annotators) > 0 {
-		for _, x := range a.Annotators {
-			ok := x.Validate()
-			if !ok {
-				return fmt.Errorf("invalid AnnotationType received %s", x)
-			}
-		}
-
-		if !a.Layer.Validate() {
-			return fmt.Errorf("invalid Stack Layer received %s", string(a.Layer))
-		}
+	if err = errors.Join(SdkInfo(a).settings()...); err != nil {
+		return err
 	}
 
-	*s = SdkInfo(*a)
+	*s = SdkInfo(a)
 	return nil
 }
 
 func (s *SdkInfo) UnmarshalYAML(data *yaml.Node) (err error) {
 	type Alias SdkInfo
-	a := &Alias{}
+	// sections left out keep their defaults
+	a := Alias(Default())
 
 	// Error with unmarshaling
 	if err = data.Decode(&a); err != nil {
 		return err
 	}
 
-	if len(a.Annotators) > 0 {
-		for _, x := range a.Annotators {
-			ok := x.Validate()
-			if !ok {
-				return fmt.Errorf("invalid AnnotationType received %s", x)
-			}
-		}
-
-		if !a.Layer.Validate() {
-			return fmt.Errorf("invalid Stack Layer received %s", string(a.Layer))
-		}
+	if err = errors.Join(SdkInfo(a).settings()...); err != nil {
+		return err
 	}
 
 	s.Annotators = a.Annotators
 	s.Hash = a.Hash
 	s.Signature = a.Signature
 	s.Stream = a.Stream
+	s.Layer = a.Layer
+	s.Layers = a.Layers
+	s.Async = a.Async
+	s.Sampling = a.Sampling
+	s.Dedup = a.Dedup
+	s.Pipeline = a.Pipeline
+	s.Chain = a.Chain
+	s.IdType = a.IdType
+	s.Tag = a.Tag
+	s.Tags = a.Tags
+	s.Enrichment = a.Enrichment
 	return nil
 }
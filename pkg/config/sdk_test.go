@@ -16,7 +16,10 @@ package config
 
 import (
 	"encoding/json"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
 	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
 )
@@ -53,3 +56,450 @@ func TestSDKInfo_AnnotationInvalid(t *testing.T) {
 	err = json.Unmarshal(b, &x)
 	test.CheckError(err, true, "test sdk invalid annotation", t)
 }
+
+func TestSDKInfo_Async(t *testing.T) {
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		async       AsyncInfo
+		expectError bool
+	}{
+		{"disabled", AsyncInfo{}, false},
+		{"workers only", AsyncInfo{Workers: 4}, false},
+		{"drop policy", AsyncInfo{Workers: 4, QueueSize: 10, Policy: contracts.BackpressureDrop}, false},
+		{"negative workers", AsyncInfo{Workers: -1}, true},
+		{"negative queue size", AsyncInfo{Workers: 1, QueueSize: -1}, true},
+		{"invalid policy", AsyncInfo{Workers: 1, Policy: "spill"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Async = tt.async
+			b, _ := json.Marshal(cfg)
+
+			var x SdkInfo
+			err := json.Unmarshal(b, &x)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.async, x.Async)
+			}
+		})
+	}
+}
+
+func TestSDKInfo_Sampling(t *testing.T) {
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		sampling    SamplingInfo
+		expectError bool
+	}{
+		{"disabled", SamplingInfo{}, false},
+		{"every nth", SamplingInfo{Every: 10}, false},
+		{"ratio", SamplingInfo{Ratio: 0.25}, false},
+		{"limits", SamplingInfo{Limits: map[contracts.AnnotationType]float64{contracts.AnnotationTPM: 0.5}}, false},
+		{"negative every", SamplingInfo{Every: -1}, true},
+		{"ratio above one", SamplingInfo{Ratio: 1.5}, true},
+		{"zero limit", SamplingInfo{Limits: map[contracts.AnnotationType]float64{contracts.AnnotationTPM: 0}}, true},
+		{"invalid kind", SamplingInfo{Limits: map[contracts.AnnotationType]float64{"gps": 1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Sampling = tt.sampling
+			b, _ := json.Marshal(cfg)
+
+			var x SdkInfo
+			err := json.Unmarshal(b, &x)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.sampling, x.Sampling)
+			}
+		})
+	}
+}
+
+func TestSDKInfo_Dedup(t *testing.T) {
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		dedup       DedupInfo
+		expectError bool
+	}{
+		{"disabled", DedupInfo{}, false},
+		{"window", DedupInfo{Window: 1000}, false},
+		{"negative window", DedupInfo{Window: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Dedup = tt.dedup
+			b, _ := json.Marshal(cfg)
+
+			var x SdkInfo
+			err := json.Unmarshal(b, &x)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.dedup, x.Dedup)
+			}
+		})
+	}
+}
+
+func TestSDKInfo_Chain(t *testing.T) {
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		chain       bool
+		dedup       DedupInfo
+		pipeline    PipelineInfo
+		expectError bool
+	}{
+		{"chained", true, DedupInfo{}, PipelineInfo{}, false},
+		{"de-duplicated", false, DedupInfo{Window: 1000}, PipelineInfo{}, false},
+		{"chained and de-duplicated", true, DedupInfo{Window: 1000}, PipelineInfo{}, true},
+		{"chained in turn", true, DedupInfo{}, PipelineInfo{Parallelism: 1}, false},
+		{"chained in parallel", true, DedupInfo{}, PipelineInfo{Parallelism: 2}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Chain = tt.chain
+			cfg.Dedup = tt.dedup
+			cfg.Pipeline = tt.pipeline
+			b, _ := json.Marshal(cfg)
+
+			var x SdkInfo
+			err := json.Unmarshal(b, &x)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.chain, x.Chain)
+			}
+		})
+	}
+}
+
+func TestSDKInfo_IdType(t *testing.T) {
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		idType      contracts.IdType
+		expectError bool
+	}{
+		{"default", "", false},
+		{"uuidv7", contracts.UUIDv7Id, false},
+		{"deterministic", contracts.DeterministicId, false},
+		{"invalid", "snowflake", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.IdType = tt.idType
+			b, _ := json.Marshal(cfg)
+
+			var x SdkInfo
+			err := json.Unmarshal(b, &x)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.idType, x.IdType)
+			}
+		})
+	}
+}
+
+func TestSDKInfo_Tag(t *testing.T) {
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		tag         TagInfo
+		expectError bool
+	}{
+		{"default", TagInfo{}, false},
+		{"env", TagInfo{Source: contracts.TagSourceEnv, Key: "COMMIT_SHA"}, false},
+		{"file", TagInfo{Source: contracts.TagSourceFile, Path: "/shared/tag"}, false},
+		{"k8s", TagInfo{Source: contracts.TagSourceKubernetes, Key: "example.com/tag", Path: "/etc/podinfo/annotations"},
+			false},
+		{"command", TagInfo{Source: contracts.TagSourceCommand, Command: []string{"git", "rev-parse", "HEAD"}}, false},
+		{"static", TagInfo{Source: contracts.TagSourceStatic, Value: "deploy-7"}, false},
+		{"git", TagInfo{Source: contracts.TagSourceGit, Path: "/src/workload"}, false},
+		{"ec2", TagInfo{Source: contracts.TagSourceEC2}, false},
+		{"gce image", TagInfo{Source: contracts.TagSourceGCE, Key: "image"}, false},
+		{"azure with path", TagInfo{Source: contracts.TagSourceAzure, Path: "/shared/tag"}, true},
+		{"invalid source", TagInfo{Source: "consul"}, true},
+		{"env with path", TagInfo{Source: contracts.TagSourceEnv, Path: "/shared/tag"}, true},
+		{"file with key", TagInfo{Source: contracts.TagSourceFile, Key: "COMMIT_SHA"}, true},
+		{"command missing", TagInfo{Source: contracts.TagSourceCommand}, true},
+		{"static missing", TagInfo{Source: contracts.TagSourceStatic}, true},
+		{"static with command", TagInfo{Source: contracts.TagSourceStatic, Value: "deploy-7",
+			Command: []string{"hostname"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Tag = tt.tag
+			b, _ := json.Marshal(cfg)
+
+			var x SdkInfo
+			err := json.Unmarshal(b, &x)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.tag, x.Tag)
+			}
+		})
+	}
+}
+
+func TestSDKInfo_Enrichment(t *testing.T) {
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name          string
+		enrichment    EnrichmentInfo
+		schemaVersion int
+		expectError   bool
+	}{
+		{"disabled", EnrichmentInfo{}, 0, false},
+		{"local fields", EnrichmentInfo{Fields: []contracts.EnrichmentField{contracts.EnrichAddresses,
+			contracts.EnrichMACs, contracts.EnrichOs}}, 0, false},
+		{"cloud fields", EnrichmentInfo{Fields: []contracts.EnrichmentField{contracts.EnrichRegion,
+			contracts.EnrichInstance}, Cloud: contracts.TagSourceEC2}, 0, false},
+		{"wrapper target", EnrichmentInfo{Fields: []contracts.EnrichmentField{contracts.EnrichOs},
+			Target: contracts.EnrichWrapper}, message.WrapperVersion3, false},
+		{"invalid field", EnrichmentInfo{Fields: []contracts.EnrichmentField{"serial"}}, 0, true},
+		{"invalid target", EnrichmentInfo{Fields: []contracts.EnrichmentField{contracts.EnrichOs},
+			Target: "header"}, 0, true},
+		{"invalid cloud", EnrichmentInfo{Fields: []contracts.EnrichmentField{contracts.EnrichRegion},
+			Cloud: contracts.TagSourceGit}, 0, true},
+		{"cloud missing", EnrichmentInfo{Fields: []contracts.EnrichmentField{contracts.EnrichInstance}}, 0, true},
+		{"wrapper schema too old", EnrichmentInfo{Fields: []contracts.EnrichmentField{contracts.EnrichOs},
+			Target: contracts.EnrichWrapper}, message.WrapperVersion2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Enrichment = tt.enrichment
+			cfg.Stream.SchemaVersion = tt.schemaVersion
+			b, _ := json.Marshal(cfg)
+
+			var x SdkInfo
+			err := json.Unmarshal(b, &x)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.enrichment, x.Enrichment)
+			}
+		})
+	}
+}
+
+func TestSDKInfo_TagSources(t *testing.T) {
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	env := TagInfo{Source: contracts.TagSourceEnv, Key: contracts.TagEnvKey}
+	file := TagInfo{Source: contracts.TagSourceFile, Path: "/shared/tag"}
+	static := TagInfo{Source: contracts.TagSourceStatic, Value: "deploy-7"}
+	tests := []struct {
+		name        string
+		tag         TagInfo
+		tags        map[contracts.LayerType][]TagInfo
+		expected    map[contracts.LayerType][]TagInfo
+		expectError bool
+	}{
+		{"default", TagInfo{}, nil, map[contracts.LayerType][]TagInfo{contracts.Application: {env}}, false},
+		{"every layer", file, nil, map[contracts.LayerType][]TagInfo{contracts.Application: {file},
+			contracts.CiCd: {file}, contracts.Os: {file}, contracts.Host: {file}}, false},
+		{"per layer", TagInfo{}, map[contracts.LayerType][]TagInfo{contracts.Os: {file, static}},
+			map[contracts.LayerType][]TagInfo{contracts.Application: {env}, contracts.Os: {file, static}}, false},
+		{"per layer over every layer", file, map[contracts.LayerType][]TagInfo{contracts.Host: {static}},
+			map[contracts.LayerType][]TagInfo{contracts.Application: {file}, contracts.CiCd: {file},
+				contracts.Os: {file}, contracts.Host: {static}}, false},
+		{"invalid layer", TagInfo{}, map[contracts.LayerType][]TagInfo{"kernel": {file}}, nil, true},
+		{"missing source", TagInfo{}, map[contracts.LayerType][]TagInfo{contracts.Os: {{Path: "/shared/tag"}}}, nil,
+			true},
+		{"invalid source", TagInfo{}, map[contracts.LayerType][]TagInfo{contracts.Os: {{Source: "static"}}}, nil,
+			true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Tag = tt.tag
+			cfg.Tags = tt.tags
+			b, _ := json.Marshal(cfg)
+
+			var x SdkInfo
+			err := json.Unmarshal(b, &x)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.expected, x.TagSources())
+			}
+		})
+	}
+}
+
+func TestSDKInfo_Layers(t *testing.T) {
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	file := TagInfo{Source: contracts.TagSourceFile, Path: "/shared/tag"}
+	tests := []struct {
+		name        string
+		layers      []contracts.LayerType
+		layer       contracts.LayerType
+		tags        map[contracts.LayerType][]TagInfo
+		expectError bool
+	}{
+		{"built-in layer", nil, contracts.Host, nil, false},
+		{"declared layer", []contracts.LayerType{"edge-gateway"}, "edge-gateway", nil, false},
+		{"declared layer tags", []contracts.LayerType{"radio-network"}, contracts.Host,
+			map[contracts.LayerType][]TagInfo{"radio-network": {file}}, false},
+		{"undeclared layer", nil, "satellite-link", nil, true},
+		{"undeclared layer tags", nil, contracts.Host, map[contracts.LayerType][]TagInfo{"satellite-link": {file}},
+			true},
+		{"invalid name", []contracts.LayerType{"Edge Gateway"}, contracts.Host, nil, true},
+		{"empty name", []contracts.LayerType{""}, contracts.Host, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Layers = tt.layers
+			cfg.Layer = tt.layer
+			cfg.Tags = tt.tags
+			b, _ := json.Marshal(cfg)
+
+			var x SdkInfo
+			err := json.Unmarshal(b, &x)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.layers, x.Layers)
+				// the declared layers are accepted by the configuration, but added only by the SDK
+				for _, l := range tt.layers {
+					assert.False(t, l.Validate(), "layer %s was added", l)
+				}
+				for l, sources := range tt.tags {
+					assert.Equal(t, sources, x.TagSources()[l])
+				}
+			}
+		})
+	}
+	assert.False(t, contracts.LayerType("satellite-link").Validate())
+}
+
+func TestSDKInfo_Pipeline(t *testing.T) {
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var cfg SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		pipeline    PipelineInfo
+		expectError bool
+	}{
+		{"default", PipelineInfo{}, false},
+		{"annotate on error", PipelineInfo{OnError: contracts.PolicyAnnotate}, false},
+		{"abort on unsatisfied", PipelineInfo{OnUnsatisfied: contracts.PolicyAbort,
+			Optional: []contracts.AnnotationType{contracts.AnnotationTPM}}, false},
+		{"invalid error policy", PipelineInfo{OnError: "retry"}, true},
+		{"annotate on unsatisfied", PipelineInfo{OnUnsatisfied: contracts.PolicyAnnotate}, true},
+		{"invalid optional annotator", PipelineInfo{Optional: []contracts.AnnotationType{"gps"}}, true},
+		{"parallel", PipelineInfo{Parallelism: 4, Timeout: 500}, false},
+		{"invalid parallelism", PipelineInfo{Parallelism: -1}, true},
+		{"invalid timeout", PipelineInfo{Timeout: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Pipeline = tt.pipeline
+			b, _ := json.Marshal(cfg)
+
+			var x SdkInfo
+			err := json.Unmarshal(b, &x)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.pipeline.ErrorPolicy(), x.Pipeline.ErrorPolicy())
+				assert.Equal(t, tt.pipeline.UnsatisfiedPolicy(), x.Pipeline.UnsatisfiedPolicy())
+			}
+		})
+	}
+}
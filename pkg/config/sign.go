@@ -15,15 +15,207 @@
 package config
 
 import (
+	"bytes"
+	"crypto"
 	"encoding/json"
 	"fmt"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 	"gopkg.in/yaml.v3"
+	"os"
+	"regexp"
+	"strings"
+	"time"
 )
 
 type SignatureInfo struct {
 	PublicKey  KeyInfo `json:"public,omitempty" yaml:"public"`
 	PrivateKey KeyInfo `json:"private,omitempty" yaml:"private"`
+
+	// PrivateKeys stages further signing keys for rotation. Each takes over from PrivateKey once its ActiveFrom
+	// time passes, so a fleet can switch keys at the same moment.
+	PrivateKeys []KeyInfo `json:"privateKeys,omitempty" yaml:"privateKeys"`
+	// PublicKeys holds further verification keys, such as those of retired signing keys, selected by Id
+	PublicKeys []KeyInfo `json:"publicKeys,omitempty" yaml:"publicKeys"`
+	// Format determines how annotation signatures are represented, defaulting to the provider's own output
+	Format contracts.SignatureFormat `json:"format,omitempty" yaml:"format"`
+	// Canonicalization signs annotations over their canonical form rather than over their encoding by this SDK, so
+	// that annotations re-encoded in transit still verify. Annotations are verified over the same form, so signers and
+	// verifiers must be configured alike.
+	Canonicalization contracts.Canonicalization `json:"canonicalization,omitempty" yaml:"canonicalization"`
+	// ReloadInterval is the number of seconds between checks of key files for changes. Key files are read once and
+	// cached, so without it a replaced key is only picked up by a restart.
+	ReloadInterval int `json:"reloadInterval,omitempty" yaml:"reloadInterval"`
+	// Http protects HTTP message signatures against replay, by limiting their lifetime and adding nonces, and sets the
+	// components they cover
+	Http *HttpSignatureInfo `json:"http,omitempty" yaml:"http"`
+}
+
+// HttpSignatureInfo limits the lifetime of HTTP message signatures, makes each usable once and sets the components
+// they cover
+type HttpSignatureInfo struct {
+	// Expires is the number of seconds the signatures made by the SDK are valid for, given as their expires parameter
+	Expires int `json:"expires,omitempty" yaml:"expires"`
+	// MaxAge is the number of seconds after their creation that signatures are accepted, older ones being rejected
+	MaxAge int `json:"maxAge,omitempty" yaml:"maxAge"`
+	// Nonce adds a random nonce to the signatures made by the SDK and requires one on those verified, which are then
+	// rejected if their nonce was already seen. Nonces are remembered until the signature expires or exceeds MaxAge,
+	// or for a day if it does neither.
+	Nonce bool `json:"nonce,omitempty" yaml:"nonce"`
+	// Legacy signs messages in the format of earlier releases rather than in that of RFC 9421, for verifiers that
+	// predate it. Messages already carrying signatures are countersigned in the format of those.
+	Legacy bool `json:"legacy,omitempty" yaml:"legacy"`
+	// Request sets the components covered by the signatures of requests, by default @method, @path, @authority and
+	// the Content-Type and Content-Digest fields when requests have them
+	Request *HttpCoverageInfo `json:"request,omitempty" yaml:"request"`
+	// Response sets the components covered by the signatures of responses, by default @status and the Content-Type
+	// and Content-Digest fields when responses have them
+	Response *HttpCoverageInfo `json:"response,omitempty" yaml:"response"`
+	// MaxBodySize is the number of bytes of the body of a request read to verify its Content-Digest, requests with a
+	// larger body being rejected, by default DefaultHttpMaxBodySize
+	MaxBodySize int64 `json:"maxBodySize,omitempty" yaml:"maxBodySize"`
+}
+
+// DefaultHttpMaxBodySize is the size of the largest body of a request read to verify its Content-Digest when
+// HttpSignatureInfo.MaxBodySize is not set
+const DefaultHttpMaxBodySize = 10 << 20
+
+// BodyLimit returns the size of the largest body of a request read to verify its Content-Digest
+func (h *HttpSignatureInfo) BodyLimit() int64 {
+	if h == nil || h.MaxBodySize == 0 {
+		return DefaultHttpMaxBodySize
+	}
+	return h.MaxBodySize
+}
+
+// HttpCoverageInfo sets the components covered by HTTP message signatures, derived components such as @method or
+// header field names. Deployments whose gateways rewrite some headers leave those optional, or out of the policy.
+type HttpCoverageInfo struct {
+	// Required components are covered by the signatures made by the SDK, which fail if a message lacks one, and must
+	// be covered by the signatures verified, which are otherwise rejected
+	Required []string `json:"required,omitempty" yaml:"required"`
+	// Optional components are covered by the signatures made by the SDK when a message has them
+	Optional []string `json:"optional,omitempty" yaml:"optional"`
+}
+
+// fieldNamePattern matches the names of HTTP header fields
+var fieldNamePattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// validate checks the components of the policy, those derived from requests not applying to responses
+func (c HttpCoverageInfo) validate(name string, response bool) error {
+	seen := make(map[string]bool)
+	for _, component := range append(append([]string(nil), c.Required...), c.Optional...) {
+		if seen[strings.ToLower(component)] {
+			return fmt.Errorf("%w: http %s component %s listed twice", contracts.ErrConfigInvalid, name, component)
+		}
+		seen[strings.ToLower(component)] = true
+
+		d := contracts.DerivedComponent(component)
+		switch {
+		case !strings.HasPrefix(component, "@"):
+			if !fieldNamePattern.MatchString(component) {
+				return fmt.Errorf("%w: invalid http %s field name %q", contracts.ErrConfigInvalid, name, component)
+			}
+		case !d.Validate() || d == contracts.QueryParam:
+			// a query parameter is named by a parameter of its component, query parameters are covered by @query-params
+			return fmt.Errorf("%w: invalid http %s component %s", contracts.ErrConfigInvalid, name, component)
+		case response != (d == contracts.Status):
+			return fmt.Errorf("%w: http %s component %s does not apply", contracts.ErrConfigInvalid, name, component)
+		}
+	}
+	return nil
+}
+
+// validate checks the signature settings, the keys are validated as they are unmarshalled
+func (s SignatureInfo) validate() error {
+	if s.Format != "" && !s.Format.Validate() {
+		return fmt.Errorf("%w: invalid SignatureFormat value provided %s", contracts.ErrConfigInvalid, s.Format)
+	}
+	if s.Canonicalization != "" && !s.Canonicalization.Validate() {
+		return fmt.Errorf("%w: invalid Canonicalization value provided %s", contracts.ErrConfigInvalid,
+			s.Canonicalization)
+	}
+	if s.ReloadInterval < 0 {
+		return fmt.Errorf("%w: invalid reloadInterval value provided %v", contracts.ErrConfigInvalid, s.ReloadInterval)
+	}
+	if s.Http != nil {
+		if s.Http.Expires < 0 {
+			return fmt.Errorf("%w: invalid http expires value provided %v", contracts.ErrConfigInvalid, s.Http.Expires)
+		}
+		if s.Http.MaxAge < 0 {
+			return fmt.Errorf("%w: invalid http maxAge value provided %v", contracts.ErrConfigInvalid, s.Http.MaxAge)
+		}
+		if s.Http.MaxBodySize < 0 {
+			return fmt.Errorf("%w: invalid http maxBodySize value provided %v", contracts.ErrConfigInvalid,
+				s.Http.MaxBodySize)
+		}
+		if s.Http.Request != nil {
+			if err := s.Http.Request.validate("request", false); err != nil {
+				return err
+			}
+		}
+		if s.Http.Response != nil {
+			if err := s.Http.Response.validate("response", true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *SignatureInfo) UnmarshalJSON(data []byte) (err error) {
+	type Alias SignatureInfo
+	a := Alias{}
+	if err = json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+
+	x := SignatureInfo(a).withDefaults()
+	if err = x.validate(); err != nil {
+		return err
+	}
+	*s = x
+	return nil
+}
+
+func (s *SignatureInfo) UnmarshalYAML(data *yaml.Node) (err error) {
+	type Alias SignatureInfo
+	a := Alias{}
+	if err = data.Decode(&a); err != nil {
+		return err
+	}
+
+	x := SignatureInfo(a).withDefaults()
+	if err = x.validate(); err != nil {
+		return err
+	}
+	*s = x
+	return nil
+}
+
+// ActivePrivateKey returns the signing key in effect at the given time, which is the one with the latest ActiveFrom
+// that is not after it. PrivateKey is used when no key in PrivateKeys has become active.
+func (s SignatureInfo) ActivePrivateKey(now time.Time) KeyInfo {
+	active := s.PrivateKey
+	for _, k := range s.PrivateKeys {
+		if !k.ActiveFrom.After(now) && k.ActiveFrom.After(active.ActiveFrom) {
+			active = k
+		}
+	}
+	return active
+}
+
+// VerificationKey returns the public key with the given identifier, as stamped on annotations signed by the
+// matching private key. Annotations without an identifier are verified with PublicKey.
+func (s SignatureInfo) VerificationKey(id string) (KeyInfo, error) {
+	if id == s.PublicKey.Id {
+		return s.PublicKey, nil
+	}
+	for _, k := range s.PublicKeys {
+		if k.Id == id {
+			return k, nil
+		}
+	}
+	return KeyInfo{}, fmt.Errorf("%w: no verification key configured for key id %s", contracts.ErrKeyUnavailable, id)
 }
 
 type KeyInfo struct {
@@ -31,12 +223,204 @@ type KeyInfo struct {
 	Path string                 `json:"path,omitempty" yaml:"path"` // Path indicates the filesystem path to the key.
 	// Path will need to be extended later. Consider that keys may be sourced from difference locations -- file, TPM,
 	// Vault, etc.
+	// Url locates a key, typically a public key published in a JWK set, to be fetched over HTTP in place of Path.
+	// Fetched keys are cached for the lifetime given by the server.
+	Url string `json:"url,omitempty" yaml:"url"`
+
+	// Id identifies a key across rotations. The Id of the signing key is stamped on annotations so verifiers can
+	// select the matching public key.
+	Id string `json:"id,omitempty" yaml:"id"`
+	// ActiveFrom is the time from which a key listed in SignatureInfo.PrivateKeys is used for signing
+	ActiveFrom time.Time `json:"activeFrom,omitempty" yaml:"activeFrom"`
+
+	// Encoding determines how signatures are serialized by algorithms supporting more than one form. Verification
+	// accepts any supported form.
+	Encoding contracts.SignatureEncoding `json:"encoding,omitempty" yaml:"encoding"`
+
+	// PassphraseEnv names an environment variable holding the passphrase of an encrypted PKCS#8 private key
+	PassphraseEnv string `json:"passphraseEnv,omitempty" yaml:"passphraseEnv"`
+	// PassphraseFile is the path to a file holding the passphrase of an encrypted PKCS#8 private key, such as a
+	// mounted secret
+	PassphraseFile string `json:"passphraseFile,omitempty" yaml:"passphraseFile"`
+	// Passphrase supplies the passphrase of an encrypted private key programmatically, taking precedence over
+	// PassphraseEnv and PassphraseFile
+	Passphrase func() ([]byte, error) `json:"-" yaml:"-"`
+
+	// Pkcs11 locates a key held by a hardware security module, used in place of Path by the pkcs11 algorithm
+	Pkcs11 *Pkcs11Info `json:"pkcs11,omitempty" yaml:"pkcs11"`
+	// Tpm locates a persistent key held by a TPM 2.0 device, used in place of Path by the tpm algorithm
+	Tpm *TpmInfo `json:"tpm,omitempty" yaml:"tpm"`
+	// AzureKeyVault locates a key in Azure Key Vault, used in place of Path by the azure-keyvault algorithm
+	AzureKeyVault *AzureKeyVaultInfo `json:"azureKeyVault,omitempty" yaml:"azureKeyVault"`
+	// GcpKms locates a key in Google Cloud KMS, used in place of Path by the gcp-kms algorithm
+	GcpKms *GcpKmsInfo `json:"gcpKms,omitempty" yaml:"gcpKms"`
+	// VaultTransit locates a key in a HashiCorp Vault transit engine, used in place of Path by the vault-transit
+	// algorithm
+	VaultTransit *VaultTransitInfo `json:"vaultTransit,omitempty" yaml:"vaultTransit"`
+
+	// Material is an already loaded key, in the same form as the file at Path, and takes precedence over Path. It
+	// can only be supplied programmatically, for example from a secret manager, so that the key need not be written
+	// to disk.
+	Material []byte `json:"-" yaml:"-"`
+	// Signer is a private key supplied programmatically, used in place of Path by the ed25519 and ecdsa-p256
+	// algorithms. Its public key is used for verification when neither Material nor Path is given.
+	Signer crypto.Signer `json:"-" yaml:"-"`
+}
+
+// ReadKey returns the key supplied in Material, or else reads it from the file at Path
+func (k KeyInfo) ReadKey() ([]byte, error) {
+	if k.Material != nil {
+		return k.Material, nil
+	}
+	return os.ReadFile(k.Path)
+}
+
+// ReadPassphrase returns the passphrase of an encrypted private key from the first of Passphrase, PassphraseEnv and
+// PassphraseFile that is set. A trailing line break in the file is ignored.
+func (k KeyInfo) ReadPassphrase() ([]byte, error) {
+	switch {
+	case k.Passphrase != nil:
+		return k.Passphrase()
+	case k.PassphraseEnv != "":
+		v, ok := os.LookupEnv(k.PassphraseEnv)
+		if !ok {
+			return nil, fmt.Errorf("%w: passphrase environment variable %s is not set",
+				contracts.ErrKeyUnavailable, k.PassphraseEnv)
+		}
+		return []byte(v), nil
+	case k.PassphraseFile != "":
+		b, err := os.ReadFile(k.PassphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.TrimRight(b, "\r\n"), nil
+	}
+	return nil, fmt.Errorf("%w: no passphrase configured for encrypted key in %s",
+		contracts.ErrKeyUnavailable, k.Source())
+}
+
+// HasKey indicates whether the key is supplied in Material, at Url or at Path, as opposed to being held by a Signer
+// or an external key store
+func (k KeyInfo) HasKey() bool {
+	return k.Material != nil || k.Url != "" || k.Path != ""
+}
+
+// Source describes where the key is read from, for use in error messages
+func (k KeyInfo) Source() string {
+	if k.Material != nil {
+		return "key material"
+	}
+	if k.Url != "" {
+		return k.Url
+	}
+	return k.Path
+}
+
+// Pkcs11Info locates a key on a token accessed through a PKCS#11 module. The token is selected by its label if
+// given, otherwise by slot.
+type Pkcs11Info struct {
+	Module     string `json:"module,omitempty" yaml:"module"`         // Module is the path to the vendor's PKCS#11 shared library
+	Slot       uint   `json:"slot,omitempty" yaml:"slot"`             // Slot is the slot holding the token
+	TokenLabel string `json:"tokenLabel,omitempty" yaml:"tokenLabel"` // TokenLabel identifies the token regardless of slot
+	Pin        string `json:"pin,omitempty" yaml:"pin"`               // Pin is the user PIN for the token
+	PinEnv     string `json:"pinEnv,omitempty" yaml:"pinEnv"`         // PinEnv names an environment variable holding the PIN, taking precedence over Pin
+	KeyLabel   string `json:"keyLabel,omitempty" yaml:"keyLabel"`     // KeyLabel identifies the key pair on the token
+}
+
+// TpmInfo locates a persistent signing key, such as one created with tpm2_evictcontrol, on a TPM 2.0 device.
+type TpmInfo struct {
+	Device  string `json:"device,omitempty" yaml:"device"`   // Device is the TPM device path, defaulting to the resource manager at /dev/tpmrm0
+	Handle  uint32 `json:"handle,omitempty" yaml:"handle"`   // Handle is the persistent handle of the key, between 0x81000000 and 0x81FFFFFF
+	Auth    string `json:"auth,omitempty" yaml:"auth"`       // Auth is the authorization value of the key, if it has one
+	AuthEnv string `json:"authEnv,omitempty" yaml:"authEnv"` // AuthEnv names an environment variable holding Auth, taking precedence over it
+}
+
+// AzureKeyVaultInfo locates an EC P-256 key in Azure Key Vault. Requests are authorized with the managed identity of
+// the host, which needs the sign permission on the key, and get for verification.
+type AzureKeyVaultInfo struct {
+	VaultUrl   string `json:"vaultUrl,omitempty" yaml:"vaultUrl"`     // VaultUrl is the vault's base URL, e.g. https://myvault.vault.azure.net
+	KeyName    string `json:"keyName,omitempty" yaml:"keyName"`       // KeyName is the name of the key in the vault
+	KeyVersion string `json:"keyVersion,omitempty" yaml:"keyVersion"` // KeyVersion pins a version of the key, the current version is used if empty
+	ClientId   string `json:"clientId,omitempty" yaml:"clientId"`     // ClientId selects a user-assigned managed identity, the system-assigned identity is used if empty
+}
+
+// GcpKmsInfo locates an EC_SIGN_P256_SHA256 key version in Google Cloud KMS. Requests are authorized with
+// application default credentials unless a service account key file is given.
+type GcpKmsInfo struct {
+	// KeyName is the resource name of the key version, i.e.
+	// projects/{project}/locations/{location}/keyRings/{ring}/cryptoKeys/{key}/cryptoKeyVersions/{version}
+	KeyName         string `json:"keyName,omitempty" yaml:"keyName"`
+	CredentialsFile string `json:"credentialsFile,omitempty" yaml:"credentialsFile"` // CredentialsFile is an optional service account key file
+	// Retry controls backoff when KMS quotas are exceeded or the service is unavailable. Five attempts with 20%
+	// jitter are made when MaxAttempts is not set. DeadLetter is not used.
+	Retry RetryInfo `json:"retry,omitempty" yaml:"retry"`
+}
+
+// VaultTransitInfo locates a key in a HashiCorp Vault transit secrets engine. Vault authenticates the SDK with a
+// token, or with an AppRole when RoleId is set. Address and token fall back to VAULT_ADDR and VAULT_TOKEN.
+type VaultTransitInfo struct {
+	Address      string   `json:"address,omitempty" yaml:"address"`           // Address is the Vault server URL, e.g. https://vault:8200
+	Namespace    string   `json:"namespace,omitempty" yaml:"namespace"`       // Namespace is the Vault Enterprise namespace, if any
+	Mount        string   `json:"mount,omitempty" yaml:"mount"`               // Mount is the path of the transit engine, defaulting to transit
+	KeyName      string   `json:"keyName,omitempty" yaml:"keyName"`           // KeyName is the name of the transit key
+	KeyVersion   int      `json:"keyVersion,omitempty" yaml:"keyVersion"`     // KeyVersion pins a version of the key, the latest is used if zero
+	Token        string   `json:"token,omitempty" yaml:"token"`               // Token is a Vault token with update access to the sign and verify paths
+	TokenEnv     string   `json:"tokenEnv,omitempty" yaml:"tokenEnv"`         // TokenEnv names an environment variable holding Token, taking precedence over it
+	RoleId       string   `json:"roleId,omitempty" yaml:"roleId"`             // RoleId enables AppRole login in place of a token
+	SecretId     string   `json:"secretId,omitempty" yaml:"secretId"`         // SecretId is the AppRole secret
+	SecretIdEnv  string   `json:"secretIdEnv,omitempty" yaml:"secretIdEnv"`   // SecretIdEnv names an environment variable holding SecretId, taking precedence over it
+	AppRoleMount string   `json:"appRoleMount,omitempty" yaml:"appRoleMount"` // AppRoleMount is the path of the AppRole auth method, defaulting to approle
+	TLS          *TLSInfo `json:"tls,omitempty" yaml:"tls"`                   // TLS configures a private CA or client certificate for the Vault server
+}
+
+// validate checks the settings that depend on the key algorithm
+func (k KeyInfo) validate() error {
+	if !k.Type.Validate() {
+		return fmt.Errorf("%w: invalid KeyAlgorithm value provided %s", contracts.ErrConfigInvalid, k.Type)
+	}
+	if k.Encoding != "" && !k.Encoding.Validate() {
+		return fmt.Errorf("%w: invalid SignatureEncoding value provided %s", contracts.ErrConfigInvalid, k.Encoding)
+	}
+	if k.Url != "" && !strings.HasPrefix(k.Url, "https://") && !strings.HasPrefix(k.Url, "http://") {
+		return fmt.Errorf("%w: key url %s must use http or https", contracts.ErrConfigInvalid, k.Url)
+	}
+	if k.Type == contracts.KeyPkcs11 && (k.Pkcs11 == nil || k.Pkcs11.Module == "" || k.Pkcs11.KeyLabel == "") {
+		return fmt.Errorf("%w: KeyAlgorithm %s requires a pkcs11 module and keyLabel",
+			contracts.ErrConfigInvalid, k.Type)
+	}
+	if k.Type == contracts.KeyTpm && (k.Tpm == nil || k.Tpm.Handle>>24 != 0x81) {
+		return fmt.Errorf("%w: KeyAlgorithm %s requires a tpm persistent handle", contracts.ErrConfigInvalid, k.Type)
+	}
+	if k.Type == contracts.KeyAzureKeyVault &&
+		(k.AzureKeyVault == nil || k.AzureKeyVault.VaultUrl == "" || k.AzureKeyVault.KeyName == "") {
+		return fmt.Errorf("%w: KeyAlgorithm %s requires an azureKeyVault vaultUrl and keyName",
+			contracts.ErrConfigInvalid, k.Type)
+	}
+	if k.Type == contracts.KeyGcpKms && (k.GcpKms == nil || !strings.Contains(k.GcpKms.KeyName, "/cryptoKeyVersions/")) {
+		return fmt.Errorf("%w: KeyAlgorithm %s requires a gcpKms keyName including the key version",
+			contracts.ErrConfigInvalid, k.Type)
+	}
+	if k.Type == contracts.KeyVaultTransit && (k.VaultTransit == nil || k.VaultTransit.KeyName == "") {
+		return fmt.Errorf("%w: KeyAlgorithm %s requires a vaultTransit keyName", contracts.ErrConfigInvalid, k.Type)
+	}
+	return nil
 }
 
 func (k *KeyInfo) UnmarshalJSON(data []byte) (err error) {
 	type Alias struct {
-		Type contracts.KeyAlgorithm `json:"type,omitempty"`
-		Path string                 `json:"path,omitempty"`
+		Type           contracts.KeyAlgorithm      `json:"type,omitempty"`
+		Id             string                      `json:"id,omitempty"`
+		ActiveFrom     time.Time                   `json:"activeFrom,omitempty"`
+		Path           string                      `json:"path,omitempty"`
+		Url            string                      `json:"url,omitempty"`
+		Encoding       contracts.SignatureEncoding `json:"encoding,omitempty"`
+		PassphraseEnv  string                      `json:"passphraseEnv,omitempty"`
+		PassphraseFile string                      `json:"passphraseFile,omitempty"`
+		Pkcs11         *Pkcs11Info                 `json:"pkcs11,omitempty"`
+		Tpm            *TpmInfo                    `json:"tpm,omitempty"`
+		AzureKeyVault  *AzureKeyVaultInfo          `json:"azureKeyVault,omitempty"`
+		GcpKms         *GcpKmsInfo                 `json:"gcpKms,omitempty"`
+		VaultTransit   *VaultTransitInfo           `json:"vaultTransit,omitempty"`
 	}
 	a := Alias{}
 	// Error with unmarshaling
@@ -44,19 +428,37 @@ func (k *KeyInfo) UnmarshalJSON(data []byte) (err error) {
 		return err
 	}
 
-	if !a.Type.Validate() {
-		return fmt.Errorf("invalid KeyAlgorithm value provided %s", a.Type)
+	if a.Type == "" {
+		a.Type = contracts.KeyEd25519
+	}
+	x := KeyInfo{
+		Type: a.Type, Path: a.Path, Url: a.Url, Id: a.Id, ActiveFrom: a.ActiveFrom, Encoding: a.Encoding,
+		PassphraseEnv: a.PassphraseEnv, PassphraseFile: a.PassphraseFile,
+		Pkcs11: a.Pkcs11, Tpm: a.Tpm, AzureKeyVault: a.AzureKeyVault, GcpKms: a.GcpKms, VaultTransit: a.VaultTransit,
 	}
-	k.Type = a.Type
-	k.Path = a.Path
+	if err = x.validate(); err != nil {
+		return err
+	}
+	*k = x
 
 	return nil
 }
 
 func (k *KeyInfo) UnmarshalYAML(data *yaml.Node) (err error) {
 	type Alias struct {
-		Type contracts.KeyAlgorithm `yaml:"type"`
-		Path string                 `yaml:"path"`
+		Type           contracts.KeyAlgorithm      `yaml:"type"`
+		Id             string                      `yaml:"id"`
+		ActiveFrom     time.Time                   `yaml:"activeFrom"`
+		Path           string                      `yaml:"path"`
+		Url            string                      `yaml:"url"`
+		Encoding       contracts.SignatureEncoding `yaml:"encoding"`
+		PassphraseEnv  string                      `yaml:"passphraseEnv"`
+		PassphraseFile string                      `yaml:"passphraseFile"`
+		Pkcs11         *Pkcs11Info                 `yaml:"pkcs11"`
+		Tpm            *TpmInfo                    `yaml:"tpm"`
+		AzureKeyVault  *AzureKeyVaultInfo          `yaml:"azureKeyVault"`
+		GcpKms         *GcpKmsInfo                 `yaml:"gcpKms"`
+		VaultTransit   *VaultTransitInfo           `yaml:"vaultTransit"`
 	}
 	a := Alias{}
 	// Error with unmarshaling
@@ -64,11 +466,18 @@ func (k *KeyInfo) UnmarshalYAML(data *yaml.Node) (err error) {
 		return err
 	}
 
-	if !a.Type.Validate() {
-		return fmt.Errorf("invalid KeyAlgorithm value provided %s", a.Type)
+	if a.Type == "" {
+		a.Type = contracts.KeyEd25519
+	}
+	x := KeyInfo{
+		Type: a.Type, Path: a.Path, Url: a.Url, Id: a.Id, ActiveFrom: a.ActiveFrom, Encoding: a.Encoding,
+		PassphraseEnv: a.PassphraseEnv, PassphraseFile: a.PassphraseFile,
+		Pkcs11: a.Pkcs11, Tpm: a.Tpm, AzureKeyVault: a.AzureKeyVault, GcpKms: a.GcpKms, VaultTransit: a.VaultTransit,
+	}
+	if err = x.validate(); err != nil {
+		return err
 	}
-	k.Type = a.Type
-	k.Path = a.Path
+	*k = x
 
 	return nil
 }
@@ -17,7 +17,11 @@ import (
 	"encoding/json"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestKeyInfoUnmarshal(t *testing.T) {
@@ -25,6 +29,81 @@ func TestKeyInfoUnmarshal(t *testing.T) {
 		Type: contracts.KeyEd25519,
 	}
 
+	ecdsa := KeyInfo{
+		Type: contracts.KeyEcdsaP256,
+	}
+
+	secp256k1 := KeyInfo{
+		Type:     contracts.KeyEcdsaSecp256k1,
+		Encoding: contracts.CompactSignature,
+	}
+
+	invalidEncoding := KeyInfo{
+		Type:     contracts.KeyEcdsaSecp256k1,
+		Encoding: "base64",
+	}
+
+	hsm := KeyInfo{
+		Type:   contracts.KeyPkcs11,
+		Pkcs11: &Pkcs11Info{Module: "/usr/lib/softhsm/libsofthsm2.so", TokenLabel: "alvarium", KeyLabel: "signer"},
+	}
+
+	hsmMissing := KeyInfo{
+		Type: contracts.KeyPkcs11,
+	}
+
+	tpm := KeyInfo{
+		Type: contracts.KeyTpm,
+		Tpm:  &TpmInfo{Handle: 0x81000001},
+	}
+
+	tpmTransient := KeyInfo{
+		Type: contracts.KeyTpm,
+		Tpm:  &TpmInfo{Handle: 0x80000001},
+	}
+
+	azure := KeyInfo{
+		Type:          contracts.KeyAzureKeyVault,
+		AzureKeyVault: &AzureKeyVaultInfo{VaultUrl: "https://alvarium.vault.azure.net", KeyName: "signer"},
+	}
+
+	azureMissing := KeyInfo{
+		Type:          contracts.KeyAzureKeyVault,
+		AzureKeyVault: &AzureKeyVaultInfo{VaultUrl: "https://alvarium.vault.azure.net"},
+	}
+
+	gcp := KeyInfo{
+		Type: contracts.KeyGcpKms,
+		GcpKms: &GcpKmsInfo{
+			KeyName: "projects/alvarium/locations/global/keyRings/gateway/cryptoKeys/signer/cryptoKeyVersions/1",
+		},
+	}
+
+	gcpNoVersion := KeyInfo{
+		Type:   contracts.KeyGcpKms,
+		GcpKms: &GcpKmsInfo{KeyName: "projects/alvarium/locations/global/keyRings/gateway/cryptoKeys/signer"},
+	}
+
+	vault := KeyInfo{
+		Type:         contracts.KeyVaultTransit,
+		VaultTransit: &VaultTransitInfo{Address: "https://vault:8200", KeyName: "signer"},
+	}
+
+	vaultMissing := KeyInfo{
+		Type:         contracts.KeyVaultTransit,
+		VaultTransit: &VaultTransitInfo{Address: "https://vault:8200"},
+	}
+
+	jwks := KeyInfo{
+		Type: contracts.KeyEcdsaP256,
+		Url:  "https://idp.example.com/.well-known/jwks.json",
+	}
+
+	jwksFile := KeyInfo{
+		Type: contracts.KeyEcdsaP256,
+		Url:  "file:///etc/alvarium/jwks.json",
+	}
+
 	fail := KeyInfo{
 		Type: "invalid",
 	}
@@ -35,6 +114,21 @@ func TestKeyInfoUnmarshal(t *testing.T) {
 		expectError bool
 	}{
 		{"valid key ed25519", pass, false},
+		{"valid key ecdsa-p256", ecdsa, false},
+		{"valid key secp256k1", secp256k1, false},
+		{"invalid signature encoding", invalidEncoding, true},
+		{"valid key pkcs11", hsm, false},
+		{"pkcs11 missing settings", hsmMissing, true},
+		{"valid key tpm", tpm, false},
+		{"tpm transient handle", tpmTransient, true},
+		{"valid key azure-keyvault", azure, false},
+		{"azure-keyvault missing key name", azureMissing, true},
+		{"valid key gcp-kms", gcp, false},
+		{"gcp-kms missing key version", gcpNoVersion, true},
+		{"valid key vault-transit", vault, false},
+		{"vault-transit missing key name", vaultMissing, true},
+		{"valid key url", jwks, false},
+		{"key url without http", jwksFile, true},
 		{"invalid key", fail, true},
 	}
 	for _, tt := range tests {
@@ -46,3 +140,165 @@ func TestKeyInfoUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestSignatureInfo_ActivePrivateKey(t *testing.T) {
+	now := time.Now()
+	keys := SignatureInfo{
+		PrivateKey: KeyInfo{Type: contracts.KeyEd25519, Id: "2024-01"},
+		PrivateKeys: []KeyInfo{
+			{Type: contracts.KeyEd25519, Id: "2024-06", ActiveFrom: now.Add(-time.Hour)},
+			{Type: contracts.KeyEd25519, Id: "2024-03", ActiveFrom: now.Add(-2 * time.Hour)},
+			{Type: contracts.KeyEd25519, Id: "2024-09", ActiveFrom: now.Add(time.Hour)},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected string
+	}{
+		{"before rotation", now.Add(-3 * time.Hour), "2024-01"},
+		{"first rotation", now.Add(-90 * time.Minute), "2024-03"},
+		{"latest active", now, "2024-06"},
+		{"scheduled key", now.Add(2 * time.Hour), "2024-09"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, keys.ActivePrivateKey(tt.now).Id)
+		})
+	}
+}
+
+func TestSignatureInfo_VerificationKey(t *testing.T) {
+	keys := SignatureInfo{
+		PublicKey: KeyInfo{Type: contracts.KeyEd25519, Path: "current.key", Id: "2024-06"},
+		PublicKeys: []KeyInfo{
+			{Type: contracts.KeyEd25519, Path: "retired.key", Id: "2024-01"},
+		},
+	}
+	unnamed := SignatureInfo{PublicKey: KeyInfo{Type: contracts.KeyEd25519, Path: "current.key"}}
+
+	tests := []struct {
+		name        string
+		keys        SignatureInfo
+		id          string
+		expected    string
+		expectError bool
+	}{
+		{"current key", keys, "2024-06", "current.key", false},
+		{"retired key", keys, "2024-01", "retired.key", false},
+		{"unknown key", keys, "2023-01", "", true},
+		{"annotation without key id", unnamed, "", "current.key", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := tt.keys.VerificationKey(tt.id)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.expected, k.Path)
+		})
+	}
+}
+
+func TestKeyInfo_ReadKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "private.key")
+	if err := os.WriteFile(path, []byte("from file"), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		key         KeyInfo
+		expected    string
+		expectError bool
+	}{
+		{"path", KeyInfo{Path: path}, "from file", false},
+		{"material", KeyInfo{Material: []byte("in memory")}, "in memory", false},
+		{"material takes precedence", KeyInfo{Path: path, Material: []byte("in memory")}, "in memory", false},
+		{"missing file", KeyInfo{Path: path + ".missing"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.key.ReadKey()
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.expected, string(b))
+			assert.True(t, tt.key.HasKey())
+		})
+	}
+	assert.False(t, KeyInfo{Type: contracts.KeyEd25519}.HasKey())
+}
+
+func TestKeyInfo_ReadPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "passphrase")
+	if err := os.WriteFile(file, []byte("from file\n"), 0600); err != nil {
+		t.Fatalf(err.Error())
+	}
+	t.Setenv("ALVARIUM_TEST_PASSPHRASE", "from env")
+	callback := func() ([]byte, error) { return []byte("from callback"), nil }
+
+	tests := []struct {
+		name        string
+		key         KeyInfo
+		expected    string
+		expectError bool
+	}{
+		{"callback", KeyInfo{Passphrase: callback, PassphraseEnv: "ALVARIUM_TEST_PASSPHRASE"}, "from callback", false},
+		{"env", KeyInfo{PassphraseEnv: "ALVARIUM_TEST_PASSPHRASE", PassphraseFile: file}, "from env", false},
+		{"file", KeyInfo{PassphraseFile: file}, "from file", false},
+		{"unset env", KeyInfo{PassphraseEnv: "ALVARIUM_TEST_UNSET"}, "", true},
+		{"missing file", KeyInfo{PassphraseFile: filepath.Join(dir, "missing")}, "", true},
+		{"not configured", KeyInfo{Path: "private.pem"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.key.ReadPassphrase()
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.expected, string(b))
+		})
+	}
+}
+
+func TestSignatureInfoUnmarshal(t *testing.T) {
+	tests := []struct {
+		name        string
+		json        string
+		expectError bool
+	}{
+		{"default format", `{"private":{"type":"ed25519"}}`, false},
+		{"jws format", `{"private":{"type":"ed25519"},"format":"jws"}`, false},
+		{"invalid format", `{"private":{"type":"ed25519"},"format":"pgp"}`, true},
+		{"jcs canonicalization", `{"private":{"type":"ed25519"},"canonicalization":"jcs"}`, false},
+		{"invalid canonicalization", `{"private":{"type":"ed25519"},"canonicalization":"c14n"}`, true},
+		{"invalid key", `{"private":{"type":"invalid"}}`, true},
+		{"reload interval", `{"private":{"type":"ed25519"},"reloadInterval":30}`, false},
+		{"negative reload interval", `{"private":{"type":"ed25519"},"reloadInterval":-1}`, true},
+		{"http replay protection", `{"private":{"type":"ed25519"},"http":{"expires":60,"maxAge":60,"nonce":true}}`,
+			false},
+		{"negative http expires", `{"private":{"type":"ed25519"},"http":{"expires":-1}}`, true},
+		{"negative http max age", `{"private":{"type":"ed25519"},"http":{"maxAge":-1}}`, true},
+		{"http max body size", `{"private":{"type":"ed25519"},"http":{"maxBodySize":1048576}}`, false},
+		{"negative http max body size", `{"private":{"type":"ed25519"},"http":{"maxBodySize":-1}}`, true},
+		{"http coverage", `{"private":{"type":"ed25519"},"http":{"request":{"required":["@method","@path","Content-Digest"],` +
+			`"optional":["Content-Type","@query-params"]},"response":{"required":["@status"],"optional":["Content-Type"]}}}`,
+			false},
+		{"http coverage listed twice", `{"private":{"type":"ed25519"},"http":{"request":{"required":["Content-Type"],` +
+			`"optional":["content-type"]}}}`, true},
+		{"http coverage invalid field name", `{"private":{"type":"ed25519"},"http":{"request":{"required":["Content Type"]}}}`,
+			true},
+		{"http coverage unknown component", `{"private":{"type":"ed25519"},"http":{"request":{"required":["@unknown"]}}}`,
+			true},
+		{"http coverage query param", `{"private":{"type":"ed25519"},"http":{"request":{"optional":["@query-param"]}}}`,
+			true},
+		{"http coverage status of request", `{"private":{"type":"ed25519"},"http":{"request":{"required":["@status"]}}}`,
+			true},
+		{"http coverage method of response", `{"private":{"type":"ed25519"},"http":{"response":{"required":["@method"]}}}`,
+			true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var x SignatureInfo
+			err := json.Unmarshal([]byte(tt.json), &x)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
@@ -19,18 +19,67 @@ import (
 	"fmt"
 
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
 	"gopkg.in/yaml.v3"
 )
 
 // StreamInfo facilitates configuration of a given streaming platform that will receive annotations
 type StreamInfo struct {
-	Type   contracts.StreamType `json:"type,omitempty" yaml:"type"`
-	Config interface{}          `json:"config,omitempty" yaml:"config"`
+	Type        contracts.StreamType  `json:"type,omitempty" yaml:"type"`
+	Config      interface{}           `json:"config,omitempty" yaml:"config"`
+	Buffer      BufferInfo            `json:"buffer,omitempty" yaml:"buffer"`
+	Batch       BatchInfo             `json:"batch,omitempty" yaml:"batch"`
+	Retry       RetryInfo             `json:"retry,omitempty" yaml:"retry"`
+	Compression CompressionInfo       `json:"compression,omitempty" yaml:"compression"`
+	RateLimit   RateLimitInfo         `json:"rateLimit,omitempty" yaml:"rateLimit"`
+	Encryption  EncryptionInfo        `json:"encryption,omitempty" yaml:"encryption"`
+	ContentType contracts.ContentType `json:"contentType,omitempty" yaml:"contentType"` // ContentType is the serialization of published wrappers, JSON by default
+	// SchemaVersion is the version of the published wrappers, see message.WrapperVersion. The newest version is used by
+	// default, older consumers may require an older one.
+	SchemaVersion int `json:"schemaVersion,omitempty" yaml:"schemaVersion"`
+}
+
+// validate checks the settings common to every stream type
+func (s StreamInfo) validate() error {
+	if !s.Type.Validate() {
+		return fmt.Errorf("%w: invalid StreamType value provided %s", contracts.ErrConfigInvalid, s.Type)
+	}
+	if s.Buffer.DropPolicy != "" && !s.Buffer.DropPolicy.Validate() {
+		return fmt.Errorf("%w: invalid DropPolicy value provided %s", contracts.ErrConfigInvalid, s.Buffer.DropPolicy)
+	}
+	if s.Compression.Encoding != "" && !s.Compression.Encoding.Validate() {
+		return fmt.Errorf("%w: invalid ContentEncoding value provided %s",
+			contracts.ErrConfigInvalid, s.Compression.Encoding)
+	}
+	if s.RateLimit.Policy != "" && !s.RateLimit.Policy.Validate() {
+		return fmt.Errorf("%w: invalid RateLimitPolicy value provided %s",
+			contracts.ErrConfigInvalid, s.RateLimit.Policy)
+	}
+	if s.ContentType != "" && !s.ContentType.Validate() {
+		return fmt.Errorf("%w: invalid ContentType value provided %s", contracts.ErrConfigInvalid, s.ContentType)
+	}
+	if s.SchemaVersion < 0 || s.SchemaVersion > message.WrapperVersion {
+		return fmt.Errorf("%w: %w %d", contracts.ErrConfigInvalid, contracts.ErrUnsupportedVersion, s.SchemaVersion)
+	}
+	if s.SchemaVersion == message.WrapperVersion1 && (s.Compression.Encoding != "" || len(s.Encryption.Recipients) > 0 ||
+		(s.ContentType != "" && s.ContentType != contracts.ContentTypeJSON)) {
+		return fmt.Errorf("%w: wrapper schema version %d cannot carry compressed, encrypted or non-JSON content",
+			contracts.ErrConfigInvalid, s.SchemaVersion)
+	}
+	return nil
 }
 
 func (s *StreamInfo) UnmarshalJSON(data []byte) (err error) {
 	type Alias struct {
-		Type contracts.StreamType `json:"type,omitempty"`
+		Type          contracts.StreamType  `json:"type,omitempty"`
+		Buffer        BufferInfo            `json:"buffer,omitempty"`
+		Batch         BatchInfo             `json:"batch,omitempty"`
+		Retry         RetryInfo             `json:"retry,omitempty"`
+		Compression   CompressionInfo       `json:"compression,omitempty"`
+		RateLimit     RateLimitInfo         `json:"rateLimit,omitempty"`
+		Encryption    EncryptionInfo        `json:"encryption,omitempty"`
+		ContentType   contracts.ContentType `json:"contentType,omitempty"`
+		SchemaVersion int                   `json:"schemaVersion,omitempty"`
 	}
 	a := Alias{}
 	// Error with unmarshaling
@@ -38,8 +87,13 @@ func (s *StreamInfo) UnmarshalJSON(data []byte) (err error) {
 		return err
 	}
 
-	if !a.Type.Validate() {
-		return fmt.Errorf("invalid StreamType value provided %s", a.Type)
+	if a.Type == "" {
+		a.Type = contracts.ConsoleStream
+	}
+	settings := StreamInfo{Type: a.Type, Buffer: a.Buffer, Batch: a.Batch, Retry: a.Retry, Compression: a.Compression,
+		RateLimit: a.RateLimit, Encryption: a.Encryption, ContentType: a.ContentType, SchemaVersion: a.SchemaVersion}
+	if err = settings.validate(); err != nil {
+		return err
 	}
 
 	if a.Type == contracts.MqttStream {
@@ -77,7 +131,7 @@ func (s *StreamInfo) UnmarshalJSON(data []byte) (err error) {
 		if err = json.Unmarshal(data, &c); err != nil {
 			return err
 		}
-		s.Type = c.Type
+		s.Type = a.Type
 		s.Config = MockStreamConfig{}
 	} else if a.Type == contracts.HederaStream {
 		type hederaAlias struct {
@@ -92,16 +146,123 @@ func (s *StreamInfo) UnmarshalJSON(data []byte) (err error) {
 		}
 		s.Type = h.Type
 		s.Config = h.Config
+	} else if a.Type == contracts.EthereumStream {
+		type ethereumAlias struct {
+			Type   contracts.StreamType `json:"type,omitempty"`
+			Config EthereumConfig       `json:"config,omitempty"`
+		}
+
+		e := ethereumAlias{}
+		// Error with unmarshaling
+		if err = json.Unmarshal(data, &e); err != nil {
+			return err
+		}
+		s.Type = e.Type
+		s.Config = e.Config
+	} else if a.Type == contracts.IotaStream {
+		type iotaAlias struct {
+			Type   contracts.StreamType `json:"type,omitempty"`
+			Config IotaConfig           `json:"config,omitempty"`
+		}
+
+		i := iotaAlias{}
+		// Error with unmarshaling
+		if err = json.Unmarshal(data, &i); err != nil {
+			return err
+		}
+		s.Type = i.Type
+		s.Config = i.Config
+	} else if a.Type == contracts.ZmqStream {
+		type zmqAlias struct {
+			Type   contracts.StreamType `json:"type,omitempty"`
+			Config ZmqConfig            `json:"config,omitempty"`
+		}
+
+		z := zmqAlias{}
+		// Error with unmarshaling
+		if err = json.Unmarshal(data, &z); err != nil {
+			return err
+		}
+		s.Type = z.Type
+		s.Config = z.Config
+	} else if a.Type == contracts.UdsStream {
+		type udsAlias struct {
+			Type   contracts.StreamType `json:"type,omitempty"`
+			Config UdsConfig            `json:"config,omitempty"`
+		}
+
+		u := udsAlias{}
+		// Error with unmarshaling
+		if err = json.Unmarshal(data, &u); err != nil {
+			return err
+		}
+		s.Type = u.Type
+		s.Config = u.Config
+	} else if a.Type == contracts.SyslogStream {
+		type syslogAlias struct {
+			Type   contracts.StreamType `json:"type,omitempty"`
+			Config SyslogConfig         `json:"config,omitempty"`
+		}
+
+		l := syslogAlias{}
+		// Error with unmarshaling
+		if err = json.Unmarshal(data, &l); err != nil {
+			return err
+		}
+		s.Type = l.Type
+		s.Config = l.Config
+	} else if a.Type == contracts.OtelStream {
+		type otelAlias struct {
+			Type   contracts.StreamType `json:"type,omitempty"`
+			Config OtelConfig           `json:"config,omitempty"`
+		}
+
+		o := otelAlias{}
+		// Error with unmarshaling
+		if err = json.Unmarshal(data, &o); err != nil {
+			return err
+		}
+		s.Type = o.Type
+		s.Config = o.Config
+	} else if a.Type == contracts.FluentdStream {
+		type fluentdAlias struct {
+			Type   contracts.StreamType `json:"type,omitempty"`
+			Config FluentdConfig        `json:"config,omitempty"`
+		}
+
+		f := fluentdAlias{}
+		// Error with unmarshaling
+		if err = json.Unmarshal(data, &f); err != nil {
+			return err
+		}
+		s.Type = f.Type
+		s.Config = f.Config
 	} else {
-		return fmt.Errorf("unhandled StreamInfo.Type value %s", a.Type)
+		return fmt.Errorf("%w: unhandled StreamInfo.Type value %s", contracts.ErrConfigInvalid, a.Type)
 	}
 
+	s.Buffer = a.Buffer
+	s.Batch = a.Batch
+	s.Retry = a.Retry
+	s.Compression = a.Compression
+	s.RateLimit = a.RateLimit
+	s.Encryption = a.Encryption
+	s.ContentType = a.ContentType
+	s.SchemaVersion = a.SchemaVersion
 	return nil
 }
 
 func (s *StreamInfo) UnmarshalYAML(data *yaml.Node) (err error) {
 	type Alias struct {
-		Type contracts.StreamType `yaml:"type"`
+		Type          contracts.StreamType  `yaml:"type"`
+		Buffer        BufferInfo            `yaml:"buffer"`
+		Batch         BatchInfo             `yaml:"batch"`
+		Retry         RetryInfo             `yaml:"retry"`
+		Compression   CompressionInfo       `yaml:"compression"`
+		RateLimit     RateLimitInfo         `yaml:"rateLimit"`
+		Encryption    EncryptionInfo        `yaml:"encryption"`
+		ContentType   contracts.ContentType `yaml:"contentType"`
+		SchemaVersion int                   `yaml:"schemaVersion"`
 	}
 	a := Alias{}
 	// Error with unmarshaling
@@ -109,8 +270,13 @@ func (s *StreamInfo) UnmarshalYAML(data *yaml.Node) (err error) {
 		return err
 	}
 
-	if !a.Type.Validate() {
-		return fmt.Errorf("invalid StreamType value provided %s", a.Type)
+	if a.Type == "" {
+		a.Type = contracts.ConsoleStream
+	}
+	settings := StreamInfo{Type: a.Type, Buffer: a.Buffer, Batch: a.Batch, Retry: a.Retry, Compression: a.Compression,
+		RateLimit: a.RateLimit, Encryption: a.Encryption, ContentType: a.ContentType, SchemaVersion: a.SchemaVersion}
+	if err = settings.validate(); err != nil {
+		return err
 	}
 
 	if a.Type == contracts.MqttStream {
@@ -139,6 +305,97 @@ func (s *StreamInfo) UnmarshalYAML(data *yaml.Node) (err error) {
 		}
 		s.Type = h.Type
 		s.Config = h.Config
+	} else if a.Type == contracts.EthereumStream {
+		type ethereumAlias struct {
+			Type   contracts.StreamType `yaml:"type"`
+			Config EthereumConfig       `yaml:"config"`
+		}
+
+		e := ethereumAlias{}
+		// Error with unmarshaling
+		if err = data.Decode(&e); err != nil {
+			return err
+		}
+		s.Type = e.Type
+		s.Config = e.Config
+	} else if a.Type == contracts.IotaStream {
+		type iotaAlias struct {
+			Type   contracts.StreamType `yaml:"type"`
+			Config IotaConfig           `yaml:"config"`
+		}
+
+		i := iotaAlias{}
+		// Error with unmarshaling
+		if err = data.Decode(&i); err != nil {
+			return err
+		}
+		s.Type = i.Type
+		s.Config = i.Config
+	} else if a.Type == contracts.ZmqStream {
+		type zmqAlias struct {
+			Type   contracts.StreamType `yaml:"type"`
+			Config ZmqConfig            `yaml:"config"`
+		}
+
+		z := zmqAlias{}
+		// Error with unmarshaling
+		if err = data.Decode(&z); err != nil {
+			return err
+		}
+		s.Type = z.Type
+		s.Config = z.Config
+	} else if a.Type == contracts.UdsStream {
+		type udsAlias struct {
+			Type   contracts.StreamType `yaml:"type"`
+			Config UdsConfig            `yaml:"config"`
+		}
+
+		u := udsAlias{}
+		// Error with unmarshaling
+		if err = data.Decode(&u); err != nil {
+			return err
+		}
+		s.Type = u.Type
+		s.Config = u.Config
+	} else if a.Type == contracts.SyslogStream {
+		type syslogAlias struct {
+			Type   contracts.StreamType `yaml:"type"`
+			Config SyslogConfig         `yaml:"config"`
+		}
+
+		l := syslogAlias{}
+		// Error with unmarshaling
+		if err = data.Decode(&l); err != nil {
+			return err
+		}
+		s.Type = l.Type
+		s.Config = l.Config
+	} else if a.Type == contracts.OtelStream {
+		type otelAlias struct {
+			Type   contracts.StreamType `yaml:"type"`
+			Config OtelConfig           `yaml:"config"`
+		}
+
+		o := otelAlias{}
+		// Error with unmarshaling
+		if err = data.Decode(&o); err != nil {
+			return err
+		}
+		s.Type = o.Type
+		s.Config = o.Config
+	} else if a.Type == contracts.FluentdStream {
+		type fluentdAlias struct {
+			Type   contracts.StreamType `yaml:"type"`
+			Config FluentdConfig        `yaml:"config"`
+		}
+
+		f := fluentdAlias{}
+		// Error with unmarshaling
+		if err = data.Decode(&f); err != nil {
+			return err
+		}
+		s.Type = f.Type
+		s.Config = f.Config
 	} else if a.Type == contracts.MockStream {
 		type mockAlias struct {
 			Type   contracts.StreamType `yaml:"type"`
@@ -161,12 +418,20 @@ func (s *StreamInfo) UnmarshalYAML(data *yaml.Node) (err error) {
 		if err = data.Decode(&c); err != nil {
 			return err
 		}
-		s.Type = c.Type
+		s.Type = a.Type
 		s.Config = MockStreamConfig{}
 	} else {
-		return fmt.Errorf("unhandled StreamInfo.Type value %s", a.Type)
+		return fmt.Errorf("%w: unhandled StreamInfo.Type value %s", contracts.ErrConfigInvalid, a.Type)
 	}
 
+	s.Buffer = a.Buffer
+	s.Batch = a.Batch
+	s.Retry = a.Retry
+	s.Compression = a.Compression
+	s.RateLimit = a.RateLimit
+	s.Encryption = a.Encryption
+	s.ContentType = a.ContentType
+	s.SchemaVersion = a.SchemaVersion
 	return nil
 }
 
@@ -179,6 +444,24 @@ type MqttConfig struct {
 	Provider  ServiceInfo `json:"provider,omitempty" yaml:"provider"`
 	Cleanness bool        `json:"cleanness,omitempty" yaml:"cleanness"`
 	Topics    []string    `json:"topics,omitempty" yaml:"topics"`
+	TLS       TLSInfo     `json:"tls,omitempty" yaml:"tls"` // TLS is applied when the provider protocol is ssl, tls, mqtts or wss
+	Retained  bool        `json:"retained,omitempty" yaml:"retained"`
+	// ActionQos overrides Qos for publishes of specific SDK actions, e.g. {"create": 1, "transit": 0}
+	ActionQos map[string]int `json:"actionQos,omitempty" yaml:"actionQos"`
+	Will      *MqttWillInfo  `json:"will,omitempty" yaml:"will"` // Will is published by the broker if the client disconnects abnormally
+	// ProtocolVersion selects MQTT 3.1.1 (default) or 5. The remaining properties only apply to MQTT 5.
+	ProtocolVersion int    `json:"protocolVersion,omitempty" yaml:"protocolVersion"`
+	MessageExpiry   int    `json:"messageExpiry,omitempty" yaml:"messageExpiry"` // MessageExpiry is the message lifetime in seconds
+	ContentType     string `json:"contentType,omitempty" yaml:"contentType"`
+	SessionExpiry   int    `json:"sessionExpiry,omitempty" yaml:"sessionExpiry"` // SessionExpiry is how long in seconds the broker retains the session after disconnect
+}
+
+// MqttWillInfo describes the Last Will and Testament message registered with the broker on connect
+type MqttWillInfo struct {
+	Topic    string `json:"topic,omitempty" yaml:"topic"`
+	Payload  string `json:"payload,omitempty" yaml:"payload"`
+	Qos      int    `json:"qos,omitempty" yaml:"qos"`
+	Retained bool   `json:"retained,omitempty" yaml:"retained"`
 }
 
 // MockStreamConfig exposes properties to simulate a stream connection for testing.
@@ -198,6 +481,122 @@ type HederaConfig struct {
 
 	// TODO (Ali Amin): Add support for other providers
 	BroadcastStream MqttConfig `json:"broadcastStream,omitempty" yaml:"broadcastStream"`
+
+	// MirrorNode enables read-back verification of submitted messages when its Url is set
+	MirrorNode HederaMirrorInfo `json:"mirrorNode,omitempty" yaml:"mirrorNode"`
+
+	// Topic controls creation of the consensus topic and the keys governing it
+	Topic HederaTopicInfo `json:"topic,omitempty" yaml:"topic"`
+	// MaxSubmitFee caps the fee in hbar paid for each message submission, overriding DefaultMaxTxFee
+	MaxSubmitFee float64 `json:"maxSubmitFee,omitempty" yaml:"maxSubmitFee"`
+	// MaxMessageSize is the size in bytes above which annotation lists are split across several messages.
+	// Defaults to the 1024 byte consensus service chunk size.
+	MaxMessageSize int `json:"maxMessageSize,omitempty" yaml:"maxMessageSize"`
+	// MaxChunks limits how many chunks a single message that cannot be split may be divided into
+	MaxChunks int `json:"maxChunks,omitempty" yaml:"maxChunks"`
+	// PrivateKey is the DER encoded operator key, taking precedence over PrivateKeyPath. It is meant to be given as
+	// a secret reference such as env:HEDERA_PRIVATE_KEY rather than in plain text.
+	PrivateKey string `json:"privateKey,omitempty" yaml:"privateKey"`
+}
+
+// HederaTopicInfo exposes properties for creating and submitting to a consensus topic
+type HederaTopicInfo struct {
+	AutoCreate    bool    `json:"autoCreate,omitempty" yaml:"autoCreate"` // AutoCreate creates a topic on connect when no Topics are configured
+	Memo          string  `json:"memo,omitempty" yaml:"memo"`
+	AdminKeyPath  string  `json:"adminKeyPath,omitempty" yaml:"adminKeyPath"`   // AdminKeyPath is a DER encoded key allowed to update or delete the topic
+	SubmitKeyPath string  `json:"submitKeyPath,omitempty" yaml:"submitKeyPath"` // SubmitKeyPath is a DER encoded key required to submit messages
+	MaxCreateFee  float64 `json:"maxCreateFee,omitempty" yaml:"maxCreateFee"`   // MaxCreateFee caps the fee in hbar paid to create the topic
+}
+
+// HederaMirrorInfo exposes properties for confirming through a mirror node's REST API that a submitted message
+// reached consensus
+type HederaMirrorInfo struct {
+	Url          string `json:"url,omitempty" yaml:"url"`                   // Url is the mirror node base URL, e.g. https://testnet.mirrornode.hedera.com
+	Timeout      int    `json:"timeout,omitempty" yaml:"timeout"`           // Timeout is how long in seconds to wait for the message to appear
+	PollInterval int    `json:"pollInterval,omitempty" yaml:"pollInterval"` // PollInterval is the delay in milliseconds between queries
+}
+
+// EthereumConfig exposes properties required to anchor annotations to an EVM compatible chain through a method
+// on a deployed contract
+type EthereumConfig struct {
+	Endpoint        string               `json:"endpoint,omitempty" yaml:"endpoint"` // Endpoint is the JSON-RPC URL of the node
+	ChainId         int64                `json:"chainId,omitempty" yaml:"chainId"`
+	PrivateKeyPath  string               `json:"privateKeyPath,omitempty" yaml:"privateKeyPath"` // Hex encoded secp256k1 key
+	ContractAddress string               `json:"contractAddress,omitempty" yaml:"contractAddress"`
+	Method          string               `json:"method,omitempty" yaml:"method"` // Method name only, arguments follow AnchorMode
+	AnchorMode      contracts.AnchorMode `json:"anchorMode,omitempty" yaml:"anchorMode"`
+	GasLimit        uint64               `json:"gasLimit,omitempty" yaml:"gasLimit"` // GasLimit will be estimated when zero
+	GasPrice        GasPriceInfo         `json:"gasPrice,omitempty" yaml:"gasPrice"`
+}
+
+// GasPriceInfo describes how transactions submitted to an EVM chain are priced. Values are denominated in gwei.
+type GasPriceInfo struct {
+	Strategy   contracts.GasPriceStrategy `json:"strategy,omitempty" yaml:"strategy"`
+	Fixed      float64                    `json:"fixed,omitempty" yaml:"fixed"`           // Fixed is used by the fixed strategy
+	Multiplier float64                    `json:"multiplier,omitempty" yaml:"multiplier"` // Multiplier is applied to suggested prices
+	Max        float64                    `json:"max,omitempty" yaml:"max"`               // Max caps the price, zero disables the cap
+}
+
+// IotaConfig exposes properties required to post annotations as tagged data blocks to an IOTA node
+type IotaConfig struct {
+	Provider ServiceInfo `json:"provider,omitempty" yaml:"provider"`
+	Tag      string      `json:"tag,omitempty" yaml:"tag"`     // Tag is attached to every block so consumers can index them
+	Token    string      `json:"token,omitempty" yaml:"token"` // Token is an optional JWT for nodes requiring authorization
+}
+
+// ZmqConfig exposes properties for distributing annotations over ZeroMQ without an intermediate broker
+type ZmqConfig struct {
+	SocketType contracts.ZmqSocketType `json:"socketType,omitempty" yaml:"socketType"`
+	Provider   ServiceInfo             `json:"provider,omitempty" yaml:"provider"`
+	Bind       bool                    `json:"bind,omitempty" yaml:"bind"`     // Bind listens on Provider rather than connecting to it
+	Topics     []string                `json:"topics,omitempty" yaml:"topics"` // Topics prefix messages sent by a PUB socket
+	Curve      ZmqCurveConfig          `json:"curve,omitempty" yaml:"curve"`
+}
+
+// ZmqCurveConfig enables CurveZMQ encryption and authentication. Keys are Z85 encoded as produced by zmq_curve_keypair.
+type ZmqCurveConfig struct {
+	Enabled           bool     `json:"enabled,omitempty" yaml:"enabled"`
+	Server            bool     `json:"server,omitempty" yaml:"server"` // Server indicates this socket acts as the CURVE server
+	PublicKey         string   `json:"publicKey,omitempty" yaml:"publicKey"`
+	SecretKeyPath     string   `json:"secretKeyPath,omitempty" yaml:"secretKeyPath"`
+	ServerKey         string   `json:"serverKey,omitempty" yaml:"serverKey"`                 // Required when acting as a client
+	AllowedClientKeys []string `json:"allowedClientKeys,omitempty" yaml:"allowedClientKeys"` // Empty allows any client
+}
+
+// UdsConfig exposes properties for handing annotations to a co-resident agent over a local socket
+type UdsConfig struct {
+	// Path is the filesystem path of a Unix domain socket, or a pipe name such as \\.\pipe\alvarium on Windows
+	Path string `json:"path,omitempty" yaml:"path"`
+}
+
+// SyslogConfig exposes properties for emitting annotations as RFC 5424 messages to a syslog collector. The
+// Provider protocol may be one of tcp, tls or udp.
+type SyslogConfig struct {
+	Provider ServiceInfo `json:"provider,omitempty" yaml:"provider"`
+	Facility int         `json:"facility,omitempty" yaml:"facility"` // Facility defaults to local0 when unset
+	AppName  string      `json:"appName,omitempty" yaml:"appName"`
+	TLS      TLSInfo     `json:"tls,omitempty" yaml:"tls"`
+}
+
+// OtelConfig exposes properties for exporting annotations as log records to an OpenTelemetry collector using
+// OTLP/HTTP. The Provider protocol may be one of http or https.
+type OtelConfig struct {
+	Provider    ServiceInfo       `json:"provider,omitempty" yaml:"provider"`
+	ServiceName string            `json:"serviceName,omitempty" yaml:"serviceName"` // ServiceName is reported as the service.name resource attribute
+	Headers     map[string]string `json:"headers,omitempty" yaml:"headers"`         // Headers are added to every export request, e.g. for authentication
+	TLS         TLSInfo           `json:"tls,omitempty" yaml:"tls"`
+}
+
+// FluentdConfig exposes properties for forwarding annotations to a Fluentd or Fluent Bit forward input. The Provider
+// protocol may be one of tcp or tls. Supplying a SharedKey enables the forward protocol's authentication handshake.
+type FluentdConfig struct {
+	Provider   ServiceInfo `json:"provider,omitempty" yaml:"provider"`
+	Tag        string      `json:"tag,omitempty" yaml:"tag"` // Tag is used by the collector to route events
+	SharedKey  string      `json:"sharedKey,omitempty" yaml:"sharedKey"`
+	Username   string      `json:"username,omitempty" yaml:"username"`
+	Password   string      `json:"password,omitempty" yaml:"password"`
+	RequireAck bool        `json:"requireAck,omitempty" yaml:"requireAck"` // RequireAck waits for the collector to acknowledge each publish
+	TLS        TLSInfo     `json:"tls,omitempty" yaml:"tls"`
 }
 
 // ServiceInfo describes a service endpoint that the deployed service is a client of. Right now, this is implicitly
@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
 	"github.com/project-alvarium/alvarium-sdk-go/test"
 )
 
@@ -50,6 +51,15 @@ func TestStreamInfoUnmarshal(t *testing.T) {
 		Topics:         []string{"topic1", "topic2"},
 	}
 
+	streamEthereum := EthereumConfig{
+		Endpoint:        "http://localhost:8545",
+		ChainId:         1337,
+		ContractAddress: "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+		Method:          "anchor",
+		AnchorMode:      contracts.AnchorHash,
+		GasPrice:        GasPriceInfo{Strategy: contracts.GasPriceDynamic},
+	}
+
 	pass := StreamInfo{
 		Type:   contracts.MockStream,
 		Config: streamMock,
@@ -70,6 +80,17 @@ func TestStreamInfoUnmarshal(t *testing.T) {
 		Config: streamHedera,
 	}
 
+	pass5 := StreamInfo{
+		Type:   contracts.EthereumStream,
+		Config: streamEthereum,
+	}
+
+	pass6 := StreamInfo{
+		Type:   contracts.MockStream,
+		Config: streamMock,
+		Buffer: BufferInfo{Path: "buffer.db", Capacity: 100, DropPolicy: contracts.DropNewest},
+	}
+
 	fail := StreamInfo{
 		Type:   "invalid",
 		Config: streamMock,
@@ -84,8 +105,34 @@ func TestStreamInfoUnmarshal(t *testing.T) {
 	b, _ := json.Marshal(&pass2)
 	c, _ := json.Marshal(&pass3)
 	d, _ := json.Marshal(&pass4)
+	g, _ := json.Marshal(&pass5)
+	h, _ := json.Marshal(&pass6)
 	e, _ := json.Marshal(&fail)
 	f, _ := json.Marshal(&fail2)
+	pass6.Buffer.DropPolicy = "invalid"
+	i, _ := json.Marshal(&pass6)
+	pass6.Buffer.DropPolicy = contracts.DropNewest
+	pass6.Compression = CompressionInfo{Encoding: contracts.ZstdEncoding}
+	j, _ := json.Marshal(&pass6)
+	pass6.Compression.Encoding = "brotli"
+	k, _ := json.Marshal(&pass6)
+	pass6.Compression.Encoding = contracts.ZstdEncoding
+	pass6.RateLimit = RateLimitInfo{Rate: 5, Burst: 10, Policy: contracts.RateLimitDrop}
+	l, _ := json.Marshal(&pass6)
+	pass6.RateLimit.Policy = "queue"
+	m, _ := json.Marshal(&pass6)
+	pass6.RateLimit.Policy = contracts.RateLimitDrop
+	pass6.ContentType = contracts.ContentTypeCBOR
+	n, _ := json.Marshal(&pass6)
+	pass6.ContentType = "application/xml"
+	o, _ := json.Marshal(&pass6)
+	pass6.ContentType = ""
+	pass6.SchemaVersion = message.WrapperVersion1
+	q, _ := json.Marshal(&pass6)
+	pass6.Compression = CompressionInfo{}
+	r, _ := json.Marshal(&pass6)
+	pass6.SchemaVersion = message.WrapperVersion + 1
+	u, _ := json.Marshal(&pass6)
 
 	tests := []struct {
 		name        string
@@ -96,8 +143,20 @@ func TestStreamInfoUnmarshal(t *testing.T) {
 		{"valid StreamInfo type #2", b, false},
 		{"valid StreamInfo type #3", c, false},
 		{"valid StreamInfo type #4", d, false},
+		{"valid StreamInfo type #5", g, false},
+		{"valid StreamInfo buffer", h, false},
 		{"invalid StreamInfo type", e, true},
 		{"unhandled StreamInfo type", f, true},
+		{"invalid StreamInfo drop policy", i, true},
+		{"valid StreamInfo compression", j, false},
+		{"invalid StreamInfo content encoding", k, true},
+		{"valid StreamInfo rate limit", l, false},
+		{"invalid StreamInfo rate limit policy", m, true},
+		{"valid StreamInfo content type", n, false},
+		{"invalid StreamInfo content type", o, true},
+		{"invalid StreamInfo compressed original schema", q, true},
+		{"valid StreamInfo original schema", r, false},
+		{"invalid StreamInfo schema version", u, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -115,11 +174,19 @@ func TestStreamInfoUnmarshal(t *testing.T) {
 					if cfg.AccountId != "testID" {
 						t.Errorf("unexpected account ID value %s", cfg.AccountId)
 					}
+				} else if s.Type == contracts.EthereumStream {
+					cfg := s.Config.(EthereumConfig)
+					if cfg.GasPrice.Strategy != contracts.GasPriceDynamic {
+						t.Errorf("unexpected gas price strategy %s", cfg.GasPrice.Strategy)
+					}
 				} else if s.Type == contracts.MockStream {
 					cfg := s.Config.(MockStreamConfig)
 					if cfg.Provider.Uri() != "http://localhost:8080" {
 						t.Errorf("unexpected provider Uri value %s", cfg.Provider.Uri())
 					}
+					if s.Buffer.Path != "" && s.Buffer.DropPolicy != contracts.DropNewest {
+						t.Errorf("unexpected buffer drop policy %s", s.Buffer.DropPolicy)
+					}
 				}
 			}
 		})
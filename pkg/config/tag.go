@@ -0,0 +1,111 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package config
+
+import (
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// TagInfo configures a source of the Tag linking the annotations of a layer to the layer below, see
+// factories.NewTagWriter and factories.NewTagValueGetter
+type TagInfo struct {
+	Source  contracts.TagSourceType `json:"source,omitempty" yaml:"source"`   // Source is where the tag is exchanged
+	Key     string                  `json:"key,omitempty" yaml:"key"`         // Key is the environment variable, pod annotation or label, or instance metadata item holding the tag
+	Path    string                  `json:"path,omitempty" yaml:"path"`       // Path is the file holding the tag, the pod annotations or labels exposed through the downward API, or the git repository
+	Command []string                `json:"command,omitempty" yaml:"command"` // Command is the name and arguments of the command printing the tag
+	Value   string                  `json:"value,omitempty" yaml:"value"`     // Value holds the tags of the static source, separated by commas
+}
+
+// Enabled reports whether the source of the tag is configured
+func (t TagInfo) Enabled() bool {
+	return t.Source != ""
+}
+
+// validate checks the tag source, allowing the zero value which keeps the default behavior
+func (t TagInfo) validate() error {
+	if t.Source == "" {
+		return nil
+	}
+	if !t.Source.Validate() {
+		return fmt.Errorf("%w: invalid tag source %s", contracts.ErrConfigInvalid, t.Source)
+	}
+	// each source takes its own settings only
+	var unused bool
+	switch t.Source {
+	case contracts.TagSourceEnv:
+		unused = t.Path != "" || len(t.Command) > 0 || t.Value != ""
+	case contracts.TagSourceFile:
+		unused = t.Key != "" || len(t.Command) > 0 || t.Value != ""
+	case contracts.TagSourceKubernetes:
+		unused = len(t.Command) > 0 || t.Value != ""
+	case contracts.TagSourceCommand:
+		if len(t.Command) == 0 {
+			return fmt.Errorf("%w: tag source %s requires a command", contracts.ErrConfigInvalid, t.Source)
+		}
+		unused = t.Key != "" || t.Path != "" || t.Value != ""
+	case contracts.TagSourceStatic:
+		if t.Value == "" {
+			return fmt.Errorf("%w: tag source %s requires a value", contracts.ErrConfigInvalid, t.Source)
+		}
+		unused = t.Key != "" || t.Path != "" || len(t.Command) > 0
+	case contracts.TagSourceGit:
+		unused = t.Key != "" || len(t.Command) > 0 || t.Value != ""
+	case contracts.TagSourceEC2, contracts.TagSourceGCE, contracts.TagSourceAzure:
+		unused = t.Path != "" || len(t.Command) > 0 || t.Value != ""
+	}
+	if unused {
+		return fmt.Errorf("%w: settings given that do not apply to tag source %s", contracts.ErrConfigInvalid, t.Source)
+	}
+	return nil
+}
+
+// validateLayerTags checks the tag sources declared per layer in Tags
+func (s SdkInfo) validateLayerTags() error {
+	for layer, sources := range s.Tags {
+		if !s.validLayer(layer) {
+			return fmt.Errorf("%w: invalid tag layer %s", contracts.ErrConfigInvalid, layer)
+		}
+		for _, source := range sources {
+			if !source.Enabled() {
+				return fmt.Errorf("%w: tag source of layer %s is required", contracts.ErrConfigInvalid, layer)
+			}
+			if err := source.validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TagSources returns the sources of the tags of each layer, read in order: those declared for the layer in Tags,
+// otherwise Tag if set. When neither is, the tags of the app layer are read from the TAG environment variable. The
+// layers declared in Layers are given sources as the built-in ones are.
+func (s SdkInfo) TagSources() map[contracts.LayerType][]TagInfo {
+	sources := make(map[contracts.LayerType][]TagInfo)
+	layers := append([]contracts.LayerType{contracts.Application, contracts.CiCd, contracts.Os, contracts.Host},
+		s.Layers...)
+	for _, layer := range layers {
+		switch {
+		case len(s.Tags[layer]) > 0:
+			sources[layer] = s.Tags[layer]
+		case s.Tag.Enabled():
+			sources[layer] = []TagInfo{s.Tag}
+		case layer == contracts.Application:
+			sources[layer] = []TagInfo{{Source: contracts.TagSourceEnv, Key: contracts.TagEnvKey}}
+		}
+	}
+	return sources
+}
@@ -0,0 +1,25 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+// TLSInfo describes the material used to secure a connection to a stream provider. Certificates and keys are PEM
+// encoded files. Supplying CertPath and KeyPath enables mutual TLS.
+type TLSInfo struct {
+	CaPath             string `json:"caPath,omitempty" yaml:"caPath"`
+	CertPath           string `json:"certPath,omitempty" yaml:"certPath"`
+	KeyPath            string `json:"keyPath,omitempty" yaml:"keyPath"`
+	ServerName         string `json:"serverName,omitempty" yaml:"serverName"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify"`
+}
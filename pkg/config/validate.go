@@ -0,0 +1,458 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package config
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// Validate checks the whole configuration ahead of its use, where problems would otherwise surface one at a time as
+// each part is first used. Beyond the checks made when a configuration is unmarshalled, it checks the settings
+// required by the stream type, that the configured key files can be read and hold keys in a form their algorithm
+// reads, and that the hash type and keys suit the annotators. Every problem found is returned, joined, each wrapping
+// contracts.ErrConfigInvalid.
+func (s SdkInfo) Validate() error {
+	errs := s.settings()
+	errs = appendErr(errs, s.Hash.validate())
+	errs = appendErr(errs, s.Signature.validate())
+	errs = appendErr(errs, s.Stream.validate())
+	errs = append(errs, s.Hash.requirements()...)
+	errs = append(errs, s.Signature.requirements(len(s.Annotators) > 0)...)
+	errs = append(errs, s.Stream.requirements()...)
+	errs = append(errs, s.compatibility()...)
+	return errors.Join(errs...)
+}
+
+// compatibility returns the problems found combining the hash, signature and annotator settings
+func (s SdkInfo) compatibility() []error {
+	var errs []error
+	for _, kind := range s.Annotators {
+		if (kind == contracts.AnnotationPKIHttp || kind == contracts.AnnotationSourceHttp) &&
+			s.Signature.PrivateKey.Type != contracts.KeyEd25519 {
+			errs = append(errs, fmt.Errorf("%w: annotator %s requires %s keys", contracts.ErrConfigInvalid, kind,
+				contracts.KeyEd25519))
+		}
+	}
+	if s.Hash.Tree != nil && s.Hash.Type != contracts.TreeHash {
+		errs = append(errs, fmt.Errorf("%w: tree settings do not apply to HashType %s", contracts.ErrConfigInvalid,
+			s.Hash.Type))
+	}
+	return errs
+}
+
+// requirements returns the problems found reading the key and salts of the hash
+func (h HashInfo) requirements() []error {
+	var errs []error
+	if h.Type.Keyed() {
+		if h.KeyEnv != "" {
+			errs = appendErr(errs, checkEnv("hash key", h.KeyEnv))
+		} else if h.KeyPath != "" {
+			errs = appendErr(errs, checkHexFile("hash key", h.KeyPath))
+		}
+	}
+	if h.Salt != nil {
+		if h.Salt.Env != "" {
+			errs = appendErr(errs, checkEnv("hash salt", h.Salt.Env))
+		} else if _, err := hex.DecodeString(h.Salt.Value); err != nil {
+			errs = append(errs, fmt.Errorf("%w: hash salt is not hex encoded", contracts.ErrConfigInvalid))
+		}
+		for tenant, salt := range h.Salt.Tenants {
+			if _, err := hex.DecodeString(salt); err != nil {
+				errs = append(errs, fmt.Errorf("%w: hash salt of tenant %s is not hex encoded",
+					contracts.ErrConfigInvalid, tenant))
+			}
+		}
+	}
+	return errs
+}
+
+// requirements returns the problems found with the configured keys. A signing key is required when signing is.
+func (s SignatureInfo) requirements(signing bool) []error {
+	var errs []error
+	if signing || s.PrivateKey.Type != "" {
+		errs = append(errs, s.PrivateKey.requirements("private key", true)...)
+	}
+	for i, k := range s.PrivateKeys {
+		errs = append(errs, k.requirements(fmt.Sprintf("private key %d", i+1), true)...)
+	}
+	if s.PublicKey.Type != "" || s.PublicKey.HasKey() {
+		errs = append(errs, s.PublicKey.requirements("public key", false)...)
+	}
+	for i, k := range s.PublicKeys {
+		errs = append(errs, k.requirements(fmt.Sprintf("public key %d", i+1), false)...)
+	}
+	return errs
+}
+
+// requirements returns the problems found with the key, named name in the messages
+func (k KeyInfo) requirements(name string, private bool) []error {
+	if err := k.validate(); err != nil {
+		return []error{fmt.Errorf("%s: %w", name, err)}
+	}
+	switch k.Type {
+	case contracts.KeyPkcs11, contracts.KeyTpm, contracts.KeyAzureKeyVault, contracts.KeyVaultTransit:
+		// held by the key store
+		return nil
+	case contracts.KeyGcpKms:
+		if k.GcpKms.CredentialsFile != "" {
+			return []error{checkFile(name+" credentials", k.GcpKms.CredentialsFile)}
+		}
+		return nil
+	}
+	if k.Material != nil || k.Url != "" || k.Signer != nil {
+		return nil
+	}
+	b, err := readFile(name, k.Path)
+	if err != nil {
+		return []error{err}
+	}
+	if !keyForm(k.Type, b) {
+		return []error{fmt.Errorf("%w: %s in %s is not a %s key in a supported form", contracts.ErrConfigInvalid,
+			name, k.Path, k.Type)}
+	}
+	if block, _ := pem.Decode(b); private && block != nil && block.Type == "ENCRYPTED PRIVATE KEY" {
+		if _, err = k.ReadPassphrase(); err != nil {
+			return []error{fmt.Errorf("%w: %s in %s is encrypted: %w", contracts.ErrConfigInvalid, name, k.Path, err)}
+		}
+	}
+	return nil
+}
+
+// keyForm reports whether b is encoded as the built-in algorithm k reads keys: PEM or a JWK for P-256 keys, hex for
+// secp256k1 keys, and any of them for ed25519 keys. Keys of other algorithms are not checked.
+func keyForm(k contracts.KeyAlgorithm, b []byte) bool {
+	isPem := func() bool {
+		block, _ := pem.Decode(b)
+		return block != nil
+	}
+	isJwk := func() bool {
+		var fields map[string]any
+		return json.Unmarshal(b, &fields) == nil && fields["kty"] != nil
+	}
+	isHex := func() bool {
+		decoded, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(b)), "0x"))
+		return err == nil && len(decoded) > 0
+	}
+	switch k {
+	case contracts.KeyEd25519:
+		return isJwk() || isPem() || isHex()
+	case contracts.KeyEcdsaP256:
+		return isJwk() || isPem()
+	case contracts.KeyEcdsaSecp256k1:
+		return isHex()
+	}
+	return true
+}
+
+// requirements returns the problems found with the settings required by the stream type
+func (s StreamInfo) requirements() []error {
+	var errs []error
+	switch s.Type {
+	case contracts.MockStream, contracts.ConsoleStream:
+		return nil
+	case contracts.MqttStream:
+		cfg, ok := s.Config.(MqttConfig)
+		if !ok {
+			return []error{configMismatch(s)}
+		}
+		errs = append(errs, cfg.requirements()...)
+	case contracts.HederaStream:
+		cfg, ok := s.Config.(HederaConfig)
+		if !ok {
+			return []error{configMismatch(s)}
+		}
+		errs = append(errs, cfg.requirements()...)
+	case contracts.EthereumStream:
+		cfg, ok := s.Config.(EthereumConfig)
+		if !ok {
+			return []error{configMismatch(s)}
+		}
+		errs = append(errs, cfg.requirements()...)
+	case contracts.IotaStream:
+		cfg, ok := s.Config.(IotaConfig)
+		if !ok {
+			return []error{configMismatch(s)}
+		}
+		errs = appendErr(errs, requireProvider(s.Type, cfg.Provider, "http", "https"))
+	case contracts.ZmqStream:
+		cfg, ok := s.Config.(ZmqConfig)
+		if !ok {
+			return []error{configMismatch(s)}
+		}
+		errs = append(errs, cfg.requirements()...)
+	case contracts.UdsStream:
+		cfg, ok := s.Config.(UdsConfig)
+		if !ok {
+			return []error{configMismatch(s)}
+		}
+		if cfg.Path == "" {
+			errs = append(errs, fmt.Errorf("%w: stream %s requires a path", contracts.ErrConfigInvalid, s.Type))
+		}
+	case contracts.SyslogStream:
+		cfg, ok := s.Config.(SyslogConfig)
+		if !ok {
+			return []error{configMismatch(s)}
+		}
+		errs = appendErr(errs, requireProvider(s.Type, cfg.Provider, "tcp", "udp", "tls"))
+		if cfg.Facility < 0 || cfg.Facility > 23 {
+			errs = append(errs, fmt.Errorf("%w: invalid syslog facility %v", contracts.ErrConfigInvalid, cfg.Facility))
+		}
+		if cfg.Provider.Protocol == "tls" {
+			errs = append(errs, cfg.TLS.requirements("syslog tls")...)
+		}
+	case contracts.OtelStream:
+		cfg, ok := s.Config.(OtelConfig)
+		if !ok {
+			return []error{configMismatch(s)}
+		}
+		errs = appendErr(errs, requireProvider(s.Type, cfg.Provider, "http", "https"))
+		if cfg.Provider.Protocol == "https" {
+			errs = append(errs, cfg.TLS.requirements("otel tls")...)
+		}
+	case contracts.FluentdStream:
+		cfg, ok := s.Config.(FluentdConfig)
+		if !ok {
+			return []error{configMismatch(s)}
+		}
+		errs = appendErr(errs, requireProvider(s.Type, cfg.Provider, "tcp", "tls"))
+		if cfg.Provider.Protocol == "tls" {
+			errs = append(errs, cfg.TLS.requirements("fluentd tls")...)
+		}
+	}
+	for i, r := range s.Encryption.Recipients {
+		errs = appendErr(errs, checkHexFile(fmt.Sprintf("encryption recipient %d key", i+1), r.PublicKeyPath))
+	}
+	return errs
+}
+
+func configMismatch(s StreamInfo) error {
+	return fmt.Errorf("%w: stream %s cannot be configured with %T", contracts.ErrConfigInvalid, s.Type, s.Config)
+}
+
+// requireProvider checks that the endpoint of a stream is given, over one of the protocols supported by the stream if
+// they are listed
+func requireProvider(stream contracts.StreamType, p ServiceInfo, protocols ...string) error {
+	var problems []string
+	if p.Host == "" {
+		problems = append(problems, "a provider host")
+	}
+	if p.Port <= 0 || p.Port > 65535 {
+		problems = append(problems, "a provider port")
+	}
+	supported := len(protocols) == 0 && p.Protocol != ""
+	for _, protocol := range protocols {
+		supported = supported || p.Protocol == protocol
+	}
+	if !supported && len(protocols) == 0 {
+		problems = append(problems, "a provider protocol")
+	} else if !supported {
+		problems = append(problems, "a provider protocol among "+strings.Join(protocols, ", "))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: stream %s requires %s", contracts.ErrConfigInvalid, stream, strings.Join(problems, " and "))
+}
+
+func (m MqttConfig) requirements() []error {
+	var errs []error
+	errs = appendErr(errs, requireProvider(contracts.MqttStream, m.Provider))
+	if len(m.Topics) == 0 {
+		errs = append(errs, fmt.Errorf("%w: stream %s requires topics", contracts.ErrConfigInvalid, contracts.MqttStream))
+	}
+	if m.Qos < 0 || m.Qos > 2 {
+		errs = append(errs, fmt.Errorf("%w: invalid MQTT qos %v", contracts.ErrConfigInvalid, m.Qos))
+	}
+	for action, qos := range m.ActionQos {
+		if qos < 0 || qos > 2 {
+			errs = append(errs, fmt.Errorf("%w: invalid MQTT qos %v for action %s", contracts.ErrConfigInvalid, qos,
+				action))
+		}
+	}
+	switch m.ProtocolVersion {
+	case 0, 3, 4, 5:
+	default:
+		errs = append(errs, fmt.Errorf("%w: unsupported MQTT protocol version %v", contracts.ErrConfigInvalid,
+			m.ProtocolVersion))
+	}
+	switch m.Provider.Protocol {
+	case "ssl", "tls", "mqtts", "wss":
+		errs = append(errs, m.TLS.requirements("mqtt tls")...)
+	}
+	return errs
+}
+
+func (h HederaConfig) requirements() []error {
+	var errs []error
+	if !h.NetType.Validate() {
+		errs = append(errs, fmt.Errorf("%w: invalid NetType value provided %s", contracts.ErrConfigInvalid, h.NetType))
+	}
+	if !isAccountId(h.AccountId) {
+		errs = append(errs, fmt.Errorf("%w: hedera accountId %q is not of the form shard.realm.num",
+			contracts.ErrConfigInvalid, h.AccountId))
+	}
+	if h.PrivateKey == "" {
+		errs = appendErr(errs, checkFile("hedera private key", h.PrivateKeyPath))
+	}
+	if len(h.Topics) == 0 && !h.Topic.AutoCreate {
+		errs = append(errs, fmt.Errorf("%w: stream %s requires topics unless topic.autoCreate is set",
+			contracts.ErrConfigInvalid, contracts.HederaStream))
+	}
+	if h.Topic.AdminKeyPath != "" {
+		errs = appendErr(errs, checkFile("hedera topic admin key", h.Topic.AdminKeyPath))
+	}
+	if h.Topic.SubmitKeyPath != "" {
+		errs = appendErr(errs, checkFile("hedera topic submit key", h.Topic.SubmitKeyPath))
+	}
+	if h.ShouldBroadcastTopic {
+		for _, err := range h.BroadcastStream.requirements() {
+			errs = append(errs, fmt.Errorf("hedera broadcast stream: %w", err))
+		}
+	}
+	return errs
+}
+
+// isAccountId reports whether id is a Hedera account ID of the form shard.realm.num
+func isAccountId(id string) bool {
+	parts := strings.Split(id, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	for _, part := range parts {
+		if _, err := strconv.ParseUint(part, 10, 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (e EthereumConfig) requirements() []error {
+	var errs []error
+	if e.Endpoint == "" {
+		errs = append(errs, fmt.Errorf("%w: stream %s requires an endpoint", contracts.ErrConfigInvalid,
+			contracts.EthereumStream))
+	}
+	errs = appendErr(errs, checkHexFile("ethereum private key", e.PrivateKeyPath))
+	address, err := hex.DecodeString(strings.TrimPrefix(e.ContractAddress, "0x"))
+	if err != nil || len(address) != 20 {
+		errs = append(errs, fmt.Errorf("%w: invalid contract address %q", contracts.ErrConfigInvalid, e.ContractAddress))
+	}
+	if e.Method == "" {
+		errs = append(errs, fmt.Errorf("%w: stream %s requires a contract method", contracts.ErrConfigInvalid,
+			contracts.EthereumStream))
+	}
+	if e.AnchorMode != "" && !e.AnchorMode.Validate() {
+		errs = append(errs, fmt.Errorf("%w: invalid AnchorMode value provided %s", contracts.ErrConfigInvalid,
+			e.AnchorMode))
+	}
+	if e.GasPrice.Strategy != "" && !e.GasPrice.Strategy.Validate() {
+		errs = append(errs, fmt.Errorf("%w: invalid GasPriceStrategy value provided %s", contracts.ErrConfigInvalid,
+			e.GasPrice.Strategy))
+	}
+	return errs
+}
+
+func (z ZmqConfig) requirements() []error {
+	var errs []error
+	if !z.SocketType.Validate() {
+		errs = append(errs, fmt.Errorf("%w: invalid ZmqSocketType value provided %s", contracts.ErrConfigInvalid,
+			z.SocketType))
+	}
+	errs = appendErr(errs, requireProvider(contracts.ZmqStream, z.Provider, "tcp"))
+	if z.Curve.Enabled {
+		if z.Curve.PublicKey == "" {
+			errs = append(errs, fmt.Errorf("%w: zmq curve requires a publicKey", contracts.ErrConfigInvalid))
+		}
+		errs = appendErr(errs, checkFile("zmq curve secret key", z.Curve.SecretKeyPath))
+		if !z.Curve.Server && z.Curve.ServerKey == "" {
+			errs = append(errs, fmt.Errorf("%w: zmq curve client requires a serverKey", contracts.ErrConfigInvalid))
+		}
+	}
+	return errs
+}
+
+// requirements returns the problems found reading the files of the TLS settings, named name in the messages
+func (t TLSInfo) requirements(name string) []error {
+	var errs []error
+	if t.CaPath != "" {
+		errs = appendErr(errs, checkPemFile(name+" ca", t.CaPath))
+	}
+	if (t.CertPath == "") != (t.KeyPath == "") {
+		errs = append(errs, fmt.Errorf("%w: %s requires both certPath and keyPath for mutual TLS",
+			contracts.ErrConfigInvalid, name))
+	} else if t.CertPath != "" {
+		errs = appendErr(errs, checkPemFile(name+" certificate", t.CertPath))
+		errs = appendErr(errs, checkPemFile(name+" key", t.KeyPath))
+	}
+	return errs
+}
+
+// checkEnv checks that the environment variable holding the value named name is set
+func checkEnv(name, key string) error {
+	if _, ok := os.LookupEnv(key); !ok {
+		return fmt.Errorf("%w: %s environment variable %s is not set", contracts.ErrConfigInvalid, name, key)
+	}
+	return nil
+}
+
+// checkFile checks that the file holding the value named name can be read
+func checkFile(name, path string) error {
+	_, err := readFile(name, path)
+	return err
+}
+
+// checkHexFile checks that the file holding the value named name can be read and is hex encoded
+func checkHexFile(name, path string) error {
+	b, err := readFile(name, path)
+	if err != nil {
+		return err
+	}
+	if _, err = hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(b)), "0x")); err != nil {
+		return fmt.Errorf("%w: %s in %s is not hex encoded", contracts.ErrConfigInvalid, name, path)
+	}
+	return nil
+}
+
+// checkPemFile checks that the file holding the value named name can be read and is PEM encoded
+func checkPemFile(name, path string) error {
+	b, err := readFile(name, path)
+	if err != nil {
+		return err
+	}
+	if block, _ := pem.Decode(b); block == nil {
+		return fmt.Errorf("%w: %s in %s is not PEM encoded", contracts.ErrConfigInvalid, name, path)
+	}
+	return nil
+}
+
+func readFile(name, path string) ([]byte, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: %s requires a path", contracts.ErrConfigInvalid, name)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s cannot be read: %w", contracts.ErrConfigInvalid, name, err)
+	}
+	return b, nil
+}
@@ -16,7 +16,6 @@ package contracts
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/oklog/ulid/v2"
@@ -26,20 +25,52 @@ import (
 // aiding in the linkage of scores across different layers of the stack. For instance, in the "app" layer,
 // it is utilized to retrieve the commit SHA of the workload where the application is running,
 // which is instrumental in tracing the impact on the current layer's score from the lower layers.
+// It is the source of the tags of the app layer unless others are configured, see config.SdkInfo.TagSources.
 const TagEnvKey = "TAG"
 
+// TagFilePath is the well-known file through which a layer hands the tag to the layers it runs, when exchanged
+// through a file, see TagSourceFile
+const TagFilePath = "/var/run/alvarium/tag"
+
+// TagAnnotationKey is the well-known pod annotation through which a layer hands the tag to the layers running in the
+// pod, when exchanged through Kubernetes, see TagSourceKubernetes
+const TagAnnotationKey = "alvarium.project-alvarium.io/tag"
+
+// The versions of the Annotation schema. Consumers reject annotations of versions newer than they support rather than
+// silently dropping the fields they do not know.
+const (
+	// AnnotationVersion1 is the original schema, annotations without a version are of this schema
+	AnnotationVersion1 = 1
+	// AnnotationVersion2 adds Version, KeyId, ParentKey and PrevHash
+	AnnotationVersion2 = 2
+	// AnnotationVersion3 adds Error
+	AnnotationVersion3 = 3
+	// AnnotationVersion4 allows several values of Tag
+	AnnotationVersion4 = 4
+	// AnnotationVersion5 adds HostInfo
+	AnnotationVersion5 = 5
+	// AnnotationVersion is the schema of the annotations created by this SDK
+	AnnotationVersion = AnnotationVersion5
+)
+
 // Annotation represents an individual criterion of evaluation in regard to a piece of data
 type Annotation struct {
+	Version     int            `json:"version,omitempty"`   // Version identifies the schema of the annotation, see AnnotationVersion
 	Id          ulid.ULID      `json:"id,omitempty"`        // Id should probably be a ULID -- uniquely identifies the annotation itself
 	Key         string         `json:"key,omitempty"`       // Key is the hash value of the data being annotated
+	ParentKey   string         `json:"parentKey,omitempty"` // ParentKey is the hash value of the data the annotated data was derived from
+	PrevHash    string         `json:"prevHash,omitempty"`  // PrevHash links the previous annotation made by the same SDK instance, if chained
 	Hash        HashType       `json:"hash,omitempty"`      // Hash identifies which algorithm was used to construct the hash
 	Host        string         `json:"host,omitempty"`      // Host is the hostname of the node making the annotation
-	Tag         string         `json:"tag,omitempty"`       // Tag is the link between the current layer and the below layer
+	Tag         TagList        `json:"tag,omitempty"`       // Tag is the link between the current layer and the below layer
 	Layer       LayerType      `json:"layer,omitempty"`     // Layer is the layer where the annotation was produced
 	Kind        AnnotationType `json:"kind,omitempty"`      // Kind indicates what kind of annotation this is
+	KeyId       string         `json:"keyId,omitempty"`     // KeyId identifies the key that produced Signature, allowing keys to be rotated
 	Signature   string         `json:"signature,omitempty"` // Signature contains the signature of the party making the annotation
 	IsSatisfied bool           `json:"isSatisfied"`         // IsSatisfied indicates whether the criteria defining the annotation were fulfilled
+	Error       string         `json:"error,omitempty"`     // Error describes why the criteria could not be evaluated, the annotation is then unsatisfied
 	Timestamp   time.Time      `json:"timestamp,omitempty"` // Timestamp indicates when the annotation was created
+	HostInfo    *HostInfo      `json:"hostInfo,omitempty"`  // HostInfo describes the host that made the annotation, if configured
 }
 
 // AnnotationList is an envelope for zero to many annotations
@@ -47,23 +78,15 @@ type AnnotationList struct {
 	Items []Annotation `json:"items,omitempty"` // Items contains 0-many annotations
 }
 
-// getTagValue retrieves the value associated with the tag field for a given layer.
-func getTagValue(layer LayerType) string {
-	switch layer {
-	case Application:
-		return os.Getenv(TagEnvKey)
-	}
-	return ""
-}
-
-// NewAnnotation is the constructor for an Annotation instance.
+// NewAnnotation is the constructor for an Annotation instance. Its Tag is left to the annotator, which reads it from
+// the sources configured for the layer.
 func NewAnnotation(key string, hash HashType, host string, layer LayerType, kind AnnotationType, satisfied bool) Annotation {
 	return Annotation{
+		Version:     AnnotationVersion,
 		Id:          NewULID(),
 		Key:         key,
 		Hash:        hash,
 		Host:        host,
-		Tag:         getTagValue(layer),
 		Layer:       layer,
 		Kind:        kind,
 		IsSatisfied: satisfied,
@@ -73,16 +96,22 @@ func NewAnnotation(key string, hash HashType, host string, layer LayerType, kind
 
 func (a *Annotation) UnmarshalJSON(data []byte) (err error) {
 	type Alias struct {
+		Version     int
 		Id          ulid.ULID
 		Key         string
+		ParentKey   string
+		PrevHash    string
 		Hash        HashType
 		Host        string
-		Tag         string
+		Tag         TagList
 		Layer       LayerType
 		Kind        AnnotationType
+		KeyId       string
 		Signature   string
 		IsSatisfied bool
+		Error       string
 		Timestamp   time.Time
+		HostInfo    *HostInfo
 	}
 	x := Alias{}
 	// Error with unmarshaling
@@ -90,6 +119,10 @@ func (a *Annotation) UnmarshalJSON(data []byte) (err error) {
 		return err
 	}
 
+	if err = checkVersion(x.Version); err != nil {
+		return err
+	}
+
 	if !x.Hash.Validate() {
 		return fmt.Errorf("invalid HashType value provided %s", x.Hash)
 	}
@@ -98,15 +131,74 @@ func (a *Annotation) UnmarshalJSON(data []byte) (err error) {
 		return fmt.Errorf("invalid AnnotationType value provided %s", x.Kind)
 	}
 
+	a.Version = x.Version
 	a.Id = x.Id
 	a.Key = x.Key
+	a.ParentKey = x.ParentKey
+	a.PrevHash = x.PrevHash
 	a.Hash = x.Hash
 	a.Host = x.Host
 	a.Tag = x.Tag
 	a.Layer = x.Layer
 	a.Kind = x.Kind
+	a.KeyId = x.KeyId
 	a.Signature = x.Signature
 	a.IsSatisfied = x.IsSatisfied
+	a.Error = x.Error
 	a.Timestamp = x.Timestamp
+	a.HostInfo = x.HostInfo
+	return nil
+}
+
+// SchemaVersion returns the version of the schema of the annotation, taking annotations without a version to be of the
+// original schema
+func (a Annotation) SchemaVersion() int {
+	if a.Version == 0 {
+		return AnnotationVersion1
+	}
+	return a.Version
+}
+
+// ConvertTo returns the annotation in the given schema version. Converting down fails rather than drop the fields
+// the older schema does not define. The version is covered by the signature, so a converted annotation must be signed
+// again before it can be verified.
+func (a Annotation) ConvertTo(version int) (Annotation, error) {
+	if err := checkVersion(version); err != nil {
+		return Annotation{}, err
+	}
+	if version < AnnotationVersion5 && a.HostInfo != nil {
+		return Annotation{}, fmt.Errorf("%w: annotation %s uses fields of schema version %d", ErrUnsupportedVersion,
+			a.Id, AnnotationVersion5)
+	}
+	if version < AnnotationVersion4 && len(a.Tag) > 1 {
+		return Annotation{}, fmt.Errorf("%w: annotation %s uses fields of schema version %d", ErrUnsupportedVersion,
+			a.Id, AnnotationVersion4)
+	}
+	if version < AnnotationVersion3 && a.Error != "" {
+		return Annotation{}, fmt.Errorf("%w: annotation %s uses fields of schema version %d", ErrUnsupportedVersion,
+			a.Id, AnnotationVersion3)
+	}
+	if version < AnnotationVersion2 {
+		if a.KeyId != "" || a.ParentKey != "" || a.PrevHash != "" {
+			return Annotation{}, fmt.Errorf("%w: annotation %s uses fields of schema version %d", ErrUnsupportedVersion,
+				a.Id, AnnotationVersion2)
+		}
+		// the original schema has no version field
+		version = 0
+	}
+	a.Version = version
+	return a, nil
+}
+
+// CheckVersion returns an error if the annotation is of a schema version newer than this SDK supports
+func (a Annotation) CheckVersion() error {
+	return checkVersion(a.Version)
+}
+
+func checkVersion(version int) error {
+	if version < 0 || version > AnnotationVersion {
+		return fmt.Errorf("%w: annotation schema version %d, the newest supported is %d", ErrUnsupportedVersion,
+			version, AnnotationVersion)
+	}
 	return nil
 }
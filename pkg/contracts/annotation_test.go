@@ -0,0 +1,144 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package contracts
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotation_UnmarshalVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expected    int
+		expectError bool
+	}{
+		{"original schema", `{"id":"01M4ZJ769XMY55M6NJN99DPEWG","hash":"sha256","kind":"src"}`, AnnotationVersion1,
+			false},
+		{"keyed schema", `{"version":2,"hash":"sha256","kind":"src","keyId":"2024-06"}`, AnnotationVersion2, false},
+		{"errored schema", `{"version":3,"hash":"sha256","kind":"tpm","error":"no tpm"}`, AnnotationVersion3, false},
+		{"tagged schema", `{"version":4,"hash":"sha256","kind":"src","tag":["4b825dc","sha256:e3b0c442"]}`,
+			AnnotationVersion4, false},
+		{"current schema", `{"version":5,"hash":"sha256","kind":"src","hostInfo":{"region":"us-east-1"}}`,
+			AnnotationVersion5, false},
+		{"newer schema", `{"version":6,"hash":"sha256","kind":"src"}`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a Annotation
+			err := json.Unmarshal([]byte(tt.data), &a)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				assert.True(t, errors.Is(err, ErrUnsupportedVersion))
+				return
+			}
+			assert.Equal(t, tt.expected, a.SchemaVersion())
+		})
+	}
+}
+
+func TestAnnotation_ConvertTo(t *testing.T) {
+	current := NewAnnotation("foo", SHA256Hash, "host", Host, AnnotationSource, true)
+	keyed := current
+	keyed.KeyId = "2024-06"
+	errored := current
+	errored.Error = "no tpm"
+	tagged := current
+	tagged.Tag = TagList{"4b825dc", "sha256:e3b0c442"}
+	described := current
+	described.HostInfo = &HostInfo{Region: "us-east-1"}
+
+	tests := []struct {
+		name        string
+		annotation  Annotation
+		version     int
+		expected    int
+		expectError bool
+	}{
+		{"current to current", current, AnnotationVersion5, AnnotationVersion5, false},
+		{"current to tagged", current, AnnotationVersion4, AnnotationVersion4, false},
+		{"described to tagged", described, AnnotationVersion4, 0, true},
+		{"current to errored", current, AnnotationVersion3, AnnotationVersion3, false},
+		{"tagged to errored", tagged, AnnotationVersion3, 0, true},
+		{"current to keyed", current, AnnotationVersion2, AnnotationVersion2, false},
+		{"errored to keyed", errored, AnnotationVersion2, 0, true},
+		{"current to original", current, AnnotationVersion1, 0, false},
+		{"keyed to original", keyed, AnnotationVersion1, 0, true},
+		{"newer than supported", current, AnnotationVersion + 1, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := tt.annotation.ConvertTo(tt.version)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				assert.True(t, errors.Is(err, ErrUnsupportedVersion))
+				return
+			}
+			assert.Equal(t, tt.expected, converted.Version)
+
+			// the original schema has no version field
+			b, _ := json.Marshal(converted)
+			assert.Equal(t, tt.expected != 0, containsVersion(b))
+		})
+	}
+}
+
+func containsVersion(b []byte) bool {
+	var fields map[string]any
+	_ = json.Unmarshal(b, &fields)
+	_, ok := fields["version"]
+	return ok
+}
+
+func TestTagList_JSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expected    TagList
+		expectError bool
+	}{
+		{"empty", `""`, nil, false},
+		{"single", `"4b825dc"`, TagList{"4b825dc"}, false},
+		{"multiple", `["4b825dc","sha256:e3b0c442"]`, TagList{"4b825dc", "sha256:e3b0c442"}, false},
+		{"invalid", `42`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tags TagList
+			err := json.Unmarshal([]byte(tt.data), &tags)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.expected, tags)
+
+			// a single tag is encoded as it was before annotations carried several
+			b, _ := json.Marshal(tags)
+			assert.JSONEq(t, tt.data, string(b))
+		})
+	}
+}
+
+func TestParseTagList(t *testing.T) {
+	assert.Nil(t, ParseTagList(""))
+	assert.Equal(t, TagList{"4b825dc"}, ParseTagList("4b825dc"))
+	assert.Equal(t, TagList{"4b825dc", "sha256:e3b0c442", "deploy-7"},
+		ParseTagList(" 4b825dc, sha256:e3b0c442\n\ndeploy-7\n"))
+}
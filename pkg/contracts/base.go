@@ -14,21 +14,29 @@
 package contracts
 
 import (
-	"github.com/oklog/ulid/v2"
-	"math/rand"
+	"crypto/rand"
+	"sync"
 	"time"
+
+	"github.com/oklog/ulid/v2"
 )
 
-// NewULID is a convenience function for generating ULIDs where necessary.
-// It is based on the example showing the "quick" method for initializing the algorithm's entropy parameter.
-// https://github.com/oklog/ulid/blob/c6bb9e1d94a82e71dfd7ff279aa6cea7c52779bb/cmd/ulid/main.go#L67
-// As described on that page, quick means "when generating, use non-crypto-grade entropy".
-func NewULID() ulid.ULID {
-	seed := time.Now().UnixNano()
-	source := rand.NewSource(seed)
-	entropy := rand.New(source)
+var (
+	entropyMu sync.Mutex
+	entropy   = ulid.Monotonic(rand.Reader, 0)
+)
 
-	id, _ := ulid.New(ulid.Timestamp(time.Now()), entropy)
+// NewULID is a convenience function for generating ULIDs where necessary. Entropy is drawn from a cryptographically
+// secure source and is monotonic, so that ULIDs generated within the same millisecond still sort in the order they
+// were generated and never collide.
+func NewULID() ulid.ULID {
+	entropyMu.Lock()
+	defer entropyMu.Unlock()
 
+	id, err := ulid.New(ulid.Timestamp(time.Now()), entropy)
+	if err != nil {
+		// The entropy of the millisecond is exhausted, which takes 2^80 ULIDs, or the source failed
+		return ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader)
+	}
 	return id
 }
@@ -13,12 +13,29 @@
  *******************************************************************************/
 package contracts
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ContentType identifies the serialization of data, including publish wrappers and the AnnotationList they carry
 type ContentType string
 
 const (
-	ContentTypeJSON ContentType = "application/json"
+	ContentTypeJSON     ContentType = "application/json"
+	ContentTypeCBOR     ContentType = "application/cbor"       // Deterministically encoded, see RFC 8949 section 4.2
+	ContentTypeProtobuf ContentType = "application/x-protobuf" // Encoded as described by pkg/message/alvarium.proto
 )
 
+func (c ContentType) Validate() bool {
+	if c == ContentTypeJSON || c == ContentTypeCBOR || c == ContentTypeProtobuf {
+		return true
+	}
+	return false
+}
+
 type NetType string
 
 const (
@@ -39,11 +56,52 @@ type HashType string
 const (
 	MD5Hash    HashType = "md5"
 	SHA256Hash HashType = "sha256"
+	SHA3Hash   HashType = "sha3-256"
+	BLAKE3Hash HashType = "blake3"
+	HMACHash   HashType = "hmac-sha256"   // Keyed hash, see HashInfo for how the key is provided
+	MerkleHash HashType = "merkle-sha256" // Keys are Merkle roots over one or more payloads, see pkg/merkle
+	TreeHash   HashType = "sha256-tree"   // Keys are Merkle roots over fixed size chunks of a payload, hashed in parallel
 	NoHash     HashType = "none"
 )
 
+// Keyed indicates whether the hash type requires a secret key
+func (t HashType) Keyed() bool {
+	return t == HMACHash
+}
+
 func (t HashType) Validate() bool {
-	if t == MD5Hash || t == SHA256Hash || t == NoHash {
+	if t == MD5Hash || t == SHA256Hash || t == SHA3Hash || t == BLAKE3Hash ||
+		t == HMACHash || t == MerkleHash || t == TreeHash || t == NoHash {
+		return true
+	}
+	return false
+}
+
+// HashEncoding determines how a derived hash is represented in an annotation Key
+type HashEncoding string
+
+const (
+	HexEncoding       HashEncoding = "hex"       // Plain hex digest, the default
+	MultihashEncoding HashEncoding = "multihash" // Self-describing multihash in base32 multibase form
+)
+
+func (e HashEncoding) Validate() bool {
+	if e == HexEncoding || e == MultihashEncoding {
+		return true
+	}
+	return false
+}
+
+// Canonicalization determines how payloads are normalized before they are hashed, and annotations before they are
+// signed
+type Canonicalization string
+
+const (
+	JCSCanonicalization Canonicalization = "jcs" // JSON Canonicalization Scheme (RFC 8785)
+)
+
+func (c Canonicalization) Validate() bool {
+	if c == JCSCanonicalization {
 		return true
 	}
 	return false
@@ -52,11 +110,61 @@ func (t HashType) Validate() bool {
 type KeyAlgorithm string
 
 const (
-	KeyEd25519 KeyAlgorithm = "ed25519"
+	KeyEd25519        KeyAlgorithm = "ed25519"
+	KeyEcdsaP256      KeyAlgorithm = "ecdsa-p256"     // Raw P-256 keys in PEM form, see KeyInfo
+	KeyEcdsaSecp256k1 KeyAlgorithm = "secp256k1"      // Hex encoded keys as used by Hedera and Ethereum, see KeyInfo
+	KeyPkcs11         KeyAlgorithm = "pkcs11"         // P-256 keys held by a hardware security module, see KeyInfo
+	KeyTpm            KeyAlgorithm = "tpm"            // P-256 keys persisted in a TPM 2.0 device, see KeyInfo
+	KeyAzureKeyVault  KeyAlgorithm = "azure-keyvault" // P-256 keys held by Azure Key Vault, see KeyInfo
+	KeyGcpKms         KeyAlgorithm = "gcp-kms"        // P-256 keys held by Google Cloud KMS, see KeyInfo
+	KeyVaultTransit   KeyAlgorithm = "vault-transit"  // Keys of any type held by a HashiCorp Vault transit engine, see KeyInfo
 )
 
+// addedKeyAlgorithms holds the algorithms made valid by AddKeyAlgorithm
+var addedKeyAlgorithms sync.Map
+
+// AddKeyAlgorithm makes Validate accept an algorithm implemented outside this module. It is called by
+// factories.RegisterKeyAlgorithm, which also supplies the implementation.
+func AddKeyAlgorithm(k KeyAlgorithm) {
+	addedKeyAlgorithms.Store(k, struct{}{})
+}
+
 func (k KeyAlgorithm) Validate() bool {
-	if k == KeyEd25519 {
+	if k == KeyEd25519 || k == KeyEcdsaP256 || k == KeyEcdsaSecp256k1 || k == KeyPkcs11 ||
+		k == KeyTpm || k == KeyAzureKeyVault || k == KeyGcpKms || k == KeyVaultTransit {
+		return true
+	}
+	_, ok := addedKeyAlgorithms.Load(k)
+	return ok
+}
+
+// SignatureEncoding determines how ECDSA signatures are serialized
+type SignatureEncoding string
+
+const (
+	DERSignature         SignatureEncoding = "der"         // ASN.1 DER, the default
+	CompactSignature     SignatureEncoding = "compact"     // 64 byte r || s, as used by Hedera
+	RecoverableSignature SignatureEncoding = "recoverable" // 65 byte r || s || v allowing the public key to be recovered, as used by Ethereum
+)
+
+func (e SignatureEncoding) Validate() bool {
+	if e == DERSignature || e == CompactSignature || e == RecoverableSignature {
+		return true
+	}
+	return false
+}
+
+// SignatureFormat determines how a signature is represented in an annotation
+type SignatureFormat string
+
+const (
+	RawFormat  SignatureFormat = "raw"  // Signature provider output as is, the default
+	JWSFormat  SignatureFormat = "jws"  // Compact JWS (RFC 7515) whose header carries alg and kid
+	COSEFormat SignatureFormat = "cose" // Base64url COSE_Sign1 (RFC 9052) with detached payload, for constrained devices
+)
+
+func (f SignatureFormat) Validate() bool {
+	if f == RawFormat || f == JWSFormat || f == COSEFormat {
 		return true
 	}
 	return false
@@ -65,20 +173,261 @@ func (k KeyAlgorithm) Validate() bool {
 type StreamType string
 
 const (
-	ConsoleStream StreamType = "console"
-	MockStream    StreamType = "mock"
-	MqttStream    StreamType = "mqtt"
-	PravegaStream StreamType = "pravega" // Currently unsupported but indicating extension point
-	HederaStream  StreamType = "hedera"
+	ConsoleStream  StreamType = "console"
+	MockStream     StreamType = "mock"
+	MqttStream     StreamType = "mqtt"
+	PravegaStream  StreamType = "pravega" // Currently unsupported but indicating extension point
+	HederaStream   StreamType = "hedera"
+	EthereumStream StreamType = "ethereum"
+	IotaStream     StreamType = "iota"
+	ZmqStream      StreamType = "zeromq"
+	UdsStream      StreamType = "uds"
+	SyslogStream   StreamType = "syslog"
+	OtelStream     StreamType = "otel"
+	FluentdStream  StreamType = "fluentd"
 )
 
 func (t StreamType) Validate() bool {
-	if t == MockStream || t == MqttStream || t == PravegaStream || t == ConsoleStream || t == HederaStream {
+	if t == MockStream || t == MqttStream || t == PravegaStream || t == ConsoleStream || t == HederaStream ||
+		t == EthereumStream || t == IotaStream || t == ZmqStream ||
+		t == UdsStream || t == SyslogStream || t == OtelStream || t == FluentdStream {
 		return true
 	}
 	return false
 }
 
+// ZmqSocketType identifies the ZeroMQ messaging pattern used to distribute annotations
+type ZmqSocketType string
+
+const (
+	ZmqPub  ZmqSocketType = "pub"
+	ZmqPush ZmqSocketType = "push"
+)
+
+func (z ZmqSocketType) Validate() bool {
+	if z == ZmqPub || z == ZmqPush {
+		return true
+	}
+	return false
+}
+
+// AnchorMode determines what is written to a ledger when anchoring annotations
+type AnchorMode string
+
+const (
+	AnchorHash     AnchorMode = "hash"     // Only a digest of the publish wrapper is written
+	AnchorEnvelope AnchorMode = "envelope" // The full publish wrapper is written
+)
+
+func (m AnchorMode) Validate() bool {
+	if m == AnchorHash || m == AnchorEnvelope {
+		return true
+	}
+	return false
+}
+
+// GasPriceStrategy determines how the gas price of an EVM transaction is derived
+type GasPriceStrategy string
+
+const (
+	GasPriceNetwork GasPriceStrategy = "network" // Legacy transaction priced from the node's suggestion
+	GasPriceFixed   GasPriceStrategy = "fixed"   // Legacy transaction priced from configuration
+	GasPriceDynamic GasPriceStrategy = "dynamic" // EIP-1559 transaction priced from the base fee and suggested tip
+)
+
+func (g GasPriceStrategy) Validate() bool {
+	if g == GasPriceNetwork || g == GasPriceFixed || g == GasPriceDynamic {
+		return true
+	}
+	return false
+}
+
+// DropPolicy determines which message is discarded when a stream buffer is full
+type DropPolicy string
+
+const (
+	DropOldest DropPolicy = "oldest" // The oldest buffered message is discarded to make room
+	DropNewest DropPolicy = "newest" // The incoming message is discarded
+)
+
+func (d DropPolicy) Validate() bool {
+	if d == DropOldest || d == DropNewest {
+		return true
+	}
+	return false
+}
+
+// RateLimitPolicy determines what happens to a publish that exceeds the configured rate
+type RateLimitPolicy string
+
+const (
+	RateLimitBlock RateLimitPolicy = "block" // The publish waits until the rate allows it
+	RateLimitDrop  RateLimitPolicy = "drop"  // The publish is rejected with an error
+)
+
+func (r RateLimitPolicy) Validate() bool {
+	if r == RateLimitBlock || r == RateLimitDrop {
+		return true
+	}
+	return false
+}
+
+// BackpressurePolicy determines what happens to asynchronous SDK work submitted while the work queue is full
+type BackpressurePolicy string
+
+const (
+	BackpressureBlock BackpressurePolicy = "block" // The caller waits for room in the queue
+	BackpressureDrop  BackpressurePolicy = "drop"  // The work is discarded and an error is logged
+)
+
+func (b BackpressurePolicy) Validate() bool {
+	if b == BackpressureBlock || b == BackpressureDrop {
+		return true
+	}
+	return false
+}
+
+// PipelinePolicy determines what the SDK does when an annotator fails or makes an unsatisfied annotation
+type PipelinePolicy string
+
+const (
+	PolicyContinue PipelinePolicy = "continue" // The annotations of the other annotators are published
+	PolicyAbort    PipelinePolicy = "abort"    // The remaining annotators are skipped and nothing is published
+	PolicyAnnotate PipelinePolicy = "annotate" // A failure is published as an unsatisfied annotation recording the error
+)
+
+func (p PipelinePolicy) Validate() bool {
+	if p == PolicyContinue || p == PolicyAbort || p == PolicyAnnotate {
+		return true
+	}
+	return false
+}
+
+// IdType identifies the generator of the identifiers of annotations
+type IdType string
+
+const (
+	ULIDId          IdType = "ulid"          // Lexicographically sortable identifiers with monotonic entropy, the default
+	UUIDv7Id        IdType = "uuidv7"        // Time-ordered RFC 9562 version 7 UUIDs
+	DeterministicId IdType = "deterministic" // Identifiers derived from the annotation, stable when it is republished
+)
+
+func (i IdType) Validate() bool {
+	if i == ULIDId || i == UUIDv7Id || i == DeterministicId {
+		return true
+	}
+	return false
+}
+
+// TagSourceType identifies where the Tag linking the annotations of a layer to those of the layer below is exchanged
+type TagSourceType string
+
+const (
+	TagSourceEnv        TagSourceType = "env"     // An environment variable, TagEnvKey by default
+	TagSourceFile       TagSourceType = "file"    // A file holding the tag, TagFilePath by default
+	TagSourceKubernetes TagSourceType = "k8s"     // An annotation of the pod, TagAnnotationKey by default
+	TagSourceCommand    TagSourceType = "command" // The output of a command, such as git rev-parse HEAD, read only
+	TagSourceStatic     TagSourceType = "static"  // A value set in the configuration, read only
+	TagSourceGit        TagSourceType = "git"     // The commit SHA HEAD of a git repository points to, read only
+	TagSourceEC2        TagSourceType = "ec2"     // An item of the EC2 instance metadata, its instance ID by default, read only
+	TagSourceGCE        TagSourceType = "gce"     // An item of the GCE instance metadata, its instance ID by default, read only
+	TagSourceAzure      TagSourceType = "azure"   // An item of the Azure instance metadata, its VM ID by default, read only
+)
+
+func (t TagSourceType) Validate() bool {
+	switch t {
+	case TagSourceEnv, TagSourceFile, TagSourceKubernetes, TagSourceCommand, TagSourceStatic, TagSourceGit,
+		TagSourceEC2, TagSourceGCE, TagSourceAzure:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsCloud reports whether the tags are read from the instance metadata of a cloud provider
+func (t TagSourceType) IsCloud() bool {
+	return t == TagSourceEC2 || t == TagSourceGCE || t == TagSourceAzure
+}
+
+// EnrichmentField identifies an attribute of the host describing annotations, see HostInfo
+type EnrichmentField string
+
+const (
+	EnrichAddresses EnrichmentField = "ip"       // The IP addresses of the network interfaces of the host
+	EnrichMACs      EnrichmentField = "mac"      // The hardware addresses of the network interfaces of the host
+	EnrichRegion    EnrichmentField = "region"   // The cloud region the host runs in
+	EnrichInstance  EnrichmentField = "instance" // The identifier of the cloud instance
+	EnrichOs        EnrichmentField = "os"       // The release of the operating system
+)
+
+func (e EnrichmentField) Validate() bool {
+	switch e {
+	case EnrichAddresses, EnrichMACs, EnrichRegion, EnrichInstance, EnrichOs:
+		return true
+	default:
+		return false
+	}
+}
+
+// EnrichmentTarget identifies where the attributes of the host are attached
+type EnrichmentTarget string
+
+const (
+	EnrichAnnotation EnrichmentTarget = "annotation" // Each annotation, covered by its signature, the default
+	EnrichWrapper    EnrichmentTarget = "wrapper"    // The publish wrapper, once per publish
+)
+
+func (e EnrichmentTarget) Validate() bool {
+	return e == EnrichAnnotation || e == EnrichWrapper
+}
+
+// ContentEncoding identifies the compression applied to the content of a publish wrapper
+type ContentEncoding string
+
+const (
+	GzipEncoding ContentEncoding = "gzip"
+	ZstdEncoding ContentEncoding = "zstd"
+)
+
+func (c ContentEncoding) Validate() bool {
+	if c == GzipEncoding || c == ZstdEncoding {
+		return true
+	}
+	return false
+}
+
+// DecodeMode selects how strictly received annotations and wrappers are decoded
+type DecodeMode string
+
+const (
+	// LenientDecoding accepts unknown and duplicate fields, so that producers read messages of newer SDKs and their
+	// own output alike
+	LenientDecoding DecodeMode = "lenient"
+	// StrictDecoding rejects unknown and duplicate fields, invalid identifiers and timestamps, and messages exceeding
+	// the size limits, for ingestion services reading untrusted input
+	StrictDecoding DecodeMode = "strict"
+)
+
+func (m DecodeMode) Validate() bool {
+	if m == LenientDecoding || m == StrictDecoding {
+		return true
+	}
+	return false
+}
+
+// ConfigFormat identifies the serialization of a configuration file, see config.Load
+type ConfigFormat string
+
+const (
+	ConfigJSON ConfigFormat = "json"
+	ConfigYAML ConfigFormat = "yaml"
+	ConfigTOML ConfigFormat = "toml"
+)
+
+func (f ConfigFormat) Validate() bool {
+	return f == ConfigJSON || f == ConfigYAML || f == ConfigTOML
+}
+
 type AnnotationType string
 
 const (
@@ -92,15 +441,89 @@ const (
 	AnnotationChecksum      AnnotationType = "checksum"
 	AnnotationVulnerability AnnotationType = "vulnerability"
 	AnnotationSBOM          AnnotationType = "sbom"
+
+	// AnnotationPKIGrpc validates the signature sent in the metadata of a gRPC call, as AnnotationPKIHttp does for
+	// HTTP requests
+	AnnotationPKIGrpc AnnotationType = "pki-grpc"
+	// AnnotationSourceHttp gives the provenance of data fetched from an upstream API, whose signed response verifies
+	AnnotationSourceHttp AnnotationType = "src-http"
 )
 
-func (t AnnotationType) Validate() bool {
-	switch t {
-	case AnnotationPKI, AnnotationTLS, AnnotationTPM, AnnotationSource, AnnotationPKIHttp, AnnotationSourceCode, AnnotationChecksum, AnnotationVulnerability:
+// AnnotationTypeInfo describes a kind of annotation
+type AnnotationTypeInfo struct {
+	Description string    // Description says what annotations of the kind attest
+	Layer       LayerType // Layer is the layer annotations of the kind are made in by default, if any
+}
+
+// annotationTypes holds the valid annotation types along with their AnnotationTypeInfo, see RegisterAnnotationType
+var annotationTypes sync.Map
+
+func init() {
+	builtin := map[AnnotationType]AnnotationTypeInfo{
+		AnnotationPKI:           {"the data carries a valid signature", Application},
+		AnnotationPKIHttp:       {"the HTTP request carries a valid signature", Application},
+		AnnotationPKIGrpc:       {"the gRPC call carries a valid signature in its metadata", Application},
+		AnnotationSource:        {"the provenance of the data, made where it was produced", Application},
+		AnnotationSourceHttp:    {"the provenance of data fetched from an upstream API whose signed response verifies", Application},
+		AnnotationTLS:           {"the data was received over TLS", Application},
+		AnnotationTPM:           {"the host has a TPM 2.0 managing its secrets", Host},
+		AnnotationSourceCode:    {"the source code was reviewed before it was built", CiCd},
+		AnnotationChecksum:      {"the checksum of the build artifact matches", CiCd},
+		AnnotationVulnerability: {"the build artifact has no known vulnerabilities", CiCd},
+	}
+	for t, info := range builtin {
+		annotationTypes.Store(t, info)
+	}
+}
+
+// RegisterAnnotationType makes Validate accept a kind of annotation made outside this module, described by info, so
+// that its annotations can be unmarshalled, it can be named in configuration and its default layer is known when
+// scoring. factories.RegisterAnnotatorWithInfo registers the kind along with the annotator making it. Registering a
+// kind that already exists, or with a layer that does not, is an error.
+func RegisterAnnotationType(t AnnotationType, info AnnotationTypeInfo) error {
+	if t == "" {
+		return errors.New("annotation type is required")
+	}
+	if info.Layer != "" && !info.Layer.Validate() {
+		return fmt.Errorf("invalid LayerType %s for annotation type %s", info.Layer, t)
+	}
+	if _, loaded := annotationTypes.LoadOrStore(t, info); loaded {
+		return fmt.Errorf("annotation type %s already exists", t)
+	}
+	return nil
+}
+
+// AddAnnotationType makes Validate accept the kind of annotation made by an annotator implemented outside this
+// module, so that its annotations can be unmarshalled and it can be named in configuration. It is called by
+// factories.RegisterAnnotator. Unlike RegisterAnnotationType, it describes nothing and adding a kind that exists has
+// no effect.
+func AddAnnotationType(t AnnotationType) {
+	annotationTypes.LoadOrStore(t, AnnotationTypeInfo{})
+}
+
+// AnnotationTypes returns the valid annotation types, built-in and registered, in lexical order
+func AnnotationTypes() []AnnotationType {
+	var types []AnnotationType
+	annotationTypes.Range(func(k, _ any) bool {
+		types = append(types, k.(AnnotationType))
 		return true
-	default:
-		return false
+	})
+	slices.Sort(types)
+	return types
+}
+
+// Info returns the description of the annotation type, false if it is not valid
+func (t AnnotationType) Info() (AnnotationTypeInfo, bool) {
+	info, ok := annotationTypes.Load(t)
+	if !ok {
+		return AnnotationTypeInfo{}, false
 	}
+	return info.(AnnotationTypeInfo), true
+}
+
+func (t AnnotationType) Validate() bool {
+	_, ok := annotationTypes.Load(t)
+	return ok
 }
 
 type DerivedComponent string
@@ -113,22 +536,63 @@ const (
 	Path        DerivedComponent = "@path"
 	Query       DerivedComponent = "@query"
 	QueryParams DerivedComponent = "@query-params"
+	Status      DerivedComponent = "@status" // Status is the status code of a response
+
+	// RequestTarget and QueryParam are only signed in the format of RFC 9421, where QueryParams is signed as a
+	// QueryParam component for each query parameter
+	RequestTarget DerivedComponent = "@request-target"
+	QueryParam    DerivedComponent = "@query-param" // QueryParam is the query parameter given by its name parameter
 )
 
 const (
 	// HttpRequestKey is the key used to reference the value within the incoming Context that corresponds to the request we need to validate.
 	HttpRequestKey  string = "HttpRequestKey"
 	ContentLength   string = "Content-Length"
+	ContentDigest   string = "Content-Digest"
 	HttpContentType string = "Content-Type"
+
+	// DataHashKey is the key used to reference the value within the incoming Context that corresponds to a hash of the
+	// data that has already been derived, such as when the data was streamed rather than held in memory.
+	DataHashKey string = "DataHashKey"
+	// HttpResponseKey is the key used to reference the value within the incoming Context that corresponds to a signed
+	// response to validate, taking precedence over HttpRequestKey.
+	HttpResponseKey string = "HttpResponseKey"
+	// TenantKey is the key used to reference the value within the incoming Context that identifies the tenant on whose
+	// behalf data is annotated, selecting the tenant's salt when hashing.
+	TenantKey string = "TenantKey"
+	// ParentHashKey is the key used to reference the value within the incoming Context that corresponds to the hash of
+	// the data from which the annotated data was derived, recorded as the ParentKey of the annotations.
+	ParentHashKey string = "ParentHashKey"
+	// ChainKey is the key used to reference the value within the incoming Context that holds the chain the annotations
+	// are appended to, see SdkInfo.Chain.
+	ChainKey string = "ChainKey"
+	// IdGeneratorKey is the key used to reference the value within the incoming Context that holds the generator of
+	// the identifiers of the annotations, see interfaces.IdGenerator.
+	IdGeneratorKey string = "IdGeneratorKey"
+	// ClockKey is the key used to reference the value within the incoming Context that holds the clock the timestamps
+	// of the annotations are read from, see interfaces.Clock.
+	ClockKey string = "ClockKey"
+	// TagValueGetterKey is the key used to reference the value within the incoming Context that holds the getter of
+	// the Tag of the annotations, see interfaces.TagValueGetter.
+	TagValueGetterKey string = "TagValueGetterKey"
+	// EncodingsKey is the key used to reference the value within the incoming Context that keeps the encodings of the
+	// annotations signed during an operation, so that they are published without being encoded again.
+	EncodingsKey string = "EncodingsKey"
+	// HostInfoKey is the key used to reference the value within the incoming Context that holds the attributes of the
+	// host attached to the annotations, see HostInfo.
+	HostInfoKey string = "HostInfoKey"
 )
 
 func (d DerivedComponent) Validate() bool {
-	if d == Method || d == Authority || d == TargetURI || d == Scheme || d == Path || d == Query || d == QueryParams {
+	if d == Method || d == Authority || d == TargetURI || d == Scheme || d == Path || d == Query || d == QueryParams ||
+		d == Status || d == RequestTarget || d == QueryParam {
 		return true
 	}
 	return false
 }
 
+// LayerType is the layer of the stack where an annotation is produced. Deployments add layers of their own, such as a
+// gateway or the network, with AddLayerType.
 type LayerType string
 
 const (
@@ -138,11 +602,35 @@ const (
 	Host        LayerType = "host"
 )
 
+// addedLayerTypes holds the layers made valid by AddLayerType
+var addedLayerTypes sync.Map
+
+// AddLayerType makes Validate accept a layer specific to a deployment, such as "gateway" or "network", so that
+// annotations can be made in it and unmarshalled strictly. It is called for the layers declared in the configuration
+// of the SDK, see config.SdkInfo.Layers.
+func AddLayerType(l LayerType) {
+	addedLayerTypes.Store(l, struct{}{})
+}
+
+// LayerTypes returns the built-in layers, from the application down to the host, followed by those added with
+// AddLayerType in lexical order
+func LayerTypes() []LayerType {
+	layers := []LayerType{Application, CiCd, Os, Host}
+	var added []LayerType
+	addedLayerTypes.Range(func(k, _ any) bool {
+		added = append(added, k.(LayerType))
+		return true
+	})
+	slices.Sort(added)
+	return append(layers, added...)
+}
+
 func (l LayerType) Validate() bool {
 	switch l {
 	case Application, CiCd, Os, Host:
 		return true
 	default:
-		return false
+		_, ok := addedLayerTypes.Load(l)
+		return ok
 	}
 }
@@ -0,0 +1,86 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package contracts
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterAnnotationType(t *testing.T) {
+	const kind AnnotationType = "attestation"
+	info := AnnotationTypeInfo{Description: "the firmware was measured at boot", Layer: Host}
+	t.Cleanup(func() { annotationTypes.Delete(kind) })
+
+	tests := []struct {
+		name        string
+		kind        AnnotationType
+		info        AnnotationTypeInfo
+		expectError bool
+	}{
+		{"empty name", "", info, true},
+		{"invalid layer", kind, AnnotationTypeInfo{Layer: "unknown"}, true},
+		{"new type", kind, info, false},
+		{"registered twice", kind, info, true},
+		{"built-in type", AnnotationTPM, info, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterAnnotationType(tt.kind, tt.info)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+
+	assert.True(t, kind.Validate())
+	read, ok := kind.Info()
+	assert.True(t, ok)
+	assert.Equal(t, info, read)
+
+	// Adding a registered type keeps its description
+	AddAnnotationType(kind)
+	read, _ = kind.Info()
+	assert.Equal(t, info, read)
+
+	types := AnnotationTypes()
+	assert.True(t, slices.IsSorted(types))
+	assert.Contains(t, types, kind)
+	assert.Contains(t, types, AnnotationTPM)
+	assert.NotContains(t, types, AnnotationSBOM)
+}
+
+func TestAnnotationType_Info(t *testing.T) {
+	tests := []struct {
+		kind  AnnotationType
+		valid bool
+		layer LayerType
+	}{
+		{AnnotationTPM, true, Host},
+		{AnnotationPKI, true, Application},
+		{AnnotationChecksum, true, CiCd},
+		{AnnotationSBOM, false, ""},
+		{"unknown", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			info, ok := tt.kind.Info()
+			assert.Equal(t, tt.valid, ok)
+			assert.Equal(t, tt.valid, tt.kind.Validate())
+			assert.Equal(t, tt.layer, info.Layer)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/strictjson"
+)
+
+// The limits enforced by StrictDecoding. They leave room for the largest signatures, those of post-quantum
+// algorithms, while bounding the memory an ingestion service spends on a single message.
+const (
+	// MaxAnnotationSize is the size of an encoded annotation, in bytes
+	MaxAnnotationSize = 64 << 10
+	// MaxFieldSize is the size of any text field of an annotation, in bytes
+	MaxFieldSize = 16 << 10
+	// MaxTags is the number of tags of an annotation
+	MaxTags = 64
+	// MaxAnnotations is the number of annotations of a list
+	MaxAnnotations = 10000
+)
+
+var (
+	annotationType     = reflect.TypeOf(Annotation{})
+	annotationListType = reflect.TypeOf(AnnotationList{})
+)
+
+// UnmarshalAnnotation parses the JSON encoding of an annotation in the given mode. In strict mode, the annotation must
+// not exceed MaxAnnotationSize, have fields other than those of the schema, nor fail CheckStrict.
+func UnmarshalAnnotation(data []byte, mode DecodeMode) (Annotation, error) {
+	var a Annotation
+	if mode == StrictDecoding {
+		if len(data) > MaxAnnotationSize {
+			return a, fmt.Errorf("%w: annotation of %d bytes exceeds %d", ErrMalformed, len(data), MaxAnnotationSize)
+		}
+		if err := strictjson.Check(data, annotationType); err != nil {
+			return a, fmt.Errorf("%w: %w", ErrMalformed, err)
+		}
+	}
+	if err := json.Unmarshal(data, &a); err != nil {
+		return Annotation{}, err
+	}
+	if mode == StrictDecoding {
+		if err := a.CheckStrict(); err != nil {
+			return Annotation{}, err
+		}
+	}
+	return a, nil
+}
+
+// UnmarshalAnnotationList parses the JSON encoding of a list of annotations in the given mode. In strict mode, the
+// list must not hold more than MaxAnnotations, and each annotation is held to the rules of UnmarshalAnnotation.
+func UnmarshalAnnotationList(data []byte, mode DecodeMode) (AnnotationList, error) {
+	var list AnnotationList
+	if mode == StrictDecoding {
+		if err := strictjson.Check(data, annotationListType); err != nil {
+			return list, fmt.Errorf("%w: %w", ErrMalformed, err)
+		}
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return AnnotationList{}, err
+	}
+	if mode == StrictDecoding {
+		if err := list.CheckStrict(); err != nil {
+			return AnnotationList{}, err
+		}
+	}
+	return list, nil
+}
+
+// CheckStrict returns an error wrapping ErrMalformed unless every annotation of the list passes CheckStrict and the
+// list holds no more than MaxAnnotations
+func (l AnnotationList) CheckStrict() error {
+	if len(l.Items) > MaxAnnotations {
+		return fmt.Errorf("%w: %d annotations exceed %d", ErrMalformed, len(l.Items), MaxAnnotations)
+	}
+	for i, a := range l.Items {
+		if err := a.CheckStrict(); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// CheckStrict returns an error wrapping ErrMalformed unless the annotation is complete and well-formed: it has an
+// identifier, a key, a known layer and a timestamp that can be encoded, and its fields fit the limits of
+// StrictDecoding. Its signature is not verified.
+func (a Annotation) CheckStrict() error {
+	if err := a.CheckVersion(); err != nil {
+		return err
+	}
+	if a.Id == (ulid.ULID{}) {
+		return fmt.Errorf("%w: annotation has no id", ErrMalformed)
+	}
+	if a.Key == "" {
+		return fmt.Errorf("%w: annotation %s has no key", ErrMalformed, a.Id)
+	}
+	if !a.Hash.Validate() {
+		return fmt.Errorf("%w: annotation %s has invalid HashType %s", ErrMalformed, a.Id, a.Hash)
+	}
+	if !a.Kind.Validate() {
+		return fmt.Errorf("%w: annotation %s has invalid AnnotationType %s", ErrMalformed, a.Id, a.Kind)
+	}
+	if !a.Layer.Validate() {
+		return fmt.Errorf("%w: annotation %s has invalid LayerType %s", ErrMalformed, a.Id, a.Layer)
+	}
+	// the timestamp must be one encoding/json can marshal again, as it is covered by the signature
+	if a.Timestamp.IsZero() || a.Timestamp.Year() < 1970 || a.Timestamp.Year() > 9999 {
+		return fmt.Errorf("%w: annotation %s has invalid timestamp %s", ErrMalformed, a.Id, a.Timestamp)
+	}
+	if len(a.Tag) > MaxTags {
+		return fmt.Errorf("%w: annotation %s has %d tags, exceeding %d", ErrMalformed, a.Id, len(a.Tag), MaxTags)
+	}
+	fields := []string{a.Key, a.ParentKey, a.PrevHash, a.Host, a.KeyId, a.Signature, a.Error}
+	fields = append(fields, a.Tag...)
+	if a.HostInfo != nil {
+		fields = append(fields, a.HostInfo.Region, a.HostInfo.InstanceId, a.HostInfo.OsRelease)
+		fields = append(fields, a.HostInfo.Addresses...)
+		fields = append(fields, a.HostInfo.MACs...)
+	}
+	for _, f := range fields {
+		if len(f) > MaxFieldSize {
+			return fmt.Errorf("%w: annotation %s has a field of %d bytes, exceeding %d", ErrMalformed, a.Id, len(f),
+				MaxFieldSize)
+		}
+	}
+	return nil
+}
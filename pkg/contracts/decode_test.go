@@ -0,0 +1,129 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package contracts
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const strictAnnotation = `{"version":5,"id":"01HQ2X7YJ1ZK3Y6Q8ZP9D2V4W5","key":"abc","hash":"sha256","host":"h",` +
+	`"tag":["a","b"],"layer":"app","kind":"src","signature":"00","isSatisfied":true,` +
+	`"timestamp":"2024-03-01T12:00:00Z","hostInfo":{"region":"us-east-1"}}`
+
+func TestUnmarshalAnnotation(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		expectStrict bool // expectStrict is set when strict decoding fails
+		expectError  bool // expectError is set when both modes fail
+	}{
+		{"valid", strictAnnotation, false, false},
+		{"unknown field", strings.Replace(strictAnnotation, `"host"`, `"hostname":"x","host"`, 1), true, false},
+		{"field of other case", strings.Replace(strictAnnotation, `"host"`, `"Host"`, 1), true, false},
+		{"duplicate field", strings.Replace(strictAnnotation, `"host":"h"`, `"host":"h","host":"i"`, 1), true, false},
+		{"unknown host field", strings.Replace(strictAnnotation, `"region"`, `"zone"`, 1), true, false},
+		{"no id", strings.Replace(strictAnnotation, `"id":"01HQ2X7YJ1ZK3Y6Q8ZP9D2V4W5",`, "", 1), true, false},
+		{"no key", strings.Replace(strictAnnotation, `"key":"abc",`, "", 1), true, false},
+		{"no timestamp", strings.Replace(strictAnnotation, `"timestamp":"2024-03-01T12:00:00Z",`, "", 1), true,
+			false},
+		{"timestamp before epoch", strings.Replace(strictAnnotation, "2024-03-01", "0001-01-02", 1), true, false},
+		{"invalid layer", strings.Replace(strictAnnotation, `"app"`, `"cloud"`, 1), true, false},
+		{"oversized field", strings.Replace(strictAnnotation, `"00"`, `"`+strings.Repeat("0", MaxFieldSize+1)+`"`,
+			1), true, false},
+		{"oversized annotation", strings.Replace(strictAnnotation, `"tag":["a","b"]`,
+			`"tag":["`+strings.Repeat("a", MaxFieldSize)+`"`+strings.Repeat(`,"`+strings.Repeat("a", MaxFieldSize)+`"`,
+				3)+`]`, 1), true, false},
+		{"too many tags", strings.Replace(strictAnnotation, `"tag":["a","b"]`,
+			`"tag":["a"`+strings.Repeat(`,"a"`, MaxTags)+`]`, 1), true, false},
+		{"trailing data", strictAnnotation + "{}", true, true},
+		{"invalid id", strings.Replace(strictAnnotation, "01HQ2X7YJ1ZK3Y6Q8ZP9D2V4W5", "not-an-id", 1), true, true},
+		{"invalid kind", strings.Replace(strictAnnotation, `"src"`, `"none"`, 1), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UnmarshalAnnotation([]byte(tt.data), LenientDecoding)
+			test.CheckError(err, tt.expectError, tt.name, t)
+
+			a, err := UnmarshalAnnotation([]byte(tt.data), StrictDecoding)
+			test.CheckError(err, tt.expectStrict, tt.name, t)
+			if err == nil {
+				assert.Equal(t, "abc", a.Key)
+			} else if !tt.expectError {
+				assert.True(t, errors.Is(err, ErrMalformed), err.Error())
+			}
+		})
+	}
+}
+
+func TestUnmarshalAnnotationList(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expectError bool
+	}{
+		{"valid", `{"items":[` + strictAnnotation + `,` + strictAnnotation + `]}`, false},
+		{"empty", `{}`, false},
+		{"unknown field", `{"items":[],"count":0}`, true},
+		{"malformed item", `{"items":[` + strictAnnotation + `,` +
+			strings.Replace(strictAnnotation, `"layer"`, `"level"`, 1) + `]}`, true},
+		{"too many items", `{"items":[` + strictAnnotation +
+			strings.Repeat(`,`+strictAnnotation, MaxAnnotations) + `]}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UnmarshalAnnotationList([]byte(tt.data), StrictDecoding)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				assert.True(t, errors.Is(err, ErrMalformed), err.Error())
+			}
+		})
+	}
+}
+
+// FuzzUnmarshalAnnotation checks that decoding never panics, that strict decoding accepts no more than lenient
+// decoding, and that strictly decoded annotations encode and decode again to the same annotation
+func FuzzUnmarshalAnnotation(f *testing.F) {
+	f.Add([]byte(strictAnnotation))
+	f.Add([]byte(`{"id":"01M4ZJ769XMY55M6NJN99DPEWG","hash":"sha256","kind":"src"}`))
+	f.Add([]byte(`{"version":5,"tag":"a","hostInfo":null,"timestamp":"9999-12-31T23:59:59.999999999+14:00"}`))
+	f.Add([]byte(`{"items":[{}]}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		lenient, lenientErr := UnmarshalAnnotation(data, LenientDecoding)
+		strict, err := UnmarshalAnnotation(data, StrictDecoding)
+		if err != nil {
+			return
+		}
+		if lenientErr != nil {
+			t.Fatalf("strictly decoded annotation rejected by lenient decoding: %v", lenientErr)
+		}
+		assert.Equal(t, lenient, strict)
+
+		encoded, err := json.Marshal(strict)
+		if err != nil {
+			t.Fatalf("strictly decoded annotation cannot be encoded: %v", err)
+		}
+		again, err := UnmarshalAnnotation(encoded, StrictDecoding)
+		if err != nil {
+			t.Fatalf("encoded annotation %s rejected: %v", encoded, err)
+		}
+		assert.True(t, strict.Timestamp.Equal(again.Timestamp))
+	})
+}
@@ -0,0 +1,222 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package contracts
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// AppendJSON appends the JSON encoding of the annotation to b, the same bytes as those of json.Marshal. It encodes
+// without reflection, so that annotations are signed and published on the hot path without allocating beyond b.
+func (a Annotation) AppendJSON(b []byte) ([]byte, error) {
+	e := encoder{b: append(b, '{')}
+	if a.Version != 0 {
+		e.field("version")
+		e.b = strconv.AppendInt(e.b, int64(a.Version), 10)
+	}
+	e.field("id")
+	var id [26]byte
+	_ = a.Id.MarshalTextTo(id[:])
+	e.b = append(e.b, '"')
+	e.b = append(e.b, id[:]...)
+	e.b = append(e.b, '"')
+	e.optional("key", a.Key)
+	e.optional("parentKey", a.ParentKey)
+	e.optional("prevHash", a.PrevHash)
+	e.optional("hash", string(a.Hash))
+	e.optional("host", a.Host)
+	switch len(a.Tag) {
+	case 0:
+	case 1:
+		e.field("tag")
+		e.string(a.Tag[0])
+	default:
+		e.field("tag")
+		e.strings(a.Tag)
+	}
+	e.optional("layer", string(a.Layer))
+	e.optional("kind", string(a.Kind))
+	e.optional("keyId", a.KeyId)
+	e.optional("signature", a.Signature)
+	e.field("isSatisfied")
+	e.b = strconv.AppendBool(e.b, a.IsSatisfied)
+	e.optional("error", a.Error)
+	e.field("timestamp")
+	if err := e.time(a.Timestamp); err != nil {
+		return b, err
+	}
+	if h := a.HostInfo; h != nil {
+		e.field("hostInfo")
+		e.b = append(e.b, '{')
+		if len(h.Addresses) > 0 {
+			e.field("addresses")
+			e.strings(h.Addresses)
+		}
+		if len(h.MACs) > 0 {
+			e.field("macs")
+			e.strings(h.MACs)
+		}
+		e.optional("region", h.Region)
+		e.optional("instanceId", h.InstanceId)
+		e.optional("osRelease", h.OsRelease)
+		e.b = append(e.b, '}')
+	}
+	return append(e.b, '}'), nil
+}
+
+// AppendJSON appends the JSON encoding of the list to b, the same bytes as those of json.Marshal, see
+// Annotation.AppendJSON
+func (l AnnotationList) AppendJSON(b []byte) ([]byte, error) {
+	if len(l.Items) == 0 {
+		return append(b, "{}"...), nil
+	}
+	start := len(b)
+	b = append(b, `{"items":[`...)
+	for i, a := range l.Items {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		var err error
+		if b, err = a.AppendJSON(b); err != nil {
+			return b[:start], err
+		}
+	}
+	return append(b, "]}"...), nil
+}
+
+// encoder appends the members of JSON objects to b
+type encoder struct {
+	b []byte
+}
+
+// field appends the name of a member, separated from the member before unless it is the first of its object
+func (e *encoder) field(name string) {
+	if e.b[len(e.b)-1] != '{' {
+		e.b = append(e.b, ',')
+	}
+	e.b = append(e.b, '"')
+	e.b = append(e.b, name...)
+	e.b = append(e.b, '"', ':')
+}
+
+// optional appends a member of type string, omitted when empty
+func (e *encoder) optional(name, value string) {
+	if value == "" {
+		return
+	}
+	e.field(name)
+	e.string(value)
+}
+
+// strings appends an array of strings
+func (e *encoder) strings(values []string) {
+	e.b = append(e.b, '[')
+	for i, v := range values {
+		if i > 0 {
+			e.b = append(e.b, ',')
+		}
+		e.string(v)
+	}
+	e.b = append(e.b, ']')
+}
+
+// time appends t as time.Time.MarshalJSON does, falling back to it for the times it rejects
+func (e *encoder) time(t time.Time) error {
+	_, offset := t.Zone()
+	if y := t.Year(); y < 0 || y > 9999 || offset <= -24*60*60 || offset >= 24*60*60 || offset%60 != 0 {
+		b, err := t.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		e.b = append(e.b, b...)
+		return nil
+	}
+	e.b = append(e.b, '"')
+	e.b = t.AppendFormat(e.b, time.RFC3339Nano)
+	e.b = append(e.b, '"')
+	return nil
+}
+
+const hex = "0123456789abcdef"
+
+// invalidUTF8 replaces invalid UTF-8 in strings, which encoding/json gives as an escaped or a literal U+FFFD
+// depending on the version of Go
+var invalidUTF8 = func() string {
+	b, _ := json.Marshal("\xff")
+	return string(b[1 : len(b)-1])
+}()
+
+// AppendJSONString appends the JSON encoding of s to b, the same bytes as those of json.Marshal
+func AppendJSONString(b []byte, s string) []byte {
+	e := encoder{b: b}
+	e.string(s)
+	return e.b
+}
+
+// string appends s quoted as encoding/json does, escaping HTML characters, invalid UTF-8 and the line and
+// paragraph separators
+func (e *encoder) string(s string) {
+	b := append(e.b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '\\', '"':
+				b = append(b, '\\', c)
+			case '\b':
+				b = append(b, '\\', 'b')
+			case '\f':
+				b = append(b, '\\', 'f')
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, invalidUTF8...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b = append(b, s[start:]...)
+	e.b = append(b, '"')
+}
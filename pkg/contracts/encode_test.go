@@ -0,0 +1,133 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package contracts
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotation_AppendJSON(t *testing.T) {
+	full := NewAnnotation("fcde2b2e", SHA256Hash, "host", Host, AnnotationSource, true)
+	full.ParentKey = "e3b0c442"
+	full.PrevHash = "4b825dc"
+	full.Tag = TagList{"4b825dc", "sha256:e3b0c442"}
+	full.KeyId = "2024-06"
+	full.Signature = "abcdef"
+	full.Error = "no tpm"
+	full.HostInfo = &HostInfo{Addresses: []string{"10.0.0.4"}, MACs: []string{"02:42:ac:11:00:02"},
+		Region: "us-east-1", InstanceId: "i-0abc", OsRelease: "Alpine Linux v3.20"}
+
+	escaped := full
+	escaped.Host = "<tag> & \"quoted\" \\ \b\f\n\r\t\x01\x1f    \xff é 日本"
+	escaped.Tag = TagList{"single </script>"}
+
+	zoned := full
+	zoned.Timestamp = time.Date(2024, time.March, 1, 12, 0, 0, 120, time.FixedZone("", -(5*60*60+30*60)))
+	zoned.HostInfo = &HostInfo{}
+
+	tests := []struct {
+		name       string
+		annotation Annotation
+	}{
+		{"empty", Annotation{}},
+		{"new", NewAnnotation("foo", SHA256Hash, "host", Host, AnnotationTPM, false)},
+		{"full", full},
+		{"escaped", escaped},
+		{"zoned", zoned},
+		{"original schema", Annotation{Id: ulid.ULID{15: 1}, Kind: AnnotationPKI, Timestamp: time.Unix(0, 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, err := json.Marshal(tt.annotation)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			actual, err := tt.annotation.AppendJSON([]byte("prefix"))
+			assert.NoError(t, err)
+			assert.Equal(t, "prefix"+string(expected), string(actual))
+
+			list := AnnotationList{Items: []Annotation{tt.annotation, tt.annotation}}
+			expected, _ = json.Marshal(list)
+			actual, err = list.AppendJSON(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+
+	actual, _ := AnnotationList{}.AppendJSON(nil)
+	assert.Equal(t, "{}", string(actual))
+
+	// times json.Marshal rejects are rejected alike
+	invalid := full
+	invalid.Timestamp = time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	_, err := invalid.AppendJSON(nil)
+	assert.Error(t, err)
+}
+
+// FuzzAnnotation_AppendJSON checks that AppendJSON encodes annotations exactly as json.Marshal does, as the signatures
+// of the annotations are verified over the encoding of json.Marshal
+func FuzzAnnotation_AppendJSON(f *testing.F) {
+	f.Add("fcde2b2e", "host", "4b825dc", "", "no tpm", int64(1709294400), int64(120), 0)
+	f.Add("< >", "\xff\xfe", "a\"b", "c\\d", "\x00", int64(-62135596800), int64(999999999), 3600)
+	f.Fuzz(func(t *testing.T, key, host, tag1, tag2, errText string, sec, nsec int64, offset int) {
+		a := NewAnnotation(key, SHA256Hash, host, Host, AnnotationSource, len(key)%2 == 0)
+		a.Tag = TagList{tag1}
+		if tag2 != "" {
+			a.Tag = append(a.Tag, tag2)
+		}
+		a.Error = errText
+		a.Timestamp = time.Unix(sec, nsec).In(time.FixedZone("", offset))
+		if len(host) > 3 {
+			a.HostInfo = &HostInfo{Addresses: []string{host}, Region: tag1}
+		}
+
+		expected, expectedErr := json.Marshal(a)
+		actual, err := a.AppendJSON(nil)
+		if expectedErr != nil {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected), string(actual))
+	})
+}
+
+// BenchmarkAnnotation_AppendJSON compares AppendJSON appending to a reused buffer with json.Marshal
+func BenchmarkAnnotation_AppendJSON(b *testing.B) {
+	a := NewAnnotation("fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9", SHA256Hash,
+		"edge-gateway-04", Host, AnnotationSource, true)
+	a.Tag = TagList{"sensor-7"}
+	a.Signature = strings.Repeat("9f", 64)
+
+	b.Run("AppendJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []byte
+		for i := 0; i < b.N; i++ {
+			buf, _ = a.AppendJSON(buf[:0])
+		}
+	})
+	b.Run("json.Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = json.Marshal(a)
+		}
+	})
+}
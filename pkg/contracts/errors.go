@@ -0,0 +1,51 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package contracts
+
+import "errors"
+
+// The errors below classify the failures of the SDK so that callers can branch on them with errors.Is. Errors returned
+// by the SDK wrap one of them along with the details of the failure.
+var (
+	// ErrConfigInvalid is returned when configuration fails validation
+	ErrConfigInvalid = errors.New("invalid configuration")
+	// ErrHashUnsupported is returned when a hash type is unknown or cannot be used as requested, such as for streaming
+	ErrHashUnsupported = errors.New("unsupported hash type")
+	// ErrKeyUnsupported is returned when a key algorithm is unknown or cannot be used as requested
+	ErrKeyUnsupported = errors.New("unsupported key algorithm")
+	// ErrKeyUnavailable is returned when a key cannot be read, fetched or parsed, or is not configured
+	ErrKeyUnavailable = errors.New("key unavailable")
+	// ErrSigningFailed is returned when an annotation could not be signed
+	ErrSigningFailed = errors.New("signing failed")
+	// ErrAnnotatorUnsupported is returned when an annotation type has no annotator
+	ErrAnnotatorUnsupported = errors.New("unsupported annotation type")
+	// ErrStreamUnsupported is returned when a stream type is unknown or its configuration does not match the type
+	ErrStreamUnsupported = errors.New("unsupported stream type")
+	// ErrNotConnected is returned when a stream provider has no connection to its platform
+	ErrNotConnected = errors.New("not connected")
+	// ErrPublishTimeout is returned when a publish is not acknowledged in time
+	ErrPublishTimeout = errors.New("publish timed out")
+	// ErrUnsupportedVersion is returned when a message uses a schema version that cannot be read or produced
+	ErrUnsupportedVersion = errors.New("unsupported schema version")
+	// ErrMalformed is returned when a received annotation or wrapper fails strict decoding, see StrictDecoding
+	ErrMalformed = errors.New("malformed message")
+	// ErrSignatureExpired is returned when an HTTP message signature has expired, is older than verifiers accept or was
+	// created in the future
+	ErrSignatureExpired = errors.New("signature expired")
+	// ErrSignatureReplayed is returned when an HTTP message signature carries a nonce that was already seen
+	ErrSignatureReplayed = errors.New("signature replayed")
+	// ErrComponentNotCovered is returned when an HTTP message signature does not cover a component it is required to
+	ErrComponentNotCovered = errors.New("component not covered")
+)
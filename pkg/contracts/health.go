@@ -0,0 +1,55 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package contracts
+
+// HealthStatus indicates whether the SDK, or one of its components, is able to do its work
+type HealthStatus string
+
+const (
+	HealthUp   HealthStatus = "up"
+	HealthDown HealthStatus = "down"
+)
+
+// ComponentHealth is the status of one component of the SDK, such as the stream provider or an annotator
+type ComponentHealth struct {
+	Name   string       `json:"name"`
+	Status HealthStatus `json:"status"`
+	Error  string       `json:"error,omitempty"` // Error explains why the component is down
+}
+
+// Health is the status of the SDK as a whole, it is down if any of its components is down
+type Health struct {
+	Status     HealthStatus      `json:"status"`
+	Components []ComponentHealth `json:"components"`
+}
+
+// NewComponentHealth returns the status of the named component given the error it reported, if any
+func NewComponentHealth(name string, err error) ComponentHealth {
+	if err != nil {
+		return ComponentHealth{Name: name, Status: HealthDown, Error: err.Error()}
+	}
+	return ComponentHealth{Name: name, Status: HealthUp}
+}
+
+// NewHealth aggregates the status of the given components
+func NewHealth(components []ComponentHealth) Health {
+	h := Health{Status: HealthUp, Components: components}
+	for _, c := range components {
+		if c.Status != HealthUp {
+			h.Status = HealthDown
+		}
+	}
+	return h
+}
@@ -0,0 +1,24 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package contracts
+
+// HostInfo describes the host that made an annotation, so that consumers such as scoring services can group and
+// weight annotations by fleet attributes. Only the attributes configured are present, see config.EnrichmentInfo.
+type HostInfo struct {
+	Addresses  []string `json:"addresses,omitempty"`  // Addresses are the IP addresses of the network interfaces
+	MACs       []string `json:"macs,omitempty"`       // MACs are the hardware addresses of the network interfaces
+	Region     string   `json:"region,omitempty"`     // Region is the cloud region the host runs in
+	InstanceId string   `json:"instanceId,omitempty"` // InstanceId identifies the cloud instance
+	OsRelease  string   `json:"osRelease,omitempty"`  // OsRelease names the release of the operating system
+}
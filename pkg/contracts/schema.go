@@ -0,0 +1,30 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package contracts
+
+import (
+	"bytes"
+	_ "embed"
+)
+
+//go:embed alvarium.schema.json
+var schema []byte
+
+// Schema returns the JSON Schema of the wire contracts, against which consumers not written in Go can validate the
+// messages they receive and generate their types. The Annotation, AnnotationList and HostInfo types of this package
+// and the PublishWrapper of pkg/message are defined under $defs by the same names, see alvarium.schema.json.
+func Schema() []byte {
+	return bytes.Clone(schema)
+}
@@ -0,0 +1,215 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// loadSchema returns the wire schema and its definition of name
+func loadSchema(t *testing.T, name string) (map[string]any, map[string]any) {
+	var root map[string]any
+	if err := json.Unmarshal(Schema(), &root); err != nil {
+		t.Fatalf(err.Error())
+	}
+	def, ok := root["$defs"].(map[string]any)[name].(map[string]any)
+	if !ok {
+		t.Fatalf("no definition of %s", name)
+	}
+	return root, def
+}
+
+// jsonFields returns the names of the fields of struct t in its JSON encoding
+func jsonFields(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "-" && t.Field(i).IsExported() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func keys(m map[string]any) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	return names
+}
+
+func TestSchema_Fields(t *testing.T) {
+	tests := []struct {
+		name string
+		t    reflect.Type
+	}{
+		{"Annotation", reflect.TypeOf(Annotation{})},
+		{"AnnotationList", reflect.TypeOf(AnnotationList{})},
+		{"HostInfo", reflect.TypeOf(HostInfo{})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, def := loadSchema(t, tt.name)
+			assert.ElementsMatch(t, jsonFields(tt.t), keys(def["properties"].(map[string]any)))
+		})
+	}
+}
+
+func TestSchema_Enums(t *testing.T) {
+	_, def := loadSchema(t, "Annotation")
+	properties := def["properties"].(map[string]any)
+	values := func(name, key string) []any {
+		return properties[name].(map[string]any)[key].([]any)
+	}
+
+	for _, v := range values("hash", "enum") {
+		assert.True(t, HashType(v.(string)).Validate(), "hash %s", v)
+	}
+	for _, v := range values("layer", "examples") {
+		assert.True(t, LayerType(v.(string)).Validate(), "layer %s", v)
+	}
+	for _, v := range values("kind", "examples") {
+		assert.True(t, AnnotationType(v.(string)).Validate(), "kind %s", v)
+	}
+	assert.Equal(t, float64(AnnotationVersion), properties["version"].(map[string]any)["maximum"],
+		"the newest annotation version is not allowed")
+}
+
+func TestSchema_Conforms(t *testing.T) {
+	root, def := loadSchema(t, "AnnotationList")
+
+	tagged := NewAnnotation("foo", SHA256Hash, "host", Application, AnnotationSource, true)
+	tagged.Tag = TagList{"4b825dc", "sha256:e3b0c442"}
+	tagged.Signature = "signature"
+	tagged.HostInfo = &HostInfo{Addresses: []string{"10.0.0.1"}, Region: "us-east-1"}
+	single := NewAnnotation("foo", SHA256Hash, "host", CiCd, AnnotationTPM, false)
+	single.Tag = TagList{"4b825dc"}
+	single.Error = "no tpm"
+	original, _ := NewAnnotation("foo", SHA256Hash, "host", Os, AnnotationPKI, true).ConvertTo(AnnotationVersion1)
+
+	b, err := json.Marshal(AnnotationList{Items: []Annotation{tagged, single, original}})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	var doc any
+	if err = json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Empty(t, conform(root, def, doc, "$"))
+
+	json.Unmarshal([]byte(`{"items":[{"id":"foo","key":"foo","hash":"sha1","kind":"src","isSatisfied":1,"tags":""}]}`),
+		&doc)
+	assert.ElementsMatch(t, []string{"$.items[0].id", "$.items[0].hash", "$.items[0].isSatisfied",
+		"$.items[0].tags", "$.items[0].timestamp"}, conform(root, def, doc, "$"))
+}
+
+// conform returns the locations where doc breaks schema, as far as the keywords used by the wire schema go
+func conform(root, schema map[string]any, doc any, at string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		def := root["$defs"].(map[string]any)[strings.TrimPrefix(ref, "#/$defs/")]
+		return conform(root, def.(map[string]any), doc, at)
+	}
+	if alternatives, ok := schema["oneOf"].([]any); ok {
+		matched := 0
+		for _, s := range alternatives {
+			if len(conform(root, s.(map[string]any), doc, at)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return []string{at}
+		}
+		return nil
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		for _, v := range enum {
+			if v == doc {
+				return nil
+			}
+		}
+		return []string{at}
+	}
+
+	switch v := doc.(type) {
+	case map[string]any:
+		if schema["type"] != "object" {
+			return []string{at}
+		}
+		var problems []string
+		for _, name := range asStrings(schema["required"]) {
+			if _, ok := v[name]; !ok {
+				problems = append(problems, at+"."+name)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for name, value := range v {
+			if s, ok := properties[name].(map[string]any); ok {
+				problems = append(problems, conform(root, s, value, at+"."+name)...)
+			} else if s, ok := schema["additionalProperties"].(map[string]any); ok {
+				problems = append(problems, conform(root, s, value, at+"."+name)...)
+			} else if schema["additionalProperties"] == false {
+				problems = append(problems, at+"."+name)
+			}
+		}
+		return problems
+	case []any:
+		if schema["type"] != "array" {
+			return []string{at}
+		}
+		var problems []string
+		for i, item := range v {
+			problems = append(problems, conform(root, schema["items"].(map[string]any), item,
+				fmt.Sprintf("%s[%d]", at, i))...)
+		}
+		return problems
+	case string:
+		if schema["type"] != "string" {
+			return []string{at}
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(v) {
+			return []string{at}
+		}
+	case bool:
+		if schema["type"] != "boolean" {
+			return []string{at}
+		}
+	case float64:
+		if schema["type"] != "integer" && schema["type"] != "number" {
+			return []string{at}
+		}
+	}
+	return nil
+}
+
+func asStrings(v any) []string {
+	var values []string
+	for _, x := range asList(v) {
+		values = append(values, x.(string))
+	}
+	return values
+}
+
+func asList(v any) []any {
+	list, _ := v.([]any)
+	return list
+}
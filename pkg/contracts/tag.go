@@ -0,0 +1,97 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package contracts
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// TagList holds the tags linking an annotation to the artifacts of the layer below it was derived from, such as the
+// commit SHA, image digest and deployment of a workload. A single tag is encoded as a string, in JSON as in CBOR, as it
+// was before annotations carried several, and several tags as an array of strings.
+type TagList []string
+
+// ParseTagList splits the tags of a list separated by commas or newlines, as exchanged between layers, dropping
+// empty ones
+func ParseTagList(s string) TagList {
+	var tags TagList
+	for _, tag := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// String returns the tags separated by commas
+func (t TagList) String() string {
+	return strings.Join(t, ",")
+}
+
+func (t TagList) MarshalJSON() ([]byte, error) {
+	switch len(t) {
+	case 0:
+		return []byte(`""`), nil
+	case 1:
+		return json.Marshal(t[0])
+	default:
+		return json.Marshal([]string(t))
+	}
+}
+
+// UnmarshalJSON accepts a single tag as a string, as well as an array of tags
+func (t *TagList) UnmarshalJSON(data []byte) error {
+	var tag string
+	if err := json.Unmarshal(data, &tag); err == nil {
+		*t = nil
+		if tag != "" {
+			*t = TagList{tag}
+		}
+		return nil
+	}
+	var tags []string
+	if err := json.Unmarshal(data, &tags); err != nil {
+		return err
+	}
+	*t = tags
+	return nil
+}
+
+func (t TagList) MarshalCBOR() ([]byte, error) {
+	if len(t) == 1 {
+		return cbor.Marshal(t[0])
+	}
+	return cbor.Marshal([]string(t))
+}
+
+// UnmarshalCBOR accepts a single tag as a string, as well as an array of tags
+func (t *TagList) UnmarshalCBOR(data []byte) error {
+	var tag string
+	if err := cbor.Unmarshal(data, &tag); err == nil {
+		*t = nil
+		if tag != "" {
+			*t = TagList{tag}
+		}
+		return nil
+	}
+	var tags []string
+	if err := cbor.Unmarshal(data, &tags); err != nil {
+		return err
+	}
+	*t = tags
+	return nil
+}
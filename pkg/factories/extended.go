@@ -0,0 +1,124 @@
+//go:build !alvarium_core && !js && !wasip1 && !tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package factories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	grpcAnnotators "github.com/project-alvarium/alvarium-sdk-go/internal/annotators/grpc"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/ethereum"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/fluentd"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hedera"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/iota"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/mqtt"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/otel"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/azurekeyvault"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/gcpkms"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/pkcs11"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/tpm"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/vaulttransit"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/syslog"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/uds"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/zeromq"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// coreBuild reports whether the SDK is built with the core profile, which leaves out the OS-specific annotators and
+// the providers with heavy dependencies so that it compiles for WebAssembly and TinyGo, see extended_core.go
+const coreBuild = false
+
+// extendedSignatureProviders creates the signature providers left out of the core build, backed by hardware or by
+// cloud key management services
+var extendedSignatureProviders = map[contracts.KeyAlgorithm]func(interval time.Duration) interfaces.SignatureProvider{
+	contracts.KeyPkcs11:        func(time.Duration) interfaces.SignatureProvider { return pkcs11.New() },
+	contracts.KeyTpm:           func(time.Duration) interfaces.SignatureProvider { return tpm.New() },
+	contracts.KeyAzureKeyVault: func(time.Duration) interfaces.SignatureProvider { return azurekeyvault.New() },
+	contracts.KeyGcpKms:        func(time.Duration) interfaces.SignatureProvider { return gcpkms.New() },
+	contracts.KeyVaultTransit:  func(time.Duration) interfaces.SignatureProvider { return vaulttransit.New() },
+}
+
+// extendedAnnotators creates the annotators left out of the core build, which inspect the host or depend on gRPC
+var extendedAnnotators = map[contracts.AnnotationType]AnnotatorFunc{
+	contracts.AnnotationTPM:     annotators.NewTpmAnnotator,
+	contracts.AnnotationPKIGrpc: grpcAnnotators.NewGrpcPkiAnnotator,
+}
+
+// newExtendedStreamProvider instantiates the stream providers left out of the core build
+func newExtendedStreamProvider(cfg config.StreamInfo, logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	switch cfg.Type {
+	case contracts.MqttStream:
+		info, ok := cfg.Config.(config.MqttConfig)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid cast for MqttStream", contracts.ErrStreamUnsupported)
+		}
+		return mqtt.NewMqttPublisher(info, logger)
+	case contracts.HederaStream:
+		info, ok := cfg.Config.(config.HederaConfig)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid cast for HederaStream", contracts.ErrStreamUnsupported)
+		}
+		return hedera.NewHederaPublisher(info, logger)
+	case contracts.EthereumStream:
+		info, ok := cfg.Config.(config.EthereumConfig)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid cast for EthereumStream", contracts.ErrStreamUnsupported)
+		}
+		return ethereum.NewEthereumPublisher(info, logger)
+	case contracts.IotaStream:
+		info, ok := cfg.Config.(config.IotaConfig)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid cast for IotaStream", contracts.ErrStreamUnsupported)
+		}
+		return iota.NewIotaPublisher(info, logger)
+	case contracts.ZmqStream:
+		info, ok := cfg.Config.(config.ZmqConfig)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid cast for ZmqStream", contracts.ErrStreamUnsupported)
+		}
+		return zeromq.NewZmqPublisher(info, logger)
+	case contracts.UdsStream:
+		info, ok := cfg.Config.(config.UdsConfig)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid cast for UdsStream", contracts.ErrStreamUnsupported)
+		}
+		return uds.NewUdsPublisher(info, logger), nil
+	case contracts.SyslogStream:
+		info, ok := cfg.Config.(config.SyslogConfig)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid cast for SyslogStream", contracts.ErrStreamUnsupported)
+		}
+		return syslog.NewSyslogPublisher(info, logger)
+	case contracts.OtelStream:
+		info, ok := cfg.Config.(config.OtelConfig)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid cast for OtelStream", contracts.ErrStreamUnsupported)
+		}
+		return otel.NewOtelPublisher(info, logger)
+	case contracts.FluentdStream:
+		info, ok := cfg.Config.(config.FluentdConfig)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid cast for FluentdStream", contracts.ErrStreamUnsupported)
+		}
+		return fluentd.NewFluentdPublisher(info, logger)
+	default:
+		return nil, fmt.Errorf("%w: unrecognized config Type value %s", contracts.ErrStreamUnsupported, cfg.Type)
+	}
+}
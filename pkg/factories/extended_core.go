@@ -0,0 +1,48 @@
+//go:build alvarium_core || js || wasip1 || tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package factories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// coreBuild reports whether the SDK is built with the core profile. The profile is selected by the alvarium_core
+// build tag, and always when building for WebAssembly (js, wasip1) or with TinyGo. It keeps hashing, signing with
+// keys read from files, URLs or configuration, the console and mock streams and the HTTP annotators, leaving out the
+// TPM and gRPC annotators, the hardware and cloud signature providers, the brokered and ledger streams and the
+// on-disk buffer.
+const coreBuild = true
+
+// extendedSignatureProviders is empty in the core build
+var extendedSignatureProviders = map[contracts.KeyAlgorithm]func(interval time.Duration) interfaces.SignatureProvider{}
+
+// extendedAnnotators is empty in the core build
+var extendedAnnotators = map[contracts.AnnotationType]AnnotatorFunc{}
+
+// newExtendedStreamProvider fails in the core build, which only keeps the console and mock streams
+func newExtendedStreamProvider(cfg config.StreamInfo, logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	if !cfg.Type.Validate() {
+		return nil, fmt.Errorf("%w: unrecognized config Type value %s", contracts.ErrStreamUnsupported, cfg.Type)
+	}
+	return nil, fmt.Errorf("%w: stream type %s is not available in the core build", contracts.ErrStreamUnsupported,
+		cfg.Type)
+}
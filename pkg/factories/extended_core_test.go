@@ -0,0 +1,77 @@
+//go:build alvarium_core || js || wasip1 || tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package factories
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoreBuild(t *testing.T) {
+	logger := NewLogger(config.LoggingInfo{MinLogLevel: slog.LevelDebug})
+	private := config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/private.key"}
+	cfg := config.SdkInfo{
+		Hash:      config.HashInfo{Type: contracts.SHA256Hash},
+		Signature: config.SignatureInfo{PrivateKey: private},
+		Layer:     contracts.Host,
+	}
+
+	streams := []struct {
+		name        string
+		cfg         config.StreamInfo
+		expectError error
+	}{
+		{"mock stream", config.StreamInfo{Type: contracts.MockStream, Config: config.MockStreamConfig{}}, nil},
+		{"console stream", config.StreamInfo{Type: contracts.ConsoleStream}, nil},
+		{"mqtt stream", config.StreamInfo{Type: contracts.MqttStream, Config: config.MqttConfig{}},
+			contracts.ErrStreamUnsupported},
+		{"unknown stream", config.StreamInfo{Type: "pigeon"}, contracts.ErrStreamUnsupported},
+		{"buffered stream", config.StreamInfo{Type: contracts.MockStream, Config: config.MockStreamConfig{},
+			Buffer: config.BufferInfo{Path: "buffer.db"}}, contracts.ErrConfigInvalid},
+	}
+	for _, tt := range streams {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewStreamProvider(tt.cfg, logger)
+			assert.True(t, errors.Is(err, tt.expectError), "unexpected error %v", err)
+		})
+	}
+
+	annotators := []struct {
+		kind        contracts.AnnotationType
+		expectError error
+	}{
+		{contracts.AnnotationSource, nil},
+		{contracts.AnnotationPKIHttp, nil},
+		{contracts.AnnotationTPM, contracts.ErrAnnotatorUnsupported},
+		{contracts.AnnotationPKIGrpc, contracts.ErrAnnotatorUnsupported},
+	}
+	for _, tt := range annotators {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			_, err := NewAnnotator(tt.kind, cfg)
+			assert.True(t, errors.Is(err, tt.expectError), "unexpected error %v", err)
+		})
+	}
+
+	_, err := NewSignatureProvider(contracts.KeyTpm)
+	assert.ErrorIs(t, err, contracts.ErrKeyUnsupported)
+	assert.ErrorContains(t, err, "core build")
+}
@@ -17,49 +17,119 @@ package factories
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
 	httpAnnotators "github.com/project-alvarium/alvarium-sdk-go/internal/annotators/http"
 	handler "github.com/project-alvarium/alvarium-sdk-go/internal/annotators/http/handler"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/buffer"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/compression"
 	"github.com/project-alvarium/alvarium-sdk-go/internal/console"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/encryption"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/blake3"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/canonical"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/hmac"
 	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/md5"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/merkle"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/multihash"
 	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/none"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/salted"
 	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/sha256"
-	"github.com/project-alvarium/alvarium-sdk-go/internal/hedera"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/sha3"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/tree"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/ids"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/message"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/metrics"
 	"github.com/project-alvarium/alvarium-sdk-go/internal/mock"
-	"github.com/project-alvarium/alvarium-sdk-go/internal/mqtt"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/ratelimit"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/retry"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/algorithm"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ecdsa"
 	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/secp256k1"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/tags"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
 )
 
+// NewStreamProvider instantiates the configured stream provider. When configured, the provider is wrapped with a
+// rate limit, a retry policy, an on-disk buffer, encryption, compression and then a batching layer. Messages are
+// therefore only buffered once retries are exhausted, and are already batched, compressed and encrypted when they are.
 func NewStreamProvider(cfg config.StreamInfo, logger interfaces.Logger) (interfaces.StreamProvider, error) {
+	return NewStreamProviderWithMetrics(cfg, logger, nil)
+}
+
+// NewStreamProviderWithMetrics instantiates the configured stream provider as NewStreamProvider does, additionally
+// reporting the activity of the underlying provider to publishMetrics when it is not nil
+func NewStreamProviderWithMetrics(cfg config.StreamInfo, logger interfaces.Logger,
+	publishMetrics interfaces.PublishMetrics) (interfaces.StreamProvider, error) {
+	provider, err := newStreamProvider(cfg, logging.Component(logger, logging.ComponentStream))
+	if err != nil {
+		return nil, err
+	}
+	if publishMetrics != nil {
+		provider = metrics.NewMeteredPublisher(cfg.Type, provider, publishMetrics)
+	}
+	// The limiter sits closest to the provider so that retries and buffer drains also count against the rate
+	if cfg.RateLimit.Rate > 0 {
+		if provider, err = ratelimit.NewRateLimitedPublisher(cfg.RateLimit, provider,
+			logging.Component(logger, logging.ComponentRateLimit)); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Retry.Enabled() {
+		var deadLetter interfaces.StreamProvider
+		if cfg.Retry.DeadLetter != nil {
+			if deadLetter, err = NewStreamProviderWithMetrics(*cfg.Retry.DeadLetter, logger, publishMetrics); err != nil {
+				return nil, err
+			}
+		}
+		if provider, err = retry.NewRetryingPublisher(cfg.Retry, provider, deadLetter,
+			logging.Component(logger, logging.ComponentRetry)); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Buffer.Path != "" {
+		if provider, err = buffer.NewBufferedPublisher(cfg.Buffer, provider,
+			logging.Component(logger, logging.ComponentBuffer)); err != nil {
+			return nil, err
+		}
+	}
+	if len(cfg.Encryption.Recipients) > 0 {
+		if provider, err = encryption.NewEncryptingPublisher(cfg.Encryption, provider,
+			logging.Component(logger, logging.ComponentEncryption)); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Compression.Encoding != "" {
+		if provider, err = compression.NewCompressingPublisher(cfg.Compression, provider,
+			logging.Component(logger, logging.ComponentCompression)); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Batch.Enabled() {
+		return message.NewBatchingPublisher(cfg.Batch, provider, logging.Component(logger, logging.ComponentBatch))
+	}
+	return provider, nil
+}
+
+func newStreamProvider(cfg config.StreamInfo, logger interfaces.Logger) (interfaces.StreamProvider, error) {
 	switch cfg.Type {
 	case contracts.MockStream:
 		info, ok := cfg.Config.(config.MockStreamConfig)
 		if !ok {
-			return nil, errors.New("invalid cast for MockStream")
+			return nil, fmt.Errorf("%w: invalid cast for MockStream", contracts.ErrStreamUnsupported)
 		}
 		return mock.NewMockPublisher(info, logger), nil
-	case contracts.MqttStream:
-		info, ok := cfg.Config.(config.MqttConfig)
-		if !ok {
-			return nil, errors.New("invalid cast for MqttStream")
-		}
-		return mqtt.NewMqttPublisher(info, logger), nil
 	case contracts.ConsoleStream:
 		return console.NewConsolePublisher(logger), nil
-	case contracts.HederaStream:
-		info, ok := cfg.Config.(config.HederaConfig)
-		if !ok {
-			return nil, errors.New("invalid cast for HederaStream")
-		}
-		return hedera.NewHederaPublisher(info, logger)
 	default:
-		return nil, fmt.Errorf("unrecognized config Type value %s", cfg.Type)
+		return newExtendedStreamProvider(cfg, logger)
 	}
 }
 
@@ -71,68 +141,384 @@ func NewHashProvider(hash contracts.HashType) (interfaces.HashProvider, error) {
 	case contracts.SHA256Hash:
 
 		return sha256.New(), nil
+	case contracts.SHA3Hash:
+		return sha3.New(), nil
+	case contracts.BLAKE3Hash:
+		return blake3.New(), nil
+	case contracts.MerkleHash:
+		return merkle.New(), nil
+	case contracts.TreeHash:
+		return tree.New(config.TreeHashInfo{})
 	case contracts.NoHash:
 		return none.New(), nil
+	case contracts.HMACHash:
+		return nil, fmt.Errorf("%w: hash type %s requires a key, use NewHashProviderWithInfo",
+			contracts.ErrHashUnsupported, hash)
+	default:
+		return nil, fmt.Errorf("%w: unrecognized hash type value %s", contracts.ErrHashUnsupported, hash)
+	}
+}
+
+// NewHashProviderWithInfo instantiates a hash provider from its configuration, supplying the secret key to keyed
+// hash types and applying the configured encoding, salt and canonicalization. Unkeyed hash types are created as by NewHashProvider.
+func NewHashProviderWithInfo(cfg config.HashInfo) (interfaces.HashProvider, error) {
+	var h interfaces.HashProvider
+	var err error
+	switch cfg.Type {
+	case contracts.HMACHash:
+		h, err = hmac.New(cfg)
+	case contracts.TreeHash:
+		if cfg.Tree != nil {
+			h, err = tree.New(*cfg.Tree)
+		} else {
+			h, err = NewHashProvider(cfg.Type)
+		}
+	default:
+		h, err = NewHashProvider(cfg.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Encoding == contracts.MultihashEncoding {
+		if h, err = multihash.New(cfg.Type, h); err != nil {
+			return nil, err
+		}
+	}
+	// The salt is mixed into the data rather than the encoded hash, and in turn into the canonical form of the data
+	if cfg.Salt != nil {
+		if h, err = salted.New(*cfg.Salt, h); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Canonicalization == contracts.JCSCanonicalization {
+		h = canonical.New(h)
+	}
+	return h, nil
+}
+
+// signatureProviders creates the signature provider of each key algorithm. Providers reading keys from files check
+// them for changes at most once per interval.
+var signatureProviders = map[contracts.KeyAlgorithm]func(interval time.Duration) interfaces.SignatureProvider{
+	contracts.KeyEd25519: func(interval time.Duration) interfaces.SignatureProvider {
+		return ed25519.NewWithReload(interval)
+	},
+	contracts.KeyEcdsaP256: func(interval time.Duration) interfaces.SignatureProvider {
+		return ecdsa.NewWithReload(interval)
+	},
+	contracts.KeyEcdsaSecp256k1: func(interval time.Duration) interfaces.SignatureProvider {
+		return secp256k1.NewWithReload(interval)
+	},
+}
+
+func init() {
+	maps.Copy(signatureProviders, extendedSignatureProviders)
+}
+
+// signatureProvidersMu guards signatureProviders against concurrent registration
+var signatureProvidersMu sync.RWMutex
+
+// RegisterKeyAlgorithm adds a key algorithm implemented outside this module. Once registered, keys of type k are
+// accepted in configuration and signed and verified with alg, reading keys from files, URLs or KeyInfo.Material as
+// the built-in algorithms do. Registering an algorithm that already exists is an error.
+func RegisterKeyAlgorithm(k contracts.KeyAlgorithm, alg interfaces.KeyAlgorithm) error {
+	if k == "" {
+		return errors.New("key algorithm name is required")
+	}
+	if alg == nil {
+		return fmt.Errorf("no implementation given for key algorithm %s", k)
+	}
+
+	signatureProvidersMu.Lock()
+	defer signatureProvidersMu.Unlock()
+	if _, ok := signatureProviders[k]; ok {
+		return fmt.Errorf("key algorithm %s is already registered", k)
+	}
+	signatureProviders[k] = func(interval time.Duration) interfaces.SignatureProvider {
+		return algorithm.New(alg, interval)
+	}
+	contracts.AddKeyAlgorithm(k)
+	return nil
+}
+
+// newSignatureProvider looks up the signature provider of the key algorithm
+func newSignatureProvider(k contracts.KeyAlgorithm, interval time.Duration) (interfaces.SignatureProvider, error) {
+	signatureProvidersMu.RLock()
+	create, ok := signatureProviders[k]
+	signatureProvidersMu.RUnlock()
+	if !ok && coreBuild && k.Validate() {
+		return nil, fmt.Errorf("%w: key algorithm %s is not available in the core build", contracts.ErrKeyUnsupported, k)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: unrecognized key algorithm value %s", contracts.ErrKeyUnsupported, k)
+	}
+	return create(interval), nil
+}
+
+// NewIdGenerator instantiates the generator of annotation identifiers of the given type
+func NewIdGenerator(id contracts.IdType) (interfaces.IdGenerator, error) {
+	switch id {
+	case contracts.ULIDId:
+		return ids.ULIDGenerator{}, nil
+	case contracts.UUIDv7Id:
+		return ids.UUIDv7Generator{}, nil
+	case contracts.DeterministicId:
+		return ids.DeterministicGenerator{}, nil
 	default:
-		return nil, fmt.Errorf("unrecognized hash type value %s", hash)
+		return nil, fmt.Errorf("%w: unrecognized id type value %s", contracts.ErrConfigInvalid, id)
 	}
 }
 
+// NewTagWriter instantiates the writer exporting the tag to the linked layers through the configured source. Only tags
+// exchanged through the environment, a file or Kubernetes can be written.
+func NewTagWriter(cfg config.TagInfo) (interfaces.TagWriter, error) {
+	switch cfg.Source {
+	case contracts.TagSourceEnv:
+		return tags.NewEnvTag(cfg.Key), nil
+	case contracts.TagSourceFile:
+		return tags.NewFileTag(cfg.Path), nil
+	case contracts.TagSourceKubernetes:
+		return tags.NewKubernetesTag(cfg.Key, cfg.Path), nil
+	default:
+		return nil, fmt.Errorf("%w: tag source %s cannot be written", contracts.ErrConfigInvalid, cfg.Source)
+	}
+}
+
+// NewTagValueGetter instantiates the getter of the tag exported by a linked layer through the configured source
+func NewTagValueGetter(cfg config.TagInfo) (interfaces.TagValueGetter, error) {
+	switch cfg.Source {
+	case contracts.TagSourceEnv:
+		return tags.NewEnvTag(cfg.Key), nil
+	case contracts.TagSourceFile:
+		return tags.NewFileTag(cfg.Path), nil
+	case contracts.TagSourceKubernetes:
+		return tags.NewKubernetesTag(cfg.Key, cfg.Path), nil
+	case contracts.TagSourceCommand:
+		return tags.NewCommandTag(cfg.Command), nil
+	case contracts.TagSourceStatic:
+		return tags.StaticTag(contracts.ParseTagList(cfg.Value)), nil
+	case contracts.TagSourceGit:
+		return tags.NewGitTag(cfg.Path), nil
+	case contracts.TagSourceEC2, contracts.TagSourceGCE, contracts.TagSourceAzure:
+		return tags.NewMetadataTag(cfg.Source, cfg.Key)
+	default:
+		return nil, fmt.Errorf("%w: unrecognized tag source value %s", contracts.ErrConfigInvalid, cfg.Source)
+	}
+}
+
+// NewLayerTagValueGetter instantiates the getter of the tags of each layer from the sources configured for it, see
+// config.SdkInfo.TagSources
+func NewLayerTagValueGetter(cfg config.SdkInfo) (interfaces.TagValueGetter, error) {
+	layers := make(map[contracts.LayerType][]interfaces.TagValueGetter)
+	for layer, sources := range cfg.TagSources() {
+		for _, source := range sources {
+			getter, err := NewTagValueGetter(source)
+			if err != nil {
+				return nil, err
+			}
+			layers[layer] = append(layers[layer], getter)
+		}
+	}
+	return tags.NewLayerTag(layers), nil
+}
+
 // NewSignatureProvider instantiates a signature provider based on the desired key algorithm
 //
 // The current working assumption is that all nodes within a Data Confidence Fabric will use the same algorithm
 // to generate their identity keys. If later there's a good reason provided as to why this might be heterogeneous,
 // the existing implementation around signatures will need to change
 func NewSignatureProvider(k contracts.KeyAlgorithm) (interfaces.SignatureProvider, error) {
-	switch k {
-	case contracts.KeyEd25519:
-		return ed25519.New(), nil
-	default:
-		return nil, fmt.Errorf("unrecognized key algorithm value %s", k)
+	return newSignatureProvider(k, 0)
+}
+
+// keyLoader is implemented by signature providers that cache keys read from files
+type keyLoader interface {
+	LoadKeys(keys config.SignatureInfo) error
+}
+
+// NewSignatureProviderWithInfo instantiates a signature provider for the configured private key. Providers reading
+// keys from files read and parse the signing keys here, once, and reload them at the configured interval.
+func NewSignatureProviderWithInfo(cfg config.SignatureInfo) (interfaces.SignatureProvider, error) {
+	s, err := newSignatureProvider(cfg.PrivateKey.Type, time.Duration(cfg.ReloadInterval)*time.Second)
+	if err != nil {
+		return nil, err
 	}
+
+	if l, ok := s.(keyLoader); ok {
+		if err := l.LoadKeys(cfg); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// AnnotatorFunc creates an annotator implemented outside this module, see RegisterAnnotator. It receives the SDK
+// configuration along with the configured hash and signature providers, as the built-in annotators do.
+type AnnotatorFunc func(cfg config.SdkInfo, hash interfaces.HashProvider,
+	sign interfaces.SignatureProvider) interfaces.Annotator
+
+// customAnnotators holds the annotators added by RegisterAnnotator, guarded by customAnnotatorsMu
+var (
+	customAnnotators   = map[contracts.AnnotationType]AnnotatorFunc{}
+	customAnnotatorsMu sync.RWMutex
+)
+
+// RegisterAnnotator adds an annotator implemented outside this module. Once registered, the kind can be listed in
+// the configured annotators and is created by NewAnnotator with fn, and annotations of that kind are accepted when
+// unmarshalled. Annotator instances can also be passed to pkg.NewSdk directly, alongside the built-in ones; their
+// kind must be registered, or added with contracts.AddAnnotationType, for consumers to read their annotations.
+// Registering a kind that already exists is an error.
+func RegisterAnnotator(kind contracts.AnnotationType, fn AnnotatorFunc) error {
+	return RegisterAnnotatorWithInfo(kind, contracts.AnnotationTypeInfo{}, fn)
+}
+
+// RegisterAnnotatorWithInfo adds an annotator like RegisterAnnotator, describing its kind with info as
+// contracts.RegisterAnnotationType does. The layer in info is used by NewAnnotator when none is configured.
+func RegisterAnnotatorWithInfo(kind contracts.AnnotationType, info contracts.AnnotationTypeInfo,
+	fn AnnotatorFunc) error {
+	if kind == "" {
+		return errors.New("annotation type is required")
+	}
+	if fn == nil {
+		return fmt.Errorf("no constructor given for annotation type %s", kind)
+	}
+
+	customAnnotatorsMu.Lock()
+	defer customAnnotatorsMu.Unlock()
+	if err := contracts.RegisterAnnotationType(kind, info); err != nil {
+		return err
+	}
+	customAnnotators[kind] = fn
+	return nil
 }
 
 func NewAnnotator(kind contracts.AnnotationType, cfg config.SdkInfo) (interfaces.Annotator, error) {
-	h, err := NewHashProvider(cfg.Hash.Type)
+	return NewAnnotatorWithMetrics(kind, cfg, nil)
+}
+
+// NewAnnotatorWithMetrics instantiates an annotator like NewAnnotator, reporting the signatures it makes to
+// signMetrics if given
+func NewAnnotatorWithMetrics(kind contracts.AnnotationType, cfg config.SdkInfo,
+	signMetrics interfaces.SignMetrics) (interfaces.Annotator, error) {
+	h, err := NewHashProviderWithInfo(cfg.Hash)
 	if err != nil {
 		return nil, err
 	}
 
-	s, err := NewSignatureProvider(cfg.Signature.PrivateKey.Type)
+	s, err := NewSignatureProviderWithInfo(cfg.Signature)
 	if err != nil {
 		return nil, err
 	}
+	if signMetrics != nil {
+		s = metrics.NewMeteredSignatureProvider(s, signMetrics)
+	}
+	if info, ok := kind.Info(); ok && cfg.Layer == "" {
+		cfg.Layer = info.Layer
+	}
 
 	var a interfaces.Annotator
 	switch kind {
 	case contracts.AnnotationSource:
 		a = annotators.NewSourceAnnotator(cfg, h, s)
-	case contracts.AnnotationTPM:
-		a = annotators.NewTpmAnnotator(cfg, h, s)
 	case contracts.AnnotationPKI:
 		a = annotators.NewPkiAnnotator(cfg, h, s)
 	case contracts.AnnotationPKIHttp:
 		a = httpAnnotators.NewHttpPkiAnnotator(cfg, h, s)
+	case contracts.AnnotationSourceHttp:
+		a = httpAnnotators.NewHttpSourceAnnotator(cfg, h, s)
 	case contracts.AnnotationTLS:
 		a = annotators.NewTlsAnnotator(cfg, h, s)
 	default:
-		return nil, fmt.Errorf("unrecognized AnnotationType %s", kind)
+		customAnnotatorsMu.RLock()
+		fn, ok := customAnnotators[kind]
+		customAnnotatorsMu.RUnlock()
+		if extended, isExtended := extendedAnnotators[kind]; isExtended {
+			fn, ok = extended, true
+		}
+		if !ok && coreBuild && kind.Validate() {
+			return nil, fmt.Errorf("%w: annotation type %s is not available in the core build",
+				contracts.ErrAnnotatorUnsupported, kind)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: unrecognized AnnotationType %s", contracts.ErrAnnotatorUnsupported, kind)
+		}
+		a = fn(cfg, h, s)
 	}
 	return a, nil
 }
 
+// NewRequestHandler returns a handler signing a request with the provider of the algorithm of keys.PrivateKey, any
+// supported by NewSignatureProvider
 func NewRequestHandler(request *http.Request, keys config.SignatureInfo) (interfaces.RequestHandler, error) {
-	var r interfaces.RequestHandler
+	signer, err := NewSignatureProvider(keys.PrivateKey.Type)
+	if err != nil {
+		return nil, err
+	}
+	return handler.NewRequestHandler(request, signer), nil
+}
 
-	switch keys.PrivateKey.Type {
-	case contracts.KeyEd25519:
-		r = handler.NewEd25519RequestHandler(request)
-	default:
-		return nil, fmt.Errorf("unrecognized Key Type %s", keys.PrivateKey.Type)
+// NewRequestHandlerWithLabel returns a handler countersigning a request under label, as an intermediary such as a
+// gateway does, keeping the signatures the request already carries
+func NewRequestHandlerWithLabel(request *http.Request, keys config.SignatureInfo,
+	label string) (interfaces.RequestHandler, error) {
+	if !handler.IsLabel(label) {
+		return nil, fmt.Errorf("%w: invalid signature label %q", contracts.ErrConfigInvalid, label)
+	}
+	signer, err := NewSignatureProvider(keys.PrivateKey.Type)
+	if err != nil {
+		return nil, err
+	}
+	return handler.NewRequestHandlerWithLabel(request, label, signer), nil
+}
+
+// NewSigningTransport returns an http.RoundTripper signing each request with the keys before it is sent by base, or
+// http.DefaultTransport if base is nil. The signatures cover the given components and header fields, by default those
+// set by keys.Http.Request, or else @method, @path, @authority, Content-Type and Content-Digest, which binds the body.
+// Requests are signed with the provider of the algorithm of keys.PrivateKey, as NewRequestHandler signs them.
+func NewSigningTransport(base http.RoundTripper, keys config.SignatureInfo, fields ...string) (http.RoundTripper, error) {
+	signer, err := NewSignatureProvider(keys.PrivateKey.Type)
+	if err != nil {
+		return nil, err
+	}
+	return handler.NewTransport(base, keys, fields, func(r *http.Request) interfaces.RequestHandler {
+		return handler.NewRequestHandler(r, signer)
+	}), nil
+}
+
+// NewVerifyingHandler returns an http.Handler serving the requests whose signature verifies against the public keys
+// found in the directory of keys.PublicKey, and answering 401 Unauthorized to the others. Verified requests are found
+// in the context under contracts.HttpRequestKey, where the pki-http annotator reads them.
+func NewVerifyingHandler(next http.Handler, keys config.SignatureInfo) http.Handler {
+	return handler.NewMiddleware(next, keys, NewSignatureProvider, nil)
+}
+
+// NewVerifyingHandlerWithNonceStore returns a verifying handler, as NewVerifyingHandler does, recording the nonces of
+// verified signatures in nonces rather than in memory. A store shared by the instances of a service rejects requests
+// replayed to another instance.
+func NewVerifyingHandlerWithNonceStore(next http.Handler, keys config.SignatureInfo,
+	nonces interfaces.NonceStore) http.Handler {
+	return handler.NewMiddleware(next, keys, NewSignatureProvider, nonces)
+}
+
+// NewCountersigningTransport returns an http.RoundTripper countersigning each request under label before it is sent
+// by base, as NewSigningTransport signs them, keeping the signatures the requests already carry. Intermediaries such
+// as gateways forwarding signed requests use it to add their own signature.
+func NewCountersigningTransport(base http.RoundTripper, keys config.SignatureInfo, label string,
+	fields ...string) (http.RoundTripper, error) {
+	if !handler.IsLabel(label) {
+		return nil, fmt.Errorf("%w: invalid signature label %q", contracts.ErrConfigInvalid, label)
+	}
+	signer, err := NewSignatureProvider(keys.PrivateKey.Type)
+	if err != nil {
+		return nil, err
 	}
-	return r, nil
+	return handler.NewTransport(base, keys, fields, func(r *http.Request) interfaces.RequestHandler {
+		return handler.NewRequestHandlerWithLabel(r, label, signer)
+	}), nil
 }
 
+// NewLogger returns a logger writing JSON to the console. Applications logging through slog, or through zap or zerolog
+// behind a slog handler, can give the SDK a logging.NewSlogLogger instead.
 func NewLogger(cfg config.LoggingInfo) interfaces.Logger {
 	return logging.NewConsoleLogger(cfg)
 }
@@ -1,3 +1,5 @@
+//go:build !alvarium_core && !js && !wasip1 && !tinygo
+
 /*******************************************************************************
  * Copyright 2024 Dell Inc.
  *
@@ -16,15 +18,29 @@ package factories
 
 import (
 	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
 	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestStreamProviderFactory(t *testing.T) {
@@ -40,6 +56,37 @@ func TestStreamProviderFactory(t *testing.T) {
 		Config: config.MqttConfig{},
 	}
 
+	pass3 := config.StreamInfo{
+		Type:   contracts.IotaStream,
+		Config: config.IotaConfig{Tag: "alvarium"},
+	}
+
+	pass4 := config.StreamInfo{
+		Type: contracts.ZmqStream,
+		Config: config.ZmqConfig{
+			SocketType: contracts.ZmqPub,
+			Provider:   config.ServiceInfo{Host: "*", Port: 5556, Protocol: "tcp"},
+			Bind:       true,
+		},
+	}
+
+	pass5 := config.StreamInfo{
+		Type:   contracts.SyslogStream,
+		Config: config.SyslogConfig{Provider: config.ServiceInfo{Host: "localhost", Port: 6514, Protocol: "tls"}},
+	}
+
+	pass6 := config.StreamInfo{
+		Type:        contracts.MockStream,
+		Config:      config.MockStreamConfig{},
+		Compression: config.CompressionInfo{Encoding: contracts.GzipEncoding, Level: 9},
+	}
+
+	pass7 := config.StreamInfo{
+		Type:      contracts.MockStream,
+		Config:    config.MockStreamConfig{},
+		RateLimit: config.RateLimitInfo{Rate: 2, Burst: 4},
+	}
+
 	fail := config.StreamInfo{
 		Type:   "invalid",
 		Config: config.MqttConfig{},
@@ -50,6 +97,18 @@ func TestStreamProviderFactory(t *testing.T) {
 		Config: config.MockStreamConfig{},
 	}
 
+	fail3 := config.StreamInfo{
+		Type:        contracts.MockStream,
+		Config:      config.MockStreamConfig{},
+		Compression: config.CompressionInfo{Encoding: contracts.ZstdEncoding, Level: 30},
+	}
+
+	fail4 := config.StreamInfo{
+		Type:       contracts.MockStream,
+		Config:     config.MockStreamConfig{},
+		Encryption: config.EncryptionInfo{Recipients: []config.RecipientInfo{{PublicKeyPath: "./missing.pub"}}},
+	}
+
 	tests := []struct {
 		name         string
 		providerType config.StreamInfo
@@ -57,8 +116,15 @@ func TestStreamProviderFactory(t *testing.T) {
 	}{
 		{"valid mock type", pass, false},
 		{"valid mqtt type", pass2, false},
+		{"valid iota type", pass3, false},
+		{"valid zeromq type", pass4, false},
+		{"valid syslog type", pass5, false},
+		{"valid compressed mock type", pass6, false},
+		{"valid rate limited mock type", pass7, false},
 		{"invalid random type", fail, true},
 		{"unimplemented pravega type", fail2, true},
+		{"invalid compression level", fail3, true},
+		{"missing encryption recipient key", fail4, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -76,7 +142,12 @@ func TestHashProviderFactory(t *testing.T) {
 	}{
 		{"valid md5 type", contracts.MD5Hash, false},
 		{"valid sha256 type", contracts.SHA256Hash, false},
+		{"valid sha3-256 type", contracts.SHA3Hash, false},
+		{"valid blake3 type", contracts.BLAKE3Hash, false},
+		{"valid merkle type", contracts.MerkleHash, false},
+		{"valid tree type", contracts.TreeHash, false},
 		{"valid none type", contracts.NoHash, false},
+		{"keyed hash type", contracts.HMACHash, true},
 		{"invalid hash type", "invalid", true},
 	}
 	for _, tt := range tests {
@@ -87,6 +158,38 @@ func TestHashProviderFactory(t *testing.T) {
 	}
 }
 
+func TestHashProviderWithInfoFactory(t *testing.T) {
+	t.Setenv("ALVARIUM_HASH_KEY", "000102030405060708090a0b0c0d0e0f")
+
+	tests := []struct {
+		name        string
+		cfg         config.HashInfo
+		expectError bool
+	}{
+		{"valid sha256 type", config.HashInfo{Type: contracts.SHA256Hash}, false},
+		{"valid hmac type", config.HashInfo{Type: contracts.HMACHash, KeyEnv: "ALVARIUM_HASH_KEY"}, false},
+		{"hmac without key", config.HashInfo{Type: contracts.HMACHash}, true},
+		{"multihash encoding", config.HashInfo{Type: contracts.SHA256Hash, Encoding: contracts.MultihashEncoding}, false},
+		{"tuned tree", config.HashInfo{Type: contracts.TreeHash, Tree: &config.TreeHashInfo{ChunkSize: 4096}}, false},
+		{"invalid tree", config.HashInfo{Type: contracts.TreeHash, Tree: &config.TreeHashInfo{Workers: -1}}, true},
+		{"salted", config.HashInfo{Type: contracts.SHA256Hash, Salt: &config.SaltInfo{Value: "0102"}}, false},
+		{"salted multihash", config.HashInfo{Type: contracts.SHA256Hash, Encoding: contracts.MultihashEncoding,
+			Salt: &config.SaltInfo{Value: "0102"}}, false},
+		{"invalid salt", config.HashInfo{Type: contracts.SHA256Hash, Salt: &config.SaltInfo{Value: "salt"}}, true},
+		{"canonical", config.HashInfo{Type: contracts.SHA256Hash, Canonicalization: contracts.JCSCanonicalization},
+			false},
+		{"multihash without code", config.HashInfo{Type: contracts.MerkleHash, Encoding: contracts.MultihashEncoding},
+			true},
+		{"invalid hash type", config.HashInfo{Type: "invalid"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHashProviderWithInfo(tt.cfg)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
 func TestSignatureProviderFactory(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -94,6 +197,13 @@ func TestSignatureProviderFactory(t *testing.T) {
 		expectError  bool
 	}{
 		{"valid ed25519 type", contracts.KeyEd25519, false},
+		{"valid ecdsa-p256 type", contracts.KeyEcdsaP256, false},
+		{"valid secp256k1 type", contracts.KeyEcdsaSecp256k1, false},
+		{"valid pkcs11 type", contracts.KeyPkcs11, false},
+		{"valid tpm type", contracts.KeyTpm, false},
+		{"valid azure-keyvault type", contracts.KeyAzureKeyVault, false},
+		{"valid gcp-kms type", contracts.KeyGcpKms, false},
+		{"valid vault-transit type", contracts.KeyVaultTransit, false},
 		{"invalid hash type", "invalid", true},
 	}
 	for _, tt := range tests {
@@ -104,6 +214,218 @@ func TestSignatureProviderFactory(t *testing.T) {
 	}
 }
 
+func TestSigningTransportFactory(t *testing.T) {
+	tests := []struct {
+		name        string
+		keyType     contracts.KeyAlgorithm
+		expectError bool
+	}{
+		{"valid ed25519 type", contracts.KeyEd25519, false},
+		{"valid ecdsa-p256 type", contracts.KeyEcdsaP256, false},
+		{"invalid key type", "invalid", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := config.SignatureInfo{PrivateKey: config.KeyInfo{Type: tt.keyType}}
+			_, err := NewSigningTransport(nil, keys)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestCountersigningFactory(t *testing.T) {
+	tests := []struct {
+		name        string
+		keyType     contracts.KeyAlgorithm
+		label       string
+		expectError bool
+	}{
+		{"valid ed25519 type", contracts.KeyEd25519, "gateway", false},
+		{"valid ecdsa-p256 type", contracts.KeyEcdsaP256, "gateway", false},
+		{"invalid key type", "invalid", "gateway", true},
+		{"empty label", contracts.KeyEd25519, "", true},
+		{"invalid label", contracts.KeyEd25519, "Gate way", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := config.SignatureInfo{PrivateKey: config.KeyInfo{Type: tt.keyType}}
+			_, err := NewCountersigningTransport(nil, keys, tt.label)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			_, err = NewRequestHandlerWithLabel(httptest.NewRequest("GET", "/", nil), keys, tt.label)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestVerifyingHandlerFactory(t *testing.T) {
+	key := func(k contracts.KeyAlgorithm, public string, private string) config.SignatureInfo {
+		return config.SignatureInfo{
+			PublicKey:  config.KeyInfo{Type: k, Path: public},
+			PrivateKey: config.KeyInfo{Type: k, Path: private},
+		}
+	}
+
+	keys := []config.SignatureInfo{
+		key(contracts.KeyEd25519, "../../test/keys/ed25519/public.key", "../../test/keys/ed25519/private.key"),
+		key(contracts.KeyEcdsaP256, "../../test/keys/ecdsa-p256/public.pem", "../../test/keys/ecdsa-p256/private.pem"),
+	}
+	for _, k := range keys {
+		signing, err := NewSigningTransport(nil, k)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		server := httptest.NewServer(NewVerifyingHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := r.Context().Value(contracts.HttpRequestKey).(*http.Request); !ok {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}), k))
+
+		tests := []struct {
+			name   string
+			client *http.Client
+			status int
+		}{
+			{"signed request", &http.Client{Transport: signing}, http.StatusOK},
+			{"unsigned request", http.DefaultClient, http.StatusUnauthorized},
+		}
+		for _, tt := range tests {
+			t.Run(string(k.PrivateKey.Type)+" "+tt.name, func(t *testing.T) {
+				resp, err := tt.client.Get(server.URL + "/foo")
+				if err != nil {
+					t.Fatalf(err.Error())
+				}
+				resp.Body.Close()
+				assert.Equal(t, tt.status, resp.StatusCode)
+			})
+		}
+		server.Close()
+	}
+}
+
+func TestSignatureProviderWithInfoFactory(t *testing.T) {
+	key := func(k contracts.KeyAlgorithm, path string) config.SignatureInfo {
+		return config.SignatureInfo{PrivateKey: config.KeyInfo{Type: k, Path: path}, ReloadInterval: 30}
+	}
+
+	tests := []struct {
+		name        string
+		cfg         config.SignatureInfo
+		expectError bool
+	}{
+		{"ed25519 key", key(contracts.KeyEd25519, "../../test/keys/ed25519/private.key"), false},
+		{"ecdsa-p256 key", key(contracts.KeyEcdsaP256, "../../test/keys/ecdsa-p256/private.pem"), false},
+		{"secp256k1 key", key(contracts.KeyEcdsaSecp256k1, "../../test/keys/secp256k1/private.key"), false},
+		{"missing key file", key(contracts.KeyEcdsaP256, "../../test/keys/ecdsa-p256/missing.pem"), true},
+		{"malformed key file", key(contracts.KeyEcdsaP256, "../../test/keys/ecdsa-p256/public.pem"), true},
+		{"key without file", key(contracts.KeyVaultTransit, ""), false},
+		{"invalid key type", key("invalid", ""), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSignatureProviderWithInfo(tt.cfg)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+// registrations numbers the names registered by tests in the process-wide registries, which cannot be undone, so
+// that every run registers names of its own and the tests can be repeated with -count
+var registrations atomic.Int64
+
+// registrationName returns name suffixed to be unregistered in this process
+func registrationName(name string) string {
+	return fmt.Sprintf("%s-%d", name, registrations.Add(1))
+}
+
+// p384 is a key algorithm implemented outside the SDK, taking DER encoded keys
+type p384 struct{}
+
+func (p384) ParsePrivateKey(b []byte, key config.KeyInfo) (crypto.Signer, error) {
+	return x509.ParseECPrivateKey(b)
+}
+
+func (p384) ParsePublicKey(b []byte, key config.KeyInfo) (crypto.PublicKey, error) {
+	return x509.ParsePKIXPublicKey(b)
+}
+
+func (p384) Sign(signer crypto.Signer, content []byte) ([]byte, error) {
+	digest := sha512.Sum384(content)
+	return signer.Sign(rand.Reader, digest[:], crypto.SHA384)
+}
+
+func (p384) Verify(key crypto.PublicKey, content, signature []byte) (bool, error) {
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return false, errors.New("not an ecdsa public key")
+	}
+	digest := sha512.Sum384(content)
+	return ecdsa.VerifyASN1(pub, digest[:], signature), nil
+}
+
+func TestRegisterKeyAlgorithm(t *testing.T) {
+	algorithm := contracts.KeyAlgorithm(registrationName("ecdsa-p384"))
+	keyJson := []byte(fmt.Sprintf(`{"type":%q}`, algorithm))
+
+	var key config.KeyInfo
+	err := json.Unmarshal(keyJson, &key)
+	assert.Error(t, err, "unregistered algorithm should not validate")
+
+	tests := []struct {
+		name        string
+		algorithm   contracts.KeyAlgorithm
+		expectError bool
+	}{
+		{"new algorithm", algorithm, false},
+		{"registered twice", algorithm, true},
+		{"built-in algorithm", contracts.KeyEd25519, true},
+		{"empty name", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterKeyAlgorithm(tt.algorithm, p384{})
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+
+	err = json.Unmarshal(keyJson, &key)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	prv, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	prvDer, err := x509.MarshalECPrivateKey(prv)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	pubDer, err := x509.MarshalPKIXPublicKey(prv.Public())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	p, err := NewSignatureProvider(algorithm)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	content := []byte("content to sign")
+	key.Material = prvDer
+	signed, err := p.Sign(key, content)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	key.Material = pubDer
+	ok, err := p.Verify(key, content, []byte(signed))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = p.Verify(key, []byte("other content"), []byte(signed))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
 func TestAnnotatorFactory(t *testing.T) {
 	b, err := os.ReadFile("../../test/res/config.json")
 	if err != nil {
@@ -123,6 +445,8 @@ func TestAnnotatorFactory(t *testing.T) {
 	}{
 		{"valid pki type", cfg, contracts.AnnotationPKI, false},
 		{"valid httpPki type", cfg, contracts.AnnotationPKIHttp, false},
+		{"valid grpcPki type", cfg, contracts.AnnotationPKIGrpc, false},
+		{"valid httpSrc type", cfg, contracts.AnnotationSourceHttp, false},
 		{"valid src type", cfg, contracts.AnnotationSource, false},
 		{"valid tpm type", cfg, contracts.AnnotationTPM, false},
 		{"valid tls type", cfg, contracts.AnnotationTLS, false},
@@ -136,6 +460,179 @@ func TestAnnotatorFactory(t *testing.T) {
 	}
 }
 
+// geoAnnotator is an annotator implemented outside the SDK
+type geoAnnotator struct {
+	kind  contracts.AnnotationType
+	layer contracts.LayerType
+}
+
+func (g geoAnnotator) Do(ctx context.Context, data []byte) (contracts.Annotation, error) {
+	return contracts.NewAnnotation(string(data), contracts.SHA256Hash, "host", g.layer, g.kind, true), nil
+}
+
+func TestRegisterAnnotator(t *testing.T) {
+	kind := contracts.AnnotationType(registrationName("geo"))
+
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	custom := strings.Replace(string(b), `["tpm","pki"]`, fmt.Sprintf(`["tpm",%q]`, kind), 1)
+	var cfg config.SdkInfo
+	err = json.Unmarshal([]byte(custom), &cfg)
+	assert.Error(t, err, "unregistered annotation type should not validate")
+
+	newGeo := func(cfg config.SdkInfo, hash interfaces.HashProvider,
+		sign interfaces.SignatureProvider) interfaces.Annotator {
+		return geoAnnotator{kind: kind, layer: cfg.Layer}
+	}
+	tests := []struct {
+		name        string
+		kind        contracts.AnnotationType
+		fn          AnnotatorFunc
+		expectError bool
+	}{
+		{"new annotator", kind, newGeo, false},
+		{"registered twice", kind, newGeo, true},
+		{"built-in annotator", contracts.AnnotationTPM, newGeo, true},
+		{"empty name", "", newGeo, true},
+		{"no constructor", "other", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterAnnotator(tt.kind, tt.fn)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+
+	err = json.Unmarshal([]byte(custom), &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	a, err := NewAnnotator(cfg.Annotators[1], cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	annotation, err := a.Do(context.Background(), []byte("data"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Annotations of the registered kind can be read back
+	b, _ = json.Marshal(annotation)
+	var read contracts.Annotation
+	assert.NoError(t, json.Unmarshal(b, &read))
+	assert.Equal(t, kind, read.Kind)
+	assert.Equal(t, cfg.Layer, read.Layer)
+}
+
+func TestRegisterAnnotatorWithInfo(t *testing.T) {
+	kind := contracts.AnnotationType(registrationName("geo-fence"))
+
+	newGeo := func(cfg config.SdkInfo, hash interfaces.HashProvider,
+		sign interfaces.SignatureProvider) interfaces.Annotator {
+		return geoAnnotator{kind: kind, layer: cfg.Layer}
+	}
+	tests := []struct {
+		name        string
+		kind        contracts.AnnotationType
+		info        contracts.AnnotationTypeInfo
+		expectError bool
+	}{
+		{"invalid layer", kind, contracts.AnnotationTypeInfo{Layer: "unknown"}, true},
+		{"new annotator", kind, contracts.AnnotationTypeInfo{Description: "the device is within its fence",
+			Layer: contracts.Host}, false},
+		{"registered twice", kind, contracts.AnnotationTypeInfo{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterAnnotatorWithInfo(tt.kind, tt.info, newGeo)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+
+	info, ok := kind.Info()
+	assert.True(t, ok)
+	assert.Equal(t, "the device is within its fence", info.Description)
+
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// The default layer of the kind applies when none is configured
+	for _, layer := range []contracts.LayerType{contracts.Application, ""} {
+		cfg.Layer = layer
+		a, err := NewAnnotator(kind, cfg)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		annotation, err := a.Do(context.Background(), []byte("data"))
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		if layer == "" {
+			layer = contracts.Host
+		}
+		assert.Equal(t, layer, annotation.Layer)
+	}
+}
+
+func TestErrorClasses(t *testing.T) {
+	b, err := os.ReadFile("../../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	missingKey := cfg.Signature
+	missingKey.PrivateKey.Path = "missing.key"
+
+	tests := []struct {
+		name     string
+		err      func() error
+		expected error
+	}{
+		{"invalid config", func() error {
+			var info config.HashInfo
+			return json.Unmarshal([]byte(`{"type":"sha1024"}`), &info)
+		}, contracts.ErrConfigInvalid},
+		{"unsupported hash", func() error {
+			_, err := NewHashProvider("sha1024")
+			return err
+		}, contracts.ErrHashUnsupported},
+		{"unsupported key", func() error {
+			_, err := NewSignatureProvider("rsa")
+			return err
+		}, contracts.ErrKeyUnsupported},
+		{"unavailable key", func() error {
+			_, err := NewSignatureProviderWithInfo(missingKey)
+			return err
+		}, contracts.ErrKeyUnavailable},
+		{"unsupported annotator", func() error {
+			_, err := NewAnnotator("invalid", cfg)
+			return err
+		}, contracts.ErrAnnotatorUnsupported},
+		{"unsupported stream", func() error {
+			_, err := NewStreamProvider(config.StreamInfo{Type: "invalid"}, nil)
+			return err
+		}, contracts.ErrStreamUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.err(), tt.expected)
+		})
+	}
+}
+
 func TestRequestHandlerFactory(t *testing.T) {
 
 	type sample struct {
@@ -0,0 +1,65 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package pkg
+
+import (
+	"errors"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+var (
+	// ErrQueueFull is the cause of failures to queue work in asynchronous mode under the drop policy
+	ErrQueueFull = errors.New("async queue full")
+	// ErrShutdown is the cause of failures to queue work once the SDK has been shut down
+	ErrShutdown = errors.New("sdk is shut down")
+	// ErrUnsatisfied is the cause of failures to satisfy a required annotation under the abort policy, see
+	// config.PipelineInfo
+	ErrUnsatisfied = errors.New("annotation not satisfied")
+	// ErrNoJournal is returned by Replay when the SDK was not given a journal, see WithJournal
+	ErrNoJournal = errors.New("no journal")
+)
+
+// Stage identifies the step of an SDK operation that failed
+type Stage string
+
+const (
+	StageQueue    Stage = "queue"    // the work could not be queued in asynchronous mode
+	StageHash     Stage = "hash"     // the data of CreateFromReader, CreateForFile or CreateMerkleBatch could not be hashed
+	StageAnnotate Stage = "annotate" // an annotator returned an error
+	StageJournal  Stage = "journal"  // the annotations could not be recorded in the journal, they are still published
+	StagePublish  Stage = "publish"  // the annotations could not be published
+)
+
+// Failure describes an SDK operation that could not be completed, see WithErrorHandler
+type Failure struct {
+	Action message.SdkAction
+	Stage  Stage
+	// Annotator is the kind of annotator that failed at StageAnnotate, when it reports one
+	Annotator contracts.AnnotationType
+	// Annotations holds the annotations that were made but not published at StagePublish, so they can be retried, or
+	// not recorded at StageJournal
+	Annotations []contracts.Annotation
+	Err         error
+}
+
+func (f Failure) Error() string {
+	return string(f.Action) + " failed to " + string(f.Stage) + ": " + f.Err.Error()
+}
+
+func (f Failure) Unwrap() error {
+	return f.Err
+}
@@ -0,0 +1,100 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package filehash derives annotation Keys for files and directory trees, such as build artifacts, so that
+// annotators do not each need to implement traversal and chunking.
+package filehash
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// HashFile derives the hash of a file's content without loading it into memory
+func HashFile(hash interfaces.HashProviderStream, name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return hash.DeriveFromReader(f)
+}
+
+// HashDirectory derives a single hash for the regular files beneath root. Each file is hashed and listed with its
+// slash separated path relative to root, in lexical order, and the resulting manifest is hashed in turn. The result
+// therefore changes if any file is added, removed, renamed or modified, but not if the tree is moved or copied
+// elsewhere. Empty directories, symbolic links and other special files are not included.
+//
+// Ignore patterns use path.Match syntax and are matched against both the relative path and the base name of each
+// entry. A matching directory is skipped entirely.
+func HashDirectory(hash interfaces.HashProviderStream, root string, ignore []string) (string, error) {
+	for _, pattern := range ignore {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return "", fmt.Errorf("invalid ignore pattern %s: %w", pattern, err)
+		}
+	}
+
+	var manifest strings.Builder
+	err := filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ignored(rel, ignore) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		h, err := HashFile(hash, name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&manifest, "%s  %s\n", h, rel)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hash.Derive([]byte(manifest.String())), nil
+}
+
+func ignored(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, rel); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, path.Base(rel)); matched {
+			return true
+		}
+	}
+	return false
+}
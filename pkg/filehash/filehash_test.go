@@ -0,0 +1,130 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package filehash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hashprovider/sha256"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTree creates the given files, keyed by slash separated relative path, beneath a new directory
+func writeTree(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf(err.Error())
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+	return root
+}
+
+func TestHashFile(t *testing.T) {
+	root := writeTree(t, map[string]string{"artifact.bin": "foo"})
+
+	tests := []struct {
+		name        string
+		path        string
+		expected    string
+		expectError bool
+	}{
+		{"file", filepath.Join(root, "artifact.bin"),
+			"2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", false},
+		{"missing file", filepath.Join(root, "missing.bin"), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := HashFile(sha256.New(), tt.path)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestHashDirectory(t *testing.T) {
+	files := map[string]string{
+		"bin/app":             "binary",
+		"docs/readme.md":      "docs",
+		"config.yaml":         "config",
+		".git/HEAD":           "ref: refs/heads/main",
+		"logs/build.log":      "log",
+		"bin/debug.log":       "log",
+		"docs/empty/.gitkeep": "",
+	}
+	ignore := []string{".git", "*.log"}
+	hash := sha256.New()
+
+	expected, err := HashDirectory(hash, writeTree(t, files), ignore)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	withoutIgnored := map[string]string{}
+	for name, content := range files {
+		withoutIgnored[name] = content
+	}
+	delete(withoutIgnored, ".git/HEAD")
+	delete(withoutIgnored, "bin/debug.log")
+	withoutIgnored["logs/build.log"] = "different log"
+
+	modified := map[string]string{}
+	for name, content := range withoutIgnored {
+		modified[name] = content
+	}
+	modified["config.yaml"] = "changed"
+
+	renamed := map[string]string{}
+	for name, content := range withoutIgnored {
+		renamed[name] = content
+	}
+	delete(renamed, "config.yaml")
+	renamed["settings.yaml"] = "config"
+
+	tests := []struct {
+		name        string
+		files       map[string]string
+		ignore      []string
+		expectEqual bool
+		expectError bool
+	}{
+		{"same tree elsewhere", files, ignore, true, false},
+		{"ignored entries differ", withoutIgnored, ignore, true, false},
+		{"modified file", modified, ignore, false, false},
+		{"renamed file", renamed, ignore, false, false},
+		{"nothing ignored", files, nil, false, false},
+		{"invalid pattern", files, []string{"["}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := HashDirectory(hash, writeTree(t, tt.files), tt.ignore)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, tt.expectEqual, result == expected)
+			}
+		})
+	}
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := HashDirectory(hash, filepath.Join(t.TempDir(), "missing"), nil)
+		assert.Error(t, err)
+	})
+}
@@ -21,3 +21,15 @@ import (
 type Annotator interface {
 	Do(ctx context.Context, data []byte) (contracts.Annotation, error)
 }
+
+// BatchAnnotator is optionally implemented by annotators able to annotate many pieces of data at once, signing the
+// resulting annotations together rather than one at a time
+type BatchAnnotator interface {
+	DoBatch(ctx context.Context, data [][]byte) ([]contracts.Annotation, error)
+}
+
+// KindReporter is optionally implemented by annotators able to tell the kind of annotation they make before making
+// it, which allows them to be selected per call (see CallOptions)
+type KindReporter interface {
+	Kind() contracts.AnnotationType
+}
@@ -14,7 +14,25 @@
 
 package interfaces
 
+import "io"
+
 type HashProvider interface {
 	// Derive converts data to an hash value.
 	Derive(data []byte) string
 }
+
+// HashProviderStream is implemented by hash providers that can derive a hash value incrementally, allowing large
+// payloads such as files or video segments to be annotated without holding them in memory.
+type HashProviderStream interface {
+	HashProvider
+
+	// DeriveFromReader converts the data read from r until EOF to a hash value.
+	DeriveFromReader(r io.Reader) (string, error)
+}
+
+// TenantHashProvider is implemented by hash providers whose hash values differ per tenant, such as when each tenant
+// has its own salt.
+type TenantHashProvider interface {
+	// ForTenant returns the hash provider to use for data annotated on behalf of the given tenant.
+	ForTenant(tenant string) (HashProvider, error)
+}
@@ -0,0 +1,35 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package interfaces
+
+import (
+	"github.com/oklog/ulid/v2"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// IdGenerator generates the identifiers of annotations in place of the default ULIDs. Identifiers are 128 bits wide
+// and rendered in ULID text form; generators of narrower identifiers, such as snowflake IDs, leave the remaining
+// bits zero.
+type IdGenerator interface {
+	// NewId returns the identifier of the annotation, which is complete but for its Id, KeyId, PrevHash and Signature
+	NewId(annotation contracts.Annotation) ulid.ULID
+}
+
+// IdGeneratorFunc adapts a function to the IdGenerator interface
+type IdGeneratorFunc func(annotation contracts.Annotation) ulid.ULID
+
+// NewId calls f(annotation)
+func (f IdGeneratorFunc) NewId(annotation contracts.Annotation) ulid.ULID {
+	return f(annotation)
+}
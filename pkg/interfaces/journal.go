@@ -0,0 +1,47 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package interfaces
+
+import (
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+// AnnotationJournal persists the annotations made by the SDK so that they can be queried locally, such as during an
+// audit, and re-published after an outage, see pkg.WithJournal. Implementations must be safe for concurrent use.
+type AnnotationJournal interface {
+	// Record persists the annotations about to be published for the given action
+	Record(action message.SdkAction, items []contracts.Annotation) error
+	// Acknowledge marks recorded annotations as published
+	Acknowledge(items []contracts.Annotation) error
+	// Replayable returns the recorded publishes selected by q, oldest first
+	Replayable(q ReplayQuery) ([]JournalEntry, error)
+}
+
+// ReplayQuery selects the publishes re-sent by Sdk.Replay. Its zero value selects every publish that was never
+// acknowledged.
+type ReplayQuery struct {
+	From time.Time // From excludes publishes recorded before it, unless it is zero
+	To   time.Time // To excludes publishes recorded at or after it, unless it is zero
+	// Acknowledged includes the publishes that were acknowledged, re-sending every publish within the time range
+	Acknowledged bool
+}
+
+// JournalEntry is a publish recorded by an AnnotationJournal
+type JournalEntry struct {
+	Action      message.SdkAction
+	Annotations []contracts.Annotation
+}
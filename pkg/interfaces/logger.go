@@ -29,3 +29,11 @@ type Logger interface {
 	// Write flushes the LogEntry to StdErr in JSON format.
 	Error(message string, args ...any)
 }
+
+// ComponentLogger is a Logger that can attribute its entries to a component of the SDK, such as the stream provider,
+// so that they can be filtered or sampled by component.
+type ComponentLogger interface {
+	Logger
+	// Component returns a logger whose entries are attributed to the named component.
+	Component(name string) Logger
+}
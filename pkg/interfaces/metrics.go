@@ -0,0 +1,54 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package interfaces
+
+import (
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// PublishMetrics receives measurements of stream provider activity so that applications can export them to their
+// monitoring system. Implementations must be safe for concurrent use and should return quickly, as they are called
+// on the publish path.
+type PublishMetrics interface {
+	// Published is called after every publish attempt made to the provider with the size of the wrapper content,
+	// how long the attempt took and the error it returned, if any
+	Published(stream contracts.StreamType, bytes int, latency time.Duration, err error)
+	// Reconnected is called when the provider re-establishes a dropped connection
+	Reconnected(stream contracts.StreamType)
+}
+
+// ReconnectReporter is optionally implemented by stream providers that maintain a connection, allowing reconnects to
+// be observed
+type ReconnectReporter interface {
+	// OnReconnect registers a handler called whenever a dropped connection is re-established
+	OnReconnect(handler func())
+}
+
+// AnnotationMetrics receives the annotations created by the SDK, see pkg.WithAnnotationMetrics. Implementations must be
+// safe for concurrent use.
+type AnnotationMetrics interface {
+	// Annotated is called for every annotation the SDK publishes
+	Annotated(kind contracts.AnnotationType, layer contracts.LayerType, satisfied bool)
+}
+
+// SignMetrics receives measurements of signing, see factories.NewAnnotatorWithMetrics. Implementations must be safe
+// for concurrent use and should return quickly, as they are called on the signing path.
+type SignMetrics interface {
+	// Signed is called after every signature made with a key of the given algorithm, with how long signing took and
+	// the error it returned, if any. A batch signature counts once.
+	Signed(algorithm contracts.KeyAlgorithm, latency time.Duration, err error)
+}
@@ -23,5 +23,22 @@ type RequestHandler interface {
 	// AddSignatureHeaders takes time of creation of request, the fields to be taken into consideration
 	// for the SignatureInput header, and the keys to be used in signing the seed.
 	// Assembles the SignatureInput and Signature fields, then adds them to the request as headers.
+	// Without fields, the components set by keys.Http.Request are covered.
 	AddSignatureHeaders(ticks time.Time, fields []string, keys config.SignatureInfo) error
 }
+
+type ResponseHandler interface {
+	// AddSignatureHeaders takes time of creation of response, the fields to be taken into consideration
+	// for the SignatureInput header, among which @status, and the keys to be used in signing the seed.
+	// Assembles the SignatureInput and Signature fields, then adds them to the response as headers.
+	// Without fields, the components set by keys.Http.Response are covered.
+	AddSignatureHeaders(ticks time.Time, fields []string, keys config.SignatureInfo) error
+}
+
+// NonceStore remembers the nonces of verified HTTP message signatures, so that a replayed request is rejected. Stores
+// shared by several instances, such as one backed by Redis, protect a service that is scaled out.
+type NonceStore interface {
+	// Seen records the nonce until the given time, or indefinitely if it is zero, and reports whether it was already
+	// recorded
+	Seen(nonce string, until time.Time) bool
+}
@@ -15,9 +15,30 @@ package interfaces
 
 import (
 	"context"
+	"io"
 	"sync"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/merkle"
 )
 
+// CallOptions customize a single call to one of the Create methods, Mutate, Transit or Publish, allowing one SDK
+// instance to apply different criteria to different categories of data. Annotators are identified by the kind they
+// report through KindReporter, those that do not report one are only applied when no Annotators are given.
+type CallOptions struct {
+	Annotators []contracts.AnnotationType // Annotators restricts the call to annotators of these kinds, if given
+	Without    []contracts.AnnotationType // Without excludes annotators of these kinds from the call
+	ParentKey  string                     // ParentKey is the hash of the data received by Transit as it was sent
+	// Delivered is called once the annotations published by the call have been delivered, or with the error that
+	// prevented it, see pkg.WithReceipt
+	Delivered func(err error)
+}
+
+// CallOption sets one of the CallOptions, see pkg.WithAnnotators, pkg.Without, pkg.WithParentKey and
+// pkg.OnDelivered
+type CallOption func(*CallOptions)
+
 type Sdk interface {
 	// BootstrapHandler provides a hook whereby shutdown signals can be trapped and gracefull handled in order to clean
 	// up resources and active connections.
@@ -25,23 +46,78 @@ type Sdk interface {
 
 	// Create handles annotations relative to the creation of new data
 	// The data parameter is the given piece of data being created marshalled as a byte array. This in turn will be
-	// used to generate a hash uniquely identifying the data item.
-	Create(ctx context.Context, data []byte)
+	// used to generate a hash uniquely identifying the data item. The annotators applied can be selected with opts.
+	Create(ctx context.Context, data []byte, opts ...CallOption)
+
+	// CreateFromReader handles annotations relative to the creation of new data that is too large to be held in memory,
+	// such as files or video segments. The data is read from r until EOF and hashed incrementally, requiring the
+	// configured hash provider to implement HashProviderStream. Annotators that inspect the content of the data, such
+	// as PKI, cannot be used with this method. The annotators applied can be selected with opts.
+	CreateFromReader(ctx context.Context, r io.Reader, opts ...CallOption)
+
+	// CreateForFile handles annotations relative to the creation of a file or directory tree, such as a build
+	// artifact. A directory is identified by a hash over the paths and contents of its files, excluding those matching
+	// the ignore patterns (see filehash.HashDirectory). Like CreateFromReader, the configured hash provider must
+	// implement HashProviderStream and annotators that inspect the content of the data cannot be used. The annotators
+	// applied can be selected with opts.
+	CreateForFile(ctx context.Context, path string, ignore []string, opts ...CallOption)
+
+	// CreateBatch handles annotations relative to the creation of many independent data items at once, such as high
+	// frequency telemetry. Each item is annotated on its own, but the annotations made by each annotator are signed
+	// together with a single batch signature (see SignatureProvider.SignBatch) and published as one AnnotationList.
+	// Unlike with CreateMerkleBatch, each item can be verified without the rest of the batch. The annotators applied
+	// can be selected with opts.
+	CreateBatch(ctx context.Context, items [][]byte, opts ...CallOption)
+
+	// CreateMerkleBatch handles annotations relative to the creation of a batch of data items, such as high rate
+	// sensor readings. A single set of annotations is created for the Merkle root over the items, which requires the
+	// merkle-sha256 hash type. The returned proofs, one per item in order, allow each item to be shown to be part of
+	// the annotated batch. Nil is returned if the batch could not be annotated. The annotators applied can be selected
+	// with opts.
+	CreateMerkleBatch(ctx context.Context, items [][]byte, opts ...CallOption) []merkle.Proof
 
 	// Mutate handles annotations relative to a data modification. That is to say, an older piece of data is being
 	// updated or transformed into new data.
 	// The old, new parameters are the given data elements marshalled as byte arrays. You must have byte representations
-	// of both the old and the new data in order to establish a provenance linkage through Mutate.
-	Mutate(ctx context.Context, old, new []byte)
+	// of both the old and the new data in order to establish a provenance linkage through Mutate. The annotators applied
+	// to the new data can be selected with opts, the source annotation of the old data is always made. The annotations
+	// of the new data carry the hash of the old data as their ParentKey, linking the two.
+	Mutate(ctx context.Context, old, new []byte, opts ...CallOption)
 
 	// Transit is a proposed method for cases where an existing piece of data is received by a separate application
 	// that is not the originator of the data. The data has simply transited from one application/host to the other.
 	// This method could be used to asses the signature validity on the received data, secure comms, checksum validation,
-	// etc.
-	Transit(ctx context.Context, data []byte)
+	// etc. The hash of the data as it was sent can be supplied with pkg.WithParentKey to record the chain of custody.
+	Transit(ctx context.Context, data []byte, opts ...CallOption)
 
 	// Publish is proposed to provide extensibility for annotators that may need to attest to the state of data before it
 	// is sent over the wire. Publish could also be useful in cases where the downstream host receiving the data isn't
 	// running Alvarium-enabled applications.
-	Publish(ctx context.Context, data []byte)
+	Publish(ctx context.Context, data []byte, opts ...CallOption)
+
+	// Drain waits until the work queued by Create, Mutate, Transit and Publish in asynchronous mode (see
+	// config.AsyncInfo) has been published, or until ctx is done. On shutdown, queued work is drained before the
+	// stream provider is closed.
+	Drain(ctx context.Context) error
+
+	// Healthy reports whether the configured stream provider is able to publish annotations, so that applications
+	// can surface it through their readiness probes. It returns an error if the SDK has not been bootstrapped.
+	Healthy(ctx context.Context) error
+
+	// Reconfigure switches the SDK over to cfg at runtime, replacing the annotators, the stream provider and the keys
+	// in use without restarting the application. The switch is atomic: each annotation is made and published
+	// entirely under either the previous or the new configuration. If cfg cannot be applied, an error is returned and
+	// the previous configuration stays in effect.
+	Reconfigure(ctx context.Context, cfg config.SdkInfo) error
+
+	// Replay re-publishes the annotations recorded in the journal given with pkg.WithJournal, selected by q, in the
+	// order they were first published. By default, the publishes that were never acknowledged by the stream provider
+	// are re-sent, reconciling the backend with the device after an outage. Replay stops at the first publish that
+	// fails, returning the number of publishes re-sent.
+	Replay(ctx context.Context, q ReplayQuery) (int, error)
+
+	// Health reports the status of each component of the SDK: the stream provider as for Healthy, the availability
+	// of the signing key and the outcome of the last annotation made by each annotator. It is suitable for readiness
+	// endpoints, which can serve the result as JSON.
+	Health(ctx context.Context) contracts.Health
 }
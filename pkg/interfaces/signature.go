@@ -15,6 +15,8 @@
 package interfaces
 
 import (
+	"crypto"
+
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
 )
 
@@ -23,4 +25,22 @@ type SignatureProvider interface {
 	Sign(key config.KeyInfo, content []byte) (string, error)
 	// Verify is the interface method using a public key to verify the signature derived from some piece of content
 	Verify(key config.KeyInfo, content, signed []byte) (bool, error)
+	// SignBatch signs many pieces of content with a single signature over their Merkle root, returning one signature
+	// per content that carries its inclusion proof. This saves a call per item on backends with per-call latency,
+	// such as HSMs and KMS.
+	SignBatch(key config.KeyInfo, contents [][]byte) ([]string, error)
+}
+
+// KeyAlgorithm is a signature algorithm over standard library key types. An algorithm registered with
+// factories.RegisterKeyAlgorithm is wrapped in a SignatureProvider which reads and caches its keys, uses
+// KeyInfo.Signer when given and hex encodes the signatures.
+type KeyAlgorithm interface {
+	// ParsePrivateKey parses private key material read from a file, a URL or KeyInfo.Material
+	ParsePrivateKey(b []byte, key config.KeyInfo) (crypto.Signer, error)
+	// ParsePublicKey parses public key material read from a file, a URL or KeyInfo.Material
+	ParsePublicKey(b []byte, key config.KeyInfo) (crypto.PublicKey, error)
+	// Sign returns the signature of the content made with the signer
+	Sign(signer crypto.Signer, content []byte) ([]byte, error)
+	// Verify reports whether the signature of the content was made with the private key of the public key
+	Verify(key crypto.PublicKey, content, signature []byte) (bool, error)
 }
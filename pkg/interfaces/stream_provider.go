@@ -13,10 +13,33 @@
  *******************************************************************************/
 package interfaces
 
-import "github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+import (
+	"context"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
 
 type StreamProvider interface {
 	Close() error
-	Connect() error
-	Publish(msg message.PublishWrapper) error
+	// Connect establishes the connection to the backing platform. Providers stop waiting on an unreachable
+	// platform once ctx is done.
+	Connect(ctx context.Context) error
+	// Publish sends the message. The context of the SDK call is passed through, so a caller can abandon a publish
+	// stuck on a reconnect or a retry backoff by canceling it or giving it a deadline.
+	Publish(ctx context.Context, msg message.PublishWrapper) error
+}
+
+// PublishFunc publishes a wrapper to the configured stream provider
+type PublishFunc func(ctx context.Context, msg message.PublishWrapper) error
+
+// PublishInterceptor wraps the publishing of the wrappers created by the SDK, see pkg.WithPublishInterceptors. An
+// interceptor may change or enrich the wrapper before passing it to next, drop it by returning without calling next,
+// or observe the outcome of next to audit it.
+type PublishInterceptor func(next PublishFunc) PublishFunc
+
+// HealthChecker is optionally implemented by stream providers that can report whether their backing platform is
+// reachable. Providers that do not implement it are assumed to be healthy.
+type HealthChecker interface {
+	// Healthy returns an error describing why the provider is unable to publish, or nil if it is ready
+	Healthy(ctx context.Context) error
 }
@@ -0,0 +1,43 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package interfaces
+
+import (
+	"context"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// TagWriter exports the identifiers that the layers below are to record as the Tag of their annotations, such as the
+// commit SHA and image digest of the workload exported by the CI/CD layer, linking their scores to those of the
+// exporting layer.
+type TagWriter interface {
+	// WriteTag exports tags, replacing any tags exported before
+	WriteTag(ctx context.Context, tags ...string) error
+}
+
+// TagValueGetter returns the Tag of the annotations made at a layer, the identifier exported by a TagWriter of the
+// layer it is linked to. The tag is looked up for every annotation, so that one exported later is picked up.
+type TagValueGetter interface {
+	// GetTagValue returns the tags of the annotations made at layer, empty if none has been exported
+	GetTagValue(layer contracts.LayerType) contracts.TagList
+}
+
+// TagValueGetterFunc adapts a function to the TagValueGetter interface
+type TagValueGetterFunc func(layer contracts.LayerType) contracts.TagList
+
+// GetTagValue calls f(layer)
+func (f TagValueGetterFunc) GetTagValue(layer contracts.LayerType) contracts.TagList {
+	return f(layer)
+}
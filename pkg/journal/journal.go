@@ -0,0 +1,355 @@
+//go:build !alvarium_core && !js && !wasip1 && !tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package journal persists the annotations made by the SDK in an embedded database on the device, indexed by the
+// hash of the data annotated, by kind and by time, so that devices can answer what they attested about a piece of
+// data during an audit without reaching the backend.
+//
+// A Journal is handed to the SDK with pkg.WithJournal, which records every annotation before it is published, and is
+// queried by the application through the same instance, the database file being locked while it is open. Annotations
+// are recorded along with the publish that carried them, which is acknowledged once the stream provider accepts it,
+// so that publishes lost to an outage can be re-sent with Sdk.Replay.
+package journal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	bolt "go.etcd.io/bbolt"
+)
+
+const openTimeout = time.Second
+
+var (
+	annotationsBucket = []byte("annotations") // annotationsBucket holds the annotations by entry, see entryKey
+	keysBucket        = []byte("keys")        // keysBucket indexes entries by the Key of their annotation
+	kindsBucket       = []byte("kinds")       // kindsBucket indexes entries by the Kind of their annotation
+	publishesBucket   = []byte("publishes")   // publishesBucket holds the publishes by publish key, see publishKey
+	pendingBucket     = []byte("pending")     // pendingBucket holds the publish keys of unacknowledged publishes
+	publishedBucket   = []byte("published")   // publishedBucket maps entries to the publish key that carried them
+)
+
+// publish is a recorded publish, the annotations it carried being referenced by entry
+type publish struct {
+	Action  message.SdkAction `json:"action"`
+	Entries [][]byte          `json:"entries"`
+}
+
+// Query selects annotations from the journal. Its zero value selects every annotation.
+type Query struct {
+	Key   string                   // Key restricts the results to the annotations of the data with this hash
+	Kind  contracts.AnnotationType // Kind restricts the results to annotations of this kind
+	From  time.Time                // From excludes annotations made before it, unless it is zero
+	To    time.Time                // To excludes annotations made at or after it, unless it is zero
+	Limit int                      // Limit is the maximum number of results, unlimited if zero
+}
+
+// Journal is a local store of annotations, safe for concurrent use
+type Journal struct {
+	db        *bolt.DB
+	retention time.Duration
+}
+
+// Open opens the journal at the configured path, creating it if need be
+func Open(cfg config.JournalInfo) (*Journal, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("%w: journal path is required", contracts.ErrConfigInvalid)
+	}
+	if cfg.Retention < 0 {
+		return nil, fmt.Errorf("%w: invalid journal retention %v", contracts.ErrConfigInvalid, cfg.Retention)
+	}
+
+	db, err := bolt.Open(cfg.Path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{annotationsBucket, keysBucket, kindsBucket, publishesBucket, pendingBucket,
+			publishedBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Journal{db: db, retention: time.Duration(cfg.Retention) * time.Millisecond}, nil
+}
+
+// Record persists the annotations about to be published for action as an unacknowledged publish, discarding those
+// that have outlived the retention period
+func (j *Journal) Record(action message.SdkAction, items []contracts.Annotation) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return j.db.Update(func(tx *bolt.Tx) error {
+		annotations := tx.Bucket(annotationsBucket)
+		keys := tx.Bucket(keysBucket)
+		kinds := tx.Bucket(kindsBucket)
+		publishes := tx.Bucket(publishesBucket)
+		published := tx.Bucket(publishedBucket)
+
+		seq, err := publishes.NextSequence()
+		if err != nil {
+			return err
+		}
+		latest := items[0].Timestamp
+		for _, a := range items[1:] {
+			if a.Timestamp.After(latest) {
+				latest = a.Timestamp
+			}
+		}
+		key := publishKey(latest, seq)
+		p := publish{Action: action}
+		for _, a := range items {
+			b, err := json.Marshal(a)
+			if err != nil {
+				return err
+			}
+			entry := entryKey(a)
+			if err = annotations.Put(entry, b); err != nil {
+				return err
+			}
+			if err = keys.Put(indexKey(a.Key, entry), nil); err != nil {
+				return err
+			}
+			if err = kinds.Put(indexKey(string(a.Kind), entry), nil); err != nil {
+				return err
+			}
+			if err = published.Put(entry, key); err != nil {
+				return err
+			}
+			p.Entries = append(p.Entries, entry)
+		}
+		b, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		if err = publishes.Put(key, b); err != nil {
+			return err
+		}
+		if err = tx.Bucket(pendingBucket).Put(key, nil); err != nil {
+			return err
+		}
+		if j.retention > 0 {
+			return prune(tx, time.Now().Add(-j.retention))
+		}
+		return nil
+	})
+}
+
+// Acknowledge marks the publishes that carried the annotations as delivered, so that they are not replayed
+func (j *Journal) Acknowledge(items []contracts.Annotation) error {
+	return j.db.Update(func(tx *bolt.Tx) error {
+		published := tx.Bucket(publishedBucket)
+		pending := tx.Bucket(pendingBucket)
+		for _, a := range items {
+			if key := published.Get(entryKey(a)); key != nil {
+				if err := pending.Delete(key); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Replayable returns the publishes selected by q, oldest first. Annotations discarded since their publish was
+// recorded are left out.
+func (j *Journal) Replayable(q interfaces.ReplayQuery) ([]interfaces.JournalEntry, error) {
+	var results []interfaces.JournalEntry
+	err := j.db.View(func(tx *bolt.Tx) error {
+		annotations := tx.Bucket(annotationsBucket)
+		publishes := tx.Bucket(publishesBucket)
+		index := tx.Bucket(pendingBucket)
+		if q.Acknowledged {
+			index = publishes
+		}
+
+		c := index.Cursor()
+		var start []byte
+		if !q.From.IsZero() {
+			start = timeKey(q.From)
+		}
+		for k, _ := c.Seek(start); k != nil; k, _ = c.Next() {
+			if !q.To.IsZero() && bytes.Compare(k[:8], timeKey(q.To)) >= 0 {
+				break
+			}
+			var p publish
+			if err := json.Unmarshal(publishes.Get(k), &p); err != nil {
+				return err
+			}
+			entry := interfaces.JournalEntry{Action: p.Action}
+			for _, e := range p.Entries {
+				b := annotations.Get(e)
+				if b == nil {
+					continue
+				}
+				var a contracts.Annotation
+				if err := json.Unmarshal(b, &a); err != nil {
+					return err
+				}
+				entry.Annotations = append(entry.Annotations, a)
+			}
+			if len(entry.Annotations) > 0 {
+				results = append(results, entry)
+			}
+		}
+		return nil
+	})
+	return results, err
+}
+
+// ByKey returns the annotations of the data with the given hash, oldest first
+func (j *Journal) ByKey(key string) ([]contracts.Annotation, error) {
+	return j.Query(Query{Key: key})
+}
+
+// Query returns the annotations selected by q, oldest first
+func (j *Journal) Query(q Query) ([]contracts.Annotation, error) {
+	var results []contracts.Annotation
+	err := j.db.View(func(tx *bolt.Tx) error {
+		annotations := tx.Bucket(annotationsBucket)
+
+		// scan the narrowest index, entries sort by time within each value of an index
+		var index *bolt.Bucket
+		var prefix []byte
+		switch {
+		case q.Key != "":
+			index, prefix = tx.Bucket(keysBucket), indexKey(q.Key, nil)
+		case q.Kind != "":
+			index, prefix = tx.Bucket(kindsBucket), indexKey(string(q.Kind), nil)
+		default:
+			index = annotations
+		}
+
+		c := index.Cursor()
+		start := prefix
+		if !q.From.IsZero() {
+			start = append(bytes.Clone(prefix), timeKey(q.From)...)
+		}
+		for k, _ := c.Seek(start); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			entry := k[len(prefix):]
+			if !q.To.IsZero() && bytes.Compare(entry[:8], timeKey(q.To)) >= 0 {
+				break
+			}
+			var a contracts.Annotation
+			if err := json.Unmarshal(annotations.Get(entry), &a); err != nil {
+				return err
+			}
+			if q.Kind != "" && a.Kind != q.Kind {
+				continue
+			}
+			results = append(results, a)
+			if q.Limit > 0 && len(results) == q.Limit {
+				break
+			}
+		}
+		return nil
+	})
+	return results, err
+}
+
+// Close closes the journal database
+func (j *Journal) Close() error {
+	return j.db.Close()
+}
+
+// prune deletes the annotations and publishes made before cutoff along with their index entries
+func prune(tx *bolt.Tx, cutoff time.Time) error {
+	annotations := tx.Bucket(annotationsBucket)
+	keys := tx.Bucket(keysBucket)
+	kinds := tx.Bucket(kindsBucket)
+	published := tx.Bucket(publishedBucket)
+	publishes := tx.Bucket(publishesBucket)
+	pending := tx.Bucket(pendingBucket)
+
+	for _, key := range expired(publishes, cutoff) {
+		if err := publishes.Delete(key); err != nil {
+			return err
+		}
+		if err := pending.Delete(key); err != nil {
+			return err
+		}
+	}
+	for _, entry := range expired(annotations, cutoff) {
+		var a contracts.Annotation
+		if err := json.Unmarshal(annotations.Get(entry), &a); err != nil {
+			return err
+		}
+		if err := keys.Delete(indexKey(a.Key, entry)); err != nil {
+			return err
+		}
+		if err := kinds.Delete(indexKey(string(a.Kind), entry)); err != nil {
+			return err
+		}
+		if err := published.Delete(entry); err != nil {
+			return err
+		}
+		if err := annotations.Delete(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expired returns the keys of the bucket, which start with a time key, that precede cutoff. They are collected before
+// being deleted, as deleting while iterating skips keys.
+func expired(bucket *bolt.Bucket, cutoff time.Time) [][]byte {
+	var keys [][]byte
+	c := bucket.Cursor()
+	for k, _ := c.First(); k != nil && bytes.Compare(k[:8], timeKey(cutoff)) < 0; k, _ = c.Next() {
+		keys = append(keys, bytes.Clone(k))
+	}
+	return keys
+}
+
+// entryKey identifies an annotation in the journal by the time it was made followed by its Id, so that entries sort
+// by time and annotations sharing an Id, such as those made with deterministic identifiers, are all kept
+func entryKey(a contracts.Annotation) []byte {
+	return append(timeKey(a.Timestamp), a.Id[:]...)
+}
+
+// publishKey identifies a publish in the journal by the time of its latest annotation, so that it is kept as long as
+// any of them, followed by a sequence number
+func publishKey(t time.Time, seq uint64) []byte {
+	return binary.BigEndian.AppendUint64(timeKey(t), seq)
+}
+
+// timeKey encodes t so that keys sort in time order
+func timeKey(t time.Time) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(t.UnixNano()))
+	return k
+}
+
+// indexKey is the key of an entry in an index, the indexed value followed by a separator and the entry
+func indexKey(value string, entry []byte) []byte {
+	k := make([]byte, 0, len(value)+1+len(entry))
+	k = append(k, value...)
+	k = append(k, 0)
+	return append(k, entry...)
+}
@@ -0,0 +1,189 @@
+//go:build !alvarium_core && !js && !wasip1 && !tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package journal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.JournalInfo
+		expectError bool
+	}{
+		{"valid journal", config.JournalInfo{Path: filepath.Join(t.TempDir(), "journal.db")}, false},
+		{"valid retention", config.JournalInfo{Path: filepath.Join(t.TempDir(), "journal.db"), Retention: 1000}, false},
+		{"missing path", config.JournalInfo{}, true},
+		{"invalid retention", config.JournalInfo{Path: filepath.Join(t.TempDir(), "journal.db"), Retention: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, err := Open(tt.cfg)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				j.Close()
+			}
+		})
+	}
+}
+
+func TestJournal_Query(t *testing.T) {
+	j, err := Open(config.JournalInfo{Path: filepath.Join(t.TempDir(), "journal.db")})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer j.Close()
+
+	start := time.Now()
+	annotate := func(key string, kind contracts.AnnotationType, offset time.Duration) contracts.Annotation {
+		a := contracts.NewAnnotation(key, contracts.SHA256Hash, "host", contracts.Host, kind, true)
+		a.Timestamp = start.Add(offset)
+		return a
+	}
+	items := []contracts.Annotation{
+		annotate("foo", contracts.AnnotationSource, 0),
+		annotate("foo", contracts.AnnotationTPM, time.Second),
+		annotate("bar", contracts.AnnotationSource, 2*time.Second),
+		annotate("foo", contracts.AnnotationSource, 3*time.Second),
+	}
+	// record out of order, results are sorted by time regardless
+	if err = j.Record(message.ActionCreate, items[2:]); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err = j.Record(message.ActionCreate, items[:2]); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name     string
+		query    Query
+		expected []contracts.Annotation
+	}{
+		{"everything", Query{}, items},
+		{"by key", Query{Key: "foo"}, []contracts.Annotation{items[0], items[1], items[3]}},
+		{"by kind", Query{Kind: contracts.AnnotationSource}, []contracts.Annotation{items[0], items[2], items[3]}},
+		{"by key and kind", Query{Key: "foo", Kind: contracts.AnnotationTPM}, items[1:2]},
+		{"by time", Query{From: start.Add(time.Second), To: start.Add(3 * time.Second)}, items[1:3]},
+		{"by key and time", Query{Key: "foo", From: start.Add(time.Second)}, []contracts.Annotation{items[1], items[3]}},
+		{"limited", Query{Limit: 2}, items[:2]},
+		{"unknown key", Query{Key: "baz"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := j.Query(tt.query)
+			test.CheckError(err, false, tt.name, t)
+			assert.Len(t, results, len(tt.expected))
+			for i := range results {
+				assert.Equal(t, tt.expected[i].Id, results[i].Id)
+			}
+		})
+	}
+}
+
+func TestJournal_Retention(t *testing.T) {
+	j, err := Open(config.JournalInfo{Path: filepath.Join(t.TempDir(), "journal.db"), Retention: 60000})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer j.Close()
+
+	expired := contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host,
+		contracts.AnnotationSource, true)
+	expired.Timestamp = time.Now().Add(-time.Hour)
+	current := contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host,
+		contracts.AnnotationSource, true)
+	if err = j.Record(message.ActionCreate, []contracts.Annotation{expired, current}); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	results, err := j.ByKey("foo")
+	test.CheckError(err, false, "by key", t)
+	assert.Len(t, results, 1)
+	assert.Equal(t, current.Id, results[0].Id)
+
+	results, err = j.Query(Query{Kind: contracts.AnnotationSource})
+	test.CheckError(err, false, "by kind", t)
+	assert.Len(t, results, 1)
+}
+
+func TestJournal_Replayable(t *testing.T) {
+	j, err := Open(config.JournalInfo{Path: filepath.Join(t.TempDir(), "journal.db")})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer j.Close()
+
+	start := time.Now()
+	annotate := func(key string, offset time.Duration) contracts.Annotation {
+		a := contracts.NewAnnotation(key, contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationSource, true)
+		a.Timestamp = start.Add(offset)
+		return a
+	}
+	created := []contracts.Annotation{annotate("foo", 0), annotate("foo", 0)}
+	mutated := []contracts.Annotation{annotate("bar", time.Second)}
+	published := []contracts.Annotation{annotate("baz", 2*time.Second)}
+	for _, p := range []struct {
+		action message.SdkAction
+		items  []contracts.Annotation
+	}{{message.ActionCreate, created}, {message.ActionMutate, mutated}, {message.ActionPublish, published}} {
+		if err = j.Record(p.action, p.items); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+	if err = j.Acknowledge(mutated); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name     string
+		query    interfaces.ReplayQuery
+		expected []message.SdkAction
+	}{
+		{"unacknowledged", interfaces.ReplayQuery{}, []message.SdkAction{message.ActionCreate, message.ActionPublish}},
+		{"all", interfaces.ReplayQuery{Acknowledged: true},
+			[]message.SdkAction{message.ActionCreate, message.ActionMutate, message.ActionPublish}},
+		{"in range", interfaces.ReplayQuery{From: start.Add(time.Second), To: start.Add(2 * time.Second),
+			Acknowledged: true}, []message.SdkAction{message.ActionMutate}},
+		{"unacknowledged in range", interfaces.ReplayQuery{From: start.Add(time.Second)},
+			[]message.SdkAction{message.ActionPublish}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := j.Replayable(tt.query)
+			test.CheckError(err, false, tt.name, t)
+			var actions []message.SdkAction
+			for _, e := range entries {
+				actions = append(actions, e.Action)
+			}
+			assert.Equal(t, tt.expected, actions)
+		})
+	}
+
+	// the annotations of a publish are replayed together
+	entries, _ := j.Replayable(interfaces.ReplayQuery{})
+	assert.Len(t, entries[0].Annotations, 2)
+	assert.Equal(t, created[1].Id, entries[0].Annotations[1].Id)
+}
@@ -22,10 +22,14 @@ import (
 	"time"
 
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
 )
 
 type ConsoleLogger struct {
 	slog.Logger
+	root      *slog.Logger
+	levels    levels
+	component string
 }
 
 func NewConsoleLogger(cfg config.LoggingInfo) ConsoleLogger {
@@ -40,18 +44,21 @@ func NewConsoleLogger(cfg config.LoggingInfo) ConsoleLogger {
 		level = slog.LevelInfo
 	}
 
-	return ConsoleLogger{
-		Logger: *slog.New(
-			newCustomJsonHandler(level).
-				WithAttrs([]slog.Attr{hostnameAtt}).(*slog.JSONHandler),
-		),
-	}
+	levels := newLevels(cfg, level)
+	root := slog.New(
+		newCustomJsonHandler(levels.lowest()).
+			WithAttrs([]slog.Attr{hostnameAtt}).(*slog.JSONHandler),
+	)
+	return ConsoleLogger{Logger: *root, root: root, levels: levels}
 }
 
 func (l ConsoleLogger) Write(level slog.Level, message string, args ...any) {
 	if !isValidLogLevel(level) {
 		level = slog.LevelInfo
 	}
+	if level < l.levels.of(l.component) {
+		return
+	}
 
 	lineAtt := getLineAtt()
 	applicationAtt := getApplicationAtt()
@@ -71,6 +78,9 @@ func (l ConsoleLogger) Write(level slog.Level, message string, args ...any) {
 }
 
 func (l ConsoleLogger) Error(message string, args ...any) {
+	if slog.LevelError < l.levels.of(l.component) {
+		return
+	}
 	lineAtt := getLineAtt()
 	applicationAtt := getApplicationAtt()
 
@@ -79,6 +89,13 @@ func (l ConsoleLogger) Error(message string, args ...any) {
 	l.Logger.Error(message, args...)
 }
 
+// Component returns a logger whose entries carry the name of the component in their attributes
+func (l ConsoleLogger) Component(name string) interfaces.Logger {
+	l.Logger = *l.root.With(componentAttrKey, name)
+	l.component = name
+	return l
+}
+
 func getLineAtt() slog.Attr {
 	_, file, line, ok := runtime.Caller(2)
 	if !ok {
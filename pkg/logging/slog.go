@@ -0,0 +1,117 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// SlogLogger writes log entries to a *slog.Logger supplied by the application, so that the SDK's output goes through
+// the same handler as the application's own, be it backed by slog, zap or zerolog. Levels, attributes and the source
+// of each entry are passed on to the handler as given.
+type SlogLogger struct {
+	root      *slog.Logger
+	logger    *slog.Logger
+	levels    levels
+	component string
+}
+
+// NewSlogLogger returns a SlogLogger writing to logger, or to slog.Default() if it is nil. Entries below the minimum
+// level in cfg, or below that of their component, are not written whatever the level of the handler.
+func NewSlogLogger(logger *slog.Logger, cfg config.LoggingInfo) SlogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return SlogLogger{root: logger, logger: logger, levels: newLevels(cfg, cfg.MinLogLevel)}
+}
+
+func (l SlogLogger) Write(level slog.Level, message string, args ...any) {
+	l.log(level, message, args...)
+}
+
+func (l SlogLogger) Error(message string, args ...any) {
+	l.log(slog.LevelError, message, args...)
+}
+
+// Component returns a logger whose entries carry the name of the component in their attributes
+func (l SlogLogger) Component(name string) interfaces.Logger {
+	l.logger = l.root.With(componentAttrKey, name)
+	l.component = name
+	return l
+}
+
+func (l SlogLogger) log(level slog.Level, message string, args ...any) {
+	ctx := context.Background()
+	if level < l.levels.of(l.component) || !l.logger.Enabled(ctx, level) {
+		return
+	}
+	// Skip runtime.Callers, log and Write or Error so that the source is the caller of the SDK logger
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(time.Now(), level, message, pcs[0])
+	r.Add(args...)
+	_ = l.logger.Handler().Handle(ctx, r)
+}
+
+// Component returns a logger attributing its entries to the named component of the SDK, if logger is an
+// interfaces.ComponentLogger. Other loggers are returned as is.
+func Component(logger interfaces.Logger, name string) interfaces.Logger {
+	if c, ok := logger.(interfaces.ComponentLogger); ok {
+		return c.Component(name)
+	}
+	return logger
+}
+
+// Discard is a logger that writes nothing, for applications that silence the SDK
+var Discard interfaces.Logger = discard{}
+
+type discard struct{}
+
+func (discard) Write(slog.Level, string, ...any) {}
+
+func (discard) Error(string, ...any) {}
+
+// levels holds the minimum level of entries written, overall and by component
+type levels struct {
+	min        slog.Level
+	components map[string]slog.Level
+}
+
+func newLevels(cfg config.LoggingInfo, minLevel slog.Level) levels {
+	return levels{min: minLevel, components: cfg.Components}
+}
+
+// of returns the minimum level of the entries written by component
+func (l levels) of(component string) slog.Level {
+	if level, ok := l.components[component]; ok {
+		return level
+	}
+	return l.min
+}
+
+// lowest returns the lowest of the minimum levels, below which no entry is written
+func (l levels) lowest() slog.Level {
+	lowest := l.min
+	for _, level := range l.components {
+		lowest = min(lowest, level)
+	}
+	return lowest
+}
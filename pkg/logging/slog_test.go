@@ -0,0 +1,130 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// entries returns the JSON entries written to buf
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var result []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf(err.Error())
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true, Level: slog.LevelDebug})
+	logger := NewSlogLogger(slog.New(handler), config.LoggingInfo{MinLogLevel: slog.LevelDebug})
+
+	logger.Write(slog.LevelDebug+2, "custom level", string(CorrelationKey), "abc")
+	logger.Error("failed", "attempt", 3)
+
+	written := entries(t, &buf)
+	assert.Len(t, written, 2)
+	assert.Equal(t, "custom level", written[0]["msg"])
+	assert.Equal(t, (slog.LevelDebug + 2).String(), written[0]["level"])
+	assert.Equal(t, "abc", written[0][string(CorrelationKey)])
+	assert.Equal(t, "slog_test.go", filepath.Base(written[0]["source"].(map[string]any)["file"].(string)),
+		"the source should be the caller of the logger")
+	assert.Equal(t, slog.LevelError.String(), written[1]["level"])
+	assert.Equal(t, float64(3), written[1]["attempt"])
+}
+
+func TestSlogLogger_Component(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := NewSlogLogger(slog.New(handler), config.LoggingInfo{
+		MinLogLevel: slog.LevelInfo,
+		Components: map[string]slog.Level{
+			ComponentStream: slog.LevelDebug,
+			ComponentRetry:  slog.LevelError + 4,
+		},
+	})
+
+	tests := []struct {
+		name      string
+		component string
+		level     slog.Level
+		written   bool
+	}{
+		{"below minimum", "", slog.LevelDebug, false},
+		{"at minimum", "", slog.LevelInfo, true},
+		{"component below minimum", ComponentSdk, slog.LevelDebug, false},
+		{"component level lowered", ComponentStream, slog.LevelDebug, true},
+		{"component silenced", ComponentRetry, slog.LevelError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			l := Component(logger, tt.component)
+			if tt.component == "" {
+				l = logger
+			}
+			l.Write(tt.level, tt.name)
+
+			written := entries(t, &buf)
+			if !tt.written {
+				assert.Empty(t, written)
+				return
+			}
+			assert.Len(t, written, 1)
+			if tt.component != "" {
+				assert.Equal(t, tt.component, written[0][componentAttrKey])
+			}
+		})
+	}
+
+	// Naming the component of a component logger replaces it
+	buf.Reset()
+	Component(Component(logger, ComponentSdk), ComponentStream).Write(slog.LevelInfo, "nested")
+	written := entries(t, &buf)
+	assert.Len(t, written, 1)
+	assert.Equal(t, ComponentStream, written[0][componentAttrKey])
+	assert.NotContains(t, buf.String(), ComponentSdk)
+}
+
+func TestComponent_Discard(t *testing.T) {
+	assert.Equal(t, Discard, Component(Discard, ComponentSdk))
+}
+
+func TestLoggingInfo_Components(t *testing.T) {
+	var cfg config.LoggingInfo
+	err := json.Unmarshal([]byte(`{"minLogLevel":"WARN","components":{"stream":"DEBUG","retry":"ERROR+4"}}`), &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Equal(t, slog.LevelWarn, cfg.MinLogLevel)
+	assert.Equal(t, map[string]slog.Level{ComponentStream: slog.LevelDebug, ComponentRetry: slog.LevelError + 4},
+		cfg.Components)
+}
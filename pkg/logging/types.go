@@ -21,4 +21,17 @@ const (
 	lineNumberAttrKey  = "line-number"
 	applicationAttrKey = "application"
 	hostnameAttrKey    = "hostname"
+	componentAttrKey   = "component"
+)
+
+// The components of the SDK that log, see Component and config.LoggingInfo
+const (
+	ComponentSdk         = "sdk"         // ComponentSdk is the SDK instance
+	ComponentStream      = "stream"      // ComponentStream is the stream provider publishing annotations
+	ComponentRetry       = "retry"       // ComponentRetry retries failed publishes
+	ComponentBuffer      = "buffer"      // ComponentBuffer buffers publishes while the stream provider is unreachable
+	ComponentRateLimit   = "ratelimit"   // ComponentRateLimit limits the rate of publishes
+	ComponentEncryption  = "encryption"  // ComponentEncryption encrypts published messages
+	ComponentCompression = "compression" // ComponentCompression compresses published messages
+	ComponentBatch       = "batch"       // ComponentBatch batches publishes
 )
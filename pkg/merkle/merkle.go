@@ -0,0 +1,161 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package merkle builds Merkle trees over batches of payloads so that a single annotation of the root can attest to
+// every payload in the batch. Inclusion proofs can be exported per payload and verified independently of the tree.
+//
+// Leaves and interior nodes are hashed with distinct prefixes as in RFC 6962 so that an interior node can never be
+// presented as a leaf. A node without a sibling is promoted to the next level unchanged rather than duplicated.
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+const (
+	leafPrefix byte = 0x00
+	nodePrefix byte = 0x01
+)
+
+// NewLeafHasher returns a hash that produces the leaf hash of the data written to it
+func NewLeafHasher() hash.Hash {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	return h
+}
+
+// LeafHash returns the hash of a single payload as a leaf of the tree
+func LeafHash(data []byte) []byte {
+	h := NewLeafHasher()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// ProofStep is a sibling hash on the path from a leaf to the root
+type ProofStep struct {
+	Hash string `json:"hash"`
+	Left bool   `json:"left"` // Left indicates that the sibling is hashed to the left of the running value
+}
+
+// Proof demonstrates that a payload is included in the tree with the given root
+type Proof struct {
+	Index int         `json:"index"`
+	Root  string      `json:"root"`
+	Path  []ProofStep `json:"path"`
+}
+
+// Tree is a Merkle tree over a batch of payloads
+type Tree struct {
+	levels [][][]byte // levels[0] holds the leaf hashes, the last level holds the root
+}
+
+// New builds a tree over the given payloads, preserving their order
+func New(items [][]byte) (*Tree, error) {
+	if len(items) == 0 {
+		return nil, errors.New("cannot build a merkle tree without items")
+	}
+
+	leaves := make([][]byte, len(items))
+	for i, item := range items {
+		leaves[i] = LeafHash(item)
+	}
+	return NewFromLeaves(leaves)
+}
+
+// NewFromLeaves builds a tree over leaf hashes that have already been derived with LeafHash or NewLeafHasher,
+// allowing callers to hash large payloads incrementally or in parallel.
+func NewFromLeaves(leaves [][]byte) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, errors.New("cannot build a merkle tree without items")
+	}
+
+	level := leaves
+	t := Tree{levels: [][][]byte{level}}
+	for len(level) > 1 {
+		var next [][]byte
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, nodeHash(level[i], level[i+1]))
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return &t, nil
+}
+
+// Root returns the hex encoded root of the tree
+func (t *Tree) Root() string {
+	return hex.EncodeToString(t.levels[len(t.levels)-1][0])
+}
+
+// Len returns the number of payloads in the tree
+func (t *Tree) Len() int {
+	return len(t.levels[0])
+}
+
+// Proof returns the inclusion proof for the payload at the given index
+func (t *Tree) Proof(index int) (Proof, error) {
+	if index < 0 || index >= t.Len() {
+		return Proof{}, fmt.Errorf("index %v out of range for tree of %v items", index, t.Len())
+	}
+
+	p := Proof{Index: index, Root: t.Root()}
+	i := index
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := i ^ 1
+		if sibling < len(level) {
+			p.Path = append(p.Path, ProofStep{Hash: hex.EncodeToString(level[sibling]), Left: i%2 == 1})
+		}
+		i /= 2
+	}
+	return p, nil
+}
+
+// Verify reports whether the proof demonstrates that data is included in the tree with the proof's root
+func Verify(data []byte, proof Proof) bool {
+	root, err := hex.DecodeString(proof.Root)
+	if err != nil {
+		return false
+	}
+
+	h := LeafHash(data)
+	for _, step := range proof.Path {
+		sibling, err := hex.DecodeString(step.Hash)
+		if err != nil {
+			return false
+		}
+		if step.Left {
+			h = nodeHash(sibling, h)
+		} else {
+			h = nodeHash(h, sibling)
+		}
+	}
+	return bytes.Equal(h, root)
+}
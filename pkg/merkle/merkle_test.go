@@ -0,0 +1,140 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package merkle
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		items       [][]byte
+		root        string
+		expectError bool
+	}{
+		{"single item", [][]byte{[]byte("foo")},
+			"1d2039fa7971f4bf01a1c20cb2a3fe7af46865ca9cd9b840c2063df8fec4ff75", false},
+		{"odd number of items", [][]byte{[]byte("foo"), []byte("bar"), []byte("baz")},
+			"5ac8333ed2f88046fe6397205b2bf26afabd252b62e910198e00c8399d10d8f5", false},
+		{"no items", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := New(tt.items)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.root, tree.Root())
+			assert.Equal(t, len(tt.items), tree.Len())
+		})
+	}
+}
+
+func TestTree_Proof(t *testing.T) {
+	for count := 1; count <= 17; count++ {
+		t.Run(fmt.Sprintf("%v items", count), func(t *testing.T) {
+			var items [][]byte
+			for i := 0; i < count; i++ {
+				items = append(items, []byte(fmt.Sprintf("reading-%v", i)))
+			}
+			tree, err := New(items)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			for i, item := range items {
+				proof, err := tree.Proof(i)
+				if err != nil {
+					t.Fatalf(err.Error())
+				}
+				// Proofs are exported for consumers so they must survive serialization
+				b, _ := json.Marshal(proof)
+				var exported Proof
+				if err = json.Unmarshal(b, &exported); err != nil {
+					t.Fatalf(err.Error())
+				}
+				assert.True(t, Verify(item, exported))
+				assert.False(t, Verify([]byte("tampered"), exported))
+			}
+		})
+	}
+}
+
+func TestTree_ProofOutOfRange(t *testing.T) {
+	tree, err := New([][]byte{[]byte("foo"), []byte("bar")})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	tests := []struct {
+		name  string
+		index int
+	}{
+		{"negative", -1},
+		{"past end", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tree.Proof(tt.index)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tree, err := New([][]byte{[]byte("foo"), []byte("bar"), []byte("baz")})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	proof, err := tree.Proof(1)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	wrongRoot := proof
+	wrongRoot.Root = "1d2039fa7971f4bf01a1c20cb2a3fe7af46865ca9cd9b840c2063df8fec4ff75"
+	invalidRoot := proof
+	invalidRoot.Root = "not hex"
+	invalidStep := proof
+	invalidStep.Path = []ProofStep{{Hash: "not hex"}}
+	swapped := proof
+	swapped.Path = append([]ProofStep{}, proof.Path...)
+	swapped.Path[0].Left = !swapped.Path[0].Left
+
+	tests := []struct {
+		name         string
+		data         []byte
+		proof        Proof
+		expectResult bool
+	}{
+		{"valid proof", []byte("bar"), proof, true},
+		{"wrong data", []byte("foo"), proof, false},
+		{"wrong root", []byte("bar"), wrongRoot, false},
+		{"invalid root", []byte("bar"), invalidRoot, false},
+		{"invalid step", []byte("bar"), invalidStep, false},
+		{"swapped sibling", []byte("bar"), swapped, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectResult, Verify(tt.data, tt.proof))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package message
+
+import (
+	"encoding/json"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// cborMode encodes deterministically, keeping timestamps as RFC 3339 strings so that annotations decoded from CBOR
+// marshal to the same JSON they were signed over
+var cborMode = func() cbor.EncMode {
+	opts := cbor.CoreDetEncOptions()
+	opts.Time = cbor.TimeRFC3339Nano
+	mode, err := opts.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}()
+
+// Marshal serializes the wrapper to be handed to a stream in its ContentType, JSON unless stated otherwise
+func Marshal(msg PublishWrapper) ([]byte, error) {
+	return MarshalContent(msg, msg.ContentType)
+}
+
+// Unmarshal parses a wrapper serialized by Marshal into v, either a PublishWrapper or a SubscribeWrapper, failing if
+// its schema version is newer than supported. The serialization is recognized from the leading byte: a JSON object
+// starts with '{', a CBOR map has major type 5 and a protobuf wrapper starts with the tag of one of its fields.
+func Unmarshal(data []byte, v any) error {
+	if err := unmarshal(data, v); err != nil {
+		return err
+	}
+	switch m := v.(type) {
+	case *PublishWrapper:
+		return checkVersion(m.Version)
+	case *SubscribeWrapper:
+		return checkVersion(m.Version)
+	}
+	return nil
+}
+
+func unmarshal(data []byte, v any) error {
+	if len(data) == 0 {
+		return json.Unmarshal(data, v)
+	}
+	switch {
+	case data[0]>>5 == 5:
+		return cbor.Unmarshal(data, v)
+	case isProtoTag(data[0]):
+		// a JSON document may also start with a newline, which shares the tag of the first field
+		if err := unmarshalProto(data, v); err == nil {
+			return nil
+		}
+	}
+	return json.Unmarshal(data, v)
+}
+
+// MarshalContent serializes v, typically an AnnotationList, as the Content of a wrapper of the given ContentType.
+// Protobuf supports wrappers, AnnotationLists and Annotations.
+func MarshalContent(v any, contentType string) ([]byte, error) {
+	switch contentType {
+	case string(contracts.ContentTypeCBOR):
+		return cborMode.Marshal(v)
+	case string(contracts.ContentTypeProtobuf):
+		return marshalProto(v)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalContent parses the Content of a wrapper of the given ContentType into v. Unlike with JSON and protobuf,
+// annotations decoded from CBOR are not validated.
+func UnmarshalContent(content []byte, contentType string, v any) error {
+	switch contentType {
+	case string(contracts.ContentTypeCBOR):
+		return cbor.Unmarshal(content, v)
+	case string(contracts.ContentTypeProtobuf):
+		return unmarshalProto(content, v)
+	}
+	return json.Unmarshal(content, v)
+}
+
+// isProtoTag reports whether b is the tag of a field of the protobuf wrapper, see alvarium.proto
+func isProtoTag(b byte) bool {
+	num, typ := protowire.DecodeTag(uint64(b))
+	return (typ == protowire.BytesType && num >= 1 && num <= 6) || (typ == protowire.VarintType && num == 7)
+}
@@ -0,0 +1,177 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package message
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal(t *testing.T) {
+	list := contracts.AnnotationList{Items: []contracts.Annotation{
+		contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationSource, true),
+		contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationTPM, false),
+		contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationPKI, true),
+	}}
+	list.Items[1].Tag = contracts.TagList{"4b825dc"}
+	list.Items[2].Tag = contracts.TagList{"4b825dc", "sha256:e3b0c442"}
+
+	tests := []struct {
+		name        string
+		contentType string
+	}{
+		{"default", ""},
+		{"json", string(contracts.ContentTypeJSON)},
+		{"cbor", string(contracts.ContentTypeCBOR)},
+		{"protobuf", string(contracts.ContentTypeProtobuf)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := MarshalContent(list, tt.contentType)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			msg := PublishWrapper{Action: ActionCreate, MessageType: "contracts.AnnotationList", Content: content,
+				ContentType: tt.contentType, TraceContext: map[string]string{"traceparent": "00-01", "tracestate": "a=b"}}
+			b, err := Marshal(msg)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			again, _ := Marshal(msg)
+			assert.Equal(t, b, again, "encoding is deterministic")
+
+			var received SubscribeWrapper
+			err = Unmarshal(b, &received)
+			test.CheckError(err, false, tt.name, t)
+			assert.Equal(t, msg.Action, received.Action)
+			assert.Equal(t, tt.contentType, received.ContentType)
+			assert.Equal(t, msg.TraceContext, received.TraceContext)
+
+			var decoded contracts.AnnotationList
+			err = UnmarshalContent(received.Content, received.ContentType, &decoded)
+			test.CheckError(err, false, tt.name, t)
+
+			// annotations must marshal to the same JSON they were signed over
+			expected, _ := json.Marshal(list)
+			actual, _ := json.Marshal(decoded)
+			assert.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestMarshal_Size(t *testing.T) {
+	list := contracts.AnnotationList{}
+	for i := 0; i < 10; i++ {
+		key := "fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"
+		list.Items = append(list.Items, contracts.NewAnnotation(key, contracts.SHA256Hash, "host", contracts.Host,
+			contracts.AnnotationSource, true))
+	}
+	marshal := func(contentType string) []byte {
+		content, _ := MarshalContent(list, contentType)
+		b, _ := Marshal(PublishWrapper{Action: ActionCreate, Content: content, ContentType: contentType})
+		return b
+	}
+	assert.Less(t, len(marshal(string(contracts.ContentTypeCBOR))), len(marshal("")))
+}
+
+func TestUnmarshal_Protobuf(t *testing.T) {
+	annotation := contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host,
+		contracts.AnnotationSource, true)
+	annotation.HostInfo = &contracts.HostInfo{Addresses: []string{"10.0.0.4", "fe80::1"}, MACs: []string{"02:42:ac:11:00:02"},
+		Region: "us-east-1", InstanceId: "i-0abc", OsRelease: "Alpine Linux v3.20"}
+	invalid := annotation
+	invalid.Kind = "gps"
+
+	valid, _ := MarshalContent(annotation, string(contracts.ContentTypeProtobuf))
+	rejected, _ := MarshalContent(invalid, string(contracts.ContentTypeProtobuf))
+
+	tests := []struct {
+		name        string
+		content     []byte
+		expectError bool
+	}{
+		{"valid annotation", valid, false},
+		{"invalid annotation type", rejected, true},
+		{"truncated annotation", valid[:len(valid)-1], true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a contracts.Annotation
+			err := UnmarshalContent(tt.content, string(contracts.ContentTypeProtobuf), &a)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err == nil {
+				assert.Equal(t, annotation.Id, a.Id)
+				assert.True(t, annotation.Timestamp.Equal(a.Timestamp))
+				assert.Equal(t, annotation.HostInfo, a.HostInfo)
+			}
+		})
+	}
+
+	// JSON may start with a newline, which is also the tag of the first protobuf field
+	var msg PublishWrapper
+	err := Unmarshal([]byte("\n{\"action\":\"create\"}"), &msg)
+	test.CheckError(err, false, "json starting with a newline", t)
+	assert.Equal(t, ActionCreate, msg.Action)
+
+	_, err = MarshalContent("foo", string(contracts.ContentTypeProtobuf))
+	test.CheckError(err, true, "unsupported protobuf type", t)
+}
+
+func TestPublishWrapper_ConvertTo(t *testing.T) {
+	current := PublishWrapper{Version: WrapperVersion, Action: ActionCreate, MessageType: "[]contracts.Annotation",
+		Content: []byte("[]"), ContentType: string(contracts.ContentTypeJSON)}
+	compressed := current
+	compressed.ContentEncoding = string(contracts.ZstdEncoding)
+	described := current
+	described.HostInfo = &contracts.HostInfo{Addresses: []string{"10.0.0.4"}, OsRelease: "Alpine Linux v3.20"}
+
+	tests := []struct {
+		name        string
+		wrapper     PublishWrapper
+		version     int
+		expectError bool
+	}{
+		{"current to current", current, WrapperVersion3, false},
+		{"current to traced", current, WrapperVersion2, false},
+		{"described to traced", described, WrapperVersion2, true},
+		{"current to original", current, WrapperVersion1, false},
+		{"compressed to original", compressed, WrapperVersion1, true},
+		{"newer than supported", current, WrapperVersion + 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := tt.wrapper.ConvertTo(tt.version)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				assert.True(t, errors.Is(err, contracts.ErrUnsupportedVersion))
+				return
+			}
+			b, _ := Marshal(converted)
+			var msg PublishWrapper
+			err = Unmarshal(b, &msg)
+			test.CheckError(err, false, tt.name, t)
+			assert.Equal(t, tt.wrapper.Content, msg.Content)
+		})
+	}
+
+	var msg PublishWrapper
+	err := Unmarshal([]byte(`{"version":4,"action":"create"}`), &msg)
+	assert.True(t, errors.Is(err, contracts.ErrUnsupportedVersion))
+}
@@ -0,0 +1,75 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package message
+
+import (
+	"context"
+	"sync"
+)
+
+// deliveryKey is the context key of the Delivery of a publish
+type deliveryKey struct{}
+
+// Delivery tracks the confirmation of a publish by the stream platform. A publish is confirmed when the stream
+// provider returns, unless the provider accepted it for later delivery, such as when buffering or batching, in which
+// case the provider takes over its confirmation with DeferDelivery.
+type Delivery struct {
+	once     sync.Once
+	mu       sync.Mutex
+	deferred bool
+	confirm  func(err error)
+}
+
+// WithDelivery returns a context tracking the delivery of the publish made with it. Confirm is called once, with nil
+// when the publish was delivered or with the error that lost it.
+func WithDelivery(ctx context.Context, confirm func(err error)) (context.Context, *Delivery) {
+	d := &Delivery{confirm: confirm}
+	return context.WithValue(ctx, deliveryKey{}, d), d
+}
+
+// WithoutDelivery returns a context whose publishes are not tracked, for publishes that do not deliver the message,
+// such as to a dead letter destination
+func WithoutDelivery(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deliveryKey{}, (*Delivery)(nil))
+}
+
+// Sent records the outcome of the call to the stream provider. The publish is confirmed unless the provider deferred
+// its confirmation and accepted it.
+func (d *Delivery) Sent(err error) {
+	d.mu.Lock()
+	deferred := d.deferred
+	d.mu.Unlock()
+	if err != nil || !deferred {
+		d.resolve(err)
+	}
+}
+
+func (d *Delivery) resolve(err error) {
+	d.once.Do(func() {
+		d.confirm(err)
+	})
+}
+
+// DeferDelivery takes over the confirmation of the publish made with ctx, returning the function to call once it has
+// been delivered or lost. The function does nothing if the publish is not tracked.
+func DeferDelivery(ctx context.Context) func(err error) {
+	d, _ := ctx.Value(deliveryKey{}).(*Delivery)
+	if d == nil {
+		return func(error) {}
+	}
+	d.mu.Lock()
+	d.deferred = true
+	d.mu.Unlock()
+	return d.resolve
+}
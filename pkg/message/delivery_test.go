@@ -0,0 +1,66 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package message
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelivery_Sent(t *testing.T) {
+	lost := errors.New("lost")
+	tests := []struct {
+		name      string
+		deferred  bool
+		sent      error
+		confirmed error // confirmed is the error of the deferred confirmation, if deferred
+		expected  []error
+	}{
+		{"delivered", false, nil, nil, []error{nil}},
+		{"failed", false, lost, nil, []error{lost}},
+		{"deferred delivered", true, nil, nil, []error{nil}},
+		{"deferred lost", true, nil, lost, []error{lost}},
+		{"deferred failed", true, lost, nil, []error{lost}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var confirmed []error
+			ctx, d := WithDelivery(context.Background(), func(err error) { confirmed = append(confirmed, err) })
+			var confirm func(err error)
+			if tt.deferred {
+				confirm = DeferDelivery(ctx)
+			}
+			d.Sent(tt.sent)
+			if tt.deferred {
+				// the confirmation has no effect once the publish failed
+				confirm(tt.confirmed)
+			}
+			assert.Equal(t, tt.expected, confirmed)
+		})
+	}
+}
+
+func TestDeferDelivery_Untracked(t *testing.T) {
+	// publishes that are not tracked can be confirmed all the same
+	DeferDelivery(context.Background())(nil)
+
+	confirmed := 0
+	ctx, d := WithDelivery(context.Background(), func(err error) { confirmed++ })
+	DeferDelivery(WithoutDelivery(ctx))(errors.New("dead letter"))
+	d.Sent(nil)
+	assert.Equal(t, 1, confirmed)
+}
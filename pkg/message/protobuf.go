@@ -0,0 +1,330 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package message
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The encoding below follows alvarium.proto, fields holding their zero value are omitted as in proto3
+
+// marshalProto serializes a wrapper, an AnnotationList or an Annotation
+func marshalProto(v any) ([]byte, error) {
+	switch m := v.(type) {
+	case PublishWrapper:
+		return appendWrapper(nil, m), nil
+	case SubscribeWrapper:
+		return appendWrapper(nil, PublishWrapper(m)), nil
+	case contracts.AnnotationList:
+		var b []byte
+		for _, a := range m.Items {
+			b = appendMessage(b, 1, appendAnnotation(nil, a))
+		}
+		return b, nil
+	case contracts.Annotation:
+		return appendAnnotation(nil, m), nil
+	}
+	return nil, fmt.Errorf("%T cannot be serialized as %s", v, contracts.ContentTypeProtobuf)
+}
+
+// unmarshalProto parses b into v, a pointer to a wrapper, an AnnotationList or an Annotation
+func unmarshalProto(b []byte, v any) error {
+	switch m := v.(type) {
+	case *PublishWrapper:
+		return consumeWrapper(b, m)
+	case *SubscribeWrapper:
+		var msg PublishWrapper
+		if err := consumeWrapper(b, &msg); err != nil {
+			return err
+		}
+		*m = SubscribeWrapper(msg)
+		return nil
+	case *contracts.AnnotationList:
+		var list contracts.AnnotationList
+		err := consumeFields(b, func(num protowire.Number, v []byte, _ uint64) error {
+			if num != 1 {
+				return nil
+			}
+			var a contracts.Annotation
+			if err := consumeAnnotation(v, &a); err != nil {
+				return err
+			}
+			list.Items = append(list.Items, a)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		*m = list
+		return nil
+	case *contracts.Annotation:
+		return consumeAnnotation(b, m)
+	}
+	return fmt.Errorf("%T cannot be parsed from %s", v, contracts.ContentTypeProtobuf)
+}
+
+func appendWrapper(b []byte, msg PublishWrapper) []byte {
+	b = appendString(b, 1, string(msg.Action))
+	b = appendString(b, 2, msg.MessageType)
+	if len(msg.Content) > 0 {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, msg.Content)
+	}
+	b = appendString(b, 4, msg.ContentEncoding)
+	b = appendString(b, 5, msg.ContentType)
+	b = appendVarint(b, 7, uint64(msg.Version))
+
+	// map entries are ordered by key so that the encoding is deterministic
+	keys := make([]string, 0, len(msg.TraceContext))
+	for k := range msg.TraceContext {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		entry := appendString(nil, 1, k)
+		entry = appendString(entry, 2, msg.TraceContext[k])
+		b = appendMessage(b, 6, entry)
+	}
+	if msg.HostInfo != nil {
+		b = appendMessage(b, 8, appendHostInfo(nil, *msg.HostInfo))
+	}
+	return b
+}
+
+func consumeWrapper(b []byte, msg *PublishWrapper) error {
+	var w PublishWrapper
+	err := consumeFields(b, func(num protowire.Number, v []byte, n uint64) error {
+		switch num {
+		case 1:
+			w.Action = SdkAction(v)
+		case 2:
+			w.MessageType = string(v)
+		case 3:
+			w.Content = append([]byte(nil), v...)
+		case 4:
+			w.ContentEncoding = string(v)
+		case 5:
+			w.ContentType = string(v)
+		case 6:
+			var key, value string
+			err := consumeFields(v, func(num protowire.Number, v []byte, _ uint64) error {
+				switch num {
+				case 1:
+					key = string(v)
+				case 2:
+					value = string(v)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if w.TraceContext == nil {
+				w.TraceContext = make(map[string]string)
+			}
+			w.TraceContext[key] = value
+		case 7:
+			w.Version = int(n)
+		case 8:
+			w.HostInfo = &contracts.HostInfo{}
+			return consumeHostInfo(v, w.HostInfo)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	*msg = w
+	return nil
+}
+
+func appendAnnotation(b []byte, a contracts.Annotation) []byte {
+	if a.Id != (ulid.ULID{}) {
+		b = appendString(b, 1, a.Id.String())
+	}
+	b = appendString(b, 2, a.Key)
+	b = appendString(b, 3, string(a.Hash))
+	b = appendString(b, 4, a.Host)
+	for _, tag := range a.Tag {
+		b = appendString(b, 5, tag)
+	}
+	b = appendString(b, 6, string(a.Layer))
+	b = appendString(b, 7, string(a.Kind))
+	b = appendString(b, 8, a.KeyId)
+	b = appendString(b, 9, a.Signature)
+	b = appendVarint(b, 10, protowire.EncodeBool(a.IsSatisfied))
+	if !a.Timestamp.IsZero() {
+		b = appendString(b, 11, a.Timestamp.Format(time.RFC3339Nano))
+	}
+	b = appendString(b, 12, a.ParentKey)
+	b = appendString(b, 13, a.PrevHash)
+	b = appendVarint(b, 14, uint64(a.Version))
+	b = appendString(b, 15, a.Error)
+	if a.HostInfo != nil {
+		b = appendMessage(b, 16, appendHostInfo(nil, *a.HostInfo))
+	}
+	return b
+}
+
+func appendHostInfo(b []byte, h contracts.HostInfo) []byte {
+	for _, address := range h.Addresses {
+		b = appendString(b, 1, address)
+	}
+	for _, mac := range h.MACs {
+		b = appendString(b, 2, mac)
+	}
+	b = appendString(b, 3, h.Region)
+	b = appendString(b, 4, h.InstanceId)
+	b = appendString(b, 5, h.OsRelease)
+	return b
+}
+
+func consumeHostInfo(b []byte, h *contracts.HostInfo) error {
+	return consumeFields(b, func(num protowire.Number, v []byte, _ uint64) error {
+		switch num {
+		case 1:
+			h.Addresses = append(h.Addresses, string(v))
+		case 2:
+			h.MACs = append(h.MACs, string(v))
+		case 3:
+			h.Region = string(v)
+		case 4:
+			h.InstanceId = string(v)
+		case 5:
+			h.OsRelease = string(v)
+		}
+		return nil
+	})
+}
+
+// consumeAnnotation parses an annotation, validating it as Annotation.UnmarshalJSON does
+func consumeAnnotation(b []byte, a *contracts.Annotation) error {
+	var x contracts.Annotation
+	err := consumeFields(b, func(num protowire.Number, v []byte, n uint64) error {
+		var err error
+		switch num {
+		case 1:
+			x.Id, err = ulid.ParseStrict(string(v))
+		case 2:
+			x.Key = string(v)
+		case 3:
+			x.Hash = contracts.HashType(v)
+		case 4:
+			x.Host = string(v)
+		case 5:
+			x.Tag = append(x.Tag, string(v))
+		case 6:
+			x.Layer = contracts.LayerType(v)
+		case 7:
+			x.Kind = contracts.AnnotationType(v)
+		case 8:
+			x.KeyId = string(v)
+		case 9:
+			x.Signature = string(v)
+		case 10:
+			x.IsSatisfied = protowire.DecodeBool(n)
+		case 11:
+			x.Timestamp, err = time.Parse(time.RFC3339Nano, string(v))
+		case 12:
+			x.ParentKey = string(v)
+		case 13:
+			x.PrevHash = string(v)
+		case 14:
+			x.Version = int(n)
+		case 15:
+			x.Error = string(v)
+		case 16:
+			x.HostInfo = &contracts.HostInfo{}
+			err = consumeHostInfo(v, x.HostInfo)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = x.CheckVersion(); err != nil {
+		return err
+	}
+	if !x.Hash.Validate() {
+		return fmt.Errorf("invalid HashType value provided %s", x.Hash)
+	}
+	if !x.Kind.Validate() {
+		return fmt.Errorf("invalid AnnotationType value provided %s", x.Kind)
+	}
+	*a = x
+	return nil
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendVarint(b []byte, num protowire.Number, n uint64) []byte {
+	if n == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, n)
+}
+
+func appendMessage(b []byte, num protowire.Number, m []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, m)
+}
+
+// consumeFields calls fn with each field of the message in b, passing the contents of length-delimited fields as v
+// and the value of varint fields as n. Fields of other wire types are skipped.
+func consumeFields(b []byte, fn func(num protowire.Number, v []byte, n uint64) error) error {
+	for len(b) > 0 {
+		num, typ, l := protowire.ConsumeTag(b)
+		if l < 0 {
+			return protowire.ParseError(l)
+		}
+		b = b[l:]
+
+		var v []byte
+		var n uint64
+		switch typ {
+		case protowire.BytesType:
+			v, l = protowire.ConsumeBytes(b)
+		case protowire.VarintType:
+			n, l = protowire.ConsumeVarint(b)
+		default:
+			l = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if l < 0 {
+			return protowire.ParseError(l)
+		}
+		b = b[l:]
+
+		if typ == protowire.BytesType || typ == protowire.VarintType {
+			if err := fn(num, v, n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
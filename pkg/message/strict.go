@@ -0,0 +1,216 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/strictjson"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The limits enforced by contracts.StrictDecoding, in addition to those on annotations
+const (
+	// MaxWrapperSize is the size of a serialized wrapper, in bytes
+	MaxWrapperSize = 8 << 20
+	// MaxContentSize is the size of the content of a wrapper once decompressed, in bytes
+	MaxContentSize = 64 << 20
+	// MaxTraceContext is the number of entries of the trace context of a wrapper
+	MaxTraceContext = 16
+)
+
+// strictCbor rejects duplicate map keys and the fields the destination does not define
+var strictCbor = func() cbor.DecMode {
+	mode, err := cbor.DecOptions{
+		DupMapKey:         cbor.DupMapKeyEnforcedAPF,
+		ExtraReturnErrors: cbor.ExtraDecErrorUnknownField,
+	}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}()
+
+// UnmarshalWith parses a wrapper serialized by Marshal into v, either a PublishWrapper or a SubscribeWrapper, in the
+// given mode. Lenient mode is that of Unmarshal. In strict mode, the wrapper must not exceed MaxWrapperSize nor have
+// fields the schema does not define, and must name a known action, message type, content type and content encoding.
+// Errors of strict decoding wrap contracts.ErrMalformed.
+func UnmarshalWith(data []byte, v any, mode contracts.DecodeMode) error {
+	if mode != contracts.StrictDecoding {
+		return Unmarshal(data, v)
+	}
+	if len(data) > MaxWrapperSize {
+		return fmt.Errorf("%w: wrapper of %d bytes exceeds %d", contracts.ErrMalformed, len(data), MaxWrapperSize)
+	}
+	if err := checkStrict(data, v); err != nil {
+		return fmt.Errorf("%w: %w", contracts.ErrMalformed, err)
+	}
+	if err := Unmarshal(data, v); err != nil {
+		return err
+	}
+	var msg PublishWrapper
+	switch m := v.(type) {
+	case *PublishWrapper:
+		msg = *m
+	case *SubscribeWrapper:
+		msg = PublishWrapper(*m)
+	default:
+		return nil
+	}
+	return msg.checkStrict()
+}
+
+// UnmarshalContentWith parses the Content of a wrapper of the given ContentType into v in the given mode. Lenient
+// mode is that of UnmarshalContent. In strict mode, fields the schema does not define are rejected, and
+// AnnotationLists and Annotations, whatever their serialization, are held to contracts.Annotation.CheckStrict.
+func UnmarshalContentWith(content []byte, contentType string, v any, mode contracts.DecodeMode) error {
+	if mode != contracts.StrictDecoding {
+		return UnmarshalContent(content, contentType, v)
+	}
+	if err := checkStrictContent(content, contentType, v); err != nil {
+		return fmt.Errorf("%w: %w", contracts.ErrMalformed, err)
+	}
+	if err := UnmarshalContent(content, contentType, v); err != nil {
+		return err
+	}
+	switch m := v.(type) {
+	case *contracts.AnnotationList:
+		return m.CheckStrict()
+	case *contracts.Annotation:
+		return m.CheckStrict()
+	}
+	return nil
+}
+
+// checkStrict checks that the wrapper only has the fields of the schema, recognizing its serialization as Unmarshal
+// does
+func checkStrict(data []byte, v any) error {
+	switch {
+	case len(data) == 0:
+		return nil
+	case data[0]>>5 == 5:
+		return checkStrictContent(data, string(contracts.ContentTypeCBOR), v)
+	case isProtoTag(data[0]):
+		// data parsed as protobuf by Unmarshal must be held to the protobuf schema
+		var msg PublishWrapper
+		if unmarshalProto(data, &msg) == nil {
+			return checkProto(data, wrapperFields)
+		}
+	}
+	return checkStrictContent(data, "", v)
+}
+
+// checkStrictContent checks that content of the given ContentType only has the fields defined by v
+func checkStrictContent(content []byte, contentType string, v any) error {
+	switch contentType {
+	case string(contracts.ContentTypeCBOR):
+		return strictCbor.Unmarshal(content, reflect.New(reflect.TypeOf(v).Elem()).Interface())
+	case string(contracts.ContentTypeProtobuf):
+		switch v.(type) {
+		case *PublishWrapper, *SubscribeWrapper:
+			return checkProto(content, wrapperFields)
+		case *contracts.AnnotationList:
+			return checkProto(content, listFields)
+		case *contracts.Annotation:
+			return checkProto(content, annotationFields)
+		}
+		return nil
+	}
+	if !json.Valid(content) {
+		return nil // left to decoding to report
+	}
+	return strictjson.Check(content, reflect.TypeOf(v))
+}
+
+// checkStrict returns an error wrapping contracts.ErrMalformed unless the wrapper names a known action, message type,
+// content type and content encoding, and its trace context fits MaxTraceContext
+func (p PublishWrapper) checkStrict() error {
+	if !p.Action.validate() {
+		return fmt.Errorf("%w: invalid action %q", contracts.ErrMalformed, p.Action)
+	}
+	if p.MessageType == "" {
+		return fmt.Errorf("%w: wrapper has no message type", contracts.ErrMalformed)
+	}
+	if p.ContentType != "" && !contracts.ContentType(p.ContentType).Validate() {
+		return fmt.Errorf("%w: invalid content type %q", contracts.ErrMalformed, p.ContentType)
+	}
+	if p.ContentEncoding != "" && !contracts.ContentEncoding(p.ContentEncoding).Validate() {
+		return fmt.Errorf("%w: invalid content encoding %q", contracts.ErrMalformed, p.ContentEncoding)
+	}
+	if len(p.TraceContext) > MaxTraceContext {
+		return fmt.Errorf("%w: trace context of %d entries exceeds %d", contracts.ErrMalformed, len(p.TraceContext),
+			MaxTraceContext)
+	}
+	return nil
+}
+
+// protoField describes a field of a protobuf message, see alvarium.proto
+type protoField struct {
+	typ    protowire.Type
+	fields map[protowire.Number]protoField // fields describes the fields of an embedded message
+}
+
+var (
+	bytesField  = protoField{typ: protowire.BytesType}
+	varintField = protoField{typ: protowire.VarintType}
+	hostFields  = map[protowire.Number]protoField{1: bytesField, 2: bytesField, 3: bytesField, 4: bytesField,
+		5: bytesField}
+	wrapperFields = map[protowire.Number]protoField{1: bytesField, 2: bytesField, 3: bytesField, 4: bytesField,
+		5: bytesField, 7: varintField,
+		6: {typ: protowire.BytesType, fields: map[protowire.Number]protoField{1: bytesField, 2: bytesField}},
+		8: {typ: protowire.BytesType, fields: hostFields}}
+	annotationFields = map[protowire.Number]protoField{1: bytesField, 2: bytesField, 3: bytesField, 4: bytesField,
+		5: bytesField, 6: bytesField, 7: bytesField, 8: bytesField, 9: bytesField, 10: varintField, 11: bytesField,
+		12: bytesField, 13: bytesField, 14: varintField, 15: bytesField,
+		16: {typ: protowire.BytesType, fields: hostFields}}
+	listFields = map[protowire.Number]protoField{1: {typ: protowire.BytesType, fields: annotationFields}}
+)
+
+// checkProto checks that the message in b only has the given fields, of their wire type
+func checkProto(b []byte, fields map[protowire.Number]protoField) error {
+	for len(b) > 0 {
+		num, typ, l := protowire.ConsumeTag(b)
+		if l < 0 {
+			return protowire.ParseError(l)
+		}
+		b = b[l:]
+		f, ok := fields[num]
+		if !ok {
+			return fmt.Errorf("unknown field %d", num)
+		}
+		if typ != f.typ {
+			return fmt.Errorf("field %d has wire type %d, expected %d", num, typ, f.typ)
+		}
+		if f.fields != nil {
+			v, l := protowire.ConsumeBytes(b)
+			if l < 0 {
+				return protowire.ParseError(l)
+			}
+			if err := checkProto(v, f.fields); err != nil {
+				return fmt.Errorf("field %d: %w", num, err)
+			}
+		}
+		l = protowire.ConsumeFieldValue(num, typ, b)
+		if l < 0 {
+			return protowire.ParseError(l)
+		}
+		b = b[l:]
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package message
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestUnmarshalWith(t *testing.T) {
+	list := contracts.AnnotationList{Items: []contracts.Annotation{
+		contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationSource, true),
+	}}
+	wrap := func(contentType string) []byte {
+		content, _ := MarshalContent(list, contentType)
+		b, _ := Marshal(PublishWrapper{Version: WrapperVersion, Action: ActionCreate, MessageType: "AnnotationList",
+			Content: content, ContentType: contentType})
+		return b
+	}
+	json, cborWrapper, proto := wrap(""), wrap(string(contracts.ContentTypeCBOR)),
+		wrap(string(contracts.ContentTypeProtobuf))
+	unknownCbor, _ := cbor.Marshal(map[string]any{"action": "create", "messageType": "AnnotationList", "extra": 1})
+	unknownProto := protowire.AppendVarint(protowire.AppendTag(bytes.Clone(proto), 20, protowire.VarintType), 1)
+	mistypedProto := protowire.AppendBytes(protowire.AppendTag(bytes.Clone(proto), 7, protowire.BytesType), nil)
+
+	tests := []struct {
+		name         string
+		data         []byte
+		expectStrict bool // expectStrict is set when strict decoding fails
+		expectError  bool // expectError is set when both modes fail
+	}{
+		{"json", json, false, false},
+		{"cbor", cborWrapper, false, false},
+		{"protobuf", proto, false, false},
+		{"json unknown field", []byte(`{"action":"create","messageType":"AnnotationList","extra":1}`), true, false},
+		{"json duplicate field", []byte(`{"action":"create","action":"mutate","messageType":"AnnotationList"}`),
+			true, false},
+		{"cbor unknown field", unknownCbor, true, false},
+		{"protobuf unknown field", unknownProto, true, false},
+		{"protobuf field of other wire type", mistypedProto, true, false},
+		{"invalid action", []byte(`{"action":"delete","messageType":"AnnotationList"}`), true, false},
+		{"no message type", []byte(`{"action":"create"}`), true, false},
+		{"invalid content type", []byte(`{"action":"create","messageType":"a","contentType":"application/xml"}`),
+			true, false},
+		{"invalid content encoding", []byte(`{"action":"create","messageType":"a","contentEncoding":"br"}`), true,
+			false},
+		{"oversized", append([]byte(`{"action":"create","messageType":"a","content":"`),
+			append(bytes.Repeat([]byte("A"), MaxWrapperSize), '"', '}')...), true, false},
+		{"newer schema", []byte(`{"version":99,"action":"create","messageType":"a"}`), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg PublishWrapper
+			err := UnmarshalWith(tt.data, &msg, contracts.LenientDecoding)
+			test.CheckError(err, tt.expectError, tt.name, t)
+
+			var received SubscribeWrapper
+			err = UnmarshalWith(tt.data, &received, contracts.StrictDecoding)
+			test.CheckError(err, tt.expectStrict, tt.name, t)
+			if err != nil && !tt.expectError {
+				assert.True(t, errors.Is(err, contracts.ErrMalformed), err.Error())
+			}
+			if err != nil {
+				return
+			}
+			var decoded contracts.AnnotationList
+			err = UnmarshalContentWith(received.Content, received.ContentType, &decoded, contracts.StrictDecoding)
+			test.CheckError(err, false, tt.name, t)
+			assert.Len(t, decoded.Items, 1)
+		})
+	}
+}
+
+func TestUnmarshalContentWith(t *testing.T) {
+	valid := contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationSource,
+		true)
+	noKey := valid
+	noKey.Key = ""
+
+	for _, contentType := range []string{"", string(contracts.ContentTypeCBOR), string(contracts.ContentTypeProtobuf)} {
+		t.Run("content type "+contentType, func(t *testing.T) {
+			content, _ := MarshalContent(contracts.AnnotationList{Items: []contracts.Annotation{valid}}, contentType)
+			var list contracts.AnnotationList
+			err := UnmarshalContentWith(content, contentType, &list, contracts.StrictDecoding)
+			test.CheckError(err, false, "valid", t)
+
+			content, _ = MarshalContent(contracts.AnnotationList{Items: []contracts.Annotation{noKey}}, contentType)
+			err = UnmarshalContentWith(content, contentType, &contracts.AnnotationList{}, contracts.LenientDecoding)
+			test.CheckError(err, false, "lenient without key", t)
+			err = UnmarshalContentWith(content, contentType, &contracts.AnnotationList{}, contracts.StrictDecoding)
+			test.CheckError(err, true, "strict without key", t)
+			assert.True(t, errors.Is(err, contracts.ErrMalformed))
+		})
+	}
+}
+
+// FuzzUnmarshalWith checks that decoding wrappers of any serialization never panics, and that strict decoding
+// accepts no more than lenient decoding
+func FuzzUnmarshalWith(f *testing.F) {
+	content, _ := MarshalContent(contracts.AnnotationList{Items: []contracts.Annotation{
+		contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationSource, true),
+	}}, "")
+	for _, contentType := range []string{"", string(contracts.ContentTypeCBOR), string(contracts.ContentTypeProtobuf)} {
+		b, _ := Marshal(PublishWrapper{Version: WrapperVersion, Action: ActionCreate, MessageType: "AnnotationList",
+			Content: content, ContentType: contentType, TraceContext: map[string]string{"traceparent": "00-01"},
+			HostInfo: &contracts.HostInfo{Region: "us-east-1"}})
+		f.Add(b)
+	}
+	f.Add([]byte("\n{\"action\":\"create\"}"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var lenient PublishWrapper
+		lenientErr := UnmarshalWith(data, &lenient, contracts.LenientDecoding)
+		var strict PublishWrapper
+		if err := UnmarshalWith(data, &strict, contracts.StrictDecoding); err != nil {
+			return
+		}
+		if lenientErr != nil {
+			t.Fatalf("strictly decoded wrapper rejected by lenient decoding: %v", lenientErr)
+		}
+		var list contracts.AnnotationList
+		if UnmarshalContentWith(strict.Content, strict.ContentType, &list, contracts.StrictDecoding) != nil {
+			return
+		}
+		if err := UnmarshalContentWith(strict.Content, strict.ContentType, &list, contracts.LenientDecoding); err != nil {
+			t.Fatalf("strictly decoded content rejected by lenient decoding: %v", err)
+		}
+	})
+}
@@ -14,6 +14,12 @@
 
 package message
 
+import (
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
 type SdkAction string
 
 const (
@@ -32,14 +38,80 @@ func (s SdkAction) validate() bool {
 	return false
 }
 
+// The versions of the wrapper schema. Consumers reject wrappers of versions newer than they support rather than
+// silently dropping the fields they do not know.
+const (
+	// WrapperVersion1 is the original schema of Action, MessageType and Content, wrappers without a version are of
+	// this schema
+	WrapperVersion1 = 1
+	// WrapperVersion2 adds Version, ContentEncoding, ContentType and TraceContext
+	WrapperVersion2 = 2
+	// WrapperVersion3 adds HostInfo
+	WrapperVersion3 = 3
+	// WrapperVersion is the schema of the wrappers published by this SDK, unless configured otherwise
+	WrapperVersion = WrapperVersion3
+)
+
 type PublishWrapper struct {
+	// Version identifies the schema of the wrapper, see WrapperVersion
+	Version     int       `json:"version,omitempty"`
 	Action      SdkAction `json:"action,omitempty"`
 	MessageType string    `json:"messageType,omitempty"`
 	Content     []byte    `json:"content,omitempty"`
+	// ContentEncoding names the compression applied to Content, if any
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+	// ContentType names the serialization of Content and of the wrapper itself, JSON unless stated otherwise
+	ContentType string `json:"contentType,omitempty"`
+	// TraceContext carries the W3C trace context (traceparent, tracestate) of the operation that produced the
+	// message, allowing consumers to continue the trace
+	TraceContext map[string]string `json:"traceContext,omitempty"`
+	// HostInfo describes the host that published the message, if configured
+	HostInfo *contracts.HostInfo `json:"hostInfo,omitempty"`
 }
 
 type SubscribeWrapper struct {
-	Action      SdkAction `json:"action,omitempty"`
-	MessageType string    `json:"messageType,omitempty"`
-	Content     []byte    `json:"content,omitempty"`
+	Version         int       `json:"version,omitempty"`
+	Action          SdkAction `json:"action,omitempty"`
+	MessageType     string    `json:"messageType,omitempty"`
+	Content         []byte    `json:"content,omitempty"`
+	ContentEncoding string    `json:"contentEncoding,omitempty"`
+	ContentType     string    `json:"contentType,omitempty"`
+	// TraceContext is the W3C trace context of the operation that produced the message, if any
+	TraceContext map[string]string `json:"traceContext,omitempty"`
+	// HostInfo describes the host that published the message, if any
+	HostInfo *contracts.HostInfo `json:"hostInfo,omitempty"`
+}
+
+// ConvertTo returns the wrapper in the given schema version. Converting down fails if the content could not be read
+// under the older schema, because it is compressed or not serialized as JSON, or if it describes the host. The trace
+// context, which consumers may ignore, is dropped.
+func (p PublishWrapper) ConvertTo(version int) (PublishWrapper, error) {
+	if err := checkVersion(version); err != nil {
+		return PublishWrapper{}, err
+	}
+	if version < WrapperVersion3 && p.HostInfo != nil {
+		return PublishWrapper{}, fmt.Errorf("%w: host info requires wrapper schema version %d",
+			contracts.ErrUnsupportedVersion, WrapperVersion3)
+	}
+	if version < WrapperVersion2 {
+		if p.ContentEncoding != "" || (p.ContentType != "" && p.ContentType != string(contracts.ContentTypeJSON)) {
+			return PublishWrapper{}, fmt.Errorf("%w: %s content with encoding %q requires wrapper schema version %d",
+				contracts.ErrUnsupportedVersion, p.ContentType, p.ContentEncoding, WrapperVersion2)
+		}
+		// the original schema has no version field
+		p.Version = 0
+		p.ContentType = ""
+		p.TraceContext = nil
+		return p, nil
+	}
+	p.Version = version
+	return p, nil
+}
+
+func checkVersion(version int) error {
+	if version < 0 || version > WrapperVersion {
+		return fmt.Errorf("%w: wrapper schema version %d, the newest supported is %d", contracts.ErrUnsupportedVersion,
+			version, WrapperVersion)
+	}
+	return nil
 }
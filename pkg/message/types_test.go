@@ -0,0 +1,63 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+// The wrapper is defined by the wire schema of pkg/contracts, which cannot refer to this package
+func TestPublishWrapper_Schema(t *testing.T) {
+	var root map[string]any
+	if err := json.Unmarshal(contracts.Schema(), &root); err != nil {
+		t.Fatalf(err.Error())
+	}
+	properties := root["$defs"].(map[string]any)["PublishWrapper"].(map[string]any)["properties"].(map[string]any)
+	property := func(name string) map[string]any {
+		return properties[name].(map[string]any)
+	}
+
+	var fields []string
+	typ := reflect.TypeOf(PublishWrapper{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		fields = append(fields, name)
+	}
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, fields, names)
+
+	for _, v := range property("action")["enum"].([]any) {
+		assert.True(t, SdkAction(v.(string)).validate(), "action %s", v)
+	}
+	for _, v := range property("contentEncoding")["enum"].([]any) {
+		assert.True(t, contracts.ContentEncoding(v.(string)).Validate(), "content encoding %s", v)
+	}
+	for _, v := range property("contentType")["enum"].([]any) {
+		assert.True(t, contracts.ContentType(v.(string)).Validate(), "content type %s", v)
+	}
+	assert.Equal(t, []any{fmt.Sprintf("%T", contracts.AnnotationList{})}, property("messageType")["examples"])
+	assert.Equal(t, float64(WrapperVersion), property("version")["maximum"],
+		"the newest wrapper version is not allowed")
+}
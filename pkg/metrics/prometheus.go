@@ -0,0 +1,121 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "alvarium"
+
+// PrometheusMetrics exports the activity of the SDK as Prometheus metrics. It implements
+// interfaces.AnnotationMetrics, interfaces.SignMetrics and interfaces.PublishMetrics so that a single instance can be
+// passed to pkg.WithAnnotationMetrics, factories.NewAnnotatorWithMetrics and pkg.WithPublishMetrics.
+type PrometheusMetrics struct {
+	annotations    *prometheus.CounterVec
+	signLatency    *prometheus.HistogramVec
+	signErrors     *prometheus.CounterVec
+	publishLatency *prometheus.HistogramVec
+	publishErrors  *prometheus.CounterVec
+	publishedBytes *prometheus.CounterVec
+	reconnects     *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics creates the SDK metrics and registers them on reg
+func NewPrometheusMetrics(reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	m := PrometheusMetrics{
+		annotations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "annotations_total",
+			Help:      "Number of annotations published, by kind, layer and whether they were satisfied.",
+		}, []string{"kind", "layer", "satisfied"}),
+		signLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "sign_duration_seconds",
+			Help:      "Time taken to sign annotations, by key algorithm.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+		}, []string{"algorithm"}),
+		signErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sign_errors_total",
+			Help:      "Number of failed signatures, by key algorithm.",
+		}, []string{"algorithm"}),
+		publishLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "publish_duration_seconds",
+			Help:      "Time taken by publish attempts to the stream provider, by stream type.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"stream"}),
+		publishErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "publish_errors_total",
+			Help:      "Number of failed publish attempts, by stream type.",
+		}, []string{"stream"}),
+		publishedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "published_bytes_total",
+			Help:      "Number of content bytes successfully published, by stream type.",
+		}, []string{"stream"}),
+		reconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reconnects_total",
+			Help:      "Number of times a dropped stream connection was re-established, by stream type.",
+		}, []string{"stream"}),
+	}
+
+	collectors := []prometheus.Collector{
+		m.annotations,
+		m.signLatency,
+		m.signErrors,
+		m.publishLatency,
+		m.publishErrors,
+		m.publishedBytes,
+		m.reconnects,
+	}
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return &m, nil
+}
+
+func (m *PrometheusMetrics) Annotated(kind contracts.AnnotationType, layer contracts.LayerType, satisfied bool) {
+	m.annotations.WithLabelValues(string(kind), string(layer), strconv.FormatBool(satisfied)).Inc()
+}
+
+func (m *PrometheusMetrics) Signed(algorithm contracts.KeyAlgorithm, latency time.Duration, err error) {
+	m.signLatency.WithLabelValues(string(algorithm)).Observe(latency.Seconds())
+	if err != nil {
+		m.signErrors.WithLabelValues(string(algorithm)).Inc()
+	}
+}
+
+func (m *PrometheusMetrics) Published(stream contracts.StreamType, bytes int, latency time.Duration, err error) {
+	m.publishLatency.WithLabelValues(string(stream)).Observe(latency.Seconds())
+	if err != nil {
+		m.publishErrors.WithLabelValues(string(stream)).Inc()
+		return
+	}
+	m.publishedBytes.WithLabelValues(string(stream)).Add(float64(bytes))
+}
+
+func (m *PrometheusMetrics) Reconnected(stream contracts.StreamType) {
+	m.reconnects.WithLabelValues(string(stream)).Inc()
+}
@@ -0,0 +1,66 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPrometheusMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := NewPrometheusMetrics(reg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	m.Annotated(contracts.AnnotationTPM, contracts.Host, true)
+	m.Annotated(contracts.AnnotationTPM, contracts.Host, false)
+	m.Annotated(contracts.AnnotationTPM, contracts.Host, false)
+	m.Signed(contracts.KeyEd25519, time.Millisecond, nil)
+	m.Signed(contracts.KeyEd25519, time.Millisecond, errors.New("failed"))
+	m.Published(contracts.MockStream, 10, time.Millisecond, nil)
+	m.Published(contracts.MockStream, 5, time.Millisecond, errors.New("failed"))
+	m.Reconnected(contracts.MockStream)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.annotations.WithLabelValues("tpm", "host", "true")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.annotations.WithLabelValues("tpm", "host", "false")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.signErrors.WithLabelValues(string(contracts.KeyEd25519))))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.publishErrors.WithLabelValues(string(contracts.MockStream))))
+	assert.Equal(t, float64(10), testutil.ToFloat64(m.publishedBytes.WithLabelValues(string(contracts.MockStream))))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.reconnects.WithLabelValues(string(contracts.MockStream))))
+
+	err = testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP alvarium_sign_errors_total Number of failed signatures, by key algorithm.
+# TYPE alvarium_sign_errors_total counter
+alvarium_sign_errors_total{algorithm="ed25519"} 1
+`), "alvarium_sign_errors_total")
+	assert.NoError(t, err)
+
+	count, err := testutil.GatherAndCount(reg, "alvarium_sign_duration_seconds", "alvarium_publish_duration_seconds")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// Registering twice on the same registry is refused
+	_, err = NewPrometheusMetrics(reg)
+	assert.Error(t, err)
+}
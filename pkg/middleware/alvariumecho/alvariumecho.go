@@ -0,0 +1,34 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package alvariumecho adapts the middleware of package middleware to Echo.
+package alvariumecho
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/middleware"
+)
+
+// Verify returns an Echo middleware answering 401 Unauthorized to the requests whose signature does not verify, see
+// middleware.Verify
+func Verify(keys config.SignatureInfo) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(middleware.Verify(keys))
+}
+
+// Annotate returns an Echo middleware annotating the body of each request, see middleware.Annotate
+func Annotate(sdk interfaces.Sdk, opts ...interfaces.CallOption) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(middleware.Annotate(sdk, opts...))
+}
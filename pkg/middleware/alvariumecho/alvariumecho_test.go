@@ -0,0 +1,98 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package alvariumecho
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+// transitSdk records the calls to Transit
+type transitSdk struct {
+	interfaces.Sdk
+	data     [][]byte
+	requests []*http.Request
+}
+
+func (s *transitSdk) Transit(ctx context.Context, data []byte, opts ...interfaces.CallOption) {
+	s.data = append(s.data, data)
+	r, _ := ctx.Value(contracts.HttpRequestKey).(*http.Request)
+	s.requests = append(s.requests, r)
+}
+
+func TestEcho(t *testing.T) {
+	keys := config.SignatureInfo{
+		PublicKey:  config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../../test/keys/ed25519/public.key"},
+		PrivateKey: config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../../test/keys/ed25519/private.key"},
+	}
+	signing, err := factories.NewSigningTransport(nil, keys)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name   string
+		client *http.Client
+		status int
+	}{
+		{"signed request", &http.Client{Transport: signing}, http.StatusOK},
+		{"unsigned request", http.DefaultClient, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := &transitSdk{}
+			served := false
+			r := echo.New()
+			r.Use(Verify(keys), Annotate(sdk))
+			r.POST("/foo", func(c echo.Context) error {
+				served = true
+				body, _ := io.ReadAll(c.Request().Body)
+				return c.Blob(http.StatusOK, "application/json", body)
+			})
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			body := []byte(`{"key":"keyA"}`)
+			resp, err := tt.client.Post(server.URL+"/foo", "application/json", bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			b, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status != http.StatusOK {
+				assert.False(t, served)
+				assert.Empty(t, sdk.data)
+				return
+			}
+			assert.Equal(t, body, b)
+			if assert.Len(t, sdk.data, 1) {
+				assert.Equal(t, body, sdk.data[0])
+				assert.NotNil(t, sdk.requests[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package alvariumgin adapts the middleware of package middleware to Gin.
+package alvariumgin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/middleware"
+)
+
+// Verify returns a Gin handler aborting the requests whose signature does not verify, see middleware.Verify
+func Verify(keys config.SignatureInfo) gin.HandlerFunc {
+	return Wrap(middleware.Verify(keys))
+}
+
+// Annotate returns a Gin handler annotating the body of each request, see middleware.Annotate
+func Annotate(sdk interfaces.Sdk, opts ...interfaces.CallOption) gin.HandlerFunc {
+	return Wrap(middleware.Annotate(sdk, opts...))
+}
+
+// Wrap adapts a net/http middleware to Gin. The request it passes on replaces that of the Gin context, and the chain
+// is aborted if it does not call the next handler.
+func Wrap(m middleware.Middleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		called := false
+		m(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			c.Request = r
+			c.Next()
+		})).ServeHTTP(c.Writer, c.Request)
+		if !called {
+			c.Abort()
+		}
+	}
+}
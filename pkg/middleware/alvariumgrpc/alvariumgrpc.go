@@ -0,0 +1,121 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package alvariumgrpc provides the gRPC counterpart of package middleware. Client interceptors sign the calls they
+// make, attaching the signature to their metadata, and server interceptors annotate the request messages received,
+// which the pki-grpc annotator then validates against that signature.
+package alvariumgrpc
+
+import (
+	"context"
+	"time"
+
+	grpcAnnotators "github.com/project-alvarium/alvarium-sdk-go/internal/annotators/grpc"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor returns an interceptor signing each call with the keys, over its method and the digest of
+// its request message. The calls fail if the keys are of an unsupported type.
+func UnaryClientInterceptor(keys config.SignatureInfo) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption) error {
+		data, err := grpcAnnotators.Marshal(req)
+		if err != nil {
+			return err
+		}
+		ctx, err = sign(ctx, method, data, keys)
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor returns an interceptor signing each stream with the keys. As the metadata of a stream is
+// sent before its messages, the signature only covers its method.
+func StreamClientInterceptor(keys config.SignatureInfo) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := sign(ctx, method, nil, keys)
+		if err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// UnaryServerInterceptor returns an interceptor annotating the request message of each call with sdk.Transit before
+// it is handled, the data having transited from the client. Calls whose message is not a protocol buffer are
+// rejected with codes.InvalidArgument.
+func UnaryServerInterceptor(sdk interfaces.Sdk, opts ...interfaces.CallOption) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		data, err := grpcAnnotators.Marshal(req)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		sdk.Transit(ctx, data, opts...)
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor annotating each message received on a stream with sdk.Transit, as
+// UnaryServerInterceptor does for calls
+func StreamServerInterceptor(sdk interfaces.Sdk, opts ...interfaces.CallOption) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &annotatedStream{ServerStream: ss, sdk: sdk, opts: opts})
+	}
+}
+
+// annotatedStream annotates the messages it receives
+type annotatedStream struct {
+	grpc.ServerStream
+	sdk  interfaces.Sdk
+	opts []interfaces.CallOption
+}
+
+func (s *annotatedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	data, err := grpcAnnotators.Marshal(m)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.sdk.Transit(s.Context(), data, s.opts...)
+	return nil
+}
+
+// sign returns the context of a call with its signature set in the outgoing metadata
+func sign(ctx context.Context, method string, data []byte, keys config.SignatureInfo) (context.Context, error) {
+	signer, err := factories.NewSignatureProvider(keys.PrivateKey.Type)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := grpcAnnotators.Sign(time.Now(), method, data, keys, signer)
+	if err != nil {
+		return nil, err
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	for k, v := range signed {
+		md[k] = v
+	}
+	return metadata.NewOutgoingContext(ctx, md), nil
+}
@@ -0,0 +1,180 @@
+//go:build !alvarium_core && !js && !wasip1 && !tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package alvariumgrpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// transitSdk records the calls to Transit, along with the result of the pki-grpc annotator
+type transitSdk struct {
+	interfaces.Sdk
+	annotator interfaces.Annotator
+	data      [][]byte
+	satisfied []bool
+}
+
+func (s *transitSdk) Transit(ctx context.Context, data []byte, opts ...interfaces.CallOption) {
+	s.data = append(s.data, data)
+	anno, err := s.annotator.Do(ctx, data)
+	s.satisfied = append(s.satisfied, err == nil && anno.IsSatisfied)
+}
+
+// echoService serves the Call method answering its request message, and the bidirectional Stream method answering
+// each message received
+var echoService = grpc.ServiceDesc{
+	ServiceName: "test.Echo",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Call",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			in := new(wrapperspb.StringValue)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req any) (any, error) {
+				return req, nil
+			}
+			if interceptor == nil {
+				return handler(ctx, in)
+			}
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Echo/Call"}, handler)
+		},
+	}},
+	Streams: []grpc.StreamDesc{{
+		StreamName: "Stream",
+		Handler: func(srv any, stream grpc.ServerStream) error {
+			for {
+				in := new(wrapperspb.StringValue)
+				if err := stream.RecvMsg(in); err == io.EOF {
+					return nil
+				} else if err != nil {
+					return err
+				}
+				if err := stream.SendMsg(in); err != nil {
+					return err
+				}
+			}
+		},
+		ServerStreams: true,
+		ClientStreams: true,
+	}},
+}
+
+// dial returns a connection to a server of echoService using the interceptors
+func dial(t *testing.T, sdk interfaces.Sdk, opts ...grpc.DialOption) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.UnaryInterceptor(UnaryServerInterceptor(sdk)),
+		grpc.StreamInterceptor(StreamServerInterceptor(sdk)))
+	server.RegisterService(&echoService, struct{}{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}))
+	conn, err := grpc.DialContext(context.Background(), "bufnet", opts...)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestInterceptors(t *testing.T) {
+	keys := config.SignatureInfo{
+		PublicKey:  config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../../test/keys/ed25519/public.key"},
+		PrivateKey: config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../../test/keys/ed25519/private.key"},
+	}
+	cfg := config.SdkInfo{Hash: config.HashInfo{Type: contracts.SHA256Hash}, Signature: keys}
+	annotator, err := factories.NewAnnotator(contracts.AnnotationPKIGrpc, cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name      string
+		opts      []grpc.DialOption
+		satisfied bool
+	}{
+		{"signed", []grpc.DialOption{grpc.WithUnaryInterceptor(UnaryClientInterceptor(keys)),
+			grpc.WithStreamInterceptor(StreamClientInterceptor(keys))}, true},
+		{"unsigned", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" call", func(t *testing.T) {
+			sdk := &transitSdk{annotator: annotator}
+			conn := dial(t, sdk, tt.opts...)
+
+			in, out := wrapperspb.String("hello"), new(wrapperspb.StringValue)
+			err := conn.Invoke(context.Background(), "/test.Echo/Call", in, out)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Equal(t, "hello", out.GetValue())
+			if assert.Len(t, sdk.data, 1) {
+				assert.Equal(t, tt.satisfied, sdk.satisfied[0])
+			}
+		})
+
+		t.Run(tt.name+" stream", func(t *testing.T) {
+			sdk := &transitSdk{annotator: annotator}
+			conn := dial(t, sdk, tt.opts...)
+
+			desc := &echoService.Streams[0]
+			stream, err := conn.NewStream(context.Background(), desc, "/test.Echo/Stream")
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			for _, v := range []string{"a", "b"} {
+				if err := stream.SendMsg(wrapperspb.String(v)); err != nil {
+					t.Fatalf(err.Error())
+				}
+				out := new(wrapperspb.StringValue)
+				if err := stream.RecvMsg(out); err != nil {
+					t.Fatalf(err.Error())
+				}
+				assert.Equal(t, v, out.GetValue())
+			}
+			stream.CloseSend()
+			assert.Equal(t, io.EOF, stream.RecvMsg(new(wrapperspb.StringValue)))
+			assert.Equal(t, []bool{tt.satisfied, tt.satisfied}, sdk.satisfied)
+		})
+	}
+
+	t.Run("unsupported key type", func(t *testing.T) {
+		invalid := keys
+		invalid.PrivateKey.Type = "invalid"
+		conn := dial(t, &transitSdk{annotator: annotator}, grpc.WithUnaryInterceptor(UnaryClientInterceptor(invalid)))
+		err := conn.Invoke(context.Background(), "/test.Echo/Call", wrapperspb.String("hello"), new(wrapperspb.StringValue))
+		assert.Error(t, err)
+	})
+}
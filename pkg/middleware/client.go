@@ -0,0 +1,71 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// AnnotateResponse annotates the body of a response fetched from an upstream API with sdk.Transit, the data having
+// transited from the upstream. The response is found in the annotation context under contracts.HttpResponseKey,
+// where the src-http annotator reads it, and its body is left for the caller to read.
+func AnnotateResponse(sdk interfaces.Sdk, resp *http.Response, opts ...interfaces.CallOption) error {
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	ctx := context.Background()
+	if resp.Request != nil {
+		ctx = resp.Request.Context()
+	}
+	sdk.Transit(context.WithValue(ctx, contracts.HttpResponseKey, resp), data, opts...)
+	return nil
+}
+
+// NewAnnotatingTransport returns an http.RoundTripper annotating each response received by base, or
+// http.DefaultTransport if base is nil, with AnnotateResponse. Clients ingesting data from upstream APIs use it to
+// annotate what they fetch.
+func NewAnnotatingTransport(base http.RoundTripper, sdk interfaces.Sdk, opts ...interfaces.CallOption) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &annotatingTransport{base: base, sdk: sdk, opts: opts}
+}
+
+type annotatingTransport struct {
+	base http.RoundTripper
+	sdk  interfaces.Sdk
+	opts []interfaces.CallOption
+}
+
+func (t *annotatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := AnnotateResponse(t.sdk, resp, t.opts...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,90 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	handler "github.com/project-alvarium/alvarium-sdk-go/internal/annotators/http/handler"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+	"github.com/stretchr/testify/assert"
+)
+
+// errorTransport fails each round trip
+type errorTransport struct{}
+
+func (errorTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("unreachable")
+}
+
+func TestAnnotatingTransport(t *testing.T) {
+	body := []byte(`{"key":"keyA"}`)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", string(contracts.ContentTypeJSON))
+		w.Header().Set(contracts.ContentDigest, handler.ContentDigest(body))
+		fields := []string{string(contracts.Status), contracts.HttpContentType, contracts.ContentDigest}
+		err := handler.NewEd25519ResponseHandler(w.Header(), http.StatusOK).AddSignatureHeaders(time.Now(), fields, keys)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(body)
+	}))
+	defer upstream.Close()
+
+	cfg := config.SdkInfo{Hash: config.HashInfo{Type: contracts.SHA256Hash}, Signature: keys}
+	src, err := factories.NewAnnotator(contracts.AnnotationSourceHttp, cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	sdk := &transitSdk{}
+	client := &http.Client{Transport: NewAnnotatingTransport(nil, sdk)}
+	resp, err := client.Get(upstream.URL)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// the body is left for the client to read
+	assert.Equal(t, body, b)
+	if assert.Len(t, sdk.data, 1) {
+		assert.Equal(t, body, sdk.data[0])
+		assert.Same(t, resp, sdk.responses[0])
+
+		// the upstream signature verifies against the data annotated
+		ctx := context.WithValue(context.Background(), contracts.HttpResponseKey, sdk.responses[0])
+		anno, err := src.Do(ctx, sdk.data[0])
+		assert.NoError(t, err)
+		assert.True(t, anno.IsSatisfied)
+	}
+
+	t.Run("round trip failing", func(t *testing.T) {
+		sdk := &transitSdk{}
+		client := &http.Client{Transport: NewAnnotatingTransport(errorTransport{}, sdk)}
+		_, err := client.Get(upstream.URL)
+		assert.Error(t, err)
+		assert.Empty(t, sdk.data)
+	})
+}
@@ -0,0 +1,83 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package middleware exposes the verification of HTTP request signatures and the annotation of request bodies as
+// net/http middleware, of the func(http.Handler) http.Handler form that routers such as Chi use directly. Adapters
+// for Gin and Echo are found in the alvariumgin and alvariumecho packages. Outgoing requests are signed with the
+// RoundTripper returned by factories.NewSigningTransport, whichever framework serves them, and the responses fetched
+// from upstream APIs are annotated with AnnotateResponse or NewAnnotatingTransport.
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	handler "github.com/project-alvarium/alvarium-sdk-go/internal/annotators/http/handler"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// Middleware wraps the handler serving a request
+type Middleware func(next http.Handler) http.Handler
+
+// Verify returns a middleware serving the requests whose signature verifies against the public keys found in the
+// directory of keys.PublicKey, and answering 401 Unauthorized to the others, see factories.NewVerifyingHandler.
+func Verify(keys config.SignatureInfo) Middleware {
+	return func(next http.Handler) http.Handler {
+		return factories.NewVerifyingHandler(next, keys)
+	}
+}
+
+// VerifyWithNonceStore returns a middleware verifying signatures as Verify does, recording their nonces in nonces,
+// see factories.NewVerifyingHandlerWithNonceStore
+func VerifyWithNonceStore(keys config.SignatureInfo, nonces interfaces.NonceStore) Middleware {
+	return func(next http.Handler) http.Handler {
+		return factories.NewVerifyingHandlerWithNonceStore(next, keys, nonces)
+	}
+}
+
+// Annotate returns a middleware annotating the body of each request with sdk.Transit before it is served, the data
+// having transited from the client. The request is found in the annotation context under contracts.HttpRequestKey,
+// where the pki-http annotator reads it, and its body is left for the next handler to read.
+func Annotate(sdk interfaces.Sdk, opts ...interfaces.CallOption) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(data))
+
+			r = WithRequest(r)
+			sdk.Transit(r.Context(), data, opts...)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithRequest returns the request with a context holding it under contracts.HttpRequestKey, as the pki-http
+// annotator expects, unless a verifying middleware has already put it there.
+func WithRequest(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(contracts.HttpRequestKey).(*http.Request); ok {
+		return r
+	}
+	req := handler.AbsoluteRequest(r)
+	return req.WithContext(context.WithValue(r.Context(), contracts.HttpRequestKey, req))
+}
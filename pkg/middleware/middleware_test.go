@@ -0,0 +1,107 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+// transitSdk records the calls to Transit
+type transitSdk struct {
+	interfaces.Sdk
+	data      [][]byte
+	requests  []*http.Request
+	responses []*http.Response
+}
+
+func (s *transitSdk) Transit(ctx context.Context, data []byte, opts ...interfaces.CallOption) {
+	s.data = append(s.data, data)
+	r, _ := ctx.Value(contracts.HttpRequestKey).(*http.Request)
+	s.requests = append(s.requests, r)
+	resp, _ := ctx.Value(contracts.HttpResponseKey).(*http.Response)
+	s.responses = append(s.responses, resp)
+}
+
+var keys = config.SignatureInfo{
+	PublicKey:  config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/public.key"},
+	PrivateKey: config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/private.key"},
+}
+
+func TestChi(t *testing.T) {
+	signing, err := factories.NewSigningTransport(nil, keys)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	tests := []struct {
+		name     string
+		verify   bool
+		client   *http.Client
+		status   int
+		annotate bool
+	}{
+		{"signed request", true, &http.Client{Transport: signing}, http.StatusOK, true},
+		{"unsigned request", true, http.DefaultClient, http.StatusUnauthorized, false},
+		{"annotate only", false, http.DefaultClient, http.StatusOK, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := &transitSdk{}
+			r := chi.NewRouter()
+			if tt.verify {
+				r.Use(Verify(keys))
+			}
+			r.Use(Annotate(sdk))
+			r.Post("/foo", func(w http.ResponseWriter, r *http.Request) {
+				// the body is left for the handler
+				io.Copy(w, r.Body)
+			})
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			body := []byte(`{"key":"keyA"}`)
+			resp, err := tt.client.Post(server.URL+"/foo", "application/json", bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			served, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if !tt.annotate {
+				assert.Empty(t, sdk.data)
+				return
+			}
+			assert.Equal(t, body, served)
+			if assert.Len(t, sdk.data, 1) {
+				assert.Equal(t, body, sdk.data[0])
+				if assert.NotNil(t, sdk.requests[0]) {
+					assert.Equal(t, server.URL+"/foo", sdk.requests[0].URL.String())
+				}
+			}
+		})
+	}
+}
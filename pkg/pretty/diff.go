@@ -0,0 +1,159 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package pretty
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// ChangeKind classifies the differences between two lists of annotations
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "new"     // the criterion is only evaluated by the second list
+	Changed ChangeKind = "changed" // the criterion is evaluated differently by the two lists
+	Removed ChangeKind = "missing" // the criterion is only evaluated by the first list
+)
+
+// Criterion identifies what an annotation evaluates: a kind of criterion, in a layer, for a piece of data.
+// Annotations of the same criterion made at different times or by different hosts are compared with each other.
+type Criterion struct {
+	Key   string
+	Kind  contracts.AnnotationType
+	Layer contracts.LayerType
+}
+
+func (c Criterion) String() string {
+	return fmt.Sprintf("%s %s %s", c.Kind, c.Layer, short(c.Key))
+}
+
+// Change is a difference between two lists of annotations
+type Change struct {
+	Kind      ChangeKind
+	Criterion Criterion
+	Before    *contracts.Annotation // Before is the annotation of the first list, nil if Added
+	After     *contracts.Annotation // After is the annotation of the second list, nil if Removed
+	Fields    []string              // Fields names the properties that differ, if Changed
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", c.Criterion, outcome(*c.After))
+	case Removed:
+		return fmt.Sprintf("- %s: %s", c.Criterion, outcome(*c.Before))
+	}
+	diffs := make([]string, len(c.Fields))
+	for i, f := range c.Fields {
+		diffs[i] = fmt.Sprintf("%s %q -> %q", f, field(*c.Before, f), field(*c.After, f))
+	}
+	return fmt.Sprintf("~ %s: %s", c.Criterion, strings.Join(diffs, ", "))
+}
+
+// compared lists the properties that tell whether a criterion is evaluated differently, leaving out those that
+// differ between any two annotations, such as the identifier, timestamp and signature
+var compared = []string{"isSatisfied", "error", "tag", "parentKey", "host", "hash"}
+
+// field returns the value of the compared property of the annotation, as shown in a diff
+func field(a contracts.Annotation, name string) string {
+	switch name {
+	case "isSatisfied":
+		return fmt.Sprint(a.IsSatisfied)
+	case "error":
+		return a.Error
+	case "tag":
+		return a.Tag.String()
+	case "parentKey":
+		return a.ParentKey
+	case "host":
+		return a.Host
+	case "hash":
+		return string(a.Hash)
+	}
+	return ""
+}
+
+// outcome describes how an annotation evaluated its criterion
+func outcome(a contracts.Annotation) string {
+	switch {
+	case a.Error != "":
+		return "error " + a.Error
+	case a.IsSatisfied:
+		return "satisfied"
+	default:
+		return "unsatisfied"
+	}
+}
+
+// Diff compares the annotations of two lists by criterion, returning what is missing from or changed in the second
+// list in the order of the first, then what is new in the order of the second. Criteria evaluated alike are left out.
+// Should a list evaluate a criterion more than once, its annotations are paired with those of the other list in
+// order.
+func Diff(before, after []contracts.Annotation) []Change {
+	pending := make(map[Criterion][]int)
+	for i, a := range after {
+		c := criterion(a)
+		pending[c] = append(pending[c], i)
+	}
+
+	var changes []Change
+	matched := make([]bool, len(after))
+	for i := range before {
+		c := criterion(before[i])
+		indexes := pending[c]
+		if len(indexes) == 0 {
+			changes = append(changes, Change{Kind: Removed, Criterion: c, Before: &before[i]})
+			continue
+		}
+		j := indexes[0]
+		pending[c] = indexes[1:]
+		matched[j] = true
+
+		var fields []string
+		for _, f := range compared {
+			if field(before[i], f) != field(after[j], f) {
+				fields = append(fields, f)
+			}
+		}
+		if len(fields) > 0 {
+			changes = append(changes, Change{Kind: Changed, Criterion: c, Before: &before[i], After: &after[j],
+				Fields: fields})
+		}
+	}
+	for j := range after {
+		if !matched[j] {
+			changes = append(changes, Change{Kind: Added, Criterion: criterion(after[j]), After: &after[j]})
+		}
+	}
+	return changes
+}
+
+// WriteDiff writes the changes one per line, marked + if new, - if missing and ~ if changed
+func WriteDiff(w io.Writer, changes []Change) error {
+	for _, c := range changes {
+		if _, err := fmt.Fprintln(w, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func criterion(a contracts.Annotation) Criterion {
+	return Criterion{Key: a.Key, Kind: a.Kind, Layer: a.Layer}
+}
@@ -0,0 +1,83 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package pretty
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	src := annotation(contracts.AnnotationSource, true)
+	tpm := annotation(contracts.AnnotationTPM, true)
+	pki := annotation(contracts.AnnotationPKI, true)
+
+	// annotations of another run differ in their identifier, timestamp and signature
+	rerun := func(a contracts.Annotation) contracts.Annotation {
+		a.Id = contracts.NewULID()
+		a.Timestamp = a.Timestamp.Add(time.Hour)
+		a.Signature = "other"
+		return a
+	}
+	failed := rerun(tpm)
+	failed.IsSatisfied = false
+	failed.Error = "no tpm"
+	otherLayer := rerun(src)
+	otherLayer.Layer = contracts.Application
+
+	tests := []struct {
+		name     string
+		before   []contracts.Annotation
+		after    []contracts.Annotation
+		expected []string
+	}{
+		{"same criteria", []contracts.Annotation{src, tpm}, []contracts.Annotation{rerun(tpm), rerun(src)}, nil},
+		{"changed", []contracts.Annotation{src, tpm}, []contracts.Annotation{rerun(src), failed},
+			[]string{`~ tpm host 2c26b46b68ff: isSatisfied "true" -> "false", error "" -> "no tpm"`}},
+		{"missing and new", []contracts.Annotation{src, tpm}, []contracts.Annotation{rerun(src), pki},
+			[]string{"- tpm host 2c26b46b68ff: satisfied", "+ pki host 2c26b46b68ff: satisfied"}},
+		{"other layer", []contracts.Annotation{src}, []contracts.Annotation{otherLayer},
+			[]string{"- src host 2c26b46b68ff: satisfied", "+ src app 2c26b46b68ff: satisfied"}},
+		{"repeated criterion", []contracts.Annotation{src, src}, []contracts.Annotation{rerun(src)},
+			[]string{"- src host 2c26b46b68ff: satisfied"}},
+		{"empty", nil, []contracts.Annotation{failed}, []string{"+ tpm host 2c26b46b68ff: error no tpm"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Diff(tt.before, tt.after)
+			var lines []string
+			for _, c := range changes {
+				lines = append(lines, c.String())
+			}
+			assert.Equal(t, tt.expected, lines)
+		})
+	}
+
+	changes := Diff([]contracts.Annotation{src, tpm}, []contracts.Annotation{failed})
+	assert.Equal(t, Changed, changes[1].Kind)
+	assert.Equal(t, []string{"isSatisfied", "error"}, changes[1].Fields)
+	assert.Equal(t, Criterion{Key: testKey, Kind: contracts.AnnotationTPM, Layer: contracts.Host}, changes[1].Criterion)
+
+	var b bytes.Buffer
+	if err := WriteDiff(&b, changes); err != nil {
+		t.Fatalf(err.Error())
+	}
+	assert.Equal(t, "- src host 2c26b46b68ff: satisfied\n"+
+		`~ tpm host 2c26b46b68ff: isSatisfied "true" -> "false", error "" -> "no tpm"`+"\n", b.String())
+}
@@ -0,0 +1,50 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package pretty renders annotations for people, as tables, and compares lists of annotations by the criteria they
+// evaluate, for example to show what changed about the same data between two runs of a pipeline.
+package pretty
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// keyLength is the number of characters of a hash value shown, enough to tell the data of a table apart
+const keyLength = 12
+
+// WriteTable writes the annotations as a table, one row per annotation in order. Hash values are shortened and
+// timestamps given in UTC to the second.
+func WriteTable(w io.Writer, items []contracts.Annotation) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tLAYER\tSATISFIED\tKEY\tHOST\tTAG\tTIMESTAMP\tERROR")
+	for _, a := range items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", a.Kind, a.Layer, strconv.FormatBool(a.IsSatisfied),
+			short(a.Key), a.Host, a.Tag, a.Timestamp.UTC().Format(time.DateTime), a.Error)
+	}
+	return tw.Flush()
+}
+
+// short shortens a hash value to keyLength characters
+func short(key string) string {
+	if len(key) <= keyLength {
+		return key
+	}
+	return key[:keyLength]
+}
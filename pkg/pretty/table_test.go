@@ -0,0 +1,49 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package pretty
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+const testKey = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+func annotation(kind contracts.AnnotationType, satisfied bool) contracts.Annotation {
+	a := contracts.NewAnnotation(testKey, contracts.SHA256Hash, "edge-01", contracts.Host, kind, satisfied)
+	a.Timestamp = time.Date(2024, 6, 11, 9, 14, 52, 391862000, time.FixedZone("CEST", 2*60*60))
+	return a
+}
+
+func TestWriteTable(t *testing.T) {
+	tpm := annotation(contracts.AnnotationTPM, false)
+	tpm.Error = "no tpm"
+	src := annotation(contracts.AnnotationSource, true)
+	src.Tag = contracts.TagList{"4b825dc", "e3b0c442"}
+
+	var b bytes.Buffer
+	if err := WriteTable(&b, []contracts.Annotation{tpm, src}); err != nil {
+		t.Fatalf(err.Error())
+	}
+	expected := "" +
+		"KIND  LAYER  SATISFIED  KEY           HOST     TAG               TIMESTAMP            ERROR\n" +
+		"tpm   host   false      2c26b46b68ff  edge-01                    2024-06-11 07:14:52  no tpm\n" +
+		"src   host   true       2c26b46b68ff  edge-01  4b825dc,e3b0c442  2024-06-11 07:14:52  \n"
+	assert.Equal(t, expected, b.String())
+}
@@ -0,0 +1,153 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package pkg
+
+import (
+	"context"
+	"sync"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+)
+
+// Receipt resolves once the annotations published by a call to Create, Mutate, Transit or Publish have been
+// delivered, see WithReceipt. Delivery is confirmed by the stream platform, such as by an MQTT PUBACK or a Hedera
+// transaction receipt, and is awaited past the stream buffer and batching, so that callers needing at-least-once
+// semantics can gate on it. A Receipt tracks a single call.
+type Receipt struct {
+	once sync.Once
+	done chan struct{}
+	err  error
+}
+
+// NewReceipt returns a Receipt to hand to a call with WithReceipt
+func NewReceipt() *Receipt {
+	return &Receipt{done: make(chan struct{})}
+}
+
+// Done is closed once the call has completed and its annotations have been delivered or lost
+func (r *Receipt) Done() <-chan struct{} {
+	return r.done
+}
+
+// Err returns the first failure of the call once Done is closed: the error of an operation that could not be
+// completed or of a publish that was not delivered. It is nil when the annotations were delivered, as well as when
+// the call had nothing to publish, such as when its data was skipped by the sampling policy.
+func (r *Receipt) Err() error {
+	select {
+	case <-r.done:
+		return r.err
+	default:
+		return nil
+	}
+}
+
+// Wait blocks until the receipt resolves, returning Err, or until ctx is done
+func (r *Receipt) Wait(ctx context.Context) error {
+	select {
+	case <-r.done:
+		return r.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Receipt) resolve(err error) {
+	r.once.Do(func() {
+		r.err = err
+		close(r.done)
+	})
+}
+
+// WithReceipt resolves r once the annotations published by the call have been delivered
+func WithReceipt(r *Receipt) interfaces.CallOption {
+	return OnDelivered(r.resolve)
+}
+
+// OnDelivered calls fn once the annotations published by the call have been delivered, or with the error that
+// prevented it. Fn is called on the goroutine that confirmed the delivery and should return quickly.
+func OnDelivered(fn func(err error)) interfaces.CallOption {
+	return func(o *interfaces.CallOptions) {
+		o.Delivered = fn
+	}
+}
+
+// trackerKey is the context key of the tracker of a call
+type trackerKey struct{}
+
+// tracker settles a call once it has completed and each of its publishes has been delivered or lost
+type tracker struct {
+	mu       sync.Mutex
+	pending  int   // pending counts the call itself and its publishes that are not settled
+	err      error // err is the first failure of the call
+	notify   func(err error)
+	notified bool
+}
+
+// track returns a context tracking the call, if the caller asked to be notified of its delivery. The call is held
+// until it is settled with settle.
+func track(ctx context.Context, call interfaces.CallOptions) context.Context {
+	if call.Delivered == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, trackerKey{}, &tracker{pending: 1, notify: call.Delivered})
+}
+
+// trackerFrom returns the tracker of the call in ctx, nil if it is not tracked
+func trackerFrom(ctx context.Context) *tracker {
+	t, _ := ctx.Value(trackerKey{}).(*tracker)
+	return t
+}
+
+// hold adds a publish to the call
+func (t *tracker) hold() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending++
+}
+
+// fail records a failure of the call without settling anything
+func (t *tracker) fail(err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+// settle settles the call or one of its publishes with err, notifying the caller once everything is settled
+func (t *tracker) settle(err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	if err != nil && t.err == nil {
+		t.err = err
+	}
+	t.pending--
+	notify := t.pending == 0 && !t.notified
+	if notify {
+		t.notified = true
+	}
+	err = t.err
+	t.mu.Unlock()
+	if notify {
+		t.notify(err)
+	}
+}
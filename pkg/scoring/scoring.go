@@ -0,0 +1,135 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package scoring computes a provisional confidence score from annotations at the edge, so that applications can act
+// on the trustworthiness of data without waiting for the scores computed by the backend.
+//
+// The confidence in a piece of data is the weighted fraction of its annotations that are satisfied. The weight of an
+// annotation is the product of the weights of its kind and of its layer, each of which defaults to 1. Annotations that
+// do not name their layer are weighed in the default layer of their kind, see contracts.AnnotationTypeInfo. A weight
+// of 0 excludes annotations from the score.
+package scoring
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// Weights sets the importance of annotations by kind and by layer
+type Weights struct {
+	Kinds  map[contracts.AnnotationType]float64 `json:"kinds,omitempty" yaml:"kinds"`   // Kinds weighs annotations by kind
+	Layers map[contracts.LayerType]float64      `json:"layers,omitempty" yaml:"layers"` // Layers weighs annotations by layer
+}
+
+// Score is the provisional confidence in a piece of data
+type Score struct {
+	Key        string  `json:"key,omitempty"` // Key is the hash of the data scored
+	Confidence float64 `json:"confidence"`    // Confidence is between 0 and 1, it is 0 when nothing was weighed
+	Satisfied  int     `json:"satisfied"`     // Satisfied is the number of weighed annotations that were satisfied
+	Total      int     `json:"total"`         // Total is the number of annotations weighed
+}
+
+// Scorer computes scores with a set of weights
+type Scorer struct {
+	weights Weights
+}
+
+// NewScorer returns a Scorer applying the supplied weights, which must be finite and not negative. Kinds must be valid, either
+// built-in or registered with contracts.RegisterAnnotationType.
+func NewScorer(weights Weights) (*Scorer, error) {
+	for kind, w := range weights.Kinds {
+		if !kind.Validate() {
+			return nil, fmt.Errorf("invalid AnnotationType %s", kind)
+		}
+		if !validWeight(w) {
+			return nil, fmt.Errorf("invalid weight %v for AnnotationType %s", w, kind)
+		}
+	}
+	for layer, w := range weights.Layers {
+		if !validWeight(w) {
+			return nil, fmt.Errorf("invalid weight %v for LayerType %s", w, layer)
+		}
+	}
+	return &Scorer{weights: weights}, nil
+}
+
+// validWeight reports whether w can weigh annotations, NaN and infinite weights would make the confidence NaN
+func validWeight(w float64) bool {
+	return w >= 0 && !math.IsNaN(w) && !math.IsInf(w, 0)
+}
+
+// Score scores all of the annotations in list together. Key is only set when every annotation shares it.
+func (s *Scorer) Score(list contracts.AnnotationList) Score {
+	var score Score
+	if len(list.Items) > 0 {
+		score.Key = list.Items[0].Key
+	}
+	var satisfied, total float64
+	for _, a := range list.Items {
+		if a.Key != score.Key {
+			score.Key = ""
+		}
+		w := s.weight(a)
+		if w == 0 {
+			continue
+		}
+		score.Total++
+		total += w
+		if a.IsSatisfied {
+			score.Satisfied++
+			satisfied += w
+		}
+	}
+	if total > 0 {
+		score.Confidence = satisfied / total
+	}
+	return score
+}
+
+// ScoreByKey scores the annotations in list separately for each piece of data they annotate
+func (s *Scorer) ScoreByKey(list contracts.AnnotationList) map[string]Score {
+	grouped := make(map[string]*contracts.AnnotationList)
+	for _, a := range list.Items {
+		g, ok := grouped[a.Key]
+		if !ok {
+			g = &contracts.AnnotationList{}
+			grouped[a.Key] = g
+		}
+		g.Items = append(g.Items, a)
+	}
+
+	scores := make(map[string]Score, len(grouped))
+	for key, g := range grouped {
+		scores[key] = s.Score(*g)
+	}
+	return scores
+}
+
+func (s *Scorer) weight(a contracts.Annotation) float64 {
+	w := 1.0
+	if k, ok := s.weights.Kinds[a.Kind]; ok {
+		w *= k
+	}
+	layer := a.Layer
+	if layer == "" {
+		info, _ := a.Kind.Info()
+		layer = info.Layer
+	}
+	if l, ok := s.weights.Layers[layer]; ok {
+		w *= l
+	}
+	return w
+}
@@ -0,0 +1,114 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package scoring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScorer(t *testing.T) {
+	tests := []struct {
+		name        string
+		weights     Weights
+		expectError bool
+	}{
+		{"no weights", Weights{}, false},
+		{"valid weights", Weights{Kinds: map[contracts.AnnotationType]float64{contracts.AnnotationTPM: 2},
+			Layers: map[contracts.LayerType]float64{contracts.Host: 0}}, false},
+		{"negative kind", Weights{Kinds: map[contracts.AnnotationType]float64{contracts.AnnotationTPM: -1}}, true},
+		{"negative layer", Weights{Layers: map[contracts.LayerType]float64{contracts.Host: -1}}, true},
+		{"NaN kind", Weights{Kinds: map[contracts.AnnotationType]float64{contracts.AnnotationTPM: math.NaN()}}, true},
+		{"infinite kind", Weights{Kinds: map[contracts.AnnotationType]float64{contracts.AnnotationTPM: math.Inf(1)}}, true},
+		{"NaN layer", Weights{Layers: map[contracts.LayerType]float64{contracts.Host: math.NaN()}}, true},
+		{"infinite layer", Weights{Layers: map[contracts.LayerType]float64{contracts.Host: math.Inf(-1)}}, true},
+		{"unknown kind", Weights{Kinds: map[contracts.AnnotationType]float64{"unknown": 1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewScorer(tt.weights)
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
+
+func TestScorer_Score(t *testing.T) {
+	annotation := func(key string, kind contracts.AnnotationType, layer contracts.LayerType,
+		satisfied bool) contracts.Annotation {
+		return contracts.Annotation{Key: key, Kind: kind, Layer: layer, IsSatisfied: satisfied}
+	}
+	list := contracts.AnnotationList{Items: []contracts.Annotation{
+		annotation("a", contracts.AnnotationTPM, contracts.Host, true),
+		annotation("a", contracts.AnnotationPKI, contracts.Application, false),
+		annotation("a", contracts.AnnotationSource, contracts.Application, true),
+		annotation("a", contracts.AnnotationTLS, contracts.Application, false),
+	}}
+
+	tests := []struct {
+		name     string
+		weights  Weights
+		list     contracts.AnnotationList
+		expected Score
+	}{
+		{"empty", Weights{}, contracts.AnnotationList{}, Score{}},
+		{"equal weights", Weights{}, list, Score{Key: "a", Confidence: 0.5, Satisfied: 2, Total: 4}},
+		{"kind weights", Weights{Kinds: map[contracts.AnnotationType]float64{contracts.AnnotationTPM: 3}}, list,
+			Score{Key: "a", Confidence: 4.0 / 6, Satisfied: 2, Total: 4}},
+		{"layer excluded", Weights{Layers: map[contracts.LayerType]float64{contracts.Host: 0}}, list,
+			Score{Key: "a", Confidence: 1.0 / 3, Satisfied: 1, Total: 3}},
+		{"mixed keys", Weights{}, contracts.AnnotationList{Items: []contracts.Annotation{
+			annotation("a", contracts.AnnotationTPM, contracts.Host, true),
+			annotation("b", contracts.AnnotationTPM, contracts.Host, true)}},
+			Score{Confidence: 1, Satisfied: 2, Total: 2}},
+		{"default layer", Weights{Layers: map[contracts.LayerType]float64{contracts.Host: 0}},
+			contracts.AnnotationList{Items: []contracts.Annotation{
+				annotation("a", contracts.AnnotationTPM, "", true),
+				annotation("a", contracts.AnnotationPKI, "", false)}},
+			Score{Key: "a", Confidence: 0, Satisfied: 0, Total: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewScorer(tt.weights)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			score := s.Score(tt.list)
+			assert.InDelta(t, tt.expected.Confidence, score.Confidence, 1e-9)
+			score.Confidence = tt.expected.Confidence
+			assert.Equal(t, tt.expected, score)
+		})
+	}
+}
+
+func TestScorer_ScoreByKey(t *testing.T) {
+	s, err := NewScorer(Weights{})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	scores := s.ScoreByKey(contracts.AnnotationList{Items: []contracts.Annotation{
+		{Key: "a", Kind: contracts.AnnotationTPM, IsSatisfied: true},
+		{Key: "b", Kind: contracts.AnnotationTPM, IsSatisfied: false},
+		{Key: "a", Kind: contracts.AnnotationPKI, IsSatisfied: false},
+	}})
+
+	assert.Equal(t, map[string]Score{
+		"a": {Key: "a", Confidence: 0.5, Satisfied: 1, Total: 2},
+		"b": {Key: "b", Confidence: 0, Satisfied: 0, Total: 1},
+	}, scores)
+}
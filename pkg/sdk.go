@@ -14,49 +14,287 @@
 package pkg
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"reflect"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/dedup"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/hostinfo"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/sampling"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/filehash"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/logging"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/merkle"
 	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 )
 
+const defaultQueueSize = 100
+
+// tracerName identifies the spans created by the SDK
+const tracerName = "github.com/project-alvarium/alvarium-sdk-go"
+
 type sdk struct {
+	cfgMu      sync.RWMutex // cfgMu is held for reading by every operation, Reconfigure holds it to switch over
 	annotators []interfaces.Annotator
 	cfg        config.SdkInfo
 	stream     interfaces.StreamProvider
+	provider   interfaces.StreamProvider // provider is nil unless given by WithStreamProvider
 	logger     interfaces.Logger
+	metrics    interfaces.PublishMetrics
+	annotated  interfaces.AnnotationMetrics
+	journal    interfaces.AnnotationJournal
+	tracer     trace.Tracer
+
+	sampler      *sampling.Sampler         // sampler is nil unless a sampling policy is configured
+	dedup        *dedup.Filter             // dedup is nil unless de-duplication is configured
+	chain        *annotators.Chain         // chain is nil unless annotations are chained, see config.SdkInfo.Chain
+	ids          interfaces.IdGenerator    // ids is nil when annotations are identified by the default ULIDs
+	customIds    bool                      // customIds is set when ids was given by WithIdGenerator rather than configured
+	tags         interfaces.TagValueGetter // tags reads the tags of the annotations from the configured sources
+	customTags   bool                      // customTags is set when tags was given by WithTagValueGetter
+	clock        interfaces.Clock          // clock is nil when annotations are timestamped by the system clock
+	host         *hostinfo.Collector       // host is nil unless host attributes are attached, see config.SdkInfo.Enrichment
+	interceptors []interfaces.PublishInterceptor
+	onError      func(Failure)
+	send         interfaces.PublishFunc // send publishes through the interceptors to the stream provider
+
+	queue   chan job       // queue holds work awaiting a worker in asynchronous mode, it is nil otherwise
+	mu      sync.RWMutex   // mu guards closed, queue is closed once with mu held
+	closed  bool           // closed is set on shutdown, after which no work is accepted
+	workers sync.WaitGroup // workers tracks the goroutines processing queue
+
+	pendingMu sync.Mutex
+	pending   int           // pending is the number of queued and running jobs
+	idle      chan struct{} // idle is closed whenever pending drops to zero, see Drain
+
+	healthMu sync.Mutex
+	states   []annotatorState             // states holds the outcome of the last annotation by each annotator
+	signer   interfaces.SignatureProvider // signer probes the signing key, it is created by the first Health call
+}
+
+// annotatorState is the outcome of the last annotation made by an annotator, reported by Health
+type annotatorState struct {
+	kind contracts.AnnotationType // kind is the kind of annotation produced, once known
+	err  error
+}
+
+// job is a unit of asynchronous work, run with the context of the call that submitted it
+type job struct {
+	ctx context.Context
+	run func(ctx context.Context)
+}
+
+// Option customizes an Sdk instance created by NewSdk
+type Option func(*sdk)
+
+// WithPublishMetrics reports the activity of the configured stream provider to metrics
+func WithPublishMetrics(metrics interfaces.PublishMetrics) Option {
+	return func(s *sdk) {
+		s.metrics = metrics
+	}
+}
+
+// WithAnnotationMetrics reports every annotation published by the SDK to metrics
+func WithAnnotationMetrics(metrics interfaces.AnnotationMetrics) Option {
+	return func(s *sdk) {
+		s.annotated = metrics
+	}
+}
+
+// WithJournal records every annotation in journal before it is published, see journal.Open
+func WithJournal(journal interfaces.AnnotationJournal) Option {
+	return func(s *sdk) {
+		s.journal = journal
+	}
+}
+
+// WithTracerProvider creates the spans of the SDK with provider instead of the global OpenTelemetry tracer provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(s *sdk) {
+		s.tracer = provider.Tracer(tracerName)
+	}
+}
+
+// WithAnnotators restricts a call to the annotators making the given kinds of annotation
+func WithAnnotators(kinds ...contracts.AnnotationType) interfaces.CallOption {
+	return func(o *interfaces.CallOptions) {
+		o.Annotators = append(o.Annotators, kinds...)
+	}
 }
 
-func NewSdk(annotators []interfaces.Annotator, cfg config.SdkInfo, logger interfaces.Logger) interfaces.Sdk {
+// Without excludes the annotators making the given kinds of annotation from a call
+func Without(kinds ...contracts.AnnotationType) interfaces.CallOption {
+	return func(o *interfaces.CallOptions) {
+		o.Without = append(o.Without, kinds...)
+	}
+}
+
+// WithParentKey records key, the hash of the data as it was sent by the previous application, as the ParentKey of the
+// annotations made by Transit
+func WithParentKey(key string) interfaces.CallOption {
+	return func(o *interfaces.CallOptions) {
+		o.ParentKey = key
+	}
+}
+
+// newCallOptions applies opts
+func newCallOptions(opts []interfaces.CallOption) interfaces.CallOptions {
+	var call interfaces.CallOptions
+	for _, opt := range opts {
+		opt(&call)
+	}
+	return call
+}
+
+// selects reports whether annotator a applies to a call
+func selects(call interfaces.CallOptions, a interfaces.Annotator) bool {
+	if len(call.Annotators) == 0 && len(call.Without) == 0 {
+		return true
+	}
+	reporter, ok := a.(interfaces.KindReporter)
+	if !ok {
+		return len(call.Annotators) == 0
+	}
+	kind := reporter.Kind()
+	if slices.Contains(call.Without, kind) {
+		return false
+	}
+	return len(call.Annotators) == 0 || slices.Contains(call.Annotators, kind)
+}
+
+// WithPublishInterceptors wraps every publish in the given interceptors. The first interceptor is the outermost, it
+// sees the wrapper first and the outcome of the publish last.
+func WithPublishInterceptors(interceptors ...interfaces.PublishInterceptor) Option {
+	return func(s *sdk) {
+		s.interceptors = append(s.interceptors, interceptors...)
+	}
+}
+
+// WithTagValueGetter tags annotations with the values of getter in place of those read from the source selected by
+// config.SdkInfo.Tag
+func WithTagValueGetter(getter interfaces.TagValueGetter) Option {
+	return func(s *sdk) {
+		s.tags = getter
+		s.customTags = true
+	}
+}
+
+// WithIdGenerator identifies annotations with the identifiers of gen in place of those selected by
+// config.SdkInfo.IdType, such as snowflake IDs or identifiers that stay stable when data is republished
+func WithIdGenerator(gen interfaces.IdGenerator) Option {
+	return func(s *sdk) {
+		s.ids = gen
+		s.customIds = true
+	}
+}
+
+// WithStreamProvider publishes to provider in place of the stream provider configured by config.SdkInfo.Stream, such
+// as an alvariumtest.Recorder in tests. The provider is kept when the SDK is reconfigured.
+func WithStreamProvider(provider interfaces.StreamProvider) Option {
+	return func(s *sdk) {
+		s.provider = provider
+	}
+}
+
+// WithClock timestamps annotations with the time told by clock in place of the system clock, so that tests can make
+// annotations at a known time
+func WithClock(clock interfaces.Clock) Option {
+	return func(s *sdk) {
+		s.clock = clock
+	}
+}
+
+// WithErrorHandler calls handler with every operation that fails, in addition to logging it, so that applications
+// can alert on or retry the failure. The handler is called on the goroutine doing the work, a worker in asynchronous
+// mode, and should return quickly.
+func WithErrorHandler(handler func(Failure)) Option {
+	return func(s *sdk) {
+		s.onError = handler
+	}
+}
+
+// NewSdk creates an SDK instance applying the given annotators, in order, to the data it is handed. Annotators may be
+// created with factories.NewAnnotator from the configured kinds, or be any other implementation of
+// interfaces.Annotator, so built-in and application specific annotators can be mixed in one pipeline.
+func NewSdk(annotators []interfaces.Annotator, cfg config.SdkInfo, logger interfaces.Logger,
+	opts ...Option) interfaces.Sdk {
+	cfg.AddLayers()
 	instance := sdk{
 		annotators: annotators,
 		cfg:        cfg,
-		logger:     logger,
+		logger:     logging.Component(logger, logging.ComponentSdk),
+		tracer:     otel.Tracer(tracerName),
+		states:     make([]annotatorState, len(annotators)),
+	}
+	for _, opt := range opts {
+		opt(&instance)
+	}
+	if cfg.Sampling.Enabled() {
+		instance.sampler = sampling.NewSampler(cfg.Sampling)
+	}
+	if cfg.Dedup.Enabled() {
+		instance.dedup = dedup.NewFilter(time.Duration(cfg.Dedup.Window) * time.Millisecond)
+	}
+	instance.chain = chainFor(cfg, nil)
+	if !instance.customIds {
+		ids, err := idsFor(cfg)
+		if err != nil {
+			instance.logger.Error(err.Error())
+		}
+		instance.ids = ids
+	}
+	if !instance.customTags {
+		tags, err := tagsFor(cfg)
+		if err != nil {
+			instance.logger.Error(err.Error())
+		}
+		instance.tags = tags
+	}
+	host, err := hostFor(cfg)
+	if err != nil {
+		instance.logger.Error(err.Error())
+	}
+	instance.host = host
+	if cfg.Async.Workers > 0 {
+		size := cfg.Async.QueueSize
+		if size == 0 {
+			size = defaultQueueSize
+		}
+		instance.queue = make(chan job, size)
 	}
 	return &instance
 }
 
 func (s *sdk) BootstrapHandler(ctx context.Context, wg *sync.WaitGroup) bool {
-	stream, err := factories.NewStreamProvider(s.cfg.Stream, s.logger)
+	stream, send, err := s.connect(ctx, s.cfg.Stream)
 	if err != nil {
 		s.logger.Error(err.Error())
 		return false
 	}
 	s.stream = stream
-	//Connect to stream provider
-	err = s.stream.Connect()
-	if err != nil {
-		s.logger.Error(err.Error())
-		return false
+	s.send = send
+
+	for i := 0; i < s.cfg.Async.Workers; i++ {
+		s.workers.Add(1)
+		go s.work()
 	}
-	s.logger.Write(slog.LevelDebug, "stream provider connection successful")
 
 	wg.Add(1)
 	go func() { // Graceful shutdown
@@ -64,113 +302,1118 @@ func (s *sdk) BootstrapHandler(ctx context.Context, wg *sync.WaitGroup) bool {
 
 		<-ctx.Done()
 		s.logger.Write(slog.LevelInfo, "shutdown received")
+		s.stopWorkers()
+		s.cfgMu.RLock()
+		defer s.cfgMu.RUnlock()
 		s.stream.Close()
 	}()
 	return true
 }
 
-func (s *sdk) Create(ctx context.Context, data []byte) {
-	var list contracts.AnnotationList
+// connect creates and connects the configured stream provider, unless one was given by WithStreamProvider, returning
+// it with the function publishing to it through the interceptors
+func (s *sdk) connect(ctx context.Context, cfg config.StreamInfo) (interfaces.StreamProvider, interfaces.PublishFunc,
+	error) {
+	stream := s.provider
+	if stream == nil {
+		var err error
+		if stream, err = factories.NewStreamProviderWithMetrics(cfg, s.logger, s.metrics); err != nil {
+			return nil, nil, err
+		}
+	}
+	send := stream.Publish
+	for i := len(s.interceptors) - 1; i >= 0; i-- {
+		send = s.interceptors[i](send)
+	}
+	send = s.traced(cfg.Type, send)
 
-	for _, a := range s.annotators {
-		annotation, err := a.Do(ctx, data)
+	//Connect to stream provider
+	if err := stream.Connect(ctx); err != nil {
+		return nil, nil, err
+	}
+	s.logger.Write(slog.LevelDebug, "stream provider connection successful")
+	return stream, send, nil
+}
+
+// Reconfigure switches the SDK over to cfg. The annotators named by cfg, its stream provider and its keys are set
+// up first, so that a configuration that cannot be applied leaves the current one in place. The switch then waits
+// for annotations in progress to be published and takes effect at once, before the previous stream provider is
+// closed. Annotators passed to NewSdk are replaced by those named by cfg. The asynchronous mode settings cannot be
+// changed at runtime and are kept.
+func (s *sdk) Reconfigure(ctx context.Context, cfg config.SdkInfo) error {
+	s.cfgMu.RLock()
+	bootstrapped := s.stream != nil
+	cfg.Async = s.cfg.Async
+	s.cfgMu.RUnlock()
+	if !bootstrapped {
+		return fmt.Errorf("%w: stream provider has not been initialized", contracts.ErrNotConnected)
+	}
+
+	cfg.AddLayers()
+	annotators := make([]interfaces.Annotator, len(cfg.Annotators))
+	for i, kind := range cfg.Annotators {
+		a, err := factories.NewAnnotator(kind, cfg)
 		if err != nil {
-			s.logger.Error(err.Error())
-			return
+			return err
 		}
-		list.Items = append(list.Items, annotation)
+		annotators[i] = a
+	}
+	ids := s.ids
+	if !s.customIds {
+		var err error
+		if ids, err = idsFor(cfg); err != nil {
+			return err
+		}
+	}
+	tags := s.tags
+	if !s.customTags {
+		var err error
+		if tags, err = tagsFor(cfg); err != nil {
+			return err
+		}
+	}
+	host, err := hostFor(cfg)
+	if err != nil {
+		return err
+	}
+	stream, send, err := s.connect(ctx, cfg.Stream)
+	if err != nil {
+		return err
 	}
 
-	b, _ := json.Marshal(list)
-	wrap := message.PublishWrapper{
-		Action:      message.ActionCreate,
-		MessageType: fmt.Sprintf("%T", list),
-		Content:     b,
+	var sampler *sampling.Sampler
+	if cfg.Sampling.Enabled() {
+		sampler = sampling.NewSampler(cfg.Sampling)
+	}
+	var filter *dedup.Filter
+	if cfg.Dedup.Enabled() {
+		filter = dedup.NewFilter(time.Duration(cfg.Dedup.Window) * time.Millisecond)
 	}
-	err := s.stream.Publish(wrap)
+	chain := chainFor(cfg, s.chain)
+
+	s.cfgMu.Lock()
+	previous := s.stream
+	s.cfg = cfg
+	s.annotators = annotators
+	s.sampler = sampler
+	s.dedup = filter
+	s.chain = chain
+	s.ids = ids
+	s.tags = tags
+	s.host = host
+	s.stream = stream
+	s.send = send
+	s.healthMu.Lock()
+	s.states = make([]annotatorState, len(annotators))
+	s.signer = nil
+	s.healthMu.Unlock()
+	s.cfgMu.Unlock()
+
+	s.logger.Write(slog.LevelInfo, "sdk reconfigured")
+	if previous == stream { // given by WithStreamProvider, it stays in use
+		return nil
+	}
+	return previous.Close()
+}
+
+// current returns the configuration in effect
+func (s *sdk) current() config.SdkInfo {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.cfg
+}
+
+// Drain waits until all queued asynchronous work has been published, or ctx is done. Work submitted while draining is
+// waited for as well. Drain returns immediately when asynchronous mode is not enabled.
+func (s *sdk) Drain(ctx context.Context) error {
+	s.pendingMu.Lock()
+	if s.pending == 0 {
+		s.pendingMu.Unlock()
+		return nil
+	}
+	idle := s.idle
+	s.pendingMu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// submit runs the work inline, or queues it for a worker in asynchronous mode applying the configured backpressure
+// policy. Queued work runs with a context keeping the values of ctx but not its cancellation, since the call returns
+// before the work is done. The call tracked in ctx, if any, is settled once the work has run or could not be queued.
+func (s *sdk) submit(ctx context.Context, action message.SdkAction, run func(ctx context.Context)) {
+	run = withEncodings(run)
+	if s.queue == nil {
+		run(ctx)
+		trackerFrom(ctx).settle(nil)
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		s.refuse(ctx, Failure{Action: action, Stage: StageQueue,
+			Err: fmt.Errorf("%w, discarding %s", ErrShutdown, action)})
+		return
+	}
+
+	j := job{ctx: context.WithoutCancel(ctx), run: run}
+	s.addPending()
+	if s.current().Async.Policy == contracts.BackpressureDrop {
+		select {
+		case s.queue <- j:
+		default:
+			s.donePending()
+			s.refuse(ctx, Failure{Action: action, Stage: StageQueue,
+				Err: fmt.Errorf("%w, dropping %s", ErrQueueFull, action)})
+		}
+		return
+	}
+	select {
+	case s.queue <- j:
+	case <-ctx.Done():
+		s.donePending()
+		s.refuse(ctx, Failure{Action: action, Stage: StageQueue,
+			Err: fmt.Errorf("abandoned %s waiting for the async queue %w", action, ctx.Err())})
+	}
+}
+
+// withEncodings returns run holding in its context the Encodings of the annotations signed during the operation, so
+// that they are published without being encoded again
+func withEncodings(run func(ctx context.Context)) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		enc := annotators.NewEncodings()
+		defer enc.Release()
+		run(context.WithValue(ctx, contracts.EncodingsKey, enc))
+	}
+}
+
+// refuse reports work that could not be queued, settling its call
+func (s *sdk) refuse(ctx context.Context, f Failure) {
+	s.report(f)
+	trackerFrom(ctx).settle(f.Err)
+}
+
+// work processes queued jobs until the queue is closed
+func (s *sdk) work() {
+	defer s.workers.Done()
+	for j := range s.queue {
+		j.run(j.ctx)
+		trackerFrom(j.ctx).settle(nil)
+		s.donePending()
+	}
+}
+
+// stopWorkers stops accepting work and waits for the workers to finish what is already queued
+func (s *sdk) stopWorkers() {
+	if s.queue == nil {
+		return
+	}
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	s.workers.Wait()
+}
+
+func (s *sdk) addPending() {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	if s.pending == 0 {
+		s.idle = make(chan struct{})
+	}
+	s.pending++
+}
+
+func (s *sdk) donePending() {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	s.pending--
+	if s.pending == 0 {
+		close(s.idle)
+	}
+}
+
+// retain copies data that will be processed asynchronously, so that callers are free to reuse their buffers
+func (s *sdk) retain(data []byte) []byte {
+	if s.queue == nil {
+		return data
+	}
+	return bytes.Clone(data)
+}
+
+func (s *sdk) Healthy(ctx context.Context) error {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.healthy(ctx)
+}
+
+// healthy reports the health of the stream provider, the caller holds cfgMu
+func (s *sdk) healthy(ctx context.Context) error {
+	if s.stream == nil {
+		return fmt.Errorf("%w: stream provider has not been initialized", contracts.ErrNotConnected)
+	}
+	if checker, ok := s.stream.(interfaces.HealthChecker); ok {
+		return checker.Healthy(ctx)
+	}
+	return nil
+}
+
+func (s *sdk) Replay(ctx context.Context, q interfaces.ReplayQuery) (int, error) {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	if s.journal == nil {
+		return 0, ErrNoJournal
+	}
+	if s.stream == nil {
+		return 0, fmt.Errorf("%w: stream provider has not been initialized", contracts.ErrNotConnected)
+	}
+	ctx, span := s.tracer.Start(ctx, "alvarium.replay")
+	defer span.End()
+
+	entries, err := s.journal.Replayable(q)
 	if err != nil {
-		s.logger.Error(err.Error())
+		failed(span, err)
+		return 0, err
 	}
+	for i, entry := range entries {
+		// stop at the first failure, so that publishes are replayed in order once the stream recovers
+		wrap, err := s.wrap(ctx, entry.Action, contracts.AnnotationList{Items: entry.Annotations})
+		if err == nil {
+			ctx, d := message.WithDelivery(ctx, s.acknowledge(ctx, entry.Action, entry.Annotations))
+			err = s.send(ctx, wrap)
+			d.Sent(err)
+		}
+		if err != nil {
+			failed(span, err)
+			return i, err
+		}
+	}
+	s.logger.Write(slog.LevelInfo, fmt.Sprintf("replayed %v journaled publishes", len(entries)))
+	return len(entries), nil
 }
 
-func (s *sdk) Mutate(ctx context.Context, old, new []byte) {
-	src, err := factories.NewAnnotator(contracts.AnnotationSource, s.cfg)
+func (s *sdk) Health(ctx context.Context) contracts.Health {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	components := []contracts.ComponentHealth{
+		contracts.NewComponentHealth("stream", s.healthy(ctx)),
+		contracts.NewComponentHealth("signature", s.probeKey()),
+	}
+
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	for i, a := range s.annotators {
+		name := typeName(a)
+		if s.states[i].kind != "" {
+			name = string(s.states[i].kind)
+		}
+		components = append(components, contracts.NewComponentHealth("annotator/"+name, s.states[i].err))
+	}
+	return contracts.NewHealth(components)
+}
+
+// probeKey signs a probe with the signing key currently in effect, showing that the key can be loaded and used. The
+// caller holds cfgMu.
+func (s *sdk) probeKey() error {
+	signer, err := s.signerFor()
 	if err != nil {
-		s.logger.Error(err.Error())
+		return err
+	}
+	_, err = signer.Sign(s.cfg.Signature.ActivePrivateKey(time.Now()), []byte("health"))
+	return err
+}
+
+// signerFor returns the signature provider of the configured keys, creating it on first use
+func (s *sdk) signerFor() (interfaces.SignatureProvider, error) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	if s.signer == nil {
+		signer, err := factories.NewSignatureProviderWithInfo(s.cfg.Signature)
+		if err != nil {
+			return nil, err
+		}
+		s.signer = signer
+	}
+	return s.signer, nil
+}
+
+// observe records the outcome of an annotation by the i-th annotator for Health
+func (s *sdk) observe(i int, kind contracts.AnnotationType, err error) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	if kind != "" {
+		s.states[i].kind = kind
+	}
+	s.states[i].err = err
+}
+
+func (s *sdk) Create(ctx context.Context, data []byte, opts ...interfaces.CallOption) {
+	data = s.retain(data)
+	call := newCallOptions(opts)
+	ctx = track(ctx, call)
+	s.submit(ctx, message.ActionCreate, func(ctx context.Context) { s.create(ctx, data, call) })
+}
+
+func (s *sdk) create(ctx context.Context, data []byte, call interfaces.CallOptions) {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	ctx, span := s.tracer.Start(ctx, "alvarium.create")
+	defer span.End()
+	ctx, ok := s.sample(ctx, message.ActionCreate, data)
+	if !ok {
 		return
 	}
-	a, err := src.Do(ctx, old)
 
-	var list contracts.AnnotationList
-	list.Items = append(list.Items, a)
+	items, ok := s.annotateAll(ctx, message.ActionCreate, call, data)
+	if !ok {
+		return
+	}
+	s.emit(ctx, message.ActionCreate, contracts.AnnotationList{Items: items})
+}
 
-	for _, a := range s.annotators {
-		annotation, err := a.Do(ctx, new)
+// sample applies the sampling policy to data, reporting whether it is annotated. The hash derived to reach the
+// decision is placed in the returned context so that the annotators do not derive it again.
+func (s *sdk) sample(ctx context.Context, action message.SdkAction, data []byte) (context.Context, bool) {
+	if s.sampler == nil {
+		return ctx, true
+	}
+	var key string
+	if s.sampler.NeedsKey() {
+		hash, err := factories.NewHashProviderWithInfo(s.cfg.Hash)
+		if err == nil {
+			key, err = annotators.DeriveHash(ctx, hash, data)
+		}
 		if err != nil {
-			s.logger.Error(err.Error())
-			return
+			s.fail(ctx, Failure{Action: action, Stage: StageHash, Err: err})
+			return ctx, false
 		}
-		if annotation.Kind != contracts.AnnotationTLS {
-			list.Items = append(list.Items, annotation)
+		ctx = context.WithValue(ctx, contracts.DataHashKey, key)
+	}
+	if !s.sampler.Sample(key) {
+		s.logger.Write(slog.LevelDebug, fmt.Sprintf("%s skipped by sampling policy", action))
+		return ctx, false
+	}
+	return ctx, true
+}
+
+// chainFor returns the chain annotations are appended to under cfg. The chain in use, if any, is carried over so that
+// it is not broken by a switch of configuration.
+func chainFor(cfg config.SdkInfo, current *annotators.Chain) *annotators.Chain {
+	if !cfg.Chain {
+		return nil
+	}
+	if current != nil {
+		return current
+	}
+	return annotators.NewChain()
+}
+
+// idsFor returns the generator of annotation identifiers selected by cfg, nil for the default ULIDs
+func idsFor(cfg config.SdkInfo) (interfaces.IdGenerator, error) {
+	if cfg.IdType == "" {
+		return nil, nil
+	}
+	return factories.NewIdGenerator(cfg.IdType)
+}
+
+// tagsFor returns the getter of the tags of annotations from the sources configured for each layer
+func tagsFor(cfg config.SdkInfo) (interfaces.TagValueGetter, error) {
+	return factories.NewLayerTagValueGetter(cfg)
+}
+
+// hostFor returns the collector of the attributes of the host, nil unless they are attached
+func hostFor(cfg config.SdkInfo) (*hostinfo.Collector, error) {
+	if !cfg.Enrichment.Enabled() {
+		return nil, nil
+	}
+	return hostinfo.NewCollector(cfg.Enrichment)
+}
+
+// annotating returns the context annotators are called with. It appends the annotations made with it to the chain of
+// the SDK, if annotations are chained, and carries the generator of their identifiers, the getter of their tag and the
+// clock timestamping them, if not the default, as well as the attributes of the host if they are attached to each
+// annotation.
+func (s *sdk) annotating(ctx context.Context) context.Context {
+	if s.chain != nil {
+		ctx = context.WithValue(ctx, contracts.ChainKey, s.chain)
+	}
+	if s.ids != nil {
+		ctx = context.WithValue(ctx, contracts.IdGeneratorKey, s.ids)
+	}
+	if s.tags != nil {
+		ctx = context.WithValue(ctx, contracts.TagValueGetterKey, s.tags)
+	}
+	if s.clock != nil {
+		ctx = context.WithValue(ctx, contracts.ClockKey, s.clock)
+	}
+	if s.host != nil && s.cfg.Enrichment.AttachTo() == contracts.EnrichAnnotation {
+		ctx = context.WithValue(ctx, contracts.HostInfoKey, s.host.HostInfo())
+	}
+	return ctx
+}
+
+// allows reports whether annotator a may annotate n pieces of data within the rate limit of its kind. Annotators
+// that do not report their kind are not limited.
+func (s *sdk) allows(a interfaces.Annotator, n int) bool {
+	return s.sampler == nil || s.sampler.Allow(kindOf(a), n)
+}
+
+// annotateAll makes the annotations of data by the annotators selected for the call, concurrently if configured (see
+// config.PipelineInfo), and applies the pipeline policies to their outcomes in the order of the annotators. It
+// returns false if the operation was aborted.
+func (s *sdk) annotateAll(ctx context.Context, action message.SdkAction, call interfaces.CallOptions,
+	data []byte) ([]contracts.Annotation, bool) {
+	type outcome struct {
+		annotator  interfaces.Annotator
+		annotation contracts.Annotation
+		err        error
+	}
+	outcomes := make([]outcome, 0, len(s.annotators))
+	selected := make([]int, 0, len(s.annotators)) // selected holds the index of the annotator of each outcome
+	for i, a := range s.annotators {
+		if selects(call, a) && s.allows(a, 1) {
+			outcomes = append(outcomes, outcome{annotator: a})
+			selected = append(selected, i)
 		}
 	}
 
-	b, _ := json.Marshal(list)
-	wrap := message.PublishWrapper{
-		Action:      message.ActionMutate,
-		MessageType: fmt.Sprintf("%T", list),
-		Content:     b,
+	run := func(o *outcome) {
+		o.annotation, o.err = s.annotate(ctx, o.annotator, data)
 	}
-	err = s.stream.Publish(wrap)
+	if s.cfg.Pipeline.Parallel() && len(outcomes) > 1 {
+		var g errgroup.Group
+		g.SetLimit(s.cfg.Pipeline.Parallelism)
+		for i := range outcomes {
+			o := &outcomes[i]
+			g.Go(func() error {
+				run(o)
+				return nil
+			})
+		}
+		_ = g.Wait()
+	}
+
+	items := make([]contracts.Annotation, 0, len(outcomes))
+	for i := range outcomes {
+		o := &outcomes[i]
+		if !s.cfg.Pipeline.Parallel() || len(outcomes) == 1 {
+			// annotators run in turn stop at the first one aborting the operation
+			run(o)
+		}
+		s.observe(selected[i], o.annotation.Kind, o.err)
+		switch s.judge(ctx, action, o.annotator, data, &o.annotation, o.err) {
+		case abort:
+			return nil, false
+		case keep:
+			items = append(items, o.annotation)
+		}
+	}
+	return items, true
+}
+
+// annotate runs the annotator within a span recording the kind of annotation it produced. The annotator is abandoned
+// if it runs past the configured timeout.
+func (s *sdk) annotate(ctx context.Context, a interfaces.Annotator, data []byte) (contracts.Annotation, error) {
+	ctx, span := s.tracer.Start(ctx, "alvarium.annotate",
+		trace.WithAttributes(attribute.String("alvarium.annotator", typeName(a))))
+	defer span.End()
+
+	annotation, err := s.do(s.annotating(ctx), a, data)
 	if err != nil {
-		s.logger.Error(err.Error())
+		failed(span, err)
+		return annotation, err
 	}
+	span.SetAttributes(attribute.String("alvarium.kind", string(annotation.Kind)),
+		attribute.Bool("alvarium.satisfied", annotation.IsSatisfied))
+	return annotation, nil
 }
 
-func (s *sdk) Transit(ctx context.Context, data []byte) {
-	var list contracts.AnnotationList
+// typeNames caches the type names of annotators, see typeName
+var typeNames sync.Map
 
-	for _, a := range s.annotators {
-		annotation, err := a.Do(ctx, data)
-		if err != nil {
-			s.logger.Error(err.Error())
-			return
+// typeName returns the type name of the annotator as the %T verb formats it, without formatting it on every call
+func typeName(a interfaces.Annotator) string {
+	t := reflect.TypeOf(a)
+	if name, ok := typeNames.Load(t); ok {
+		return name.(string)
+	}
+	name := fmt.Sprintf("%T", a)
+	typeNames.Store(t, name)
+	return name
+}
+
+// verdict is what the pipeline policies make of the outcome of an annotator, see config.PipelineInfo
+type verdict int
+
+const (
+	keep  verdict = iota // the annotation is published
+	skip                 // the annotator is left out, the other annotations are published
+	abort                // the operation is abandoned, nothing more is published
+)
+
+// judge applies the pipeline policies to the annotation made of data by annotator a, or to the error it returned. A
+// failure published under the annotate policy replaces the annotation.
+func (s *sdk) judge(ctx context.Context, action message.SdkAction, a interfaces.Annotator, data []byte,
+	annotation *contracts.Annotation, err error) verdict {
+	if err == nil {
+		return s.satisfied(ctx, action, *annotation)
+	}
+	v := s.failure(ctx, action, a, err)
+	if v == keep {
+		if *annotation, err = s.errorAnnotation(ctx, kindOf(a), data, err); err != nil {
+			s.fail(ctx, Failure{Action: action, Stage: StageAnnotate, Annotator: kindOf(a), Err: err})
+			return abort
 		}
-		list.Items = append(list.Items, annotation)
 	}
+	return v
+}
 
-	b, _ := json.Marshal(list)
-	wrap := message.PublishWrapper{
-		Action:      message.ActionTransit,
-		MessageType: fmt.Sprintf("%T", list),
-		Content:     b,
+// failure reports the error returned by annotator a and applies the error policy to it. Keep means that the failure
+// is to be published, see errorAnnotation.
+func (s *sdk) failure(ctx context.Context, action message.SdkAction, a interfaces.Annotator, err error) verdict {
+	kind := kindOf(a)
+	f := Failure{Action: action, Stage: StageAnnotate, Annotator: kind, Err: err}
+	v := s.errorVerdict(kind)
+	if v == abort {
+		s.fail(ctx, f)
+	} else {
+		s.tolerate(ctx, f)
+	}
+	return v
+}
+
+// errorVerdict applies the error policy to a failure of an annotator of kind
+func (s *sdk) errorVerdict(kind contracts.AnnotationType) verdict {
+	if slices.Contains(s.cfg.Pipeline.Optional, kind) {
+		return skip
+	}
+	switch s.cfg.Pipeline.ErrorPolicy() {
+	case contracts.PolicyContinue:
+		return skip
+	case contracts.PolicyAnnotate:
+		if kind != "" { // the kind of annotation to publish is not known
+			return keep
+		}
+	}
+	return abort
+}
+
+// satisfied applies the unsatisfied policy to annotation
+func (s *sdk) satisfied(ctx context.Context, action message.SdkAction, annotation contracts.Annotation) verdict {
+	if annotation.IsSatisfied || slices.Contains(s.cfg.Pipeline.Optional, annotation.Kind) ||
+		s.cfg.Pipeline.UnsatisfiedPolicy() != contracts.PolicyAbort {
+		return keep
+	}
+	err := fmt.Errorf("%w: %s annotation of %s", ErrUnsatisfied, annotation.Kind, annotation.Key)
+	s.fail(ctx, Failure{Action: action, Stage: StageAnnotate, Annotator: annotation.Kind, Err: err})
+	return abort
+}
+
+// errorAnnotation makes the unsatisfied annotation of the given kind recording that the annotator of data failed
+// with cause, signed with the configured keys
+func (s *sdk) errorAnnotation(ctx context.Context, kind contracts.AnnotationType, data []byte,
+	cause error) (contracts.Annotation, error) {
+	hash, err := factories.NewHashProviderWithInfo(s.cfg.Hash)
+	if err != nil {
+		return contracts.Annotation{}, err
 	}
-	err := s.stream.Publish(wrap)
+	key, err := annotators.DeriveHash(ctx, hash, data)
 	if err != nil {
-		s.logger.Error(err.Error())
+		return contracts.Annotation{}, err
+	}
+	signer, err := s.signerFor()
+	if err != nil {
+		return contracts.Annotation{}, err
+	}
+	hostname, _ := os.Hostname()
+
+	ctx = s.annotating(ctx)
+	annotation := contracts.NewAnnotation(key, s.cfg.Hash.Type, hostname, s.cfg.Layer, kind, false)
+	annotation.Timestamp = annotators.Timestamp(ctx, annotation)
+	annotation.ParentKey = annotators.ParentKey(ctx)
+	annotation.Error = cause.Error()
+	annotation.Tag = annotators.TagValue(ctx, annotation)
+	annotation.HostInfo = annotators.HostInfo(ctx)
+	annotation.Id = annotators.NewId(ctx, annotation)
+	if err = annotators.SignAndLink(ctx, s.cfg.Signature, signer, &annotation); err != nil {
+		return contracts.Annotation{}, err
 	}
+	return annotation, nil
 }
 
-func (s *sdk) Publish(ctx context.Context, data []byte) {
-	var list contracts.AnnotationList
+// do calls the annotator, returning once it completes or the configured timeout elapses. An annotator that ignores
+// the cancellation of its context keeps running in the background, its annotation being discarded.
+func (s *sdk) do(ctx context.Context, a interfaces.Annotator, data []byte) (contracts.Annotation, error) {
+	if s.cfg.Pipeline.Timeout <= 0 {
+		return a.Do(ctx, data)
+	}
+	timeout := time.Duration(s.cfg.Pipeline.Timeout) * time.Millisecond
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	for _, a := range s.annotators {
+	type result struct {
+		annotation contracts.Annotation
+		err        error
+	}
+	done := make(chan result, 1)
+	go func() {
 		annotation, err := a.Do(ctx, data)
+		done <- result{annotation, err}
+	}()
+	select {
+	case r := <-done:
+		return r.annotation, r.err
+	case <-ctx.Done():
+		return contracts.Annotation{}, fmt.Errorf("annotator %T did not complete within %v: %w", a, timeout, ctx.Err())
+	}
+}
+
+// traced wraps send in a span, propagating its trace context to consumers through the wrapper. It sits outside the
+// publish interceptors so that they run within the span.
+func (s *sdk) traced(stream contracts.StreamType, send interfaces.PublishFunc) interfaces.PublishFunc {
+	return func(ctx context.Context, msg message.PublishWrapper) error {
+		ctx, span := s.tracer.Start(ctx, "alvarium.send", trace.WithSpanKind(trace.SpanKindProducer),
+			trace.WithAttributes(attribute.String("alvarium.action", string(msg.Action)),
+				attribute.String("alvarium.stream", string(stream))))
+		defer span.End()
+
+		carrier := propagation.MapCarrier{}
+		propagation.TraceContext{}.Inject(ctx, carrier)
+		if len(carrier) > 0 {
+			msg.TraceContext = carrier
+		}
+		err := send(ctx, msg)
 		if err != nil {
-			s.logger.Error(err.Error())
+			failed(span, err)
+		}
+		return err
+	}
+}
+
+// failed marks span as failed with err
+func failed(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// emit publishes the annotations made by an operation
+func (s *sdk) emit(ctx context.Context, action message.SdkAction, list contracts.AnnotationList) {
+	if len(list.Items) == 0 && s.sampler != nil {
+		// every annotator was held back by its rate limit
+		return
+	}
+	if s.dedup != nil {
+		if list.Items = s.dedup.Filter(list.Items); len(list.Items) == 0 {
 			return
 		}
-		list.Items = append(list.Items, annotation)
 	}
+	s.record(list)
+	if s.journal != nil {
+		if err := s.journal.Record(action, list.Items); err != nil {
+			s.tolerate(ctx, Failure{Action: action, Stage: StageJournal, Annotations: list.Items, Err: err})
+		}
+	}
+	wrap, err := s.wrap(ctx, action, list)
+	if err == nil {
+		// the publish may be confirmed later by the stream provider, once it is delivered
+		t := trackerFrom(ctx)
+		t.hold()
+		acknowledge := s.acknowledge(ctx, action, list.Items)
+		var d *message.Delivery
+		ctx, d = message.WithDelivery(ctx, func(err error) {
+			acknowledge(err)
+			t.settle(err)
+		})
+		err = s.send(ctx, wrap)
+		d.Sent(err)
+	}
+	if err != nil {
+		s.fail(ctx, Failure{Action: action, Stage: StagePublish, Annotations: list.Items, Err: err})
+	}
+}
 
-	b, _ := json.Marshal(list)
+// listMessageType is the MessageType of the wrappers publishing annotations
+var listMessageType = fmt.Sprintf("%T", contracts.AnnotationList{})
+
+// wrap creates the wrapper publishing list for action in the configured content type and schema version
+func (s *sdk) wrap(ctx context.Context, action message.SdkAction,
+	list contracts.AnnotationList) (message.PublishWrapper, error) {
+	contentType := string(s.cfg.Stream.ContentType)
+	var b []byte
+	var err error
+	if contentType == "" || contentType == string(contracts.ContentTypeJSON) {
+		b, err = encodeList(annotators.EncodingsFrom(ctx), list)
+	} else {
+		b, err = message.MarshalContent(list, contentType)
+	}
+	if err != nil {
+		return message.PublishWrapper{}, err
+	}
 	wrap := message.PublishWrapper{
-		Action:      message.ActionPublish,
-		MessageType: fmt.Sprintf("%T", list),
+		Version:     message.WrapperVersion,
+		Action:      action,
+		MessageType: listMessageType,
 		Content:     b,
+		ContentType: contentType,
+	}
+	if s.host != nil && s.cfg.Enrichment.AttachTo() == contracts.EnrichWrapper {
+		wrap.HostInfo = s.host.HostInfo()
+	}
+	if version := s.cfg.Stream.SchemaVersion; version != 0 {
+		return wrap.ConvertTo(version)
+	}
+	return wrap, nil
+}
+
+// encodeList encodes list in JSON as json.Marshal does, reusing the encodings kept in enc of the annotations signed
+// during the operation
+func encodeList(enc *annotators.Encodings, list contracts.AnnotationList) ([]byte, error) {
+	if len(list.Items) == 0 {
+		return list.AppendJSON(nil)
+	}
+	// annotations encode in well under a kilobyte, unless tagged with many values
+	b := make([]byte, 0, 768*len(list.Items))
+	b = append(b, `{"items":[`...)
+	for i, a := range list.Items {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		if encoded, ok := enc.Lookup(a); ok {
+			b = append(b, encoded...)
+			continue
+		}
+		var err error
+		if b, err = a.AppendJSON(b); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, "]}"...), nil
+}
+
+// acknowledge returns the confirmation of the publish of items, marking them as delivered in the journal, if any, once
+// the publish is confirmed without error. The journal is resolved now, as the confirmation may come after the
+// configuration has changed.
+func (s *sdk) acknowledge(ctx context.Context, action message.SdkAction,
+	items []contracts.Annotation) func(err error) {
+	journal := s.journal
+	return func(err error) {
+		if err != nil || journal == nil {
+			return
+		}
+		if err = journal.Acknowledge(items); err != nil {
+			s.tolerate(ctx, Failure{Action: action, Stage: StageJournal, Annotations: items, Err: err})
+		}
+	}
+}
+
+// fail reports the failure of the operation whose span is in ctx, failing its call if tracked
+func (s *sdk) fail(ctx context.Context, f Failure) {
+	trackerFrom(ctx).fail(f.Err)
+	s.tolerate(ctx, f)
+}
+
+// tolerate reports a failure that the operation whose span is in ctx carries on past, such as of an optional
+// annotator or of the journal, without failing its call
+func (s *sdk) tolerate(ctx context.Context, f Failure) {
+	failed(trace.SpanFromContext(ctx), f.Err)
+	s.report(f)
+}
+
+// report logs the failure and hands it to the error handler, if any
+func (s *sdk) report(f Failure) {
+	s.logger.Error(f.Err.Error())
+	if s.onError != nil {
+		s.onError(f)
+	}
+}
+
+// kindOf returns the kind of annotation made by a, if it reports one
+func kindOf(a interfaces.Annotator) contracts.AnnotationType {
+	if reporter, ok := a.(interfaces.KindReporter); ok {
+		return reporter.Kind()
+	}
+	return ""
+}
+
+// record reports the annotations about to be published to the annotation metrics, if any
+func (s *sdk) record(list contracts.AnnotationList) {
+	if s.annotated == nil {
+		return
+	}
+	for _, a := range list.Items {
+		s.annotated.Annotated(a.Kind, a.Layer, a.IsSatisfied)
 	}
-	err := s.stream.Publish(wrap)
+}
+
+func (s *sdk) CreateFromReader(ctx context.Context, r io.Reader, opts ...interfaces.CallOption) {
+	hash, err := s.streamHashProvider(ctx)
+	var key string
+	if err == nil {
+		key, err = hash.DeriveFromReader(r)
+	}
+	s.createHashed(ctx, key, err, opts)
+}
+
+func (s *sdk) CreateForFile(ctx context.Context, path string, ignore []string, opts ...interfaces.CallOption) {
+	key, err := s.hashFile(ctx, path, ignore)
+	s.createHashed(ctx, key, err, opts)
+}
+
+// hashFile returns the hash of the file or directory tree at path
+func (s *sdk) hashFile(ctx context.Context, path string, ignore []string) (string, error) {
+	hash, err := s.streamHashProvider(ctx)
 	if err != nil {
-		s.logger.Error(err.Error())
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return filehash.HashDirectory(hash, path, ignore)
+	}
+	return filehash.HashFile(hash, path)
+}
+
+// createHashed creates the data whose hash key was derived by the caller, or reports the error deriving it, settling
+// the call
+func (s *sdk) createHashed(ctx context.Context, key string, err error, opts []interfaces.CallOption) {
+	if err != nil {
+		ctx = track(ctx, newCallOptions(opts))
+		s.fail(ctx, Failure{Action: message.ActionCreate, Stage: StageHash, Err: err})
+		trackerFrom(ctx).settle(nil)
+		return
+	}
+	s.Create(context.WithValue(ctx, contracts.DataHashKey, key), nil, opts...)
+}
+
+// streamHashProvider returns the configured hash provider for the tenant in the context, if any, ensuring that it
+// can hash data incrementally
+func (s *sdk) streamHashProvider(ctx context.Context) (interfaces.HashProviderStream, error) {
+	cfg := s.current().Hash
+	hash, err := factories.NewHashProviderWithInfo(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if hash, err = annotators.ForTenant(ctx, hash); err != nil {
+		return nil, err
+	}
+	stream, ok := hash.(interfaces.HashProviderStream)
+	if !ok {
+		return nil, fmt.Errorf("%w: hash type %s does not support streaming", contracts.ErrHashUnsupported, cfg.Type)
+	}
+	return stream, nil
+}
+
+func (s *sdk) CreateMerkleBatch(ctx context.Context, items [][]byte, opts ...interfaces.CallOption) []merkle.Proof {
+	if s.current().Hash.Type != contracts.MerkleHash {
+		s.createHashed(ctx, "", fmt.Errorf("%w: batch annotation requires hash type %s", contracts.ErrHashUnsupported,
+			contracts.MerkleHash), opts)
+		return nil
+	}
+	tree, err := merkle.New(items)
+	if err != nil {
+		s.createHashed(ctx, "", err, opts)
+		return nil
+	}
+
+	proofs := make([]merkle.Proof, tree.Len())
+	for i := range proofs {
+		if proofs[i], err = tree.Proof(i); err != nil {
+			s.createHashed(ctx, "", err, opts)
+			return nil
+		}
+	}
+	s.createHashed(ctx, tree.Root(), nil, opts)
+	return proofs
+}
+
+func (s *sdk) CreateBatch(ctx context.Context, items [][]byte, opts ...interfaces.CallOption) {
+	retained := make([][]byte, len(items))
+	for i := range items {
+		retained[i] = s.retain(items[i])
+	}
+	call := newCallOptions(opts)
+	ctx = track(ctx, call)
+	s.submit(ctx, message.ActionCreate, func(ctx context.Context) { s.createBatch(ctx, retained, call) })
+}
+
+// createBatch publishes one AnnotationList per annotator selected for the call, annotating the items together when
+// the annotator supports it
+func (s *sdk) createBatch(ctx context.Context, items [][]byte, call interfaces.CallOptions) {
+	if len(items) == 0 {
+		return
+	}
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	ctx, span := s.tracer.Start(ctx, "alvarium.create_batch",
+		trace.WithAttributes(attribute.Int("alvarium.items", len(items))))
+	defer span.End()
+
+	sampled := items[:0:0]
+	for _, item := range items {
+		if _, ok := s.sample(ctx, message.ActionCreate, item); ok {
+			sampled = append(sampled, item)
+		}
+	}
+	if len(sampled) == 0 {
+		return
+	}
+	items = sampled
+
+	for i, a := range s.annotators {
+		if !selects(call, a) || !s.allows(a, len(items)) {
+			continue
+		}
+		var list contracts.AnnotationList
+		if batch, ok := a.(interfaces.BatchAnnotator); ok {
+			annotations, err := batch.DoBatch(s.annotating(ctx), items)
+			var kind contracts.AnnotationType
+			if len(annotations) > 0 {
+				kind = annotations[0].Kind
+			}
+			s.observe(i, kind, err)
+			if err != nil {
+				// the batch failed as a whole, the policy applies once
+				switch s.failure(ctx, message.ActionCreate, a, err) {
+				case abort:
+					return
+				case skip:
+					continue
+				}
+				annotations = nil
+				for _, item := range items {
+					annotation, err := s.errorAnnotation(ctx, kindOf(a), item, err)
+					if err != nil {
+						s.fail(ctx, Failure{Action: message.ActionCreate, Stage: StageAnnotate, Annotator: kindOf(a),
+							Err: err})
+						return
+					}
+					annotations = append(annotations, annotation)
+				}
+			}
+			for _, annotation := range annotations {
+				if s.satisfied(ctx, message.ActionCreate, annotation) == abort {
+					return
+				}
+			}
+			list.Items = annotations
+		} else {
+			for _, item := range items {
+				annotation, err := s.annotate(ctx, a, item)
+				s.observe(i, annotation.Kind, err)
+				switch s.judge(ctx, message.ActionCreate, a, item, &annotation, err) {
+				case abort:
+					return
+				case keep:
+					list.Items = append(list.Items, annotation)
+				}
+			}
+		}
+		s.emit(ctx, message.ActionCreate, list)
+	}
+}
+
+func (s *sdk) Mutate(ctx context.Context, old, new []byte, opts ...interfaces.CallOption) {
+	old, new = s.retain(old), s.retain(new)
+	call := newCallOptions(opts)
+	ctx = track(ctx, call)
+	s.submit(ctx, message.ActionMutate, func(ctx context.Context) { s.mutate(ctx, old, new, call) })
+}
+
+func (s *sdk) mutate(ctx context.Context, old, new []byte, call interfaces.CallOptions) {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	ctx, span := s.tracer.Start(ctx, "alvarium.mutate")
+	defer span.End()
+	// the hash of new is not kept in the context, it would be taken for the hash of old by the source annotator
+	if _, ok := s.sample(ctx, message.ActionMutate, new); !ok {
+		return
+	}
+
+	src, err := factories.NewAnnotator(contracts.AnnotationSource, s.cfg)
+	if err != nil {
+		s.fail(ctx, Failure{Action: message.ActionMutate, Stage: StageAnnotate, Annotator: contracts.AnnotationSource,
+			Err: err})
+		return
+	}
+	a, err := s.annotate(ctx, src, old)
+	if err != nil {
+		s.fail(ctx, Failure{Action: message.ActionMutate, Stage: StageAnnotate, Annotator: contracts.AnnotationSource,
+			Err: err})
+		return
+	}
+
+	var list contracts.AnnotationList
+	list.Items = append(list.Items, a)
+	ctx = context.WithValue(ctx, contracts.ParentHashKey, a.Key)
+
+	items, ok := s.annotateAll(ctx, message.ActionMutate, call, new)
+	if !ok {
+		return
+	}
+	for _, annotation := range items {
+		if annotation.Kind != contracts.AnnotationTLS {
+			list.Items = append(list.Items, annotation)
+		}
+	}
+	s.emit(ctx, message.ActionMutate, list)
+}
+
+func (s *sdk) Transit(ctx context.Context, data []byte, opts ...interfaces.CallOption) {
+	data = s.retain(data)
+	call := newCallOptions(opts)
+	ctx = track(ctx, call)
+	s.submit(ctx, message.ActionTransit, func(ctx context.Context) { s.transit(ctx, data, call) })
+}
+
+func (s *sdk) transit(ctx context.Context, data []byte, call interfaces.CallOptions) {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	ctx, span := s.tracer.Start(ctx, "alvarium.transit")
+	defer span.End()
+	if call.ParentKey != "" {
+		ctx = context.WithValue(ctx, contracts.ParentHashKey, call.ParentKey)
+	}
+	ctx, ok := s.sample(ctx, message.ActionTransit, data)
+	if !ok {
+		return
+	}
+
+	items, ok := s.annotateAll(ctx, message.ActionTransit, call, data)
+	if !ok {
+		return
+	}
+	s.emit(ctx, message.ActionTransit, contracts.AnnotationList{Items: items})
+}
+
+func (s *sdk) Publish(ctx context.Context, data []byte, opts ...interfaces.CallOption) {
+	data = s.retain(data)
+	call := newCallOptions(opts)
+	ctx = track(ctx, call)
+	s.submit(ctx, message.ActionPublish, func(ctx context.Context) { s.publish(ctx, data, call) })
+}
+
+func (s *sdk) publish(ctx context.Context, data []byte, call interfaces.CallOptions) {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	ctx, span := s.tracer.Start(ctx, "alvarium.publish")
+	defer span.End()
+	ctx, ok := s.sample(ctx, message.ActionPublish, data)
+	if !ok {
+		return
+	}
+
+	items, ok := s.annotateAll(ctx, message.ActionPublish, call, data)
+	if !ok {
+		return
 	}
+	s.emit(ctx, message.ActionPublish, contracts.AnnotationList{Items: items})
 }
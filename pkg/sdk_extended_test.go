@@ -0,0 +1,197 @@
+//go:build !alvarium_core && !js && !wasip1 && !tinygo
+
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/interfaces"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/journal"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSdk_CreateBatch(t *testing.T) {
+	logger := factories.NewLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+
+	b, err := os.ReadFile("../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	cfg.Signature.PrivateKey.Path = "../test/keys/ed25519/private.key"
+	cfg.Signature.PublicKey.Path = "../test/keys/ed25519/public.key"
+
+	tpm, err := factories.NewAnnotator(contracts.AnnotationTPM, cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	counting := &gatedAnnotator{}
+	instance := NewSdk([]interfaces.Annotator{tpm, counting}, cfg, logger)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	if !instance.BootstrapHandler(ctx, &wg) {
+		t.Fatalf("bootstrap failed")
+	}
+	defer wg.Wait()
+	defer cancel()
+
+	// Annotators without batch support annotate each item in turn
+	instance.CreateBatch(context.Background(), [][]byte{[]byte("reading-1"), []byte("reading-2"), []byte("reading-3")})
+	assert.Equal(t, []string{"reading-1", "reading-2", "reading-3"}, counting.data)
+
+	instance.CreateBatch(context.Background(), nil)
+	assert.Equal(t, 3, counting.count)
+
+	// Annotators that do not report their kind are not selected by kind
+	r := NewReceipt()
+	instance.CreateBatch(context.Background(), [][]byte{[]byte("reading-4")}, WithAnnotators(contracts.AnnotationTPM),
+		WithReceipt(r))
+	wait, cancelWait := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelWait()
+	assert.NoError(t, r.Wait(wait))
+	assert.Equal(t, 3, counting.count)
+}
+
+func TestSdk_Journal(t *testing.T) {
+	logger := factories.NewLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+
+	b, err := os.ReadFile("../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	cfg.Signature.PrivateKey.Path = "../test/keys/ed25519/private.key"
+
+	j, err := journal.Open(config.JournalInfo{Path: filepath.Join(t.TempDir(), "journal.db")})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer j.Close()
+
+	src, err := factories.NewAnnotator(contracts.AnnotationSource, cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	instance := NewSdk([]interfaces.Annotator{src, unsatisfiedAnnotator{}}, cfg, logger, WithJournal(j))
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	if !instance.BootstrapHandler(ctx, &wg) {
+		t.Fatalf("bootstrap failed")
+	}
+	defer wg.Wait()
+	defer cancel()
+
+	instance.Create(context.Background(), []byte("foo"))
+	instance.Create(context.Background(), []byte("bar"))
+	instance.Create(context.Background(), []byte("foo"))
+
+	all, err := j.Query(journal.Query{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 6)
+
+	// what was attested about the first piece of data
+	attested, err := j.ByKey(all[0].Key)
+	assert.NoError(t, err)
+	assert.Len(t, attested, 2)
+
+	unsatisfied, err := j.Query(journal.Query{Kind: contracts.AnnotationTPM})
+	assert.NoError(t, err)
+	assert.Len(t, unsatisfied, 3)
+}
+
+func TestSdk_Replay(t *testing.T) {
+	logger := factories.NewLogger(config.LoggingInfo{MinLogLevel: slog.LevelInfo})
+
+	b, err := os.ReadFile("../test/res/config.json")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	var cfg config.SdkInfo
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	cfg.Signature.PrivateKey.Path = "../test/keys/ed25519/private.key"
+
+	j, err := journal.Open(config.JournalInfo{Path: filepath.Join(t.TempDir(), "journal.db")})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer j.Close()
+
+	// the stream is down until outage is cleared
+	outage := true
+	var actions []message.SdkAction
+	down := func(next interfaces.PublishFunc) interfaces.PublishFunc {
+		return func(ctx context.Context, msg message.PublishWrapper) error {
+			if outage {
+				return contracts.ErrNotConnected
+			}
+			actions = append(actions, msg.Action)
+			return next(ctx, msg)
+		}
+	}
+
+	instance := NewSdk([]interfaces.Annotator{unsatisfiedAnnotator{}}, cfg, logger)
+	_, err = instance.Replay(context.Background(), interfaces.ReplayQuery{})
+	assert.ErrorIs(t, err, ErrNoJournal)
+
+	instance = NewSdk([]interfaces.Annotator{unsatisfiedAnnotator{}}, cfg, logger, WithJournal(j),
+		WithPublishInterceptors(down))
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	if !instance.BootstrapHandler(ctx, &wg) {
+		t.Fatalf("bootstrap failed")
+	}
+	defer wg.Wait()
+	defer cancel()
+
+	instance.Create(context.Background(), []byte("foo"))
+	instance.Mutate(context.Background(), []byte("foo"), []byte("bar"))
+	_, err = instance.Replay(context.Background(), interfaces.ReplayQuery{})
+	assert.ErrorIs(t, err, contracts.ErrNotConnected)
+
+	outage = false
+	instance.Publish(context.Background(), []byte("bar"))
+	n, err := instance.Replay(context.Background(), interfaces.ReplayQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []message.SdkAction{message.ActionPublish, message.ActionCreate, message.ActionMutate}, actions)
+
+	// replayed publishes are acknowledged
+	n, err = instance.Replay(context.Background(), interfaces.ReplayQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
@@ -0,0 +1,108 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package verification
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+// canonicalFixture is an annotation signed over its canonical form, in one of the encodings it may be received in, see
+// test/canonical
+type canonicalFixture struct {
+	Description string          `json:"description"`
+	Annotation  json.RawMessage `json:"annotation"`
+	Base        string          `json:"base"` // Base is the expected signature base
+	// PublicKey is the path, relative to the fixture, of the ed25519 public key of a fixture signed by another SDK.
+	// Fixtures without one are signed with the test key of this SDK.
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+// TestCanonicalForm checks the golden fixtures: annotations whose JSON encodings differ in property order, timestamp
+// format and null handling must yield the same signature base byte for byte and verify. Those signed with the test key
+// of this SDK must also be signed alike by it.
+func TestCanonicalForm(t *testing.T) {
+	paths, err := filepath.Glob("../../test/canonical/*.json")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no canonical fixtures found: %v", err)
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			var fixture canonicalFixture
+			if err = json.Unmarshal(b, &fixture); err != nil {
+				t.Fatalf(err.Error())
+			}
+			var a contracts.Annotation
+			if err = json.Unmarshal(fixture.Annotation, &a); err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			base, err := annotators.SignatureBase(a, contracts.JCSCanonicalization)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			assert.Equal(t, fixture.Base, string(base))
+
+			keys := testKeys(contracts.RawFormat)
+			keys.PrivateKey.Id = a.KeyId
+			keys.PublicKey.Id = a.KeyId
+			keys.Canonicalization = contracts.JCSCanonicalization
+			if fixture.PublicKey != "" {
+				keys.PublicKey.Path = filepath.Join(filepath.Dir(path), fixture.PublicKey)
+			}
+			assert.NoError(t, VerifyAnnotation(a, NewConfigResolver(keys)))
+
+			if fixture.PublicKey == "" {
+				signed := a
+				if err = annotators.SignWithActiveKey(keys, ed25519.New(), &signed); err != nil {
+					t.Fatalf(err.Error())
+				}
+				assert.Equal(t, a.Signature, signed.Signature)
+			}
+
+			tampered := a
+			tampered.Host = "elsewhere"
+			assert.ErrorIs(t, VerifyAnnotation(tampered, NewConfigResolver(keys)), ErrInvalidSignature)
+		})
+	}
+}
+
+// The canonical form of an annotation does not depend on the form in which it was received
+func TestCanonicalForm_SameBase(t *testing.T) {
+	bases := make(map[string]bool)
+	for _, name := range []string{"go-v1.json", "offset-timestamp-v1.json", "null-properties-v1.json"} {
+		b, err := os.ReadFile(filepath.Join("../../test/canonical", name))
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		var fixture canonicalFixture
+		if err = json.Unmarshal(b, &fixture); err != nil {
+			t.Fatalf(err.Error())
+		}
+		bases[fixture.Base] = true
+	}
+	assert.Len(t, bases, 1)
+}
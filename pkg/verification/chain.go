@@ -0,0 +1,43 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package verification
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+)
+
+// ErrBrokenChain is returned when chained annotations are missing or out of order
+var ErrBrokenChain = errors.New("broken annotation chain")
+
+// VerifyChain checks that items, annotations made by a single SDK instance with chaining enabled (see
+// config.SdkInfo.Chain) in the order they were made, form an unbroken chain. The first item may link to annotations
+// outside items. Signatures are not checked, see VerifyAnnotation.
+func VerifyChain(items []contracts.Annotation) error {
+	for i := 1; i < len(items); i++ {
+		prev, err := annotators.ChainHash(items[i-1])
+		if err != nil {
+			return fmt.Errorf("annotation %s: %w", items[i-1].Id, err)
+		}
+		if items[i].PrevHash != prev {
+			return fmt.Errorf("annotation %s: %w, it does not follow annotation %s", items[i].Id, ErrBrokenChain,
+				items[i-1].Id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package verification
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyChain(t *testing.T) {
+	keys := testKeys(contracts.RawFormat)
+	ctx := context.WithValue(context.Background(), contracts.ChainKey, annotators.NewChain())
+
+	chain := make([]contracts.Annotation, 4)
+	for i := range chain {
+		chain[i] = contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host,
+			contracts.AnnotationSource, true)
+		if err := annotators.SignAndLink(ctx, keys, ed25519.New(), &chain[i]); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+	tampered := append([]contracts.Annotation{}, chain...)
+	tampered[1].IsSatisfied = false
+
+	tests := []struct {
+		name        string
+		items       []contracts.Annotation
+		expectError bool
+	}{
+		{"complete chain", chain, false},
+		{"chain suffix", chain[2:], false},
+		{"empty chain", nil, false},
+		{"gap", []contracts.Annotation{chain[0], chain[2], chain[3]}, true},
+		{"reordered", []contracts.Annotation{chain[0], chain[2], chain[1], chain[3]}, true},
+		{"tampered", tampered, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyChain(tt.items)
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if err != nil {
+				assert.True(t, errors.Is(err, ErrBrokenChain))
+			}
+		})
+	}
+
+	assert.Empty(t, chain[0].PrevHash)
+	for _, a := range chain {
+		assert.NoError(t, VerifyAnnotation(a, NewConfigResolver(keys)), "PrevHash is covered by the signature")
+	}
+}
@@ -0,0 +1,79 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package verification
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+// ErrInvalidAnnotation is returned when received annotations cannot be parsed, including when they name an unknown
+// HashType or AnnotationType
+var ErrInvalidAnnotation = errors.New("invalid annotation")
+
+// Received is an AnnotationList read from a stream whose annotations have all been validated and verified
+type Received struct {
+	Action       message.SdkAction
+	Annotations  contracts.AnnotationList
+	TraceContext map[string]string   // TraceContext is the trace context of the operation that produced the list, if any
+	HostInfo     *contracts.HostInfo // HostInfo describes the host that published the list, if attached to the wrapper
+}
+
+// Ingest parses a publish wrapper as read from any stream, in JSON or CBOR, and hands back its annotations once every
+// one of them has been validated, naming a known HashType and AnnotationType, and its signature verified with keys. It
+// is the starting point of an Alvarium consumer written in Go. The errors of all annotations failing validation or
+// verification are joined in the result.
+func Ingest(data []byte, keys KeyResolver) (Received, error) {
+	return IngestWith(data, keys, contracts.LenientDecoding)
+}
+
+// IngestWith is Ingest in the given mode. Ingestion services reading untrusted input decode in strict mode, see
+// message.UnmarshalWith and DecodeWith.
+func IngestWith(data []byte, keys KeyResolver, mode contracts.DecodeMode) (Received, error) {
+	var msg message.PublishWrapper
+	if err := message.UnmarshalWith(data, &msg, mode); err != nil {
+		return Received{}, fmt.Errorf("malformed publish wrapper: %w", err)
+	}
+	return ReceiveWith(msg, keys, mode)
+}
+
+// Receive validates and verifies the annotations carried by a publish wrapper already parsed by the caller, as Ingest
+// does. Compressed content is decompressed first; encrypted content must be decrypted by the caller.
+func Receive(msg message.PublishWrapper, keys KeyResolver) (Received, error) {
+	return ReceiveWith(msg, keys, contracts.LenientDecoding)
+}
+
+// ReceiveWith is Receive in the given mode
+func ReceiveWith(msg message.PublishWrapper, keys KeyResolver, mode contracts.DecodeMode) (Received, error) {
+	list, err := DecodeWith(msg, mode)
+	if err != nil {
+		return Received{}, err
+	}
+
+	var errs []error
+	for _, a := range list.Items {
+		if err := VerifyAnnotation(a, keys); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return Received{}, errors.Join(errs...)
+	}
+	return Received{Action: msg.Action, Annotations: list, TraceContext: msg.TraceContext,
+		HostInfo: msg.HostInfo}, nil
+}
@@ -0,0 +1,153 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package verification
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIngest(t *testing.T) {
+	keys := testKeys(contracts.RawFormat)
+	valid := contracts.AnnotationList{Items: []contracts.Annotation{signedAnnotation(t, keys), signedAnnotation(t, keys)}}
+	tampered := contracts.AnnotationList{Items: []contracts.Annotation{signedAnnotation(t, keys)}}
+	tampered.Items[0].IsSatisfied = false
+
+	validContent, _ := json.Marshal(valid)
+	tamperedContent, _ := json.Marshal(tampered)
+	unknownHashContent := bytes.Replace(validContent, []byte(`"sha256"`), []byte(`"sha1"`), 1)
+
+	wrap := func(content []byte) []byte {
+		b, _ := json.Marshal(message.PublishWrapper{Action: message.ActionCreate, MessageType: "contracts.AnnotationList",
+			Content: content, TraceContext: map[string]string{"traceparent": "00-01-02-01"}})
+		return b
+	}
+
+	cborContent, _ := message.MarshalContent(valid, string(contracts.ContentTypeCBOR))
+	cborList, _ := message.Marshal(message.PublishWrapper{Action: message.ActionCreate,
+		MessageType: "contracts.AnnotationList", Content: cborContent, ContentType: string(contracts.ContentTypeCBOR),
+		TraceContext: map[string]string{"traceparent": "00-01-02-01"}})
+
+	protoContent, _ := message.MarshalContent(valid, string(contracts.ContentTypeProtobuf))
+	protoList, _ := message.Marshal(message.PublishWrapper{Action: message.ActionCreate,
+		MessageType: "contracts.AnnotationList", Content: protoContent,
+		ContentType: string(contracts.ContentTypeProtobuf), TraceContext: map[string]string{"traceparent": "00-01-02-01"}})
+
+	tests := []struct {
+		name        string
+		data        []byte
+		expectError bool
+		expected    error
+	}{
+		{"valid list", wrap(validContent), false, nil},
+		{"unknown hash type", wrap(unknownHashContent), true, ErrInvalidAnnotation},
+		{"tampered list", wrap(tamperedContent), true, ErrInvalidSignature},
+		{"malformed wrapper", []byte("{"), true, nil},
+		{"cbor list", cborList, false, nil},
+		{"protobuf list", protoList, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received, err := Ingest(tt.data, NewConfigResolver(keys))
+			test.CheckError(err, tt.expectError, tt.name, t)
+			if tt.expected != nil {
+				assert.True(t, errors.Is(err, tt.expected))
+			}
+			if err == nil {
+				assert.Equal(t, message.ActionCreate, received.Action)
+				assert.Equal(t, "00-01-02-01", received.TraceContext["traceparent"])
+				assert.Len(t, received.Annotations.Items, len(valid.Items))
+				assert.Equal(t, valid.Items[0].Id, received.Annotations.Items[0].Id)
+			}
+		})
+	}
+}
+
+func TestIngestWith(t *testing.T) {
+	keys := testKeys(contracts.RawFormat)
+	valid := contracts.AnnotationList{Items: []contracts.Annotation{signedAnnotation(t, keys)}}
+	validContent, _ := json.Marshal(valid)
+	unknownFieldContent := bytes.Replace(validContent, []byte(`"host":`), []byte(`"hostname":"x","host":`), 1)
+
+	var bomb bytes.Buffer
+	w := gzip.NewWriter(&bomb)
+	w.Write(bytes.Repeat([]byte{' '}, message.MaxContentSize))
+	w.Write(validContent)
+	w.Close()
+
+	wrap := func(content []byte, encoding string) []byte {
+		b, _ := json.Marshal(message.PublishWrapper{Action: message.ActionCreate, MessageType: "contracts.AnnotationList",
+			Content: content, ContentEncoding: encoding})
+		return b
+	}
+
+	tests := []struct {
+		name         string
+		data         []byte
+		expectStrict bool // expectStrict is set when strict decoding fails
+	}{
+		{"valid list", wrap(validContent, ""), false},
+		{"unknown wrapper field", bytes.Replace(wrap(validContent, ""), []byte(`"action"`),
+			[]byte(`"priority":1,"action"`), 1), true},
+		{"unknown annotation field", wrap(unknownFieldContent, ""), true},
+		{"content expanding beyond the limit", wrap(bomb.Bytes(), string(contracts.GzipEncoding)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := IngestWith(tt.data, NewConfigResolver(keys), contracts.LenientDecoding)
+			test.CheckError(err, false, tt.name, t)
+
+			_, err = IngestWith(tt.data, NewConfigResolver(keys), contracts.StrictDecoding)
+			test.CheckError(err, tt.expectStrict, tt.name, t)
+		})
+	}
+}
+
+// FuzzDecodeWith checks that decoding the content of wrappers never panics, and that strict decoding accepts no more
+// than lenient decoding
+func FuzzDecodeWith(f *testing.F) {
+	a := contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationSource, true)
+	content, _ := json.Marshal(contracts.AnnotationList{Items: []contracts.Annotation{a}})
+	for _, contentType := range []contracts.ContentType{contracts.ContentTypeJSON, contracts.ContentTypeCBOR,
+		contracts.ContentTypeProtobuf} {
+		b, _ := message.MarshalContent(contracts.AnnotationList{Items: []contracts.Annotation{a}}, string(contentType))
+		f.Add(b, string(contentType), "")
+	}
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	w.Write(content)
+	w.Close()
+	f.Add(compressed.Bytes(), "", string(contracts.GzipEncoding))
+
+	f.Fuzz(func(t *testing.T, content []byte, contentType string, encoding string) {
+		msg := message.PublishWrapper{Action: message.ActionCreate, MessageType: "contracts.AnnotationList",
+			Content: content, ContentType: contentType, ContentEncoding: encoding}
+		_, lenientErr := DecodeWith(msg, contracts.LenientDecoding)
+		if _, err := DecodeWith(msg, contracts.StrictDecoding); err != nil {
+			return
+		}
+		if lenientErr != nil {
+			t.Fatalf("strictly decoded content rejected by lenient decoding: %v", lenientErr)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// Package verification validates the signatures on annotations produced by the SDK. It applies the same
+// canonicalization used when signing, so that consumers such as scoring services and auditors need not reimplement
+// it. Raw, JWS, COSE and batch signatures are recognized automatically.
+package verification
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/compression"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/factories"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+)
+
+// ErrInvalidSignature is returned when an annotation's signature does not match its content
+var ErrInvalidSignature = errors.New("invalid annotation signature")
+
+var annotationListType = fmt.Sprintf("%T", contracts.AnnotationList{})
+
+// KeyResolver supplies the public key with which to verify an annotation, typically selected by its KeyId
+type KeyResolver interface {
+	ResolveKey(a contracts.Annotation) (config.KeyInfo, error)
+}
+
+// KeyResolverFunc adapts an ordinary function to a KeyResolver
+type KeyResolverFunc func(a contracts.Annotation) (config.KeyInfo, error)
+
+func (f KeyResolverFunc) ResolveKey(a contracts.Annotation) (config.KeyInfo, error) {
+	return f(a)
+}
+
+// CanonicalResolver is a KeyResolver that also names the canonicalization over which annotations were signed.
+// Annotations resolved by a plain KeyResolver are verified over their raw form.
+type CanonicalResolver interface {
+	KeyResolver
+	Canonicalization() contracts.Canonicalization
+}
+
+type configResolver struct {
+	keys config.SignatureInfo
+}
+
+// NewConfigResolver returns a KeyResolver that selects among the public keys of a signature configuration by the
+// annotation's KeyId. Annotations are verified over the canonicalization of the configuration.
+func NewConfigResolver(keys config.SignatureInfo) KeyResolver {
+	return configResolver{keys: keys}
+}
+
+func (r configResolver) ResolveKey(a contracts.Annotation) (config.KeyInfo, error) {
+	return r.keys.VerificationKey(a.KeyId)
+}
+
+func (r configResolver) Canonicalization() contracts.Canonicalization {
+	return r.keys.Canonicalization
+}
+
+// VerifyAnnotation validates the signature on an annotation using the key supplied by the resolver, over the
+// canonicalization it names if it is a CanonicalResolver. An annotation whose signature does not match yields an
+// error wrapping ErrInvalidSignature.
+func VerifyAnnotation(a contracts.Annotation, keys KeyResolver) error {
+	key, err := keys.ResolveKey(a)
+	if err != nil {
+		return fmt.Errorf("annotation %s: %w", a.Id, err)
+	}
+	signature, err := factories.NewSignatureProvider(key.Type)
+	if err != nil {
+		return fmt.Errorf("annotation %s: %w", a.Id, err)
+	}
+	var c contracts.Canonicalization
+	if r, ok := keys.(CanonicalResolver); ok {
+		c = r.Canonicalization()
+	}
+	ok, err := annotators.VerifySignature(key, signature, a, c)
+	if err != nil {
+		return fmt.Errorf("annotation %s: %w", a.Id, err)
+	}
+	if !ok {
+		return fmt.Errorf("annotation %s: %w", a.Id, ErrInvalidSignature)
+	}
+	return nil
+}
+
+// VerifyWrapped validates the signature on every annotation carried by a published AnnotationList. Compressed
+// content is decompressed first; encrypted content must be decrypted by the caller. The errors of all annotations
+// failing verification are joined in the result.
+func VerifyWrapped(msg message.PublishWrapper, keys KeyResolver) error {
+	list, err := Decode(msg)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, a := range list.Items {
+		if err := VerifyAnnotation(a, keys); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Decode extracts the AnnotationList carried by msg, decompressing and parsing it according to its ContentType. The
+// annotations are validated, naming a known HashType and AnnotationType, but their signatures are not verified.
+func Decode(msg message.PublishWrapper) (contracts.AnnotationList, error) {
+	return DecodeWith(msg, contracts.LenientDecoding)
+}
+
+// DecodeWith is Decode in the given mode. In strict mode, the content must not expand beyond
+// message.MaxContentSize, and the list and its annotations are held to the rules of message.UnmarshalContentWith.
+func DecodeWith(msg message.PublishWrapper, mode contracts.DecodeMode) (contracts.AnnotationList, error) {
+	var list contracts.AnnotationList
+	if msg.MessageType != annotationListType {
+		return list, fmt.Errorf("unexpected message type %s", msg.MessageType)
+	}
+	var content []byte
+	var err error
+	if mode == contracts.StrictDecoding {
+		content, err = compression.DecompressLimit(msg.ContentEncoding, msg.Content, message.MaxContentSize)
+	} else {
+		content, err = compression.Decompress(msg.ContentEncoding, msg.Content)
+	}
+	if err != nil {
+		return list, err
+	}
+	if err := message.UnmarshalContentWith(content, msg.ContentType, &list, mode); err != nil {
+		return list, fmt.Errorf("%w: %w", ErrInvalidAnnotation, err)
+	}
+	// annotations decoded from CBOR have not been validated by Annotation.UnmarshalJSON
+	for _, a := range list.Items {
+		if err := a.CheckVersion(); err != nil {
+			return list, fmt.Errorf("%w: %w", ErrInvalidAnnotation, err)
+		}
+		if !a.Hash.Validate() {
+			return list, fmt.Errorf("%w: invalid HashType value provided %s", ErrInvalidAnnotation, a.Hash)
+		}
+		if !a.Kind.Validate() {
+			return list, fmt.Errorf("%w: invalid AnnotationType value provided %s", ErrInvalidAnnotation, a.Kind)
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,147 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package verification
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/project-alvarium/alvarium-sdk-go/internal/annotators"
+	"github.com/project-alvarium/alvarium-sdk-go/internal/signprovider/ed25519"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/config"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/contracts"
+	"github.com/project-alvarium/alvarium-sdk-go/pkg/message"
+	"github.com/project-alvarium/alvarium-sdk-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func testKeys(format contracts.SignatureFormat) config.SignatureInfo {
+	return config.SignatureInfo{
+		PrivateKey: config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/private.key", Id: "2024-06"},
+		PublicKey:  config.KeyInfo{Type: contracts.KeyEd25519, Path: "../../test/keys/ed25519/public.key", Id: "2024-06"},
+		Format:     format,
+	}
+}
+
+func signedAnnotation(t *testing.T, keys config.SignatureInfo) contracts.Annotation {
+	a := contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationSource, true)
+	if err := annotators.SignWithActiveKey(keys, ed25519.New(), &a); err != nil {
+		t.Fatalf(err.Error())
+	}
+	return a
+}
+
+func TestVerifyAnnotation(t *testing.T) {
+	keys := testKeys(contracts.RawFormat)
+	raw := signedAnnotation(t, keys)
+	jws := signedAnnotation(t, testKeys(contracts.JWSFormat))
+	cose := signedAnnotation(t, testKeys(contracts.COSEFormat))
+	batch := []contracts.Annotation{
+		contracts.NewAnnotation("foo", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationSource, true),
+		contracts.NewAnnotation("bar", contracts.SHA256Hash, "host", contracts.Host, contracts.AnnotationSource, true),
+	}
+	if err := annotators.SignBatchWithActiveKey(keys, ed25519.New(), batch); err != nil {
+		t.Fatalf(err.Error())
+	}
+	canonicalKeys := keys
+	canonicalKeys.Canonicalization = contracts.JCSCanonicalization
+	canonical := signedAnnotation(t, canonicalKeys)
+	canonicalJwsKeys := testKeys(contracts.JWSFormat)
+	canonicalJwsKeys.Canonicalization = contracts.JCSCanonicalization
+	canonicalJws := signedAnnotation(t, canonicalJwsKeys)
+	tampered := raw
+	tampered.IsSatisfied = false
+	tamperedCanonical := canonical
+	tamperedCanonical.IsSatisfied = false
+	unknownKey := raw
+	unknownKey.KeyId = "2023-01"
+
+	tests := []struct {
+		name        string
+		annotation  contracts.Annotation
+		keys        config.SignatureInfo
+		expectError bool
+	}{
+		{"valid raw signature", raw, keys, false},
+		{"valid jws signature", jws, keys, false},
+		{"valid cose signature", cose, keys, false},
+		{"valid batch signature", batch[1], keys, false},
+		{"valid canonical signature", canonical, canonicalKeys, false},
+		{"valid canonical jws signature", canonicalJws, canonicalKeys, false},
+		{"canonical signature verified raw", canonical, keys, true},
+		{"raw signature verified canonical", raw, canonicalKeys, true},
+		{"tampered annotation", tampered, keys, true},
+		{"tampered canonical annotation", tamperedCanonical, canonicalKeys, true},
+		{"unknown key id", unknownKey, keys, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyAnnotation(tt.annotation, NewConfigResolver(tt.keys))
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+
+	// a plain KeyResolver verifies over the raw form
+	plain := KeyResolverFunc(func(a contracts.Annotation) (config.KeyInfo, error) {
+		return canonicalKeys.VerificationKey(a.KeyId)
+	})
+	assert.NoError(t, VerifyAnnotation(raw, plain))
+	assert.ErrorIs(t, VerifyAnnotation(canonical, plain), ErrInvalidSignature)
+
+	err := VerifyAnnotation(tampered, NewConfigResolver(keys))
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+}
+
+func TestVerifyWrapped(t *testing.T) {
+	keys := testKeys(contracts.RawFormat)
+	valid := contracts.AnnotationList{Items: []contracts.Annotation{signedAnnotation(t, keys), signedAnnotation(t, keys)}}
+	tampered := contracts.AnnotationList{Items: []contracts.Annotation{signedAnnotation(t, keys), signedAnnotation(t, keys)}}
+	tampered.Items[1].Host = "other"
+
+	listType := "contracts.AnnotationList"
+	validContent, _ := json.Marshal(valid)
+	tamperedContent, _ := json.Marshal(tampered)
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	w.Write(validContent)
+	w.Close()
+
+	tests := []struct {
+		name        string
+		msg         message.PublishWrapper
+		expectError bool
+	}{
+		{"valid list", message.PublishWrapper{Action: message.ActionCreate, MessageType: listType,
+			Content: validContent}, false},
+		{"compressed list", message.PublishWrapper{Action: message.ActionCreate, MessageType: listType,
+			Content: compressed.Bytes(), ContentEncoding: string(contracts.GzipEncoding)}, false},
+		{"tampered list", message.PublishWrapper{Action: message.ActionCreate, MessageType: listType,
+			Content: tamperedContent}, true},
+		{"unexpected message type", message.PublishWrapper{Action: message.ActionCreate, MessageType: "foo",
+			Content: validContent}, true},
+		{"unsupported encoding", message.PublishWrapper{Action: message.ActionCreate, MessageType: listType,
+			Content: validContent, ContentEncoding: "foo"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWrapped(tt.msg, NewConfigResolver(keys))
+			test.CheckError(err, tt.expectError, tt.name, t)
+		})
+	}
+}
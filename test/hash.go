@@ -0,0 +1,55 @@
+/*******************************************************************************
+ * Copyright 2024 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// StreamHasher is a hash provider deriving hash values from readers, as interfaces.HashProviderStream does
+type StreamHasher interface {
+	Derive(data []byte) string
+	DeriveFromReader(r io.Reader) (string, error)
+}
+
+// CheckDeriveFromReader verifies that p derives the same hash value from a reader as from the data read, and fails
+// when reading does. Tests of a provider check its specific output, such as salting or keying, on their own.
+func CheckDeriveFromReader(t *testing.T, p StreamHasher) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", []byte{}},
+		{"large payload", bytes.Repeat([]byte("foo"), 100000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.DeriveFromReader(bytes.NewReader(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, p.Derive(tt.data), result)
+		})
+	}
+
+	t.Run("read error", func(t *testing.T) {
+		_, err := p.DeriveFromReader(iotest.ErrReader(errors.New("read failed")))
+		assert.Error(t, err)
+	})
+}